@@ -11,6 +11,7 @@ import (
 	"github.com/cppforlife/cobrautil"
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
 	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd"
 	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
 
@@ -21,10 +22,7 @@ import (
 func main() {
 	err := nonExitingMain()
 	if err != nil {
-		if typedErr, ok := err.(cmdapp.ExitStatus); ok {
-			os.Exit(typedErr.ExitStatus())
-		}
-		os.Exit(1)
+		os.Exit(cmdapp.ExitStatusForError(err))
 	}
 }
 
@@ -46,9 +44,17 @@ func nonExitingMain() error {
 		return err
 	}
 
-	if !cobrautil.IsCobraManagedCommand(os.Args) {
+	if !cobrautil.IsCobraManagedCommand(os.Args) && !hasStructuredOutput(command) {
 		confUI.PrintLinef("Succeeded")
 	}
 
 	return nil
 }
+
+func hasStructuredOutput(command *cobra.Command) bool {
+	leafCmd, _, err := command.Find(os.Args[1:])
+	if err != nil {
+		return false
+	}
+	return cmdapp.HasStructuredOutput(leafCmd)
+}
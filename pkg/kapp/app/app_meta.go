@@ -6,6 +6,7 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -17,8 +18,28 @@ type Meta struct {
 	LastChangeName string     `json:"lastChangeName,omitempty"`
 	LastChange     ChangeMeta `json:"lastChange,omitempty"`
 
+	LastSuccessfulChangeName       string    `json:"lastSuccessfulChangeName,omitempty"`
+	LastSuccessfulChangeFinishedAt time.Time `json:"lastSuccessfulChangeFinishedAt,omitempty"`
+
 	UsedGVs []schema.GroupVersion `json:"usedGVs,omitempty"`
 	UsedGKs *[]schema.GroupKind   `json:"usedGKs,omitempty"`
+
+	// Protected apps cannot be deleted unless explicitly requested
+	Protected bool `json:"protected,omitempty"`
+	// ExpiresAt is set for apps deployed with TTL; expired apps are deleted by 'kapp gc'
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Impersonation is used by default for all changes to the app (recorded on app creation)
+	Impersonation *MetaImpersonation `json:"impersonation,omitempty"`
+
+	// IncrementalDigests identify resources that were unchanged (and converged) as of
+	// last successful deploy with --incremental (see deploy's incrementalDeploy)
+	IncrementalDigests []string `json:"incrementalDigests,omitempty"`
+}
+
+type MetaImpersonation struct {
+	User   string   `json:"user,omitempty"`
+	Groups []string `json:"groups,omitempty"`
 }
 
 func NewAppMetaFromData(data map[string]string) (Meta, error) {
@@ -45,6 +66,10 @@ func (m Meta) AsData() map[string]string {
 	return map[string]string{"spec": m.AsString()}
 }
 
+func (m Meta) IsExpired(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(now)
+}
+
 func (m Meta) Labels() map[string]string {
 	return map[string]string{m.LabelKey: m.LabelValue}
 }
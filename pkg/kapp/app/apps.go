@@ -34,6 +34,12 @@ func NewApps(nsName string, coreClient kubernetes.Interface,
 	return Apps{nsName, coreClient, identifiedResources, logger}
 }
 
+// WithNamespace returns apps stored in a different namespace
+func (a Apps) WithNamespace(nsName string) Apps {
+	a.nsName = nsName
+	return a
+}
+
 func (a Apps) Find(name string) (App, error) {
 	if len(name) == 0 {
 		return nil, fmt.Errorf("Expected app name to be non-empty")
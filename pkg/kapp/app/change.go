@@ -6,8 +6,10 @@ package app
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -19,6 +21,14 @@ type ChangeImpl struct {
 	coreClient kubernetes.Interface
 	meta       ChangeMeta
 
+	// Encoded ChangeInput (empty if not recorded or not yet loaded from chunks)
+	input       string
+	inputChunks int
+	// Encoded cluster state of resources before change was applied
+	// (empty if not recorded or not yet loaded from chunks)
+	snapshot       string
+	snapshotChunks int
+
 	createdAt time.Time
 
 	appChangesMaxToKeep int
@@ -29,6 +39,155 @@ var _ Change = &ChangeImpl{}
 func (c *ChangeImpl) Name() string     { return c.name }
 func (c *ChangeImpl) Meta() ChangeMeta { return c.meta }
 
+func (c *ChangeImpl) Input() (*ChangeInput, error) {
+	err := c.loadChunks(&c.input, changeInputDataKey, c.inputChunks)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.input) == 0 {
+		return nil, nil
+	}
+	input, err := NewChangeInputFromEncodedString(c.input)
+	if err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+// RecordInput saves input with the change. Input that does not fit
+// into app change is not recorded (change will not be available for rollback).
+func (c *ChangeImpl) RecordInput(input ChangeInput) error {
+	encoded, err := input.AsEncodedString()
+	if err != nil {
+		return err
+	}
+
+	if len(encoded) > changeChunkMaxSize*changeMaxChunks {
+		return nil
+	}
+
+	c.input = encoded
+
+	if c.appChangesMaxToKeep == 0 {
+		return nil
+	}
+
+	return c.updateInput()
+}
+
+func (c *ChangeImpl) DeleteInput() error {
+	c.input = ""
+	return c.updateInput()
+}
+
+func (c *ChangeImpl) updateInput() error {
+	var err error
+	c.inputChunks, err = c.updateData(changeInputDataKey, c.input)
+	return err
+}
+
+// updateData stores value within app change if it fits,
+// otherwise value is split into chunks; returns number of chunks
+func (c *ChangeImpl) updateData(key, val string) (int, error) {
+	change, err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Get(context.TODO(), c.name, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("Getting app change: %w", err)
+	}
+
+	if change.Data == nil {
+		change.Data = map[string]string{}
+	}
+
+	prevNumChunks := numChangeChunks(change.Data, key)
+	delete(change.Data, key)
+	delete(change.Data, changeChunksDataKey(key))
+
+	var numChunks int
+
+	if len(val) > 0 {
+		if changeDataSize(change.Data)+len(val) <= changeInputMaxEncodedSize {
+			change.Data[key] = val
+		} else {
+			numChunks, err = c.chunks().Save(change, key, val)
+			if err != nil {
+				return 0, err
+			}
+			change.Data[changeChunksDataKey(key)] = strconv.Itoa(numChunks)
+		}
+	}
+
+	_, err = c.coreClient.CoreV1().ConfigMaps(c.nsName).Update(context.TODO(), change, metav1.UpdateOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("Updating app change: %w", err)
+	}
+
+	// Remove chunks that are no longer referenced
+	err = c.chunks().Delete(key, numChunks, prevNumChunks)
+	if err != nil {
+		return 0, err
+	}
+
+	return numChunks, nil
+}
+
+func (c *ChangeImpl) loadChunks(val *string, key string, numChunks int) error {
+	if len(*val) > 0 || numChunks == 0 {
+		return nil
+	}
+	loadedVal, err := c.chunks().Load(key, numChunks)
+	if err != nil {
+		return err
+	}
+	*val = loadedVal
+	return nil
+}
+
+func (c *ChangeImpl) chunks() changeChunks {
+	return newChangeChunks(c.name, c.nsName, c.coreClient)
+}
+
+func changeDataSize(data map[string]string) int {
+	var size int
+	for k, v := range data {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+func (c *ChangeImpl) Snapshot() ([]ctlres.Resource, error) {
+	err := c.loadChunks(&c.snapshot, changeSnapshotDataKey, c.snapshotChunks)
+	if err != nil {
+		return nil, fmt.Errorf("Loading app change snapshot: %w", err)
+	}
+	if len(c.snapshot) == 0 {
+		return nil, nil
+	}
+	snapshot, err := NewChangeInputFromEncodedString(c.snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("Decoding app change snapshot: %w", err)
+	}
+	return snapshot.Resources, nil
+}
+
+// RecordSnapshot saves cluster state of resources (taken before applying change)
+// with the change. Unlike input, snapshot that does not fit into app change results in an error.
+func (c *ChangeImpl) RecordSnapshot(resources []ctlres.Resource) error {
+	encoded, err := ChangeInput{Resources: resources}.AsEncodedString()
+	if err != nil {
+		return err
+	}
+
+	if len(encoded) > changeChunkMaxSize*changeMaxChunks {
+		return fmt.Errorf("Expected snapshot of %d resources to fit into app change (%d bytes encoded, %d bytes max); "+
+			"write snapshot to a file instead", len(resources), len(encoded), changeChunkMaxSize*changeMaxChunks)
+	}
+
+	c.snapshot = encoded
+
+	c.snapshotChunks, err = c.updateData(changeSnapshotDataKey, c.snapshot)
+	return err
+}
+
 func (c *ChangeImpl) Fail() error {
 	return c.update(func(meta *ChangeMeta) {
 		falseBool := false
@@ -38,6 +197,16 @@ func (c *ChangeImpl) Fail() error {
 	})
 }
 
+func (c *ChangeImpl) FailWithProgress(progress ChangeProgress) error {
+	return c.update(func(meta *ChangeMeta) {
+		falseBool := false
+
+		meta.Successful = &falseBool
+		meta.FinishedAt = time.Now().UTC()
+		meta.Progress = &progress
+	})
+}
+
 func (c *ChangeImpl) Succeed() error {
 	return c.update(func(meta *ChangeMeta) {
 		trueBool := true
@@ -48,7 +217,17 @@ func (c *ChangeImpl) Succeed() error {
 }
 
 func (c *ChangeImpl) Delete() error {
-	err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Delete(context.TODO(), c.name, metav1.DeleteOptions{})
+	err := c.chunks().Delete(changeInputDataKey, 0, c.inputChunks)
+	if err != nil {
+		return err
+	}
+
+	err = c.chunks().Delete(changeSnapshotDataKey, 0, c.snapshotChunks)
+	if err != nil {
+		return err
+	}
+
+	err = c.coreClient.CoreV1().ConfigMaps(c.nsName).Delete(context.TODO(), c.name, metav1.DeleteOptions{})
 	if err != nil {
 		return fmt.Errorf("Deleting app change: %w", err)
 	}
@@ -71,7 +250,14 @@ func (c *ChangeImpl) update(doFunc func(*ChangeMeta)) error {
 	doFunc(&meta)
 
 	c.meta = meta
-	change.Data = meta.AsData()
+
+	// Preserve other keys (e.g. recorded input)
+	if change.Data == nil {
+		change.Data = map[string]string{}
+	}
+	for k, v := range meta.AsData() {
+		change.Data[k] = v
+	}
 
 	_, err = c.coreClient.CoreV1().ConfigMaps(c.nsName).Update(context.TODO(), change, metav1.UpdateOptions{})
 	if err != nil {
@@ -90,3 +276,12 @@ func (NoopChange) Meta() ChangeMeta { return ChangeMeta{} }
 func (NoopChange) Fail() error      { return nil }
 func (NoopChange) Succeed() error   { return nil }
 func (NoopChange) Delete() error    { return nil }
+
+func (NoopChange) Input() (*ChangeInput, error)  { return nil, nil }
+func (NoopChange) RecordInput(ChangeInput) error { return nil }
+func (NoopChange) DeleteInput() error            { return nil }
+
+func (NoopChange) Snapshot() ([]ctlres.Resource, error)   { return nil, nil }
+func (NoopChange) RecordSnapshot([]ctlres.Resource) error { return nil }
+
+func (NoopChange) FailWithProgress(ChangeProgress) error { return nil }
@@ -0,0 +1,151 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	isChangeChunkLabelKey   = "kapp.k14s.io/is-app-change-chunk"
+	isChangeChunkLabelValue = ""
+
+	changeChunkDataKey = "chunk"
+	// Holds number of chunks that value of a key is split into (e.g. inputChunks)
+	changeChunksDataKeySuffix = "Chunks"
+
+	changeChunkMaxSize = 768 * 1024
+	// Values larger than this are not stored (~24MiB encoded)
+	changeMaxChunks = 32
+)
+
+// changeChunks stores values that do not fit into app change ConfigMap
+// in additional ConfigMaps (chunks) owned by app change. Chunks are named
+// after app change and key, hence they are only retrieved when needed.
+type changeChunks struct {
+	changeName string
+	nsName     string
+	coreClient kubernetes.Interface
+}
+
+func newChangeChunks(changeName, nsName string, coreClient kubernetes.Interface) changeChunks {
+	return changeChunks{changeName, nsName, coreClient}
+}
+
+func changeChunksDataKey(key string) string { return key + changeChunksDataKeySuffix }
+
+// numChangeChunks returns number of chunks recorded in app change data for a key
+func numChangeChunks(data map[string]string, key string) int {
+	num, err := strconv.Atoi(data[changeChunksDataKey(key)])
+	if err != nil {
+		return 0
+	}
+	return num
+}
+
+func (c changeChunks) Load(key string, num int) (string, error) {
+	var result strings.Builder
+
+	for i := 0; i < num; i++ {
+		chunk, err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Get(context.TODO(), c.name(key, i), metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("Getting app change chunk: %w", err)
+		}
+		result.WriteString(chunk.Data[changeChunkDataKey])
+	}
+
+	return result.String(), nil
+}
+
+// Save splits value into chunks (replacing existing chunks) and returns number of chunks
+func (c changeChunks) Save(owner *corev1.ConfigMap, key, val string) (int, error) {
+	var num int
+
+	for ; len(val) > 0; num++ {
+		size := changeChunkMaxSize
+		if size > len(val) {
+			size = len(val)
+		}
+
+		err := c.save(owner, c.name(key, num), val[:size])
+		if err != nil {
+			return 0, err
+		}
+
+		val = val[size:]
+	}
+
+	return num, nil
+}
+
+func (c changeChunks) save(owner *corev1.ConfigMap, name, val string) error {
+	chunk := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.nsName,
+			Labels:    map[string]string{isChangeChunkLabelKey: isChangeChunkLabelValue},
+			// Chunks are garbage collected together with app change
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       owner.Name,
+				UID:        owner.UID,
+			}},
+		},
+		Data: map[string]string{changeChunkDataKey: val},
+	}
+
+	_, err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Create(context.TODO(), chunk, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("Creating app change chunk: %w", err)
+	}
+
+	_, err = c.coreClient.CoreV1().ConfigMaps(c.nsName).Update(context.TODO(), chunk, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("Updating app change chunk: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes chunks starting with a given one (e.g. ones left over after value shrunk)
+func (c changeChunks) Delete(key string, from, num int) error {
+	for i := from; i < num; i++ {
+		err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Delete(context.TODO(), c.name(key, i), metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("Deleting app change chunk: %w", err)
+		}
+	}
+	return nil
+}
+
+// Move recreates chunks in app change's new namespace
+func (c changeChunks) Move(newOwner *corev1.ConfigMap, key string, num int) error {
+	val, err := c.Load(key, num)
+	if err != nil {
+		return err
+	}
+
+	_, err = newChangeChunks(c.changeName, newOwner.Namespace, c.coreClient).Save(newOwner, key, val)
+	if err != nil {
+		return err
+	}
+
+	return c.Delete(key, 0, num)
+}
+
+func (c changeChunks) name(key string, i int) string {
+	return fmt.Sprintf("%s-%s-%d", c.changeName, strings.ToLower(key), i)
+}
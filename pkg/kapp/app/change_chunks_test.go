@@ -0,0 +1,222 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/kapptest"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const changeChunkLabelSelector = "kapp.k14s.io/is-app-change-chunk"
+
+func TestChangeInputChunks(t *testing.T) {
+	coreClient := newCoreClient(t)
+	changes := ctlapp.NewRecordedAppChanges("ns1", "app", "app-label", true, coreClient)
+
+	change, err := changes.Begin(ctlapp.ChangeMeta{Description: "update"}, 10)
+	require.NoError(t, err)
+
+	// Input that fits into app change is not split
+	smallInput := changeInput(t, 1024)
+	require.NoError(t, change.RecordInput(smallInput))
+	require.Empty(t, chunkNames(t, coreClient, "ns1"))
+	requireInput(t, changes, smallInput)
+
+	// Input that does not fit is split into chunks owned by app change
+	largeInput := changeInput(t, 2*1024*1024)
+	require.NoError(t, change.RecordInput(largeInput))
+
+	names := chunkNames(t, coreClient, "ns1")
+	require.True(t, len(names) > 2, "Expected large input to be split into multiple chunks: %v", names)
+	for i, name := range names {
+		require.Equal(t, fmt.Sprintf("%s-input-%d", change.Name(), i), name)
+	}
+
+	chunk, err := coreClient.CoreV1().ConfigMaps("ns1").Get(context.TODO(), names[0], metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, chunk.OwnerReferences, 1)
+	require.Equal(t, change.Name(), chunk.OwnerReferences[0].Name)
+	require.True(t, len(chunk.Data["chunk"]) <= 768*1024)
+
+	changeCM, err := coreClient.CoreV1().ConfigMaps("ns1").Get(context.TODO(), change.Name(), metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%d", len(names)), changeCM.Data["inputChunks"])
+	require.NotContains(t, changeCM.Data, "input")
+
+	// Chunks are reassembled when input is read from listed app change
+	requireInput(t, changes, largeInput)
+
+	// Chunks left over after input shrunk are deleted
+	mediumInput := changeInput(t, 1024*1024)
+	require.NoError(t, change.RecordInput(mediumInput))
+
+	shrunkNames := chunkNames(t, coreClient, "ns1")
+	require.True(t, len(shrunkNames) > 0 && len(shrunkNames) < len(names),
+		"Expected fewer chunks after input shrunk: %v", shrunkNames)
+	requireInput(t, changes, mediumInput)
+
+	// Chunks are deleted once input fits into app change again
+	require.NoError(t, change.RecordInput(smallInput))
+	require.Empty(t, chunkNames(t, coreClient, "ns1"))
+	requireInput(t, changes, smallInput)
+
+	require.NoError(t, change.RecordInput(largeInput))
+	require.NotEmpty(t, chunkNames(t, coreClient, "ns1"))
+
+	require.NoError(t, change.DeleteInput())
+	require.Empty(t, chunkNames(t, coreClient, "ns1"))
+	requireInput(t, changes, ctlapp.ChangeInput{})
+}
+
+func TestChangeSnapshotChunksDelete(t *testing.T) {
+	coreClient := newCoreClient(t)
+	changes := ctlapp.NewRecordedAppChanges("ns1", "app", "app-label", true, coreClient)
+
+	change, err := changes.Begin(ctlapp.ChangeMeta{Description: "update"}, 10)
+	require.NoError(t, err)
+
+	largeInput := changeInput(t, 2*1024*1024)
+	require.NoError(t, change.RecordInput(largeInput))
+	require.NoError(t, change.RecordSnapshot(largeInput.Resources))
+
+	names := chunkNames(t, coreClient, "ns1")
+	require.Contains(t, names, change.Name()+"-input-0")
+	require.Contains(t, names, change.Name()+"-snapshot-0")
+
+	listed, err := changes.List()
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	snapshot, err := listed[0].Snapshot()
+	require.NoError(t, err)
+	requireSameResources(t, largeInput.Resources, snapshot)
+
+	// Deleting app change deletes its chunks (number of chunks is read from listed app change)
+	require.NoError(t, listed[0].Delete())
+	require.Empty(t, chunkNames(t, coreClient, "ns1"))
+	require.Empty(t, configMapNames(t, coreClient, "ns1", ""))
+}
+
+func TestRecordedAppChangesDeleteAllWithChunks(t *testing.T) {
+	coreClient := newCoreClient(t)
+	changes := ctlapp.NewRecordedAppChanges("ns1", "app", "app-label", true, coreClient)
+
+	for i := 0; i < 2; i++ {
+		change, err := changes.Begin(ctlapp.ChangeMeta{Description: "update"}, 10)
+		require.NoError(t, err)
+		require.NoError(t, change.RecordInput(changeInput(t, 2*1024*1024)))
+		require.NoError(t, change.RecordSnapshot(changeInput(t, 1024*1024).Resources))
+	}
+
+	require.NotEmpty(t, chunkNames(t, coreClient, "ns1"))
+
+	require.NoError(t, changes.DeleteAll())
+	require.Empty(t, configMapNames(t, coreClient, "ns1", ""))
+}
+
+func TestRecordedAppChangesRenameMovesChunks(t *testing.T) {
+	coreClient := newCoreClient(t)
+	changes := ctlapp.NewRecordedAppChanges("ns1", "app", "app-label", true, coreClient)
+
+	change, err := changes.Begin(ctlapp.ChangeMeta{Description: "update"}, 10)
+	require.NoError(t, err)
+
+	largeInput := changeInput(t, 2*1024*1024)
+	require.NoError(t, change.RecordInput(largeInput))
+
+	names := chunkNames(t, coreClient, "ns1")
+	require.NotEmpty(t, names)
+
+	require.NoError(t, changes.Rename("app", "ns2"))
+
+	// Chunks are recreated for app change in new namespace (owned by new app change)
+	require.Empty(t, configMapNames(t, coreClient, "ns1", ""))
+	require.Equal(t, names, chunkNames(t, coreClient, "ns2"))
+
+	newChange, err := coreClient.CoreV1().ConfigMaps("ns2").Get(context.TODO(), change.Name(), metav1.GetOptions{})
+	require.NoError(t, err)
+
+	chunk, err := coreClient.CoreV1().ConfigMaps("ns2").Get(context.TODO(), names[0], metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, chunk.OwnerReferences, 1)
+	require.Equal(t, newChange.UID, chunk.OwnerReferences[0].UID)
+
+	requireInput(t, ctlapp.NewRecordedAppChanges("ns2", "app", "app-label", true, coreClient), largeInput)
+}
+
+func newCoreClient(t *testing.T) kubernetes.Interface {
+	coreClient, err := kubernetes.NewForConfig(kapptest.NewCluster(t, kapptest.ClusterOpts{}).RESTConfig())
+	require.NoError(t, err)
+	return coreClient
+}
+
+// changeInput returns input which encoded size is about given size
+// (data is random so that it is not compressed away)
+func changeInput(t *testing.T, size int) ctlapp.ChangeInput {
+	data := make([]byte, size*3/4)
+	rand.New(rand.NewSource(int64(size))).Read(data)
+
+	res, err := ctlres.NewResourceFromBytes([]byte(fmt.Sprintf(`{"apiVersion": "v1", "kind": "ConfigMap",
+"metadata": {"name": "large", "namespace": "ns1"}, "data": {"data": "%s"}}`, base64.StdEncoding.EncodeToString(data))))
+	require.NoError(t, err)
+
+	return ctlapp.ChangeInput{Resources: []ctlres.Resource{res}}
+}
+
+func requireInput(t *testing.T, changes ctlapp.RecordedAppChanges, expected ctlapp.ChangeInput) {
+	listed, err := changes.List()
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	input, err := listed[0].Input()
+	require.NoError(t, err)
+
+	if len(expected.Resources) == 0 {
+		require.Nil(t, input)
+		return
+	}
+
+	require.NotNil(t, input)
+	requireSameResources(t, expected.Resources, input.Resources)
+}
+
+func requireSameResources(t *testing.T, expected, actual []ctlres.Resource) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		require.True(t, expected[i].Equal(actual[i]), "Expected resource %d to match", i)
+	}
+}
+
+func chunkNames(t *testing.T, coreClient kubernetes.Interface, nsName string) []string {
+	return configMapNames(t, coreClient, nsName, changeChunkLabelSelector)
+}
+
+// configMapNames returns names sorted by chunk index where applicable
+func configMapNames(t *testing.T, coreClient kubernetes.Interface, nsName, selector string) []string {
+	list, err := coreClient.CoreV1().ConfigMaps(nsName).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	require.NoError(t, err)
+
+	var names []string
+	for _, cm := range list.Items {
+		names = append(names, cm.Name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	changeInputDataKey    = "input"
+	changeSnapshotDataKey = "snapshot"
+
+	// Keep well under ConfigMap size limit (1MiB) since
+	// change meta is stored within the same ConfigMap
+	changeInputMaxEncodedSize = 768 * 1024
+)
+
+// ChangeInput holds what was given to kapp for a particular change
+// so that it could be re-applied later (e.g. via rollback)
+type ChangeInput struct {
+	Resources     []ctlres.Resource
+	IntoNamespace string
+	MapNamespaces []string
+}
+
+type changeInputData struct {
+	Resources     string   `json:"resources"`
+	IntoNamespace string   `json:"intoNamespace,omitempty"`
+	MapNamespaces []string `json:"mapNamespaces,omitempty"`
+}
+
+// AsEncodedString returns gzipped and base64 encoded representation
+func (i ChangeInput) AsEncodedString() (string, error) {
+	var resourcesYAML []string
+
+	for _, res := range i.Resources {
+		bs, err := res.AsYAMLBytes()
+		if err != nil {
+			return "", fmt.Errorf("Serializing resource '%s': %w", res.Description(), err)
+		}
+		resourcesYAML = append(resourcesYAML, string(bs))
+	}
+
+	data := changeInputData{
+		Resources:     "---\n" + strings.Join(resourcesYAML, "---\n"),
+		IntoNamespace: i.IntoNamespace,
+		MapNamespaces: i.MapNamespaces,
+	}
+
+	dataBs, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("Marshaling change input: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buf)
+
+	_, err = gzipWriter.Write(dataBs)
+	if err != nil {
+		return "", fmt.Errorf("Compressing change input: %w", err)
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		return "", fmt.Errorf("Compressing change input: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func NewChangeInputFromEncodedString(encoded string) (ChangeInput, error) {
+	compressedBs, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ChangeInput{}, fmt.Errorf("Decoding change input: %w", err)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(compressedBs))
+	if err != nil {
+		return ChangeInput{}, fmt.Errorf("Decompressing change input: %w", err)
+	}
+
+	dataBs, err := io.ReadAll(gzipReader)
+	if err != nil {
+		return ChangeInput{}, fmt.Errorf("Decompressing change input: %w", err)
+	}
+
+	var data changeInputData
+
+	err = json.Unmarshal(dataBs, &data)
+	if err != nil {
+		return ChangeInput{}, fmt.Errorf("Unmarshaling change input: %w", err)
+	}
+
+	resources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(data.Resources))).Resources()
+	if err != nil {
+		return ChangeInput{}, fmt.Errorf("Parsing change input resources: %w", err)
+	}
+
+	return ChangeInput{
+		Resources:     resources,
+		IntoNamespace: data.IntoNamespace,
+		MapNamespaces: data.MapNamespaces,
+	}, nil
+}
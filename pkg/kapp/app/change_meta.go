@@ -9,6 +9,15 @@ import (
 	"time"
 )
 
+const (
+	ChangeOperationDeploy    = "deploy"
+	ChangeOperationDelete    = "delete"
+	ChangeOperationRollback  = "rollback"
+	ChangeOperationApplyPlan = "apply-plan"
+	ChangeOperationAdopt     = "adopt"
+	ChangeOperationDisown    = "disown"
+)
+
 type ChangeMeta struct {
 	StartedAt  time.Time `json:"startedAt"`
 	FinishedAt time.Time `json:"finishedAt,omitempty"`
@@ -16,9 +25,42 @@ type ChangeMeta struct {
 	Successful  *bool  `json:"successful,omitempty"`
 	Description string `json:"description,omitempty"`
 
+	// Operation is one of ChangeOperation* values; empty for older changes
+	Operation string `json:"operation,omitempty"`
+	// User is the identity of the user that made the change (if known)
+	User string `json:"user,omitempty"`
+	// NumResources is the number of app resources after the change
+	NumResources int `json:"numResources,omitempty"`
+
 	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Metadata describes provenance of the change (e.g. git SHA, CI job URL)
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Images maps original image references to digests that were applied
+	Images map[string]string `json:"images,omitempty"`
+
+	Progress *ChangeProgress `json:"progress,omitempty"`
 }
 
+// ChangeProgress records how far a change got
+// before it was stopped (e.g. due to a deploy timeout)
+type ChangeProgress struct {
+	StopReason string `json:"stopReason,omitempty"`
+	NumChanges int    `json:"numChanges"`
+	NumApplied int    `json:"numApplied"`
+	NumWaited  int    `json:"numWaited"`
+}
+
+// ChangeProgressError associates change progress with an error
+// so that it could be recorded when change is marked as failed
+type ChangeProgressError struct {
+	Err      error
+	Progress ChangeProgress
+}
+
+func (e ChangeProgressError) Error() string { return e.Err.Error() }
+func (e ChangeProgressError) Unwrap() error { return e.Err }
+
 func NewChangeMetaFromString(data string) ChangeMeta {
 	var meta ChangeMeta
 
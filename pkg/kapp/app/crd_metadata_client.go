@@ -0,0 +1,332 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	AppMetadataStorageConfigMap = "configmap"
+	AppMetadataStorageCRD       = "crd"
+
+	crdMetadataAPIVersion = "kapp.k14s.io/v1alpha1"
+)
+
+var (
+	crdMetadataAppGVR       = schema.GroupVersionResource{Group: "kapp.k14s.io", Version: "v1alpha1", Resource: "apps"}
+	crdMetadataAppChangeGVR = schema.GroupVersionResource{Group: "kapp.k14s.io", Version: "v1alpha1", Resource: "appchanges"}
+)
+
+// NewCRDMetadataClient returns client that stores app metadata and app changes
+// as App and AppChange custom resources instead of ConfigMaps.
+// Only ConfigMap operations used by this package are supported.
+func NewCRDMetadataClient(coreClient kubernetes.Interface, dynamicClient dynamic.Interface) kubernetes.Interface {
+	return crdMetadataClient{coreClient, dynamicClient}
+}
+
+type crdMetadataClient struct {
+	kubernetes.Interface
+	dynamicClient dynamic.Interface
+}
+
+func (c crdMetadataClient) CoreV1() corev1client.CoreV1Interface {
+	return crdMetadataCoreV1{c.Interface.CoreV1(), c.dynamicClient}
+}
+
+type crdMetadataCoreV1 struct {
+	corev1client.CoreV1Interface
+	dynamicClient dynamic.Interface
+}
+
+func (c crdMetadataCoreV1) ConfigMaps(nsName string) corev1client.ConfigMapInterface {
+	return crdMetadataConfigMaps{nsName, c.dynamicClient}
+}
+
+type crdMetadataConfigMaps struct {
+	nsName        string
+	dynamicClient dynamic.Interface
+}
+
+var _ corev1client.ConfigMapInterface = crdMetadataConfigMaps{}
+
+type crdMetadataObj struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   metav1.ObjectMeta `json:"metadata"`
+	Data       map[string]string `json:"data,omitempty"`
+	Status     crdMetadataStatus `json:"status,omitempty"`
+}
+
+// crdMetadataStatus is derived from data to allow
+// introspecting app state via kubectl
+type crdMetadataStatus struct {
+	LastChangeName string `json:"lastChangeName,omitempty"`
+	Successful     *bool  `json:"successful,omitempty"`
+	Description    string `json:"description,omitempty"`
+}
+
+func (c crdMetadataConfigMaps) Create(ctx context.Context, cm *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	gvr, kind := c.gvrForLabels(cm.Labels)
+
+	obj, err := c.toUnstructured(cm, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.dynamicClient.Resource(gvr).Namespace(c.nsName).Create(ctx, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fromUnstructured(result)
+}
+
+func (c crdMetadataConfigMaps) Update(ctx context.Context, cm *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	gvr, kind := c.gvrForLabels(cm.Labels)
+
+	obj, err := c.toUnstructured(cm, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.dynamicClient.Resource(gvr).Namespace(c.nsName).Update(ctx, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fromUnstructured(result)
+}
+
+func (c crdMetadataConfigMaps) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	err := c.dynamicClient.Resource(crdMetadataAppGVR).Namespace(c.nsName).Delete(ctx, name, opts)
+	if err != nil && errors.IsNotFound(err) {
+		return c.dynamicClient.Resource(crdMetadataAppChangeGVR).Namespace(c.nsName).Delete(ctx, name, opts)
+	}
+	return err
+}
+
+// Get looks up apps first since only name is available
+func (c crdMetadataConfigMaps) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	result, err := c.dynamicClient.Resource(crdMetadataAppGVR).Namespace(c.nsName).Get(ctx, name, opts)
+	if err != nil && errors.IsNotFound(err) {
+		result, err = c.dynamicClient.Resource(crdMetadataAppChangeGVR).Namespace(c.nsName).Get(ctx, name, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fromUnstructured(result)
+}
+
+func (c crdMetadataConfigMaps) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ConfigMapList, error) {
+	gvr := crdMetadataAppGVR
+	// Check for change label first since app label is its prefix
+	if strings.Contains(opts.LabelSelector, isChangeLabelKey) {
+		gvr = crdMetadataAppChangeGVR
+	}
+
+	list, err := c.dynamicClient.Resource(gvr).Namespace(c.nsName).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &corev1.ConfigMapList{ListMeta: metav1.ListMeta{ResourceVersion: list.GetResourceVersion()}}
+
+	for i := range list.Items {
+		cm, err := c.fromUnstructured(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, *cm)
+	}
+
+	return result, nil
+}
+
+func (c crdMetadataConfigMaps) DeleteCollection(context.Context, metav1.DeleteOptions, metav1.ListOptions) error {
+	return c.notSupportedErr("delete collection")
+}
+
+func (c crdMetadataConfigMaps) Watch(context.Context, metav1.ListOptions) (watch.Interface, error) {
+	return nil, c.notSupportedErr("watch")
+}
+
+func (c crdMetadataConfigMaps) Patch(context.Context, string, types.PatchType, []byte, metav1.PatchOptions, ...string) (*corev1.ConfigMap, error) {
+	return nil, c.notSupportedErr("patch")
+}
+
+func (c crdMetadataConfigMaps) Apply(context.Context, *corev1ac.ConfigMapApplyConfiguration, metav1.ApplyOptions) (*corev1.ConfigMap, error) {
+	return nil, c.notSupportedErr("apply")
+}
+
+func (c crdMetadataConfigMaps) gvrForLabels(labels map[string]string) (schema.GroupVersionResource, string) {
+	if _, found := labels[isChangeLabelKey]; found {
+		return crdMetadataAppChangeGVR, "AppChange"
+	}
+	return crdMetadataAppGVR, "App"
+}
+
+func (c crdMetadataConfigMaps) toUnstructured(cm *corev1.ConfigMap, kind string) (*unstructured.Unstructured, error) {
+	obj := crdMetadataObj{
+		APIVersion: crdMetadataAPIVersion,
+		Kind:       kind,
+		Metadata:   cm.ObjectMeta,
+		Data:       cm.Data,
+		Status:     c.status(cm.Data, kind),
+	}
+	obj.Metadata.Namespace = c.nsName
+
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Marshaling app metadata: %w", err)
+	}
+
+	var result unstructured.Unstructured
+
+	err = result.UnmarshalJSON(bs)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling app metadata: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c crdMetadataConfigMaps) fromUnstructured(obj *unstructured.Unstructured) (*corev1.ConfigMap, error) {
+	var metaObj crdMetadataObj
+
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &metaObj)
+	if err != nil {
+		return nil, fmt.Errorf("Converting app metadata: %w", err)
+	}
+
+	return &corev1.ConfigMap{ObjectMeta: metaObj.Metadata, Data: metaObj.Data}, nil
+}
+
+func (c crdMetadataConfigMaps) status(data map[string]string, kind string) crdMetadataStatus {
+	if kind == "App" {
+		var meta Meta
+		if json.Unmarshal([]byte(data["spec"]), &meta) != nil {
+			return crdMetadataStatus{}
+		}
+		return crdMetadataStatus{
+			LastChangeName: meta.LastChangeName,
+			Successful:     meta.LastChange.Successful,
+			Description:    meta.LastChange.Description,
+		}
+	}
+
+	var meta ChangeMeta
+	if json.Unmarshal([]byte(data["spec"]), &meta) != nil {
+		return crdMetadataStatus{}
+	}
+	return crdMetadataStatus{Successful: meta.Successful, Description: meta.Description}
+}
+
+func (c crdMetadataConfigMaps) notSupportedErr(op string) error {
+	return fmt.Errorf("Operation '%s' is not supported for app metadata stored in custom resources", op)
+}
+
+// CRDMetadataDefinitions returns CRDs that have to be installed
+// before app metadata could be stored in custom resources
+func CRDMetadataDefinitions() string {
+	return crdMetadataDefinitionsYAML
+}
+
+const crdMetadataDefinitionsYAML = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: apps.kapp.k14s.io
+spec:
+  group: kapp.k14s.io
+  names:
+    kind: App
+    listKind: AppList
+    plural: apps
+    singular: app
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+    additionalPrinterColumns:
+    - name: Last Change
+      type: string
+      jsonPath: .status.lastChangeName
+    - name: Successful
+      type: boolean
+      jsonPath: .status.successful
+    - name: Description
+      type: string
+      jsonPath: .status.description
+    - name: Age
+      type: date
+      jsonPath: .metadata.creationTimestamp
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          data:
+            type: object
+            additionalProperties:
+              type: string
+          status:
+            type: object
+            x-kubernetes-preserve-unknown-fields: true
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: appchanges.kapp.k14s.io
+spec:
+  group: kapp.k14s.io
+  names:
+    kind: AppChange
+    listKind: AppChangeList
+    plural: appchanges
+    singular: appchange
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+    additionalPrinterColumns:
+    - name: Successful
+      type: boolean
+      jsonPath: .status.successful
+    - name: Description
+      type: string
+      jsonPath: .status.description
+    - name: Age
+      type: date
+      jsonPath: .metadata.creationTimestamp
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          data:
+            type: object
+            additionalProperties:
+              type: string
+          status:
+            type: object
+            x-kubernetes-preserve-unknown-fields: true
+`
@@ -0,0 +1,87 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	EventReasonDeployStarted       = "DeployStarted"
+	EventReasonDeploySucceeded     = "DeploySucceeded"
+	EventReasonDeployFailed        = "DeployFailed"
+	EventReasonResourceWaitFailed  = "ResourceWaitFailed"
+	EventReasonResourceWaitTimeout = "ResourceWaitTimeout"
+
+	eventSourceComponent = "kapp"
+
+	// Event messages are limited by API server
+	eventMaxMessageLen = 1024
+)
+
+// RecordEvent creates Event that refers to app metadata object
+// so that it is listed by 'kubectl get events' in app namespace
+func (a *RecordedApp) RecordEvent(eventType, reason, message string) error {
+	app, found, err := a.find(a.fqName())
+	if err != nil {
+		return err
+	}
+	if !found {
+		app, found, err = a.find(a.name)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("App '%s' (namespace: %s) does not exist", a.name, a.nsName)
+		}
+	}
+
+	involvedObj := corev1.ObjectReference{
+		APIVersion:      "v1",
+		Kind:            "ConfigMap",
+		Namespace:       a.nsName,
+		Name:            app.Name,
+		UID:             app.UID,
+		ResourceVersion: app.ResourceVersion,
+	}
+
+	if _, isCRD := a.coreClient.(crdMetadataClient); isCRD {
+		involvedObj.APIVersion = crdMetadataAPIVersion
+		involvedObj.Kind = "App"
+	}
+
+	if len(message) > eventMaxMessageLen {
+		message = message[:eventMaxMessageLen-3] + "..."
+	}
+
+	now := metav1.NewTime(time.Now())
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: app.Name + ".",
+			Namespace:    a.nsName,
+		},
+		InvolvedObject:      involvedObj,
+		Reason:              reason,
+		Message:             message,
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: eventSourceComponent},
+		ReportingController: eventSourceComponent,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+	}
+
+	_, err = a.coreClient.CoreV1().Events(a.nsName).Create(context.TODO(), event, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("Creating event: %w", err)
+	}
+
+	return nil
+}
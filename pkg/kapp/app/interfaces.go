@@ -6,6 +6,7 @@ package app
 import (
 	"time"
 
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -23,15 +24,25 @@ type App interface {
 	UpdateUsedGVsAndGKs([]schema.GroupVersion, []schema.GroupKind) error
 
 	CreateOrUpdate(string, map[string]string, bool) (bool, error)
+	SetLabelsAndAnnotations(labels, annotations map[string]string) error
+	SetProtected(bool) error
+	SetExpiresAt(*time.Time) error
+	SetImpersonation(*MetaImpersonation) error
+	SetIncrementalDigests([]string) error
+	SetLabelKey(string) error
 	Exists() (bool, string, error)
 	Delete() error
 	Rename(string, string) error
 
+	// RecordEvent creates Kubernetes Event for app (type is Normal or Warning)
+	RecordEvent(eventType, reason, message string) error
+
 	// Sorted as first is oldest
 	Changes() ([]Change, error)
 	LastChange() (Change, error)
 	BeginChange(ChangeMeta, int) (Change, error)
-	GCChanges(max int, reviewFunc func(changesToDelete []Change) error) (int, int, error)
+	GCChanges(retention ChangesRetention, reviewFunc func(changesToDelete []Change) error) (int, int, error)
+	GCChangeInputs(max int) (int, error)
 }
 
 type Change interface {
@@ -39,7 +50,17 @@ type Change interface {
 	Meta() ChangeMeta
 
 	Fail() error
+	FailWithProgress(ChangeProgress) error
 	Succeed() error
 
+	// Input returns nil if input was not recorded
+	Input() (*ChangeInput, error)
+	RecordInput(ChangeInput) error
+	DeleteInput() error
+
+	// Snapshot returns nil if snapshot was not recorded
+	Snapshot() ([]ctlres.Resource, error)
+	RecordSnapshot([]ctlres.Resource) error
+
 	Delete() error
 }
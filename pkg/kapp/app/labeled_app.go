@@ -47,6 +47,22 @@ func (a *LabeledApp) UpdateUsedGVsAndGKs([]schema.GroupVersion, []schema.GroupKi
 func (a *LabeledApp) CreateOrUpdate(_ string, _ map[string]string, _ bool) (bool, error) {
 	return false, nil
 }
+func (a *LabeledApp) SetLabelsAndAnnotations(_, _ map[string]string) error {
+	return fmt.Errorf("Setting app labels and annotations is not supported for apps specified via label selector")
+}
+func (a *LabeledApp) SetProtected(_ bool) error {
+	return fmt.Errorf("Protecting app is not supported for apps specified via label selector")
+}
+func (a *LabeledApp) SetExpiresAt(_ *time.Time) error {
+	return fmt.Errorf("Setting app TTL is not supported for apps specified via label selector")
+}
+func (a *LabeledApp) SetImpersonation(_ *MetaImpersonation) error {
+	return fmt.Errorf("Recording impersonation is not supported for apps specified via label selector")
+}
+func (a *LabeledApp) SetIncrementalDigests(_ []string) error { return nil }
+func (a *LabeledApp) SetLabelKey(_ string) error {
+	return fmt.Errorf("Changing app label key is not supported for apps specified via label selector")
+}
 func (a *LabeledApp) Exists() (bool, string, error) { return true, "", nil }
 
 func (a *LabeledApp) Delete() error {
@@ -75,9 +91,13 @@ func (a *LabeledApp) Rename(_ string, _ string) error { return fmt.Errorf("Not s
 
 func (a *LabeledApp) Meta() (Meta, error) { return Meta{}, nil }
 
+// RecordEvent is a noop since there is no app metadata object to refer to
+func (a *LabeledApp) RecordEvent(_, _, _ string) error { return nil }
+
 func (a *LabeledApp) Changes() ([]Change, error)                  { return nil, nil }
 func (a *LabeledApp) LastChange() (Change, error)                 { return nil, nil }
 func (a *LabeledApp) BeginChange(ChangeMeta, int) (Change, error) { return NoopChange{}, nil }
-func (a *LabeledApp) GCChanges(_ int, _ func(changesToDelete []Change) error) (int, int, error) {
+func (a *LabeledApp) GCChanges(_ ChangesRetention, _ func(changesToDelete []Change) error) (int, int, error) {
 	return 0, 0, nil
 }
+func (a *LabeledApp) GCChangeInputs(_ int) (int, error) { return 0, nil }
@@ -0,0 +1,230 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+)
+
+const (
+	lockNameSuffix = "-kapp-lock"
+)
+
+type LockOpts struct {
+	// Lock is considered abandoned if it was not renewed within TTL
+	TTL         time.Duration
+	ForceUnlock bool
+}
+
+// Lock prevents concurrent modifications of the same app
+// by holding a coordination.k8s.io Lease while changes are made
+type Lock struct {
+	name   string
+	nsName string
+	holder string
+	opts   LockOpts
+
+	coreClient kubernetes.Interface
+	logger     logger.Logger
+}
+
+// LockedError indicates that app is currently locked by someone else
+type LockedError struct {
+	LockName string
+	Holder   string
+	RenewAt  time.Time
+}
+
+func (e LockedError) Error() string {
+	return fmt.Sprintf("App is locked by '%s' (lease: %s, last renewed at %s); "+
+		"wait for it to finish or use --force-unlock if it's abandoned",
+		e.Holder, e.LockName, e.RenewAt.Format(time.RFC3339))
+}
+
+func NewLock(app App, coreClient kubernetes.Interface, opts LockOpts, logger logger.Logger) Lock {
+	hostname, _ := os.Hostname()
+	holder := fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), uuid.NewString())
+
+	return Lock{
+		name:       app.Name() + lockNameSuffix,
+		nsName:     app.Namespace(),
+		holder:     holder,
+		opts:       opts,
+		coreClient: coreClient,
+		logger:     logger.NewPrefixed("Lock"),
+	}
+}
+
+// Acquire takes the lock and keeps renewing it until returned func is called
+func (l Lock) Acquire() (func(), error) {
+	// Lease duration is specified in whole seconds
+	if l.opts.TTL < time.Second {
+		return nil, fmt.Errorf("Expected lock TTL to be at least 1s, but was '%s'", l.opts.TTL)
+	}
+
+	if l.opts.ForceUnlock {
+		err := l.leases().Delete(context.TODO(), l.name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("Deleting app lock: %w", err)
+		}
+	}
+
+	err := l.acquire()
+	if err != nil {
+		return nil, err
+	}
+
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+
+	go func() {
+		defer close(doneCh)
+		l.renewPeriodically(stopCh)
+	}()
+
+	var releaseOnce sync.Once
+
+	release := func() {
+		releaseOnce.Do(func() {
+			close(stopCh)
+			<-doneCh
+
+			err := l.release()
+			if err != nil {
+				l.logger.Error("Failed to release app lock: %s", err)
+			}
+		})
+	}
+
+	return release, nil
+}
+
+func (l Lock) acquire() error {
+	now := metav1.NewMicroTime(time.Now())
+	ttlSecs := int32(l.opts.TTL.Seconds())
+
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      l.name,
+			Namespace: l.nsName,
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &l.holder,
+			LeaseDurationSeconds: &ttlSecs,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+
+	_, err := l.leases().Create(context.TODO(), lease, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("Creating app lock: %w", err)
+	}
+
+	existingLease, err := l.leases().Get(context.TODO(), l.name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting app lock: %w", err)
+	}
+
+	if !l.isExpired(existingLease) {
+		lockedErr := LockedError{LockName: l.name}
+		if existingLease.Spec.HolderIdentity != nil {
+			lockedErr.Holder = *existingLease.Spec.HolderIdentity
+		}
+		if existingLease.Spec.RenewTime != nil {
+			lockedErr.RenewAt = existingLease.Spec.RenewTime.Time
+		}
+		return lockedErr
+	}
+
+	// Take over abandoned lock; update fails if someone else took it over first
+	existingLease.Spec = lease.Spec
+
+	_, err = l.leases().Update(context.TODO(), existingLease, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("Taking over expired app lock: %w", err)
+	}
+
+	return nil
+}
+
+func (l Lock) isExpired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	ttl := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	return lease.Spec.RenewTime.Add(ttl).Before(time.Now())
+}
+
+func (l Lock) renewPeriodically(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(l.opts.TTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			err := l.renew()
+			if err != nil {
+				l.logger.Error("Failed to renew app lock: %s", err)
+			}
+		}
+	}
+}
+
+func (l Lock) renew() error {
+	lease, err := l.leases().Get(context.TODO(), l.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != l.holder {
+		return fmt.Errorf("Expected app lock to be held by '%s'", l.holder)
+	}
+
+	now := metav1.NewMicroTime(time.Now())
+	lease.Spec.RenewTime = &now
+
+	_, err = l.leases().Update(context.TODO(), lease, metav1.UpdateOptions{})
+	return err
+}
+
+func (l Lock) release() error {
+	lease, err := l.leases().Get(context.TODO(), l.name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Do not delete lock that was forcefully taken over by someone else
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != l.holder {
+		return nil
+	}
+
+	return l.leases().Delete(context.TODO(), l.name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+	})
+}
+
+func (l Lock) leases() coordinationv1client.LeaseInterface {
+	return l.coreClient.CoordinationV1().Leases(l.nsName)
+}
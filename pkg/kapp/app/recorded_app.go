@@ -18,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -27,6 +28,9 @@ const (
 	KappIsConfigmapMigratedAnnotationValue = ""
 	AppSuffix                              = ".apps.k14s.io"
 	KappAppChangesUseAppLabelAnnotationKey = "kapp.k14s.io/app-changes-use-app-label"
+
+	// Labels and annotations with this prefix are managed by kapp
+	kappInternalKeyPrefix = "kapp.k14s.io/"
 )
 
 type RecordedApp struct {
@@ -402,9 +406,24 @@ func (a *RecordedApp) Rename(newName string, newNamespace string) error {
 	return a.renameConfigMap(app, newName, newNamespace)
 }
 
+// renameConfigMap creates new app, moves app changes and only then deletes
+// old app so that interrupted rename could be continued by running it again
 func (a *RecordedApp) renameConfigMap(app *corev1.ConfigMap, name, ns string) error {
 	oldName := app.Name
 
+	meta, err := NewAppMetaFromData(app.Data)
+	if err != nil {
+		return err
+	}
+
+	_, changesUseAppLabel := app.ObjectMeta.Annotations[KappAppChangesUseAppLabelAnnotationKey]
+
+	newAppName := strings.TrimSuffix(name, AppSuffix)
+	if !changesUseAppLabel && len(newAppName) > validation.LabelValueMaxLength {
+		return fmt.Errorf("Expected new app name to be at most %d characters long for app changes to be found",
+			validation.LabelValueMaxLength)
+	}
+
 	// Clear out all existing meta fields
 	app.ObjectMeta = metav1.ObjectMeta{
 		Name:        name,
@@ -413,9 +432,21 @@ func (a *RecordedApp) renameConfigMap(app *corev1.ConfigMap, name, ns string) er
 		Annotations: app.ObjectMeta.Annotations,
 	}
 
-	_, err := a.coreClient.CoreV1().ConfigMaps(ns).Create(context.TODO(), app, metav1.CreateOptions{})
+	_, err = a.coreClient.CoreV1().ConfigMaps(ns).Create(context.TODO(), app, metav1.CreateOptions{})
 	if err != nil {
-		return fmt.Errorf("Creating app: %w", err)
+		if !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("Creating app: %w", err)
+		}
+		err = a.checkRenamedApp(name, ns, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = NewRecordedAppChanges(a.nsName, strings.TrimSuffix(oldName, AppSuffix), meta.LabelValue,
+		changesUseAppLabel, a.coreClient).Rename(newAppName, ns)
+	if err != nil {
+		return fmt.Errorf("Renaming app changes: %w", err)
 	}
 
 	err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Delete(context.TODO(), oldName, metav1.DeleteOptions{})
@@ -424,7 +455,24 @@ func (a *RecordedApp) renameConfigMap(app *corev1.ConfigMap, name, ns string) er
 		return fmt.Errorf("Deleting app: %w", err)
 	}
 
-	// TODO deal with app history somehow?
+	return nil
+}
+
+// checkRenamedApp makes sure that already existing app was created by previous rename attempt
+func (a *RecordedApp) checkRenamedApp(name, ns string, meta Meta) error {
+	existingApp, err := a.coreClient.CoreV1().ConfigMaps(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting app: %w", err)
+	}
+
+	existingMeta, err := NewAppMetaFromData(existingApp.Data)
+	if err != nil {
+		return err
+	}
+
+	if existingMeta.LabelKey != meta.LabelKey || existingMeta.LabelValue != meta.LabelValue {
+		return fmt.Errorf("App '%s' (namespace: %s) already exists", strings.TrimSuffix(name, AppSuffix), ns)
+	}
 
 	return nil
 }
@@ -579,6 +627,87 @@ func (a *RecordedApp) update(doFunc func(*Meta)) error {
 	return nil
 }
 
+// SetLabelsAndAnnotations sets user provided labels and annotations
+// on the app record; unlike labels given during app creation their values may change
+func (a *RecordedApp) SetLabelsAndAnnotations(labels, annotations map[string]string) error {
+	for key := range labels {
+		if strings.HasPrefix(key, kappInternalKeyPrefix) {
+			return fmt.Errorf("Expected app label '%s' to not use reserved prefix '%s'", key, kappInternalKeyPrefix)
+		}
+	}
+	for key := range annotations {
+		if strings.HasPrefix(key, kappInternalKeyPrefix) {
+			return fmt.Errorf("Expected app annotation '%s' to not use reserved prefix '%s'", key, kappInternalKeyPrefix)
+		}
+	}
+
+	name := a.name
+	if a.isMigrated {
+		name = a.fqName()
+	}
+
+	app, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting app: %w", err)
+	}
+
+	if app.Labels == nil {
+		app.Labels = map[string]string{}
+	}
+	for key, val := range labels {
+		app.Labels[key] = val
+	}
+
+	if app.Annotations == nil {
+		app.Annotations = map[string]string{}
+	}
+	for key, val := range annotations {
+		app.Annotations[key] = val
+	}
+
+	_, err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Update(context.TODO(), app, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("Updating app: %w", err)
+	}
+
+	return nil
+}
+
+func (a *RecordedApp) SetProtected(protected bool) error {
+	return a.update(func(meta *Meta) {
+		meta.Protected = protected
+	})
+}
+
+func (a *RecordedApp) SetExpiresAt(expiresAt *time.Time) error {
+	return a.update(func(meta *Meta) {
+		meta.ExpiresAt = expiresAt
+	})
+}
+
+func (a *RecordedApp) SetImpersonation(impersonation *MetaImpersonation) error {
+	return a.update(func(meta *Meta) {
+		meta.Impersonation = impersonation
+	})
+}
+
+func (a *RecordedApp) SetIncrementalDigests(digests []string) error {
+	return a.update(func(meta *Meta) {
+		meta.IncrementalDigests = digests
+	})
+}
+
+// SetLabelKey changes label key used to associate resources with this app.
+// Label value (unique to this app) stays the same.
+func (a *RecordedApp) SetLabelKey(key string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("Expected app label key '%s' to be a valid label key: %s", key, strings.Join(errs, "; "))
+	}
+	return a.update(func(meta *Meta) {
+		meta.LabelKey = key
+	})
+}
+
 type appTrackingChange struct {
 	change *ChangeImpl
 	app    *RecordedApp
@@ -600,6 +729,17 @@ func (c appTrackingChange) Fail() error {
 	return err
 }
 
+func (c appTrackingChange) FailWithProgress(progress ChangeProgress) error {
+	err := c.change.FailWithProgress(progress)
+	if err != nil {
+		return err
+	}
+
+	_ = c.syncOnApp()
+
+	return err
+}
+
 func (c appTrackingChange) Succeed() error {
 	err := c.change.Succeed()
 	if err != nil {
@@ -611,6 +751,26 @@ func (c appTrackingChange) Succeed() error {
 	return err
 }
 
+func (c appTrackingChange) Input() (*ChangeInput, error) {
+	return c.change.Input()
+}
+
+func (c appTrackingChange) RecordInput(input ChangeInput) error {
+	return c.change.RecordInput(input)
+}
+
+func (c appTrackingChange) DeleteInput() error {
+	return c.change.DeleteInput()
+}
+
+func (c appTrackingChange) Snapshot() ([]ctlres.Resource, error) {
+	return c.change.Snapshot()
+}
+
+func (c appTrackingChange) RecordSnapshot(resources []ctlres.Resource) error {
+	return c.change.RecordSnapshot(resources)
+}
+
 func (c appTrackingChange) Delete() error {
 	return c.change.Delete()
 }
@@ -619,5 +779,10 @@ func (c appTrackingChange) syncOnApp() error {
 	return c.app.update(func(meta *Meta) {
 		meta.LastChangeName = c.change.Name()
 		meta.LastChange = c.change.meta
+
+		if c.change.meta.Successful != nil && *c.change.meta.Successful {
+			meta.LastSuccessfulChangeName = c.change.Name()
+			meta.LastSuccessfulChangeFinishedAt = c.change.meta.FinishedAt
+		}
 	})
 }
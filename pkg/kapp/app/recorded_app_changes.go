@@ -10,6 +10,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -40,6 +41,30 @@ func NewRecordedAppChanges(nsName, appName, changeLabelValue string, appChangeUs
 func (a RecordedAppChanges) List() ([]Change, error) {
 	var result []Change
 
+	changes, err := a.listConfigMaps()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes.Items {
+		result = append(result, &ChangeImpl{
+			name:           change.Name,
+			nsName:         a.nsName,
+			coreClient:     a.coreClient,
+			meta:           NewChangeMetaFromData(change.Data),
+			input:          change.Data[changeInputDataKey],
+			inputChunks:    numChangeChunks(change.Data, changeInputDataKey),
+			snapshot:       change.Data[changeSnapshotDataKey],
+			snapshotChunks: numChangeChunks(change.Data, changeSnapshotDataKey),
+			createdAt:      change.CreationTimestamp.Time,
+		})
+	}
+
+	return result, nil
+}
+
+// listConfigMaps returns app changes sorted as first is oldest
+func (a RecordedAppChanges) listConfigMaps() (*corev1.ConfigMapList, error) {
 	listOpts := metav1.ListOptions{
 		LabelSelector: labels.Set(map[string]string{
 			isChangeLabelKey: isChangeLabelValue,
@@ -61,48 +86,98 @@ func (a RecordedAppChanges) List() ([]Change, error) {
 		return nil, err
 	}
 
-	sort.Slice(changes.Items, func(i, j int) bool {
+	// Prefer start time since app changes moved to
+	// another namespace are recreated at about the same time
+	sort.SliceStable(changes.Items, func(i, j int) bool {
+		iStartedAt := NewChangeMetaFromData(changes.Items[i].Data).StartedAt
+		jStartedAt := NewChangeMetaFromData(changes.Items[j].Data).StartedAt
+		if !iStartedAt.Equal(jStartedAt) {
+			return iStartedAt.Before(jStartedAt)
+		}
 		iT := &changes.Items[i].CreationTimestamp
 		jT := &changes.Items[j].CreationTimestamp
 		return iT.Before(jT)
 	})
 
-	for _, change := range changes.Items {
-		result = append(result, &ChangeImpl{
-			name:       change.Name,
-			nsName:     a.nsName,
-			coreClient: a.coreClient,
-			meta:       NewChangeMetaFromData(change.Data),
-			createdAt:  change.CreationTimestamp.Time,
-		})
-	}
-
-	return result, nil
+	return changes, nil
 }
 
-func (a RecordedAppChanges) DeleteAll() error {
-	listOpts := metav1.ListOptions{
-		LabelSelector: labels.Set(map[string]string{
-			isChangeLabelKey: isChangeLabelValue,
-			changeLabelKey:   a.changeLabelValue,
-		}).String(),
+// Rename relabels app changes for a new app name and
+// moves them into a new namespace if it's different
+func (a RecordedAppChanges) Rename(newAppName, newNsName string) error {
+	changes, err := a.listConfigMaps()
+	if err != nil {
+		return err
 	}
 
-	if !a.appChangeUsesAppLabel {
-		listOpts = metav1.ListOptions{
-			LabelSelector: labels.Set(map[string]string{
-				isChangeLabelKey:     isChangeLabelValue,
-				legacyChangeLabelKey: a.appName,
-			}).String(),
+	for _, change := range changes.Items {
+		change := change // copy
+
+		if _, found := change.Labels[legacyChangeLabelKey]; found || !a.appChangeUsesAppLabel {
+			if len(newAppName) <= validation.LabelValueMaxLength {
+				change.Labels[legacyChangeLabelKey] = newAppName
+			} else {
+				delete(change.Labels, legacyChangeLabelKey)
+			}
+		}
+
+		if newNsName == a.nsName {
+			_, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Update(context.TODO(), &change, metav1.UpdateOptions{})
+			if err != nil {
+				return fmt.Errorf("Updating app change: %w", err)
+			}
+			continue
+		}
+
+		change.ObjectMeta = metav1.ObjectMeta{
+			Name:        change.Name,
+			Namespace:   newNsName,
+			Labels:      change.Labels,
+			Annotations: change.Annotations,
+		}
+
+		newChange, err := a.coreClient.CoreV1().ConfigMaps(newNsName).Create(context.TODO(), &change, metav1.CreateOptions{})
+		if err != nil {
+			if !errors.IsAlreadyExists(err) {
+				return fmt.Errorf("Creating app change: %w", err)
+			}
+			newChange, err = a.coreClient.CoreV1().ConfigMaps(newNsName).Get(context.TODO(), change.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("Getting app change: %w", err)
+			}
+		}
+
+		// Chunks are owned by app change hence they are recreated for a new app change
+		for _, key := range []string{changeInputDataKey, changeSnapshotDataKey} {
+			err := newChangeChunks(change.Name, a.nsName, a.coreClient).Move(newChange, key, numChangeChunks(change.Data, key))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Delete(context.TODO(), change.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("Deleting app change: %w", err)
 		}
 	}
 
-	changes, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).List(context.TODO(), listOpts)
+	return nil
+}
+
+func (a RecordedAppChanges) DeleteAll() error {
+	changes, err := a.listConfigMaps()
 	if err != nil {
 		return err
 	}
 
 	for _, change := range changes.Items {
+		for _, key := range []string{changeInputDataKey, changeSnapshotDataKey} {
+			err := newChangeChunks(change.Name, a.nsName, a.coreClient).Delete(key, 0, numChangeChunks(change.Data, key))
+			if err != nil {
+				return err
+			}
+		}
+
 		err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Delete(context.TODO(), change.Name, metav1.DeleteOptions{})
 		if err != nil {
 			return err
@@ -114,9 +189,12 @@ func (a RecordedAppChanges) DeleteAll() error {
 
 func (a RecordedAppChanges) Begin(meta ChangeMeta, appChangesMaxToKeep int) (*ChangeImpl, error) {
 	newMeta := ChangeMeta{
-		StartedAt:   time.Now().UTC(),
-		Description: meta.Description,
-		Namespaces:  meta.Namespaces,
+		StartedAt:    time.Now().UTC(),
+		Description:  meta.Description,
+		Operation:    meta.Operation,
+		User:         meta.User,
+		NumResources: meta.NumResources,
+		Namespaces:   meta.Namespaces,
 	}
 
 	configMap := &corev1.ConfigMap{
@@ -3,11 +3,24 @@
 
 package app
 
+import (
+	"time"
+)
+
 const (
-	AppChangesMaxToKeepDefault = 200
+	AppChangesMaxToKeepDefault          = 200
+	AppChangesMaxToKeepResourcesDefault = 10
 )
 
-func (a *RecordedApp) GCChanges(max int, reviewFunc func(changesToDelete []Change) error) (int, int, error) {
+// ChangesRetention determines which app changes are garbage collected
+type ChangesRetention struct {
+	MaxToKeep int
+	// Changes started earlier than MaxAge ago are deleted
+	// (newest change is always kept); zero disables age based deletion
+	MaxAge time.Duration
+}
+
+func (a *RecordedApp) GCChanges(retention ChangesRetention, reviewFunc func(changesToDelete []Change) error) (int, int, error) {
 	if reviewFunc == nil {
 		reviewFunc = func(_ []Change) error { return nil }
 	}
@@ -17,24 +30,66 @@ func (a *RecordedApp) GCChanges(max int, reviewFunc func(changesToDelete []Chang
 		return 0, 0, err
 	}
 
-	if len(changes) < max {
+	// First change is oldest
+	numToDelete := 0
+	if len(changes) > retention.MaxToKeep {
+		numToDelete = len(changes) - retention.MaxToKeep
+	}
+
+	if retention.MaxAge > 0 {
+		cutoff := time.Now().Add(-retention.MaxAge)
+		for numToDelete < len(changes)-1 && changes[numToDelete].Meta().StartedAt.Before(cutoff) {
+			numToDelete++
+		}
+	}
+
+	if numToDelete == 0 {
 		return len(changes), 0, reviewFunc(nil)
 	}
 
-	// First change is oldest
-	changes = changes[0 : len(changes)-max]
+	changesToDelete := changes[0:numToDelete]
 
-	err = reviewFunc(changes)
+	err = reviewFunc(changesToDelete)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	for _, change := range changes {
+	for _, change := range changesToDelete {
 		err := change.Delete()
 		if err != nil {
 			return 0, 0, err
 		}
 	}
 
-	return max, len(changes), nil
+	return len(changes) - numToDelete, numToDelete, nil
+}
+
+// GCChangeInputs removes recorded input from all but
+// the newest max app changes that have recorded input
+func (a *RecordedApp) GCChangeInputs(max int) (int, error) {
+	changes, err := a.Changes()
+	if err != nil {
+		return 0, err
+	}
+
+	var numKept, numDeleted int
+
+	// Last change is newest
+	for i := len(changes) - 1; i >= 0; i-- {
+		input, err := changes[i].Input()
+		if err != nil || input == nil {
+			continue
+		}
+		if numKept < max {
+			numKept++
+			continue
+		}
+		err = changes[i].DeleteInput()
+		if err != nil {
+			return numDeleted, err
+		}
+		numDeleted++
+	}
+
+	return numDeleted, nil
 }
@@ -3,19 +3,39 @@
 
 package app
 
+import (
+	"errors"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
 type Touch struct {
 	App              App
 	Description      string
+	Operation        string
+	User             string
+	NumResources     int
 	Namespaces       []string
 	IgnoreSuccessErr bool
+	Metadata         map[string]string
+	Images           map[string]string
+
+	// Recorded with the change if specified
+	Input    *ChangeInput
+	Snapshot []ctlres.Resource
 
 	AppChangesMaxToKeep int
 }
 
 func (t Touch) Do(doFunc func() error) error {
 	meta := ChangeMeta{
-		Description: t.Description,
-		Namespaces:  t.Namespaces,
+		Description:  t.Description,
+		Operation:    t.Operation,
+		User:         t.User,
+		NumResources: t.NumResources,
+		Namespaces:   t.Namespaces,
+		Metadata:     t.Metadata,
+		Images:       t.Images,
 	}
 
 	change, err := t.App.BeginChange(meta, t.AppChangesMaxToKeep)
@@ -23,9 +43,30 @@ func (t Touch) Do(doFunc func() error) error {
 		return err
 	}
 
+	if t.Input != nil {
+		err = change.RecordInput(*t.Input)
+		if err != nil {
+			_ = change.Fail()
+			return err
+		}
+	}
+
+	if t.Snapshot != nil {
+		err = change.RecordSnapshot(t.Snapshot)
+		if err != nil {
+			_ = change.Fail()
+			return err
+		}
+	}
+
 	workErr := doFunc()
 	if workErr != nil {
-		_ = change.Fail()
+		var progressErr ChangeProgressError
+		if errors.As(workErr, &progressErr) {
+			_ = change.FailWithProgress(progressErr.Progress)
+		} else {
+			_ = change.Fail()
+		}
 		return workErr
 	}
 
@@ -0,0 +1,145 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	RecordResultSucceeded = "succeeded"
+	RecordResultFailed    = "failed"
+)
+
+// Record describes single create, update or delete performed against cluster
+type Record struct {
+	Time      time.Time `json:"time"`
+	StartedAt time.Time `json:"startedAt"`
+
+	App          string `json:"app"`
+	AppNamespace string `json:"appNamespace"`
+	Operation    string `json:"operation"`
+	User         string `json:"user,omitempty"`
+
+	Op       string         `json:"op"`
+	Resource RecordResource `json:"resource"`
+	DiffMD5  string         `json:"diffMD5,omitempty"`
+
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+type RecordResource struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+}
+
+type sink interface {
+	Write(Record) error
+	Close() error
+}
+
+// Log writes records to all configured sinks
+type Log struct {
+	sinks []sink
+	lock  sync.Mutex
+}
+
+// NewLog opens sinks; supported specs are file path (JSON lines are appended),
+// syslog:// (local syslog daemon), syslog+udp://host:port and syslog+tcp://host:port
+func NewLog(specs []string) (*Log, error) {
+	log := &Log{}
+
+	for _, spec := range specs {
+		sink, err := newSink(spec)
+		if err != nil {
+			log.Close()
+			return nil, fmt.Errorf("Opening audit log '%s': %w", spec, err)
+		}
+		log.sinks = append(log.sinks, sink)
+	}
+
+	return log, nil
+}
+
+func newSink(spec string) (sink, error) {
+	switch {
+	case spec == "syslog://" || spec == "syslog":
+		return newLocalSyslogSink()
+	case strings.HasPrefix(spec, "syslog+udp://"):
+		return newSyslogSink("udp", strings.TrimPrefix(spec, "syslog+udp://"))
+	case strings.HasPrefix(spec, "syslog+tcp://"):
+		return newSyslogSink("tcp", strings.TrimPrefix(spec, "syslog+tcp://"))
+	case strings.Contains(spec, "://"):
+		return nil, fmt.Errorf("Unknown audit log scheme (supported: file path, syslog://, syslog+udp://, syslog+tcp://)")
+	default:
+		return newFileSink(spec)
+	}
+}
+
+// Write sends record to all sinks, even if some of them fail
+func (l *Log) Write(record Record) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var errs []error
+
+	for _, sink := range l.sinks {
+		err := sink.Write(record)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (l *Log) Close() error {
+	var errs []error
+	for _, sink := range l.sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+type fileSink struct {
+	file *os.File
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file}, nil
+}
+
+func (s *fileSink) Write(record Record) error {
+	bs, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.file.Write(append(bs, '\n'))
+	if err != nil {
+		return fmt.Errorf("Writing audit log file: %w", err)
+	}
+
+	// Synced so that records survive abrupt termination
+	err = s.file.Sync()
+	if err != nil {
+		return fmt.Errorf("Syncing audit log file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileSink) Close() error { return s.file.Close() }
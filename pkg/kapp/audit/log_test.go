@@ -0,0 +1,122 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit_test
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	ctlaudit "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/audit"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+func TestRecorderWritesAppliedAndFailedChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	log, err := ctlaudit.NewLog([]string{path})
+	require.NoError(t, err)
+
+	recorder := ctlaudit.NewRecorder(log, ctlaudit.Record{
+		App:          "app1",
+		AppNamespace: "ns1",
+		Operation:    "deploy",
+	}, func(err error) { require.NoError(t, err) })
+	recorder.SetUser("user1")
+
+	var forwarded int
+	progressFunc := recorder.ProgressFunc(func(ctlcap.ProgressEvent) { forwarded++ })
+
+	res := &ctlcap.ProgressEventResource{Namespace: "ns1", Name: "cm1", Kind: "ConfigMap", APIVersion: "v1"}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeDiffComputed, Time: now})
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeChangeApplied, Time: now, Resource: res, Op: "add"})
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeChangeApplied, Time: now, Resource: res, Op: "noop"})
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeChangeFailed, Time: now, Resource: res, Op: "update", Error: "denied"})
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeResourceReady, Time: now, Resource: res, Op: "update"})
+
+	require.Equal(t, 5, forwarded)
+	require.NoError(t, log.Close())
+
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(bs)), "\n")
+	require.Len(t, lines, 2)
+
+	var records []ctlaudit.Record
+	for _, line := range lines {
+		var record ctlaudit.Record
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	require.Equal(t, "app1", records[0].App)
+	require.Equal(t, "ns1", records[0].AppNamespace)
+	require.Equal(t, "deploy", records[0].Operation)
+	require.Equal(t, "user1", records[0].User)
+	require.Equal(t, "add", records[0].Op)
+	require.Equal(t, ctlaudit.RecordResource{Namespace: "ns1", Name: "cm1", Kind: "ConfigMap", APIVersion: "v1"}, records[0].Resource)
+	require.Equal(t, ctlaudit.RecordResultSucceeded, records[0].Result)
+	require.True(t, records[0].Time.Equal(now))
+	require.False(t, records[0].StartedAt.IsZero())
+
+	require.Equal(t, "update", records[1].Op)
+	require.Equal(t, ctlaudit.RecordResultFailed, records[1].Result)
+	require.Equal(t, "denied", records[1].Error)
+}
+
+func TestLogAppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	for i := 0; i < 2; i++ {
+		log, err := ctlaudit.NewLog([]string{path})
+		require.NoError(t, err)
+		require.NoError(t, log.Write(ctlaudit.Record{App: "app1"}))
+		require.NoError(t, log.Close())
+	}
+
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(string(bs), "\n"))
+}
+
+func TestLogWritesToSyslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	log, err := ctlaudit.NewLog([]string{"syslog+udp://" + conn.LocalAddr().String()})
+	require.NoError(t, err)
+	defer log.Close()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	err = log.Write(ctlaudit.Record{Time: now, App: "app1", Op: "delete", Result: ctlaudit.RecordResultFailed})
+	require.NoError(t, err)
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	msg := string(buf[:n])
+	require.True(t, strings.HasPrefix(msg, "<132>1 2024-01-02T03:04:05Z "), "Unexpected message: %s", msg)
+	require.Contains(t, msg, " kapp ")
+	require.Contains(t, msg, `"app":"app1"`)
+	require.Contains(t, msg, `"op":"delete"`)
+}
+
+func TestLogUnknownScheme(t *testing.T) {
+	_, err := ctlaudit.NewLog([]string{"https://example.com"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Unknown audit log scheme")
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"time"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+// Recorder converts progress of applying changes into audit records
+type Recorder struct {
+	log      *Log
+	template Record
+	diffMD5s map[RecordResource]string
+	errFunc  func(error)
+}
+
+// NewRecorder returns nil recorder (all methods are no-op) if log is nil;
+// errFunc is called when record cannot be written
+func NewRecorder(log *Log, template Record, errFunc func(error)) *Recorder {
+	if log == nil {
+		return nil
+	}
+	if template.StartedAt.IsZero() {
+		template.StartedAt = time.Now().UTC()
+	}
+	return &Recorder{log: log, template: template, diffMD5s: map[RecordResource]string{}, errFunc: errFunc}
+}
+
+// SetUser records identity making changes (known only after connecting to cluster)
+func (r *Recorder) SetUser(user string) {
+	if r == nil {
+		return
+	}
+	r.template.User = user
+}
+
+// SetChanges records diff hashes of changes that are about to be applied
+func (r *Recorder) SetChanges(changes []*ctlcap.ClusterChange) {
+	if r == nil {
+		return
+	}
+	for _, change := range changes {
+		res := newRecordResource(ctlcap.NewProgressEventResource(change))
+		r.diffMD5s[res] = change.ConfigurableTextDiff().Full().MinimalMD5()
+	}
+}
+
+// ProgressFunc returns progress func that writes record for each
+// applied or failed change before calling next func
+func (r *Recorder) ProgressFunc(next ctlcap.ProgressEventFunc) ctlcap.ProgressEventFunc {
+	if r == nil {
+		return next
+	}
+	return func(event ctlcap.ProgressEvent) {
+		r.record(event)
+		if next != nil {
+			next(event)
+		}
+	}
+}
+
+func (r *Recorder) record(event ctlcap.ProgressEvent) {
+	if event.Resource == nil {
+		return
+	}
+
+	switch ctlcap.ClusterChangeApplyOp(event.Op) {
+	case ctlcap.ClusterChangeApplyOpAdd, ctlcap.ClusterChangeApplyOpUpdate, ctlcap.ClusterChangeApplyOpDelete:
+	default:
+		return
+	}
+
+	record := r.template
+	record.Time = event.Time
+	record.Op = event.Op
+	record.Resource = newRecordResource(event.Resource)
+	record.DiffMD5 = r.diffMD5s[record.Resource]
+
+	switch event.Type {
+	case ctlcap.ProgressEventTypeChangeApplied:
+		record.Result = RecordResultSucceeded
+	case ctlcap.ProgressEventTypeChangeFailed:
+		record.Result = RecordResultFailed
+		record.Error = event.Error
+	default:
+		return
+	}
+
+	err := r.log.Write(record)
+	if err != nil && r.errFunc != nil {
+		r.errFunc(err)
+	}
+}
+
+func newRecordResource(res *ctlcap.ProgressEventResource) RecordResource {
+	return RecordResource{
+		Namespace:  res.Namespace,
+		Name:       res.Name,
+		Kind:       res.Kind,
+		APIVersion: res.APIVersion,
+	}
+}
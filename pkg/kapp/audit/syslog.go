@@ -0,0 +1,124 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	// Facility local0 (16) with severity notice (5) for succeeded changes
+	// and warning (4) for failed ones
+	syslogPriorityNotice  = 16*8 + 5
+	syslogPriorityWarning = 16*8 + 4
+
+	syslogAppName = "kapp"
+	syslogTimeout = 5 * time.Second
+)
+
+var (
+	// Locations used by common syslog daemons on Linux and macOS
+	localSyslogAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+)
+
+// syslogSink writes RFC 5424 messages with JSON record as message body;
+// implemented without log/syslog package since it is not available on Windows
+type syslogSink struct {
+	network  string
+	addr     string
+	conn     net.Conn
+	hostname string
+}
+
+func newSyslogSink(network, addr string) (*syslogSink, error) {
+	sink := &syslogSink{network: network, addr: addr}
+	sink.hostname, _ = os.Hostname()
+
+	err := sink.connect()
+	if err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func newLocalSyslogSink() (*syslogSink, error) {
+	var lastErr error
+
+	for _, addr := range localSyslogAddrs {
+		for _, network := range []string{"unixgram", "unix"} {
+			sink, err := newSyslogSink(network, addr)
+			if err == nil {
+				return sink, nil
+			}
+			lastErr = err
+		}
+	}
+
+	return nil, fmt.Errorf("Connecting to local syslog: %w", lastErr)
+}
+
+func (s *syslogSink) connect() error {
+	conn, err := net.DialTimeout(s.network, s.addr, syslogTimeout)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *syslogSink) Write(record Record) error {
+	bs, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	priority := syslogPriorityNotice
+	if record.Result == RecordResultFailed {
+		priority = syslogPriorityWarning
+	}
+
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - - %s", priority, record.Time.UTC().Format(time.RFC3339Nano),
+		nilValue(s.hostname), syslogAppName, os.Getpid(), bs)
+
+	// Stream transports require framing (RFC 6587 octet counting)
+	if s.network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	err = s.write(msg)
+	if err != nil {
+		// Reconnect once in case connection was dropped (e.g. syslog daemon restarted)
+		s.conn.Close()
+		err = s.connect()
+		if err == nil {
+			err = s.write(msg)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("Writing to syslog: %w", err)
+	}
+	return nil
+}
+
+func (s *syslogSink) write(msg string) error {
+	err := s.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	if err != nil {
+		return err
+	}
+	_, err = s.conn.Write([]byte(msg))
+	return err
+}
+
+func (s *syslogSink) Close() error { return s.conn.Close() }
+
+func nilValue(val string) string {
+	if len(val) == 0 {
+		return "-"
+	}
+	return val
+}
@@ -29,6 +29,9 @@ const (
 
 type AddOrUpdateChangeOpts struct {
 	DefaultUpdateStrategy string
+	// Resources matched use fallback-on-replace update strategy
+	// instead of default one (explicit annotation still takes precedence)
+	FallbackOnReplaceMatcher ctlres.ResourceMatcher
 }
 
 type AddOrUpdateChange struct {
@@ -72,6 +75,9 @@ func (c AddOrUpdateChange) ApplyStrategy() (ApplyStrategy, error) {
 		strategy, found := newRes.Annotations()[updateStrategyAnnKey]
 		if !found {
 			strategy = c.opts.DefaultUpdateStrategy
+			if c.opts.FallbackOnReplaceMatcher != nil && c.opts.FallbackOnReplaceMatcher.Matches(newRes) {
+				strategy = string(updateStrategyFallbackOnReplaceAnnValue)
+			}
 		}
 
 		switch ClusterChangeApplyStrategyOp(strategy) {
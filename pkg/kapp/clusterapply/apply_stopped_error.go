@@ -0,0 +1,58 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	ApplyStoppedReasonDeadline    = "deadline exceeded"
+	ApplyStoppedReasonInterrupted = "interrupted"
+)
+
+// ApplyStoppedError is returned when applying of a change set was stopped
+// before all changes were applied and waited on. No new changes are
+// issued once it is returned; changes that were already in flight complete.
+type ApplyStoppedError struct {
+	Reason string
+
+	NumTotal   int
+	NumApplied int
+	NumWaited  int
+}
+
+var _ error = ApplyStoppedError{}
+
+func (e ApplyStoppedError) Error() string {
+	return fmt.Sprintf("Stopped applying changes (%s): applied %d/%d, waited %d/%d",
+		e.Reason, e.NumApplied, e.NumTotal, e.NumWaited, e.NumTotal)
+}
+
+// applyStop tracks conditions under which change set application should stop
+type applyStop struct {
+	deadline    time.Time
+	interruptCh <-chan struct{}
+}
+
+func (s applyStop) Reason() (string, bool) {
+	if s.interruptCh != nil {
+		select {
+		case <-s.interruptCh:
+			return ApplyStoppedReasonInterrupted, true
+		default:
+		}
+	}
+	if !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		return ApplyStoppedReasonDeadline, true
+	}
+	return "", false
+}
+
+type applyStoppedErr struct {
+	reason string
+}
+
+func (e applyStoppedErr) Error() string { return "Stopped applying changes: " + e.reason }
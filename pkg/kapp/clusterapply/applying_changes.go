@@ -24,10 +24,15 @@ type ApplyingChanges struct {
 	clusterChangeFactory ClusterChangeFactory
 	ui                   UI
 	exitOnError          bool
+	stop                 applyStop
+	progress             progressEvents
 }
 
-func NewApplyingChanges(numTotal int, opts ApplyingChangesOpts, clusterChangeFactory ClusterChangeFactory, ui UI, exitOnError bool) *ApplyingChanges {
-	return &ApplyingChanges{numTotal, opts, map[*ctldgraph.Change]struct{}{}, clusterChangeFactory, ui, exitOnError}
+func NewApplyingChanges(numTotal int, opts ApplyingChangesOpts, clusterChangeFactory ClusterChangeFactory,
+	ui UI, exitOnError bool, stop applyStop, progressFunc ProgressEventFunc) *ApplyingChanges {
+
+	return &ApplyingChanges{numTotal, opts, map[*ctldgraph.Change]struct{}{},
+		clusterChangeFactory, ui, exitOnError, stop, progressEvents{progressFunc}}
 }
 
 type applyResult struct {
@@ -36,6 +41,7 @@ type applyResult struct {
 	DescMsgs      []string
 	Retryable     bool
 	Err           error
+	Skipped       bool
 }
 
 func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange, []string, error) {
@@ -68,6 +74,13 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 				applyThrottle.Take()
 				defer applyThrottle.Done()
 
+				// Do not start applying queued up changes once stopped;
+				// changes that are already in flight will complete
+				if _, stopped := c.stop.Reason(); stopped {
+					applyCh <- applyResult{Change: change, Skipped: true}
+					return
+				}
+
 				clusterChange := change.Change.(wrappedClusterChange).ClusterChange
 				retryable, descMsgs, err := clusterChange.Apply()
 
@@ -83,15 +96,22 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 
 		var appliedChanges []WaitingChange
 		var lastErr error
+		var skippedChanges bool
 
 		for i := 0; i < len(nonAppliedChanges); i++ {
 			result := <-applyCh
 
+			if result.Skipped {
+				skippedChanges = true
+				continue
+			}
+
 			c.ui.Notify(result.DescMsgs)
 
 			if result.Err != nil {
 				lastErr = result.Err
 				if !result.Retryable {
+					c.progress.emitChange(ProgressEventTypeChangeFailed, result.ClusterChange, "", result.Err)
 					if c.exitOnError {
 						return nil, nil, result.Err
 					}
@@ -102,9 +122,15 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 			}
 
 			c.markApplied(result.Change)
+			c.progress.emitChange(ProgressEventTypeChangeApplied, result.ClusterChange, "", nil)
 			appliedChanges = append(appliedChanges, WaitingChange{result.Change, result.ClusterChange, time.Now()})
 		}
 
+		if skippedChanges {
+			reason, _ := c.stop.Reason()
+			return appliedChanges, unsuccessfulChangeDesc, applyStoppedErr{reason}
+		}
+
 		if len(appliedChanges) > 0 {
 			return appliedChanges, unsuccessfulChangeDesc, nil
 		}
@@ -113,6 +139,10 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 			return nil, unsuccessfulChangeDesc, fmt.Errorf("Timed out waiting after %s: Last error: %s", c.opts.Timeout, lastErr)
 		}
 
+		if reason, stopped := c.stop.Reason(); stopped {
+			return nil, unsuccessfulChangeDesc, applyStoppedErr{reason}
+		}
+
 		time.Sleep(c.opts.CheckInterval)
 	}
 }
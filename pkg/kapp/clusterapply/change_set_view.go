@@ -6,12 +6,12 @@ package clusterapply
 import (
 	"fmt"
 
-	"github.com/cppforlife/color"
 	"github.com/cppforlife/go-cli-ui/ui"
 	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
 	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
 )
 
 type ChangeSetViewOpts struct {
@@ -75,13 +75,13 @@ func (v ChangeSetView) printChangesYAML(ui ui.UI) error {
 			continue
 
 		case ClusterChangeApplyOpDelete:
-			opAndResDesc = color.RedString(opAndResDesc)
+			opAndResDesc = theme.Current().DiffRemoved.Sprintf("%s", opAndResDesc)
 
 		case ClusterChangeApplyOpExists:
-			opAndResDesc = color.GreenString(opAndResDesc)
+			opAndResDesc = theme.Current().DiffAdded.Sprintf("%s", opAndResDesc)
 
 		default:
-			opAndResDesc = color.GreenString(opAndResDesc)
+			opAndResDesc = theme.Current().DiffAdded.Sprintf("%s", opAndResDesc)
 			res, err := ctlres.NewResourceWithManagedFields(view.Resource(), false).Resource()
 			if err != nil {
 				return err
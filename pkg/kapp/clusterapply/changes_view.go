@@ -263,15 +263,20 @@ type ValueResourceConverged struct {
 }
 
 func NewValueResourceConverged(resource ctlres.Resource) ValueResourceConverged {
-	// TODO how to retrieve waiting rules
-	convergedResFactory := NewConvergedResourceFactory(nil, ConvergedResourceFactoryOpts{})
-
-	// TODO state vs err vs output
-	state, _, err := convergedResFactory.New(resource, nil).IsDoneApplying()
-	stateUI := NewDoneApplyStateUI(state, err)
+	stateUI := NewResourceConvergedStateUI(resource)
 
 	stateVal := uitable.ValueFmt{V: uitable.NewValueString(stateUI.State), Error: stateUI.Error}
 	reasonVal := uitable.NewValueString(wordwrap.WrapString(stateUI.Message, 35))
 
 	return ValueResourceConverged{stateVal, reasonVal}
 }
+
+// NewResourceConvergedStateUI returns reconcile state of a resource as shown in the UI
+func NewResourceConvergedStateUI(resource ctlres.Resource) DoneApplyStateUI {
+	// TODO how to retrieve waiting rules
+	convergedResFactory := NewConvergedResourceFactory(nil, ConvergedResourceFactoryOpts{})
+
+	// TODO state vs err vs output
+	state, _, err := convergedResFactory.New(resource, nil).IsDoneApplying()
+	return NewDoneApplyStateUI(state, err)
+}
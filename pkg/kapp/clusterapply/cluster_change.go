@@ -53,6 +53,15 @@ type ClusterChangeOpts struct {
 	Wait         bool
 	WaitIgnored  bool
 
+	// Wait failures of matched resources are reported
+	// as warnings and do not fail overall apply
+	NonBlockingWaitMatcher ctlres.ResourceMatcher
+	// Last matching rule determines how resource is waited on
+	WaitBehaviorRules []ctlconf.WaitBehaviorRule
+
+	// Label key recorded for the app (removed from orphaned resources)
+	AppLabelKey string
+
 	AddOrUpdateChangeOpts
 }
 
@@ -68,6 +77,8 @@ type ClusterChange struct {
 	markedNeedsWaiting bool
 
 	diffMaskRules []ctlconf.DiffMaskRule
+
+	result *clusterChangeResultRecorder
 }
 
 var _ ChangeView = &ClusterChange{}
@@ -80,7 +91,7 @@ func NewClusterChange(change ctldiff.Change, opts ClusterChangeOpts,
 	diffMaskRules []ctlconf.DiffMaskRule) *ClusterChange {
 
 	return &ClusterChange{change, opts, identifiedResources,
-		changeFactory, changeSetFactory, convergedResFactory, ui, false, diffMaskRules}
+		changeFactory, changeSetFactory, convergedResFactory, ui, false, diffMaskRules, &clusterChangeResultRecorder{}}
 }
 
 func (c *ClusterChange) ApplyOp() ClusterChangeApplyOp {
@@ -123,6 +134,10 @@ func (c *ClusterChange) WaitOp() ClusterChangeWaitOp {
 		return ClusterChangeWaitOpNoop
 	}
 
+	if rule := c.waitBehaviorRule(); rule != nil && rule.Type == ctlconf.WaitBehaviorTypeDisable {
+		return ClusterChangeWaitOpNoop
+	}
+
 	switch c.change.Op() {
 	case ctldiff.ChangeOpAdd, ctldiff.ChangeOpUpdate:
 		return ClusterChangeWaitOpOK
@@ -179,13 +194,14 @@ func (c *ClusterChange) Apply() (bool, []string, error) {
 
 	strategy, err := c.applyStrategy()
 	if err != nil {
+		c.result.recordApply(false, err)
 		return false, descMsgs, err
 	}
 
 	err = strategy.Apply()
 	if err != nil {
 		switch err.(type) {
-		case ExistsChangeError:
+		case ExistsChangeError, ScaleToZeroPendingError:
 			retryable = true
 		default:
 			retryable = ctlres.IsResourceChangeBlockedErr(err)
@@ -193,10 +209,13 @@ func (c *ClusterChange) Apply() (bool, []string, error) {
 	}
 
 	if retryable {
-		descMsgs = append(descMsgs, uiWaitMsgPrefix+"Retryable error: "+err.Error())
+		descMsgs = append(descMsgs, uiWaitMsgPrefix()+"Retryable error: "+err.Error())
 	}
 
-	return retryable, descMsgs, c.applyErr(err)
+	err = c.applyErr(err)
+	c.result.recordApply(retryable, err)
+
+	return retryable, descMsgs, err
 }
 
 func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
@@ -209,7 +228,7 @@ func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
 			c.changeSetFactory, c.opts.AddOrUpdateChangeOpts, c.diffMaskRules}.ApplyStrategy()
 
 	case ClusterChangeApplyOpDelete:
-		return DeleteChange{c.change, c.identifiedResources}.ApplyStrategy()
+		return DeleteChange{c.change, c.identifiedResources, c.opts.AppLabelKey}.ApplyStrategy()
 
 	case ClusterChangeApplyOpNoop:
 		return NoopStrategy{}, nil
@@ -224,6 +243,7 @@ func (c *ClusterChange) applyStrategy() (ApplyStrategy, error) {
 
 func (c *ClusterChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, error) {
 	state, descMsgs, err := c.isDoneApplying()
+	c.result.recordWait(state, err)
 	primaryDescMsg := fmt.Sprintf("%s: %s", NewDoneApplyStateUI(state, err).State, c.WaitDescription())
 	return state, append([]string{primaryDescMsg}, descMsgs...), err
 }
@@ -233,10 +253,25 @@ func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, erro
 
 	switch op {
 	case ClusterChangeWaitOpOK:
-		return ReconcilingChange{c.change, c.identifiedResources, c.convergedResFactory}.IsDoneApplying()
+		rule := c.waitBehaviorRule()
+		if rule != nil && rule.Type == ctlconf.WaitBehaviorTypeNoop {
+			return ctlresm.DoneApplyState{Done: true, Successful: true}, nil, nil
+		}
+
+		state, descMsgs, err := ReconcilingChange{c.change, c.identifiedResources, c.convergedResFactory}.IsDoneApplying()
+		if err != nil || !state.Done || !state.Successful {
+			return state, descMsgs, err
+		}
+
+		if rule != nil && rule.Type == ctlconf.WaitBehaviorTypeConditions {
+			state, err := c.isDoneWithConditions(rule.Conditions)
+			return state, descMsgs, err
+		}
+
+		return state, descMsgs, nil
 
 	case ClusterChangeWaitOpDelete:
-		return DeleteChange{c.change, c.identifiedResources}.IsDoneApplying()
+		return DeleteChange{c.change, c.identifiedResources, c.opts.AppLabelKey}.IsDoneApplying()
 
 	case ClusterChangeWaitOpNoop:
 		return ctlresm.DoneApplyState{Done: true, Successful: true}, nil, nil
@@ -246,6 +281,33 @@ func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, erro
 	}
 }
 
+func (c *ClusterChange) waitBehaviorRule() *ctlconf.WaitBehaviorRule {
+	var result *ctlconf.WaitBehaviorRule
+	for i, rule := range c.opts.WaitBehaviorRules {
+		if rule.ResourceMatcher().Matches(c.Resource()) {
+			result = &c.opts.WaitBehaviorRules[i]
+		}
+	}
+	return result
+}
+
+func (c *ClusterChange) isDoneWithConditions(conditions []string) (ctlresm.DoneApplyState, error) {
+	// Resource in cluster has up-to-date status unlike applied resource
+	res, err := c.identifiedResources.Get(c.Resource())
+	if err != nil {
+		return ctlresm.DoneApplyState{}, err
+	}
+
+	if ok, msg := ctlresm.NewConditions(res).IsSelectedTrue(conditions); !ok {
+		return ctlresm.DoneApplyState{Done: false, Message: msg}, nil
+	}
+	return ctlresm.DoneApplyState{Done: true, Successful: true}, nil
+}
+
+func (c *ClusterChange) IsNonBlockingWait() bool {
+	return c.opts.NonBlockingWaitMatcher != nil && c.opts.NonBlockingWaitMatcher.Matches(c.Resource())
+}
+
 func (c *ClusterChange) ApplyDescription() string {
 	return fmt.Sprintf("%s %s", applyOpCodeUI[c.ApplyOp()], c.change.NewOrExistingResource().Description())
 }
@@ -254,6 +316,9 @@ func (c *ClusterChange) WaitDescription() string {
 	return fmt.Sprintf("%s %s", waitOpCodeUI[c.WaitOp()], c.change.NewOrExistingResource().Description())
 }
 
+// Result returns outcome of applying and waiting on this change so far
+func (c *ClusterChange) Result() ClusterChangeResult { return c.result.get() }
+
 func (c *ClusterChange) Resource() ctlres.Resource { return c.change.NewOrExistingResource() }
 
 func (c *ClusterChange) ClusterOriginalResource() ctlres.Resource {
@@ -0,0 +1,87 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"sync"
+
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+)
+
+type ClusterChangeResultState string
+
+const (
+	ClusterChangeResultStatePending    ClusterChangeResultState = "pending"
+	ClusterChangeResultStateSucceeded  ClusterChangeResultState = "succeeded"
+	ClusterChangeResultStateFailed     ClusterChangeResultState = "failed"
+	ClusterChangeResultStateInProgress ClusterChangeResultState = "in-progress"
+)
+
+// ClusterChangeResult describes outcome of applying and waiting
+// on a cluster change so far (e.g. to be reported after apply)
+type ClusterChangeResult struct {
+	ApplyState ClusterChangeResultState
+	ApplyError string
+
+	WaitState   ClusterChangeResultState
+	WaitMessage string
+	WaitError   string
+}
+
+type clusterChangeResultRecorder struct {
+	lock   sync.Mutex
+	result ClusterChangeResult
+}
+
+func (r *clusterChangeResultRecorder) recordApply(retryable bool, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch {
+	case err == nil:
+		r.result.ApplyState = ClusterChangeResultStateSucceeded
+		r.result.ApplyError = ""
+	case retryable:
+		// Will be retried, hence outcome is not known yet
+		r.result.ApplyState = ClusterChangeResultStateInProgress
+		r.result.ApplyError = err.Error()
+	default:
+		r.result.ApplyState = ClusterChangeResultStateFailed
+		r.result.ApplyError = err.Error()
+	}
+}
+
+func (r *clusterChangeResultRecorder) recordWait(state ctlresm.DoneApplyState, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.result.WaitMessage = state.Message
+	r.result.WaitError = ""
+
+	switch {
+	case err != nil:
+		r.result.WaitState = ClusterChangeResultStateFailed
+		r.result.WaitError = err.Error()
+	case !state.Done:
+		r.result.WaitState = ClusterChangeResultStateInProgress
+	case state.Successful:
+		r.result.WaitState = ClusterChangeResultStateSucceeded
+	default:
+		r.result.WaitState = ClusterChangeResultStateFailed
+	}
+}
+
+func (r *clusterChangeResultRecorder) get() ClusterChangeResult {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	result := r.result
+	if len(result.ApplyState) == 0 {
+		result.ApplyState = ClusterChangeResultStatePending
+	}
+	if len(result.WaitState) == 0 {
+		result.WaitState = ClusterChangeResultStatePending
+	}
+	return result
+}
@@ -4,8 +4,10 @@
 package clusterapply
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
 	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
@@ -20,6 +22,14 @@ type ClusterChangeSetOpts struct {
 
 	ExitEarlyOnApplyError bool
 	ExitEarlyOnWaitError  bool
+
+	// Deadline bounds the whole apply (including waiting);
+	// no new changes are applied after it passes (zero means no deadline)
+	Deadline time.Time
+	// Interrupt stops applying of new changes once closed
+	Interrupt <-chan struct{}
+	// ProgressFunc receives machine readable progress events (optional)
+	ProgressFunc ProgressEventFunc
 }
 
 type ClusterChangeSet struct {
@@ -96,19 +106,28 @@ func (c ClusterChangeSet) markChangesToWait(change *ctldgraph.Change) bool {
 func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 	defer c.logger.DebugFunc("Apply").Finish()
 
+	// Wait rule plugins are only used while waiting for changes
+	defer c.clusterChangeFactory.convergedResFactory.Close()
+
 	expectedNumChanges := len(changesGraph.All())
 
+	stop := applyStop{deadline: c.opts.Deadline, interruptCh: c.opts.Interrupt}
 	blockedChanges := ctldgraph.NewBlockedChanges(changesGraph)
-	applyingChanges := NewApplyingChanges(
-		expectedNumChanges, c.opts.ApplyingChangesOpts, c.clusterChangeFactory, c.ui, c.opts.ExitEarlyOnApplyError)
-	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts, c.ui, c.opts.ExitEarlyOnWaitError)
+	applyingChanges := NewApplyingChanges(expectedNumChanges, c.opts.ApplyingChangesOpts,
+		c.clusterChangeFactory, c.ui, c.opts.ExitEarlyOnApplyError, stop, c.opts.ProgressFunc)
+	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts,
+		c.ui, c.opts.ExitEarlyOnWaitError, stop, c.opts.ProgressFunc, c.logger)
 
 	var unsuccessfulChanges []string
 
 	for {
+		if reason, stopped := stop.Reason(); stopped {
+			return c.stoppedErr(reason, applyingChanges, waitingChanges)
+		}
+
 		appliedChanges, unsuccessfulChangeDesc, err := applyingChanges.Apply(blockedChanges.Unblocked())
 		if err != nil {
-			return err
+			return c.wrapStoppedErr(err, applyingChanges, waitingChanges)
 		}
 
 		unsuccessfulChanges = append(unsuccessfulChanges, unsuccessfulChangeDesc...)
@@ -135,7 +154,7 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 
 		doneChanges, unsuccessfulChangeDesc, err := waitingChanges.WaitForAny()
 		if err != nil {
-			return err
+			return c.wrapStoppedErr(err, applyingChanges, waitingChanges)
 		}
 
 		unsuccessfulChanges = append(unsuccessfulChanges, unsuccessfulChangeDesc...)
@@ -146,6 +165,25 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 	}
 }
 
+func (c ClusterChangeSet) wrapStoppedErr(err error, applyingChanges *ApplyingChanges, waitingChanges *WaitingChanges) error {
+	var stoppedErr applyStoppedErr
+	if errors.As(err, &stoppedErr) {
+		return c.stoppedErr(stoppedErr.reason, applyingChanges, waitingChanges)
+	}
+	return err
+}
+
+func (c ClusterChangeSet) stoppedErr(reason string, applyingChanges *ApplyingChanges, waitingChanges *WaitingChanges) error {
+	err := ApplyStoppedError{
+		Reason:     reason,
+		NumTotal:   applyingChanges.numTotal,
+		NumApplied: applyingChanges.numApplied(),
+		NumWaited:  waitingChanges.numWaited,
+	}
+	c.ui.NotifySection("stopped applying changes (%s) %s", reason, applyingChanges.stats())
+	return err
+}
+
 func ClusterChangesAsChangeViews(changes []*ClusterChange) []ChangeView {
 	var result []ChangeView
 	for _, change := range changes {
@@ -8,9 +8,9 @@ import (
 	"reflect"
 	"sort"
 
-	"github.com/cppforlife/color"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
 )
 
 const (
@@ -162,24 +162,23 @@ func (c ConvergedResource) isResourceDoneApplying(res ctlres.Resource,
 	return nil, nil
 }
 
-var (
-	uiWaitChildPrefix    = color.New(color.Faint).Sprintf(" L ") // consistent with inspect tree view
-	uiWaitMsgPrefix      = color.New(color.Faint).Sprintf(" ^ ")
-	uiWaitChildMsgPrefix = "   " + uiWaitMsgPrefix
-)
+// Prefixes are styled at the time of use since theme is configured after startup
+func uiWaitChildPrefix() string    { return theme.Current().Decoration.Sprintf(" L ") } // consistent with inspect tree view
+func uiWaitMsgPrefix() string      { return theme.Current().Decoration.Sprintf(" ^ ") }
+func uiWaitChildMsgPrefix() string { return "   " + uiWaitMsgPrefix() }
 
 func (c ConvergedResource) buildParentDescMsg(_ ctlres.Resource, state ctlresm.DoneApplyState) []string {
 	if len(state.Message) > 0 {
-		return []string{uiWaitMsgPrefix + state.Message}
+		return []string{uiWaitMsgPrefix() + state.Message}
 	}
 	return []string{}
 }
 
 func (c ConvergedResource) buildChildDescMsg(res ctlres.Resource, state ctlresm.DoneApplyState) []string {
-	msgs := []string{fmt.Sprintf(uiWaitChildPrefix+"%s: waiting on %s", NewDoneApplyStateUI(state, nil).State, res.Description())}
+	msgs := []string{fmt.Sprintf(uiWaitChildPrefix()+"%s: waiting on %s", NewDoneApplyStateUI(state, nil).State, res.Description())}
 
 	if len(state.Message) > 0 {
-		msgs = append(msgs, uiWaitChildMsgPrefix+state.Message)
+		msgs = append(msgs, uiWaitChildMsgPrefix()+state.Message)
 	}
 
 	return msgs
@@ -12,16 +12,23 @@ import (
 
 type ConvergedResourceFactoryOpts struct {
 	IgnoreFailingAPIServices bool
+	WaitForServiceEndpoints  bool
 }
 
 type ConvergedResourceFactory struct {
-	waitRules []ctlconf.WaitRule
-	opts      ConvergedResourceFactoryOpts
+	waitRules       []ctlconf.WaitRule
+	waitRulePlugins *ctlresm.WaitRulePlugins
+	opts            ConvergedResourceFactoryOpts
 }
 
 func NewConvergedResourceFactory(waitRules []ctlconf.WaitRule,
 	opts ConvergedResourceFactoryOpts) ConvergedResourceFactory {
-	return ConvergedResourceFactory{waitRules, opts}
+	return ConvergedResourceFactory{waitRules, ctlresm.NewWaitRulePlugins(), opts}
+}
+
+// Close stops plugin processes started for wait rules
+func (f ConvergedResourceFactory) Close() error {
+	return f.waitRulePlugins.Close()
 }
 
 func (f ConvergedResourceFactory) New(res ctlres.Resource,
@@ -44,7 +51,7 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 			return ctlresm.NewDeleting(res), nil
 		},
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
-			return ctlresm.NewCustomWaitingResource(res, f.waitRules), nil
+			return ctlresm.NewCustomWaitingResource(res, f.waitRules, f.waitRulePlugins), nil
 		},
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			return ctlresm.NewAPIExtensionsVxCRD(res), nil
@@ -58,8 +65,12 @@ func (f ConvergedResourceFactory) New(res ctlres.Resource,
 		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			return ctlresm.NewCoreV1Pod(res), nil
 		},
-		func(res ctlres.Resource, _ []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
-			return ctlresm.NewCoreV1Service(res), nil
+		func(res ctlres.Resource, aRs []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
+			svc := ctlresm.NewCoreV1Service(res, aRs, f.opts.WaitForServiceEndpoints)
+			if svc != nil && svc.WaitsForEndpoints() {
+				return svc, []ctlres.ResourceRef{ctlresm.CoreV1ServiceEndpointSlicesRef}
+			}
+			return svc, nil
 		},
 		func(res ctlres.Resource, aRs []ctlres.Resource) (SpecificResource, []ctlres.ResourceRef) {
 			// Use newly provided associated resources as they may be modified by ConvergedResource
@@ -20,8 +20,10 @@ const (
 	deleteStrategyPlainAnnValue  ClusterChangeApplyStrategyOp = ""
 	deleteStrategyOrphanAnnValue ClusterChangeApplyStrategyOp = "orphan"
 
-	appLabelKey      = "kapp.k14s.io/app" // TODO duplicated here
-	orphanedLabelKey = "kapp.k14s.io/orphaned"
+	deleteStrategyScaleToZeroAnnValue ClusterChangeApplyStrategyOp = "scale-to-zero"
+
+	defaultAppLabelKey = "kapp.k14s.io/app" // TODO duplicated here
+	orphanedLabelKey   = "kapp.k14s.io/orphaned"
 )
 
 var (
@@ -31,6 +33,7 @@ var (
 type DeleteChange struct {
 	change              ctldiff.Change
 	identifiedResources ctlres.IdentifiedResources
+	appLabelKey         string
 }
 
 type inoperableResourceRef struct {
@@ -73,6 +76,9 @@ func (c DeleteChange) ApplyStrategy() (ApplyStrategy, error) {
 	case deleteStrategyOrphanAnnValue:
 		return DeleteOrphanStrategy{res, c}, nil
 
+	case deleteStrategyScaleToZeroAnnValue:
+		return DeleteScaleToZeroStrategy{res, c}, nil
+
 	default:
 		return nil, fmt.Errorf("Unknown delete strategy: %s", strategy)
 	}
@@ -132,7 +138,7 @@ func (c DeleteOrphanStrategy) Apply() error {
 		// Reason: In labeled app labels are not accessible in delete operation now. Without the label info kapp can not apply the changes
 		map[string]interface{}{
 			"op":   "remove",
-			"path": "/metadata/labels/" + jsonPointerEncoder.Replace(appLabelKey),
+			"path": "/metadata/labels/" + jsonPointerEncoder.Replace(c.d.labelKey()),
 		},
 		map[string]interface{}{
 			"op":    "add",
@@ -150,9 +156,16 @@ func (c DeleteOrphanStrategy) Apply() error {
 	return err
 }
 
+func (c DeleteChange) labelKey() string {
+	if len(c.appLabelKey) > 0 {
+		return c.appLabelKey
+	}
+	return defaultAppLabelKey
+}
+
 func descMessage(res ctlres.Resource) []string {
 	if res.IsDeleting() {
-		return []string{uiWaitMsgPrefix +
+		return []string{uiWaitMsgPrefix() +
 			ctlresm.NewDeleting(res).IsDoneApplying().Message}
 	}
 	return []string{}
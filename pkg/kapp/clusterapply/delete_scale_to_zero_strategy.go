@@ -0,0 +1,118 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"fmt"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DeleteScaleToZeroStrategy scales workloads down to zero replicas
+// and waits for all of their pods to terminate before deleting them.
+// Resources that cannot be scaled are deleted as usual.
+type DeleteScaleToZeroStrategy struct {
+	res ctlres.Resource
+	d   DeleteChange
+}
+
+func (c DeleteScaleToZeroStrategy) Op() ClusterChangeApplyStrategyOp {
+	return deleteStrategyScaleToZeroAnnValue
+}
+
+func (c DeleteScaleToZeroStrategy) Apply() error {
+	latestRes, err := c.d.identifiedResources.Get(c.res)
+	if err != nil {
+		return err
+	}
+
+	replicas, selector, scalable, err := c.scaleSpec(latestRes)
+	if err != nil {
+		return err
+	}
+	if !scalable {
+		return c.d.identifiedResources.Delete(c.res)
+	}
+
+	if replicas == nil || *replicas != 0 {
+		_, err := c.d.identifiedResources.Patch(latestRes, types.MergePatchType, []byte(`{"spec":{"replicas":0}}`))
+		if err != nil {
+			return fmt.Errorf("Scaling to zero: %w", err)
+		}
+		return ScaleToZeroPendingError{"Scaled to zero replicas, waiting for pods to terminate"}
+	}
+
+	numPods, err := c.numPods(latestRes, selector)
+	if err != nil {
+		return err
+	}
+	if numPods > 0 {
+		return ScaleToZeroPendingError{fmt.Sprintf("Waiting for %d pods to terminate", numPods)}
+	}
+
+	return c.d.identifiedResources.Delete(c.res)
+}
+
+func (c DeleteScaleToZeroStrategy) scaleSpec(res ctlres.Resource) (*int32, *metav1.LabelSelector, bool, error) {
+	switch {
+	case (ctlres.APIVersionKindMatcher{APIVersion: "apps/v1", Kind: "Deployment"}).Matches(res):
+		var obj appsv1.Deployment
+		err := res.AsTypedObj(&obj)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return obj.Spec.Replicas, obj.Spec.Selector, true, nil
+
+	case (ctlres.APIVersionKindMatcher{APIVersion: "apps/v1", Kind: "StatefulSet"}).Matches(res):
+		var obj appsv1.StatefulSet
+		err := res.AsTypedObj(&obj)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return obj.Spec.Replicas, obj.Spec.Selector, true, nil
+
+	default:
+		return nil, nil, false, nil
+	}
+}
+
+func (c DeleteScaleToZeroStrategy) numPods(res ctlres.Resource, selector *metav1.LabelSelector) (int, error) {
+	if selector == nil {
+		return 0, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return 0, fmt.Errorf("Parsing pod selector: %w", err)
+	}
+
+	podRefs := []ctlres.ResourceRef{{schema.GroupVersionResource{Version: "v1", Resource: "pods"}}}
+
+	pods, err := c.d.identifiedResources.List(labelSelector, podRefs, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: []string{res.Namespace()},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Listing pods: %w", err)
+	}
+
+	var num int
+	for _, pod := range pods {
+		if pod.Namespace() == res.Namespace() {
+			num++
+		}
+	}
+	return num, nil
+}
+
+// ScaleToZeroPendingError indicates that deletion has to be retried
+// once workload pods have terminated
+type ScaleToZeroPendingError struct {
+	msg string
+}
+
+func (e ScaleToZeroPendingError) Error() string { return e.msg }
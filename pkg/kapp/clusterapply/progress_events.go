@@ -0,0 +1,95 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+import (
+	"time"
+)
+
+type ProgressEventType string
+
+const (
+	ProgressEventTypeDiffComputed   ProgressEventType = "diffComputed"
+	ProgressEventTypeChangeApplied  ProgressEventType = "changeApplied"
+	ProgressEventTypeChangeFailed   ProgressEventType = "changeFailed"
+	ProgressEventTypeResourceReady  ProgressEventType = "resourceReady"
+	ProgressEventTypeResourceFailed ProgressEventType = "resourceFailed"
+	ProgressEventTypeWaitTimeout    ProgressEventType = "waitTimeout"
+	ProgressEventTypeCompleted      ProgressEventType = "completed"
+)
+
+// ProgressEvent describes single step of applying changes
+// in a machine readable way (e.g. to render live progress)
+type ProgressEvent struct {
+	Type ProgressEventType `json:"type"`
+	Time time.Time         `json:"time"`
+
+	Resource *ProgressEventResource `json:"resource,omitempty"`
+	Op       string                 `json:"op,omitempty"`
+	WaitOp   string                 `json:"waitOp,omitempty"`
+
+	// Populated for diffComputed event
+	Ops map[string]int `json:"ops,omitempty"`
+
+	Message    string `json:"message,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Successful *bool  `json:"successful,omitempty"`
+}
+
+type ProgressEventResource struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+}
+
+// ProgressEventFunc receives events in order they happen (never concurrently)
+type ProgressEventFunc func(ProgressEvent)
+
+type progressEvents struct {
+	fn ProgressEventFunc
+}
+
+func (p progressEvents) emit(event ProgressEvent) {
+	if p.fn == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	p.fn(event)
+}
+
+func (p progressEvents) emitChange(eventType ProgressEventType, change *ClusterChange, msg string, err error) {
+	event := ProgressEvent{
+		Type:     eventType,
+		Resource: NewProgressEventResource(change),
+		Op:       string(change.ApplyOp()),
+		WaitOp:   string(change.WaitOp()),
+		Message:  msg,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	p.emit(event)
+}
+
+func NewProgressEventResource(change *ClusterChange) *ProgressEventResource {
+	res := change.Resource()
+	return &ProgressEventResource{
+		Namespace:  res.Namespace(),
+		Name:       res.Name(),
+		Kind:       res.Kind(),
+		APIVersion: res.APIVersion(),
+	}
+}
+
+// NewDiffComputedProgressEvent summarizes calculated changes
+func NewDiffComputedProgressEvent(changes []*ClusterChange) ProgressEvent {
+	ops := map[string]int{}
+	for _, change := range changes {
+		ops[string(change.ApplyOp())]++
+	}
+	return ProgressEvent{Type: ProgressEventTypeDiffComputed, Time: time.Now().UTC(), Ops: ops}
+}
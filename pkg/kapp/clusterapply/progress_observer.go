@@ -0,0 +1,45 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply
+
+// ProgressObserver is notified about progress of applying changes so that
+// frontends (e.g. NDJSON progress, programs embedding kapp) do not depend
+// on text output. Methods are called in order events happen (never concurrently).
+type ProgressObserver interface {
+	OnDiffComputed(ProgressEvent)
+	// OnChangeApplied is also called when change failed to apply (event type is changeFailed)
+	OnChangeApplied(ProgressEvent)
+	// OnResourceReady is also called when resource failed to reconcile (event type is resourceFailed)
+	OnResourceReady(ProgressEvent)
+	OnWaitTimeout(ProgressEvent)
+}
+
+// NoopProgressObserver can be embedded by observers that are only interested in some events
+type NoopProgressObserver struct{}
+
+var _ ProgressObserver = NoopProgressObserver{}
+
+func (NoopProgressObserver) OnDiffComputed(ProgressEvent)  {}
+func (NoopProgressObserver) OnChangeApplied(ProgressEvent) {}
+func (NoopProgressObserver) OnResourceReady(ProgressEvent) {}
+func (NoopProgressObserver) OnWaitTimeout(ProgressEvent)   {}
+
+// NewObserverProgressFunc returns progress func that dispatches events
+// to observers (in given order) based on event type
+func NewObserverProgressFunc(observers ...ProgressObserver) ProgressEventFunc {
+	return func(event ProgressEvent) {
+		for _, observer := range observers {
+			switch event.Type {
+			case ProgressEventTypeDiffComputed:
+				observer.OnDiffComputed(event)
+			case ProgressEventTypeChangeApplied, ProgressEventTypeChangeFailed:
+				observer.OnChangeApplied(event)
+			case ProgressEventTypeResourceReady, ProgressEventTypeResourceFailed:
+				observer.OnResourceReady(event)
+			case ProgressEventTypeWaitTimeout:
+				observer.OnWaitTimeout(event)
+			}
+		}
+	}
+}
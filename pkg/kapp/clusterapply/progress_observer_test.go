@@ -0,0 +1,47 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterapply_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+type recordingObserver struct {
+	ctlcap.NoopProgressObserver
+	applied []ctlcap.ProgressEventType
+	timeout int
+}
+
+func (o *recordingObserver) OnChangeApplied(event ctlcap.ProgressEvent) {
+	o.applied = append(o.applied, event.Type)
+}
+
+func (o *recordingObserver) OnWaitTimeout(ctlcap.ProgressEvent) { o.timeout++ }
+
+func TestNewObserverProgressFuncDispatchesByType(t *testing.T) {
+	observer1 := &recordingObserver{}
+	observer2 := &recordingObserver{}
+
+	progressFunc := ctlcap.NewObserverProgressFunc(observer1, observer2)
+
+	for _, eventType := range []ctlcap.ProgressEventType{
+		ctlcap.ProgressEventTypeDiffComputed,
+		ctlcap.ProgressEventTypeChangeApplied,
+		ctlcap.ProgressEventTypeResourceReady,
+		ctlcap.ProgressEventTypeChangeFailed,
+		ctlcap.ProgressEventTypeWaitTimeout,
+		ctlcap.ProgressEventTypeCompleted,
+	} {
+		progressFunc(ctlcap.ProgressEvent{Type: eventType})
+	}
+
+	for _, observer := range []*recordingObserver{observer1, observer2} {
+		require.Equal(t, []ctlcap.ProgressEventType{
+			ctlcap.ProgressEventTypeChangeApplied, ctlcap.ProgressEventTypeChangeFailed}, observer.applied)
+		require.Equal(t, 1, observer.timeout)
+	}
+}
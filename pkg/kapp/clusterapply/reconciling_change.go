@@ -8,6 +8,7 @@ import (
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type ReconcilingChange struct {
@@ -31,5 +32,14 @@ func (c ReconcilingChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, e
 		return ctlresm.DoneApplyState{}, nil, err
 	}
 
-	return c.convergedResFactory.New(parentRes, labeledResources.GetAssociated).IsDoneApplying()
+	associatedRsFunc := func(res ctlres.Resource, resRefs []ctlres.ResourceRef) ([]ctlres.Resource, error) {
+		// EndpointSlices are managed by Kubernetes and do not carry association label
+		if svc := ctlresm.NewCoreV1Service(res, nil, false); svc != nil {
+			return c.identifiedResources.List(
+				labels.Set(svc.EndpointSlicesSelector()).AsSelector(), resRefs, ctlres.IdentifiedResourcesListOpts{})
+		}
+		return labeledResources.GetAssociated(res, resRefs)
+	}
+
+	return c.convergedResFactory.New(parentRes, associatedRsFunc).IsDoneApplying()
 }
@@ -10,7 +10,9 @@ import (
 
 	uierrs "github.com/cppforlife/go-cli-ui/errors"
 	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
 	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
 )
 
@@ -19,8 +21,19 @@ type WaitingChangesOpts struct {
 	ResourceTimeout time.Duration
 	CheckInterval   time.Duration
 	Concurrency     int
+
+	// ChangedFunc returns channel closed once any of waited resources may
+	// have changed so that they are checked before check interval passes
+	// (nil means that resources are checked every check interval)
+	ChangedFunc func() <-chan struct{}
 }
 
+const (
+	// Multiple resources usually change at once (e.g. as they are reconciled),
+	// hence checking them right after first change would waste requests
+	waitingChangesChangedDebounce = 100 * time.Millisecond
+)
+
 type WaitingChanges struct {
 	numTotal       int // for ui
 	numWaited      int // for ui
@@ -28,6 +41,9 @@ type WaitingChanges struct {
 	opts           WaitingChangesOpts
 	ui             UI
 	exitOnError    bool
+	stop           applyStop
+	progress       progressEvents
+	logger         logger.Logger
 }
 
 type WaitingChange struct {
@@ -36,8 +52,11 @@ type WaitingChange struct {
 	startTime time.Time
 }
 
-func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, ui UI, exitOnError bool) *WaitingChanges {
-	return &WaitingChanges{numTotal, 0, nil, opts, ui, exitOnError}
+func NewWaitingChanges(numTotal int, opts WaitingChangesOpts, ui UI,
+	exitOnError bool, stop applyStop, progressFunc ProgressEventFunc, logger logger.Logger) *WaitingChanges {
+
+	return &WaitingChanges{numTotal, 0, nil, opts, ui, exitOnError,
+		stop, progressEvents{progressFunc}, logger.NewPrefixed("WaitingChanges")}
 }
 
 func (c *WaitingChanges) Track(changes []WaitingChange) {
@@ -59,6 +78,12 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 	startTime := time.Now()
 
 	for {
+		var changedCh <-chan struct{}
+		if c.opts.ChangedFunc != nil {
+			// Taken before checking so that changes made while checking are not missed
+			changedCh = c.opts.ChangedFunc()
+		}
+
 		c.ui.NotifySection("waiting on %d changes %s", len(c.trackedChanges), c.stats())
 
 		waitCh := make(chan waitResult, len(c.trackedChanges))
@@ -92,9 +117,17 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 
 			desc := fmt.Sprintf("waiting on %s", change.Cluster.WaitDescription())
 			c.ui.Notify(descMsgs)
+			c.logger.Debug("%s: done=%t successful=%t err=%v", desc, state.Done, state.Successful, err)
 
 			if err != nil {
+				c.progress.emitChange(ProgressEventTypeResourceFailed, change.Cluster, state.Message, err)
 				err = fmt.Errorf("%s: Errored: %w", desc, err)
+				if change.Cluster.IsNonBlockingWait() {
+					c.numWaited++
+					c.warnNonBlocking(err)
+					doneChanges = append(doneChanges, change)
+					continue
+				}
 				if c.exitOnError {
 					return nil, nil, err
 				}
@@ -119,12 +152,19 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 					msg += " (" + state.Message + ")"
 				}
 				err := fmt.Errorf("%s: Finished unsuccessfully%s", desc, msg)
+				c.progress.emitChange(ProgressEventTypeResourceFailed, change.Cluster, state.Message, nil)
+				if change.Cluster.IsNonBlockingWait() {
+					c.warnNonBlocking(err)
+					doneChanges = append(doneChanges, change)
+					continue
+				}
 				if c.exitOnError {
 					return nil, nil, err
 				}
 				unsuccessfulChangeDesc = append(unsuccessfulChangeDesc, err.Error())
 
 			case state.Done && state.Successful:
+				c.progress.emitChange(ProgressEventTypeResourceReady, change.Cluster, state.Message, nil)
 				doneChanges = append(doneChanges, change)
 			}
 		}
@@ -137,21 +177,68 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []string, error) {
 
 		if time.Now().Sub(startTime) > c.opts.Timeout {
 			var trackedResourcesDesc []string
+			var nonBlockingChanges []WaitingChange
 			for _, change := range c.trackedChanges {
+				c.progress.emitChange(ProgressEventTypeWaitTimeout, change.Cluster,
+					fmt.Sprintf("Timed out waiting after %s", c.opts.Timeout), nil)
+				if change.Cluster.IsNonBlockingWait() {
+					nonBlockingChanges = append(nonBlockingChanges, change)
+					continue
+				}
 				trackedResourcesDesc = append(trackedResourcesDesc, change.Cluster.Resource().Description())
 			}
-			return nil, unsuccessfulChangeDesc, uierrs.NewSemiStructuredError(fmt.Errorf("Timed out waiting after %s for resources: [%s]", c.opts.Timeout, strings.Join(trackedResourcesDesc, ", ")))
+			if len(trackedResourcesDesc) == 0 {
+				for _, change := range nonBlockingChanges {
+					c.numWaited++
+					c.warnNonBlocking(fmt.Errorf("waiting on %s: Timed out waiting after %s",
+						change.Cluster.WaitDescription(), c.opts.Timeout))
+				}
+				c.trackedChanges = nil
+				return nonBlockingChanges, unsuccessfulChangeDesc, nil
+			}
+			return nil, unsuccessfulChangeDesc, WaitTimeoutError{c.opts.Timeout, trackedResourcesDesc}
 		}
 
-		time.Sleep(c.opts.CheckInterval)
+		if reason, stopped := c.stop.Reason(); stopped {
+			return nil, unsuccessfulChangeDesc, applyStoppedErr{reason}
+		}
+
+		c.sleep(changedCh)
+	}
+}
+
+// sleep waits for check interval to pass or for resources to change
+func (c *WaitingChanges) sleep(changedCh <-chan struct{}) {
+	timer := time.NewTimer(c.opts.CheckInterval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-changedCh:
+		time.Sleep(waitingChangesChangedDebounce)
 	}
 }
 
+// WaitTimeoutError is returned when resources did not converge within wait timeout
+type WaitTimeoutError struct {
+	Timeout   time.Duration
+	Resources []string
+}
+
+func (e WaitTimeoutError) Error() string {
+	return uierrs.NewSemiStructuredError(fmt.Errorf("Timed out waiting after %s for resources: [%s]",
+		e.Timeout, strings.Join(e.Resources, ", "))).Error()
+}
+
 func (c *WaitingChanges) Complete() error {
 	c.ui.NotifySection("waiting complete %s", c.stats())
 	return nil
 }
 
+func (c *WaitingChanges) warnNonBlocking(err error) {
+	c.ui.Notify([]string{theme.Current().Warning.Sprintf("Warning: Ignoring failure of non-blocking resource: %s", err)})
+}
+
 func (c *WaitingChanges) stats() string {
 	return fmt.Sprintf("[%d/%d done]", c.numWaited, c.numTotal)
 }
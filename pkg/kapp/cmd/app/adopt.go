@@ -0,0 +1,325 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type AdoptOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags           Flags
+	FileFlags          cmdtools.FileFlags
+	ResourceTypesFlags ResourceTypesFlags
+	LockFlags          LockFlags
+
+	Selector                             string
+	IntoNamespace                        string
+	OverrideOwnershipOfExistingResources bool
+
+	FileSystem fs.FS
+}
+
+func NewAdoptOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *AdoptOptions {
+	return &AdoptOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewAdoptCmd(o *AdoptOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt",
+		Short: "Take ownership of existing resources without modifying them",
+		Long: `Take ownership of existing resources without modifying them.
+
+Resources are labeled as belonging to the app; their contents are not changed.
+Resources are selected either by label selector (--selector) or by resources
+specified in files (-f). Subsequent deploys of the app manage adopted resources
+as if they were created by kapp (e.g. adopted resources that are not part of
+deployed configuration will be deleted).`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+		},
+		Example: `
+  # Adopt resources specified in config/ into app 'app1'
+  kapp adopt -a app1 -f config/
+
+  # Adopt resources labeled with 'app=app1' into app 'app1'
+  kapp adopt -a app1 --selector app=app1`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.FileFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	o.LockFlags.Set(cmd)
+	cmd.Flags().StringVar(&o.Selector, "selector", "", "Adopt resources matching label selector (e.g. 'app=app1')")
+	cmd.Flags().StringVar(&o.IntoNamespace, "into-ns", "", "Look up resources specified in files in namespace")
+	cmd.Flags().BoolVar(&o.OverrideOwnershipOfExistingResources, "dangerous-override-ownership-of-existing-resources",
+		false, "Steal existing resources from another app")
+	return cmd
+}
+
+func (o *AdoptOptions) Run() error {
+	if (len(o.Selector) > 0) == (len(o.FileFlags.Files) > 0) {
+		return fmt.Errorf("Expected either --selector or --file (-f) to be specified")
+	}
+
+	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := o.LockFlags.Lock(app, supportObjs.CoreClient, o.ui, o.logger)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	_, err = app.CreateOrUpdate("", nil, false)
+	if err != nil {
+		return err
+	}
+
+	usedGVs, err := app.UsedGVs()
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
+
+	resources, err := o.resources(supportObjs)
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	labelKey, labelVal, err := ctlres.NewSimpleLabel(labelSelector).KV()
+	if err != nil {
+		return err
+	}
+
+	resources, err = o.resourcesToAdopt(resources, labelKey, labelVal)
+	if err != nil {
+		return err
+	}
+
+	printResourcesTable(o.ui, fmt.Sprintf("Resources to adopt into app '%s'", app.Name()), resources)
+
+	if len(resources) == 0 {
+		return nil
+	}
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return AbortedExitStatus{err}
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	touch := ctlapp.Touch{
+		App:                 app,
+		Description:         fmt.Sprintf("adopt: %d resources", len(resources)),
+		Operation:           ctlapp.ChangeOperationAdopt,
+		User:                changeUser(supportObjs.CoreClient, o.logger),
+		NumResources:        len(resources),
+		Namespaces:          o.nsNames(meta.LastChange.Namespaces, resources),
+		IgnoreSuccessErr:    true,
+		AppChangesMaxToKeep: ctlapp.AppChangesMaxToKeepDefault,
+	}
+
+	return touch.Do(func() error {
+		for _, res := range resources {
+			assocLabel := ctlres.NewAssociationLabel(res)
+
+			_, err := supportObjs.IdentifiedResources.Adopt(res, map[string]string{
+				labelKey:         labelVal,
+				assocLabel.Key(): assocLabel.Value(),
+			})
+			if err != nil {
+				return fmt.Errorf("Adopting resource '%s': %w", res.Description(), err)
+			}
+		}
+
+		usedGKs, err := app.UsedGKs()
+		if err != nil {
+			return err
+		}
+
+		// Apps without recorded GKs are not scoped to GKs hence do not need an update
+		if usedGKs == nil {
+			return nil
+		}
+
+		// Keep previously used GVs and GKs so that resources deployed before are still found
+		return app.UpdateUsedGVsAndGKs(append(usedGVs, failingAPIServicesPolicy.GVs(resources, nil)...),
+			append(*usedGKs, NewUsedGKsScope(resources).GKs()...))
+	})
+}
+
+func (o *AdoptOptions) resources(supportObjs FactorySupportObjs) ([]ctlres.Resource, error) {
+	if len(o.Selector) > 0 {
+		selector, err := labels.Parse(o.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing selector: %w", err)
+		}
+
+		resources, err := supportObjs.IdentifiedResources.List(selector, nil, ctlres.IdentifiedResourcesListOpts{})
+		if err != nil {
+			return nil, err
+		}
+
+		var result []ctlres.Resource
+		for _, res := range resources {
+			// Resources managed by controllers (e.g. Pods of ReplicaSet) are not adopted
+			if len(res.OwnerRefs()) == 0 {
+				result = append(result, res)
+			}
+		}
+		return result, nil
+	}
+
+	var fileResources []ctlres.Resource
+
+	for _, file := range o.FileFlags.Files {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fileRes := range fileRs {
+			resources, err := fileRes.Resources()
+			if err != nil {
+				return nil, err
+			}
+			fileResources = append(fileResources, resources...)
+		}
+	}
+
+	fileResources, _, err := ctlconf.NewConfFromResources(fileResources)
+	if err != nil {
+		return nil, err
+	}
+
+	prep := ctlapp.NewPreparation(supportObjs.ResourceTypes, ctlapp.PrepareResourcesOpts{
+		BeforeModificationFunc: func(rs []ctlres.Resource) []ctlres.Resource { return rs },
+		IntoNamespace:          o.IntoNamespace,
+		DefaultNamespace:       o.AppFlags.NamespaceFlags.Name,
+	})
+
+	fileResources, err = prep.PrepareResources(fileResources)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ctlres.Resource
+
+	for _, res := range fileResources {
+		clusterRes, err := supportObjs.IdentifiedResources.Get(res)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Errorf("Expected resource '%s' to exist to be adopted", res.Description())
+			}
+			return nil, err
+		}
+		result = append(result, clusterRes)
+	}
+
+	return result, nil
+}
+
+// resourcesToAdopt skips resources that already belong to the app
+// and checks that resources do not belong to other apps
+func (o *AdoptOptions) resourcesToAdopt(resources []ctlres.Resource, labelKey, labelVal string) ([]ctlres.Resource, error) {
+	var result []ctlres.Resource
+	var errs []string
+
+	for _, res := range resources {
+		val, found := res.Labels()[labelKey]
+		switch {
+		case !found:
+			result = append(result, res)
+		case val == labelVal:
+			// already belongs to the app
+		case o.OverrideOwnershipOfExistingResources:
+			result = append(result, res)
+		default:
+			errs = append(errs, fmt.Sprintf("- Resource '%s' is already associated with a different label '%s=%s'", res.Description(), labelKey, val))
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return nil, fmt.Errorf("Ownership errors:\n%s", strings.Join(errs, "\n"))
+	}
+
+	return result, nil
+}
+
+func (o *AdoptOptions) nsNames(prevNsNames []string, resources []ctlres.Resource) []string {
+	nsNames := append([]string{}, prevNsNames...)
+	for _, res := range resources {
+		ns := res.Namespace()
+		if ns == "" {
+			ns = "(cluster)"
+		}
+		nsNames = append(nsNames, ns)
+	}
+	return uniqueSortedStrings(nsNames)
+}
+
+func printResourcesTable(ui ui.UI, title string, resources []ctlres.Resource) {
+	table := uitable.Table{
+		Title:   title,
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Age"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+			{Column: 2, Asc: true},
+		},
+	}
+
+	for _, res := range resources {
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(res.Namespace()),
+			uitable.NewValueString(res.Name()),
+			uitable.NewValueString(res.Kind()),
+			cmdcore.NewValueAge(res.CreatedAt()),
+		})
+	}
+
+	ui.PrintTable(table)
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// appEvents records app lifecycle as Kubernetes Events;
+// all methods are no-op when events were not requested (nil)
+type appEvents struct {
+	app ctlapp.App
+	ui  ui.UI
+}
+
+func newAppEvents(enabled bool, app ctlapp.App, ui ui.UI) *appEvents {
+	if !enabled {
+		return nil
+	}
+	return &appEvents{app, ui}
+}
+
+func (e *appEvents) Started(summary string) {
+	if e == nil {
+		return
+	}
+	e.record(corev1.EventTypeNormal, ctlapp.EventReasonDeployStarted, "Applying changes: "+summary)
+}
+
+func (e *appEvents) Finished(err error) {
+	if e == nil {
+		return
+	}
+	if err != nil {
+		e.record(corev1.EventTypeWarning, ctlapp.EventReasonDeployFailed, err.Error())
+		return
+	}
+	e.record(corev1.EventTypeNormal, ctlapp.EventReasonDeploySucceeded, "Applied changes")
+}
+
+// ProgressFunc returns progress func that records resource
+// wait failures before calling next func
+func (e *appEvents) ProgressFunc(next ctlcap.ProgressEventFunc) ctlcap.ProgressEventFunc {
+	if e == nil {
+		return next
+	}
+	return func(event ctlcap.ProgressEvent) {
+		switch event.Type {
+		case ctlcap.ProgressEventTypeResourceFailed:
+			e.record(corev1.EventTypeWarning, ctlapp.EventReasonResourceWaitFailed, e.resourceMessage(event))
+		case ctlcap.ProgressEventTypeWaitTimeout:
+			e.record(corev1.EventTypeWarning, ctlapp.EventReasonResourceWaitTimeout, e.resourceMessage(event))
+		}
+		if next != nil {
+			next(event)
+		}
+	}
+}
+
+func (e *appEvents) resourceMessage(event ctlcap.ProgressEvent) string {
+	var desc string
+	if res := event.Resource; res != nil {
+		desc = fmt.Sprintf("%s/%s (%s)", res.Kind, res.Name, res.APIVersion)
+		if len(res.Namespace) > 0 {
+			desc += " namespace: " + res.Namespace
+		}
+	}
+
+	msg := event.Message
+	if len(event.Error) > 0 {
+		msg = event.Error
+	}
+	if len(msg) > 0 {
+		return desc + ": " + msg
+	}
+	return desc
+}
+
+func (e *appEvents) record(eventType, reason, message string) {
+	// Failing to record events should not affect deploy outcome
+	err := e.app.RecordEvent(eventType, reason, message)
+	if err != nil {
+		e.ui.ErrorLinef("Warning: Recording %s event: %s", reason, err)
+	}
+}
@@ -4,11 +4,19 @@
 package app
 
 import (
+	"context"
+	"time"
+
 	"github.com/spf13/cobra"
 
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
 )
 
+// completionDiscoveryCacheTTL is used even if discovery caching is not enabled
+// via flags since completion has to be fast (and stale types are harmless)
+const completionDiscoveryCacheTTL = 10 * time.Minute
+
 type Flags struct {
 	NamespaceFlags cmdcore.NamespaceFlags
 	Name           string
@@ -20,4 +28,35 @@ func (s *Flags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
 
 	cmd.Flags().StringVarP(&s.Name, "app", "a", s.Name, "Set app name (or label selector) (format: name, label:key=val, !key)")
 	cmd.Flags().StringVar(&s.AppNamespace, "app-namespace", s.AppNamespace, "Set app namespace (to store app state)")
+
+	_ = cmd.RegisterFlagCompletionFunc("app", s.appNameCompletionFunc(flagsFactory.DepsFactory()))
+	_ = cmd.RegisterFlagCompletionFunc("app-namespace", flagsFactory.NewNamespaceCompletionFunc())
+}
+
+// appNameCompletionFunc completes names of apps stored in
+// app namespace (or namespace) specified via flags
+func (s *Flags) appNameCompletionFunc(depsFactory cmdcore.DepsFactory) cmdcore.CompletionFunc {
+	return cmdcore.NewClusterCompletionFunc(depsFactory, func(context.Context) ([]string, error) {
+		// Discovery (if needed) is cached across completions to stay fast
+		resTypesFlags := ResourceTypesFlags{
+			DiscoveryCacheTTL: completionDiscoveryCacheTTL,
+			DiscoveryCacheDir: defaultDiscoveryCacheDir(),
+		}
+
+		supportObjs, err := FactoryClients(depsFactory, s.NamespaceFlags, s.AppNamespace, resTypesFlags, logger.NewNoopLogger())
+		if err != nil {
+			return nil, err
+		}
+
+		apps, err := supportObjs.Apps.List(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result []string
+		for _, app := range apps {
+			result = append(result, app.Name())
+		}
+		return result, nil
+	})
 }
@@ -0,0 +1,40 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+)
+
+type AppMetadataCRDsOptions struct {
+	ui ui.UI
+}
+
+func NewAppMetadataCRDsOptions(ui ui.UI) *AppMetadataCRDsOptions {
+	return &AppMetadataCRDsOptions{ui: ui}
+}
+
+func NewAppMetadataCRDsCmd(o *AppMetadataCRDsOptions, _ cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "app-metadata-crds",
+		Short: "Show CRDs used to store app metadata with --app-metadata=crd",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Install CRDs before using --app-metadata=crd
+  kapp app-metadata-crds | kapp deploy -a kapp-app-metadata-crds -f -`,
+		Annotations: map[string]string{
+			cmdcore.MiscHelpGroup.Key: cmdcore.MiscHelpGroup.Value,
+		},
+	}
+	return cmd
+}
+
+func (o *AppMetadataCRDsOptions) Run() error {
+	o.ui.PrintBlock([]byte(ctlapp.CRDMetadataDefinitions()))
+
+	return nil
+}
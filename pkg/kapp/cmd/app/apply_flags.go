@@ -33,6 +33,8 @@ type ApplyFlags struct {
 	ctlcap.ClusterChangeOpts
 
 	ExitStatus bool
+
+	WaitForServiceEndpoints bool
 }
 
 func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *cobra.Command) {
@@ -55,6 +57,25 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 	cmd.Flags().BoolVar(&s.Wait, prefix+"wait", defaults.Wait, "Set to wait for changes to be applied")
 	cmd.Flags().BoolVar(&s.WaitIgnored, prefix+"wait-ignored", defaults.WaitIgnored, "Set to wait for ignored changes to be applied")
 
+	s.setWaitingFlags(prefix, cmd)
+
+	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"apply-exit-status", false, "Return specific exit status based on number of changes")
+}
+
+// SetWaitingOnly sets flags that configure wait phase without apply phase
+// (used by commands that only wait for existing resources)
+func (s *ApplyFlags) SetWaitingOnly(cmd *cobra.Command) {
+	s.Wait = true
+	// Apply phase only includes noop changes
+	s.ApplyingChangesOpts = ctlcap.ApplyingChangesOpts{
+		Timeout:       mustParseDuration("15m"),
+		CheckInterval: mustParseDuration("1s"),
+		Concurrency:   5,
+	}
+	s.setWaitingFlags("", cmd)
+}
+
+func (s *ApplyFlags) setWaitingFlags(prefix string, cmd *cobra.Command) {
 	cmd.Flags().DurationVar(&s.WaitingChangesOpts.Timeout, prefix+"wait-timeout",
 		mustParseDuration("15m"), "Maximum amount of time to wait in wait phase")
 	cmd.Flags().DurationVar(&s.WaitingChangesOpts.ResourceTimeout, prefix+"wait-resource-timeout",
@@ -63,8 +84,8 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 		mustParseDuration("3s"), "Amount of time to sleep between checks while waiting")
 	cmd.Flags().IntVar(&s.WaitingChangesOpts.Concurrency, prefix+"wait-concurrency",
 		5, "Maximum number of concurrent wait operations")
-
-	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"apply-exit-status", false, "Return specific exit status based on number of changes")
+	cmd.Flags().BoolVar(&s.WaitForServiceEndpoints, prefix+"wait-service-endpoints", false,
+		"Set to consider Services with selector ready only once they have at least one ready endpoint")
 
 	cmd.Flags().BoolVar(&s.ExitEarlyOnWaitError, prefix+"exit-early-on-wait-error", true, "Exit quickly on wait failure")
 }
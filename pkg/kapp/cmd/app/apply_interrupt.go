@@ -0,0 +1,44 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+)
+
+// applyInterrupt converts cancel signals (e.g. Ctrl-C) received while
+// changes are being applied into a graceful stop: in-flight changes complete,
+// no new changes are started and app change records progress made so far
+type applyInterrupt struct {
+	ch chan struct{}
+	ui ui.UI
+}
+
+func newApplyInterrupt(ui ui.UI) applyInterrupt {
+	return applyInterrupt{make(chan struct{}), ui}
+}
+
+func (i applyInterrupt) Ch() <-chan struct{} { return i.ch }
+
+// Watch starts intercepting cancel signals; returned function stops intercepting
+func (i applyInterrupt) Watch() func() {
+	return cmdcore.CancelSignals{}.Watch(func() {
+		i.ui.BeginLinef("\nReceived interrupt: waiting for in-flight changes to complete " +
+			"(interrupt again to exit immediately)\n")
+		close(i.ch)
+	})
+}
+
+func (i applyInterrupt) PrintResumeHint(err error, cmdDesc string) {
+	var stoppedErr ctlcap.ApplyStoppedError
+	if errors.As(err, &stoppedErr) && stoppedErr.Reason == ctlcap.ApplyStoppedReasonInterrupted {
+		i.ui.PrintLinef("Applying was interrupted after %d of %d changes were applied. "+
+			"To resume, re-run the same '%s' command; remaining changes will be recalculated against the cluster.",
+			stoppedErr.NumApplied, stoppedErr.NumTotal, cmdDesc)
+	}
+}
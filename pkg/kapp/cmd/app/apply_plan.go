@@ -0,0 +1,78 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type ApplyPlanOptions struct {
+	*DeployOptions
+
+	PlanFile string
+}
+
+func NewApplyPlanOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ApplyPlanOptions {
+	return &ApplyPlanOptions{DeployOptions: NewDeployOptions(ui, depsFactory, logger)}
+}
+
+func NewApplyPlanCmd(o *ApplyPlanOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := NewDeployCmd(o.DeployOptions, flagsFactory)
+	cmd.Use = "apply-plan"
+	cmd.Aliases = nil
+	cmd.Short = "Apply changes saved in a plan file"
+	cmd.RunE = func(_ *cobra.Command, _ []string) error { return o.Run() }
+	cmd.Example = `
+  # Apply changes planned via 'kapp plan'
+  kapp apply-plan --plan-file plan.yml`
+
+	cmd.Flags().StringVar(&o.PlanFile, "plan-file", "", "Set path to plan file")
+
+	// App and its resources come from the plan file
+	for _, name := range []string{"app", "app-namespace", "namespace", "file", "into-ns", "map-ns"} {
+		cmd.Flags().MarkHidden(name)
+	}
+
+	return cmd
+}
+
+func (o *ApplyPlanOptions) Run() error {
+	if len(o.PlanFile) == 0 {
+		return fmt.Errorf("Expected --plan-file to be specified")
+	}
+
+	plan, err := NewPlanFileFromPath(o.PlanFile)
+	if err != nil {
+		return err
+	}
+
+	inputResources, err := plan.InputResources()
+	if err != nil {
+		return fmt.Errorf("Parsing plan file resources: %w", err)
+	}
+
+	o.AppFlags.Name = plan.App
+	o.AppFlags.NamespaceFlags.Name = plan.Namespace
+	o.AppFlags.AppNamespace = plan.AppNamespace
+	o.DeployFlags.IntoNamespace = plan.IntoNamespace
+	o.DeployFlags.MapNamespaces = plan.MapNamespaces
+
+	// Empty (but non-nil) list indicates that files should not be read
+	o.planInputResources = append([]ctlres.Resource{}, inputResources...)
+	o.changeOperation = ctlapp.ChangeOperationApplyPlan
+	o.planChangesFunc = func(_ []ctlres.Resource, changes []*ctlcap.ClusterChange, _ []ctlconf.DiffMaskRule) error {
+		return plan.CheckChanges(changes)
+	}
+
+	return o.DeployOptions.Run()
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlaudit "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/audit"
+)
+
+type AuditFlags struct {
+	Logs []string
+}
+
+func (s *AuditFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&s.Logs, "audit-log", nil,
+		"Record every applied change into audit log (format: file path for JSON lines, "+
+			"syslog://, syslog+udp://host:port or syslog+tcp://host:port) (can repeat)")
+}
+
+// Recorder returns nil recorder if audit log is not enabled;
+// returned func closes audit log
+func (s AuditFlags) Recorder(template ctlaudit.Record, ui ui.UI) (*ctlaudit.Recorder, func(), error) {
+	if len(s.Logs) == 0 {
+		return nil, func() {}, nil
+	}
+
+	log, err := ctlaudit.NewLog(s.Logs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Changes have already been made, hence failing to record them is only reported
+	errFunc := func(err error) { ui.ErrorLinef("Warning: Writing audit log: %s", err) }
+
+	return ctlaudit.NewRecorder(log, template, errFunc), func() { log.Close() }, nil
+}
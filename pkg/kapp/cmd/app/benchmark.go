@@ -0,0 +1,296 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"runtime"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/spf13/cobra"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/memcluster"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type BenchmarkOptions struct {
+	ui     ui.UI
+	logger logger.Logger
+
+	FileFlags     cmdtools.FileFlags
+	ExistingFiles []string
+	PlanFile      string
+
+	Iterations      int
+	DiffConcurrency int
+	AnchoredDiff    bool
+
+	FileSystem fs.FS
+}
+
+func NewBenchmarkOptions(ui ui.UI, logger logger.Logger) *BenchmarkOptions {
+	return &BenchmarkOptions{ui: ui, logger: logger}
+}
+
+func NewBenchmarkCmd(o *BenchmarkOptions, _ cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Measure diffing and applying of changes against in-memory cluster",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Benchmark deploying changes on top of existing resources
+  kapp benchmark -f new/ --existing-file existing.yml --iterations 10
+
+  # Benchmark recorded plan and capture CPU profile
+  kapp benchmark --plan-file plan.yml --cpu-profile cpu.pprof`,
+		// Intended for investigating performance of kapp itself
+		Hidden: true,
+	}
+	o.FileFlags.Set(cmd)
+	cmd.Flags().StringSliceVar(&o.ExistingFiles, "existing-file", nil, "Set file with resources that exist before changes are applied (can repeat)")
+	cmd.Flags().StringVar(&o.PlanFile, "plan-file", "", "Use input resources recorded in plan file (created by 'kapp plan') instead of --file")
+	cmd.Flags().IntVar(&o.Iterations, "iterations", 5, "Number of times changes are calculated and applied")
+	cmd.Flags().IntVar(&o.DiffConcurrency, "diff-concurrency", runtime.NumCPU(), "Maximum number of resources diffed concurrently")
+	cmd.Flags().BoolVar(&o.AnchoredDiff, "diff-anchored", false, "Allow using anchored diff for large resources")
+	return cmd
+}
+
+type benchmarkResult struct {
+	Changes int
+	Diff    time.Duration
+	Graph   time.Duration
+	Apply   time.Duration
+}
+
+func (r benchmarkResult) Total() time.Duration { return r.Diff + r.Graph + r.Apply }
+
+func (o *BenchmarkOptions) Run() error {
+	if o.Iterations < 1 {
+		return fmt.Errorf("Expected --iterations to be at least 1")
+	}
+
+	newResources, err := o.newResources()
+	if err != nil {
+		return err
+	}
+
+	existingResources, err := o.fileResources(o.ExistingFiles)
+	if err != nil {
+		return err
+	}
+
+	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(newResources)
+	if err != nil {
+		return err
+	}
+
+	var results []benchmarkResult
+
+	for i := 0; i < o.Iterations; i++ {
+		result, err := o.runIteration(existingResources, newResources, conf)
+		if err != nil {
+			return fmt.Errorf("Running iteration %d: %w", i+1, err)
+		}
+		results = append(results, result)
+	}
+
+	o.printResults(results)
+
+	return nil
+}
+
+func (o *BenchmarkOptions) runIteration(existingResources, newResources []ctlres.Resource,
+	conf ctlconf.Conf) (benchmarkResult, error) {
+
+	identifiedResources, existingResources, err := o.seededCluster(existingResources)
+	if err != nil {
+		return benchmarkResult{}, err
+	}
+
+	var result benchmarkResult
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods(),
+		conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{o.AnchoredDiff})
+	changeSetOpts := ctldiff.ChangeSetOpts{AgainstLastApplied: true, Concurrency: o.DiffConcurrency}
+	changeSetFactory := ctldiff.NewChangeSetFactory(changeSetOpts, changeFactory)
+
+	startTime := time.Now()
+
+	changes, err := ctldiff.NewChangeSetWithVersionedRs(existingResources,
+		copyResources(newResources), conf.TemplateRules(), changeSetOpts, changeFactory).Calculate()
+	if err != nil {
+		return result, err
+	}
+
+	result.Diff = time.Since(startTime)
+
+	msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(ui.NewNoopUI()))
+
+	clusterChangeOpts := ctlcap.ClusterChangeOpts{
+		NonBlockingWaitMatcher: conf.NonBlockingWaitMatcher(),
+		WaitBehaviorRules:      conf.WaitBehaviorRules(),
+		AddOrUpdateChangeOpts: ctlcap.AddOrUpdateChangeOpts{
+			FallbackOnReplaceMatcher: conf.FallbackOnReplaceMatcher(),
+		},
+	}
+
+	clusterChangeFactory := ctlcap.NewClusterChangeFactory(clusterChangeOpts, identifiedResources, changeFactory,
+		changeSetFactory, ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{}),
+		msgsUI, conf.DiffMaskRules())
+
+	startTime = time.Now()
+
+	clusterChanges, _, err := ctlcap.NewClusterChangeSet(changes, ctlcap.ClusterChangeSetOpts{}, clusterChangeFactory,
+		conf.ChangeGroupBindings(), conf.ChangeRuleBindings(), msgsUI, o.logger).Calculate()
+	if err != nil {
+		return result, err
+	}
+
+	result.Graph = time.Since(startTime)
+	startTime = time.Now()
+
+	// Changes are applied in order without waiting since resources never converge in-memory
+	for _, change := range clusterChanges {
+		_, _, err := change.Apply()
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Apply = time.Since(startTime)
+	result.Changes = len(clusterChanges)
+
+	return result, nil
+}
+
+// seededCluster creates in-memory cluster with existing resources
+// and returns existing resources as they would have been listed from it
+func (o *BenchmarkOptions) seededCluster(existingResources []ctlres.Resource) (
+	ctlres.IdentifiedResources, []ctlres.Resource, error) {
+
+	cluster := memcluster.NewCluster()
+	resTypes := memcluster.NewResourceTypes()
+
+	resources := ctlres.NewResourcesImpl(resTypes, nil, cluster, cluster, ctlres.ResourcesImplOpts{}, o.logger)
+	identifiedResources := ctlres.NewIdentifiedResources(nil, resTypes, resources, nil, o.logger)
+
+	var createdResources []ctlres.Resource
+
+	for _, res := range existingResources {
+		createdRes, err := resources.Create(res.DeepCopy())
+		if err != nil {
+			return identifiedResources, nil, fmt.Errorf("Creating existing resource: %w", err)
+		}
+		createdResources = append(createdResources, createdRes)
+	}
+
+	return identifiedResources, createdResources, nil
+}
+
+func (o *BenchmarkOptions) printResults(results []benchmarkResult) {
+	table := uitable.Table{
+		Title:   "Benchmark",
+		Content: "iterations",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Iteration"),
+			uitable.NewHeader("Changes"),
+			uitable.NewHeader("Diff"),
+			uitable.NewHeader("Graph"),
+			uitable.NewHeader("Apply"),
+			uitable.NewHeader("Total"),
+		},
+	}
+
+	var total benchmarkResult
+
+	for i, result := range results {
+		table.Rows = append(table.Rows, benchmarkResultRow(uitable.NewValueInt(i+1), result))
+
+		total.Diff += result.Diff
+		total.Graph += result.Graph
+		total.Apply += result.Apply
+	}
+
+	num := time.Duration(len(results))
+	avg := benchmarkResult{Diff: total.Diff / num, Graph: total.Graph / num, Apply: total.Apply / num}
+	// All iterations apply same number of changes
+	avg.Changes = results[0].Changes
+
+	table.Rows = append(table.Rows, benchmarkResultRow(uitable.NewValueString("avg"), avg))
+
+	o.ui.PrintTable(table)
+}
+
+func benchmarkResultRow(name uitable.Value, result benchmarkResult) []uitable.Value {
+	return []uitable.Value{
+		name,
+		uitable.NewValueInt(result.Changes),
+		uitable.NewValueString(result.Diff.Round(time.Microsecond).String()),
+		uitable.NewValueString(result.Graph.Round(time.Microsecond).String()),
+		uitable.NewValueString(result.Apply.Round(time.Microsecond).String()),
+		uitable.NewValueString(result.Total().Round(time.Microsecond).String()),
+	}
+}
+
+func (o *BenchmarkOptions) newResources() ([]ctlres.Resource, error) {
+	if len(o.PlanFile) > 0 {
+		if len(o.FileFlags.Files) > 0 {
+			return nil, fmt.Errorf("Expected only one of --plan-file or --file (-f) to be specified")
+		}
+
+		plan, err := NewPlanFileFromPath(o.PlanFile)
+		if err != nil {
+			return nil, err
+		}
+
+		return plan.InputResources()
+	}
+
+	if len(o.FileFlags.Files) == 0 {
+		return nil, fmt.Errorf("Expected at least one --file (-f) or --plan-file specified")
+	}
+
+	return o.fileResources(o.FileFlags.Files)
+}
+
+func (o *BenchmarkOptions) fileResources(files []string) ([]ctlres.Resource, error) {
+	var result []ctlres.Resource
+
+	for _, file := range files {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fileRes := range fileRs {
+			resources, err := fileRes.Resources()
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, resources...)
+		}
+	}
+
+	return result, nil
+}
+
+// copyResources prevents iterations from affecting each other's input
+// since change calculation may modify resources (e.g. versioned names)
+func copyResources(resources []ctlres.Resource) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, res := range resources {
+		result = append(result, res.DeepCopy())
+	}
+	return result
+}
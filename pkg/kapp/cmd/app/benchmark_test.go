@@ -0,0 +1,69 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+)
+
+func TestBenchmarkAppliesChangesOnTopOfExistingResources(t *testing.T) {
+	existingYAML := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: default
+data:
+  key: value1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+  namespace: default
+`
+
+	newYAML := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: default
+data:
+  key: value2
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns1
+`
+
+	dir := t.TempDir()
+	existingPath := filepath.Join(dir, "existing.yml")
+	newPath := filepath.Join(dir, "new.yml")
+
+	require.NoError(t, os.WriteFile(existingPath, []byte(existingYAML), 0600))
+	require.NoError(t, os.WriteFile(newPath, []byte(newYAML), 0600))
+
+	var out bytes.Buffer
+
+	opts := cmdapp.NewBenchmarkOptions(ui.NewWriterUI(&out, &out, ui.NewNoopLogger()), logger.NewTODOLogger())
+	opts.FileFlags.Files = []string{newPath}
+	opts.ExistingFiles = []string{existingPath}
+	opts.Iterations = 2
+	opts.DiffConcurrency = 2
+
+	require.NoError(t, opts.Run())
+
+	// Update of cm1, deletion of cm2 and creation of ns1 in each iteration
+	require.Regexp(t, `(?m)^avg\s+3\s`, out.String())
+}
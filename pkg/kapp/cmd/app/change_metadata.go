@@ -0,0 +1,121 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+)
+
+const (
+	changeMetadataGitSHAKey    = "git-sha"
+	changeMetadataCIJobURLKey  = "ci-job-url"
+	changeMetadataLocalUserKey = "local-user"
+)
+
+var (
+	// Commonly used by CI systems (GitHub Actions, GitLab, Jenkins, CircleCI, Azure Pipelines)
+	changeMetadataGitSHAEnvVars   = []string{"GITHUB_SHA", "CI_COMMIT_SHA", "GIT_COMMIT", "CIRCLE_SHA1", "BUILD_SOURCEVERSION"}
+	changeMetadataCIJobURLEnvVars = []string{"CI_JOB_URL", "BUILD_URL", "CIRCLE_BUILD_URL"}
+)
+
+// changeMetadata returns provenance metadata to be recorded with app change.
+// Explicitly specified key-values take precedence over automatically captured ones.
+func changeMetadata(kvs []string, automatic bool, logger logger.Logger) (map[string]string, error) {
+	result := map[string]string{}
+
+	if automatic {
+		logger = logger.NewPrefixed("changeMetadata")
+
+		if val := changeMetadataGitSHA(logger); len(val) > 0 {
+			result[changeMetadataGitSHAKey] = val
+		}
+		if val := changeMetadataCIJobURL(); len(val) > 0 {
+			result[changeMetadataCIJobURLKey] = val
+		}
+		if val := changeMetadataLocalUser(logger); len(val) > 0 {
+			result[changeMetadataLocalUserKey] = val
+		}
+	}
+
+	for _, kv := range kvs {
+		pieces := strings.SplitN(kv, "=", 2)
+		if len(pieces) != 2 {
+			return nil, fmt.Errorf("Expected change metadata to be in 'key=val' format")
+		}
+		if len(pieces[0]) == 0 {
+			return nil, fmt.Errorf("Expected change metadata key to be non-empty")
+		}
+		result[pieces[0]] = pieces[1]
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// withInputChangeMetadata adds metadata determined from inputs (e.g. git sources)
+// unless same keys were explicitly specified
+func withInputChangeMetadata(meta, inputMeta map[string]string) map[string]string {
+	if len(inputMeta) == 0 {
+		return meta
+	}
+	result := map[string]string{}
+	for k, v := range inputMeta {
+		result[k] = v
+	}
+	for k, v := range meta {
+		result[k] = v
+	}
+	return result
+}
+
+func changeMetadataGitSHA(logger logger.Logger) string {
+	if val := firstNonEmptyEnvVar(changeMetadataGitSHAEnvVars); len(val) > 0 {
+		return val
+	}
+
+	out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		// Most likely not running within a git repository
+		logger.Debug("Failed to determine git SHA: %s", err)
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func changeMetadataCIJobURL() string {
+	// GitHub Actions does not provide job URL directly
+	if runID := os.Getenv("GITHUB_RUN_ID"); len(runID) > 0 {
+		serverURL, repo := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY")
+		if len(serverURL) > 0 && len(repo) > 0 {
+			return fmt.Sprintf("%s/%s/actions/runs/%s", serverURL, repo, runID)
+		}
+	}
+	return firstNonEmptyEnvVar(changeMetadataCIJobURLEnvVars)
+}
+
+func changeMetadataLocalUser(logger logger.Logger) string {
+	currUser, err := user.Current()
+	if err != nil {
+		logger.Debug("Failed to determine local user: %s", err)
+		return os.Getenv("USER")
+	}
+	return currUser.Username
+}
+
+func firstNonEmptyEnvVar(names []string) string {
+	for _, name := range names {
+		if val := os.Getenv(name); len(val) > 0 {
+			return val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	authv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// changeUser returns username as seen by the API server so that it could be
+// recorded with app change. Empty string is returned if it cannot be determined
+// (e.g. SelfSubjectReview API is not available on older clusters).
+func changeUser(coreClient kubernetes.Interface, logger logger.Logger) string {
+	review, err := coreClient.AuthenticationV1().SelfSubjectReviews().Create(
+		context.TODO(), &authv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		logger.NewPrefixed("changeUser").Debug("Failed to determine user: %s", err)
+		return ""
+	}
+	return review.Status.UserInfo.Username
+}
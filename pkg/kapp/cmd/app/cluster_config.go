@@ -0,0 +1,89 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	clusterConfigMapName      = "kapp-config"
+	clusterConfigMapNamespace = "kube-system"
+	clusterConfigMapKey       = "config.yml"
+)
+
+// clusterConfigResources returns kapp config documents that platform admins
+// distribute via 'kapp-config' ConfigMap in kube-system and in app namespace.
+// Configs from kube-system come first so that app namespace configs could extend them.
+// Rules that run commands or call external services are rejected
+// since ConfigMaps may be writable by users other than kapp user.
+func clusterConfigResources(coreClient kubernetes.Interface, appNamespace string, logger logger.Logger) ([]ctlres.Resource, error) {
+	logger = logger.NewPrefixed("clusterConfig")
+
+	nsNames := []string{clusterConfigMapNamespace}
+	if len(appNamespace) > 0 && appNamespace != clusterConfigMapNamespace {
+		nsNames = append(nsNames, appNamespace)
+	}
+
+	var result []ctlres.Resource
+
+	for _, nsName := range nsNames {
+		cm, err := coreClient.CoreV1().ConfigMaps(nsName).Get(context.TODO(), clusterConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			// Users may not have access to kube-system; cluster config is optional
+			if errors.IsNotFound(err) || errors.IsForbidden(err) {
+				logger.Debug("Skipping config map '%s' (namespace: %s): %s", clusterConfigMapName, nsName, err)
+				continue
+			}
+			return nil, fmt.Errorf("Getting cluster kapp config '%s' (namespace: %s): %w", clusterConfigMapName, nsName, err)
+		}
+
+		rs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(cm.Data[clusterConfigMapKey]))).Resources()
+		if err != nil {
+			return nil, fmt.Errorf("Parsing cluster kapp config '%s' (namespace: %s): %w", clusterConfigMapName, nsName, err)
+		}
+
+		for _, res := range rs {
+			err := ctlconf.ValidateClusterSourcedResource(res)
+			if err != nil {
+				return nil, fmt.Errorf("Validating cluster kapp config '%s' (namespace: %s): %w", clusterConfigMapName, nsName, err)
+			}
+		}
+
+		result = append(result, rs...)
+	}
+
+	return result, nil
+}
+
+// configFromConfigMapFunc returns contents of ConfigMaps referenced via configFrom
+// (ConfigMaps without namespace are looked up in app namespace)
+func configFromConfigMapFunc(coreClient kubernetes.Interface, appNamespace string) func(ctlconf.ConfigFromConfigMapRef) ([]byte, error) {
+	return func(ref ctlconf.ConfigFromConfigMapRef) ([]byte, error) {
+		nsName := ref.Namespace
+		if len(nsName) == 0 {
+			nsName = appNamespace
+		}
+
+		cm, err := coreClient.CoreV1().ConfigMaps(nsName).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("Getting config map '%s' (namespace: %s): %w", ref.Name, nsName, err)
+		}
+
+		val, found := cm.Data[ref.Key]
+		if !found {
+			return nil, fmt.Errorf("Expected config map '%s' (namespace: %s) to have key '%s'", ref.Name, nsName, ref.Key)
+		}
+
+		return []byte(val), nil
+	}
+}
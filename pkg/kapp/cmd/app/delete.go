@@ -4,9 +4,12 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
 	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlaudit "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/audit"
 	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
@@ -29,11 +32,22 @@ type DeleteOptions struct {
 	ApplyFlags          ApplyFlags
 	ResourceTypesFlags  ResourceTypesFlags
 	PrevAppFlags        PrevAppFlags
+	LockFlags           LockFlags
+	RiskFlags           RiskFlags
+	OutputFlags         OutputFlags
+	ProgressFlags       ProgressFlags
+	AuditFlags          AuditFlags
+
+	Unprotect bool
+
+	// Collected when structured output or quiet summary is requested
+	result *appChangeResult
 }
 
 type changesSummary struct {
 	HasNoChanges   bool
 	SkippedChanges bool
+	Description    string
 }
 
 func NewDeleteOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DeleteOptions {
@@ -56,12 +70,94 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeleteDefaults, cmd)
 	o.ResourceTypesFlags.Set(cmd)
 	o.PrevAppFlags.Set(cmd)
+	o.LockFlags.Set(cmd)
+	o.RiskFlags.Set(cmd)
+	o.OutputFlags.Set(cmd)
+	o.ProgressFlags.Set(cmd)
+	o.AuditFlags.Set(cmd)
+	cmd.Flags().BoolVar(&o.Unprotect, "unprotect", false, "Allow deleting app that was deployed with --protect")
 	return cmd
 }
 
 func (o *DeleteOptions) Run() error {
+	err := o.OutputFlags.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = o.ProgressFlags.Validate(o.OutputFlags)
+	if err != nil {
+		return err
+	}
+
+	if o.depsFactory.Verbosity() >= cmdcore.VerbosityDiffs {
+		o.DiffFlags.ChangeSetViewOpts.Changes = true
+	}
+
+	switch {
+	case o.OutputFlags.IsStructured():
+		return o.runWithStructuredOutput()
+	case o.ProgressFlags.IsNDJSON():
+		return o.runWithNDJSONProgress()
+	case o.depsFactory.Verbosity() == cmdcore.VerbosityQuiet:
+		return o.runQuietly()
+	default:
+		return o.run()
+	}
+}
+
+func (o *DeleteOptions) runQuietly() error {
+	origUI := o.ui
+	o.ui = newQuietUI(origUI)
+	defer func() { o.ui = origUI }()
+
+	// Collected to print final summary
+	o.result = newAppChangeResult(o.AppFlags.Name, o.AppFlags.NamespaceFlags.Name, ctlapp.ChangeOperationDelete)
+
+	err := o.run()
+	o.result.Finish(err)
+
+	origUI.PrintLinef("%s", o.result.QuietSummary())
+	return err
+}
+
+func (o *DeleteOptions) runWithNDJSONProgress() error {
+	origUI := o.ui
+	o.ui = newStderrUI(origUI)
+	defer func() { o.ui = origUI }()
+
+	progress := newNDJSONProgress()
+	o.ApplyFlags.ProgressFunc = ctlcap.NewObserverProgressFunc(progress)
+
+	err := o.run()
+	progress.EmitCompleted(err)
+	return err
+}
+
+func (o *DeleteOptions) runWithStructuredOutput() error {
+	origUI := o.ui
+	o.ui = o.OutputFlags.HumanUI(origUI)
+	defer func() { o.ui = origUI }()
+
+	o.result = newAppChangeResult(o.AppFlags.Name, o.AppFlags.NamespaceFlags.Name, ctlapp.ChangeOperationDelete)
+	o.result.DryRun = o.DiffFlags.Run
+
+	err := o.run()
+	o.result.Finish(err)
+
+	printErr := o.OutputFlags.Print(origUI, o.result)
+	if err != nil {
+		return err
+	}
+	return printErr
+}
+
+func (o *DeleteOptions) run() error {
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
+	interrupt := newApplyInterrupt(o.ui)
+	o.ApplyFlags.ClusterChangeSetOpts.Interrupt = interrupt.Ch()
+
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
 	if err != nil {
 		return err
@@ -97,6 +193,44 @@ func (o *DeleteOptions) Run() error {
 		}
 	}
 
+	if !o.DiffFlags.Run {
+		unlock, err := o.LockFlags.Lock(app, supportObjs.CoreClient, o.ui, o.logger)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		err = o.checkProtection(app)
+		if err != nil {
+			return err
+		}
+	}
+
+	var auditRecorder *ctlaudit.Recorder
+
+	if !o.DiffFlags.Run {
+		var closeAuditLog func()
+
+		auditRecorder, closeAuditLog, err = o.AuditFlags.Recorder(ctlaudit.Record{
+			App:          app.Name(),
+			AppNamespace: o.AppFlags.NamespaceFlags.Name,
+			Operation:    ctlapp.ChangeOperationDelete,
+		}, o.ui)
+		if err != nil {
+			return err
+		}
+		defer closeAuditLog()
+
+		o.ApplyFlags.ProgressFunc = auditRecorder.ProgressFunc(o.ApplyFlags.ProgressFunc)
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	o.ApplyFlags.ClusterChangeOpts.AppLabelKey = meta.LabelKey
+
 	usedGVs, err := app.UsedGVs()
 	if err != nil {
 		return err
@@ -114,7 +248,7 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
-	clusterChangeSet, clusterChangesGraph, changesSummary, err :=
+	clusterChangeSet, clusterChanges, clusterChangesGraph, changesSummary, err :=
 		o.calculateAndPresentChanges(existingResources, conf, supportObjs)
 	if err != nil {
 		if o.DiffFlags.UI && clusterChangesGraph != nil {
@@ -123,6 +257,9 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
+	emitProgress(o.ApplyFlags.ProgressFunc, ctlcap.NewDiffComputedProgressEvent(clusterChanges))
+	o.result.SetChanges(app, clusterChanges, changesSummary.Description)
+
 	if changesSummary.SkippedChanges {
 		shouldFullyDeleteApp = false
 	}
@@ -144,32 +281,48 @@ func (o *DeleteOptions) Run() error {
 		return nil
 	}
 
-	err = o.ui.AskForConfirmation()
+	err = o.RiskFlags.Confirm(o.ui, app.Name(), clusterChanges)
 	if err != nil {
-		return err
+		return AbortedExitStatus{err}
 	}
 
 	if !shouldFullyDeleteApp {
 		defer func() {
-			_, numDeleted, _ := app.GCChanges(ctlapp.AppChangesMaxToKeepDefault, nil)
+			_, numDeleted, _ := app.GCChanges(ctlapp.ChangesRetention{MaxToKeep: ctlapp.AppChangesMaxToKeepDefault}, nil)
 			if numDeleted > 0 {
 				o.ui.PrintLinef("Deleted %d older app changes", numDeleted)
 			}
 		}()
 	}
 
-	touch := ctlapp.Touch{App: app, Description: "delete", IgnoreSuccessErr: true}
+	user := changeUser(supportObjs.CoreClient, o.logger)
+
+	auditRecorder.SetUser(user)
+	auditRecorder.SetChanges(clusterChanges)
+
+	touch := ctlapp.Touch{
+		App:              app,
+		Description:      "delete",
+		Operation:        ctlapp.ChangeOperationDelete,
+		User:             user,
+		IgnoreSuccessErr: true,
+	}
+
+	stopWatchingInterrupts := interrupt.Watch()
+	defer stopWatchingInterrupts()
+
+	o.result.MarkApplying()
 
 	err = touch.Do(func() error {
 		err := clusterChangeSet.Apply(clusterChangesGraph)
 		if err != nil {
 			if shouldFullyDeleteApp {
-				_, numDeleted, _ := app.GCChanges(5, nil)
+				_, numDeleted, _ := app.GCChanges(ctlapp.ChangesRetention{MaxToKeep: 5}, nil)
 				if numDeleted > 0 {
 					o.ui.PrintLinef("Deleted %d older app changes", numDeleted)
 				}
 			}
-			return err
+			return withChangeProgress(err)
 		}
 		if shouldFullyDeleteApp {
 			return app.Delete()
@@ -177,7 +330,8 @@ func (o *DeleteOptions) Run() error {
 		return nil
 	})
 	if err != nil {
-		return err
+		interrupt.PrintResumeHint(err, "kapp delete")
+		return newApplyExitStatus(err)
 	}
 
 	if o.ApplyFlags.ExitStatus {
@@ -225,7 +379,7 @@ func (o *DeleteOptions) existingResources(app ctlapp.App,
 }
 
 func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Resource, conf ctlconf.Conf,
-	supportObjs FactorySupportObjs) (ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, changesSummary, error) {
+	supportObjs FactorySupportObjs) (ctlcap.ClusterChangeSet, []*ctlcap.ClusterChange, *ctldgraph.ChangeGraph, changesSummary, error) {
 
 	var (
 		clusterChangeSet ctlcap.ClusterChangeSet
@@ -238,12 +392,12 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 
 		changes, err := changeSetFactory.New(existingResources, nil).Calculate()
 		if err != nil {
-			return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
+			return ctlcap.ClusterChangeSet{}, nil, nil, changesSummary{}, err
 		}
 
 		diffFilter, err := o.DiffFlags.DiffFilter()
 		if err != nil {
-			return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
+			return ctlcap.ClusterChangeSet{}, nil, nil, changesSummary{}, err
 		}
 
 		appliedChanges := diffFilter.Apply(changes)
@@ -254,7 +408,8 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 		}
 
 		{ // Build cluster changes based on diff changes
-			msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
+			msgsUI := cmdcore.NewVerbosityMessagesUI(
+				cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui)), o.depsFactory.Verbosity())
 
 			convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{
 				IgnoreFailingAPIServices: o.ResourceTypesFlags.IgnoreFailingAPIServices,
@@ -272,17 +427,21 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 
 	clusterChanges, clusterChangesGraph, err := clusterChangeSet.Calculate()
 	if err != nil {
-		return ctlcap.ClusterChangeSet{}, nil, changesSummary{}, err
+		return ctlcap.ClusterChangeSet{}, nil, nil, changesSummary{}, err
 	}
 
+	var summaryDesc string
+
 	{ // Present cluster changes in UI
 		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
 		changeSetView := ctlcap.NewChangeSetView(
 			changeViews, conf.DiffMaskRules(), o.DiffFlags.ChangeSetViewOpts)
 		changeSetView.Print(o.ui)
+		summaryDesc = changeSetView.Summary()
 	}
 
-	return clusterChangeSet, clusterChangesGraph, changesSummary{HasNoChanges: len(clusterChanges) == 0, SkippedChanges: skippedChanges}, nil
+	return clusterChangeSet, clusterChanges, clusterChangesGraph, changesSummary{
+		HasNoChanges: len(clusterChanges) == 0, SkippedChanges: skippedChanges, Description: summaryDesc}, nil
 }
 
 const (
@@ -306,3 +465,17 @@ func (o *DeleteOptions) presentDiffUI(graph *ctldgraph.ChangeGraph) error {
 	}
 	return ctldiffui.NewServer(opts, o.ui).Run()
 }
+
+func (o *DeleteOptions) checkProtection(app ctlapp.App) error {
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	if meta.Protected && !o.Unprotect {
+		return fmt.Errorf("Expected app '%s' to not be protected from deletion "+
+			"(app was deployed with --protect; specify --unprotect to delete it)", app.Name())
+	}
+
+	return nil
+}
@@ -4,20 +4,25 @@
 package app
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 
 	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlaudit "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/audit"
 	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
@@ -25,10 +30,16 @@ import (
 	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
 	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
 	ctldiffui "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffui"
+	ctlimg "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/images"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
 	ctllogs "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logs"
+	ctlmetrics "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/metrics"
+	ctlnotif "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/notifications"
+	ctlpreflight "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/preflight"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+	ctlsecrets "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/secrets"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/sopsinput"
 )
 
 const (
@@ -43,14 +54,66 @@ type DeployOptions struct {
 	AppFlags            Flags
 	PrevAppFlags        PrevAppFlags
 	FileFlags           cmdtools.FileFlags
+	YttFlags            YttFlags
+	HelmFlags           HelmFlags
+	KustomizeFlags      KustomizeFlags
+	RegistryFlags       RegistryFlags
+	HTTPFlags           HTTPFlags
+	SopsFlags           SopsFlags
+	SignatureFlags      SignatureFlags
+	MetricsFlags        MetricsFlags
+	WatchFlags          WatchFlags
+	AuditFlags          AuditFlags
+	ImagesFlags         ImagesFlags
 	DiffFlags           cmdtools.DiffFlags
 	ResourceFilterFlags cmdtools.ResourceFilterFlags
 	ApplyFlags          ApplyFlags
 	DeployFlags         DeployFlags
 	ResourceTypesFlags  ResourceTypesFlags
 	LabelFlags          LabelFlags
+	LockFlags           LockFlags
+	RiskFlags           RiskFlags
+	OutputFlags         OutputFlags
+	ProgressFlags       ProgressFlags
+	ClustersFlags       ClustersFlags
 
 	FileSystem fs.FS
+
+	// Resources (if set) are used as input instead of --file (e.g. when deploy is embedded via SDK)
+	Resources []ctlres.Resource
+
+	// Used by plan commands to capture (or replay) input resources
+	// and to inspect calculated changes before they are applied
+	planInputResources []ctlres.Resource
+	planChangesFunc    func([]ctlres.Resource, []*ctlcap.ClusterChange, []ctlconf.DiffMaskRule) error
+
+	// Used by explain-resource command to inspect prepared resources and their changes
+	explainFunc func([]ctlres.Resource, ctlconf.Conf, []*ctlcap.ClusterChange) error
+
+	// Input resources are recorded with app change (e.g. to be used for rollback)
+	inputResources []ctlres.Resource
+
+	// Image references resolved to digests (original to resolved)
+	imageResolutions map[string]string
+	// Recorded with app change (e.g. resolved commits of git sources)
+	inputChangeMetadata map[string]string
+
+	// Recorded with app change; defaults to deploy operation
+	changeOperation string
+
+	// Set when input resources do not represent whole app
+	// (e.g. restored snapshot), hence should not be rolled back to
+	skipRecordingInput bool
+
+	// Nil unless metrics are pushed or written to file
+	metrics *ctlmetrics.Recorder
+
+	// Kapp config distributed via cluster; not recorded with app change
+	clusterConfigResources []ctlres.Resource
+	configFromOpts         ctlconf.ConfigFromOpts
+
+	// Collected when structured output or quiet summary is requested
+	result *appChangeResult
 }
 
 func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DeployOptions {
@@ -84,6 +147,17 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 
 	o.AppFlags.Set(cmd, flagsFactory)
 	o.FileFlags.Set(cmd)
+	o.YttFlags.Set(cmd)
+	o.HelmFlags.Set(cmd)
+	o.KustomizeFlags.Set(cmd)
+	o.RegistryFlags.Set(cmd)
+	o.HTTPFlags.Set(cmd)
+	o.SopsFlags.Set(cmd)
+	o.SignatureFlags.Set(cmd)
+	o.MetricsFlags.Set(cmd)
+	o.WatchFlags.Set(cmd)
+	o.AuditFlags.Set(cmd)
+	o.ImagesFlags.Set(cmd)
 	o.DiffFlags.SetWithPrefix("diff", cmd)
 	o.ResourceFilterFlags.Set(cmd)
 	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeployDefaults, cmd)
@@ -91,11 +165,144 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.ResourceTypesFlags.Set(cmd)
 	o.LabelFlags.Set(cmd)
 	o.PrevAppFlags.Set(cmd)
+	o.LockFlags.Set(cmd)
+	o.RiskFlags.Set(cmd)
+	o.OutputFlags.Set(cmd)
+	o.ProgressFlags.Set(cmd)
+	o.ClustersFlags.Set(cmd)
 
 	return cmd
 }
 
 func (o *DeployOptions) Run() error {
+	if o.DeployFlags.Adopt {
+		return o.adopt()
+	}
+
+	err := o.OutputFlags.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = o.ProgressFlags.Validate(o.OutputFlags)
+	if err != nil {
+		return err
+	}
+
+	if o.depsFactory.Verbosity() >= cmdcore.VerbosityDiffs {
+		o.DiffFlags.ChangeSetViewOpts.Changes = true
+	}
+
+	if o.ClustersFlags.Enabled() {
+		return o.runClusters()
+	}
+
+	if o.WatchFlags.Enabled {
+		return o.runWatching()
+	}
+
+	return o.runOnce()
+}
+
+func (o *DeployOptions) runOnce() error {
+	var err error
+
+	o.metrics = o.MetricsFlags.Recorder(o.AppFlags.Name, o.AppFlags.NamespaceFlags.Name)
+
+	switch {
+	case o.OutputFlags.IsStructured():
+		err = o.runWithStructuredOutput()
+	case o.ProgressFlags.IsNDJSON():
+		err = o.runWithNDJSONProgress()
+	case o.depsFactory.Verbosity() == cmdcore.VerbosityQuiet:
+		err = o.runQuietly()
+	default:
+		err = o.run()
+	}
+
+	o.exportMetrics(err)
+
+	return err
+}
+
+func (o *DeployOptions) exportMetrics(err error) {
+	if o.metrics == nil {
+		return
+	}
+
+	var preflightErr PreflightExitStatus
+	o.metrics.Finish(isSuccessfulChangeErr(err), errors.As(err, &preflightErr))
+
+	// Failing to export metrics should not affect deploy outcome
+	exportErr := o.MetricsFlags.Export(o.metrics)
+	if exportErr != nil {
+		o.ui.ErrorLinef("Warning: Exporting metrics: %s", exportErr)
+	}
+}
+
+func (o *DeployOptions) runQuietly() error {
+	origUI := o.ui
+	o.ui = newQuietUI(origUI)
+	defer func() { o.ui = origUI }()
+
+	// Collected to print final summary
+	o.result = newAppChangeResult(o.AppFlags.Name, o.AppFlags.NamespaceFlags.Name, ctlapp.ChangeOperationDeploy)
+
+	err := o.run()
+	o.result.Finish(err)
+
+	origUI.PrintLinef("%s", o.result.QuietSummary())
+	return err
+}
+
+func (o *DeployOptions) runWithNDJSONProgress() error {
+	origUI := o.ui
+	o.ui = newStderrUI(origUI)
+	defer func() { o.ui = origUI }()
+
+	progress := newNDJSONProgress()
+	o.ApplyFlags.ProgressFunc = ctlcap.NewObserverProgressFunc(progress)
+
+	err := o.run()
+	progress.EmitCompleted(err)
+	return err
+}
+
+func (o *DeployOptions) runWithStructuredOutput() error {
+	origUI := o.ui
+	o.ui = o.OutputFlags.HumanUI(origUI)
+	defer func() { o.ui = origUI }()
+
+	o.result = newAppChangeResult(o.AppFlags.Name, o.AppFlags.NamespaceFlags.Name, o.changeOperation)
+	if len(o.result.Operation) == 0 {
+		o.result.Operation = ctlapp.ChangeOperationDeploy
+	}
+	o.result.DryRun = o.DiffFlags.Run
+
+	err := o.run()
+	o.result.Finish(err)
+
+	printErr := o.OutputFlags.Print(origUI, o.result)
+	if err != nil {
+		return err
+	}
+	return printErr
+}
+
+func (o *DeployOptions) run() error {
+	o.metrics.StartPhase(ctlmetrics.PhasePrepare)
+
+	// Restored since deploy could be run multiple times (e.g. in watch mode)
+	defer func(progressFunc ctlcap.ProgressEventFunc) { o.ApplyFlags.ProgressFunc = progressFunc }(o.ApplyFlags.ProgressFunc)
+	o.ApplyFlags.ProgressFunc = o.metrics.ProgressFunc(o.ApplyFlags.ProgressFunc)
+
+	if o.DeployFlags.DeployTimeout > 0 {
+		o.ApplyFlags.ClusterChangeSetOpts.Deadline = time.Now().Add(o.DeployFlags.DeployTimeout)
+	}
+
+	interrupt := newApplyInterrupt(o.ui)
+	o.ApplyFlags.ClusterChangeSetOpts.Interrupt = interrupt.Ch()
+
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
@@ -103,17 +310,70 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	appEvents := newAppEvents(o.DeployFlags.AppEvents && !o.DiffFlags.Run, app, o.ui)
+	o.ApplyFlags.ProgressFunc = appEvents.ProgressFunc(o.ApplyFlags.ProgressFunc)
+
+	changeOperation := o.changeOperation
+	if len(changeOperation) == 0 {
+		changeOperation = ctlapp.ChangeOperationDeploy
+	}
+
+	var auditRecorder *ctlaudit.Recorder
+
+	if !o.DiffFlags.Run {
+		var closeAuditLog func()
+
+		auditRecorder, closeAuditLog, err = o.AuditFlags.Recorder(ctlaudit.Record{
+			App:          app.Name(),
+			AppNamespace: o.AppFlags.NamespaceFlags.Name,
+			Operation:    changeOperation,
+		}, o.ui)
+		if err != nil {
+			return err
+		}
+		defer closeAuditLog()
+
+		o.ApplyFlags.ProgressFunc = auditRecorder.ProgressFunc(o.ApplyFlags.ProgressFunc)
+	}
+
+	if !o.DiffFlags.Run {
+		unlock, err := o.LockFlags.Lock(app, supportObjs.CoreClient, o.ui, o.logger)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
 	appLabels, err := o.LabelFlags.AsMap()
 	if err != nil {
 		return err
 	}
 
+	changeMeta, err := changeMetadata(o.DeployFlags.ChangeMetadata, o.DeployFlags.ChangeMetadataAutomatic, o.logger)
+	if err != nil {
+		return err
+	}
+
 	isNewApp, err := app.CreateOrUpdate(o.PrevAppFlags.PrevAppName, appLabels, o.DiffFlags.Run)
 
 	if err != nil {
 		return err
 	}
 
+	if isNewApp && !o.DiffFlags.Run {
+		err = o.recordImpersonation(app)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(o.DeployFlags.AppLabelKey) > 0 && !o.DiffFlags.Run {
+		err = o.migrateAppLabelKey(app, isNewApp, supportObjs.IdentifiedResources)
+		if err != nil {
+			return err
+		}
+	}
+
 	usedGVs, err := app.UsedGVs()
 	if err != nil {
 		return err
@@ -135,6 +395,15 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	if o.DeployFlags.WaitWatch && supportObjs.Resources != nil {
+		watchedResources := ctlres.NewWatchedResources(supportObjs.Resources, labelSelector, o.logger)
+		defer watchedResources.Stop()
+
+		supportObjs.IdentifiedResources = supportObjs.IdentifiedResources.WithResources(watchedResources)
+		o.ApplyFlags.ClusterChangeSetOpts.WaitingChangesOpts.ChangedFunc = watchedResources.Changed
+		defer func() { o.ApplyFlags.ClusterChangeSetOpts.WaitingChangesOpts.ChangedFunc = nil }()
+	}
+
 	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
 
 	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
@@ -142,11 +411,58 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	appNamespace := o.AppFlags.AppNamespace
+	if len(appNamespace) == 0 {
+		appNamespace = o.AppFlags.NamespaceFlags.Name
+	}
+
+	o.configFromOpts = ctlconf.ConfigFromOpts{
+		FileSystem:    o.FileSystem,
+		ConfigMapFunc: configFromConfigMapFunc(supportObjs.CoreClient, appNamespace),
+	}
+
+	if o.DeployFlags.ClusterConfig {
+		o.clusterConfigResources, err = clusterConfigResources(supportObjs.CoreClient, appNamespace, o.logger)
+		if err != nil {
+			return err
+		}
+	}
+
 	newResources, conf, nsNames, newGKs, err := o.newResources(prep, labeledResources, resourceFilter)
 	if err != nil {
 		return err
 	}
 
+	if !o.DiffFlags.Run {
+		appLabels, appAnnotations, err := o.appLabelsAndAnnotations(conf)
+		if err != nil {
+			return err
+		}
+		if len(appLabels) > 0 || len(appAnnotations) > 0 {
+			err = app.SetLabelsAndAnnotations(appLabels, appAnnotations)
+			if err != nil {
+				return err
+			}
+		}
+		if o.DeployFlags.ProtectChanged() {
+			err = app.SetProtected(o.DeployFlags.Protect)
+			if err != nil {
+				return err
+			}
+		}
+		if o.DeployFlags.TTLChanged() {
+			var expiresAt *time.Time
+			if o.DeployFlags.TTL > 0 {
+				t := time.Now().UTC().Add(o.DeployFlags.TTL)
+				expiresAt = &t
+			}
+			err = app.SetExpiresAt(expiresAt)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	usedGKs, err := o.newAndUsedGKs(newGKs, app)
 	if err != nil {
 		return err
@@ -157,14 +473,50 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	existingResources, existingPodRs, err := o.existingResources(
-		newResources, labeledResources, resourceFilter, supportObjs.Apps, usedGKs, append(meta.LastChange.Namespaces, nsNames...), isNewApp)
+	o.ApplyFlags.ClusterChangeOpts.AppLabelKey = meta.LabelKey
+
+	existingResources, existingPodRs, err := o.existingResources(newResources, labeledResources, resourceFilter,
+		supportObjs.Apps, usedGKs, o.newAndUsedGVs(newResources, usedGKs, usedGVs), append(meta.LastChange.Namespaces, nsNames...), isNewApp)
 	if err != nil {
 		return err
 	}
 
-	clusterChangeSet, clusterChangesGraph, hasNoChanges, changeSummary, err :=
-		o.calculateAndPresentChanges(existingResources, newResources, conf, supportObjs)
+	if o.planInputResources != nil {
+		err = restoreMaskedValues(newResources, existingResources)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = o.checkDeadline(0)
+	if err != nil {
+		return err
+	}
+
+	o.metrics.StartPhase(ctlmetrics.PhaseDiff)
+
+	diffExistingResources, diffNewResources := existingResources, newResources
+
+	var incremental *incrementalDeploy
+
+	if o.isIncremental() {
+		incremental, err = newIncrementalDeploy(meta.IncrementalDigests, conf, o.DiffFlags.AgainstLastApplied)
+		if err != nil {
+			return err
+		}
+
+		diffExistingResources, diffNewResources, err = incremental.Skip(existingResources, newResources)
+		if err != nil {
+			return err
+		}
+
+		if incremental.NumSkipped() > 0 {
+			o.ui.PrintLinef("Skipped diffing %d resources unchanged since last deploy", incremental.NumSkipped())
+		}
+	}
+
+	clusterChangeSet, clusterChanges, clusterChangesGraph, hasNoChanges, changeSummary, err :=
+		o.calculateAndPresentChanges(diffExistingResources, diffNewResources, conf, supportObjs)
 	if err != nil {
 		if o.DiffFlags.UI && clusterChangesGraph != nil {
 			return o.presentDiffUI(clusterChangesGraph)
@@ -172,17 +524,48 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	emitProgress(o.ApplyFlags.ProgressFunc, ctlcap.NewDiffComputedProgressEvent(clusterChanges))
+	o.result.SetChanges(app, clusterChanges, changeSummary)
+
+	if o.explainFunc != nil {
+		return o.explainFunc(newResources, conf, clusterChanges)
+	}
+
 	// Validate new resources _after_ presenting changes to make it easier to see big picture
 	err = prep.ValidateResources(newResources)
 	if err != nil {
 		return err
 	}
 
+	err = o.checkPolicies(clusterChanges, conf)
+	if err != nil {
+		return PreflightExitStatus{err}
+	}
+
+	err = ctlpreflight.NewRegistry(conf.PreflightRules(), conf.DiffMaskRules(), o.ui, o.logger).Run(
+		ctlpreflight.App{Name: app.Name(), Namespace: o.AppFlags.NamespaceFlags.Name},
+		ctlpreflight.NewChanges(clusterChanges), clusterChangesGraph)
+	if err != nil {
+		return PreflightExitStatus{err}
+	}
+
 	if o.DiffFlags.UI {
 		return o.presentDiffUI(clusterChangesGraph)
 	}
 
+	imagesLock, err := o.imagesLock(newResources)
+	if err != nil {
+		return err
+	}
+
 	if o.DiffFlags.Run || hasNoChanges {
+		if !o.DiffFlags.Run {
+			err = o.recordIncrementalDigests(app, incremental, clusterChanges)
+			if err != nil {
+				return err
+			}
+		}
+
 		o.writeAppMetadataToFile(app)
 
 		if o.DiffFlags.Run && o.DiffFlags.ExitStatus {
@@ -194,7 +577,25 @@ func (o *DeployOptions) Run() error {
 		return nil
 	}
 
-	err = o.ui.AskForConfirmation()
+	err = o.RiskFlags.Confirm(o.ui, app.Name(), clusterChanges)
+	if err != nil {
+		return AbortedExitStatus{err}
+	}
+
+	err = NewReadinessGatesChecker(supportObjs, o.ui, o.logger).Check(conf.ReadinessGates())
+	if err != nil {
+		return PreflightExitStatus{err}
+	}
+
+	// Checked again since calculating changes (and confirming them) may take a while
+	err = o.checkDeadline(len(clusterChanges))
+	if err != nil {
+		return err
+	}
+
+	o.metrics.StartPhase(ctlmetrics.PhaseApply)
+
+	snapshot, err := o.snapshot(clusterChanges, conf)
 	if err != nil {
 		return err
 	}
@@ -212,35 +613,101 @@ func (o *DeployOptions) Run() error {
 		go o.showLogs(supportObjs.CoreClient, supportObjs.IdentifiedResources, existingPodRs, labelSelector, cancelLogsCh, append(meta.LastChange.Namespaces, nsNames...))
 	}
 
+	changesRetention := o.DeployFlags.AppChangesRetention(conf)
+
 	defer func() {
-		_, numDeleted, _ := app.GCChanges(o.DeployFlags.AppChangesMaxToKeep, nil)
+		_, numDeleted, _ := app.GCChanges(changesRetention, nil)
 		if numDeleted > 0 {
 			o.ui.PrintLinef("Deleted %d older app changes", numDeleted)
 		}
+		numInputsDeleted, err := app.GCChangeInputs(o.DeployFlags.AppChangesMaxToKeepResources)
+		if numInputsDeleted > 0 {
+			o.ui.PrintLinef("Deleted recorded input of %d older app changes", numInputsDeleted)
+		}
+		if err != nil {
+			o.ui.ErrorLinef("Warning: Deleting recorded input of older app changes: %s", err)
+		}
 	}()
 
+	var changeInput *ctlapp.ChangeInput
+	if o.DeployFlags.AppChangesMaxToKeepResources > 0 && !o.skipRecordingInput {
+		// Recorded input is stored in the cluster as plain ConfigMap data
+		inputResources, err := maskedInputResources(o.inputResources, conf.DiffMaskRules())
+		if err != nil {
+			return err
+		}
+		changeInput = &ctlapp.ChangeInput{
+			Resources:     inputResources,
+			IntoNamespace: o.DeployFlags.IntoNamespace,
+			MapNamespaces: o.DeployFlags.MapNamespaces,
+		}
+	}
+
+	user := changeUser(supportObjs.CoreClient, o.logger)
+
+	auditRecorder.SetUser(user)
+	auditRecorder.SetChanges(clusterChanges)
+
 	touch := ctlapp.Touch{
 		App:                 app,
 		Description:         "update: " + changeSummary,
+		Operation:           changeOperation,
+		User:                user,
+		NumResources:        len(newResources),
 		Namespaces:          nsNames,
 		IgnoreSuccessErr:    true,
-		AppChangesMaxToKeep: o.DeployFlags.AppChangesMaxToKeep,
+		Metadata:            withInputChangeMetadata(changeMeta, o.inputChangeMetadata),
+		Images:              imagesLock.AsMap(),
+		AppChangesMaxToKeep: changesRetention.MaxToKeep,
+		Input:               changeInput,
+	}
+
+	if o.DeployFlags.Snapshot {
+		touch.Snapshot = snapshot
 	}
 
+	stopWatchingInterrupts := interrupt.Watch()
+	defer stopWatchingInterrupts()
+
+	o.result.MarkApplying()
+
+	notifier := ctlnotif.NewNotifier(conf.Notifications(), nil)
+	notifEvent := ctlnotif.NewEvent(app.Name(), o.AppFlags.NamespaceFlags.Name, changeSummary, clusterChanges)
+
+	startedNotifEvent := notifEvent
+	startedNotifEvent.Type = ctlconf.NotificationEventStarted
+	o.notify(notifier, startedNotifEvent)
+	appEvents.Started(changeSummary)
+
 	err = touch.Do(func() error {
 		defer o.writeAppMetadataToFile(app)
 
 		err := clusterChangeSet.Apply(clusterChangesGraph)
 		if err != nil {
-			return err
+			return withChangeProgress(err)
 		}
 
 		// Remove unused GVs and GKs
-		return app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(newResources, nil),
+		err = app.UpdateUsedGVsAndGKs(failingAPIServicesPolicy.GVs(newResources, nil),
 			NewUsedGKsScope(newResources).GKs())
+		if err != nil {
+			return err
+		}
+
+		return o.recordIncrementalDigests(app, incremental, clusterChanges)
 	})
+
+	o.notify(notifier, notifEvent.WithResult(err))
+	appEvents.Finished(err)
+
 	if err != nil {
-		return err
+		interrupt.PrintResumeHint(err, "kapp deploy")
+
+		var stoppedErr ctlcap.ApplyStoppedError
+		if errors.As(err, &stoppedErr) && stoppedErr.Reason == ctlcap.ApplyStoppedReasonDeadline {
+			return DeployTimeoutExitStatus{o.DeployFlags.DeployTimeout, err}
+		}
+		return newApplyExitStatus(err)
 	}
 
 	if o.ApplyFlags.ExitStatus {
@@ -249,6 +716,65 @@ func (o *DeployOptions) Run() error {
 	return nil
 }
 
+// isIncremental indicates whether unchanged resources could be skipped;
+// plans and explanations always include all resources
+func (o *DeployOptions) isIncremental() bool {
+	return o.DeployFlags.Incremental && o.planChangesFunc == nil && o.planInputResources == nil && o.explainFunc == nil
+}
+
+func (o *DeployOptions) recordIncrementalDigests(app ctlapp.App,
+	incremental *incrementalDeploy, clusterChanges []*ctlcap.ClusterChange) error {
+
+	if incremental == nil {
+		return nil
+	}
+
+	digests := incremental.Digests(clusterChanges)
+	if !incremental.Changed(digests) {
+		return nil
+	}
+
+	err := app.SetIncrementalDigests(digests)
+	if err != nil {
+		return fmt.Errorf("Recording incremental deploy digests: %w", err)
+	}
+
+	return nil
+}
+
+// checkDeadline fails deploy before any changes are applied if deploy timeout
+// already passed; in-flight cluster requests are not interrupted to get here sooner
+func (o *DeployOptions) checkDeadline(numChanges int) error {
+	deadline := o.ApplyFlags.ClusterChangeSetOpts.Deadline
+	if deadline.IsZero() || time.Now().Before(deadline) {
+		return nil
+	}
+	return DeployTimeoutExitStatus{o.DeployFlags.DeployTimeout, ctlcap.ApplyStoppedError{
+		Reason:   ctlcap.ApplyStoppedReasonDeadline,
+		NumTotal: numChanges,
+	}}
+}
+
+func (o *DeployOptions) notify(notifier *ctlnotif.Notifier, event ctlnotif.Event) {
+	// Failing to notify should not affect deploy outcome
+	err := notifier.Notify(event)
+	if err != nil {
+		o.ui.ErrorLinef("Warning: Sending %s notifications: %s", event.Type, err)
+	}
+}
+
+func (o *DeployOptions) adopt() error {
+	adoptOpts := NewAdoptOptions(o.ui, o.depsFactory, o.logger)
+	adoptOpts.AppFlags = o.AppFlags
+	adoptOpts.FileFlags = o.FileFlags
+	adoptOpts.ResourceTypesFlags = o.ResourceTypesFlags
+	adoptOpts.LockFlags = o.LockFlags
+	adoptOpts.IntoNamespace = o.DeployFlags.IntoNamespace
+	adoptOpts.OverrideOwnershipOfExistingResources = o.DeployFlags.OverrideOwnershipOfExistingResources
+	adoptOpts.FileSystem = o.FileSystem
+	return adoptOpts.Run()
+}
+
 func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App) ([]schema.GroupKind, error) {
 	if o.DeployFlags.DisableGKScoping {
 		return []schema.GroupKind{}, nil
@@ -262,10 +788,16 @@ func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App)
 		return nil, err
 	}
 
-	// Handle existing apps without cached GKs
+	// Handle existing apps without cached GKs by scoping to GKs of recorded inputs.
 	// These apps can cache and scope to GKs in subsequent deploys
 	if usedGKs == nil {
-		return []schema.GroupKind{}, nil
+		usedGKs, err = o.recordedInputGKs(app)
+		if err != nil {
+			return nil, err
+		}
+		if usedGKs == nil {
+			return []schema.GroupKind{}, nil
+		}
 	}
 
 	for _, gk := range *usedGKs {
@@ -294,6 +826,50 @@ func (o *DeployOptions) newAndUsedGKs(newGKs []schema.GroupKind, app ctlapp.App)
 	return uniqGKs, nil
 }
 
+// recordedInputGKs returns GKs of inputs recorded with app changes
+// or nil if any of changes does not have its input recorded
+func (o *DeployOptions) recordedInputGKs(app ctlapp.App) (*[]schema.GroupKind, error) {
+	changes, err := app.Changes()
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	var gks []schema.GroupKind
+
+	for _, change := range changes {
+		input, err := change.Input()
+		if err != nil {
+			return nil, err
+		}
+		if input == nil {
+			return nil, nil
+		}
+		gks = append(gks, NewUsedGKsScope(input.Resources).GKs()...)
+	}
+
+	return &gks, nil
+}
+
+// newAndUsedGVs returns versions used to limit listed versions of each GK
+// (resources are listed once per version otherwise)
+func (o *DeployOptions) newAndUsedGVs(newResources []ctlres.Resource,
+	usedGKs []schema.GroupKind, usedGVs []schema.GroupVersion) []schema.GroupVersion {
+
+	// Versions are only limited together with GKs (e.g. not for apps without cached GVs)
+	if len(usedGKs) == 0 || len(usedGVs) == 0 {
+		return nil
+	}
+
+	gvs := append([]schema.GroupVersion{}, usedGVs...)
+	for _, res := range newResources {
+		gvs = append(gvs, res.GroupVersion())
+	}
+	return gvs
+}
+
 func (o *DeployOptions) newResources(
 	prep ctlapp.Preparation, labeledResources *ctlres.LabeledResources,
 	resourceFilter ctlres.ResourceFilter) ([]ctlres.Resource, ctlconf.Conf, []string, []schema.GroupKind, error) {
@@ -303,7 +879,35 @@ func (o *DeployOptions) newResources(
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
 
-	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(newResources)
+	// Keep pristine copy since resources are modified below
+	o.inputResources = nil
+	for _, res := range newResources {
+		o.inputResources = append(o.inputResources, res.DeepCopy())
+	}
+
+	if o.planChangesFunc != nil && o.planInputResources == nil {
+		o.planInputResources = o.inputResources
+	}
+
+	// Cluster config comes before in-manifest config so that the latter takes precedence
+	newResources = append(append([]ctlres.Resource{}, o.clusterConfigResources...), newResources...)
+
+	newResources, err = ctlconf.ResolveConfigFrom(newResources, o.configFromOpts)
+	if err != nil {
+		return nil, ctlconf.Conf{}, nil, nil, err
+	}
+
+	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaultsAndOpts(
+		newResources, ctlconf.ConfOpts{
+			StrictParsing:   o.DeployFlags.StrictConfig,
+			EnvSubstitution: o.DeployFlags.ConfigEnv,
+			Profile:         o.DeployFlags.ConfigProfile,
+		})
+	if err != nil {
+		return nil, ctlconf.Conf{}, nil, nil, err
+	}
+
+	conf, err = conf.WithPolicies(o.DeployFlags.Policies)
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
@@ -313,8 +917,30 @@ func (o *DeployOptions) newResources(
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
 
+	// Resolved after input resources are recorded so that secret values are not stored
+	conf, err = o.resolveSecrets(newResources, conf)
+	if err != nil {
+		return nil, ctlconf.Conf{}, nil, nil, err
+	}
+
+	// Mutate before kapp adds its labels so that mutations cannot remove them
+	err = o.applyMutations(newResources, conf.ApplyMutationMods())
+	if err != nil {
+		return nil, ctlconf.Conf{}, nil, nil, err
+	}
+
+	err = o.applyStrategies(newResources, conf)
+	if err != nil {
+		return nil, ctlconf.Conf{}, nil, nil, err
+	}
+
+	additionalLabels, err := o.additionalLabels(conf)
+	if err != nil {
+		return nil, ctlconf.Conf{}, nil, nil, err
+	}
+
 	err = labeledResources.Prepare(newResources, conf.OwnershipLabelMods(),
-		conf.LabelScopingMods(o.DeployFlags.DefaultLabelScopingRules), conf.AdditionalLabels())
+		conf.LabelScopingMods(o.DeployFlags.DefaultLabelScopingRules), additionalLabels)
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, nil, err
 	}
@@ -327,14 +953,154 @@ func (o *DeployOptions) newResources(
 	return resourceFilter.Apply(newResources), conf, nsNames, newGKs, nil
 }
 
+// appLabelsAndAnnotations merges app labels and annotations
+// specified in kapp config and via flags (flags take precedence)
+func (o *DeployOptions) appLabelsAndAnnotations(conf ctlconf.Conf) (map[string]string, map[string]string, error) {
+	labels := conf.AppLabels()
+	annotations := conf.AppAnnotations()
+
+	flagLabels, err := o.LabelFlags.AppLabelsAsMap()
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range flagLabels {
+		labels[k] = v
+	}
+
+	flagAnnotations, err := o.LabelFlags.AppAnnotationsAsMap()
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range flagAnnotations {
+		annotations[k] = v
+	}
+
+	return labels, annotations, nil
+}
+
+// additionalLabels includes app labels that should be propagated to app resources
+func (o *DeployOptions) additionalLabels(conf ctlconf.Conf) (map[string]string, error) {
+	result := conf.AdditionalLabels()
+
+	propagateKeys := append(conf.PropagateAppLabels(), o.LabelFlags.PropagateAppLabels...)
+	if len(propagateKeys) == 0 {
+		return result, nil
+	}
+
+	appLabels, _, err := o.appLabelsAndAnnotations(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	creationLabels, err := o.LabelFlags.AsMap()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range propagateKeys {
+		val, found := appLabels[key]
+		if !found {
+			val, found = creationLabels[key]
+		}
+		if !found {
+			return nil, fmt.Errorf("Expected app label '%s' to be specified to propagate it to app resources", key)
+		}
+		result[key] = val
+	}
+
+	return result, nil
+}
+
+func (o *DeployOptions) applyMutations(resources []ctlres.Resource, mods []ctlres.ResourceModWithMultiple) error {
+	for _, res := range resources {
+		for _, mod := range mods {
+			if mod.IsResourceMatching(res) {
+				err := mod.ApplyFromMultiple(res, nil)
+				if err != nil {
+					return fmt.Errorf("Applying mutation rules: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (o *DeployOptions) applyStrategies(resources []ctlres.Resource, conf ctlconf.Conf) error {
+	for _, res := range resources {
+		for _, mod := range conf.ApplyStrategyMods(res) {
+			err := mod.Apply(res)
+			if err != nil {
+				return fmt.Errorf("Applying strategy rules: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *DeployOptions) resolveSecrets(resources []ctlres.Resource, conf ctlconf.Conf) (ctlconf.Conf, error) {
+	secretProviders := conf.SecretProviders()
+	if len(secretProviders) == 0 {
+		return conf, nil
+	}
+
+	resolver, err := ctlsecrets.NewResolverFromConfigs(secretProviders, ctlsecrets.ProviderOpts{})
+	if err != nil {
+		return ctlconf.Conf{}, err
+	}
+
+	maskRules, err := resolver.Resolve(resources)
+	if err != nil {
+		return ctlconf.Conf{}, err
+	}
+
+	return conf.WithDiffMaskRules(maskRules), nil
+}
+
 func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
 	var allResources []ctlres.Resource
 
+	if o.planInputResources != nil {
+		for _, res := range o.planInputResources {
+			allResources = append(allResources, res.DeepCopy())
+		}
+		return allResources, nil
+	}
+
+	if o.Resources != nil {
+		for _, res := range o.Resources {
+			allResources = append(allResources, res.DeepCopy())
+		}
+		return allResources, nil
+	}
+
 	if len(o.FileFlags.Files) == 0 {
 		return nil, fmt.Errorf("Expected at least one --file (-f) specified with a file or directory path")
 	}
+
+	rendered := newRenderedInputs()
+
+	fileRsOpts, err := o.HTTPFlags.FileResourcesOpts()
+	if err != nil {
+		return nil, err
+	}
+
+	var decryptor *sopsinput.Decryptor
+	if o.SopsFlags.Enabled {
+		decryptor = sopsinput.NewDecryptor(o.SopsFlags.Binary)
+		fileRsOpts.WrapSource = decryptor.Source
+	}
+
 	for _, file := range o.FileFlags.Files {
-		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		resources, found, err := o.renderedResources(file, &rendered)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			allResources = append(allResources, resources...)
+			continue
+		}
+
+		fileRs, err := ctlres.NewFileResourcesWithOpts(o.FileSystem, file, fileRsOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -348,12 +1114,60 @@ func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
 			allResources = append(allResources, resources...)
 		}
 	}
+
+	err = rendered.Check(o.YttFlags, o.HelmFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	if decryptor != nil {
+		// Decrypted values are masked (via enforced rules) in diffs, recorded app changes and plan files
+		maskConfig, err := decryptor.MaskConfigResource()
+		if err != nil {
+			return nil, err
+		}
+		if maskConfig != nil {
+			allResources = append(allResources, maskConfig)
+		}
+	}
+
+	o.inputChangeMetadata = rendered.ChangeMetadata()
+
+	imagesResolver, err := o.ImagesFlags.Resolver()
+	if err != nil {
+		return nil, err
+	}
+
+	allResources, o.imageResolutions, err = imagesResolver.Resolve(allResources, ctlconf.IsConfigResource)
+	if err != nil {
+		return nil, err
+	}
 	return allResources, nil
 }
 
+// imagesLock records images of resources that are about to be applied
+// so that recorded digests match exactly what is applied
+func (o *DeployOptions) imagesLock(newResources []ctlres.Resource) (ctlimg.Lock, error) {
+	if o.imageResolutions == nil {
+		return ctlimg.Lock{}, nil
+	}
+
+	lock := ctlimg.NewLockFromResources(newResources, o.imageResolutions)
+
+	if len(o.ImagesFlags.LockOutput) > 0 {
+		err := lock.WriteToFile(o.ImagesFlags.LockOutput)
+		if err != nil {
+			return ctlimg.Lock{}, err
+		}
+	}
+
+	return lock, nil
+}
+
 func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 	labeledResources *ctlres.LabeledResources, resourceFilter ctlres.ResourceFilter,
-	apps ctlapp.Apps, usedGKs []schema.GroupKind, resourceNamespaces []string, isNewApp bool) ([]ctlres.Resource, []ctlres.Resource, error) {
+	apps ctlapp.Apps, usedGKs []schema.GroupKind, usedGVs []schema.GroupVersion,
+	resourceNamespaces []string, isNewApp bool) ([]ctlres.Resource, []ctlres.Resource, error) {
 
 	labelErrorResolutionFunc := func(key string, val string) string {
 		items, _ := apps.List(nil)
@@ -367,10 +1181,11 @@ func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 	}
 
 	matchingOpts := ctlres.AllAndMatchingOpts{
-		ExistingNonLabeledResourcesCheck:            o.DeployFlags.ExistingNonLabeledResourcesCheck,
-		ExistingNonLabeledResourcesCheckConcurrency: o.DeployFlags.ExistingNonLabeledResourcesCheckConcurrency,
-		SkipResourceOwnershipCheck:                  o.DeployFlags.OverrideOwnershipOfExistingResources,
-		IsNewApp:                                    isNewApp,
+		ExistingNonLabeledResourcesCheck:              o.DeployFlags.ExistingNonLabeledResourcesCheck,
+		ExistingNonLabeledResourcesCheckConcurrency:   o.DeployFlags.ExistingNonLabeledResourcesCheckConcurrency,
+		ExistingNonLabeledResourcesCheckListThreshold: o.DeployFlags.ExistingNonLabeledResourcesCheckListThreshold,
+		SkipResourceOwnershipCheck:                    o.DeployFlags.OverrideOwnershipOfExistingResources,
+		IsNewApp:                                      isNewApp,
 
 		// Prevent accidently overriding kapp state records
 		DisallowedResourcesByLabelKeys: []string{ctlapp.KappIsAppLabelKey},
@@ -379,6 +1194,7 @@ func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 		//Scope resource searching to UsedGKs
 		IdentifiedResourcesListOpts: ctlres.IdentifiedResourcesListOpts{
 			GKsScope:           usedGKs,
+			GVsScope:           usedGVs,
 			ResourceNamespaces: resourceNamespaces,
 		},
 	}
@@ -405,7 +1221,7 @@ func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 
 func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 	newResources []ctlres.Resource, conf ctlconf.Conf, supportObjs FactorySupportObjs) (
-	ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, bool, string, error) {
+	ctlcap.ClusterChangeSet, []*ctlcap.ClusterChange, *ctldgraph.ChangeGraph, bool, string, error) {
 
 	var clusterChangeSet ctlcap.ClusterChangeSet
 
@@ -415,31 +1231,38 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 
 		err := ctldiff.NewRenewableResources(existingResources, newResources).Prepare()
 		if err != nil {
-			return clusterChangeSet, nil, false, "", err
+			return clusterChangeSet, nil, nil, false, "", err
 		}
 
 		changes, err := ctldiff.NewChangeSetWithVersionedRs(
 			existingResources, newResources, conf.TemplateRules(),
 			o.DiffFlags.ChangeSetOpts, changeFactory).Calculate()
 		if err != nil {
-			return clusterChangeSet, nil, false, "", err
+			return clusterChangeSet, nil, nil, false, "", err
 		}
 
 		diffFilter, err := o.DiffFlags.DiffFilter()
 		if err != nil {
-			return clusterChangeSet, nil, false, "", err
+			return clusterChangeSet, nil, nil, false, "", err
 		}
 
 		changes = diffFilter.Apply(changes)
 
-		msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
+		msgsUI := cmdcore.NewVerbosityMessagesUI(
+			cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui)), o.depsFactory.Verbosity())
 
 		convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{
 			IgnoreFailingAPIServices: o.ResourceTypesFlags.IgnoreFailingAPIServices,
+			WaitForServiceEndpoints:  o.ApplyFlags.WaitForServiceEndpoints,
 		})
 
+		clusterChangeOpts := o.ApplyFlags.ClusterChangeOpts
+		clusterChangeOpts.FallbackOnReplaceMatcher = conf.FallbackOnReplaceMatcher()
+		clusterChangeOpts.NonBlockingWaitMatcher = conf.NonBlockingWaitMatcher()
+		clusterChangeOpts.WaitBehaviorRules = conf.WaitBehaviorRules()
+
 		clusterChangeFactory := ctlcap.NewClusterChangeFactory(
-			o.ApplyFlags.ClusterChangeOpts, supportObjs.IdentifiedResources,
+			clusterChangeOpts, supportObjs.IdentifiedResources,
 			changeFactory, changeSetFactory, convergedResFactory, msgsUI, conf.DiffMaskRules())
 
 		clusterChangeSet = ctlcap.NewClusterChangeSet(
@@ -450,7 +1273,7 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 	clusterChanges, clusterChangesGraph, err := clusterChangeSet.Calculate()
 	if err != nil {
 		// Return graph for inspection
-		return clusterChangeSet, clusterChangesGraph, false, "", err
+		return clusterChangeSet, nil, clusterChangesGraph, false, "", err
 	}
 
 	var changesSummary string
@@ -463,7 +1286,26 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 		changesSummary = changeSetView.Summary()
 	}
 
-	return clusterChangeSet, clusterChangesGraph, (len(clusterChanges) == 0), changesSummary, err
+	if o.planChangesFunc != nil {
+		err := o.planChangesFunc(o.planInputResources, clusterChanges, conf.DiffMaskRules())
+		if err != nil {
+			return clusterChangeSet, nil, clusterChangesGraph, false, "", err
+		}
+	}
+
+	if len(o.DeployFlags.TerraformPlanFile) > 0 {
+		plan, err := NewTerraformPlan(o.AppFlags.Name, clusterChanges, conf.DiffMaskRules())
+		if err != nil {
+			return clusterChangeSet, nil, clusterChangesGraph, false, "", err
+		}
+
+		err = plan.Write(o.DeployFlags.TerraformPlanFile)
+		if err != nil {
+			return clusterChangeSet, nil, clusterChangesGraph, false, "", err
+		}
+	}
+
+	return clusterChangeSet, clusterChanges, clusterChangesGraph, (len(clusterChanges) == 0), changesSummary, err
 }
 
 func (o *DeployOptions) existingPodResources(existingResources []ctlres.Resource) []ctlres.Resource {
@@ -476,6 +1318,52 @@ func (o *DeployOptions) existingPodResources(existingResources []ctlres.Resource
 	return existingPods
 }
 
+// snapshot captures current cluster state of resources that are about
+// to be updated or deleted so that they could be restored later
+func (o *DeployOptions) snapshot(clusterChanges []*ctlcap.ClusterChange, conf ctlconf.Conf) ([]ctlres.Resource, error) {
+	if !o.DeployFlags.Snapshot && len(o.DeployFlags.SnapshotFile) == 0 {
+		return nil, nil
+	}
+
+	snapshot := []ctlres.Resource{}
+
+	for _, change := range clusterChanges {
+		switch change.ApplyOp() {
+		case ctlcap.ClusterChangeApplyOpUpdate, ctlcap.ClusterChangeApplyOpDelete:
+			clusterRes := change.ClusterOriginalResource()
+			if clusterRes == nil {
+				continue
+			}
+			res, err := portableResource(clusterRes, conf, o.ApplyFlags.ClusterChangeOpts.AppLabelKey)
+			if err != nil {
+				return nil, fmt.Errorf("Snapshotting resource '%s': %w", clusterRes.Description(), err)
+			}
+			snapshot = append(snapshot, res)
+		}
+	}
+
+	if len(o.DeployFlags.SnapshotFile) > 0 {
+		var resourcesYAML []string
+
+		for _, res := range snapshot {
+			bs, err := res.AsYAMLBytes()
+			if err != nil {
+				return nil, fmt.Errorf("Serializing resource '%s': %w", res.Description(), err)
+			}
+			resourcesYAML = append(resourcesYAML, "---\n"+string(bs))
+		}
+
+		err := os.WriteFile(o.DeployFlags.SnapshotFile, []byte(strings.Join(resourcesYAML, "")), 0600)
+		if err != nil {
+			return nil, fmt.Errorf("Writing snapshot file: %w", err)
+		}
+
+		o.ui.PrintLinef("Saved snapshot of %d resources to '%s'", len(snapshot), o.DeployFlags.SnapshotFile)
+	}
+
+	return snapshot, nil
+}
+
 func (o *DeployOptions) writeAppMetadataToFile(app ctlapp.App) error {
 	if o.DeployFlags.AppMetadataFile != "" {
 		meta, err := app.Meta()
@@ -572,3 +1460,95 @@ func (o *DeployOptions) presentDiffUI(graph *ctldgraph.ChangeGraph) error {
 	}
 	return ctldiffui.NewServer(opts, o.ui).Run()
 }
+
+// recordImpersonation records explicitly configured identity with new app
+// so that subsequent changes to the app use it by default
+func (o *DeployOptions) recordImpersonation(app ctlapp.App) error {
+	impersonation := o.depsFactory.Impersonation()
+	if len(impersonation.UserName) == 0 && len(impersonation.Groups) == 0 {
+		return nil
+	}
+	return app.SetImpersonation(&ctlapp.MetaImpersonation{User: impersonation.UserName, Groups: impersonation.Groups})
+}
+
+// migrateAppLabelKey switches app to a different label key. Existing app resources
+// are labeled with new key first so that they continue to be found via app label selector;
+// old label is removed from them as part of the following update.
+func (o *DeployOptions) migrateAppLabelKey(app ctlapp.App, isNewApp bool, identifiedResources ctlres.IdentifiedResources) error {
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	newKey := o.DeployFlags.AppLabelKey
+
+	if meta.LabelKey == newKey {
+		return nil
+	}
+
+	if !isNewApp {
+		o.ui.PrintLinef("Migrating app label key from '%s' to '%s'", meta.LabelKey, newKey)
+
+		resources, err := identifiedResources.List(labels.Set(meta.Labels()).AsSelector(), nil,
+			ctlres.IdentifiedResourcesListOpts{ResourceNamespaces: meta.LastChange.Namespaces})
+		if err != nil {
+			return err
+		}
+
+		patchJSON, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]string{newKey: meta.LabelValue},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, res := range resources {
+			// Transient resources (e.g. Pods) are relabeled by their owners
+			if res.Transient() {
+				continue
+			}
+			_, err := identifiedResources.Patch(res, types.MergePatchType, patchJSON)
+			if err != nil {
+				return fmt.Errorf("Relabeling resource '%s': %w", res.Description(), err)
+			}
+		}
+	}
+
+	return app.SetLabelKey(newKey)
+}
+
+// checkPolicies rejects changes that violate policies enabled via --policy
+func (o *DeployOptions) checkPolicies(clusterChanges []*ctlcap.ClusterChange, conf ctlconf.Conf) error {
+	if len(conf.Policies()) == 0 {
+		return nil
+	}
+
+	var violations []string
+
+	for _, change := range clusterChanges {
+		var op string
+
+		switch change.ApplyOp() {
+		case ctlcap.ClusterChangeApplyOpAdd:
+			op = ctlconf.PolicyOperationCreate
+		case ctlcap.ClusterChangeApplyOpUpdate:
+			op = ctlconf.PolicyOperationUpdate
+		case ctlcap.ClusterChangeApplyOpDelete:
+			op = ctlconf.PolicyOperationDelete
+		default:
+			continue
+		}
+
+		for _, policy := range conf.Policies() {
+			violations = append(violations, policy.Check(change.Resource(), op)...)
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("Policy violations:\n- %s", strings.Join(violations, "\n- "))
+	}
+
+	return nil
+}
@@ -24,7 +24,7 @@ func (d DeployApplyExitStatus) Error() string {
 
 func (d DeployApplyExitStatus) ExitStatus() int {
 	if d.hasNoChanges {
-		return 2
+		return ExitStatusNoChanges
 	}
-	return 3
+	return ExitStatusPendingChanges
 }
@@ -0,0 +1,233 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	clusterTargetsAPIVersion = "kapp.k14s.io/v1alpha1"
+	clusterTargetsKind       = "ClusterTargets"
+)
+
+type ClustersFlags struct {
+	Contexts []string
+	File     string
+}
+
+func (s *ClustersFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&s.Contexts, "clusters", nil,
+		"Deploy app to each of kubeconfig contexts (format: ctx1,ctx2) (could be specified multiple times)")
+	cmd.Flags().StringVar(&s.File, "clusters-file", "",
+		"Deploy app to each of targets specified in ClusterTargets file")
+}
+
+func (s ClustersFlags) Enabled() bool {
+	return len(s.Contexts) > 0 || len(s.File) > 0
+}
+
+// ClusterTargets is read from --clusters-file and lists clusters
+// (kubeconfig contexts) that app is deployed to
+type ClusterTargets struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Targets    []ClusterTarget `json:"targets,omitempty"`
+}
+
+type ClusterTarget struct {
+	Context string `json:"context"`
+	// Namespace overrides app namespace (--namespace) for this cluster
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func NewClusterTargetsFromBytes(bs []byte) (ClusterTargets, error) {
+	var targets ClusterTargets
+
+	err := yaml.UnmarshalStrict(bs, &targets)
+	if err != nil {
+		return ClusterTargets{}, fmt.Errorf("Unmarshaling cluster targets: %w", err)
+	}
+
+	if targets.APIVersion != clusterTargetsAPIVersion || targets.Kind != clusterTargetsKind {
+		return ClusterTargets{}, fmt.Errorf("Expected cluster targets to have apiVersion '%s' and kind '%s'",
+			clusterTargetsAPIVersion, clusterTargetsKind)
+	}
+
+	for i, target := range targets.Targets {
+		if len(target.Context) == 0 {
+			return ClusterTargets{}, fmt.Errorf("Expected cluster target %d to specify context", i)
+		}
+	}
+
+	return targets, nil
+}
+
+// Targets returns clusters specified via --clusters followed by ones from --clusters-file
+func (s ClustersFlags) Targets() ([]ClusterTarget, error) {
+	var result []ClusterTarget
+
+	for _, context := range s.Contexts {
+		result = append(result, ClusterTarget{Context: context})
+	}
+
+	if len(s.File) > 0 {
+		bs, err := os.ReadFile(s.File)
+		if err != nil {
+			return nil, fmt.Errorf("Reading cluster targets file '%s': %w", s.File, err)
+		}
+		targets, err := NewClusterTargetsFromBytes(bs)
+		if err != nil {
+			return nil, fmt.Errorf("Reading cluster targets file '%s': %w", s.File, err)
+		}
+		result = append(result, targets.Targets...)
+	}
+
+	seen := map[string]struct{}{}
+	for _, target := range result {
+		if _, found := seen[target.Context]; found {
+			return nil, fmt.Errorf("Expected cluster context '%s' to be specified once", target.Context)
+		}
+		seen[target.Context] = struct{}{}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("Expected at least one cluster target to be specified")
+	}
+
+	return result, nil
+}
+
+type ClusterDeployResult struct {
+	Target ClusterTarget
+	Err    error
+}
+
+// ClustersDeployError aggregates deploy outcomes across clusters. Clusters
+// that only returned diff or apply exit statuses are not considered failed.
+type ClustersDeployError struct {
+	Results []ClusterDeployResult
+}
+
+var _ ExitStatus = ClustersDeployError{}
+
+func (e ClustersDeployError) Error() string {
+	failed := e.failed()
+	if len(failed) == 0 {
+		return fmt.Sprintf("Exiting after deploying to %d cluster(s) (exit status %d)", len(e.Results), e.ExitStatus())
+	}
+
+	var lines []string
+	for _, result := range failed {
+		lines = append(lines, fmt.Sprintf("- %s: %s", result.Target.Context, result.Err))
+	}
+	return fmt.Sprintf("Deploying to %d of %d cluster(s) failed:\n%s",
+		len(failed), len(e.Results), strings.Join(lines, "\n"))
+}
+
+// ExitStatus is shared exit status of failed clusters (or general error if they differ).
+// When no cluster failed, it indicates whether any cluster had (pending) changes.
+func (e ClustersDeployError) ExitStatus() int {
+	if failed := e.failed(); len(failed) > 0 {
+		exitStatus := ExitStatusForError(failed[0].Err)
+		for _, result := range failed[1:] {
+			if ExitStatusForError(result.Err) != exitStatus {
+				return ExitStatusError
+			}
+		}
+		return exitStatus
+	}
+
+	var exitStatus int
+	for _, result := range e.Results {
+		if resultExitStatus := ExitStatusForError(result.Err); resultExitStatus > exitStatus {
+			exitStatus = resultExitStatus
+		}
+	}
+	return exitStatus
+}
+
+func (e ClustersDeployError) failed() []ClusterDeployResult {
+	var result []ClusterDeployResult
+	for _, res := range e.Results {
+		if !isSuccessfulChangeErr(res.Err) {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+// runClusters deploys app to each cluster one after another; failure
+// to deploy to one cluster does not prevent deploying to others
+func (o *DeployOptions) runClusters() error {
+	if o.WatchFlags.Enabled {
+		return fmt.Errorf("Expected --watch to not be specified with --clusters or --clusters-file")
+	}
+	if o.OutputFlags.IsStructured() {
+		return fmt.Errorf("Expected --output to not be structured with --clusters or --clusters-file")
+	}
+
+	targets, err := o.ClustersFlags.Targets()
+	if err != nil {
+		return err
+	}
+
+	var results []ClusterDeployResult
+	var hasNonNilErr bool
+
+	for _, target := range targets {
+		o.ui.PrintLinef("--- deploying app '%s' to cluster '%s'", o.AppFlags.Name, target.Context)
+
+		err := o.clusterDeployOptions(target).runOnce()
+		if err != nil {
+			hasNonNilErr = true
+			if !isSuccessfulChangeErr(err) {
+				o.ui.ErrorLinef("--- deploying app '%s' to cluster '%s' failed: %s", o.AppFlags.Name, target.Context, err)
+			}
+		}
+
+		results = append(results, ClusterDeployResult{Target: target, Err: err})
+	}
+
+	o.printClustersSummary(results)
+
+	if hasNonNilErr {
+		return ClustersDeployError{Results: results}
+	}
+	return nil
+}
+
+// clusterDeployOptions returns copy of options targeting given cluster
+// so that state collected while deploying is not shared between clusters
+func (o *DeployOptions) clusterDeployOptions(target ClusterTarget) *DeployOptions {
+	cpOpts := *o
+	cpOpts.depsFactory = o.depsFactory.WithContext(target.Context)
+	cpOpts.ClustersFlags = ClustersFlags{}
+	if len(target.Namespace) > 0 {
+		cpOpts.AppFlags.NamespaceFlags.Name = target.Namespace
+	}
+	return &cpOpts
+}
+
+func (o *DeployOptions) printClustersSummary(results []ClusterDeployResult) {
+	o.ui.PrintLinef("")
+	o.ui.PrintLinef("Clusters summary (%d):", len(results))
+
+	for _, result := range results {
+		status := "succeeded"
+		switch {
+		case result.Err == nil:
+		case isSuccessfulChangeErr(result.Err):
+			status = fmt.Sprintf("succeeded (exit status %d)", ExitStatusForError(result.Err))
+		default:
+			status = fmt.Sprintf("failed (exit status %d)", ExitStatusForError(result.Err))
+		}
+		o.ui.PrintLinef("- %s: %s", result.Target.Context, status)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+)
+
+func TestClustersFlagsTargets(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "targets.yml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: ClusterTargets
+targets:
+- context: ctx2
+  namespace: ns2
+- context: ctx3
+`), 0600))
+
+	targets, err := cmdapp.ClustersFlags{Contexts: []string{"ctx1"}, File: file}.Targets()
+	require.NoError(t, err)
+	require.Equal(t, []cmdapp.ClusterTarget{{Context: "ctx1"}, {Context: "ctx2", Namespace: "ns2"}, {Context: "ctx3"}}, targets)
+
+	_, err = cmdapp.ClustersFlags{Contexts: []string{"ctx2"}, File: file}.Targets()
+	require.EqualError(t, err, "Expected cluster context 'ctx2' to be specified once")
+
+	_, err = cmdapp.NewClusterTargetsFromBytes([]byte("apiVersion: kapp.k14s.io/v1alpha1\nkind: ClusterTargets\ntargets: [{namespace: ns1}]"))
+	require.EqualError(t, err, "Expected cluster target 0 to specify context")
+
+	_, err = cmdapp.NewClusterTargetsFromBytes([]byte("kind: Other"))
+	require.EqualError(t, err, "Expected cluster targets to have apiVersion 'kapp.k14s.io/v1alpha1' and kind 'ClusterTargets'")
+}
+
+func TestClustersDeployErrorExitStatus(t *testing.T) {
+	result := func(context string, err error) cmdapp.ClusterDeployResult {
+		return cmdapp.ClusterDeployResult{Target: cmdapp.ClusterTarget{Context: context}, Err: err}
+	}
+	timeoutErr := cmdapp.TimeoutExitStatus{fmt.Errorf("timed out")}
+	applyErr := cmdapp.ApplyErrorExitStatus{fmt.Errorf("failed")}
+	noChangesErr := cmdapp.DeployDiffExitStatus{HasNoChanges: true}
+	changesErr := cmdapp.DeployDiffExitStatus{HasNoChanges: false}
+
+	examples := []struct {
+		Results    []cmdapp.ClusterDeployResult
+		ExitStatus int
+	}{
+		{[]cmdapp.ClusterDeployResult{result("ctx1", nil), result("ctx2", timeoutErr)}, cmdapp.ExitStatusTimeout},
+		{[]cmdapp.ClusterDeployResult{result("ctx1", timeoutErr), result("ctx2", applyErr)}, cmdapp.ExitStatusError},
+		{[]cmdapp.ClusterDeployResult{result("ctx1", changesErr), result("ctx2", applyErr)}, cmdapp.ExitStatusApplyError},
+		{[]cmdapp.ClusterDeployResult{result("ctx1", noChangesErr), result("ctx2", noChangesErr)}, cmdapp.ExitStatusNoChanges},
+		{[]cmdapp.ClusterDeployResult{result("ctx1", noChangesErr), result("ctx2", changesErr)}, cmdapp.ExitStatusPendingChanges},
+	}
+
+	for i, ex := range examples {
+		require.Equal(t, ex.ExitStatus, cmdapp.ExitStatusForError(cmdapp.ClustersDeployError{Results: ex.Results}), "Example %d", i)
+	}
+
+	err := cmdapp.ClustersDeployError{Results: []cmdapp.ClusterDeployResult{result("ctx1", nil), result("ctx2", applyErr)}}
+	require.EqualError(t, err, "Deploying to 1 of 2 cluster(s) failed:\n- ctx2: failed")
+}
@@ -28,7 +28,7 @@ func (d DeployDiffExitStatus) Error() string {
 
 func (d DeployDiffExitStatus) ExitStatus() int {
 	if d.HasNoChanges {
-		return 2
+		return ExitStatusNoChanges
 	}
-	return 3
+	return ExitStatusPendingChanges
 }
@@ -5,9 +5,12 @@ package app
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
 )
 
 type DeployFlags struct {
@@ -15,11 +18,15 @@ type DeployFlags struct {
 	Patch      bool
 	AllowEmpty bool
 
-	ExistingNonLabeledResourcesCheck            bool
-	ExistingNonLabeledResourcesCheckConcurrency int
-	OverrideOwnershipOfExistingResources        bool
+	ExistingNonLabeledResourcesCheck              bool
+	ExistingNonLabeledResourcesCheckConcurrency   int
+	ExistingNonLabeledResourcesCheckListThreshold int
+	OverrideOwnershipOfExistingResources          bool
+	Adopt                                         bool
 
-	AppChangesMaxToKeep int
+	AppChangesMaxToKeep          int
+	AppChangesMaxAge             time.Duration
+	AppChangesMaxToKeepResources int
 
 	DefaultLabelScopingRules bool
 
@@ -28,9 +35,40 @@ type DeployFlags struct {
 	AppMetadataFile string
 
 	DisableGKScoping bool
+
+	DeployTimeout time.Duration
+
+	WaitWatch bool
+
+	Incremental bool
+
+	Policies      []string
+	StrictConfig  bool
+	ConfigEnv     bool
+	ConfigProfile string
+	ClusterConfig bool
+
+	Protect     bool
+	TTL         time.Duration
+	AppLabelKey string
+
+	Snapshot     bool
+	SnapshotFile string
+
+	TerraformPlanFile string
+
+	AppEvents bool
+
+	ChangeMetadata          []string
+	ChangeMetadataAutomatic bool
+
+	// Used to determine whether flags should take precedence over kapp config
+	flags *pflag.FlagSet
 }
 
 func (s *DeployFlags) Set(cmd *cobra.Command) {
+	s.flags = cmd.Flags()
+
 	cmd.Flags().BoolVar(&s.AllowCheck, "allow-check", false, "Enable client-side allowing")
 	cmd.Flags().StringSliceVar(&s.AllowedNamespaces, "allow-ns", nil, "Set allowed namespace for resources (does not apply to the app itself)")
 	cmd.Flags().BoolVar(&s.AllowAllNamespaces, "allow-all-ns", false, "Set to allow all namespaces for resources (does not apply to the app itself)")
@@ -46,18 +84,97 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 		true, "Find and consider existing non-labeled resources in diff")
 	cmd.Flags().IntVar(&s.ExistingNonLabeledResourcesCheckConcurrency, "existing-non-labeled-resources-check-concurrency",
 		100, "Concurrency to check for existing non-labeled resources")
+	cmd.Flags().IntVar(&s.ExistingNonLabeledResourcesCheckListThreshold, "existing-non-labeled-resources-check-list-threshold",
+		20, "List resources of the same type and namespace instead of getting each one when there are at least this many of them (0 disables listing)")
 	cmd.Flags().BoolVar(&s.OverrideOwnershipOfExistingResources, "dangerous-override-ownership-of-existing-resources",
 		false, "Steal existing resources from another app")
+	cmd.Flags().BoolVar(&s.Adopt, "adopt", false,
+		"Only take ownership of existing resources specified in files without modifying them (same as 'kapp adopt')")
 
 	cmd.Flags().BoolVar(&s.DefaultLabelScopingRules, "default-label-scoping-rules",
 		true, "Use default label scoping rules")
 
 	cmd.Flags().IntVar(&s.AppChangesMaxToKeep, "app-changes-max-to-keep", ctlapp.AppChangesMaxToKeepDefault, "Maximum number of app changes to keep")
+	cmd.Flags().DurationVar(&s.AppChangesMaxAge, "app-changes-max-age", 0, "Maximum age of app changes to keep (newest change is always kept; 0 disables age based deletion)")
+	cmd.Flags().IntVar(&s.AppChangesMaxToKeepResources, "app-changes-max-to-keep-resources", ctlapp.AppChangesMaxToKeepResourcesDefault,
+		"Maximum number of app changes to keep recorded resources for (used by rollback and app change describe)")
 
 	cmd.Flags().BoolVar(&s.Logs, "logs", true, fmt.Sprintf("Show logs from Pods annotated as '%s'", deployLogsAnnKey))
 	cmd.Flags().BoolVar(&s.LogsAll, "logs-all", false, "Show logs from all Pods")
 	cmd.Flags().StringVar(&s.AppMetadataFile, "app-metadata-file-output", "", "Set filename to write app metadata")
 
+	cmd.Flags().BoolVar(&s.ClusterConfig, "cluster-config", false,
+		"Use kapp config from 'kapp-config' ConfigMap (key 'config.yml') in kube-system and app namespace")
+	cmd.Flags().BoolVar(&s.StrictConfig, "strict-config", false,
+		"Fail on unknown keys in kapp config (same as setting 'strictParsing: true' in each config)")
+	cmd.Flags().StringVar(&s.ConfigProfile, "config-profile", "",
+		"Activate kapp configs tagged with given profile (configs without profile are always active)")
+	cmd.Flags().BoolVar(&s.ConfigEnv, "config-env", false,
+		"Substitute ${VAR} references in kapp config with environment variables (same as setting 'envSubstitution: true' in each config)")
+	cmd.Flags().StringSliceVar(&s.Policies, "policy", nil,
+		"Enforce kapp policy with given name; policies are specified as kapp.k14s.io/v1alpha1 Policy documents (can repeat)")
+
+	cmd.Flags().BoolVar(&s.Protect, "protect", false,
+		"Protect app from deletion; 'kapp delete' requires --unprotect afterwards (use --protect=false to remove protection)")
+
+	cmd.Flags().DurationVar(&s.TTL, "ttl", 0,
+		"Mark app to be deleted by 'kapp gc' once duration passes since this deploy (0s removes previously set TTL)")
+
+	cmd.Flags().StringVar(&s.AppLabelKey, "app-label-key", "",
+		"Label key used to associate resources with app (defaults to 'kapp.k14s.io/app' for new apps; "+
+			"existing app resources are relabeled when changed)")
+
+	cmd.Flags().BoolVar(&s.Snapshot, "snapshot", false,
+		"Record cluster state of resources that are about to be updated or deleted with app change (see 'kapp rollback --from-snapshot')")
+	cmd.Flags().StringVar(&s.SnapshotFile, "snapshot-file", "",
+		"Write cluster state of resources that are about to be updated or deleted into a file before applying changes")
+
+	cmd.Flags().StringVar(&s.TerraformPlanFile, "diff-terraform-plan-file", "",
+		"Write calculated changes into a file in Terraform JSON plan format (e.g. for plan review tooling)")
+
+	cmd.Flags().BoolVar(&s.AppEvents, "app-events", false,
+		"Create Kubernetes Events in app namespace for deploy start, outcome and resource wait failures")
+
+	cmd.Flags().StringSliceVar(&s.ChangeMetadata, "change-metadata", nil,
+		"Record metadata with app change (format: key=val) (can repeat)")
+	cmd.Flags().BoolVar(&s.ChangeMetadataAutomatic, "change-metadata-automatic", true,
+		"Record git SHA, CI job URL and local user with app change when available")
+
 	cmd.Flags().BoolVar(&s.DisableGKScoping, "dangerous-disable-gk-scoping",
 		false, "Disable scoping of resource searching to used GroupKinds")
+
+	cmd.Flags().DurationVar(&s.DeployTimeout, "deploy-timeout", 0,
+		"Maximum amount of time for the whole deploy (diff, apply and wait); no new changes are applied after it passes (0s means no timeout)")
+
+	cmd.Flags().BoolVar(&s.WaitWatch, "wait-watch", true,
+		"Watch app resources (per resource type and namespace) instead of getting each resource while waiting")
+
+	cmd.Flags().BoolVar(&s.Incremental, "incremental", false,
+		"Skip diffing resources whose content and cluster resourceVersion did not change since last successful deploy with this flag")
+}
+
+// AppChangesRetention returns retention based on kapp config
+// unless it was explicitly specified via flags
+func (s *DeployFlags) AppChangesRetention(conf ctlconf.Conf) ctlapp.ChangesRetention {
+	result := ctlapp.ChangesRetention{MaxToKeep: s.AppChangesMaxToKeep, MaxAge: s.AppChangesMaxAge}
+	confRetention := conf.AppChangesRetention()
+
+	if confRetention.MaxToKeep != nil && !s.flagChanged("app-changes-max-to-keep") {
+		result.MaxToKeep = *confRetention.MaxToKeep
+	}
+	if len(confRetention.MaxAge) > 0 && !s.flagChanged("app-changes-max-age") {
+		result.MaxAge = confRetention.MaxAgeDuration()
+	}
+
+	return result
+}
+
+// ProtectChanged indicates whether app protection should be updated
+func (s *DeployFlags) ProtectChanged() bool { return s.flagChanged("protect") }
+
+// TTLChanged indicates whether app expiry should be updated
+func (s *DeployFlags) TTLChanged() bool { return s.flagChanged("ttl") }
+
+func (s *DeployFlags) flagChanged(name string) bool {
+	return s.flags != nil && s.flags.Changed(name)
 }
@@ -0,0 +1,47 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+type DeployTimeoutExitStatus struct {
+	Timeout time.Duration
+	Err     error
+}
+
+var _ ExitStatus = DeployTimeoutExitStatus{}
+
+func (d DeployTimeoutExitStatus) Error() string {
+	return fmt.Sprintf("Deploy timed out after %s: %s (exit status %d)",
+		d.Timeout, d.Err, d.ExitStatus())
+}
+
+func (d DeployTimeoutExitStatus) Unwrap() error { return d.Err }
+
+func (d DeployTimeoutExitStatus) ExitStatus() int { return ExitStatusTimeout }
+
+// withChangeProgress attaches apply progress to the error
+// so that it is recorded in the app change when applying was stopped
+func withChangeProgress(err error) error {
+	var stoppedErr ctlcap.ApplyStoppedError
+	if errors.As(err, &stoppedErr) {
+		return ctlapp.ChangeProgressError{
+			Err: err,
+			Progress: ctlapp.ChangeProgress{
+				StopReason: stoppedErr.Reason,
+				NumChanges: stoppedErr.NumTotal,
+				NumApplied: stoppedErr.NumApplied,
+				NumWaited:  stoppedErr.NumWaited,
+			},
+		}
+	}
+	return err
+}
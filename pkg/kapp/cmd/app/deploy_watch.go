@@ -0,0 +1,154 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/gitinput"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/ociinput"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type WatchFlags struct {
+	Enabled        bool
+	ResyncInterval time.Duration
+	PollInterval   time.Duration
+}
+
+func (s *WatchFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.Enabled, "watch", false,
+		"Keep running and re-deploy when input files change or periodically to correct drift")
+	cmd.Flags().DurationVar(&s.ResyncInterval, "watch-resync-interval", time.Minute,
+		"Set how often to re-deploy in watch mode even if input files did not change")
+	cmd.Flags().DurationVar(&s.PollInterval, "watch-poll-interval", time.Second,
+		"Set how often to check input files for changes in watch mode")
+}
+
+// runWatching re-deploys whenever local inputs change or resync interval passes;
+// failed deploys are reported, but do not stop watching (interrupt does)
+func (o *DeployOptions) runWatching() error {
+	for _, file := range o.FileFlags.Files {
+		if ctlres.IsStreamFile(file) {
+			return fmt.Errorf("Expected --file (-f) to not be stdin (-) or other stream when --watch is specified")
+		}
+	}
+	if o.WatchFlags.ResyncInterval <= 0 || o.WatchFlags.PollInterval <= 0 {
+		return fmt.Errorf("Expected --watch-resync-interval and --watch-poll-interval to be greater than zero")
+	}
+
+	// Watched for whole session since interrupt may arrive outside of apply
+	// (e.g. while calculating changes), in which case deploy is allowed to finish
+	interruptCh := make(chan struct{})
+	stopWatchingInterrupts := cmdcore.CancelSignals{}.Watch(func() { close(interruptCh) })
+	defer stopWatchingInterrupts()
+
+	o.ui.PrintLinef("Watching input files (resyncing every %s); interrupt to stop", o.WatchFlags.ResyncInterval)
+
+	for {
+		// Taken before deploy so that changes made while deploying are not missed
+		fingerprint, err := o.inputsFingerprint()
+		if err != nil {
+			return err
+		}
+
+		err = o.runOnce()
+		if err != nil {
+			var stoppedErr ctlcap.ApplyStoppedError
+			if errors.As(err, &stoppedErr) && stoppedErr.Reason == ctlcap.ApplyStoppedReasonInterrupted {
+				return err
+			}
+			if !isSuccessfulChangeErr(err) {
+				o.ui.ErrorLinef("kapp: Error: %s", err)
+			}
+		}
+
+		reason, stop, err := o.waitForNextDeploy(fingerprint, interruptCh)
+		if err != nil || stop {
+			return err
+		}
+
+		o.ui.PrintLinef("\n%s: re-deploying (%s)", time.Now().Format("15:04:05"), reason)
+	}
+}
+
+func (o *DeployOptions) waitForNextDeploy(fingerprint string, interruptCh <-chan struct{}) (string, bool, error) {
+	resyncTimer := time.NewTimer(o.WatchFlags.ResyncInterval)
+	defer resyncTimer.Stop()
+
+	pollTicker := time.NewTicker(o.WatchFlags.PollInterval)
+	defer pollTicker.Stop()
+
+	for {
+		select {
+		case <-interruptCh:
+			o.ui.PrintLinef("\nReceived interrupt: stopped watching")
+			return "", true, nil
+
+		case <-resyncTimer.C:
+			return "resync interval passed", false, nil
+
+		case <-pollTicker.C:
+			newFingerprint, err := o.inputsFingerprint()
+			if err != nil {
+				return "", false, err
+			}
+			if newFingerprint != fingerprint {
+				return "input files changed", false, nil
+			}
+		}
+	}
+}
+
+// inputsFingerprint summarizes names, sizes and modification times of local input files;
+// remote inputs (e.g. URLs, git repositories) are re-read on resync
+func (o *DeployOptions) inputsFingerprint() (string, error) {
+	paths := append([]string{}, o.FileFlags.Files...)
+	paths = append(paths, o.YttFlags.DataValues.FromFiles...)
+	paths = append(paths, o.HelmFlags.ValuesFiles...)
+
+	hash := sha256.New()
+
+	for _, path := range paths {
+		if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") ||
+			gitinput.IsURL(path) || ociinput.IsRef(path) {
+			continue
+		}
+
+		fsys := o.FileSystem
+		root := path
+		if fsys == nil {
+			fsys = os.DirFS(filepath.Dir(path))
+			root = filepath.Base(path)
+		}
+
+		err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hash, "%s %d %d %s\n", path, info.Size(), info.ModTime().UnixNano(), info.Mode())
+			return nil
+		})
+		if err != nil {
+			// Missing files are reported by deploy itself
+			fmt.Fprintf(hash, "%s error %s\n", path, err)
+		}
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type DisownOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            Flags
+	ResourceFilterFlags cmdtools.ResourceFilterFlags
+	ResourceTypesFlags  ResourceTypesFlags
+	LockFlags           LockFlags
+
+	All bool
+}
+
+func NewDisownOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DisownOptions {
+	return &DisownOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewDisownCmd(o *DisownOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disown",
+		Short: "Release resources from an app without deleting them",
+		Long: `Release resources from an app without deleting them.
+
+Ownership labels and annotations are removed from selected resources so that
+subsequent deploys and deletes of the app ignore them. Released resources could be
+adopted by another app (via 'kapp adopt' or 'kapp deploy') or managed by other tools.
+Labels within Pod templates are not modified to avoid restarting Pods.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+		},
+		Example: `
+  # Release ConfigMap 'config' from app 'app1'
+  kapp disown -a app1 --filter-kind-name ConfigMap/config
+
+  # Release all resources from app 'app1'
+  kapp disown -a app1 --all`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.ResourceFilterFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	o.LockFlags.Set(cmd)
+	cmd.Flags().BoolVar(&o.All, "all", false, "Release all app resources")
+	return cmd
+}
+
+func (o *DisownOptions) Run() error {
+	if o.All == o.ResourceFilterFlags.IsSet() {
+		return fmt.Errorf("Expected either resources to be selected via filter flags or --all to be specified")
+	}
+
+	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := o.LockFlags.Lock(app, supportObjs.CoreClient, o.ui, o.logger)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	usedGVs, err := app.UsedGVs()
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	labelKey, _, err := ctlres.NewSimpleLabel(labelSelector).KV()
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	allResources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces})
+	if err != nil {
+		return err
+	}
+
+	var ownedResources []ctlres.Resource
+	for _, res := range allResources {
+		// Transient resources are released together with their owners
+		if !res.Transient() {
+			ownedResources = append(ownedResources, res)
+		}
+	}
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return err
+	}
+
+	resources := resourceFilter.Apply(ownedResources)
+
+	printResourcesTable(o.ui, fmt.Sprintf("Resources to release from app '%s'", app.Name()), resources)
+
+	if len(resources) == 0 {
+		return nil
+	}
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return AbortedExitStatus{err}
+	}
+
+	touch := ctlapp.Touch{
+		App:                 app,
+		Description:         fmt.Sprintf("disown: %d resources", len(resources)),
+		Operation:           ctlapp.ChangeOperationDisown,
+		User:                changeUser(supportObjs.CoreClient, o.logger),
+		NumResources:        len(resources),
+		Namespaces:          meta.LastChange.Namespaces,
+		IgnoreSuccessErr:    true,
+		AppChangesMaxToKeep: ctlapp.AppChangesMaxToKeepDefault,
+	}
+
+	return touch.Do(func() error {
+		for _, res := range resources {
+			labelKeys := []string{labelKey, ctlres.NewAssociationLabel(res).Key()}
+
+			_, err := supportObjs.IdentifiedResources.Disown(res, labelKeys)
+			if err != nil {
+				return fmt.Errorf("Disowning resource '%s': %w", res.Description(), err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,265 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type DriftOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            Flags
+	ResourceFilterFlags cmdtools.ResourceFilterFlags
+	ResourceTypesFlags  ResourceTypesFlags
+
+	Diff       bool
+	ExitStatus bool
+}
+
+func NewDriftOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DriftOptions {
+	return &DriftOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewDriftCmd(o *DriftOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Show app resources that were modified since they were last applied",
+		Long: `Show app resources that were modified since they were last applied.
+
+Resources are compared against the copy of resource that kapp recorded when
+resource was last applied, hence configuration files are not necessary and
+read-only access to the cluster is sufficient. Resources that do not have
+recorded copy (e.g. due to kapp.k14s.io/disable-original annotation) are not checked.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+		},
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.ResourceFilterFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	cmd.Flags().BoolVar(&o.Diff, "diff", false, "Show diff that would restore drifted resources to their last applied state")
+	cmd.Flags().BoolVar(&o.ExitStatus, "exit-status", false, "Return specific exit status based on drift (2: no drift, 3: drift)")
+	return cmd
+}
+
+type driftedResource struct {
+	Resource ctlres.Resource
+	Change   ctldiff.Change
+}
+
+func (o *DriftOptions) Run() error {
+	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	usedGVs, err := app.UsedGVs()
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	resources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces})
+	if err != nil {
+		return err
+	}
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return err
+	}
+
+	resources = resourceFilter.Apply(resources)
+
+	conf, err := o.lastChangeConf(app)
+	if err != nil {
+		return err
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(),
+		conf.DiffAgainstLastAppliedFieldExclusionMods(), conf.DiffAgainstExistingFieldExclusionMods(), ctldiff.ChangeOpts{})
+
+	var checkedResources []ctlres.Resource
+	var driftedResources []driftedResource
+
+	for _, res := range resources {
+		change, tracked, err := changeFactory.NewResourceWithHistory(res).DriftChange()
+		if err != nil {
+			return fmt.Errorf("Checking drift of resource '%s': %w", res.Description(), err)
+		}
+		if !tracked {
+			continue
+		}
+		checkedResources = append(checkedResources, res)
+		if change != nil {
+			driftedResources = append(driftedResources, driftedResource{res, change})
+		}
+	}
+
+	o.printTable(app, checkedResources, driftedResources)
+
+	if o.Diff {
+		o.printDiffs(driftedResources, conf)
+	}
+
+	if o.ExitStatus {
+		return DriftExitStatus{HasDrift: len(driftedResources) > 0}
+	}
+
+	return nil
+}
+
+// lastChangeConf uses config that app was last deployed with (if recorded)
+// so that rebase rules match ones used during deploy
+func (o *DriftOptions) lastChangeConf(app ctlapp.App) (ctlconf.Conf, error) {
+	inputResources, err := lastChangeInputResources(app)
+	if err != nil {
+		return ctlconf.Conf{}, err
+	}
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(inputResources)
+	return conf, err
+}
+
+func lastChangeInputResources(app ctlapp.App) ([]ctlres.Resource, error) {
+	lastChange, err := app.LastChange()
+	if err != nil {
+		return nil, err
+	}
+
+	if lastChange == nil {
+		return nil, nil
+	}
+
+	input, err := lastChange.Input()
+	if err != nil {
+		return nil, err
+	}
+	if input == nil {
+		return nil, nil
+	}
+
+	return input.Resources, nil
+}
+
+func (o *DriftOptions) printTable(app ctlapp.App, checkedResources []ctlres.Resource, driftedResources []driftedResource) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Drift in app '%s'", app.Name()),
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Drifted"),
+			uitable.NewHeader("Fields"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+			{Column: 2, Asc: true},
+		},
+
+		Notes: []string{fmt.Sprintf("%d/%d resources drifted", len(driftedResources), len(checkedResources))},
+	}
+
+	driftedByUID := map[string]driftedResource{}
+	for _, drifted := range driftedResources {
+		driftedByUID[drifted.Resource.UID()] = drifted
+	}
+
+	for _, res := range checkedResources {
+		drifted, found := driftedByUID[res.UID()]
+
+		var paths []string
+		if found {
+			paths = uniqueSortedStrings(drifted.Change.OpsDiff().Paths())
+		}
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(res.Namespace()),
+			uitable.NewValueString(res.Name()),
+			uitable.NewValueString(res.Kind()),
+			uitable.ValueFmt{V: uitable.NewValueBool(found), Error: found},
+			uitable.NewValueStrings(paths),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
+
+func (o *DriftOptions) printDiffs(driftedResources []driftedResource, conf ctlconf.Conf) {
+	opts := ctldiff.TextDiffViewOpts{Context: 2, LineNumbers: true, Mask: true}
+
+	for _, drifted := range driftedResources {
+		textDiffView := ctldiff.NewTextDiffView(drifted.Change.ConfigurableTextDiff(), conf.DiffMaskRules(), opts)
+		o.ui.BeginLinef("@@ restore %s @@\n", drifted.Resource.Description())
+		o.ui.PrintBlock([]byte(textDiffView.String()))
+	}
+}
+
+func uniqueSortedStrings(strs []string) []string {
+	seen := map[string]struct{}{}
+	var result []string
+	for _, str := range strs {
+		if _, found := seen[str]; !found {
+			seen[str] = struct{}{}
+			result = append(result, str)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+type DriftExitStatus struct {
+	HasDrift bool
+}
+
+var _ ExitStatus = DriftExitStatus{}
+
+func (d DriftExitStatus) Error() string {
+	msg := "no drift"
+	if d.HasDrift {
+		msg = "drift"
+	}
+	return fmt.Sprintf("Exiting after detecting %s (exit status %d)", msg, d.ExitStatus())
+}
+
+func (d DriftExitStatus) ExitStatus() int {
+	if d.HasDrift {
+		return 3
+	}
+	return 2
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+// Exit statuses returned by kapp commands so that scripts
+// could branch on a failure mode. Statuses 2 and 3 are only
+// returned when requested via --diff-exit-status, --apply-exit-status
+// or --exit-status flags.
+const (
+	ExitStatusError          = 1
+	ExitStatusNoChanges      = 2
+	ExitStatusPendingChanges = 3
+	ExitStatusTimeout        = 4
+	ExitStatusApplyError     = 5
+	ExitStatusPreflightError = 6
+	ExitStatusAborted        = 7
+	ExitStatusUsageError     = 64
+)
+
+// ExitStatusForError returns exit status for an error returned by a command
+func ExitStatusForError(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitStatus ExitStatus
+	if errors.As(err, &exitStatus) {
+		return exitStatus.ExitStatus()
+	}
+	return ExitStatusError
+}
+
+// TimeoutExitStatus indicates that resources did not converge in time
+type TimeoutExitStatus struct{ Err error }
+
+// ApplyErrorExitStatus indicates that some changes failed to apply or to converge
+type ApplyErrorExitStatus struct{ Err error }
+
+// PreflightExitStatus indicates that changes were rejected before applying
+// (e.g. by policies or readiness gates)
+type PreflightExitStatus struct{ Err error }
+
+// AbortedExitStatus indicates that user declined confirmation or interrupted applying
+type AbortedExitStatus struct{ Err error }
+
+// UsageExitStatus indicates that command was invoked incorrectly (e.g. unknown flag)
+type UsageExitStatus struct{ Err error }
+
+var (
+	_ ExitStatus = TimeoutExitStatus{}
+	_ ExitStatus = ApplyErrorExitStatus{}
+	_ ExitStatus = PreflightExitStatus{}
+	_ ExitStatus = AbortedExitStatus{}
+	_ ExitStatus = UsageExitStatus{}
+)
+
+func (e TimeoutExitStatus) Error() string   { return e.Err.Error() }
+func (e TimeoutExitStatus) Unwrap() error   { return e.Err }
+func (e TimeoutExitStatus) ExitStatus() int { return ExitStatusTimeout }
+
+func (e ApplyErrorExitStatus) Error() string   { return e.Err.Error() }
+func (e ApplyErrorExitStatus) Unwrap() error   { return e.Err }
+func (e ApplyErrorExitStatus) ExitStatus() int { return ExitStatusApplyError }
+
+func (e PreflightExitStatus) Error() string   { return e.Err.Error() }
+func (e PreflightExitStatus) Unwrap() error   { return e.Err }
+func (e PreflightExitStatus) ExitStatus() int { return ExitStatusPreflightError }
+
+func (e AbortedExitStatus) Error() string   { return e.Err.Error() }
+func (e AbortedExitStatus) Unwrap() error   { return e.Err }
+func (e AbortedExitStatus) ExitStatus() int { return ExitStatusAborted }
+
+func (e UsageExitStatus) Error() string   { return e.Err.Error() }
+func (e UsageExitStatus) Unwrap() error   { return e.Err }
+func (e UsageExitStatus) ExitStatus() int { return ExitStatusUsageError }
+
+// newApplyExitStatus categorizes error returned while applying changes
+func newApplyExitStatus(err error) error {
+	var stoppedErr ctlcap.ApplyStoppedError
+	if errors.As(err, &stoppedErr) {
+		switch stoppedErr.Reason {
+		case ctlcap.ApplyStoppedReasonInterrupted:
+			return AbortedExitStatus{err}
+		case ctlcap.ApplyStoppedReasonDeadline:
+			return TimeoutExitStatus{err}
+		}
+	}
+	var waitTimeoutErr ctlcap.WaitTimeoutError
+	if errors.As(err, &waitTimeoutErr) {
+		return TimeoutExitStatus{err}
+	}
+	return ApplyErrorExitStatus{err}
+}
@@ -0,0 +1,233 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/spf13/cobra"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	kappLabelKeyPrefix          = "kapp.k14s.io/"
+	explainCreateStrategyAnnKey = "kapp.k14s.io/create-strategy"
+	explainUpdateStrategyAnnKey = "kapp.k14s.io/update-strategy"
+)
+
+type ExplainResourceOptions struct {
+	*DeployOptions
+
+	Resource string
+}
+
+func NewExplainResourceOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ExplainResourceOptions {
+	return &ExplainResourceOptions{DeployOptions: NewDeployOptions(ui, depsFactory, logger)}
+}
+
+func NewExplainResourceCmd(o *ExplainResourceOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := NewDeployCmd(o.DeployOptions, flagsFactory)
+	cmd.Use = "explain-resource"
+	cmd.Aliases = nil
+	cmd.Short = "Explain which config rules apply to a resource"
+	cmd.Long = `Explain which config rules apply to a resource.
+
+Resources are prepared the same way as during deploy (without making any changes)
+and for resources matching --resource prints effective change, apply strategy,
+change groups and rules, ownership labels and config rules matching it.`
+	cmd.RunE = func(_ *cobra.Command, _ []string) error { return o.Run() }
+	cmd.Example = `
+  # Explain rules that apply to deployment 'web' in app 'app1'
+  kapp explain-resource -a app1 -f config/ --resource deployment/web`
+
+	cmd.Flags().StringVar(&o.Resource, "resource", "", "Set resource to explain (format: kind/name, e.g. deployment/web)")
+
+	return cmd
+}
+
+func (o *ExplainResourceOptions) Run() error {
+	kind, name, err := o.parseResource()
+	if err != nil {
+		return err
+	}
+
+	origUI := o.ui
+	// Changes are not presented since only explanation is of interest
+	o.ui = newQuietUI(origUI)
+	defer func() { o.ui = origUI }()
+
+	o.DiffFlags.Run = true
+
+	o.explainFunc = func(newResources []ctlres.Resource, conf ctlconf.Conf, changes []*ctlcap.ClusterChange) error {
+		var found bool
+		for _, res := range newResources {
+			if strings.EqualFold(res.Kind(), kind) && res.Name() == name {
+				err := o.explain(origUI, res, conf, changes)
+				if err != nil {
+					return err
+				}
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("Expected to find resource '%s' in provided files", o.Resource)
+		}
+		return nil
+	}
+
+	return o.DeployOptions.Run()
+}
+
+func (o *ExplainResourceOptions) parseResource() (string, string, error) {
+	pieces := strings.Split(o.Resource, "/")
+	if len(pieces) != 2 || len(pieces[0]) == 0 || len(pieces[1]) == 0 {
+		return "", "", fmt.Errorf("Expected --resource to be in format 'kind/name', but was '%s'", o.Resource)
+	}
+	return pieces[0], pieces[1], nil
+}
+
+func (o *ExplainResourceOptions) explain(ui ui.UI, res ctlres.Resource,
+	conf ctlconf.Conf, changes []*ctlcap.ClusterChange) error {
+
+	changeDesc, strategyDesc, err := o.changeAndStrategy(res, changes)
+	if err != nil {
+		return err
+	}
+
+	graphChange := ctldgraph.NewChange(explainedChange{res}, conf.ChangeGroupBindings(), conf.ChangeRuleBindings())
+
+	groups, err := graphChange.Groups()
+	if err != nil {
+		return err
+	}
+
+	var groupNames []string
+	for _, group := range groups {
+		groupNames = append(groupNames, group.Name)
+	}
+	sort.Strings(groupNames)
+
+	rules, err := graphChange.AllRules()
+	if err != nil {
+		return err
+	}
+
+	var ruleStrs []string
+	for _, rule := range rules {
+		ruleStrs = append(ruleStrs, rule.String())
+	}
+	sort.Strings(ruleStrs)
+
+	var labels []string
+	for k, v := range res.Labels() {
+		if strings.HasPrefix(k, kappLabelKeyPrefix) {
+			labels = append(labels, k+"="+v)
+		}
+	}
+	sort.Strings(labels)
+
+	ui.PrintTable(uitable.Table{
+		Title:   fmt.Sprintf("Resource %s", res.Description()),
+		Content: "properties",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Change"),
+			uitable.NewHeader("Apply strategy"),
+			uitable.NewHeader("Change groups"),
+			uitable.NewHeader("Change rules"),
+			uitable.NewHeader("Ownership labels"),
+		},
+
+		Transpose: true,
+
+		Rows: [][]uitable.Value{{
+			cmdcore.NewValueNamespace(res.Namespace()),
+			uitable.NewValueString(res.Name()),
+			uitable.NewValueString(res.Kind()),
+			uitable.NewValueString(changeDesc),
+			uitable.NewValueString(strategyDesc),
+			uitable.NewValueStrings(groupNames),
+			uitable.NewValueStrings(ruleStrs),
+			uitable.NewValueStrings(labels),
+		}},
+	})
+
+	rulesTable := uitable.Table{
+		Title:   fmt.Sprintf("Config rules matching %s", res.Description()),
+		Content: "rules",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Config"),
+			uitable.NewHeader("Rule"),
+			uitable.NewHeader("Details"),
+		},
+	}
+
+	for _, rule := range conf.MatchingRules(res) {
+		rulesTable.Rows = append(rulesTable.Rows, []uitable.Value{
+			uitable.NewValueString(rule.Config),
+			uitable.NewValueString(rule.Rule),
+			uitable.NewValueString(rule.Details),
+		})
+	}
+
+	ui.PrintTable(rulesTable)
+
+	return nil
+}
+
+func (o *ExplainResourceOptions) changeAndStrategy(res ctlres.Resource,
+	changes []*ctlcap.ClusterChange) (string, string, error) {
+
+	resKey := ctlres.NewUniqueResourceKey(res).String()
+
+	for _, change := range changes {
+		if ctlres.NewUniqueResourceKey(change.Resource()).String() != resKey {
+			continue
+		}
+		strategyOp, err := change.ApplyStrategyOp()
+		if err != nil {
+			return "", "", err
+		}
+		strategyDesc := string(strategyOp)
+		if len(strategyDesc) == 0 {
+			strategyDesc = "default"
+		}
+		return fmt.Sprintf("%s (wait: %s)", change.ApplyOp(), change.WaitOp()), strategyDesc, nil
+	}
+
+	// Strategy annotations are only used when resource changes
+	var strategies []string
+	for _, key := range []string{explainCreateStrategyAnnKey, explainUpdateStrategyAnnKey} {
+		if val, found := res.Annotations()[key]; found {
+			strategies = append(strategies, fmt.Sprintf("%s: %s", key, val))
+		}
+	}
+	if len(strategies) == 0 {
+		strategies = []string{"default"}
+	}
+	return "none (resource is up to date)", strings.Join(strategies, ", "), nil
+}
+
+// explainedChange allows to determine change groups and rules of a resource
+type explainedChange struct {
+	res ctlres.Resource
+}
+
+var _ ctldgraph.ActualChange = explainedChange{}
+
+func (c explainedChange) Resource() ctlres.Resource    { return c.res }
+func (c explainedChange) Op() ctldgraph.ActualChangeOp { return ctldgraph.ActualChangeOpUpsert }
@@ -0,0 +1,344 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"archive/tar"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	exportConfigFileName = "kapp-config.yml"
+)
+
+var (
+	// Fields populated by the cluster that should not be carried over
+	exportServerPopulatedPaths = [][]string{
+		{"status"},
+		{"metadata", "uid"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "generation"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "deletionTimestamp"},
+		{"metadata", "deletionGracePeriodSeconds"},
+		{"metadata", "managedFields"},
+		{"metadata", "selfLink"},
+		{"metadata", "ownerReferences"},
+	}
+
+	// Labels and annotations kapp uses for its own bookkeeping
+	// (user provided kapp annotations, e.g. change groups, are kept)
+	exportKappLabelKeys = []string{
+		"kapp.k14s.io/app",
+		"kapp.k14s.io/association",
+	}
+	exportKappAnnKeys = []string{
+		"kapp.k14s.io/original",
+		"kapp.k14s.io/original-diff-md5",
+		"kapp.k14s.io/original-diff",
+		"kapp.k14s.io/original-diff-full",
+		"kapp.k14s.io/identity",
+		"kapp.k14s.io/nonce",
+		"kapp.k14s.io/last-renewed-time",
+	}
+
+	exportFileNameUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+type ExportOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            Flags
+	ResourceFilterFlags cmdtools.ResourceFilterFlags
+	ResourceTypesFlags  ResourceTypesFlags
+
+	OutputDir string
+	OutputTar string
+}
+
+func NewExportOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ExportOptions {
+	return &ExportOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewExportCmd(o *ExportOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export app resources and config so that they could be deployed elsewhere",
+		Long: `Export app resources and config so that they could be deployed elsewhere.
+
+Resources are fetched from the cluster and cleaned of fields populated by the cluster
+(status, metadata such as uid and resourceVersion, fields that rebase rules copy
+from existing resources) and of labels and annotations used for kapp bookkeeping.
+Kapp config that app was last deployed with is included as well.
+
+By default resources are printed as a YAML stream; use --output-dir or --output-tar
+to write one file per resource.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+		},
+		Example: `
+  # Print exported resources
+  kapp export -a app1
+
+  # Export into a directory and deploy exported resources to a different cluster
+  kapp export -a app1 --output-dir ./app1
+  kapp deploy -a app1 -f ./app1 --kubeconfig-context other`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.ResourceFilterFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	cmd.Flags().StringVar(&o.OutputDir, "output-dir", "", "Write exported resources into a directory (one file per resource)")
+	cmd.Flags().StringVar(&o.OutputTar, "output-tar", "", "Write exported resources into a tar file (one file per resource)")
+	return cmd
+}
+
+type exportedFile struct {
+	Name    string
+	Content []byte
+}
+
+func (o *ExportOptions) Run() error {
+	if len(o.OutputDir) > 0 && len(o.OutputTar) > 0 {
+		return fmt.Errorf("Expected only one of --output-dir or --output-tar to be specified")
+	}
+
+	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	usedGVs, err := app.UsedGVs()
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	resources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces})
+	if err != nil {
+		return err
+	}
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return err
+	}
+
+	resources = resourceFilter.Apply(resources)
+
+	inputResources, err := lastChangeInputResources(app)
+	if err != nil {
+		return err
+	}
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(inputResources)
+	if err != nil {
+		return err
+	}
+
+	files, err := o.exportedFiles(resources, inputResources, conf, meta.LabelKey)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(o.OutputDir) > 0:
+		return o.writeDir(files)
+	case len(o.OutputTar) > 0:
+		return o.writeTar(files)
+	default:
+		for _, file := range files {
+			o.ui.PrintBlock(append([]byte("---\n"), file.Content...))
+		}
+		return nil
+	}
+}
+
+func (o *ExportOptions) exportedFiles(resources, inputResources []ctlres.Resource,
+	conf ctlconf.Conf, appLabelKey string) ([]exportedFile, error) {
+	var files []exportedFile
+
+	for _, res := range resources {
+		// Transient resources are created by the cluster (e.g. Pods of ReplicaSet)
+		if res.Transient() {
+			continue
+		}
+
+		res, err := portableResource(res, conf, appLabelKey)
+		if err != nil {
+			return nil, err
+		}
+
+		bs, err := res.AsYAMLBytes()
+		if err != nil {
+			return nil, fmt.Errorf("Serializing resource '%s': %w", res.Description(), err)
+		}
+
+		files = append(files, exportedFile{Name: o.fileName(res), Content: bs})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	var configsYAML []string
+
+	for _, res := range inputResources {
+		if ctlconf.IsConfigResource(res) {
+			bs, err := res.AsYAMLBytes()
+			if err != nil {
+				return nil, fmt.Errorf("Serializing config '%s': %w", res.Description(), err)
+			}
+			configsYAML = append(configsYAML, string(bs))
+		}
+	}
+
+	if len(configsYAML) > 0 {
+		files = append(files, exportedFile{
+			Name:    exportConfigFileName,
+			Content: []byte(strings.Join(configsYAML, "---\n")),
+		})
+	}
+
+	return files, nil
+}
+
+// portableResource returns copy of resource without fields populated by the cluster
+// and kapp bookkeeping so that it could be (re)deployed as is
+func portableResource(res ctlres.Resource, conf ctlconf.Conf, appLabelKey string) (ctlres.Resource, error) {
+	res = res.DeepCopy()
+
+	mods := conf.ExportMods()
+
+	for _, path := range exportServerPopulatedPaths {
+		mods = append(mods, removeFieldMod(path...))
+	}
+	// App may be using custom label key (see deploy --app-label-key)
+	for _, key := range append([]string{appLabelKey}, exportKappLabelKeys...) {
+		mods = append(mods, removeFieldMod("metadata", "labels", key))
+	}
+	for _, key := range exportKappAnnKeys {
+		mods = append(mods, removeFieldMod("metadata", "annotations", key))
+	}
+
+	for _, mod := range mods {
+		if mod.IsResourceMatching(res) {
+			err := mod.Apply(res)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Avoid leaving behind empty maps after removing kapp keys
+	emptyPaths := map[string]bool{"labels": len(res.Labels()) == 0, "annotations": len(res.Annotations()) == 0}
+	for _, key := range []string{"labels", "annotations"} {
+		if emptyPaths[key] {
+			err := removeFieldMod("metadata", key).Apply(res)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func removeFieldMod(path ...string) ctlres.FieldRemoveMod {
+	return ctlres.FieldRemoveMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings(path),
+	}
+}
+
+func (o *ExportOptions) fileName(res ctlres.Resource) string {
+	var pieces []string
+	for _, piece := range []string{res.Kind(), res.Namespace(), res.Name()} {
+		if len(piece) > 0 {
+			pieces = append(pieces, strings.ToLower(exportFileNameUnsafeChars.ReplaceAllString(piece, "-")))
+		}
+	}
+	return strings.Join(pieces, "_") + ".yml"
+}
+
+func (o *ExportOptions) writeDir(files []exportedFile) error {
+	err := os.MkdirAll(o.OutputDir, 0700)
+	if err != nil {
+		return fmt.Errorf("Creating output directory: %w", err)
+	}
+
+	for _, file := range files {
+		err := os.WriteFile(filepath.Join(o.OutputDir, file.Name), file.Content, 0600)
+		if err != nil {
+			return fmt.Errorf("Writing file '%s': %w", file.Name, err)
+		}
+	}
+
+	o.ui.PrintLinef("Exported %d files into '%s'", len(files), o.OutputDir)
+	return nil
+}
+
+func (o *ExportOptions) writeTar(files []exportedFile) error {
+	tarFile, err := os.OpenFile(o.OutputTar, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("Creating output tar: %w", err)
+	}
+	defer tarFile.Close()
+
+	tw := tar.NewWriter(tarFile)
+	modTime := time.Now()
+
+	for _, file := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    file.Name,
+			Mode:    0600,
+			Size:    int64(len(file.Content)),
+			ModTime: modTime,
+		})
+		if err != nil {
+			return fmt.Errorf("Writing tar header for '%s': %w", file.Name, err)
+		}
+
+		_, err = tw.Write(file.Content)
+		if err != nil {
+			return fmt.Errorf("Writing tar contents for '%s': %w", file.Name, err)
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return fmt.Errorf("Closing output tar: %w", err)
+	}
+
+	o.ui.PrintLinef("Exported %d files into '%s'", len(files), o.OutputTar)
+	return nil
+}
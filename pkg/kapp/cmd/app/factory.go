@@ -4,11 +4,14 @@
 package app
 
 import (
+	"fmt"
+
 	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 type FactorySupportObjs struct {
@@ -16,6 +19,9 @@ type FactorySupportObjs struct {
 	ResourceTypes       *ctlres.ResourceTypesImpl
 	IdentifiedResources ctlres.IdentifiedResources
 	Apps                ctlapp.Apps
+
+	// Used to build resources served differently (e.g. from watches)
+	Resources *ctlres.ResourcesImpl
 }
 
 func FactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFlags, appNamespace string,
@@ -43,11 +49,13 @@ func FactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFl
 	resTypes := ctlres.NewResourceTypesImpl(coreClient, ctlres.ResourceTypesImplOpts{
 		IgnoreFailingAPIServices:   resTypesFlags.IgnoreFailingAPIServices,
 		CanIgnoreFailingAPIService: resTypesFlags.CanIgnoreFailingAPIService,
+		DiscoveryCache:             resTypesFlags.DiscoveryCache(),
 	})
 
 	resourcesImplOpts := ctlres.ResourcesImplOpts{
 		FallbackAllowedNamespaces:        []string{nsFlags.Name},
 		ScopeToFallbackAllowedNamespaces: resTypesFlags.ScopeToFallbackAllowedNamespaces,
+		ListConcurrency:                  resTypesFlags.ListConcurrency,
 	}
 
 	resources := ctlres.NewResourcesImpl(
@@ -56,11 +64,24 @@ func FactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFl
 	identifiedResources := ctlres.NewIdentifiedResources(
 		coreClient, resTypes, resources, resourcesImplOpts.FallbackAllowedNamespaces, logger)
 
+	appsCoreClient := coreClient
+
+	switch depsFactory.AppMetadataStorage() {
+	case "", ctlapp.AppMetadataStorageConfigMap:
+		// use ConfigMaps
+	case ctlapp.AppMetadataStorageCRD:
+		appsCoreClient = ctlapp.NewCRDMetadataClient(coreClient, mutedDynamicClient)
+	default:
+		return FactorySupportObjs{}, fmt.Errorf("Unknown app metadata storage '%s' (supported: %s, %s)",
+			depsFactory.AppMetadataStorage(), ctlapp.AppMetadataStorageConfigMap, ctlapp.AppMetadataStorageCRD)
+	}
+
 	result := FactorySupportObjs{
 		CoreClient:          coreClient,
 		ResourceTypes:       resTypes,
 		IdentifiedResources: identifiedResources,
-		Apps:                ctlapp.NewApps(appNamespace, coreClient, identifiedResources, logger),
+		Apps:                ctlapp.NewApps(appNamespace, appsCoreClient, identifiedResources, logger),
+		Resources:           resources,
 	}
 
 	return result, nil
@@ -79,5 +100,43 @@ func Factory(depsFactory cmdcore.DepsFactory, appFlags Flags,
 		return nil, FactorySupportObjs{}, err
 	}
 
+	impersonation, err := recordedImpersonation(depsFactory, app)
+	if err != nil {
+		return nil, FactorySupportObjs{}, err
+	}
+
+	if impersonation != nil {
+		logger.NewPrefixed("Factory").Debug("Impersonating '%s' recorded with app", impersonation.User)
+
+		// Rebuild clients so that all requests (including app metadata updates) use recorded identity.
+		// Shared factory is not modified since other apps (e.g. within app group deployed
+		// in parallel) may have different identities recorded.
+		impersonatingFactory := depsFactory.WithImpersonation(
+			rest.ImpersonationConfig{UserName: impersonation.User, Groups: impersonation.Groups})
+
+		return Factory(impersonatingFactory, appFlags, resTypesFlags, logger)
+	}
+
 	return app, supportingObjs, nil
 }
+
+// recordedImpersonation returns identity that was recorded with the app
+// unless impersonation was explicitly configured
+func recordedImpersonation(depsFactory cmdcore.DepsFactory, app ctlapp.App) (*ctlapp.MetaImpersonation, error) {
+	configured := depsFactory.Impersonation()
+	if len(configured.UserName) > 0 || len(configured.Groups) > 0 {
+		return nil, nil
+	}
+
+	exists, _, err := app.Exists()
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return nil, err
+	}
+
+	return meta.Impersonation, nil
+}
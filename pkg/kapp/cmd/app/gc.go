@@ -0,0 +1,176 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+)
+
+type GCOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	DiffFlags      cmdtools.DiffFlags
+	ApplyFlags     ApplyFlags
+	LockFlags      LockFlags
+	AllNamespaces  bool
+}
+
+func NewGCOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *GCOptions {
+	return &GCOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewGCCmd(o *GCOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "garbage-collect",
+		Aliases: []string{"gc"},
+		Short:   "Delete apps whose TTL expired",
+		Long: `Delete apps whose TTL expired.
+
+Apps deployed with --ttl are deleted (together with their resources) once TTL
+passes since their last deploy. Protected apps (deployed with --protect) are skipped.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+			TTYByDefaultKey:          "",
+		},
+		Example: `
+  # Deploy preview environment that expires in 3 days
+  kapp deploy -a preview-123 -f config/ --ttl 72h
+
+  # Delete expired apps in all namespaces
+  kapp gc -A`,
+	}
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	o.DiffFlags.SetWithPrefix("diff", cmd)
+	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeleteDefaults, cmd)
+	o.LockFlags.Set(cmd)
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", false, "Delete expired apps in all namespaces")
+	return cmd
+}
+
+func (o *GCOptions) Run() error {
+	nsFlags := o.NamespaceFlags
+	if o.AllNamespaces {
+		nsFlags.Name = ""
+	}
+
+	supportObjs, err := FactoryClients(o.depsFactory, nsFlags, "", ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	apps, err := supportObjs.Apps.List(nil)
+	if err != nil {
+		return err
+	}
+
+	expiredApps, err := o.expiredApps(apps, time.Now())
+	if err != nil {
+		return err
+	}
+
+	o.printTable(expiredApps)
+
+	if len(expiredApps) == 0 {
+		return nil
+	}
+
+	var failedApps []string
+
+	for _, app := range expiredApps {
+		err := o.deleteApp(app)
+		if err != nil {
+			o.ui.ErrorLinef("--- failed deleting app '%s' (namespace: %s): %s", app.Name(), app.Namespace(), err)
+			failedApps = append(failedApps, fmt.Sprintf("- %s (namespace: %s): %s", app.Name(), app.Namespace(), err))
+		}
+	}
+
+	if len(failedApps) > 0 {
+		return fmt.Errorf("Deleting expired apps:\n%s", strings.Join(failedApps, "\n"))
+	}
+
+	return nil
+}
+
+func (o *GCOptions) expiredApps(apps []ctlapp.App, now time.Time) ([]ctlapp.App, error) {
+	var result []ctlapp.App
+
+	for _, app := range apps {
+		meta, err := app.Meta()
+		if err != nil {
+			return nil, err
+		}
+
+		if !meta.IsExpired(now) {
+			continue
+		}
+
+		if meta.Protected {
+			o.ui.PrintLinef("Skipping expired app '%s' (namespace: %s) since it is protected", app.Name(), app.Namespace())
+			continue
+		}
+
+		result = append(result, app)
+	}
+
+	return result, nil
+}
+
+func (o *GCOptions) deleteApp(app ctlapp.App) error {
+	o.ui.PrintLinef("--- deleting app '%s' (namespace: %s)", app.Name(), app.Namespace())
+
+	deleteOpts := NewDeleteOptions(o.ui, o.depsFactory, o.logger)
+	deleteOpts.AppFlags = Flags{
+		Name:           app.Name(),
+		NamespaceFlags: cmdcore.NamespaceFlags{Name: app.Namespace()},
+	}
+	deleteOpts.DiffFlags = o.DiffFlags
+	deleteOpts.ApplyFlags = o.ApplyFlags
+	deleteOpts.LockFlags = o.LockFlags
+
+	return deleteOpts.Run()
+}
+
+func (o *GCOptions) printTable(apps []ctlapp.App) {
+	table := uitable.Table{
+		Title:   "Expired apps",
+		Content: "apps",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Expired At"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+		},
+	}
+
+	for _, app := range apps {
+		meta, _ := app.Meta()
+
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(app.Namespace()),
+			uitable.NewValueString(app.Name()),
+			uitable.NewValueTime(*meta.ExpiresAt),
+		})
+	}
+
+	o.ui.PrintTable(table)
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	ctlimg "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/images"
+)
+
+type ImagesFlags struct {
+	LockFile    string
+	ResolverCmd string
+	LockOutput  string
+}
+
+func (s *ImagesFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&s.LockFile, "images-lock", "",
+		"Set images lock file (kbld/imgpkg ImagesLock) used to replace image references with digests")
+	cmd.Flags().StringVar(&s.ResolverCmd, "images-resolver-cmd", "",
+		"Set command that resolves images to digests; receives resources via stdin (e.g. 'kbld -f -')")
+	cmd.Flags().StringVar(&s.LockOutput, "kbld-lock-output", "",
+		"Set file path to write images lock of deployed images to")
+}
+
+func (s ImagesFlags) Resolver() (ctlimg.Resolver, error) {
+	opts := ctlimg.ResolverOpts{Command: strings.Fields(s.ResolverCmd)}
+
+	if len(s.LockFile) > 0 {
+		lock, err := ctlimg.NewLockFromFile(s.LockFile)
+		if err != nil {
+			return ctlimg.Resolver{}, err
+		}
+		opts.Lock = &lock
+	}
+
+	return ctlimg.NewResolver(opts), nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/version"
+)
+
+const (
+	// Digests are truncated since they are stored in app metadata
+	incrementalDigestLen = 16
+	// Keeps app metadata well within ConfigMap size limit (~400KiB of digests);
+	// resources over the limit are always diffed
+	incrementalMaxDigests = 20000
+)
+
+// incrementalDeploy skips diffing of resources that were unchanged (and converged)
+// as of last successful deploy. Resource is skipped when its digest (based on
+// its content, its cluster resourceVersion and kapp config) was recorded;
+// any change to a resource on the cluster (including its status) changes its
+// resourceVersion, hence such resources are diffed as usual.
+type incrementalDeploy struct {
+	contextDigest   string
+	recordedDigests map[string]struct{}
+
+	contentDigests map[string]string
+	skippedDigests []string
+}
+
+func newIncrementalDeploy(recordedDigests []string, conf ctlconf.Conf, againstLastApplied bool) (*incrementalDeploy, error) {
+	confBs, err := json.Marshal(conf.Configs())
+	if err != nil {
+		return nil, fmt.Errorf("Serializing kapp config: %w", err)
+	}
+
+	recorded := map[string]struct{}{}
+	for _, digest := range recordedDigests {
+		recorded[digest] = struct{}{}
+	}
+
+	return &incrementalDeploy{
+		// Diffing behaviour may change between kapp versions
+		contextDigest:   digestOf([]byte(version.Version), confBs, []byte(strconv.FormatBool(againstLastApplied))),
+		recordedDigests: recorded,
+		contentDigests:  map[string]string{},
+	}, nil
+}
+
+// Skip returns existing and new resources that need to be diffed
+// (ones that were not unchanged since last deploy)
+func (d *incrementalDeploy) Skip(existingRs, newRs []ctlres.Resource) ([]ctlres.Resource, []ctlres.Resource, error) {
+	existingRsByKey := map[string]ctlres.Resource{}
+	for _, res := range existingRs {
+		existingRsByKey[ctlres.NewUniqueResourceKey(res).String()] = res
+	}
+
+	skippedKeys := map[string]struct{}{}
+	var resultNewRs []ctlres.Resource
+
+	for _, res := range newRs {
+		key := ctlres.NewUniqueResourceKey(res).String()
+
+		bs, err := res.AsCompactBytes()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Serializing resource '%s': %w", res.Description(), err)
+		}
+
+		d.contentDigests[key] = digestOf(bs)
+
+		if existingRes, found := existingRsByKey[key]; found {
+			digest := d.digest(key, existingRes.ResourceVersion())
+			if _, found := d.recordedDigests[digest]; found {
+				skippedKeys[key] = struct{}{}
+				d.skippedDigests = append(d.skippedDigests, digest)
+				continue
+			}
+		}
+
+		resultNewRs = append(resultNewRs, res)
+	}
+
+	var resultExistingRs []ctlres.Resource
+
+	for _, res := range existingRs {
+		if _, found := skippedKeys[ctlres.NewUniqueResourceKey(res).String()]; !found {
+			resultExistingRs = append(resultExistingRs, res)
+		}
+	}
+
+	return resultExistingRs, resultNewRs, nil
+}
+
+func (d *incrementalDeploy) NumSkipped() int { return len(d.skippedDigests) }
+
+// Digests returns digests of resources that are unchanged after successful deploy.
+// Resources that were applied are only recorded once they are found
+// unchanged by a subsequent deploy since their resourceVersion is not known.
+func (d *incrementalDeploy) Digests(clusterChanges []*ctlcap.ClusterChange) []string {
+	result := append([]string{}, d.skippedDigests...)
+
+	for _, change := range clusterChanges {
+		existingRes := change.ClusterOriginalResource()
+		if existingRes == nil || change.ApplyOp() != ctlcap.ClusterChangeApplyOpNoop || change.WaitOp() != ctlcap.ClusterChangeWaitOpNoop {
+			continue
+		}
+
+		key := ctlres.NewUniqueResourceKey(change.Resource()).String()
+		if _, found := d.contentDigests[key]; found {
+			result = append(result, d.digest(key, existingRes.ResourceVersion()))
+		}
+	}
+
+	sort.Strings(result)
+
+	if len(result) > incrementalMaxDigests {
+		result = result[:incrementalMaxDigests]
+	}
+
+	return result
+}
+
+// Changed indicates whether digests need to be recorded
+func (d *incrementalDeploy) Changed(digests []string) bool {
+	if len(digests) != len(d.recordedDigests) {
+		return true
+	}
+	for _, digest := range digests {
+		if _, found := d.recordedDigests[digest]; !found {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *incrementalDeploy) digest(key, resourceVersion string) string {
+	return digestOf([]byte(d.contextDigest), []byte(key), []byte(d.contentDigests[key]), []byte(resourceVersion))
+}
+
+func digestOf(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write(part)
+		// Separates parts so that they cannot be shifted between each other
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:incrementalDigestLen]
+}
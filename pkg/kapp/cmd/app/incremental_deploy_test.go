@@ -0,0 +1,90 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestIncrementalDeploySkipsUnchangedResources(t *testing.T) {
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err)
+
+	newRs := []ctlres.Resource{incrementalConfigMap("cm1", "", "val1"), incrementalConfigMap("cm2", "", "val1")}
+	existingRs := []ctlres.Resource{incrementalConfigMap("cm1", "10", "val1"), incrementalConfigMap("cm2", "11", "val1")}
+
+	// Nothing is skipped without recorded digests
+	incremental, err := newIncrementalDeploy(nil, conf, true)
+	require.NoError(t, err)
+
+	diffExistingRs, diffNewRs, err := incremental.Skip(existingRs, newRs)
+	require.NoError(t, err)
+	require.Len(t, diffExistingRs, 2)
+	require.Len(t, diffNewRs, 2)
+	require.Equal(t, 0, incremental.NumSkipped())
+
+	recorded := []string{incremental.digest(ctlres.NewUniqueResourceKey(newRs[0]).String(), "10")}
+
+	incremental, err = newIncrementalDeploy(recorded, conf, true)
+	require.NoError(t, err)
+
+	diffExistingRs, diffNewRs, err = incremental.Skip(existingRs, newRs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cm2"}, incrementalNames(diffExistingRs))
+	require.Equal(t, []string{"cm2"}, incrementalNames(diffNewRs))
+	require.Equal(t, 1, incremental.NumSkipped())
+	require.False(t, incremental.Changed(incremental.Digests(nil)))
+
+	// Changed content or cluster resource version require diffing
+	for _, rs := range [][]ctlres.Resource{
+		{incrementalConfigMap("cm1", "", "val2"), incrementalConfigMap("cm1", "10", "val1")},
+		{incrementalConfigMap("cm1", "", "val1"), incrementalConfigMap("cm1", "12", "val1")},
+	} {
+		incremental, err = newIncrementalDeploy(recorded, conf, true)
+		require.NoError(t, err)
+
+		_, diffNewRs, err = incremental.Skip(rs[1:], rs[:1])
+		require.NoError(t, err)
+		require.Len(t, diffNewRs, 1)
+		require.True(t, incremental.Changed(incremental.Digests(nil)))
+	}
+
+	// Recorded digests do not apply once diffing behaviour changes
+	incremental, err = newIncrementalDeploy(recorded, conf, false)
+	require.NoError(t, err)
+
+	_, diffNewRs, err = incremental.Skip(existingRs, newRs)
+	require.NoError(t, err)
+	require.Len(t, diffNewRs, 2)
+}
+
+func incrementalConfigMap(name, resourceVersion, val string) ctlres.Resource {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + `
+  namespace: default
+data:
+  key: ` + val + `
+`))
+	if len(resourceVersion) > 0 {
+		// Cluster assigned fields are not part of new resources
+		obj := res.UnstructuredObject()
+		obj["metadata"].(map[string]interface{})["resourceVersion"] = resourceVersion
+	}
+	return res
+}
+
+func incrementalNames(rs []ctlres.Resource) []string {
+	var result []string
+	for _, res := range rs {
+		result = append(result, res.Name())
+	}
+	return result
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type HelmFlags struct {
+	ValuesFiles []string
+	Repo        string
+	Version     string
+	ReleaseName string
+	Binary      string
+}
+
+func (s *HelmFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&s.ValuesFiles, "helm-values", nil,
+		"Set values file for Helm charts (specified via --file) (can repeat)")
+	cmd.Flags().StringVar(&s.Repo, "helm-repo", "",
+		"Set Helm chart repository URL; --file values are then treated as chart names")
+	cmd.Flags().StringVar(&s.Version, "helm-version", "", "Set Helm chart version (used with --helm-repo)")
+	cmd.Flags().StringVar(&s.ReleaseName, "helm-release-name", "", "Set Helm release name (defaults to app name)")
+	cmd.Flags().StringVar(&s.Binary, "helm-binary", "helm", "Set path to helm binary used to render charts")
+}
+
+func (s HelmFlags) IsUsed() bool {
+	return len(s.ValuesFiles) > 0 || len(s.Repo) > 0 || len(s.Version) > 0
+}
+
+type KustomizeFlags struct {
+	Binary string
+}
+
+func (s *KustomizeFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&s.Binary, "kustomize-binary", "kustomize",
+		"Set path to kustomize binary used to build directories with kustomization.yaml (kustomize is not built in)")
+}
+
+type RegistryFlags struct {
+	PlainHTTP bool
+}
+
+func (s *RegistryFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.PlainHTTP, "registry-plain-http", false,
+		"Connect to registry over plain HTTP when pulling oci:// files (e.g. local registries)")
+}
+
+type SopsFlags struct {
+	Enabled bool
+	Binary  string
+}
+
+func (s *SopsFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.Enabled, "sops", true, "Decrypt SOPS encrypted --file (-f) files before deploying")
+	cmd.Flags().StringVar(&s.Binary, "sops-binary", "sops", "Set path to sops binary used to decrypt files (sops is not built in)")
+}
+
+type HTTPFlags struct {
+	Headers         []string
+	BearerTokenFile string
+	CACertFile      string
+	ClientCertFile  string
+	ClientKeyFile   string
+	Retries         int
+}
+
+func (s *HTTPFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&s.Headers, "http-header", nil,
+		"Set header for http(s):// files (format: 'Name: value') (can repeat)")
+	cmd.Flags().StringVar(&s.BearerTokenFile, "http-bearer-token-file", "",
+		"Set path to file with bearer token used for http(s):// files")
+	cmd.Flags().StringVar(&s.CACertFile, "http-ca-cert", "", "Set path to CA certificate used to verify http(s):// files server")
+	cmd.Flags().StringVar(&s.ClientCertFile, "http-client-cert", "", "Set path to client certificate used for http(s):// files")
+	cmd.Flags().StringVar(&s.ClientKeyFile, "http-client-key", "", "Set path to client key used for http(s):// files")
+	cmd.Flags().IntVar(&s.Retries, "http-retries", 3, "Set number of retries for failed http(s):// file requests")
+}
+
+func (s HTTPFlags) FileResourcesOpts() (ctlres.FileResourcesOpts, error) {
+	opts := ctlres.HTTPFileSourceOpts{
+		Headers:      map[string]string{},
+		Retries:      s.Retries,
+		RetryBackoff: time.Second,
+	}
+
+	for _, header := range s.Headers {
+		pieces := strings.SplitN(header, ":", 2)
+		if len(pieces) != 2 || len(strings.TrimSpace(pieces[0])) == 0 {
+			return ctlres.FileResourcesOpts{}, fmt.Errorf("Expected --http-header '%s' to be in format 'Name: value'", header)
+		}
+		opts.Headers[strings.TrimSpace(pieces[0])] = strings.TrimSpace(pieces[1])
+	}
+
+	if len(s.BearerTokenFile) > 0 {
+		bs, err := os.ReadFile(s.BearerTokenFile)
+		if err != nil {
+			return ctlres.FileResourcesOpts{}, fmt.Errorf("Reading --http-bearer-token-file: %w", err)
+		}
+		opts.BearerToken = strings.TrimSpace(string(bs))
+	}
+
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return ctlres.FileResourcesOpts{}, err
+	}
+	opts.TLSConfig = tlsConfig
+
+	return ctlres.FileResourcesOpts{HTTP: opts}, nil
+}
+
+func (s HTTPFlags) tlsConfig() (*tls.Config, error) {
+	if len(s.CACertFile) == 0 && len(s.ClientCertFile) == 0 && len(s.ClientKeyFile) == 0 {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(s.CACertFile) > 0 {
+		bs, err := os.ReadFile(s.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Reading --http-ca-cert: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(bs) {
+			return nil, fmt.Errorf("Expected --http-ca-cert to contain PEM encoded certificates")
+		}
+	}
+
+	if len(s.ClientCertFile) > 0 || len(s.ClientKeyFile) > 0 {
+		if len(s.ClientCertFile) == 0 || len(s.ClientKeyFile) == 0 {
+			return nil, fmt.Errorf("Expected both --http-client-cert and --http-client-key to be specified")
+		}
+		cert, err := tls.LoadX509KeyPair(s.ClientCertFile, s.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Loading HTTP client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/gitinput"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/helminput"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/kustomizeinput"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/ociinput"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/yttinput"
+)
+
+const (
+	changeMetadataGitSourceKeyPrefix = "git-sha:"
+	changeMetadataSignatureKeyPrefix = "signature:"
+)
+
+// renderedInputs tracks which kinds of inputs were rendered
+// to make sure that flags specific to them were not ignored
+type renderedInputs struct {
+	YttTemplates bool
+	HelmCharts   bool
+
+	// GitSHAs holds resolved commits keyed by git source
+	GitSHAs map[string]string
+	// Signatures holds verification results keyed by input
+	Signatures map[string]string
+}
+
+func newRenderedInputs() renderedInputs {
+	return renderedInputs{GitSHAs: map[string]string{}, Signatures: map[string]string{}}
+}
+
+// ChangeMetadata records resolved commits of git sources
+// and signature verification results with app change
+func (r renderedInputs) ChangeMetadata() map[string]string {
+	result := map[string]string{}
+	for desc, sha := range r.GitSHAs {
+		result[changeMetadataGitSourceKeyPrefix+desc] = sha
+	}
+	for desc, verification := range r.Signatures {
+		result[changeMetadataSignatureKeyPrefix+desc] = verification
+	}
+	return result
+}
+
+func (r renderedInputs) Check(yttFlags YttFlags, helmFlags HelmFlags) error {
+	if yttFlags.HasDataValues() && !r.YttTemplates {
+		return fmt.Errorf("Expected at least one --file (-f) directory with ytt templates " +
+			"since ytt data values were specified")
+	}
+	if helmFlags.IsUsed() && !r.HelmCharts {
+		return fmt.Errorf("Expected at least one --file (-f) Helm chart since Helm flags were specified")
+	}
+	return nil
+}
+
+// renderedResources renders file if it is an input that needs rendering
+// (e.g. git repository, OCI artifact, Helm chart, kustomization or directory with ytt templates);
+// returns false if file should be read as plain resources
+func (o *DeployOptions) renderedResources(file string, rendered *renderedInputs) ([]ctlres.Resource, bool, error) {
+	if gitinput.IsURL(file) {
+		src, err := gitinput.ParseURL(file)
+		if err != nil {
+			return nil, false, err
+		}
+		gitOpts, err := o.SignatureFlags.GitResourcesOpts()
+		if err != nil {
+			return nil, false, err
+		}
+		resources, sha, err := src.ResourcesWithOpts(gitOpts)
+		if err != nil {
+			return nil, false, err
+		}
+		rendered.GitSHAs[src.Description()] = sha
+		if o.SignatureFlags.Verify {
+			rendered.Signatures[src.Description()] = fmt.Sprintf("verified ssh signature of tag '%s'", src.Ref)
+		}
+		return resources, true, nil
+	}
+
+	if ociinput.IsRef(file) {
+		ref, err := ociinput.ParseRef(file)
+		if err != nil {
+			return nil, false, err
+		}
+		if o.SignatureFlags.Verify {
+			verifier, err := o.SignatureFlags.CosignVerifier(o.RegistryFlags)
+			if err != nil {
+				return nil, false, err
+			}
+			desc := ref.String()
+			// Fetch exactly what was verified
+			ref.Digest, err = verifier.Verify(ref)
+			if err != nil {
+				return nil, false, err
+			}
+			rendered.Signatures[desc] = fmt.Sprintf("verified cosign signature of digest '%s'", ref.Digest)
+		}
+		resources, err := ociinput.NewArtifact(ref, ociinput.ArtifactOpts{
+			PlainHTTP: o.RegistryFlags.PlainHTTP,
+		}).Resources()
+		if err != nil {
+			return nil, false, err
+		}
+		return resources, true, nil
+	}
+
+	if o.SignatureFlags.Verify {
+		return nil, false, fmt.Errorf("Expected --file (-f) '%s' to be an OCI artifact (oci://) "+
+			"or git tag (git+) since --verify-signature was specified", file)
+	}
+
+	if ctlres.IsStreamFile(file) || strings.HasPrefix(file, "http://") || strings.HasPrefix(file, "https://") {
+		return nil, false, nil
+	}
+
+	// Remote charts are referenced by name
+	if len(o.HelmFlags.Repo) > 0 {
+		resources, err := o.helmResources(file)
+		if err != nil {
+			return nil, false, err
+		}
+		rendered.HelmCharts = true
+		return resources, true, nil
+	}
+
+	isDir, err := o.isDir(file)
+	if err != nil || !isDir {
+		return nil, false, err
+	}
+
+	if helminput.IsChartDir(o.FileSystem, file) {
+		resources, err := o.helmResources(file)
+		if err != nil {
+			return nil, false, err
+		}
+		rendered.HelmCharts = true
+		return resources, true, nil
+	}
+
+	if kustomizeinput.IsKustomizationDir(o.FileSystem, file) {
+		resources, err := kustomizeinput.NewDir(file, o.KustomizeFlags.Binary).Resources()
+		if err != nil {
+			return nil, false, err
+		}
+		return resources, true, nil
+	}
+
+	if o.YttFlags.Enabled {
+		tpl := yttinput.NewDirTemplate(o.FileSystem, file)
+
+		isTemplate, err := tpl.IsTemplate()
+		if err != nil || !isTemplate {
+			return nil, false, err
+		}
+
+		resources, err := tpl.Resources(o.YttFlags.DataValues)
+		if err != nil {
+			return nil, false, err
+		}
+		rendered.YttTemplates = true
+		return resources, true, nil
+	}
+
+	return nil, false, nil
+}
+
+func (o *DeployOptions) helmResources(chart string) ([]ctlres.Resource, error) {
+	releaseName := o.HelmFlags.ReleaseName
+	if len(releaseName) == 0 {
+		releaseName = o.AppFlags.Name
+	}
+
+	namespace := o.DeployFlags.IntoNamespace
+	if len(namespace) == 0 {
+		namespace = o.AppFlags.NamespaceFlags.Name
+	}
+
+	return helminput.NewChart(chart, helminput.ChartOpts{
+		Binary:      o.HelmFlags.Binary,
+		ReleaseName: releaseName,
+		Namespace:   namespace,
+		ValuesFiles: o.HelmFlags.ValuesFiles,
+		Repo:        o.HelmFlags.Repo,
+		Version:     o.HelmFlags.Version,
+	}).Resources()
+}
+
+func (o *DeployOptions) isDir(file string) (bool, error) {
+	var fileInfo fs.FileInfo
+	var err error
+
+	if o.FileSystem != nil {
+		fileInfo, err = fs.Stat(o.FileSystem, file)
+	} else {
+		fileInfo, err = os.Stat(file)
+	}
+	if err != nil {
+		return false, err
+	}
+	return fileInfo.IsDir(), nil
+}
@@ -5,6 +5,7 @@ package app
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
@@ -24,10 +25,13 @@ type InspectOptions struct {
 	AppFlags            Flags
 	ResourceFilterFlags cmdtools.ResourceFilterFlags
 	ResourceTypesFlags  ResourceTypesFlags
+	OutputFlags         OutputFlags
+	TableFlags          cmdcore.TableFlags
 
 	Raw           bool
 	Status        bool
 	Tree          bool
+	IncludeOwned  bool
 	ManagedFields bool
 }
 
@@ -48,14 +52,27 @@ func NewInspectCmd(o *InspectOptions, flagsFactory cmdcore.FlagsFactory) *cobra.
 	o.AppFlags.Set(cmd, flagsFactory)
 	o.ResourceFilterFlags.Set(cmd)
 	o.ResourceTypesFlags.Set(cmd)
+	o.OutputFlags.Set(cmd)
+	o.TableFlags.Set(cmd)
 	cmd.Flags().BoolVar(&o.Raw, "raw", false, "Output raw YAML resource content")
 	cmd.Flags().BoolVar(&o.Status, "status", false, "Output status content")
 	cmd.Flags().BoolVarP(&o.Tree, "tree", "t", false, "Tree view")
+	cmd.Flags().BoolVar(&o.IncludeOwned, "include-owned", false,
+		"Include resources owned via ownerReferences by app resources even if they are not labeled (e.g. created by custom resource controllers)")
 	cmd.Flags().BoolVar(&o.ManagedFields, "managed-fields", false, "Keep the metadata.managedFields when printing objects")
 	return cmd
 }
 
 func (o *InspectOptions) Run() error {
+	err := o.OutputFlags.Validate()
+	if err != nil {
+		return err
+	}
+
+	if o.OutputFlags.IsStructured() && (o.Raw || o.Status || o.Tree) {
+		return fmt.Errorf("Expected --output to not be used together with --raw, --status or --tree")
+	}
+
 	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
 
 	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
@@ -80,12 +97,21 @@ func (o *InspectOptions) Run() error {
 		return err
 	}
 
-	resources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, resources.IdentifiedResourcesListOpts{
-		ResourceNamespaces: meta.LastChange.Namespaces})
+	listOpts := resources.IdentifiedResourcesListOpts{ResourceNamespaces: meta.LastChange.Namespaces}
+
+	resources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, listOpts)
 	if err != nil {
 		return err
 	}
 
+	if o.IncludeOwned {
+		ownedResources, err := supportObjs.IdentifiedResources.ListOwned(resources, listOpts)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, ownedResources...)
+	}
+
 	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
 	if err != nil {
 		return err
@@ -95,6 +121,9 @@ func (o *InspectOptions) Run() error {
 	source := fmt.Sprintf("app '%s'", app.Name())
 
 	switch {
+	case o.OutputFlags.IsStructured():
+		return o.printStructured(resources)
+
 	case o.Raw:
 		for _, res := range resources {
 			historylessRes, err := ctldiff.NewResourceWithoutHistory(res, nil).Resource()
@@ -121,9 +150,40 @@ func (o *InspectOptions) Run() error {
 		if o.Tree {
 			cmdtools.InspectTreeView{Source: source, Resources: resources, Sort: true}.Print(o.ui)
 		} else {
-			cmdtools.InspectView{Source: source, Resources: resources, Sort: true}.Print(o.ui)
+			table := cmdtools.InspectView{Source: source, Resources: resources, Sort: true}.Table()
+
+			err := o.TableFlags.Apply(&table)
+			if err != nil {
+				return err
+			}
+
+			o.ui.PrintTable(table)
 		}
 	}
 
 	return nil
 }
+
+func (o *InspectOptions) printStructured(resources []ctlres.Resource) error {
+	result := []inspectedResource{}
+
+	for _, res := range resources {
+		result = append(result, newInspectedResource(res))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].resourceRef, result[j].resourceRef
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.APIVersion < b.APIVersion
+	})
+
+	return o.OutputFlags.Print(o.ui, result)
+}
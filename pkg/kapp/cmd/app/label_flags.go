@@ -12,21 +12,44 @@ import (
 
 type LabelFlags struct {
 	Labels []string
+
+	AppLabels          []string
+	AppAnnotations     []string
+	PropagateAppLabels []string
 }
 
 func (s *LabelFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().StringSliceVar(&s.Labels, "labels", nil, "Set app label (format: key=val, key=) (can repeat)")
+
+	cmd.Flags().StringSliceVar(&s.AppLabels, "app-label", nil,
+		"Set app label that can be changed in subsequent deploys (format: key=val, key=) (can repeat)")
+	cmd.Flags().StringSliceVar(&s.AppAnnotations, "app-annotation", nil,
+		"Set app annotation (format: key=val, key=) (can repeat)")
+	cmd.Flags().StringSliceVar(&s.PropagateAppLabels, "propagate-app-label", nil,
+		"Add app label with given key to all app resources (can repeat)")
 }
 
 func (s *LabelFlags) AsMap() (map[string]string, error) {
+	return s.kvsAsMap(s.Labels, "label")
+}
+
+func (s *LabelFlags) AppLabelsAsMap() (map[string]string, error) {
+	return s.kvsAsMap(s.AppLabels, "app label")
+}
+
+func (s *LabelFlags) AppAnnotationsAsMap() (map[string]string, error) {
+	return s.kvsAsMap(s.AppAnnotations, "app annotation")
+}
+
+func (s *LabelFlags) kvsAsMap(kvs []string, desc string) (map[string]string, error) {
 	result := map[string]string{}
-	for _, val := range s.Labels {
+	for _, val := range kvs {
 		pieces := strings.SplitN(val, "=", 2)
 		if len(pieces) != 2 {
-			return nil, fmt.Errorf("Expected label to be in 'key=val' format")
+			return nil, fmt.Errorf("Expected %s to be in 'key=val' format", desc)
 		}
 		if len(pieces[0]) == 0 {
-			return nil, fmt.Errorf("Expected label key to be non-empty")
+			return nil, fmt.Errorf("Expected %s key to be non-empty", desc)
 		}
 		result[pieces[0]] = pieces[1]
 	}
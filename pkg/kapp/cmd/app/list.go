@@ -10,6 +10,7 @@ import (
 	"github.com/cppforlife/go-cli-ui/ui"
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
 	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
@@ -22,7 +23,10 @@ type ListOptions struct {
 
 	NamespaceFlags cmdcore.NamespaceFlags
 	AppFilterFlags cmdtools.AppFilterFlags
+	OutputFlags    OutputFlags
+	TableFlags     cmdcore.TableFlags
 	AllNamespaces  bool
+	Wide           bool
 }
 
 func NewListOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ListOptions {
@@ -41,11 +45,19 @@ func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	}
 	o.NamespaceFlags.Set(cmd, flagsFactory)
 	o.AppFilterFlags.Set(cmd)
+	o.OutputFlags.Set(cmd)
+	o.TableFlags.Set(cmd)
 	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", false, "List apps in all namespaces")
+	cmd.Flags().BoolVar(&o.Wide, "wide", false, "Include last successful change, operation, resource count and user")
 	return cmd
 }
 
 func (o *ListOptions) Run() error {
+	err := o.OutputFlags.Validate()
+	if err != nil {
+		return err
+	}
+
 	tableTitle := fmt.Sprintf("Apps in namespace '%s'", o.NamespaceFlags.Name)
 	nsHeader := uitable.NewHeader("Namespace")
 	nsHeader.Hidden = true
@@ -83,6 +95,10 @@ func (o *ListOptions) Run() error {
 		return err
 	}
 
+	if o.OutputFlags.IsStructured() {
+		return o.printStructured(items)
+	}
+
 	labelHeader := uitable.NewHeader("Label")
 	labelHeader.Hidden = true
 
@@ -92,6 +108,20 @@ func (o *ListOptions) Run() error {
 	lcaHeader := uitable.NewHeader("Last Change Age")
 	lcaHeader.Title = "Lca"
 
+	lscaHeader := uitable.NewHeader("Last Successful Change Age")
+	lscaHeader.Title = "Lsca"
+	lscaHeader.Hidden = !o.Wide
+
+	opHeader := uitable.NewHeader("Last Change Operation")
+	opHeader.Title = "Op"
+	opHeader.Hidden = !o.Wide
+
+	resourcesHeader := uitable.NewHeader("Resources")
+	resourcesHeader.Hidden = !o.Wide
+
+	userHeader := uitable.NewHeader("User")
+	userHeader.Hidden = !o.Wide
+
 	table := uitable.Table{
 		Title:   tableTitle,
 		Content: "apps",
@@ -103,6 +133,10 @@ func (o *ListOptions) Run() error {
 			uitable.NewHeader("Namespaces"),
 			lcsHeader,
 			lcaHeader,
+			lscaHeader,
+			opHeader,
+			resourcesHeader,
+			userHeader,
 		},
 
 		SortBy: []uitable.ColumnSort{
@@ -116,6 +150,12 @@ func (o *ListOptions) Run() error {
 		},
 	}
 
+	if o.Wide {
+		table.Notes = append(table.Notes,
+			lscaHeader.Title+": Last Successful Change Age",
+			opHeader.Title+": Last Change Operation")
+	}
+
 	for _, item := range items {
 		sel, err := item.LabelSelector()
 		if err != nil {
@@ -133,6 +173,11 @@ func (o *ListOptions) Run() error {
 			return err
 		}
 
+		meta, err := item.Meta()
+		if err != nil {
+			return err
+		}
+
 		if lastChange != nil {
 			row = append(row,
 				newNamespacesValue(lastChange.Meta().Namespaces),
@@ -141,23 +186,117 @@ func (o *ListOptions) Run() error {
 					Error: lastChange.Meta().Successful == nil || *lastChange.Meta().Successful != true,
 				},
 				cmdcore.NewValueAge(lastChange.Meta().StartedAt),
+				cmdcore.NewValueAge(meta.LastSuccessfulChangeFinishedAt),
+				uitable.NewValueString(lastChange.Meta().Operation),
+				uitable.NewValueInt(lastChange.Meta().NumResources),
+				uitable.NewValueString(lastChange.Meta().User),
 			)
 		} else {
 			row = append(row,
 				newNamespacesValue(nil),
 				cmdcore.NewValueUnknownBool(nil),
 				cmdcore.NewValueAge(time.Time{}),
+				cmdcore.NewValueAge(time.Time{}),
+				uitable.NewValueString(""),
+				uitable.NewValueString(""),
+				uitable.NewValueString(""),
 			)
 		}
 
 		table.Rows = append(table.Rows, row)
 	}
 
+	err = o.TableFlags.Apply(&table)
+	if err != nil {
+		return err
+	}
+
 	o.ui.PrintTable(table)
 
 	return nil
 }
 
+type listedApp struct {
+	Namespace     string   `json:"namespace"`
+	Name          string   `json:"name"`
+	LabelSelector string   `json:"labelSelector"`
+	Namespaces    []string `json:"namespaces,omitempty"`
+
+	LastChange           *listedAppChange `json:"lastChange,omitempty"`
+	LastSuccessfulChange *listedAppChange `json:"lastSuccessfulChange,omitempty"`
+}
+
+type listedAppChange struct {
+	Name         string     `json:"name"`
+	Operation    string     `json:"operation,omitempty"`
+	User         string     `json:"user,omitempty"`
+	Successful   *bool      `json:"successful,omitempty"`
+	Description  string     `json:"description,omitempty"`
+	NumResources int        `json:"numResources,omitempty"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+}
+
+func (o *ListOptions) printStructured(items []ctlapp.App) error {
+	result := []listedApp{}
+
+	for _, item := range items {
+		sel, err := item.LabelSelector()
+		if err != nil {
+			return err
+		}
+
+		meta, err := item.Meta()
+		if err != nil {
+			return err
+		}
+
+		listed := listedApp{
+			Namespace:     item.Namespace(),
+			Name:          item.Name(),
+			LabelSelector: sel.String(),
+		}
+
+		lastChange, err := item.LastChange()
+		if err != nil {
+			return err
+		}
+
+		if lastChange != nil {
+			changeMeta := lastChange.Meta()
+			listed.Namespaces = changeMeta.Namespaces
+			listed.LastChange = &listedAppChange{
+				Name:         lastChange.Name(),
+				Operation:    changeMeta.Operation,
+				User:         changeMeta.User,
+				Successful:   changeMeta.Successful,
+				Description:  changeMeta.Description,
+				NumResources: changeMeta.NumResources,
+				StartedAt:    nonZeroTime(changeMeta.StartedAt),
+				FinishedAt:   nonZeroTime(changeMeta.FinishedAt),
+			}
+		}
+
+		if len(meta.LastSuccessfulChangeName) > 0 {
+			listed.LastSuccessfulChange = &listedAppChange{
+				Name:       meta.LastSuccessfulChangeName,
+				FinishedAt: nonZeroTime(meta.LastSuccessfulChangeFinishedAt),
+			}
+		}
+
+		result = append(result, listed)
+	}
+
+	return o.OutputFlags.Print(o.ui, result)
+}
+
+func nonZeroTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 func newNamespacesValue(nss []string) uitable.Value {
 	var result string
 	var lineLen int
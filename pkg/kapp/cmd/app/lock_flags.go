@@ -0,0 +1,73 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+type LockFlags struct {
+	Enabled     bool
+	ForceUnlock bool
+	TTL         time.Duration
+}
+
+func (s *LockFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.Enabled, "lock", true, "Prevent concurrent modifications of the same app by holding a lease")
+	cmd.Flags().BoolVar(&s.ForceUnlock, "force-unlock", false, "Forcefully release app lock left behind by another kapp run")
+
+	s.TTL = 5 * time.Minute
+	cmd.Flags().Var((*lockTTLValue)(&s.TTL), "lock-ttl", "Consider app lock abandoned if it was not renewed within this duration (at least 1s)")
+}
+
+// lockTTLValue rejects durations that cannot be represented
+// as lease duration (whole seconds) or used for lock renewal
+type lockTTLValue time.Duration
+
+func (v *lockTTLValue) Set(val string) error {
+	dur, err := time.ParseDuration(val)
+	if err != nil {
+		return err
+	}
+	if dur < time.Second {
+		return fmt.Errorf("Expected lock TTL to be at least 1s, but was '%s'", dur)
+	}
+	*v = lockTTLValue(dur)
+	return nil
+}
+
+func (v *lockTTLValue) Type() string   { return "duration" }
+func (v *lockTTLValue) String() string { return time.Duration(*v).String() }
+
+// Lock returns function that releases lock once app modifications are done
+func (s *LockFlags) Lock(app ctlapp.App, coreClient kubernetes.Interface, ui ui.UI, logger logger.Logger) (func(), error) {
+	noopUnlock := func() {}
+
+	// Apps found via label selector do not have a namespace to keep lock in
+	if !s.Enabled || len(app.Namespace()) == 0 {
+		return noopUnlock, nil
+	}
+
+	lock := ctlapp.NewLock(app, coreClient, ctlapp.LockOpts{TTL: s.TTL, ForceUnlock: s.ForceUnlock}, logger)
+
+	unlock, err := lock.Acquire()
+	if err != nil {
+		if errors.IsForbidden(err) {
+			ui.PrintLinef("%s", theme.Current().Warning.Sprintf("Warning: Skipping app locking since leases are not accessible: %s", err))
+			return noopUnlock, nil
+		}
+		return nil, err
+	}
+
+	return unlock, nil
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+)
+
+func TestLockFlagsTTL(t *testing.T) {
+	parse := func(args ...string) (cmdapp.LockFlags, error) {
+		var flags cmdapp.LockFlags
+		cmd := &cobra.Command{}
+		flags.Set(cmd)
+		return flags, cmd.ParseFlags(args)
+	}
+
+	flags, err := parse()
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Minute, flags.TTL)
+
+	flags, err = parse("--lock-ttl=90s")
+	require.NoError(t, err)
+	require.Equal(t, 90*time.Second, flags.TTL)
+
+	for _, val := range []string{"0", "0s", "500ms", "-1m"} {
+		_, err = parse("--lock-ttl=" + val)
+		require.ErrorContains(t, err, "Expected lock TTL to be at least 1s", "Value: %s", val)
+	}
+
+	_, err = parse("--lock-ttl=abc")
+	require.ErrorContains(t, err, "invalid duration")
+}
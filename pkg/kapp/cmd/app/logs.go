@@ -4,6 +4,8 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
@@ -12,6 +14,7 @@ import (
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/matcher"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type LogsOptions struct {
@@ -41,7 +44,10 @@ func NewLogsCmd(o *LogsOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
   kapp logs -a app1 -f
 
   # Show logs from pods that start with 'web'
-  kapp logs -a app1 -f -m web%`,
+  kapp logs -a app1 -f -m web%
+
+  # Show last 5m of logs from 'nginx' containers in pods labeled 'tier=web'
+  kapp logs -a app1 -l tier=web --container nginx --since 5m --lines -1`,
 	}
 	o.AppFlags.Set(cmd, flagsFactory)
 	o.LogsFlags.Set(cmd)
@@ -64,6 +70,16 @@ func (o *LogsOptions) Run() error {
 		return err
 	}
 
+	if len(o.LogsFlags.PodSelector) > 0 {
+		podSelector, err := labels.Parse(o.LogsFlags.PodSelector)
+		if err != nil {
+			return fmt.Errorf("Parsing pod selector: %w", err)
+		}
+		reqs, _ := podSelector.Requirements()
+		// Pods still have to belong to the app
+		labelSelector = labelSelector.Add(reqs...)
+	}
+
 	podWatcher := ctlres.FilteringPodWatcher{
 		func(pod *corev1.Pod) bool {
 			if len(o.LogsFlags.PodName) > 0 {
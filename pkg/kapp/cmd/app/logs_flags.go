@@ -5,6 +5,7 @@ package app
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	ctllogs "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logs"
@@ -15,33 +16,56 @@ type LogsFlags struct {
 	Lines          int64
 	ContainerNames []string
 	ContainerTag   bool
+	ColorTags      bool
 	PodName        string
+	PodSelector    string
+	Since          time.Duration
 }
 
 func (s *LogsFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&s.Follow, "follow", "f", false, "As new pods are added, new pod logs will be printed")
 	cmd.Flags().Int64Var(&s.Lines, "lines", 10, "Limit to number of lines (use -1 to remove limit)")
 
+	cmd.Flags().DurationVar(&s.Since, "since", 0, "Only show logs newer than relative duration (e.g. 5s, 2m, 3h)")
+
 	cmd.Flags().BoolVar(&s.ContainerTag, "container-tag", true, "Include container tag")
+	cmd.Flags().BoolVar(&s.ColorTags, "color-tags", true, "Color container tags differently for each pod")
 
 	cmd.Flags().StringVarP(&s.PodName, "pod-name", "m", "",
 		"Set pod name to filter logs (% acts as wildcard, e.g. 'app%')")
+	cmd.Flags().StringVarP(&s.PodSelector, "pod-selector", "l", "",
+		"Set label selector to filter pods across app workloads (e.g. 'tier=web')")
 
 	cmd.Flags().StringSliceVarP(&s.ContainerNames, "container-name", "c", nil,
 		"Set container name to filter logs (% acts as wildcard, e.g. 'app%') (can repeat)")
+	cmd.Flags().StringSliceVar(&s.ContainerNames, "container", nil, "Alias of --container-name (can repeat)")
 }
 
 func (s *LogsFlags) PodLogOpts() (ctllogs.PodLogOpts, error) {
-	if !s.Follow && s.Lines <= 0 {
+	// Unbounded logs are only allowed when following or limited by time
+	if !s.Follow && s.Since == 0 && s.Lines <= 0 {
 		return ctllogs.PodLogOpts{}, fmt.Errorf(
-			"Expected --lines to be greater than zero since --follow is not specified")
+			"Expected --lines to be greater than zero since neither --follow nor --since is specified")
+	}
+
+	if s.Since < 0 {
+		return ctllogs.PodLogOpts{}, fmt.Errorf("Expected --since to be a positive duration")
 	}
 
-	opts := ctllogs.PodLogOpts{Follow: s.Follow, ContainerNames: s.ContainerNames, ContainerTag: s.ContainerTag}
+	opts := ctllogs.PodLogOpts{Follow: s.Follow, ContainerNames: s.ContainerNames,
+		ContainerTag: s.ContainerTag, ColorTags: s.ColorTags}
 
 	if s.Lines >= 0 {
 		opts.Lines = &s.Lines
 	}
+	if s.Since > 0 {
+		// Logs API only supports second precision
+		sinceSecs := int64(s.Since.Round(time.Second).Seconds())
+		if sinceSecs == 0 {
+			sinceSecs = 1
+		}
+		opts.SinceSeconds = &sinceSecs
+	}
 
 	return opts, nil
 }
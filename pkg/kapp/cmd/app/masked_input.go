@@ -0,0 +1,148 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// Prefix of values produced by diff masking (see diff.MaskedResource)
+const maskedInputValuePrefix = "<-- value not shown"
+
+// maskedInputResources returns copies of resources with values matched by
+// diff mask rules (e.g. Secret data) masked so that they are not persisted
+func maskedInputResources(resources []ctlres.Resource, rules []ctlconf.DiffMaskRule) ([]ctlres.Resource, error) {
+	var result []ctlres.Resource
+
+	for _, res := range resources {
+		maskedRes, err := ctldiff.NewMaskedResource(res, rules).Resource()
+		if err != nil {
+			return nil, fmt.Errorf("Masking resource '%s': %w", res.Description(), err)
+		}
+		result = append(result, maskedRes)
+	}
+
+	return result, nil
+}
+
+// restoreMaskedValues replaces masked values in resources that came from
+// recorded input (e.g. during rollback) with values currently in the cluster.
+// Secret stringData values are restored from data since cluster only keeps the latter.
+func restoreMaskedValues(newResources, existingResources []ctlres.Resource) error {
+	existingByKey := map[string]ctlres.Resource{}
+	for _, res := range existingResources {
+		existingByKey[ctlres.NewUniqueResourceKey(res).String()] = res
+	}
+
+	for _, res := range newResources {
+		maskedPaths := maskedValuePaths(res.UnstructuredObject(), nil)
+		if len(maskedPaths) == 0 {
+			continue
+		}
+
+		existingRes, found := existingByKey[ctlres.NewUniqueResourceKey(res).String()]
+		if !found {
+			return fmt.Errorf("Expected resource '%s' with masked values to exist in the cluster "+
+				"so that values could be restored (values are not recorded in app changes)", res.Description())
+		}
+
+		for _, path := range maskedPaths {
+			err := restoreMaskedValue(res, existingRes, path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func restoreMaskedValue(res, existingRes ctlres.Resource, path []string) error {
+	isSecretStringData := res.APIVersion() == "v1" && res.Kind() == "Secret" &&
+		len(path) == 2 && path[0] == "stringData"
+
+	if isSecretStringData {
+		val, found := valueAtPath(existingRes.UnstructuredObject(), []string{"data", path[1]})
+		if found {
+			deleteValueAtPath(res.UnstructuredObject(), path)
+			stringData, _ := valueAtPath(res.UnstructuredObject(), path[:1])
+			if typedStringData, _ := stringData.(map[string]interface{}); len(typedStringData) == 0 {
+				deleteValueAtPath(res.UnstructuredObject(), path[:1])
+			}
+			return setValueAtPath(res.UnstructuredObject(), []string{"data", path[1]}, val)
+		}
+	}
+
+	val, found := valueAtPath(existingRes.UnstructuredObject(), path)
+	if !found {
+		return fmt.Errorf("Expected resource '%s' to have value at path '%s' in the cluster "+
+			"so that masked value could be restored", res.Description(), strings.Join(path, "."))
+	}
+
+	return setValueAtPath(res.UnstructuredObject(), path, val)
+}
+
+func maskedValuePaths(val interface{}, path []string) [][]string {
+	var result [][]string
+
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		for k, v := range typedVal {
+			result = append(result, maskedValuePaths(v, append(append([]string{}, path...), k))...)
+		}
+	case string:
+		if strings.HasPrefix(typedVal, maskedInputValuePrefix) {
+			result = append(result, path)
+		}
+	}
+
+	return result
+}
+
+func valueAtPath(obj map[string]interface{}, path []string) (interface{}, bool) {
+	var val interface{} = obj
+
+	for _, key := range path {
+		typedVal, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		val, ok = typedVal[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return val, true
+}
+
+func setValueAtPath(obj map[string]interface{}, path []string, val interface{}) error {
+	for i, key := range path[:len(path)-1] {
+		next, found := obj[key]
+		if !found {
+			next = map[string]interface{}{}
+			obj[key] = next
+		}
+		typedNext, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Expected value at path '%s' to be a map", strings.Join(path[:i+1], "."))
+		}
+		obj = typedNext
+	}
+
+	obj[path[len(path)-1]] = val
+	return nil
+}
+
+func deleteValueAtPath(obj map[string]interface{}, path []string) {
+	parent, found := valueAtPath(obj, path[:len(path)-1])
+	if typedParent, ok := parent.(map[string]interface{}); found && ok {
+		delete(typedParent, path[len(path)-1])
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestMaskedInputResourcesDoNotIncludeSecretValues(t *testing.T) {
+	secret := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+data:
+  password: bXktcGFzc3dvcmQ=
+stringData:
+  token: my-token
+`))
+
+	configMap := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: value
+`))
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err)
+
+	resources, err := maskedInputResources([]ctlres.Resource{secret, configMap}, conf.DiffMaskRules())
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	bs, err := resources[0].AsYAMLBytes()
+	require.NoError(t, err)
+	require.NotContains(t, string(bs), "bXktcGFzc3dvcmQ=")
+	require.NotContains(t, string(bs), "my-token")
+
+	bs, err = resources[1].AsYAMLBytes()
+	require.NoError(t, err)
+	require.Contains(t, string(bs), "key: value")
+
+	// Original resources are not modified
+	bs, err = secret.AsYAMLBytes()
+	require.NoError(t, err)
+	require.Contains(t, string(bs), "my-token")
+}
+
+func TestRestoreMaskedValuesFromExistingResources(t *testing.T) {
+	secret := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+  namespace: ns1
+data:
+  password: bXktcGFzc3dvcmQ=
+stringData:
+  token: my-token
+`))
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err)
+
+	masked, err := maskedInputResources([]ctlres.Resource{secret}, conf.DiffMaskRules())
+	require.NoError(t, err)
+
+	existingSecret := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+  namespace: ns1
+data:
+  password: bXktcGFzc3dvcmQ=
+  token: bXktdG9rZW4=
+`))
+
+	err = restoreMaskedValues(masked, []ctlres.Resource{existingSecret})
+	require.NoError(t, err)
+
+	bs, err := masked[0].AsYAMLBytes()
+	require.NoError(t, err)
+	require.Equal(t, `apiVersion: v1
+data:
+  password: bXktcGFzc3dvcmQ=
+  token: bXktdG9rZW4=
+kind: Secret
+metadata:
+  name: secret1
+  namespace: ns1
+`, string(bs))
+}
+
+func TestRestoreMaskedValuesRequiresExistingResource(t *testing.T) {
+	secret := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+  namespace: ns1
+data:
+  password: bXktcGFzc3dvcmQ=
+`))
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	require.NoError(t, err)
+
+	masked, err := maskedInputResources([]ctlres.Resource{secret}, conf.DiffMaskRules())
+	require.NoError(t, err)
+
+	err = restoreMaskedValues(masked, nil)
+	require.EqualError(t, err, "Expected resource 'secret/secret1 (v1) namespace: ns1' with masked values to exist "+
+		"in the cluster so that values could be restored (values are not recorded in app changes)")
+}
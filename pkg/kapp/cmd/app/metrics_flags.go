@@ -0,0 +1,47 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+	ctlmetrics "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/metrics"
+)
+
+type MetricsFlags struct {
+	PushgatewayURL string
+	Job            string
+	Textfile       string
+}
+
+func (s *MetricsFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&s.PushgatewayURL, "metrics-pushgateway-url", "",
+		"Push Prometheus metrics of deploy to Pushgateway (e.g. http://pushgateway:9091)")
+	cmd.Flags().StringVar(&s.Job, "metrics-job", "kapp", "Set Pushgateway job name")
+	cmd.Flags().StringVar(&s.Textfile, "metrics-textfile", "",
+		"Write Prometheus metrics of deploy to file (e.g. for node exporter textfile collector)")
+}
+
+// Recorder returns nil if metrics are not enabled
+func (s MetricsFlags) Recorder(app, namespace string) *ctlmetrics.Recorder {
+	if len(s.PushgatewayURL) == 0 && len(s.Textfile) == 0 {
+		return nil
+	}
+	return ctlmetrics.NewRecorder(app, namespace)
+}
+
+func (s MetricsFlags) Export(recorder *ctlmetrics.Recorder) error {
+	if recorder == nil {
+		return nil
+	}
+	if len(s.Textfile) > 0 {
+		err := recorder.WriteTextfile(s.Textfile)
+		if err != nil {
+			return err
+		}
+	}
+	if len(s.PushgatewayURL) > 0 {
+		return recorder.Push(nil, s.PushgatewayURL, s.Job)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+)
+
+type MigrateAppOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags     Flags
+	NewNamespace string
+}
+
+func NewMigrateAppOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *MigrateAppOptions {
+	return &MigrateAppOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewMigrateAppCmd(o *MigrateAppOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-app",
+		Short: "Move app metadata to a different namespace",
+		Long: `Move app metadata and app changes to a different namespace.
+
+Deployed resources are not modified. Once migrated, specify
+--app-namespace with the new namespace for subsequent commands.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Move metadata of app 'app1' from namespace 'default' into 'apps'
+  kapp migrate-app -a app1 -n default --new-namespace apps`,
+		Annotations: map[string]string{
+			cmdcore.AppSupportHelpGroup.Key: cmdcore.AppSupportHelpGroup.Value,
+		},
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.NewNamespace, "new-namespace", "", "Set namespace to move app metadata into")
+	return cmd
+}
+
+func (o *MigrateAppOptions) Run() error {
+	if len(o.NewNamespace) == 0 {
+		return fmt.Errorf("Expected --new-namespace to be specified")
+	}
+
+	app, _, err := Factory(o.depsFactory, o.AppFlags, ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	exists, notExistsMsg, err := app.Exists()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("%s", notExistsMsg)
+	}
+
+	if app.Namespace() == o.NewNamespace {
+		return fmt.Errorf("Expected new namespace to differ from current app namespace '%s'", app.Namespace())
+	}
+
+	o.ui.PrintLinef("Moving metadata of app '%s' from namespace '%s' to '%s' (resources will not be changed)",
+		app.Name(), app.Namespace(), o.NewNamespace)
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return AbortedExitStatus{err}
+	}
+
+	err = app.Rename(app.Name(), o.NewNamespace)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Use '--app-namespace %s' to refer to the app from now on", o.NewNamespace)
+
+	return nil
+}
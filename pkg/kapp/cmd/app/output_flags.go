@@ -0,0 +1,93 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	OutputFormatTable = "table"
+	OutputFormatJSON  = "json"
+	OutputFormatYAML  = "yaml"
+)
+
+type OutputFlags struct {
+	Format string
+
+	// DocumentFunc (if set) receives documents instead of them being printed
+	// in one of the formats (e.g. when commands are embedded via SDK)
+	DocumentFunc func(obj interface{}) error
+}
+
+func (s *OutputFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&s.Format, "output", "o", OutputFormatTable, "Set output format (table, json, yaml)")
+}
+
+func (s *OutputFlags) Validate() error {
+	switch s.Format {
+	// Empty when options are not populated via flags (e.g. deploy is used by other commands)
+	case "", OutputFormatTable, OutputFormatJSON, OutputFormatYAML:
+		return nil
+	default:
+		return fmt.Errorf("Unknown output format '%s' (supported: %s, %s, %s)",
+			s.Format, OutputFormatTable, OutputFormatJSON, OutputFormatYAML)
+	}
+}
+
+// IsStructured indicates that output should be printed as a document instead of tables
+func (s *OutputFlags) IsStructured() bool {
+	return s.DocumentFunc != nil || s.Format == OutputFormatJSON || s.Format == OutputFormatYAML
+}
+
+// HumanUI returns UI for human oriented output given UI that documents are printed to
+func (s *OutputFlags) HumanUI(parent ui.UI) ui.UI {
+	if s.DocumentFunc != nil {
+		return parent
+	}
+	return newStderrUI(parent)
+}
+
+func (s *OutputFlags) Print(ui ui.UI, obj interface{}) error {
+	if s.DocumentFunc != nil {
+		return s.DocumentFunc(obj)
+	}
+
+	var bs []byte
+	var err error
+
+	switch s.Format {
+	case OutputFormatJSON:
+		bs, err = json.MarshalIndent(obj, "", "  ")
+		bs = append(bs, '\n')
+	case OutputFormatYAML:
+		bs, err = yaml.Marshal(obj)
+	default:
+		return fmt.Errorf("Expected structured output format, but was '%s'", s.Format)
+	}
+	if err != nil {
+		return fmt.Errorf("Marshaling output: %w", err)
+	}
+
+	ui.PrintBlock(bs)
+	return nil
+}
+
+// HasStructuredOutput indicates that command was asked to print documents,
+// hence any additional output (e.g. target cluster) should be avoided
+func HasStructuredOutput(cmd *cobra.Command) bool {
+	if flag := cmd.Flags().Lookup("progress-format"); flag != nil && flag.Value.String() == ProgressFormatNDJSON {
+		return true
+	}
+	flag := cmd.Flags().Lookup("output")
+	if flag == nil {
+		return false
+	}
+	return flag.Value.String() == OutputFormatJSON || flag.Value.String() == OutputFormatYAML
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type PlanOptions struct {
+	*DeployOptions
+
+	PlanFile string
+}
+
+func NewPlanOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *PlanOptions {
+	return &PlanOptions{DeployOptions: NewDeployOptions(ui, depsFactory, logger)}
+}
+
+func NewPlanCmd(o *PlanOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := NewDeployCmd(o.DeployOptions, flagsFactory)
+	cmd.Use = "plan"
+	cmd.Aliases = nil
+	cmd.Short = "Plan app deploy and save changes into a plan file"
+	cmd.RunE = func(_ *cobra.Command, _ []string) error { return o.Run() }
+	cmd.Example = `
+  # Save planned changes for app 'app1' based on config files in config/
+  kapp plan -a app1 -f config/ --plan-file plan.yml
+
+  # Later apply exactly planned changes
+  kapp apply-plan --plan-file plan.yml`
+
+	cmd.Flags().StringVar(&o.PlanFile, "plan-file", "", "Set path to write plan file to")
+
+	return cmd
+}
+
+func (o *PlanOptions) Run() error {
+	if len(o.PlanFile) == 0 {
+		return fmt.Errorf("Expected --plan-file to be specified")
+	}
+
+	o.DiffFlags.Run = true
+
+	o.planChangesFunc = func(inputResources []ctlres.Resource, changes []*ctlcap.ClusterChange, maskRules []ctlconf.DiffMaskRule) error {
+		plan, err := NewPlanFile(o.AppFlags, o.DeployFlags, inputResources, changes, maskRules)
+		if err != nil {
+			return err
+		}
+
+		err = plan.Sign()
+		if err != nil {
+			return err
+		}
+
+		err = plan.Write(o.PlanFile)
+		if err != nil {
+			return err
+		}
+
+		o.ui.PrintLinef("Wrote plan with %d changes to '%s'", len(plan.Changes), o.PlanFile)
+		return nil
+	}
+
+	return o.DeployOptions.Run()
+}
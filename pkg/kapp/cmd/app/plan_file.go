@@ -0,0 +1,276 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	planAPIVersion = "kapp.k14s.io/v1alpha1"
+	planKind       = "Plan"
+
+	// When set, plans are signed with HMAC-SHA256 using value as a key;
+	// otherwise plans only carry SHA256 checksum of their contents
+	planSigningKeyEnvVar = "KAPP_PLAN_SIGNING_KEY"
+
+	planSignatureTypeSHA256     = "sha256"
+	planSignatureTypeHMACSHA256 = "hmac-sha256"
+)
+
+type PlanFile struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	App          string `json:"app"`
+	Namespace    string `json:"namespace"`
+	AppNamespace string `json:"appNamespace,omitempty"`
+
+	IntoNamespace string   `json:"intoNamespace,omitempty"`
+	MapNamespaces []string `json:"mapNamespaces,omitempty"`
+
+	// Resources are input resources (including kapp config) as YAML documents
+	Resources string       `json:"resources"`
+	Changes   []PlanChange `json:"changes"`
+
+	SignatureType string `json:"signatureType"`
+	Signature     string `json:"signature"`
+}
+
+type PlanChange struct {
+	Resource string `json:"resource"`
+	ApplyOp  string `json:"applyOp"`
+	WaitOp   string `json:"waitOp"`
+	DiffMD5  string `json:"diffMD5"`
+	Diff     string `json:"diff,omitempty"`
+}
+
+// NewPlanFile masks input resources with enforced diff mask rules (e.g. decrypted SOPS values),
+// since other values (e.g. Secret data) are necessary to apply the plan; diffs are masked with all rules.
+func NewPlanFile(appFlags Flags, deployFlags DeployFlags, inputResources []ctlres.Resource,
+	changes []*ctlcap.ClusterChange, maskRules []ctlconf.DiffMaskRule) (PlanFile, error) {
+
+	inputResources, err := maskedInputResources(inputResources, enforcedMaskRules(maskRules))
+	if err != nil {
+		return PlanFile{}, err
+	}
+
+	var resourcesYAML []string
+
+	for _, res := range inputResources {
+		bs, err := res.AsYAMLBytes()
+		if err != nil {
+			return PlanFile{}, fmt.Errorf("Serializing resource '%s': %w", res.Description(), err)
+		}
+		resourcesYAML = append(resourcesYAML, string(bs))
+	}
+
+	planChanges, err := NewPlanChanges(changes, maskRules)
+	if err != nil {
+		return PlanFile{}, err
+	}
+
+	plan := PlanFile{
+		APIVersion:   planAPIVersion,
+		Kind:         planKind,
+		App:          appFlags.Name,
+		Namespace:    appFlags.NamespaceFlags.Name,
+		AppNamespace: appFlags.AppNamespace,
+
+		IntoNamespace: deployFlags.IntoNamespace,
+		MapNamespaces: deployFlags.MapNamespaces,
+
+		Resources: "---\n" + strings.Join(resourcesYAML, "---\n"),
+		Changes:   planChanges,
+	}
+
+	return plan, nil
+}
+
+// NewPlanChanges includes diffs masked with given rules (if any);
+// diff checksums are always calculated against unmasked diffs
+func NewPlanChanges(changes []*ctlcap.ClusterChange, maskRules []ctlconf.DiffMaskRule) ([]PlanChange, error) {
+	var result []PlanChange
+
+	for _, change := range changes {
+		textDiff := change.ConfigurableTextDiff().Full()
+
+		maskedTextDiff, err := change.ConfigurableTextDiff().Masked(maskRules)
+		if err != nil {
+			return nil, fmt.Errorf("Masking diff of resource '%s': %w", change.Resource().Description(), err)
+		}
+
+		result = append(result, PlanChange{
+			Resource: change.Resource().Description(),
+			ApplyOp:  string(change.ApplyOp()),
+			WaitOp:   string(change.WaitOp()),
+			DiffMD5:  textDiff.MinimalMD5(),
+			Diff:     maskedTextDiff.MinimalString(),
+		})
+	}
+
+	return result, nil
+}
+
+func enforcedMaskRules(rules []ctlconf.DiffMaskRule) []ctlconf.DiffMaskRule {
+	var result []ctlconf.DiffMaskRule
+	for _, rule := range rules {
+		if rule.Enforce {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+func NewPlanFileFromPath(path string) (PlanFile, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return PlanFile{}, fmt.Errorf("Reading plan file: %w", err)
+	}
+
+	var plan PlanFile
+
+	err = yaml.Unmarshal(bs, &plan)
+	if err != nil {
+		return PlanFile{}, fmt.Errorf("Unmarshaling plan file: %w", err)
+	}
+
+	if plan.APIVersion != planAPIVersion || plan.Kind != planKind {
+		return PlanFile{}, fmt.Errorf("Expected plan file to have apiVersion '%s' and kind '%s'", planAPIVersion, planKind)
+	}
+
+	err = plan.Verify()
+	if err != nil {
+		return PlanFile{}, err
+	}
+
+	return plan, nil
+}
+
+func (p PlanFile) InputResources() ([]ctlres.Resource, error) {
+	return ctlres.NewFileResource(ctlres.NewBytesSource([]byte(p.Resources))).Resources()
+}
+
+func (p *PlanFile) Sign() error {
+	sigType, sig, err := p.calculateSignature(os.Getenv(planSigningKeyEnvVar))
+	if err != nil {
+		return err
+	}
+	p.SignatureType = sigType
+	p.Signature = sig
+	return nil
+}
+
+func (p PlanFile) Verify() error {
+	key := os.Getenv(planSigningKeyEnvVar)
+
+	switch p.SignatureType {
+	case planSignatureTypeSHA256:
+		// Otherwise anyone could modify plan and replace signature with plain checksum
+		if len(key) > 0 {
+			return fmt.Errorf("Expected plan file to be signed with '%s' since env variable '%s' is set, but was '%s'",
+				planSignatureTypeHMACSHA256, planSigningKeyEnvVar, p.SignatureType)
+		}
+	case planSignatureTypeHMACSHA256:
+		if len(key) == 0 {
+			return fmt.Errorf("Expected env variable '%s' to be set to verify signed plan", planSigningKeyEnvVar)
+		}
+	default:
+		return fmt.Errorf("Unknown plan signature type '%s' (supported: %s, %s)",
+			p.SignatureType, planSignatureTypeSHA256, planSignatureTypeHMACSHA256)
+	}
+
+	_, sig, err := p.calculateSignature(key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(sig), []byte(p.Signature)) {
+		return fmt.Errorf("Expected plan file signature to match its contents (plan was modified or signed with a different key)")
+	}
+	return nil
+}
+
+func (p PlanFile) calculateSignature(key string) (string, string, error) {
+	p.SignatureType = ""
+	p.Signature = ""
+
+	bs, err := json.Marshal(p)
+	if err != nil {
+		return "", "", fmt.Errorf("Marshaling plan: %w", err)
+	}
+
+	if len(key) == 0 {
+		sum := sha256.Sum256(bs)
+		return planSignatureTypeSHA256, hex.EncodeToString(sum[:]), nil
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(bs)
+	return planSignatureTypeHMACSHA256, hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (p PlanFile) Write(path string) error {
+	bs, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("Marshaling plan: %w", err)
+	}
+
+	err = os.WriteFile(path, bs, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing plan file: %w", err)
+	}
+	return nil
+}
+
+// CheckChanges makes sure that currently calculated changes
+// are exactly the same as ones that were planned
+func (p PlanFile) CheckChanges(changes []*ctlcap.ClusterChange) error {
+	currChanges, err := NewPlanChanges(changes, nil)
+	if err != nil {
+		return err
+	}
+
+	var driftedMsgs []string
+
+	plannedByRes := map[string]PlanChange{}
+	for _, change := range p.Changes {
+		plannedByRes[change.Resource] = change
+	}
+
+	for _, change := range currChanges {
+		planned, found := plannedByRes[change.Resource]
+		switch {
+		case !found:
+			driftedMsgs = append(driftedMsgs, fmt.Sprintf("- %s: change was not planned", change.Resource))
+		case planned.ApplyOp != change.ApplyOp || planned.WaitOp != change.WaitOp:
+			driftedMsgs = append(driftedMsgs, fmt.Sprintf("- %s: planned '%s/%s' but found '%s/%s'",
+				change.Resource, planned.ApplyOp, planned.WaitOp, change.ApplyOp, change.WaitOp))
+		case planned.DiffMD5 != change.DiffMD5:
+			driftedMsgs = append(driftedMsgs, fmt.Sprintf("- %s: diff no longer matches planned diff", change.Resource))
+		}
+		delete(plannedByRes, change.Resource)
+	}
+
+	for _, change := range p.Changes {
+		if _, found := plannedByRes[change.Resource]; found {
+			driftedMsgs = append(driftedMsgs, fmt.Sprintf("- %s: planned change is no longer necessary", change.Resource))
+		}
+	}
+
+	if len(driftedMsgs) > 0 {
+		return fmt.Errorf("Cluster has drifted since planning (re-run 'kapp plan'):\n%s", strings.Join(driftedMsgs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestPlanFileVerifiesChecksumWithoutSigningKey(t *testing.T) {
+	t.Setenv("KAPP_PLAN_SIGNING_KEY", "")
+
+	path := writePlan(t, newPlan())
+
+	_, err := cmdapp.NewPlanFileFromPath(path)
+	require.NoError(t, err)
+}
+
+func TestPlanFileVerifiesSignatureWithSigningKey(t *testing.T) {
+	t.Setenv("KAPP_PLAN_SIGNING_KEY", "key1")
+
+	path := writePlan(t, newPlan())
+
+	_, err := cmdapp.NewPlanFileFromPath(path)
+	require.NoError(t, err)
+
+	t.Setenv("KAPP_PLAN_SIGNING_KEY", "key2")
+
+	_, err = cmdapp.NewPlanFileFromPath(path)
+	require.EqualError(t, err, "Expected plan file signature to match its contents (plan was modified or signed with a different key)")
+}
+
+func TestPlanFileRejectsChecksumWhenSigningKeyIsSet(t *testing.T) {
+	t.Setenv("KAPP_PLAN_SIGNING_KEY", "key1")
+
+	// Tampered plan with recalculated plain checksum instead of signature
+	plan := newPlan()
+	plan.Resources = "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: injected\n"
+
+	bs, err := json.Marshal(plan)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(bs)
+	plan.SignatureType = "sha256"
+	plan.Signature = hex.EncodeToString(sum[:])
+
+	path := filepath.Join(t.TempDir(), "plan.yml")
+	require.NoError(t, plan.Write(path))
+
+	_, err = cmdapp.NewPlanFileFromPath(path)
+	require.EqualError(t, err, "Expected plan file to be signed with 'hmac-sha256' since env variable 'KAPP_PLAN_SIGNING_KEY' is set, but was 'sha256'")
+}
+
+func TestPlanFileWithMultipleInputResources(t *testing.T) {
+	t.Setenv("KAPP_PLAN_SIGNING_KEY", "")
+
+	plan, err := cmdapp.NewPlanFileFromPath(writePlan(t, newPlan()))
+	require.NoError(t, err)
+
+	resources, err := plan.InputResources()
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	require.Equal(t, "cm1", resources[0].Name())
+	require.Equal(t, "cm2", resources[1].Name())
+}
+
+func TestPlanFileMasksValuesMatchedByEnforcedRules(t *testing.T) {
+	resources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(`
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: decrypted
+stringData:
+  password: decrypted-password
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: regular
+stringData:
+  password: regular-password
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+diffMaskRules:
+- path: [stringData]
+  enforce: true
+  resourceMatchers:
+  - nameRegexMatcher: {regex: "^decrypted$"}
+`))).Resources()
+	require.NoError(t, err)
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(resources)
+	require.NoError(t, err)
+
+	plan, err := cmdapp.NewPlanFile(cmdapp.Flags{}, cmdapp.DeployFlags{}, resources, nil, conf.DiffMaskRules())
+	require.NoError(t, err)
+
+	require.NotContains(t, plan.Resources, "decrypted-password")
+	// Necessary to apply the plan later
+	require.Contains(t, plan.Resources, "regular-password")
+}
+
+func newPlan() cmdapp.PlanFile {
+	return cmdapp.PlanFile{
+		APIVersion: "kapp.k14s.io/v1alpha1",
+		Kind:       "Plan",
+		App:        "app1",
+		Namespace:  "ns1",
+		Resources: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+`,
+	}
+}
+
+func writePlan(t *testing.T, plan cmdapp.PlanFile) string {
+	require.NoError(t, plan.Sign())
+
+	path := filepath.Join(t.TempDir(), "plan.yml")
+	require.NoError(t, plan.Write(path))
+	return path
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+const (
+	ProgressFormatText   = "text"
+	ProgressFormatNDJSON = "ndjson"
+)
+
+type ProgressFlags struct {
+	Format string
+}
+
+func (s *ProgressFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&s.Format, "progress-format", ProgressFormatText,
+		"Set progress format (text, ndjson); ndjson prints one JSON event per line to stdout and text output to stderr")
+}
+
+func (s *ProgressFlags) Validate(outputFlags OutputFlags) error {
+	switch s.Format {
+	// Empty when options are not populated via flags
+	case "", ProgressFormatText:
+		return nil
+	case ProgressFormatNDJSON:
+		if outputFlags.IsStructured() {
+			return fmt.Errorf("Expected --progress-format=%s to not be used together with --output %s",
+				ProgressFormatNDJSON, outputFlags.Format)
+		}
+		return nil
+	default:
+		return fmt.Errorf("Unknown progress format '%s' (supported: %s, %s)",
+			s.Format, ProgressFormatText, ProgressFormatNDJSON)
+	}
+}
+
+func (s *ProgressFlags) IsNDJSON() bool { return s.Format == ProgressFormatNDJSON }
+
+// ndjsonProgress writes each progress event as a single line of JSON
+type ndjsonProgress struct {
+	out  io.Writer
+	lock sync.Mutex
+}
+
+var _ ctlcap.ProgressObserver = &ndjsonProgress{}
+
+func newNDJSONProgress() *ndjsonProgress {
+	return &ndjsonProgress{out: os.Stdout}
+}
+
+func (p *ndjsonProgress) Emit(event ctlcap.ProgressEvent) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	bs, err := json.Marshal(event)
+	if err != nil {
+		// Event only includes plain values
+		panic(fmt.Sprintf("Marshaling progress event: %s", err))
+	}
+	_, _ = p.out.Write(append(bs, '\n'))
+}
+
+func (p *ndjsonProgress) OnDiffComputed(event ctlcap.ProgressEvent)  { p.Emit(event) }
+func (p *ndjsonProgress) OnChangeApplied(event ctlcap.ProgressEvent) { p.Emit(event) }
+func (p *ndjsonProgress) OnResourceReady(event ctlcap.ProgressEvent) { p.Emit(event) }
+func (p *ndjsonProgress) OnWaitTimeout(event ctlcap.ProgressEvent)   { p.Emit(event) }
+
+func (p *ndjsonProgress) EmitCompleted(err error) {
+	successful := isSuccessfulChangeErr(err)
+	event := ctlcap.ProgressEvent{
+		Type:       ctlcap.ProgressEventTypeCompleted,
+		Time:       time.Now().UTC(),
+		Successful: &successful,
+	}
+	if !successful {
+		event.Error = err.Error()
+	}
+	p.Emit(event)
+}
+
+func emitProgress(progressFunc ctlcap.ProgressEventFunc, event ctlcap.ProgressEvent) {
+	if progressFunc != nil {
+		progressFunc(event)
+	}
+}
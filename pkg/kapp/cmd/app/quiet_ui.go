@@ -0,0 +1,37 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+// quietUI only lets through errors and questions (see --quiet flag)
+type quietUI struct {
+	parent ui.UI
+}
+
+var _ ui.UI = quietUI{}
+
+func newQuietUI(parent ui.UI) quietUI { return quietUI{parent} }
+
+func (u quietUI) ErrorLinef(pattern string, args ...interface{}) {
+	u.parent.ErrorLinef(pattern, args...)
+}
+func (u quietUI) PrintLinef(string, ...interface{}) {}
+func (u quietUI) BeginLinef(string, ...interface{}) {}
+func (u quietUI) EndLinef(string, ...interface{})   {}
+func (u quietUI) PrintBlock([]byte)                 {}
+func (u quietUI) PrintErrorBlock(block string)      { u.parent.PrintErrorBlock(block) }
+func (u quietUI) PrintTable(uitable.Table)          {}
+
+func (u quietUI) AskForText(label string) (string, error) { return u.parent.AskForText(label) }
+func (u quietUI) AskForChoice(label string, options []string) (int, error) {
+	return u.parent.AskForChoice(label, options)
+}
+func (u quietUI) AskForPassword(label string) (string, error) { return u.parent.AskForPassword(label) }
+func (u quietUI) AskForConfirmation() error                   { return u.parent.AskForConfirmation() }
+func (u quietUI) IsInteractive() bool                         { return u.parent.IsInteractive() }
+func (u quietUI) Flush()                                      { u.parent.Flush() }
@@ -0,0 +1,137 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
+)
+
+// ReadinessGatesChecker makes sure that external dependencies
+// declared via ReadinessGates config are ready before apply begins
+type ReadinessGatesChecker struct {
+	supportObjs FactorySupportObjs
+	ui          ui.UI
+	logger      logger.Logger
+}
+
+func NewReadinessGatesChecker(supportObjs FactorySupportObjs, ui ui.UI, logger logger.Logger) ReadinessGatesChecker {
+	return ReadinessGatesChecker{supportObjs, ui, logger.NewPrefixed("ReadinessGatesChecker")}
+}
+
+func (c ReadinessGatesChecker) Check(allGates []ctlconf.ReadinessGates) error {
+	for _, gates := range allGates {
+		for _, gate := range gates.Gates {
+			c.ui.PrintLinef("Waiting for readiness gate %s", gate.Description())
+
+			var lastMsg string
+			var ready bool
+
+			err := util.Retry(gates.CheckIntervalDuration(), gates.TimeoutDuration(), func() (bool, error) {
+				msg, err := c.checkGate(gate)
+				if err != nil {
+					return false, err
+				}
+				if len(msg) > 0 {
+					if msg != lastMsg {
+						c.ui.PrintLinef("  ^ %s", msg)
+						lastMsg = msg
+					}
+					return false, nil
+				}
+				ready = true
+				return true, nil
+			})
+			if err != nil {
+				return fmt.Errorf("Waiting for readiness gate %s: %w", gate.Description(), err)
+			}
+			if !ready {
+				return fmt.Errorf("Timed out waiting after %s for readiness gate %s: %s",
+					gates.TimeoutDuration(), gate.Description(), lastMsg)
+			}
+		}
+	}
+	return nil
+}
+
+// checkGate returns non-empty message when gate is not ready yet
+func (c ReadinessGatesChecker) checkGate(gate ctlconf.ReadinessGate) (string, error) {
+	switch {
+	case gate.HTTP != nil:
+		return c.checkHTTP(*gate.HTTP)
+	case gate.DNS != nil:
+		return c.checkDNS(*gate.DNS)
+	case gate.App != nil:
+		return c.checkApp(*gate.App)
+	default:
+		return "", fmt.Errorf("Unknown readiness gate type")
+	}
+}
+
+func (c ReadinessGatesChecker) checkHTTP(gate ctlconf.ReadinessGateHTTP) (string, error) {
+	client := http.Client{Timeout: 10 * time.Second}
+
+	resp, err := client.Get(gate.URL)
+	if err != nil {
+		return fmt.Sprintf("Request failed: %s", err), nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Sprintf("Expected status code 200 but got %d", resp.StatusCode), nil
+	}
+	return "", nil
+}
+
+func (c ReadinessGatesChecker) checkDNS(gate ctlconf.ReadinessGateDNS) (string, error) {
+	addrs, err := net.LookupHost(gate.Name)
+	if err != nil {
+		return fmt.Sprintf("Lookup failed: %s", err), nil
+	}
+	if len(addrs) == 0 {
+		return "Expected name to resolve to at least one address", nil
+	}
+	return "", nil
+}
+
+func (c ReadinessGatesChecker) checkApp(gate ctlconf.ReadinessGateApp) (string, error) {
+	apps := c.supportObjs.Apps
+	if len(gate.Namespace) > 0 {
+		apps = apps.WithNamespace(gate.Namespace)
+	}
+
+	app, err := apps.Find(gate.Name)
+	if err != nil {
+		return "", err
+	}
+
+	exists, _, err := app.Exists()
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "App does not exist", nil
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case meta.LastChange.Successful == nil:
+		return "App has not finished deploying", nil
+	case !*meta.LastChange.Successful:
+		return "App last deploy was not successful", nil
+	default:
+		return "", nil
+	}
+}
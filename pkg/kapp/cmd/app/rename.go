@@ -28,9 +28,15 @@ func NewRenameOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.L
 
 func NewRenameCmd(o *RenameOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "rename",
-		Short: "Rename app",
-		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Use:     "rename",
+		Aliases: []string{"rename-app"},
+		Short:   "Rename app",
+		Long: `Rename app by recreating its metadata and moving its app changes.
+
+Resources owned by the app do not need to be relabeled since they are
+associated with the app via a label value that does not depend on app name.
+If rename is interrupted, running the same command again continues it.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 		Annotations: map[string]string{
 			cmdcore.AppSupportHelpGroup.Key: cmdcore.AppSupportHelpGroup.Value,
 		},
@@ -70,12 +76,12 @@ func (o *RenameOptions) Run() error {
 		newNamespace = o.AppFlags.NamespaceFlags.Name
 	}
 
-	o.ui.PrintLinef("Renaming '%s' (namespace: %s) to '%s' (namespace: %s) (app changes will not be renamed)",
+	o.ui.PrintLinef("Renaming '%s' (namespace: %s) to '%s' (namespace: %s)",
 		app.Name(), o.AppFlags.NamespaceFlags.Name, newName, newNamespace)
 
 	err = o.ui.AskForConfirmation()
 	if err != nil {
-		return err
+		return AbortedExitStatus{err}
 	}
 
 	return app.Rename(newName, newNamespace)
@@ -4,7 +4,12 @@
 package app
 
 import (
+	"os"
+	"path/filepath"
+	"time"
+
 	"github.com/spf13/cobra"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -13,6 +18,11 @@ type ResourceTypesFlags struct {
 	CanIgnoreFailingAPIService func(schema.GroupVersion) bool
 
 	ScopeToFallbackAllowedNamespaces bool
+
+	ListConcurrency int
+
+	DiscoveryCacheTTL time.Duration
+	DiscoveryCacheDir string
 }
 
 func (s *ResourceTypesFlags) Set(cmd *cobra.Command) {
@@ -21,6 +31,30 @@ func (s *ResourceTypesFlags) Set(cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&s.ScopeToFallbackAllowedNamespaces, "dangerous-scope-to-fallback-allowed-namespaces",
 		false, "Scope resource searching to fallback allowed namespaces")
+
+	cmd.Flags().IntVar(&s.ListConcurrency, "resource-list-concurrency",
+		0, "Maximum number of resource types listed concurrently when fetching resources (0 means no limit)")
+
+	cmd.Flags().DurationVar(&s.DiscoveryCacheTTL, "discovery-cache-ttl",
+		0, "Reuse discovered API resources for this long across invocations (0 disables caching)")
+	cmd.Flags().StringVar(&s.DiscoveryCacheDir, "discovery-cache-dir",
+		defaultDiscoveryCacheDir(), "Directory to keep discovered API resources in")
+}
+
+// DiscoveryCache returns nil if caching is disabled
+func (s *ResourceTypesFlags) DiscoveryCache() *ctlres.DiscoveryCache {
+	if s.DiscoveryCacheTTL <= 0 || len(s.DiscoveryCacheDir) == 0 {
+		return nil
+	}
+	return ctlres.NewDiscoveryCache(s.DiscoveryCacheDir, s.DiscoveryCacheTTL)
+}
+
+func defaultDiscoveryCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kapp", "discovery")
 }
 
 func (s *ResourceTypesFlags) FailingAPIServicePolicy() *FailingAPIServicesPolicy {
@@ -0,0 +1,98 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	statefulGKs = map[schema.GroupKind]struct{}{
+		{Group: "apps", Kind: "StatefulSet"}:       {},
+		{Group: "", Kind: "PersistentVolumeClaim"}: {},
+		{Group: "", Kind: "PersistentVolume"}:      {},
+	}
+	namespaceGK = schema.GroupKind{Group: "", Kind: "Namespace"}
+	crdGK       = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+)
+
+type RiskFlags struct {
+	ConfirmThreshold int
+}
+
+func (s *RiskFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&s.ConfirmThreshold, "confirm-risky-changes-threshold", 1,
+		"Require typing app name to confirm once number of high-risk changes (deletion of stateful "+
+			"resources or namespaces, CRD updates or deletion) reaches threshold (0 disables)")
+}
+
+type riskyChange struct {
+	Reason      string
+	Description string
+}
+
+// Confirm summarizes high-risk changes and asks for confirmation;
+// app name has to be typed once threshold is reached. Confirmation
+// is not asked in non-interactive mode (e.g. --yes).
+func (s RiskFlags) Confirm(ui ui.UI, appName string, changes []*ctlcap.ClusterChange) error {
+	riskyChanges := newRiskyChanges(changes)
+
+	if len(riskyChanges) > 0 {
+		warningStyle := theme.Current().Warning
+		ui.PrintLinef("%s", warningStyle.Sprintf("High-risk changes (%d):", len(riskyChanges)))
+		for _, change := range riskyChanges {
+			ui.PrintLinef("- %s: %s", change.Reason, change.Description)
+		}
+		ui.PrintLinef("")
+	}
+
+	if s.ConfirmThreshold <= 0 || len(riskyChanges) < s.ConfirmThreshold || !ui.IsInteractive() {
+		return ui.AskForConfirmation()
+	}
+
+	typedName, err := ui.AskForText(fmt.Sprintf("Type app name '%s' to confirm", appName))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(typedName) != appName {
+		return fmt.Errorf("Stopped due to typed app name not matching '%s'", appName)
+	}
+	return nil
+}
+
+func newRiskyChanges(changes []*ctlcap.ClusterChange) []riskyChange {
+	var result []riskyChange
+
+	for _, change := range changes {
+		res := change.Resource()
+		gk := res.GroupKind()
+
+		switch change.ApplyOp() {
+		case ctlcap.ClusterChangeApplyOpDelete:
+			if _, found := statefulGKs[gk]; found {
+				result = append(result, riskyChange{"delete stateful resource", res.Description()})
+			}
+			if gk == namespaceGK {
+				result = append(result, riskyChange{"delete namespace", res.Description()})
+			}
+			if gk == crdGK {
+				result = append(result, riskyChange{"delete CRD (deletes all its custom resources)", res.Description()})
+			}
+
+		case ctlcap.ClusterChangeApplyOpUpdate:
+			if gk == crdGK {
+				result = append(result, riskyChange{"update CRD", res.Description()})
+			}
+		}
+	}
+
+	return result
+}
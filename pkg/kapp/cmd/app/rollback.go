@@ -0,0 +1,187 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type RollbackOptions struct {
+	*DeployOptions
+
+	ToChange     string
+	FromSnapshot bool
+}
+
+func NewRollbackOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *RollbackOptions {
+	return &RollbackOptions{DeployOptions: NewDeployOptions(ui, depsFactory, logger)}
+}
+
+func NewRollbackCmd(o *RollbackOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := NewDeployCmd(o.DeployOptions, flagsFactory)
+	cmd.Use = "rollback"
+	cmd.Aliases = []string{"rb"}
+	cmd.Short = "Rollback app to resources recorded in a previous app change"
+	cmd.RunE = func(_ *cobra.Command, _ []string) error { return o.Run() }
+	cmd.Example = `
+  # Rollback app 'app1' to previous successful app change
+  kapp rollback -a app1
+
+  # Rollback app 'app1' to particular app change (see 'kapp app-change list')
+  kapp rollback -a app1 --to-change app1-change-7xbkx
+
+  # Restore resources of app 'app1' to the state captured before last change
+  # (last change must have been deployed with --snapshot)
+  kapp rollback -a app1 --from-snapshot`
+
+	cmd.Flags().StringVar(&o.ToChange, "to-change", "",
+		"Set app change name to rollback to (defaults to previous successful app change)")
+	cmd.Flags().BoolVar(&o.FromSnapshot, "from-snapshot", false,
+		"Restore resources to cluster state captured before app change was applied (with --to-change defaults to last app change with snapshot); "+
+			"resources created by app change are not deleted")
+
+	// Resources come from recorded app change
+	for _, name := range []string{"file", "into-ns", "map-ns"} {
+		cmd.Flags().MarkHidden(name)
+	}
+
+	return cmd
+}
+
+func (o *RollbackOptions) Run() error {
+	app, _, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	changes, err := app.Changes()
+	if err != nil {
+		return err
+	}
+
+	if o.FromSnapshot {
+		return o.runFromSnapshot(changes)
+	}
+
+	change, input, err := o.targetChange(changes)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Rolling back to app change '%s' (%s)", change.Name(), change.Meta().Description)
+
+	o.DeployFlags.IntoNamespace = input.IntoNamespace
+	o.DeployFlags.MapNamespaces = input.MapNamespaces
+
+	// Empty (but non-nil) list indicates that files should not be read
+	o.planInputResources = append([]ctlres.Resource{}, input.Resources...)
+	o.changeOperation = ctlapp.ChangeOperationRollback
+
+	return o.DeployOptions.Run()
+}
+
+func (o *RollbackOptions) runFromSnapshot(changes []ctlapp.Change) error {
+	change, snapshot, err := o.targetSnapshotChange(changes)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Restoring snapshot taken before app change '%s' (%s)", change.Name(), change.Meta().Description)
+
+	// Snapshot only includes resources that were updated or deleted,
+	// hence other resources should be kept as is
+	o.DeployFlags.Patch = true
+	o.skipRecordingInput = true
+	o.planInputResources = append([]ctlres.Resource{}, snapshot...)
+	o.changeOperation = ctlapp.ChangeOperationRollback
+
+	return o.DeployOptions.Run()
+}
+
+func (o *RollbackOptions) targetSnapshotChange(changes []ctlapp.Change) (ctlapp.Change, []ctlres.Resource, error) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+
+		if len(o.ToChange) > 0 && change.Name() != o.ToChange {
+			continue
+		}
+
+		snapshot, err := change.Snapshot()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case len(snapshot) > 0:
+			return change, snapshot, nil
+		case len(o.ToChange) > 0:
+			return nil, nil, fmt.Errorf("Expected app change '%s' to have recorded snapshot", o.ToChange)
+		}
+	}
+
+	if len(o.ToChange) > 0 {
+		return nil, nil, fmt.Errorf("Expected to find app change '%s'", o.ToChange)
+	}
+	return nil, nil, fmt.Errorf("Expected to find app change with recorded snapshot (deploy with --snapshot)")
+}
+
+func (o *RollbackOptions) targetChange(changes []ctlapp.Change) (ctlapp.Change, ctlapp.ChangeInput, error) {
+	if len(o.ToChange) > 0 {
+		for _, change := range changes {
+			if change.Name() == o.ToChange {
+				return o.changeWithInput(change)
+			}
+		}
+		return nil, ctlapp.ChangeInput{}, fmt.Errorf("Expected to find app change '%s'", o.ToChange)
+	}
+
+	// Changes are sorted oldest first; skip last change
+	// since it represents currently deployed state
+	for i := len(changes) - 2; i >= 0; i-- {
+		change := changes[i]
+		meta := change.Meta()
+
+		if meta.Successful == nil || !*meta.Successful {
+			continue
+		}
+
+		input, err := change.Input()
+		if err != nil {
+			return nil, ctlapp.ChangeInput{}, err
+		}
+		if input != nil {
+			return change, *input, nil
+		}
+	}
+
+	return nil, ctlapp.ChangeInput{}, fmt.Errorf(
+		"Expected to find previous successful app change with recorded resources")
+}
+
+func (o *RollbackOptions) changeWithInput(change ctlapp.Change) (ctlapp.Change, ctlapp.ChangeInput, error) {
+	meta := change.Meta()
+
+	if meta.Successful == nil || !*meta.Successful {
+		return nil, ctlapp.ChangeInput{}, fmt.Errorf(
+			"Expected app change '%s' to be successful to rollback to it", change.Name())
+	}
+
+	input, err := change.Input()
+	if err != nil {
+		return nil, ctlapp.ChangeInput{}, err
+	}
+	if input == nil {
+		return nil, ctlapp.ChangeInput{}, fmt.Errorf(
+			"Expected app change '%s' to have recorded resources to rollback to it", change.Name())
+	}
+
+	return change, *input, nil
+}
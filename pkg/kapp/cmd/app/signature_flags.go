@@ -0,0 +1,63 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/gitinput"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/ociinput"
+)
+
+type SignatureFlags struct {
+	Verify bool
+
+	CosignKey        string
+	CosignIdentity   string
+	CosignOIDCIssuer string
+	CosignBinary     string
+
+	AllowedSignersFile string
+}
+
+func (s *SignatureFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.Verify, "verify-signature", false,
+		"Verify signatures of oci:// and git+ files before deploying (other files are not allowed)")
+	cmd.Flags().StringVar(&s.CosignKey, "verify-signature-cosign-key", "",
+		"Set path (or KMS URI) of cosign public key used to verify OCI artifacts")
+	cmd.Flags().StringVar(&s.CosignIdentity, "verify-signature-cosign-identity", "",
+		"Set expected signing identity for keyless verification of OCI artifacts")
+	cmd.Flags().StringVar(&s.CosignOIDCIssuer, "verify-signature-cosign-oidc-issuer", "",
+		"Set expected OIDC issuer for keyless verification of OCI artifacts")
+	cmd.Flags().StringVar(&s.CosignBinary, "cosign-binary", "cosign", "Set path to cosign binary used to verify signatures")
+	cmd.Flags().StringVar(&s.AllowedSignersFile, "verify-signature-allowed-signers", "",
+		"Set path to ssh allowed signers file used to verify git tags")
+}
+
+func (s SignatureFlags) CosignVerifier(registryFlags RegistryFlags) (ociinput.CosignVerifier, error) {
+	if len(s.CosignKey) == 0 && (len(s.CosignIdentity) == 0 || len(s.CosignOIDCIssuer) == 0) {
+		return ociinput.CosignVerifier{}, fmt.Errorf("Expected --verify-signature-cosign-key or both " +
+			"--verify-signature-cosign-identity and --verify-signature-cosign-oidc-issuer to be specified " +
+			"to verify OCI artifacts")
+	}
+	return ociinput.CosignVerifier{
+		Binary:                s.CosignBinary,
+		Key:                   s.CosignKey,
+		Identity:              s.CosignIdentity,
+		OIDCIssuer:            s.CosignOIDCIssuer,
+		AllowInsecureRegistry: registryFlags.PlainHTTP,
+	}, nil
+}
+
+func (s SignatureFlags) GitResourcesOpts() (gitinput.ResourcesOpts, error) {
+	if !s.Verify {
+		return gitinput.ResourcesOpts{}, nil
+	}
+	if len(s.AllowedSignersFile) == 0 {
+		return gitinput.ResourcesOpts{}, fmt.Errorf(
+			"Expected --verify-signature-allowed-signers to be specified to verify git tags")
+	}
+	return gitinput.ResourcesOpts{AllowedSignersFile: s.AllowedSignersFile}, nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// stderrUI sends all human oriented output to stderr so that
+// stdout only includes structured document (see --output flag).
+// Questions are still asked via parent UI.
+type stderrUI struct {
+	parent ui.UI
+	stderr ui.UI
+}
+
+var _ ui.UI = stderrUI{}
+
+func newStderrUI(parent ui.UI) stderrUI {
+	return stderrUI{parent: parent, stderr: ui.NewWriterUI(os.Stderr, os.Stderr, ui.NewNoopLogger())}
+}
+
+func (u stderrUI) ErrorLinef(pattern string, args ...interface{}) {
+	u.stderr.ErrorLinef(pattern, args...)
+}
+func (u stderrUI) PrintLinef(pattern string, args ...interface{}) {
+	u.stderr.PrintLinef(pattern, args...)
+}
+func (u stderrUI) BeginLinef(pattern string, args ...interface{}) {
+	u.stderr.BeginLinef(pattern, args...)
+}
+func (u stderrUI) EndLinef(pattern string, args ...interface{}) { u.stderr.EndLinef(pattern, args...) }
+func (u stderrUI) PrintBlock(block []byte)                      { u.stderr.PrintBlock(block) }
+func (u stderrUI) PrintErrorBlock(block string)                 { u.stderr.PrintErrorBlock(block) }
+func (u stderrUI) PrintTable(table uitable.Table)               { u.stderr.PrintTable(table) }
+
+func (u stderrUI) AskForText(label string) (string, error) { return u.parent.AskForText(label) }
+func (u stderrUI) AskForChoice(label string, options []string) (int, error) {
+	return u.parent.AskForChoice(label, options)
+}
+func (u stderrUI) AskForPassword(label string) (string, error) { return u.parent.AskForPassword(label) }
+func (u stderrUI) AskForConfirmation() error                   { return u.parent.AskForConfirmation() }
+func (u stderrUI) IsInteractive() bool                         { return u.parent.IsInteractive() }
+func (u stderrUI) Flush()                                      { u.parent.Flush() }
+
+// appChangeResult is printed by deploy and delete commands with --output json|yaml
+type appChangeResult struct {
+	App        appResultMeta        `json:"app"`
+	Operation  string               `json:"operation"`
+	DryRun     bool                 `json:"dryRun,omitempty"`
+	Summary    appChangeSummary     `json:"summary"`
+	Changes    []resourceChange     `json:"changes"`
+	Successful bool                 `json:"successful"`
+	Error      string               `json:"error,omitempty"`
+	LastChange *appResultLastChange `json:"lastChange,omitempty"`
+
+	app            ctlapp.App
+	clusterChanges []*ctlcap.ClusterChange
+	applying       bool
+}
+
+type appResultMeta struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type appChangeSummary struct {
+	Description string         `json:"description,omitempty"`
+	Ops         map[string]int `json:"ops"`
+	WaitOps     map[string]int `json:"waitOps"`
+}
+
+type resourceChange struct {
+	resourceRef
+
+	Op          string `json:"op"`
+	WaitOp      string `json:"waitOp"`
+	ApplyResult string `json:"applyResult,omitempty"`
+	ApplyError  string `json:"applyError,omitempty"`
+	WaitResult  string `json:"waitResult,omitempty"`
+	WaitMessage string `json:"waitMessage,omitempty"`
+	WaitError   string `json:"waitError,omitempty"`
+	NonBlocking bool   `json:"nonBlockingWait,omitempty"`
+}
+
+type resourceRef struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+}
+
+type appResultLastChange struct {
+	Name       string     `json:"name"`
+	Successful *bool      `json:"successful,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+func newAppChangeResult(appName, appNamespace, operation string) *appChangeResult {
+	return &appChangeResult{
+		App:       appResultMeta{Name: appName, Namespace: appNamespace},
+		Operation: operation,
+		Summary:   appChangeSummary{Ops: map[string]int{}, WaitOps: map[string]int{}},
+		Changes:   []resourceChange{},
+	}
+}
+
+// SetChanges records calculated changes; results are filled in by Finish.
+// Methods are no-ops when structured output was not requested (nil result).
+func (r *appChangeResult) SetChanges(app ctlapp.App, clusterChanges []*ctlcap.ClusterChange, desc string) {
+	if r == nil {
+		return
+	}
+	r.app = app
+	r.clusterChanges = clusterChanges
+	r.Summary.Description = desc
+}
+
+// MarkApplying indicates that changes started to be applied
+// hence their results should be included
+func (r *appChangeResult) MarkApplying() {
+	if r == nil {
+		return
+	}
+	r.applying = true
+}
+
+// Finish records outcome of applying changes and resulting app state
+func (r *appChangeResult) Finish(err error) {
+	r.Successful = isSuccessfulChangeErr(err)
+	if !r.Successful {
+		r.Error = err.Error()
+	}
+
+	for _, change := range r.clusterChanges {
+		resChange := resourceChange{
+			resourceRef: newResourceRef(change.Resource()),
+			Op:          string(change.ApplyOp()),
+			WaitOp:      string(change.WaitOp()),
+			NonBlocking: change.IsNonBlockingWait(),
+		}
+
+		r.Summary.Ops[resChange.Op]++
+		r.Summary.WaitOps[resChange.WaitOp]++
+
+		if r.applying {
+			result := change.Result()
+			resChange.ApplyResult = string(result.ApplyState)
+			resChange.ApplyError = result.ApplyError
+			if change.WaitOp() != ctlcap.ClusterChangeWaitOpNoop {
+				resChange.WaitResult = string(result.WaitState)
+				resChange.WaitMessage = result.WaitMessage
+				resChange.WaitError = result.WaitError
+			}
+		}
+
+		r.Changes = append(r.Changes, resChange)
+	}
+
+	if r.app == nil || r.DryRun {
+		return
+	}
+
+	// App may have been deleted (e.g. by delete command)
+	lastChange, lastChangeErr := r.app.LastChange()
+	if lastChangeErr == nil && lastChange != nil {
+		meta := lastChange.Meta()
+		r.LastChange = &appResultLastChange{
+			Name:       lastChange.Name(),
+			Successful: meta.Successful,
+			StartedAt:  nonZeroTime(meta.StartedAt),
+			FinishedAt: nonZeroTime(meta.FinishedAt),
+		}
+	}
+}
+
+// isSuccessfulChangeErr indicates that command did not fail
+// (exit statuses only carry information about pending changes)
+func isSuccessfulChangeErr(err error) bool {
+	var diffExitStatus DeployDiffExitStatus
+	var applyExitStatus DeployApplyExitStatus
+	return err == nil || errors.As(err, &diffExitStatus) || errors.As(err, &applyExitStatus)
+}
+
+// QuietSummary describes changes and their outcome in a single line
+func (r *appChangeResult) QuietSummary() string {
+	desc := "no changes"
+	if len(r.Changes) > 0 {
+		desc = r.Summary.Description
+	}
+
+	var numFailed int
+	for _, change := range r.Changes {
+		if change.ApplyResult == string(ctlcap.ClusterChangeResultStateFailed) ||
+			change.WaitResult == string(ctlcap.ClusterChangeResultStateFailed) {
+			numFailed++
+		}
+	}
+	if numFailed > 0 {
+		desc += fmt.Sprintf(" (%d failed)", numFailed)
+	}
+
+	return fmt.Sprintf("App '%s' (namespace: %s): %s", r.App.Name, r.App.Namespace, desc)
+}
+
+func newResourceRef(res ctlres.Resource) resourceRef {
+	return resourceRef{
+		Namespace:  res.Namespace(),
+		Name:       res.Name(),
+		Kind:       res.Kind(),
+		APIVersion: res.APIVersion(),
+	}
+}
+
+// inspectedResource is printed by inspect command with --output json|yaml
+type inspectedResource struct {
+	resourceRef
+
+	Owner          string     `json:"owner,omitempty"`
+	ReconcileState string     `json:"reconcileState,omitempty"`
+	ReconcileInfo  string     `json:"reconcileInfo,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+}
+
+func newInspectedResource(res ctlres.Resource) inspectedResource {
+	result := inspectedResource{resourceRef: newResourceRef(res)}
+
+	if res.IsProvisioned() {
+		result.Owner = cmdtools.NewValueResourceOwner(res).String()
+
+		stateUI := ctlcap.NewResourceConvergedStateUI(res)
+		result.ReconcileState = stateUI.State
+		result.ReconcileInfo = stateUI.Message
+		result.CreatedAt = nonZeroTime(res.CreatedAt())
+	}
+
+	return result
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	// Version of 'terraform show -json' plan representation that is mimicked
+	terraformPlanFormatVersion = "1.2"
+
+	// Same resource type as used by Terraform Kubernetes provider for arbitrary manifests
+	// so that existing policies (e.g. checking after.kind) can be reused
+	terraformPlanResourceType = "kubernetes_manifest"
+	terraformPlanProviderName = "registry.terraform.io/hashicorp/kubernetes"
+)
+
+var (
+	terraformPlanInvalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+)
+
+// TerraformPlan is a subset of Terraform JSON plan representation
+// sufficient for plan review tooling that inspects resource_changes
+type TerraformPlan struct {
+	FormatVersion   string                    `json:"format_version"`
+	Applyable       bool                      `json:"applyable"`
+	Complete        bool                      `json:"complete"`
+	Errored         bool                      `json:"errored"`
+	ResourceChanges []TerraformResourceChange `json:"resource_changes"`
+}
+
+type TerraformResourceChange struct {
+	Address      string          `json:"address"`
+	Mode         string          `json:"mode"`
+	Type         string          `json:"type"`
+	Name         string          `json:"name"`
+	Index        string          `json:"index"`
+	ProviderName string          `json:"provider_name"`
+	Change       TerraformChange `json:"change"`
+}
+
+type TerraformChange struct {
+	Actions         []string               `json:"actions"`
+	Before          map[string]interface{} `json:"before"`
+	After           map[string]interface{} `json:"after"`
+	AfterUnknown    map[string]interface{} `json:"after_unknown"`
+	BeforeSensitive bool                   `json:"before_sensitive"`
+	AfterSensitive  bool                   `json:"after_sensitive"`
+}
+
+// NewTerraformPlan converts calculated changes; before and after values
+// are compared (i.e. rebased) resources with all diff mask rules applied
+func NewTerraformPlan(appName string, changes []*ctlcap.ClusterChange, maskRules []ctlconf.DiffMaskRule) (TerraformPlan, error) {
+	plan := TerraformPlan{
+		FormatVersion:   terraformPlanFormatVersion,
+		Complete:        true,
+		ResourceChanges: []TerraformResourceChange{},
+	}
+
+	name := terraformPlanInvalidNameChars.ReplaceAllString(appName, "_")
+
+	for _, change := range changes {
+		res := change.Resource()
+		index := ctlres.NewUniqueResourceKey(res).String()
+
+		beforeRes, afterRes, err := change.ConfigurableTextDiff().MaskedResources(maskRules)
+		if err != nil {
+			return TerraformPlan{}, fmt.Errorf("Masking resource '%s': %w", res.Description(), err)
+		}
+
+		actions := terraformPlanActions(change.ApplyOp())
+		if actions[0] != "no-op" {
+			plan.Applyable = true
+		}
+
+		tfChange := TerraformChange{
+			Actions:      actions,
+			AfterUnknown: map[string]interface{}{},
+		}
+
+		if beforeRes != nil {
+			tfChange.Before = beforeRes.UnstructuredObject()
+		}
+
+		switch {
+		case change.ApplyOp() == ctlcap.ClusterChangeApplyOpDelete:
+			// Keep after empty even though compared resource is present
+		case afterRes != nil:
+			tfChange.After = afterRes.UnstructuredObject()
+		default:
+			// Ignored changes do not carry new resource
+			tfChange.After = tfChange.Before
+		}
+
+		plan.ResourceChanges = append(plan.ResourceChanges, TerraformResourceChange{
+			Address:      fmt.Sprintf("%s.%s[%q]", terraformPlanResourceType, name, index),
+			Mode:         "managed",
+			Type:         terraformPlanResourceType,
+			Name:         name,
+			Index:        index,
+			ProviderName: terraformPlanProviderName,
+			Change:       tfChange,
+		})
+	}
+
+	return plan, nil
+}
+
+func terraformPlanActions(op ctlcap.ClusterChangeApplyOp) []string {
+	switch op {
+	case ctlcap.ClusterChangeApplyOpAdd:
+		return []string{"create"}
+	case ctlcap.ClusterChangeApplyOpUpdate:
+		return []string{"update"}
+	case ctlcap.ClusterChangeApplyOpDelete:
+		return []string{"delete"}
+	default:
+		return []string{"no-op"}
+	}
+}
+
+func (p TerraformPlan) Write(path string) error {
+	bs, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Marshaling terraform plan: %w", err)
+	}
+
+	err = os.WriteFile(path, append(bs, '\n'), 0600)
+	if err != nil {
+		return fmt.Errorf("Writing terraform plan file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type WaitOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            Flags
+	ResourceFilterFlags cmdtools.ResourceFilterFlags
+	ApplyFlags          ApplyFlags
+	ResourceTypesFlags  ResourceTypesFlags
+
+	ConfigFiles   []string
+	ClusterConfig bool
+
+	FileSystem fs.FS
+}
+
+func NewWaitOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *WaitOptions {
+	return &WaitOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewWaitCmd(o *WaitOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait",
+		Short: "Wait for app's resources to converge",
+		Long: `Wait for app's resources to converge without applying any changes.
+
+Resources are waited using same wait rules and timeouts as used by deploy.
+Resources that have already converged are skipped. Config files with custom
+wait rules could be provided via -f (only kapp config documents are allowed).`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+			TTYByDefaultKey:          "",
+		},
+		Example: `
+  # Wait for resources of app 'app1' after restarting its deployments
+  kapp wait -a app1
+
+  # Wait with custom wait rules
+  kapp wait -a app1 -f config/kapp-config.yml --wait-timeout 5m`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.ResourceFilterFlags.Set(cmd)
+	o.ApplyFlags.SetWaitingOnly(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	cmd.Flags().StringSliceVarP(&o.ConfigFiles, "file", "f", nil,
+		"Set file with kapp config (format: /tmp/foo, https://..., -) (can repeat)")
+	cmd.Flags().BoolVar(&o.ClusterConfig, "cluster-config", false,
+		"Use kapp config from 'kapp-config' ConfigMaps in kube-system and app namespace")
+	return cmd
+}
+
+func (o *WaitOptions) Run() error {
+	failingAPIServicesPolicy := o.ResourceTypesFlags.FailingAPIServicePolicy()
+
+	app, supportObjs, err := Factory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	exists, notExistsMsg, err := app.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%s", notExistsMsg)
+	}
+
+	meta, err := app.Meta()
+	if err != nil {
+		return err
+	}
+
+	usedGVs, err := app.UsedGVs()
+	if err != nil {
+		return err
+	}
+
+	failingAPIServicesPolicy.MarkRequiredGVs(usedGVs)
+
+	conf, err := o.conf(supportObjs)
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	existingResources, err := supportObjs.IdentifiedResources.List(labelSelector, nil, ctlres.IdentifiedResourcesListOpts{
+		ResourceNamespaces: meta.LastChange.Namespaces})
+	if err != nil {
+		return err
+	}
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return err
+	}
+
+	existingResources = resourceFilter.Apply(existingResources)
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{})
+	changeSetFactory := ctldiff.NewChangeSetFactory(ctldiff.ChangeSetOpts{}, changeFactory)
+
+	var changes []ctldiff.Change
+
+	for _, res := range existingResources {
+		// Transient resources (e.g. Pods of Deployments) are waited via their owners
+		if res.Transient() {
+			continue
+		}
+		// Unchanged resource is waited only if it has not converged yet
+		change, err := changeFactory.NewExactChange(res, res)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, change)
+	}
+
+	msgsUI := cmdcore.NewVerbosityMessagesUI(
+		cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui)), o.depsFactory.Verbosity())
+
+	convergedResFactory := ctlcap.NewConvergedResourceFactory(conf.WaitRules(), ctlcap.ConvergedResourceFactoryOpts{
+		IgnoreFailingAPIServices: o.ResourceTypesFlags.IgnoreFailingAPIServices,
+		WaitForServiceEndpoints:  o.ApplyFlags.WaitForServiceEndpoints,
+	})
+
+	clusterChangeOpts := o.ApplyFlags.ClusterChangeOpts
+	clusterChangeOpts.AppLabelKey = meta.LabelKey
+	clusterChangeOpts.NonBlockingWaitMatcher = conf.NonBlockingWaitMatcher()
+	clusterChangeOpts.WaitBehaviorRules = conf.WaitBehaviorRules()
+
+	clusterChangeFactory := ctlcap.NewClusterChangeFactory(
+		clusterChangeOpts, supportObjs.IdentifiedResources,
+		changeFactory, changeSetFactory, convergedResFactory, msgsUI, conf.DiffMaskRules())
+
+	clusterChangeSet := ctlcap.NewClusterChangeSet(
+		changes, o.ApplyFlags.ClusterChangeSetOpts, clusterChangeFactory,
+		conf.ChangeGroupBindings(), conf.ChangeRuleBindings(), msgsUI, o.logger)
+
+	clusterChanges, clusterChangesGraph, err := clusterChangeSet.Calculate()
+	if err != nil {
+		return err
+	}
+
+	if len(clusterChanges) == 0 {
+		o.ui.PrintLinef("All resources of %s have already converged", app.Description())
+		return nil
+	}
+
+	changeSetView := ctlcap.NewChangeSetView(ctlcap.ClusterChangesAsChangeViews(clusterChanges),
+		conf.DiffMaskRules(), ctlcap.ChangeSetViewOpts{Summary: true})
+	changeSetView.Print(o.ui)
+
+	err = clusterChangeSet.Apply(clusterChangesGraph)
+	if err != nil {
+		return newApplyExitStatus(withChangeProgress(err))
+	}
+	return nil
+}
+
+// conf includes only kapp config documents since wait
+// does not accept resources to be applied
+func (o *WaitOptions) conf(supportObjs FactorySupportObjs) (ctlconf.Conf, error) {
+	var resources []ctlres.Resource
+
+	if o.ClusterConfig {
+		clusterResources, err := clusterConfigResources(supportObjs.CoreClient, o.AppFlags.NamespaceFlags.Name, o.logger)
+		if err != nil {
+			return ctlconf.Conf{}, err
+		}
+		resources = append(resources, clusterResources...)
+	}
+
+	for _, file := range o.ConfigFiles {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return ctlconf.Conf{}, err
+		}
+
+		for _, fileRes := range fileRs {
+			rs, err := fileRes.Resources()
+			if err != nil {
+				return ctlconf.Conf{}, err
+			}
+
+			for _, res := range rs {
+				if !ctlconf.IsConfigResource(res) {
+					return ctlconf.Conf{}, fmt.Errorf("Expected only kapp config documents to be provided "+
+						"to wait command, but found '%s'", res.Description())
+				}
+			}
+			resources = append(resources, rs...)
+		}
+	}
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(resources)
+	return conf, err
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/yttinput"
+)
+
+type YttFlags struct {
+	Enabled    bool
+	DataValues yttinput.DataValues
+}
+
+func (s *YttFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&s.Enabled, "ytt", true,
+		"Render directories (specified via --file) that contain ytt templates before diffing")
+	cmd.Flags().StringArrayVar(&s.DataValues.KVsFromStrings, "ytt-data-value", nil,
+		"Set ytt data value, as string (format: key.subkey=123) (can repeat)")
+	cmd.Flags().StringArrayVar(&s.DataValues.KVsFromYAML, "ytt-data-value-yaml", nil,
+		"Set ytt data value, parsed as YAML (format: key.subkey=true) (can repeat)")
+	cmd.Flags().StringArrayVar(&s.DataValues.FromFiles, "ytt-data-values-file", nil,
+		"Set ytt data values via a YAML file (format: /file/path.yml) (can repeat)")
+}
+
+func (s YttFlags) HasDataValues() bool {
+	return len(s.DataValues.KVsFromStrings) > 0 || len(s.DataValues.KVsFromYAML) > 0 || len(s.DataValues.FromFiles) > 0
+}
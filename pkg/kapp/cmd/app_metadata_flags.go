@@ -0,0 +1,27 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+)
+
+type AppMetadataFlags struct {
+	Storage string
+}
+
+func (f *AppMetadataFlags) Set(cmd *cobra.Command, _ cmdcore.FlagsFactory) {
+	cmd.PersistentFlags().StringVar(&f.Storage, "app-metadata", ctlapp.AppMetadataStorageConfigMap,
+		fmt.Sprintf("Set where app metadata and app changes are stored (%s, %s); "+
+			"'%s' requires CRDs from 'kapp app-metadata-crds' to be installed",
+			ctlapp.AppMetadataStorageConfigMap, ctlapp.AppMetadataStorageCRD, ctlapp.AppMetadataStorageCRD))
+}
+
+func (f *AppMetadataFlags) Configure(depsFactory cmdcore.DepsFactory) {
+	depsFactory.ConfigureAppMetadataStorage(f.Storage)
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package appchange
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+)
+
+type DescribeOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags   cmdapp.Flags
+	ChangeName string
+}
+
+func NewDescribeOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DescribeOptions {
+	return &DescribeOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewDescribeCmd(o *DescribeOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "describe",
+		Aliases: []string{"desc"},
+		Short:   "Describe app change including resources that were deployed",
+		RunE:    func(_ *cobra.Command, _ []string) error { return o.Run() },
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.ChangeName, "change", "", "Set app change name (defaults to last app change)")
+	return cmd
+}
+
+func (o *DescribeOptions) Run() error {
+	app, _, err := cmdapp.Factory(o.depsFactory, o.AppFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	changes, err := app.Changes()
+	if err != nil {
+		return err
+	}
+
+	change, err := o.findChange(changes)
+	if err != nil {
+		return err
+	}
+
+	AppChangesTable{"App change", []ctlapp.Change{change}, TimeFlags{}}.Print(o.ui)
+
+	o.printImages(change.Meta().Images)
+
+	input, err := change.Input()
+	if err != nil {
+		return err
+	}
+	if input == nil {
+		o.ui.PrintLinef("App change does not have recorded resources")
+		return nil
+	}
+
+	// Mask resources based on config that was deployed with them
+	resources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(input.Resources)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range resources {
+		maskedRes, err := ctldiff.NewMaskedResource(res, conf.DiffMaskRules()).Resource()
+		if err != nil {
+			return fmt.Errorf("Masking resource '%s': %w", res.Description(), err)
+		}
+
+		resBs, err := maskedRes.AsYAMLBytes()
+		if err != nil {
+			return err
+		}
+
+		o.ui.PrintBlock(append([]byte("---\n"), resBs...))
+	}
+
+	return nil
+}
+
+func (o *DescribeOptions) printImages(images map[string]string) {
+	if len(images) == 0 {
+		return
+	}
+
+	var origRefs []string
+	for ref := range images {
+		origRefs = append(origRefs, ref)
+	}
+	sort.Strings(origRefs)
+
+	o.ui.PrintLinef("Images:")
+	for _, ref := range origRefs {
+		if images[ref] == ref {
+			o.ui.PrintLinef("- %s", ref)
+		} else {
+			o.ui.PrintLinef("- %s -> %s", ref, images[ref])
+		}
+	}
+	o.ui.PrintLinef("")
+}
+
+func (o *DescribeOptions) findChange(changes []ctlapp.Change) (ctlapp.Change, error) {
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("Expected app to have at least one app change")
+	}
+
+	if len(o.ChangeName) == 0 {
+		// First change is oldest
+		return changes[len(changes)-1], nil
+	}
+
+	for _, change := range changes {
+		if change.Name() == o.ChangeName {
+			return change, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Expected to find app change '%s'", o.ChangeName)
+}
@@ -4,6 +4,8 @@
 package appchange
 
 import (
+	"time"
+
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
 	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
@@ -19,6 +21,7 @@ type GCOptions struct {
 
 	AppFlags cmdapp.Flags
 	Max      int
+	MaxAge   time.Duration
 }
 
 func NewGCOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *GCOptions {
@@ -34,6 +37,7 @@ func NewGCCmd(o *GCOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
 	}
 	o.AppFlags.Set(cmd, flagsFactory)
 	cmd.Flags().IntVar(&o.Max, "max", ctlapp.AppChangesMaxToKeepDefault, "Maximum number of app changes to keep")
+	cmd.Flags().DurationVar(&o.MaxAge, "max-age", 0, "Maximum age of app changes to keep (newest change is always kept)")
 	return cmd
 }
 
@@ -48,13 +52,13 @@ func (o *GCOptions) Run() error {
 
 		err = o.ui.AskForConfirmation()
 		if err != nil {
-			return err
+			return cmdapp.AbortedExitStatus{err}
 		}
 
 		return nil
 	}
 
-	numKept, numDeleted, err := app.GCChanges(o.Max, reviewFunc)
+	numKept, numDeleted, err := app.GCChanges(ctlapp.ChangesRetention{MaxToKeep: o.Max, MaxAge: o.MaxAge}, reviewFunc)
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,7 @@ package appchange
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,8 +23,9 @@ type ListOptions struct {
 	depsFactory cmdcore.DepsFactory
 	logger      logger.Logger
 
-	AppFlags  cmdapp.Flags
-	TimeFlags TimeFlags
+	AppFlags   cmdapp.Flags
+	TimeFlags  TimeFlags
+	TableFlags cmdcore.TableFlags
 }
 
 func NewListOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ListOptions {
@@ -39,6 +41,7 @@ func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	}
 	o.AppFlags.Set(cmd, flagsFactory)
 	o.TimeFlags.Set(cmd)
+	o.TableFlags.Set(cmd)
 	return cmd
 }
 
@@ -73,7 +76,14 @@ func (o *ListOptions) Run() error {
 		}
 	}
 
-	AppChangesTable{"App changes", changes, o.TimeFlags}.Print(o.ui)
+	table := AppChangesTable{"App changes", changes, o.TimeFlags}.Table()
+
+	err = o.TableFlags.Apply(&table)
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintTable(table)
 
 	return nil
 }
@@ -95,6 +105,10 @@ type AppChangesTable struct {
 }
 
 func (t AppChangesTable) Print(ui ui.UI) {
+	ui.PrintTable(t.Table())
+}
+
+func (t AppChangesTable) Table() uitable.Table {
 	nsHeader := uitable.NewHeader("Namespaces")
 	nsHeader.Hidden = true
 
@@ -108,6 +122,7 @@ func (t AppChangesTable) Print(ui ui.UI) {
 			uitable.NewHeader("Finished At"),
 			uitable.NewHeader("Successful"),
 			uitable.NewHeader("Description"),
+			uitable.NewHeader("Metadata"),
 			nsHeader,
 		},
 
@@ -132,9 +147,19 @@ func (t AppChangesTable) Print(ui ui.UI) {
 				Error: change.Meta().Successful == nil || *change.Meta().Successful != true,
 			},
 			uitable.NewValueString(change.Meta().Description),
+			uitable.NewValueStrings(t.metadata(change.Meta().Metadata)),
 			uitable.NewValueString(strings.Join(change.Meta().Namespaces, ",")),
 		})
 	}
 
-	ui.PrintTable(table)
+	return table
+}
+
+func (t AppChangesTable) metadata(metadata map[string]string) []string {
+	var result []string
+	for key, val := range metadata {
+		result = append(result, key+"="+val)
+	}
+	sort.Strings(result)
+	return result
 }
@@ -0,0 +1,129 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package appgroup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	appGroupConfigFileName   = "app-group.yml"
+	appGroupConfigAPIVersion = "kapp.k14s.io/v1alpha1"
+	appGroupConfigKind       = "AppGroupConfig"
+)
+
+// AppGroupConfig is read from app group directory and
+// describes relationships between apps (sub-directories)
+type AppGroupConfig struct {
+	APIVersion string              `json:"apiVersion"`
+	Kind       string              `json:"kind"`
+	Apps       []AppGroupConfigApp `json:"apps,omitempty"`
+}
+
+type AppGroupConfigApp struct {
+	// Name of the sub-directory
+	Name      string   `json:"name"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// NewAppGroupConfigFromDir returns empty config if directory does not include config file
+func NewAppGroupConfigFromDir(dir string) (AppGroupConfig, error) {
+	path := filepath.Join(dir, appGroupConfigFileName)
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return AppGroupConfig{}, nil
+		}
+		return AppGroupConfig{}, fmt.Errorf("Reading app group config '%s': %w", path, err)
+	}
+
+	var config AppGroupConfig
+
+	err = yaml.UnmarshalStrict(bs, &config)
+	if err != nil {
+		return AppGroupConfig{}, fmt.Errorf("Unmarshaling app group config '%s': %w", path, err)
+	}
+
+	if config.APIVersion != appGroupConfigAPIVersion || config.Kind != appGroupConfigKind {
+		return AppGroupConfig{}, fmt.Errorf("Expected app group config '%s' to have apiVersion '%s' and kind '%s'",
+			path, appGroupConfigAPIVersion, appGroupConfigKind)
+	}
+
+	return config, nil
+}
+
+// Order returns apps sorted so that each app comes after its dependencies.
+// Apps without relationship between them are sorted by name.
+func (c AppGroupConfig) Order(apps []appGroupApp) ([]appGroupApp, error) {
+	appsByDir := map[string]appGroupApp{}
+	for _, app := range apps {
+		appsByDir[app.Dir] = app
+	}
+
+	deps := map[string][]string{}
+
+	for _, configApp := range c.Apps {
+		if _, found := appsByDir[configApp.Name]; !found {
+			return nil, fmt.Errorf("Expected app '%s' specified in app group config to have a directory", configApp.Name)
+		}
+		for _, dep := range configApp.DependsOn {
+			if _, found := appsByDir[dep]; !found {
+				return nil, fmt.Errorf("Expected dependency '%s' of app '%s' to have a directory", dep, configApp.Name)
+			}
+		}
+		deps[configApp.Name] = append(deps[configApp.Name], configApp.DependsOn...)
+	}
+
+	var result []appGroupApp
+	done := map[string]bool{}
+
+	for len(result) < len(apps) {
+		var ready []string
+
+		for dir := range appsByDir {
+			if done[dir] {
+				continue
+			}
+			isReady := true
+			for _, dep := range deps[dir] {
+				if !done[dep] {
+					isReady = false
+					break
+				}
+			}
+			if isReady {
+				ready = append(ready, dir)
+			}
+		}
+
+		if len(ready) == 0 {
+			var remaining []string
+			for dir := range appsByDir {
+				if !done[dir] {
+					remaining = append(remaining, dir)
+				}
+			}
+			sort.Strings(remaining)
+			return nil, fmt.Errorf("Expected app group config to not have dependency cycles (found between apps: %s)",
+				strings.Join(remaining, ", "))
+		}
+
+		sort.Strings(ready)
+
+		// Pick one app at a time to keep alphabetical order among independent apps
+		done[ready[0]] = true
+		app := appsByDir[ready[0]]
+		app.DependsOn = deps[ready[0]]
+		result = append(result, app)
+	}
+
+	return result, nil
+}
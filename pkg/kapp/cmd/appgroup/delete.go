@@ -31,6 +31,7 @@ type DeleteOptions struct {
 type DeleteAppFlags struct {
 	DiffFlags  cmdtools.DiffFlags
 	ApplyFlags cmdapp.ApplyFlags
+	LockFlags  cmdapp.LockFlags
 }
 
 func NewDeleteOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DeleteOptions {
@@ -47,6 +48,7 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.AppGroupFlags.Set(cmd, flagsFactory)
 	o.AppFlags.DiffFlags.SetWithPrefix("diff", cmd)
 	o.AppFlags.ApplyFlags.SetWithDefaults("", cmdapp.ApplyFlagsDeleteDefaults, cmd)
+	o.AppFlags.LockFlags.Set(cmd)
 	return cmd
 }
 
@@ -87,6 +89,7 @@ func (o *DeleteOptions) deleteApp(name string) error {
 	}
 	deleteOpts.DiffFlags = o.AppFlags.DiffFlags
 	deleteOpts.ApplyFlags = o.AppFlags.ApplyFlags
+	deleteOpts.LockFlags = o.AppFlags.LockFlags
 
 	return deleteOpts.Run()
 }
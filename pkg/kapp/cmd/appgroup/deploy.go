@@ -8,6 +8,8 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/spf13/cobra"
@@ -34,6 +36,7 @@ type DeployAppFlags struct {
 	DeleteApplyFlags    cmdapp.ApplyFlags
 	DeployFlags         cmdapp.DeployFlags
 	LabelFlags          cmdapp.LabelFlags
+	LockFlags           cmdapp.LockFlags
 }
 
 func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DeployOptions {
@@ -42,9 +45,23 @@ func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.L
 
 func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:         "deploy",
-		Aliases:     []string{"d", "dep"},
-		Short:       "Deploy app group",
+		Use:     "deploy",
+		Aliases: []string{"d", "dep"},
+		Short:   "Deploy app group",
+		Long: `Deploy app group.
+
+Each sub-directory of specified directory is deployed as a separate app.
+Apps are deployed in alphabetical order, unless directory includes 'app-group.yml'
+that declares dependencies between apps:
+
+  apiVersion: kapp.k14s.io/v1alpha1
+  kind: AppGroupConfig
+  apps:
+  - name: app2        # sub-directory name
+    dependsOn: [app1] # deployed after app1; skipped if app1 fails
+
+Apps are deployed after their dependencies. When an app fails to deploy,
+apps depending on it (directly or indirectly) are skipped.`,
 		RunE:        func(_ *cobra.Command, _ []string) error { return o.Run() },
 		Annotations: map[string]string{cmdapp.TTYByDefaultKey: ""},
 	}
@@ -56,6 +73,7 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.AppFlags.DeleteApplyFlags.SetWithDefaults("delete", cmdapp.ApplyFlagsDeleteDefaults, cmd)
 	o.AppFlags.DeployFlags.Set(cmd)
 	o.AppFlags.LabelFlags.Set(cmd)
+	o.AppFlags.LockFlags.Set(cmd)
 	return cmd
 }
 
@@ -71,17 +89,17 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	var exitCode float64
-	// TODO is there some order between apps?
-	for _, appGroupApp := range updatedApps {
-		err := o.deployApp(appGroupApp)
-		if err != nil {
-			if deployErr, ok := err.(cmdapp.DeployDiffExitStatus); ok {
-				exitCode = math.Max(exitCode, float64(deployErr.ExitStatus()))
-			} else {
-				return err
-			}
-		}
+	if o.DeployFlags.Concurrency < 1 {
+		return fmt.Errorf("Expected --app-group-concurrency to be at least 1")
+	}
+	if o.DeployFlags.Concurrency > 1 && o.ui.IsInteractive() {
+		return fmt.Errorf("Expected --yes (-y) to be specified when deploying apps in parallel")
+	}
+
+	exitCode, failedApps := o.deployApps(updatedApps)
+
+	if len(failedApps) > 0 {
+		return fmt.Errorf("Deploying app group '%s':\n%s", o.AppGroupFlags.Name, strings.Join(failedApps, "\n"))
 	}
 
 	supportObjs, err := cmdapp.FactoryClients(o.depsFactory, o.AppGroupFlags.NamespaceFlags, o.AppGroupFlags.AppNamespace, cmdapp.ResourceTypesFlags{}, o.logger)
@@ -94,14 +112,17 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	updatedAppNames := map[string]struct{}{}
+	for _, app := range updatedApps {
+		updatedAppNames[app.Name] = struct{}{}
+	}
+
 	// Delete apps that no longer are present in directories
 	for _, app := range existingAppsInGroup {
-		for _, v := range updatedApps {
-			if app.Name() == v.Name {
-				err := o.deleteApp(app.Name())
-				if err != nil {
-					return err
-				}
+		if _, found := updatedAppNames[app.Name()]; !found {
+			err := o.deleteApp(app.Name())
+			if err != nil {
+				return err
 			}
 		}
 	}
@@ -114,9 +135,96 @@ func (o *DeployOptions) Run() error {
 	return nil
 }
 
+type appDeployResult struct {
+	App appGroupApp
+	Err error
+}
+
+// deployApps deploys apps after their dependencies (up to configured number
+// of apps at a time); dependents of failed apps are skipped
+func (o *DeployOptions) deployApps(apps []appGroupApp) (float64, []string) {
+	var exitCode float64
+	var failedApps []string
+
+	pending := append([]appGroupApp{}, apps...)
+	finishedAppDirs := map[string]bool{}
+	failedAppDirs := map[string]bool{}
+	numRunning := 0
+
+	parallel := o.DeployFlags.Concurrency > 1
+	uiLock := &sync.Mutex{}
+	resultCh := make(chan appDeployResult, len(apps))
+
+	for len(pending) > 0 || numRunning > 0 {
+		var stillPending []appGroupApp
+
+		for _, app := range pending {
+			var failedDeps []string
+			allDepsFinished := true
+
+			for _, dep := range app.DependsOn {
+				if failedAppDirs[dep] {
+					failedDeps = append(failedDeps, dep)
+				}
+				if !finishedAppDirs[dep] {
+					allDepsFinished = false
+				}
+			}
+
+			switch {
+			case len(failedDeps) > 0:
+				o.ui.PrintLinef("--- skipping app '%s' because its dependencies failed: %s",
+					app.Name, strings.Join(failedDeps, ", "))
+				finishedAppDirs[app.Dir] = true
+				failedAppDirs[app.Dir] = true
+				failedApps = append(failedApps, fmt.Sprintf("- %s: skipped (dependencies failed)", app.Name))
+
+			case !allDepsFinished || numRunning >= o.DeployFlags.Concurrency:
+				stillPending = append(stillPending, app)
+
+			default:
+				numRunning++
+				app := app // copy
+
+				if parallel {
+					go func() { resultCh <- appDeployResult{app, o.deployApp(app, newPrefixedUI(o.ui, app.Dir, uiLock))} }()
+				} else {
+					resultCh <- appDeployResult{app, o.deployApp(app, o.ui)}
+				}
+			}
+		}
+
+		pending = stillPending
+
+		if numRunning == 0 {
+			continue
+		}
+
+		result := <-resultCh
+		numRunning--
+		finishedAppDirs[result.App.Dir] = true
+
+		if result.Err != nil {
+			if deployErr, ok := result.Err.(cmdapp.DeployDiffExitStatus); ok {
+				exitCode = math.Max(exitCode, float64(deployErr.ExitStatus()))
+			} else {
+				o.ui.ErrorLinef("--- deploying app '%s' failed: %s", result.App.Name, result.Err)
+				failedAppDirs[result.App.Dir] = true
+				failedApps = append(failedApps, fmt.Sprintf("- %s: %s", result.App.Name, result.Err))
+			}
+		}
+	}
+
+	return exitCode, failedApps
+}
+
 type appGroupApp struct {
 	Name string
 	Path string
+
+	// Dir is a name of the app sub-directory
+	Dir       string
+	DependsOn []string
 }
 
 func (o *DeployOptions) appsToUpdate() ([]appGroupApp, error) {
@@ -136,17 +244,24 @@ func (o *DeployOptions) appsToUpdate() ([]appGroupApp, error) {
 		app := appGroupApp{
 			Name: fmt.Sprintf("%s-%s", o.AppGroupFlags.Name, fi.Name()),
 			Path: filepath.Join(dir, fi.Name()),
+			Dir:  fi.Name(),
 		}
 		applications = append(applications, app)
 	}
-	return applications, nil
+
+	config, err := NewAppGroupConfigFromDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Order(applications)
 }
 
-func (o *DeployOptions) deployApp(app appGroupApp) error {
-	o.ui.PrintLinef("--- deploying app '%s' (namespace: %s) from %s",
+func (o *DeployOptions) deployApp(app appGroupApp, ui ui.UI) error {
+	ui.PrintLinef("--- deploying app '%s' (namespace: %s) from %s",
 		app.Name, o.appNamespace(), app.Path)
 
-	deployOpts := cmdapp.NewDeployOptions(o.ui, o.depsFactory, o.logger)
+	deployOpts := cmdapp.NewDeployOptions(ui, o.depsFactory, o.logger)
 	deployOpts.AppFlags = cmdapp.Flags{
 		Name:           app.Name,
 		NamespaceFlags: o.AppGroupFlags.NamespaceFlags,
@@ -159,13 +274,18 @@ func (o *DeployOptions) deployApp(app appGroupApp) error {
 	deployOpts.ResourceFilterFlags = o.AppFlags.ResourceFilterFlags
 	deployOpts.ApplyFlags = o.AppFlags.ApplyFlags
 	deployOpts.DeployFlags = o.AppFlags.DeployFlags
+	deployOpts.LockFlags = o.AppFlags.LockFlags
 
 	deployOpts.LabelFlags = o.AppFlags.LabelFlags
 	deployOpts.LabelFlags.Labels = append(
 		deployOpts.LabelFlags.Labels,
 		fmt.Sprintf("%s=%s", appGroupAnnKey, o.AppGroupFlags.Name))
 
-	return deployOpts.Run()
+	err := deployOpts.Run()
+	if err == nil {
+		ui.PrintLinef("--- deployed app '%s'", app.Name)
+	}
+	return err
 }
 
 func (o *DeployOptions) deleteApp(name string) error {
@@ -180,6 +300,7 @@ func (o *DeployOptions) deleteApp(name string) error {
 	}
 	deleteOpts.DiffFlags = o.AppFlags.DiffFlags
 	deleteOpts.ApplyFlags = o.AppFlags.DeleteApplyFlags
+	deleteOpts.LockFlags = o.AppFlags.LockFlags
 
 	return deleteOpts.Run()
 }
@@ -8,9 +8,11 @@ import (
 )
 
 type DeployFlags struct {
-	Directory string
+	Directory   string
+	Concurrency int
 }
 
 func (s *DeployFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&s.Directory, "directory", "d", "", "Set directory (format: /tmp/foo)")
+	cmd.Flags().IntVar(&s.Concurrency, "app-group-concurrency", 1, "Maximum number of independent apps to deploy in parallel (requires --yes if greater than 1)")
 }
@@ -0,0 +1,102 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package appgroup
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+// prefixedUI prefixes each line with app name so that
+// output of apps deployed in parallel could be told apart.
+// Output is serialized via lock shared between all apps.
+type prefixedUI struct {
+	parent ui.UI
+	prefix string
+	lock   *sync.Mutex
+}
+
+var _ ui.UI = prefixedUI{}
+
+func newPrefixedUI(parent ui.UI, name string, lock *sync.Mutex) prefixedUI {
+	return prefixedUI{parent: parent, prefix: fmt.Sprintf("[%s] ", name), lock: lock}
+}
+
+func (u prefixedUI) ErrorLinef(pattern string, args ...interface{}) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.parent.ErrorLinef("%s%s", u.prefix, fmt.Sprintf(pattern, args...))
+}
+
+func (u prefixedUI) PrintLinef(pattern string, args ...interface{}) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.parent.PrintLinef("%s%s", u.prefix, fmt.Sprintf(pattern, args...))
+}
+
+// BeginLinef prints whole line since other apps may print in between begin and end
+func (u prefixedUI) BeginLinef(pattern string, args ...interface{}) {
+	u.PrintLinef("%s", strings.TrimSuffix(fmt.Sprintf(pattern, args...), "\n"))
+}
+
+func (u prefixedUI) EndLinef(pattern string, args ...interface{}) {
+	msg := strings.TrimSuffix(fmt.Sprintf(pattern, args...), "\n")
+	if len(msg) > 0 {
+		u.PrintLinef("%s", msg)
+	}
+}
+
+func (u prefixedUI) PrintBlock(block []byte) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.parent.PrintBlock([]byte(u.prefixLines(string(block))))
+}
+
+func (u prefixedUI) PrintErrorBlock(block string) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.parent.PrintErrorBlock(u.prefixLines(block))
+}
+
+func (u prefixedUI) PrintTable(table uitable.Table) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	table.Title = u.prefix + table.Title
+	u.parent.PrintTable(table)
+}
+
+func (u prefixedUI) AskForText(label string) (string, error) {
+	return u.parent.AskForText(u.prefix + label)
+}
+
+func (u prefixedUI) AskForChoice(label string, options []string) (int, error) {
+	return u.parent.AskForChoice(u.prefix+label, options)
+}
+
+func (u prefixedUI) AskForPassword(label string) (string, error) {
+	return u.parent.AskForPassword(u.prefix + label)
+}
+
+func (u prefixedUI) AskForConfirmation() error { return u.parent.AskForConfirmation() }
+func (u prefixedUI) IsInteractive() bool       { return u.parent.IsInteractive() }
+
+func (u prefixedUI) Flush() {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.parent.Flush()
+}
+
+func (u prefixedUI) prefixLines(str string) string {
+	lines := strings.SplitAfter(str, "\n")
+	for i, line := range lines {
+		if len(line) > 0 {
+			lines[i] = u.prefix + line
+		}
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Kapp config",
+		Annotations: map[string]string{
+			cmdcore.MiscHelpGroup.Key: cmdcore.MiscHelpGroup.Value,
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type EffectiveOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+
+	FileFlags     cmdtools.FileFlags
+	StrictConfig  bool
+	ConfigEnv     bool
+	ConfigProfile string
+	ShowDefaults  bool
+
+	FileSystem fs.FS
+}
+
+func NewEffectiveOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *EffectiveOptions {
+	return &EffectiveOptions{ui: ui, depsFactory: depsFactory}
+}
+
+func NewEffectiveCmd(o *EffectiveOptions, _ cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "effective",
+		Short: "Show effective kapp config",
+		Long: `Show effective kapp config.
+
+Configs are layered in order of their priority (configs with same priority keep
+the order in which they were provided, after default config). Rule kinds listed
+in replaceRules of a config drop rules of that kind from configs layered before it.`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Show configs included with app manifests in the order they are applied
+  kapp config effective -f config/`,
+	}
+	o.FileFlags.Set(cmd)
+	cmd.Flags().BoolVar(&o.StrictConfig, "strict-config", false, "Parse kapp config strictly (unknown fields fail parsing)")
+	cmd.Flags().StringVar(&o.ConfigProfile, "config-profile", "", "Activate kapp configs tagged with given profile")
+	cmd.Flags().BoolVar(&o.ConfigEnv, "config-env", false, "Substitute ${VAR} references in kapp config with environment variables")
+	cmd.Flags().BoolVar(&o.ShowDefaults, "defaults", false, "Include default config")
+	return cmd
+}
+
+func (o *EffectiveOptions) Run() error {
+	if len(o.FileFlags.Files) == 0 {
+		return fmt.Errorf("Expected at least one --file (-f) specified with a file or directory path")
+	}
+
+	var resources []ctlres.Resource
+
+	for _, file := range o.FileFlags.Files {
+		fileRs, err := ctlres.NewFileResources(o.FileSystem, file)
+		if err != nil {
+			return err
+		}
+
+		for _, fileRes := range fileRs {
+			rs, err := fileRes.Resources()
+			if err != nil {
+				return err
+			}
+			resources = append(resources, rs...)
+		}
+	}
+
+	// ConfigMaps cannot be imported since cluster is not accessed
+	resources, err := ctlconf.ResolveConfigFrom(resources, ctlconf.ConfigFromOpts{FileSystem: o.FileSystem})
+	if err != nil {
+		return err
+	}
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaultsAndOpts(
+		resources, ctlconf.ConfOpts{
+			StrictParsing:   o.StrictConfig,
+			EnvSubstitution: o.ConfigEnv,
+			Profile:         o.ConfigProfile,
+		})
+	if err != nil {
+		return err
+	}
+
+	for i, config := range conf.Configs() {
+		if !o.ShowDefaults && config.IsDefault() {
+			continue
+		}
+
+		bs, err := config.EffectiveYAML()
+		if err != nil {
+			return fmt.Errorf("Serializing config '%s': %w", config.Description(), err)
+		}
+
+		o.ui.PrintBlock([]byte(fmt.Sprintf("---\n# %d: %s (priority: %d)\n", i, config.Description(), config.Priority)))
+		o.ui.PrintBlock(bs)
+	}
+
+	return nil
+}
@@ -11,14 +11,30 @@ import (
 
 type CancelSignals struct{}
 
-func (CancelSignals) Watch(stopFunc func()) {
+// Watch calls stopFunc once when one of cancel signals is received.
+// Subsequent signals are not intercepted (i.e. second Ctrl-C terminates process).
+// Returned function stops watching.
+func (CancelSignals) Watch(stopFunc func()) func() {
 	signalCh := make(chan os.Signal, 1)
-	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGHUP)
+	doneCh := make(chan struct{})
+
+	signal.Notify(signalCh, syscall.SIGINT, syscall.SIGHUP, syscall.SIGTERM)
+
 	go func() {
 		defer signal.Stop(signalCh)
 		select {
 		case <-signalCh:
 			stopFunc()
+		case <-doneCh:
 		}
 	}()
+
+	var stopped bool
+
+	return func() {
+		if !stopped {
+			stopped = true
+			close(doneCh)
+		}
+	}
 }
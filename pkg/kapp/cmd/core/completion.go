@@ -0,0 +1,96 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cppforlife/cobrautil"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CompletionFunc returns flag values that could be used for shell completion
+type CompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completionTimeout keeps shell responsive when cluster is slow or unreachable
+const completionTimeout = 2 * time.Second
+
+// NewClusterCompletionFunc wraps function that fetches values from the cluster.
+// Flags are resolved first (e.g. kubeconfig, namespace) since completion
+// does not run regular command hooks; errors (including timing out
+// after completionTimeout) result in no suggestions.
+func NewClusterCompletionFunc(depsFactory DepsFactory, valuesFunc func(context.Context) ([]string, error)) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// Target cluster output would be mistaken for suggestions
+		depsFactory.ConfigurePrintTarget(false)
+		depsFactory.ConfigureWarnings(false)
+
+		err := cobrautil.ResolveFlagsForCmd(cmd, args)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		values, err := completionValues(valuesFunc)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var result []string
+		for _, val := range values {
+			if strings.HasPrefix(val, toComplete) {
+				result = append(result, val)
+			}
+		}
+		return result, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionValues does not wait for valuesFunc past timeout
+// even if it does not respect context (process exits after completing)
+func completionValues(valuesFunc func(context.Context) ([]string, error)) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	type valuesResult struct {
+		values []string
+		err    error
+	}
+
+	resultCh := make(chan valuesResult, 1)
+
+	go func() {
+		values, err := valuesFunc(ctx)
+		resultCh <- valuesResult{values, err}
+	}()
+
+	select {
+	case result := <-resultCh:
+		return result.values, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func newNamespaceCompletionFunc(depsFactory DepsFactory) CompletionFunc {
+	return NewClusterCompletionFunc(depsFactory, func(ctx context.Context) ([]string, error) {
+		coreClient, err := depsFactory.CoreClient()
+		if err != nil {
+			return nil, err
+		}
+
+		nsList, err := coreClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		var result []string
+		for _, ns := range nsList.Items {
+			result = append(result, ns.Name)
+		}
+		return result, nil
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionValues(t *testing.T) {
+	values, err := completionValues(func(context.Context) ([]string, error) {
+		return []string{"app1", "app2"}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"app1", "app2"}, values)
+
+	_, err = completionValues(func(context.Context) ([]string, error) {
+		return nil, fmt.Errorf("unreachable")
+	})
+	require.EqualError(t, err, "unreachable")
+}
+
+func TestCompletionValuesTimeout(t *testing.T) {
+	releaseCh := make(chan struct{})
+	defer close(releaseCh)
+
+	startedAt := time.Now()
+
+	// Does not respect context (e.g. listing apps)
+	_, err := completionValues(func(context.Context) ([]string, error) {
+		<-releaseCh
+		return []string{"app"}, nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(startedAt), completionTimeout+time.Second)
+}
@@ -6,9 +6,13 @@ package core
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
 
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -18,8 +22,13 @@ type ConfigFactory interface {
 	ConfigureContextResolver(func() (string, error))
 	ConfigureYAMLResolver(func() (string, error))
 	ConfigureClient(float32, int)
+	ConfigureClientMaxInflight(int)
+	ConfigureClientProtobuf(bool)
 	RESTConfig() (*rest.Config, error)
 	DefaultNamespace() (string, error)
+
+	// WithContext returns copy of factory that builds configs for given kubeconfig context
+	WithContext(context string) ConfigFactory
 }
 
 type ConfigFactoryImpl struct {
@@ -27,8 +36,15 @@ type ConfigFactoryImpl struct {
 	contextResolverFunc func() (string, error)
 	yamlResolverFunc    func() (string, error)
 
-	qps   float32
-	burst int
+	qps         float32
+	burst       int
+	maxInflight int
+	protobuf    bool
+
+	// Throttle is shared between all produced configs
+	// so that limit applies across all clients
+	inflightThrottleOnce sync.Once
+	inflightThrottle     util.Throttle
 }
 
 var _ ConfigFactory = &ConfigFactoryImpl{}
@@ -54,6 +70,27 @@ func (f *ConfigFactoryImpl) ConfigureClient(qps float32, burst int) {
 	f.burst = burst
 }
 
+func (f *ConfigFactoryImpl) ConfigureClientMaxInflight(maxInflight int) {
+	f.maxInflight = maxInflight
+}
+
+func (f *ConfigFactoryImpl) ConfigureClientProtobuf(protobuf bool) {
+	f.protobuf = protobuf
+}
+
+func (f *ConfigFactoryImpl) WithContext(context string) ConfigFactory {
+	// Throttle is not shared since limit applies per cluster
+	return &ConfigFactoryImpl{
+		pathResolverFunc:    f.pathResolverFunc,
+		contextResolverFunc: func() (string, error) { return context, nil },
+		yamlResolverFunc:    f.yamlResolverFunc,
+		qps:                 f.qps,
+		burst:               f.burst,
+		maxInflight:         f.maxInflight,
+		protobuf:            f.protobuf,
+	}
+}
+
 func (f *ConfigFactoryImpl) RESTConfig() (*rest.Config, error) {
 	isExplicitYAMLConfig, config, err := f.clientConfig()
 	if err != nil {
@@ -83,6 +120,21 @@ func (f *ConfigFactoryImpl) RESTConfig() (*rest.Config, error) {
 		restConfig.Burst = f.burst
 	}
 
+	if f.protobuf {
+		// JSON is used for types that cannot be encoded in protobuf (e.g. CRs)
+		restConfig.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+		restConfig.ContentType = runtime.ContentTypeProtobuf
+	}
+
+	if f.maxInflight > 0 {
+		f.inflightThrottleOnce.Do(func() {
+			f.inflightThrottle = util.NewThrottle(f.maxInflight)
+		})
+		restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return maxInflightRoundTripper{throttle: f.inflightThrottle, delegate: rt}
+		})
+	}
+
 	return restConfig, nil
 }
 
@@ -6,10 +6,13 @@ package core
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -19,6 +22,27 @@ type DepsFactory interface {
 	DynamicClient(opts DynamicClientOpts) (dynamic.Interface, error)
 	CoreClient() (kubernetes.Interface, error)
 	ConfigureWarnings(warnings bool)
+
+	AppMetadataStorage() string
+	ConfigureAppMetadataStorage(storage string)
+
+	// ConfigurePrintTarget controls whether target cluster is printed
+	// (e.g. it's disabled when command outputs JSON or YAML documents)
+	ConfigurePrintTarget(print bool)
+
+	// Impersonation applies to clients created after it's configured
+	Impersonation() rest.ImpersonationConfig
+	ConfigureImpersonation(rest.ImpersonationConfig)
+	// WithImpersonation returns copy of factory that builds clients with given identity
+	// without affecting clients built by this factory (e.g. in other goroutines)
+	WithImpersonation(rest.ImpersonationConfig) DepsFactory
+	// WithContext returns copy of factory that builds clients for given kubeconfig context
+	// (target cluster is printed again for clients built by returned factory)
+	WithContext(context string) DepsFactory
+
+	// Verbosity controls how much progress output commands print (see Verbosity* consts)
+	Verbosity() int
+	ConfigureVerbosity(level int)
 }
 
 type DepsFactoryImpl struct {
@@ -27,6 +51,11 @@ type DepsFactoryImpl struct {
 	printTargetOnce *sync.Once
 
 	Warnings bool
+
+	appMetadataStorage string
+	skipPrintTarget    bool
+	impersonation      rest.ImpersonationConfig
+	verbosity          int
 }
 
 var _ DepsFactory = &DepsFactoryImpl{}
@@ -35,7 +64,8 @@ func NewDepsFactoryImpl(configFactory ConfigFactory, ui ui.UI) *DepsFactoryImpl
 	return &DepsFactoryImpl{
 		configFactory:   configFactory,
 		ui:              ui,
-		printTargetOnce: &sync.Once{}}
+		printTargetOnce: &sync.Once{},
+		verbosity:       VerbosityDefault}
 }
 
 type DynamicClientOpts struct {
@@ -50,6 +80,7 @@ func (f *DepsFactoryImpl) DynamicClient(opts DynamicClientOpts) (dynamic.Interfa
 
 	// copy to avoid mutating the passed-in config
 	cpConfig := rest.CopyConfig(config)
+	f.applyImpersonation(cpConfig)
 
 	if opts.Warnings {
 		cpConfig.WarningHandler = f.newWarningHandler()
@@ -57,11 +88,20 @@ func (f *DepsFactoryImpl) DynamicClient(opts DynamicClientOpts) (dynamic.Interfa
 		cpConfig.WarningHandler = rest.NoWarnings{}
 	}
 
-	clientset, err := dynamic.NewForConfig(cpConfig)
+	var clientset dynamic.Interface
+
+	clientset, err = dynamic.NewForConfig(cpConfig)
 	if err != nil {
 		return nil, fmt.Errorf("Building Dynamic clientset: %w", err)
 	}
 
+	if cpConfig.ContentType == runtime.ContentTypeProtobuf {
+		clientset, err = newProtobufDynamicClient(clientset, cpConfig)
+		if err != nil {
+			return nil, fmt.Errorf("Building Dynamic clientset: %w", err)
+		}
+	}
+
 	f.printTarget(config)
 
 	return clientset, nil
@@ -73,7 +113,10 @@ func (f *DepsFactoryImpl) CoreClient() (kubernetes.Interface, error) {
 		return nil, err
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	cpConfig := rest.CopyConfig(config)
+	f.applyImpersonation(cpConfig)
+
+	clientset, err := kubernetes.NewForConfig(cpConfig)
 	if err != nil {
 		return nil, fmt.Errorf("Building Core clientset: %w", err)
 	}
@@ -87,7 +130,55 @@ func (f *DepsFactoryImpl) ConfigureWarnings(warnings bool) {
 	f.Warnings = warnings
 }
 
+func (f *DepsFactoryImpl) AppMetadataStorage() string {
+	return f.appMetadataStorage
+}
+
+func (f *DepsFactoryImpl) ConfigureAppMetadataStorage(storage string) {
+	f.appMetadataStorage = storage
+}
+
+func (f *DepsFactoryImpl) ConfigurePrintTarget(print bool) {
+	f.skipPrintTarget = !print
+}
+
+func (f *DepsFactoryImpl) Verbosity() int { return f.verbosity }
+
+func (f *DepsFactoryImpl) ConfigureVerbosity(level int) {
+	f.verbosity = level
+}
+
+func (f *DepsFactoryImpl) Impersonation() rest.ImpersonationConfig {
+	return f.impersonation
+}
+
+func (f *DepsFactoryImpl) ConfigureImpersonation(impersonation rest.ImpersonationConfig) {
+	f.impersonation = impersonation
+}
+
+func (f *DepsFactoryImpl) WithImpersonation(impersonation rest.ImpersonationConfig) DepsFactory {
+	cpFactory := *f
+	cpFactory.impersonation = impersonation
+	return &cpFactory
+}
+
+func (f *DepsFactoryImpl) WithContext(context string) DepsFactory {
+	cpFactory := *f
+	cpFactory.configFactory = f.configFactory.WithContext(context)
+	cpFactory.printTargetOnce = &sync.Once{}
+	return &cpFactory
+}
+
+func (f *DepsFactoryImpl) applyImpersonation(config *rest.Config) {
+	if len(f.impersonation.UserName) > 0 || len(f.impersonation.Groups) > 0 {
+		config.Impersonate = f.impersonation
+	}
+}
+
 func (f *DepsFactoryImpl) printTarget(config *rest.Config) {
+	if f.skipPrintTarget {
+		return
+	}
 	f.printTargetOnce.Do(func() {
 		nodesDesc := f.summarizeNodes(config)
 		if len(nodesDesc) > 0 {
@@ -143,6 +234,8 @@ type uiWriter struct {
 }
 
 func (w uiWriter) Write(data []byte) (int, error) {
-	w.ui.BeginLinef("%s", data)
+	msg := string(data)
+	trimmedMsg := strings.TrimSuffix(msg, "\n")
+	w.ui.BeginLinef("%s%s", theme.Current().Warning.Sprintf("%s", trimmedMsg), strings.TrimPrefix(msg, trimmedMsg))
 	return len(data), nil
 }
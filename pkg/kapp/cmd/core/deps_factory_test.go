@@ -0,0 +1,38 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"k8s.io/client-go/rest"
+)
+
+func TestDepsFactoryWithImpersonationDoesNotModifyOriginal(t *testing.T) {
+	depsFactory := cmdcore.NewDepsFactoryImpl(nil, nil)
+	depsFactory.ConfigureVerbosity(cmdcore.VerbosityDefault)
+
+	var wg sync.WaitGroup
+
+	// Apps within app group may be deployed in parallel with different identities
+	for i := 0; i < 10; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userName := fmt.Sprintf("user%d", i)
+			impersonatingFactory := depsFactory.WithImpersonation(rest.ImpersonationConfig{UserName: userName})
+			require.Equal(t, userName, impersonatingFactory.Impersonation().UserName)
+			require.Equal(t, cmdcore.VerbosityDefault, impersonatingFactory.Verbosity())
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, rest.ImpersonationConfig{}, depsFactory.Impersonation())
+}
@@ -15,3 +15,10 @@ func NewFlagsFactory(configFactory ConfigFactory, depsFactory DepsFactory) Flags
 func (f FlagsFactory) NewNamespaceNameFlag(str *string) *NamespaceNameFlag {
 	return NewNamespaceNameFlag(str, f.configFactory)
 }
+
+func (f FlagsFactory) NewNamespaceCompletionFunc() CompletionFunc {
+	return newNamespaceCompletionFunc(f.depsFactory)
+}
+
+// DepsFactory is used by flags that complete values from the cluster
+func (f FlagsFactory) DepsFactory() DepsFactory { return f.depsFactory }
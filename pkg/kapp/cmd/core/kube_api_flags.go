@@ -8,16 +8,25 @@ import (
 )
 
 type KubeAPIFlags struct {
-	QPS   float32
-	Burst int
+	QPS         float32
+	Burst       int
+	MaxInflight int
+	Protobuf    bool
 }
 
 func (f *KubeAPIFlags) Set(cmd *cobra.Command, _ FlagsFactory) {
 	// Similar names are used by kubelet and other controllers
 	cmd.PersistentFlags().Float32Var(&f.QPS, "kube-api-qps", 1000, "Set Kubernetes API client QPS limit")
 	cmd.PersistentFlags().IntVar(&f.Burst, "kube-api-burst", 1000, "Set Kubernetes API client burst limit")
+	cmd.PersistentFlags().IntVar(&f.MaxInflight, "kube-api-max-inflight", 0,
+		"Set maximum number of concurrent Kubernetes API requests (0 means no limit)")
+	cmd.PersistentFlags().BoolVar(&f.Protobuf, "kube-api-protobuf", false,
+		"Use protobuf when getting and listing built-in resources (only used with Kubernetes 1.29 "+
+			"or older since fields unknown to kapp would be dropped)")
 }
 
 func (f *KubeAPIFlags) Configure(config ConfigFactory) {
 	config.ConfigureClient(f.QPS, f.Burst)
+	config.ConfigureClientMaxInflight(f.MaxInflight)
+	config.ConfigureClientProtobuf(f.Protobuf)
 }
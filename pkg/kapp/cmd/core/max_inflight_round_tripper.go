@@ -0,0 +1,37 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"net/http"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
+)
+
+// maxInflightRoundTripper limits number of concurrent requests
+// made through all clients that share the same throttle.
+// Long running requests (watches, followed logs) are not counted,
+// since they would otherwise hold on to a slot indefinitely.
+type maxInflightRoundTripper struct {
+	throttle util.Throttle
+	delegate http.RoundTripper
+}
+
+var _ http.RoundTripper = maxInflightRoundTripper{}
+
+func (rt maxInflightRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.isLongRunning(req) {
+		return rt.delegate.RoundTrip(req)
+	}
+
+	rt.throttle.Take()
+	defer rt.throttle.Done()
+
+	return rt.delegate.RoundTrip(req)
+}
+
+func (maxInflightRoundTripper) isLongRunning(req *http.Request) bool {
+	query := req.URL.Query()
+	return query.Get("watch") == "true" || query.Get("follow") == "true"
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
+)
+
+type blockingRoundTripper struct {
+	inflight    int32
+	maxInflight int32
+	releaseCh   chan struct{}
+}
+
+func (rt *blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	current := atomic.AddInt32(&rt.inflight, 1)
+	defer atomic.AddInt32(&rt.inflight, -1)
+
+	for {
+		max := atomic.LoadInt32(&rt.maxInflight)
+		if current <= max || atomic.CompareAndSwapInt32(&rt.maxInflight, max, current) {
+			break
+		}
+	}
+
+	<-rt.releaseCh
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestMaxInflightRoundTripperLimitsConcurrentRequests(t *testing.T) {
+	delegate := &blockingRoundTripper{releaseCh: make(chan struct{})}
+	rt := maxInflightRoundTripper{throttle: util.NewThrottle(2), delegate: delegate}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, "https://cluster/api/v1/configmaps", nil)
+			require.NoError(t, err)
+			_, err = rt.RoundTrip(req)
+			require.NoError(t, err)
+		}()
+	}
+
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&delegate.inflight) == 2 },
+		5*time.Second, 10*time.Millisecond)
+
+	// Give remaining requests a chance to (incorrectly) get through
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, int32(2), atomic.LoadInt32(&delegate.inflight))
+
+	close(delegate.releaseCh)
+	wg.Wait()
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&delegate.maxInflight))
+}
+
+func TestMaxInflightRoundTripperDoesNotLimitLongRunningRequests(t *testing.T) {
+	delegate := &blockingRoundTripper{releaseCh: make(chan struct{})}
+	rt := maxInflightRoundTripper{throttle: util.NewThrottle(1), delegate: delegate}
+
+	urls := []string{
+		"https://cluster/api/v1/configmaps?watch=true",
+		"https://cluster/api/v1/namespaces/ns/pods/pod/log?follow=true",
+		"https://cluster/api/v1/configmaps",
+	}
+
+	var wg sync.WaitGroup
+
+	for _, url := range urls {
+		url := url
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			_, err = rt.RoundTrip(req)
+			require.NoError(t, err)
+		}()
+	}
+
+	// Regular request takes the only slot; watch and follow requests go through regardless
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&delegate.inflight) == 3 },
+		5*time.Second, 10*time.Millisecond)
+
+	close(delegate.releaseCh)
+	wg.Wait()
+}
@@ -19,6 +19,7 @@ type NamespaceFlags struct {
 func (s *NamespaceFlags) Set(cmd *cobra.Command, flagsFactory FlagsFactory) {
 	name := flagsFactory.NewNamespaceNameFlag(&s.Name)
 	cmd.Flags().VarP(name, "namespace", "n", "Specified namespace ($KAPP_NAMESPACE or default from kubeconfig)")
+	_ = cmd.RegisterFlagCompletionFunc("namespace", flagsFactory.NewNamespaceCompletionFunc())
 }
 
 type NamespaceNameFlag struct {
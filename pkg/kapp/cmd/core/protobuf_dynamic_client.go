@@ -0,0 +1,238 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	builtinResourceKindsOnce sync.Once
+	builtinResourceKindsMemo map[schema.GroupVersionResource]schema.GroupVersionKind
+)
+
+// protobufMaxServerMinor is minor version of Kubernetes that vendored client-go
+// (v0.29) corresponds to; built-in types of newer servers may have fields
+// unknown to client-go scheme which would be dropped when decoding protobuf
+const protobufMaxServerMinor = 29
+
+// protobufDynamicClient gets and lists built-in resources (ones known to client-go scheme)
+// in protobuf since decoding JSON is expensive for large apps. Other resources (e.g. CRs)
+// and other requests (e.g. updates, watches) are made via given dynamic client.
+// Objects are decoded into client-go types, hence it is only used with servers
+// whose built-in types are fully known to client-go (see protobufMaxServerMinor).
+type protobufDynamicClient struct {
+	dynamic.Interface
+	restClient rest.Interface
+	kinds      map[schema.GroupVersionResource]schema.GroupVersionKind
+}
+
+var _ dynamic.Interface = protobufDynamicClient{}
+
+// newProtobufDynamicClient returns given client as is when server is newer than client-go
+func newProtobufDynamicClient(client dynamic.Interface, config *rest.Config) (dynamic.Interface, error) {
+	supported, err := protobufSupportedByServer(config)
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return client, nil
+	}
+
+	cpConfig := rest.CopyConfig(config)
+	cpConfig.ContentConfig = rest.ContentConfig{
+		// Server responds with JSON for types that cannot be encoded in protobuf
+		AcceptContentTypes:   runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON,
+		ContentType:          runtime.ContentTypeJSON,
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+	}
+
+	restClient, err := rest.UnversionedRESTClientFor(cpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Building protobuf REST client: %w", err)
+	}
+
+	return protobufDynamicClient{client, restClient, builtinResourceKinds()}, nil
+}
+
+func protobufSupportedByServer(config *rest.Config) (bool, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return false, fmt.Errorf("Building discovery client: %w", err)
+	}
+
+	info, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return false, fmt.Errorf("Getting server version: %w", err)
+	}
+
+	// Minor versions may include suffix (e.g. '29+')
+	minor, err := strconv.Atoi(strings.TrimRightFunc(info.Minor, func(r rune) bool { return !unicode.IsDigit(r) }))
+	if err != nil || info.Major != "1" {
+		return false, nil
+	}
+
+	return minor <= protobufMaxServerMinor, nil
+}
+
+func (c protobufDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	client := c.Interface.Resource(gvr)
+
+	gvk, found := c.kinds[gvr]
+	if !found {
+		return client
+	}
+
+	return protobufNamespaceableResourceClient{
+		protobufResourceClient: protobufResourceClient{
+			ResourceInterface: client,
+			restClient:        c.restClient,
+			gvr:               gvr,
+			gvk:               gvk,
+		},
+		client: client,
+	}
+}
+
+type protobufNamespaceableResourceClient struct {
+	protobufResourceClient
+	client dynamic.NamespaceableResourceInterface
+}
+
+func (c protobufNamespaceableResourceClient) Namespace(namespace string) dynamic.ResourceInterface {
+	nsClient := c.protobufResourceClient
+	nsClient.ResourceInterface = c.client.Namespace(namespace)
+	nsClient.namespace = namespace
+	return nsClient
+}
+
+type protobufResourceClient struct {
+	dynamic.ResourceInterface
+
+	restClient rest.Interface
+	gvr        schema.GroupVersionResource
+	gvk        schema.GroupVersionKind
+	namespace  string
+}
+
+func (c protobufResourceClient) Get(ctx context.Context, name string,
+	opts metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+
+	// Subresources may not be of resource's kind
+	if len(name) == 0 || len(subresources) > 0 {
+		return c.ResourceInterface.Get(ctx, name, opts, subresources...)
+	}
+
+	obj, err := c.restClient.Get().AbsPath(c.urlSegments(name)...).
+		SpecificallyVersionedParams(&opts, scheme.ParameterCodec, c.gvk.GroupVersion()).Do(ctx).Get()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.toUnstructured(obj)
+}
+
+func (c protobufResourceClient) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	obj, err := c.restClient.Get().AbsPath(c.urlSegments("")...).
+		SpecificallyVersionedParams(&opts, scheme.ParameterCodec, c.gvk.GroupVersion()).Do(ctx).Get()
+	if err != nil {
+		return nil, err
+	}
+
+	listMeta, err := meta.ListAccessor(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Reading %s list metadata: %w", c.gvk.Kind, err)
+	}
+
+	items, err := meta.ExtractList(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Extracting %s list items: %w", c.gvk.Kind, err)
+	}
+
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	list.SetAPIVersion(c.gvk.GroupVersion().String())
+	list.SetKind(c.gvk.Kind + "List")
+	list.SetResourceVersion(listMeta.GetResourceVersion())
+	list.SetContinue(listMeta.GetContinue())
+	list.SetRemainingItemCount(listMeta.GetRemainingItemCount())
+
+	for _, item := range items {
+		un, err := c.toUnstructured(item)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, *un)
+	}
+
+	return list, nil
+}
+
+func (c protobufResourceClient) toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("Converting %s to unstructured: %w", c.gvk.Kind, err)
+	}
+
+	un := &unstructured.Unstructured{Object: content}
+	// Decoded typed objects do not retain their type information
+	un.SetGroupVersionKind(c.gvk)
+
+	return un, nil
+}
+
+func (c protobufResourceClient) urlSegments(name string) []string {
+	var segments []string
+	if len(c.gvr.Group) == 0 {
+		segments = []string{"api", c.gvr.Version}
+	} else {
+		segments = []string{"apis", c.gvr.Group, c.gvr.Version}
+	}
+	if len(c.namespace) > 0 {
+		segments = append(segments, "namespaces", c.namespace)
+	}
+	segments = append(segments, c.gvr.Resource)
+	if len(name) > 0 {
+		segments = append(segments, name)
+	}
+	return segments
+}
+
+// builtinResourceKinds maps resources to kinds registered in client-go scheme.
+// Resource names are guessed from kinds; incorrectly guessed resources are
+// not found when looked up hence such resources are retrieved in JSON.
+func builtinResourceKinds() map[schema.GroupVersionResource]schema.GroupVersionKind {
+	builtinResourceKindsOnce.Do(func() {
+		result := map[schema.GroupVersionResource]schema.GroupVersionKind{}
+
+		for gvk := range scheme.Scheme.AllKnownTypes() {
+			if gvk.Version == runtime.APIVersionInternal || strings.HasSuffix(gvk.Kind, "List") {
+				continue
+			}
+			// Only listable kinds are resources (e.g. excludes options kinds)
+			if !scheme.Scheme.Recognizes(gvk.GroupVersion().WithKind(gvk.Kind + "List")) {
+				continue
+			}
+			gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+			result[gvr] = gvk
+		}
+
+		builtinResourceKindsMemo = result
+	})
+	return builtinResourceKindsMemo
+}
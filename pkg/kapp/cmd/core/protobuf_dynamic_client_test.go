@@ -0,0 +1,121 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+func TestProtobufDynamicClient(t *testing.T) {
+	info, found := runtime.SerializerInfoForMediaType(scheme.Codecs.SupportedMediaTypes(), runtime.ContentTypeProtobuf)
+	require.True(t, found)
+	encoder := scheme.Codecs.EncoderForVersion(info.Serializer, corev1.SchemeGroupVersion)
+
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "ns", ResourceVersion: "2"},
+		Data:       map[string]string{"key": "value"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var obj runtime.Object
+
+		switch r.URL.Path {
+		case "/version":
+			w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+			w.Write([]byte(`{"major": "1", "minor": "28+"}`))
+			return
+		case "/api/v1/namespaces/ns/configmaps/cm":
+			obj = &configMap
+		case "/api/v1/namespaces/ns/configmaps":
+			require.Equal(t, "app=test", r.URL.Query().Get("labelSelector"))
+			obj = &corev1.ConfigMapList{ListMeta: metav1.ListMeta{ResourceVersion: "3"}, Items: []corev1.ConfigMap{configMap}}
+		case "/apis/example.com/v1/namespaces/ns/widgets/w":
+			require.NotContains(t, r.Header.Get("Accept"), runtime.ContentTypeProtobuf)
+			w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+			w.Write([]byte(`{"apiVersion":"example.com/v1","kind":"Widget","metadata":{"name":"w","namespace":"ns"}}`))
+			return
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		require.True(t, strings.HasPrefix(r.Header.Get("Accept"), runtime.ContentTypeProtobuf))
+		w.Header().Set("Content-Type", runtime.ContentTypeProtobuf)
+		require.NoError(t, encoder.Encode(obj, w))
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+
+	jsonClient, err := dynamic.NewForConfig(config)
+	require.NoError(t, err)
+
+	client, err := newProtobufDynamicClient(jsonClient, config)
+	require.NoError(t, err)
+
+	configMapsClient := client.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).Namespace("ns")
+
+	res, err := configMapsClient.Get(context.TODO(), "cm", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "v1", res.GetAPIVersion())
+	require.Equal(t, "ConfigMap", res.GetKind())
+	require.Equal(t, "2", res.GetResourceVersion())
+	require.Equal(t, map[string]interface{}{"key": "value"}, res.Object["data"])
+
+	list, err := configMapsClient.List(context.TODO(), metav1.ListOptions{LabelSelector: "app=test"})
+	require.NoError(t, err)
+	require.Equal(t, "ConfigMapList", list.GetKind())
+	require.Equal(t, "3", list.GetResourceVersion())
+	require.Len(t, list.Items, 1)
+	require.Equal(t, "ConfigMap", list.Items[0].GetKind())
+	require.Equal(t, "cm", list.Items[0].GetName())
+
+	// Resources unknown to client-go scheme are retrieved in JSON
+	res, err = client.Resource(schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}).
+		Namespace("ns").Get(context.TODO(), "w", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "Widget", res.GetKind())
+}
+
+func TestProtobufDynamicClientNewerServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/version", r.URL.Path)
+		w.Header().Set("Content-Type", runtime.ContentTypeJSON)
+		w.Write([]byte(`{"major": "1", "minor": "30"}`))
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+
+	jsonClient, err := dynamic.NewForConfig(config)
+	require.NoError(t, err)
+
+	// Built-in types of newer servers may have fields unknown to client-go
+	client, err := newProtobufDynamicClient(jsonClient, config)
+	require.NoError(t, err)
+	require.Equal(t, dynamic.Interface(jsonClient), client)
+}
+
+func TestProtobufMaxServerMinorMatchesClientGo(t *testing.T) {
+	bs, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "go.mod"))
+	require.NoError(t, err)
+	require.Regexp(t, fmt.Sprintf(`\n\s*k8s.io/client-go v0\.%d\.`, protobufMaxServerMinor), string(bs),
+		"Expected protobufMaxServerMinor to match client-go version")
+}
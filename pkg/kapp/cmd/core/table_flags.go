@@ -0,0 +1,147 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"os"
+	"strings"
+
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	tableMinColumnWidth  = 10
+	tableTruncatedSuffix = "..."
+)
+
+type TableFlags struct {
+	Columns    []string
+	NoTruncate bool
+}
+
+func (s *TableFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&s.Columns, "columns", nil,
+		"Show only given columns by name or title (e.g. 'name,kind,age'; hidden columns could be included)")
+	cmd.Flags().BoolVar(&s.NoTruncate, "no-truncate", false,
+		"Do not truncate long values to fit terminal width")
+}
+
+// Apply selects columns and truncates values so that table
+// fits into terminal width. Values are not truncated when
+// output is not a terminal (e.g. piped into other commands).
+func (s TableFlags) Apply(table *uitable.Table) error {
+	if len(s.Columns) > 0 {
+		var headers []uitable.Header
+		for _, col := range s.Columns {
+			headers = append(headers, uitable.Header{Key: uitable.KeyifyHeader(col), Title: col})
+		}
+		err := table.SetColumnVisibility(headers)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !s.NoTruncate {
+		if width := s.terminalWidth(); width > 0 {
+			s.truncate(table, width)
+		}
+	}
+	return nil
+}
+
+func (s TableFlags) terminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+func (s TableFlags) truncate(table *uitable.Table, maxWidth int) {
+	allRows := table.Rows
+	for _, section := range table.Sections {
+		allRows = append(allRows, section.Rows...)
+	}
+
+	widths := map[int]int{}
+	totalWidth := 0
+
+	for i, header := range table.Header {
+		if header.Hidden {
+			continue
+		}
+		widths[i] = len(header.Title)
+		for _, row := range allRows {
+			if i >= len(row) {
+				continue
+			}
+			if _, isFmt := row[i].(uitable.ValueFmt); isFmt {
+				// Formatted values are expected to be short (e.g. states)
+				widths[i] = -1
+				break
+			}
+			for _, line := range strings.Split(row[i].String(), "\n") {
+				if len(line) > widths[i] {
+					widths[i] = len(line)
+				}
+			}
+		}
+		if widths[i] < 0 {
+			delete(widths, i)
+		}
+		totalWidth += widths[i] + len(table.BorderStr)
+	}
+
+	if len(table.BorderStr) == 0 {
+		// Default border between columns
+		totalWidth += 2 * len(widths)
+	}
+
+	// Shrink widest columns first until table fits
+	for totalWidth > maxWidth {
+		widestIdx, widest := -1, tableMinColumnWidth
+		for i, width := range widths {
+			if width > widest || (width == widest && widestIdx >= 0 && i < widestIdx) {
+				widestIdx, widest = i, width
+			}
+		}
+		if widestIdx < 0 {
+			break
+		}
+		widths[widestIdx]--
+		totalWidth--
+	}
+
+	for _, row := range allRows {
+		for i, width := range widths {
+			if i < len(row) {
+				row[i] = truncatedValue{row[i], width}
+			}
+		}
+	}
+}
+
+// truncatedValue preserves original value for sorting
+type truncatedValue struct {
+	orig  uitable.Value
+	width int
+}
+
+var _ uitable.Value = truncatedValue{}
+
+func (t truncatedValue) Value() uitable.Value { return t.orig.Value() }
+
+func (t truncatedValue) Compare(other uitable.Value) int { return t.orig.Compare(other) }
+
+func (t truncatedValue) String() string {
+	lines := strings.Split(t.orig.String(), "\n")
+	for i, line := range lines {
+		if len(line) > t.width {
+			lines[i] = line[:t.width-len(tableTruncatedSuffix)] + tableTruncatedSuffix
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package core
+
+import (
+	"fmt"
+)
+
+const (
+	// VerbosityQuiet only shows summary of changes and errors
+	VerbosityQuiet = 0
+	// VerbosityChanges additionally shows changes table and apply sections
+	VerbosityChanges = 1
+	// VerbosityProgress additionally shows per-resource apply and wait lines
+	VerbosityProgress = 2
+	// VerbosityDiffs additionally shows diffs of changed resources
+	VerbosityDiffs = 3
+	// VerbosityDebug additionally shows debug logs
+	VerbosityDebug = 4
+
+	VerbosityDefault = VerbosityProgress
+)
+
+func ValidateVerbosity(level int) error {
+	if level < VerbosityQuiet || level > VerbosityDebug {
+		return fmt.Errorf("Expected verbosity to be between %d and %d, but was %d", VerbosityQuiet, VerbosityDebug, level)
+	}
+	return nil
+}
+
+// VerbosityMessagesUI drops apply and wait messages
+// that should not be shown at configured verbosity
+type VerbosityMessagesUI struct {
+	ui        MessagesUI
+	verbosity int
+}
+
+var _ MessagesUI = VerbosityMessagesUI{}
+
+func NewVerbosityMessagesUI(ui MessagesUI, verbosity int) VerbosityMessagesUI {
+	return VerbosityMessagesUI{ui, verbosity}
+}
+
+func (ui VerbosityMessagesUI) NotifySection(msg string, args ...interface{}) {
+	if ui.verbosity >= VerbosityChanges {
+		ui.ui.NotifySection(msg, args...)
+	}
+}
+
+func (ui VerbosityMessagesUI) Notify(msgs []string) {
+	if ui.verbosity >= VerbosityProgress {
+		ui.ui.Notify(msgs)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"k8s.io/client-go/rest"
+)
+
+type ImpersonationFlags struct {
+	User   string
+	Groups []string
+}
+
+func (f *ImpersonationFlags) Set(cmd *cobra.Command, _ cmdcore.FlagsFactory) {
+	// Same names as used by kubectl
+	cmd.PersistentFlags().StringVar(&f.User, "as", "",
+		"Set user or service account (e.g. 'system:serviceaccount:ns:name') to impersonate "+
+			"(recorded with new apps and used by default for subsequent changes)")
+	cmd.PersistentFlags().StringSliceVar(&f.Groups, "as-group", nil, "Set group to impersonate (can repeat)")
+}
+
+func (f *ImpersonationFlags) Configure(depsFactory cmdcore.DepsFactory) {
+	depsFactory.ConfigureImpersonation(rest.ImpersonationConfig{UserName: f.User, Groups: f.Groups})
+}
@@ -12,8 +12,10 @@ import (
 	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
 	cmdac "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/appchange"
 	cmdag "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/appgroup"
+	cmdconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/config"
 	cmdcm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/configmap"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	cmdserve "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/serve"
 	cmdsa "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/serviceaccount"
 	cmdtools "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/tools"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
@@ -26,12 +28,17 @@ type KappOptions struct {
 	configFactory cmdcore.ConfigFactory
 	depsFactory   cmdcore.DepsFactory
 
-	UIFlags         UIFlags
-	LoggerFlags     LoggerFlags
-	KubeAPIFlags    cmdcore.KubeAPIFlags
-	KubeconfigFlags cmdcore.KubeconfigFlags
-	WarningFlags    WarningFlags
-	ProfilingFlags  ProfilingFlags
+	UIFlags            UIFlags
+	LoggerFlags        LoggerFlags
+	KubeAPIFlags       cmdcore.KubeAPIFlags
+	KubeconfigFlags    cmdcore.KubeconfigFlags
+	WarningFlags       WarningFlags
+	AppMetadataFlags   AppMetadataFlags
+	ProfilingFlags     ProfilingFlags
+	ImpersonationFlags ImpersonationFlags
+	VerbosityFlags     VerbosityFlags
+
+	versionCheck *backgroundVersionCheck
 }
 
 func NewKappOptions(ui *ui.ConfUI, configFactory cmdcore.ConfigFactory,
@@ -54,7 +61,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		Use:   "kapp",
 		Short: "kapp helps to manage applications on your Kubernetes cluster",
 
-		RunE: cobrautil.ShowHelp,
+		RunE: usageRunE(cobrautil.ShowHelp),
 
 		// Affects children as well
 		SilenceErrors: true,
@@ -65,6 +72,7 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		Version:           version.Version,
 
 		PersistentPostRunE: func(*cobra.Command, []string) error {
+			o.versionCheck.Notify(o.ui)
 			return o.ProfilingFlags.flushProfiling()
 		},
 
@@ -85,7 +93,10 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	o.KubeAPIFlags.Set(cmd, flagsFactory)
 	o.KubeconfigFlags.Set(cmd, flagsFactory)
 	o.WarningFlags.Set(cmd, flagsFactory)
+	o.AppMetadataFlags.Set(cmd, flagsFactory)
+	o.ImpersonationFlags.Set(cmd, flagsFactory)
 	o.ProfilingFlags.Set(cmd, flagsFactory)
+	o.VerbosityFlags.Set(cmd, flagsFactory)
 
 	o.configFactory.ConfigurePathResolver(o.KubeconfigFlags.Path.Value)
 	o.configFactory.ConfigureContextResolver(o.KubeconfigFlags.Context.Value)
@@ -95,12 +106,27 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 
 	cmd.AddCommand(cmdapp.NewListCmd(cmdapp.NewListOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewInspectCmd(cmdapp.NewInspectOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewDriftCmd(cmdapp.NewDriftOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewExportCmd(cmdapp.NewExportOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewAdoptCmd(cmdapp.NewAdoptOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewDisownCmd(cmdapp.NewDisownOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeployCmd(cmdapp.NewDeployOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewPlanCmd(cmdapp.NewPlanOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewExplainResourceCmd(cmdapp.NewExplainResourceOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewApplyPlanCmd(cmdapp.NewApplyPlanOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewRollbackCmd(cmdapp.NewRollbackOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeployConfigCmd(cmdapp.NewDeployConfigOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(cmdapp.NewAppMetadataCRDsCmd(cmdapp.NewAppMetadataCRDsOptions(o.ui), flagsFactory))
 	cmd.AddCommand(cmdapp.NewDeleteCmd(cmdapp.NewDeleteOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewGCCmd(cmdapp.NewGCOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewRenameCmd(cmdapp.NewRenameOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewMigrateAppCmd(cmdapp.NewMigrateAppOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewWaitCmd(cmdapp.NewWaitOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewLogsCmd(cmdapp.NewLogsOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmdapp.NewLabelCmd(cmdapp.NewLabelOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	cmd.AddCommand(cmdapp.NewBenchmarkCmd(cmdapp.NewBenchmarkOptions(o.ui, o.logger), flagsFactory))
+
+	cmd.AddCommand(cmdserve.NewServeCmd(cmdserve.NewServeOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 
 	agCmd := cmdag.NewCmd()
 	agCmd.AddCommand(cmdag.NewDeployCmd(cmdag.NewDeployOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
@@ -111,8 +137,13 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 	cmCmd.AddCommand(cmdcm.NewListCmd(cmdcm.NewListOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(cmCmd)
 
+	confCmd := cmdconf.NewCmd()
+	confCmd.AddCommand(cmdconf.NewEffectiveCmd(cmdconf.NewEffectiveOptions(o.ui, o.depsFactory), flagsFactory))
+	cmd.AddCommand(confCmd)
+
 	acCmd := cmdac.NewCmd()
 	acCmd.AddCommand(cmdac.NewListCmd(cmdac.NewListOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
+	acCmd.AddCommand(cmdac.NewDescribeCmd(cmdac.NewDescribeOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	acCmd.AddCommand(cmdac.NewGCCmd(cmdac.NewGCOptions(o.ui, o.depsFactory, o.logger), flagsFactory))
 	cmd.AddCommand(acCmd)
 
@@ -136,13 +167,26 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		}
 	}
 
-	configureGlobal := cobrautil.WrapRunEForCmd(func(*cobra.Command, []string) error {
-		o.UIFlags.ConfigureUI(o.ui)
-		o.LoggerFlags.Configure(o.logger)
+	configureGlobal := cobrautil.WrapRunEForCmd(func(cmd *cobra.Command, _ []string) error {
+		err := o.UIFlags.ConfigureUI(o.ui, cmd)
+		if err != nil {
+			return err
+		}
+		o.depsFactory.ConfigurePrintTarget(!cmdapp.HasStructuredOutput(cmd))
+		err = o.LoggerFlags.Configure(o.logger)
+		if err != nil {
+			return err
+		}
 		o.KubeAPIFlags.Configure(o.configFactory)
 		o.WarningFlags.Configure(o.depsFactory)
-		o.ProfilingFlags.initProfiling()
-		return nil
+		o.AppMetadataFlags.Configure(o.depsFactory)
+		o.ImpersonationFlags.Configure(o.depsFactory)
+		err = o.ProfilingFlags.initProfiling()
+		if err != nil {
+			return err
+		}
+		// Overrides print target and debug settings configured above
+		return o.VerbosityFlags.Configure(o.depsFactory, o.logger)
 	})
 
 	configureTTYFlag := func(cmd *cobra.Command) {
@@ -157,16 +201,33 @@ func NewKappCmd(o *KappOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		}))
 	}
 
-	cobrautil.VisitCommands(cmd, cobrautil.ReconfigureLeafCmds(cobrautil.DisallowExtraArgs))
+	cobrautil.VisitCommands(cmd, cobrautil.ReconfigureLeafCmds(disallowExtraArgs))
 
-	// Completion command have to be added after the cobrautil.DisallowExtraArgs.
+	// Completion command have to be added after the disallowExtraArgs.
 	// This due to the ReconfigureLeafCmds that we do not want to have enforced for the completion
 	// This configurations forces all nodes to do not accept extra args, but the completion requires 1 extra arg
 	cmd.AddCommand(NewCmdCompletion())
 
+	// Flag defaults from user config need to be set before flags are used
+	configureUserConfig := cobrautil.WrapRunEForCmd(func(cmd *cobra.Command, _ []string) error {
+		userConfig, err := NewUserConfigFromEnv()
+		if err != nil {
+			return err
+		}
+		o.UIFlags.ColorStyles = userConfig.Colors
+		// Version command reports newer release itself via --check
+		if userConfig.CheckForUpdates && cmd.Name() != "version" {
+			o.versionCheck = newBackgroundVersionCheck()
+		}
+		return userConfig.Apply(cmd)
+	})
+
+	cobrautil.VisitCommands(cmd, configureUsageErrors)
+
 	// Last one runs first
 	cobrautil.VisitCommands(cmd, finishDebugLog, cobrautil.ReconfigureCmdWithSubcmd, configureGlobal,
-		cobrautil.WrapRunEForCmd(cobrautil.ResolveFlagsForCmd), cobrautil.ReconfigureLeafCmds(configureTTYFlag))
+		cobrautil.WrapRunEForCmd(cobrautil.ResolveFlagsForCmd), cobrautil.ReconfigureLeafCmds(configureTTYFlag),
+		configureUserConfig)
 	return cmd
 }
 
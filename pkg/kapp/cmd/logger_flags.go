@@ -4,19 +4,34 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/spf13/cobra"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
 )
 
 type LoggerFlags struct {
-	Debug bool
+	Debug  bool
+	Format string
+	Levels []string
 }
 
 func (f *LoggerFlags) Set(cmd *cobra.Command, _ cmdcore.FlagsFactory) {
 	cmd.PersistentFlags().BoolVar(&f.Debug, "debug", false, "Include debug output")
+	cmd.PersistentFlags().StringVar(&f.Format, "log-format", logger.FormatText, "Set log format (text, json)")
+	cmd.PersistentFlags().StringSliceVar(&f.Levels, "log-level", nil,
+		"Set log level (error, info, debug) in 'level' or 'subsystem=level' format; subsystems: "+
+			strings.Join(logger.Subsystems, ", ")+" (can be specified multiple times)")
 }
 
-func (f *LoggerFlags) Configure(logger *logger.UILogger) {
+func (f *LoggerFlags) Configure(logger *logger.UILogger) error {
 	logger.SetDebug(f.Debug)
+
+	err := logger.SetFormat(f.Format)
+	if err != nil {
+		return err
+	}
+
+	return logger.SetLevels(f.Levels)
 }
@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"runtime"
 	"runtime/pprof"
+	"runtime/trace"
 
 	"github.com/spf13/cobra"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
@@ -17,21 +18,36 @@ import (
 type ProfilingFlags struct {
 	profileName   string
 	profileOutput string
+
+	cpuProfile string
+	memProfile string
+	trace      string
+
+	cpuProfileFile *os.File
+	traceFile      *os.File
 }
 
 func (p *ProfilingFlags) Set(cmd *cobra.Command, _ cmdcore.FlagsFactory) {
 	cmd.PersistentFlags().StringVar(&p.profileName, "profile", "none", "Name of profile to capture. One of (none|cpu|heap|goroutine|threadcreate|block|mutex)")
 	cmd.PersistentFlags().StringVar(&p.profileOutput, "profile-output", "profile.pprof", "Name of the file to write the profile to")
+	cmd.PersistentFlags().StringVar(&p.cpuProfile, "cpu-profile", "", "Write CPU profile to file")
+	cmd.PersistentFlags().StringVar(&p.memProfile, "mem-profile", "", "Write heap profile to file once command finishes")
+	cmd.PersistentFlags().StringVar(&p.trace, "trace", "", "Write execution trace to file (view with 'go tool trace')")
 }
 
 func (p *ProfilingFlags) initProfiling() error {
-	var (
-		f   *os.File
-		err error
-	)
+	var f *os.File
+
+	err := p.initFileProfiling()
+	if err != nil {
+		return err
+	}
+
 	switch p.profileName {
 	case "none":
-		return nil
+		if !p.fileProfiling() {
+			return nil
+		}
 	case "cpu":
 		f, err = os.Create(p.profileOutput)
 		if err != nil {
@@ -68,7 +84,81 @@ func (p *ProfilingFlags) initProfiling() error {
 	return nil
 }
 
+// initFileProfiling starts profiles that can be used together
+// with each other as well as with --profile
+func (p *ProfilingFlags) initFileProfiling() error {
+	var err error
+
+	if len(p.cpuProfile) > 0 {
+		if p.profileName == "cpu" {
+			return fmt.Errorf("Expected only one of --cpu-profile or --profile=cpu to be specified")
+		}
+		p.cpuProfileFile, err = os.Create(p.cpuProfile)
+		if err != nil {
+			return fmt.Errorf("Creating CPU profile file: %w", err)
+		}
+		err = pprof.StartCPUProfile(p.cpuProfileFile)
+		if err != nil {
+			return fmt.Errorf("Starting CPU profile: %w", err)
+		}
+	}
+
+	if len(p.trace) > 0 {
+		p.traceFile, err = os.Create(p.trace)
+		if err != nil {
+			return fmt.Errorf("Creating trace file: %w", err)
+		}
+		err = trace.Start(p.traceFile)
+		if err != nil {
+			return fmt.Errorf("Starting trace: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *ProfilingFlags) fileProfiling() bool {
+	return len(p.cpuProfile) > 0 || len(p.memProfile) > 0 || len(p.trace) > 0
+}
+
+func (p *ProfilingFlags) flushFileProfiling() error {
+	if p.cpuProfileFile != nil {
+		pprof.StopCPUProfile()
+		p.cpuProfileFile.Close()
+		p.cpuProfileFile = nil
+	}
+
+	if p.traceFile != nil {
+		trace.Stop()
+		p.traceFile.Close()
+		p.traceFile = nil
+	}
+
+	if len(p.memProfile) > 0 {
+		f, err := os.Create(p.memProfile)
+		if err != nil {
+			return fmt.Errorf("Creating heap profile file: %w", err)
+		}
+		defer f.Close()
+
+		// Include all allocations up to this point
+		runtime.GC()
+
+		err = pprof.WriteHeapProfile(f)
+		if err != nil {
+			return fmt.Errorf("Writing heap profile: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (p *ProfilingFlags) flushProfiling() error {
+	err := p.flushFileProfiling()
+	if err != nil {
+		return err
+	}
+
 	switch p.profileName {
 	case "none":
 		return nil
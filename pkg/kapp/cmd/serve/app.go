@@ -0,0 +1,312 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	appFinalizer = "serve.kapp.k14s.io/delete"
+
+	defaultServiceAccountName = "default"
+)
+
+var (
+	appGVR = schema.GroupVersionResource{Group: "serve.kapp.k14s.io", Version: "v1alpha1", Resource: "apps"}
+
+	// Local paths (including git+file://) would allow reading serve's own files
+	appFetchFileSchemes = []string{"https://", "http://", "git+https://", "git+http://", "oci://"}
+)
+
+// App describes manifests to deploy and options to deploy them with.
+// Deleting App deletes deployed resources.
+type App struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AppSpec   `json:"spec,omitempty"`
+	Status AppStatus `json:"status,omitempty"`
+}
+
+type AppSpec struct {
+	// Defaults to App name
+	AppName string `json:"appName,omitempty"`
+	// Namespace of resources that do not specify it; defaults to App namespace
+	Namespace string `json:"namespace,omitempty"`
+	// Service account (in App namespace) to impersonate when deploying and deleting;
+	// defaults to 'default' (serve's own identity is never used)
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Paused Apps are neither deployed nor deleted
+	Paused bool `json:"paused,omitempty"`
+	// Defaults to serve --sync-period
+	SyncPeriod string `json:"syncPeriod,omitempty"`
+
+	Fetch []AppFetch `json:"fetch,omitempty"`
+	// Flags passed to deploy and delete commands (e.g. --diff-changes, --wait-timeout=5m);
+	// only flags that do not change inputs, target cluster or app identity are allowed
+	DeployOptions []string `json:"deployOptions,omitempty"`
+	DeleteOptions []string `json:"deleteOptions,omitempty"`
+}
+
+// AppFetch specifies exactly one manifest source
+type AppFetch struct {
+	// Files by relative path
+	Inline *AppFetchInline `json:"inline,omitempty"`
+	// Each key (in App namespace) is a file
+	ConfigMap *AppFetchConfigMap `json:"configMap,omitempty"`
+	// Remote URL accepted by deploy --file (https://, http://, git+https://, git+http:// or oci://)
+	File string `json:"file,omitempty"`
+}
+
+type AppFetchInline struct {
+	Paths map[string]string `json:"paths,omitempty"`
+}
+
+type AppFetchConfigMap struct {
+	Name string `json:"name"`
+}
+
+type AppStatus struct {
+	ObservedGeneration int64            `json:"observedGeneration,omitempty"`
+	LastDeploy         *AppStatusDeploy `json:"lastDeploy,omitempty"`
+}
+
+type AppStatusDeploy struct {
+	StartedAt  metav1.Time `json:"startedAt"`
+	FinishedAt metav1.Time `json:"finishedAt"`
+	Successful bool        `json:"successful"`
+	Error      string      `json:"error,omitempty"`
+}
+
+func NewAppFromUnstructured(obj unstructured.Unstructured) (App, error) {
+	var app App
+
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &app)
+	if err != nil {
+		return App{}, fmt.Errorf("Converting App '%s/%s': %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	return app, nil
+}
+
+func (a App) Description() string { return fmt.Sprintf("%s/%s", a.Namespace, a.Name) }
+
+func (a App) Validate() error {
+	if len(a.Spec.Fetch) == 0 {
+		return fmt.Errorf("Expected at least one fetch source to be specified")
+	}
+
+	for i, fetch := range a.Spec.Fetch {
+		var num int
+		for _, specified := range []bool{fetch.Inline != nil, fetch.ConfigMap != nil, len(fetch.File) > 0} {
+			if specified {
+				num++
+			}
+		}
+		if num != 1 {
+			return fmt.Errorf("Expected fetch %d to specify exactly one of inline, configMap or file", i)
+		}
+		if fetch.Inline != nil {
+			for path := range fetch.Inline.Paths {
+				if !filepath.IsLocal(path) {
+					return fmt.Errorf("Expected fetch %d inline path '%s' to be relative and within fetch directory", i, path)
+				}
+			}
+		}
+		if fetch.ConfigMap != nil && len(fetch.ConfigMap.Name) == 0 {
+			return fmt.Errorf("Expected fetch %d config map name to be specified", i)
+		}
+		if len(fetch.File) > 0 && !hasAnyPrefix(fetch.File, appFetchFileSchemes) {
+			return fmt.Errorf("Expected fetch %d file '%s' to be a remote URL (%s)",
+				i, fetch.File, strings.Join(appFetchFileSchemes, ", "))
+		}
+	}
+
+	if len(a.Spec.SyncPeriod) > 0 {
+		_, err := time.ParseDuration(a.Spec.SyncPeriod)
+		if err != nil {
+			return fmt.Errorf("Parsing sync period: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (a App) AppName() string {
+	if len(a.Spec.AppName) > 0 {
+		return a.Spec.AppName
+	}
+	return a.Name
+}
+
+func (a App) ServiceAccountName() string {
+	if len(a.Spec.ServiceAccountName) > 0 {
+		return a.Spec.ServiceAccountName
+	}
+	return defaultServiceAccountName
+}
+
+func (a App) TargetNamespace() string {
+	if len(a.Spec.Namespace) > 0 {
+		return a.Spec.Namespace
+	}
+	return a.Namespace
+}
+
+func (a App) SyncPeriod(defaultPeriod time.Duration) time.Duration {
+	if len(a.Spec.SyncPeriod) > 0 {
+		// Expected to be checked via Validate
+		period, err := time.ParseDuration(a.Spec.SyncPeriod)
+		if err == nil {
+			return period
+		}
+	}
+	return defaultPeriod
+}
+
+func (a App) HasFinalizer() bool {
+	for _, finalizer := range a.Finalizers {
+		if finalizer == appFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// NextSyncIn returns duration until App has to be deployed again;
+// zero if App was changed since it was last deployed
+func (a App) NextSyncIn(defaultPeriod time.Duration, now time.Time) time.Duration {
+	if a.Status.LastDeploy == nil || a.Status.ObservedGeneration != a.Generation {
+		return 0
+	}
+	nextSync := a.Status.LastDeploy.FinishedAt.Add(a.SyncPeriod(defaultPeriod))
+	if nextSync.Before(now) {
+		return 0
+	}
+	return nextSync.Sub(now)
+}
+
+// fetchedFiles writes inline and config map sources into dir and
+// returns values for deploy --file in the order sources are specified
+func (a App) fetchedFiles(dir string, configMapDataFunc func(string) (map[string]string, error)) ([]string, error) {
+	var files []string
+
+	for i, fetch := range a.Spec.Fetch {
+		var paths map[string]string
+
+		switch {
+		case len(fetch.File) > 0:
+			files = append(files, fetch.File)
+			continue
+
+		case fetch.Inline != nil:
+			paths = fetch.Inline.Paths
+
+		case fetch.ConfigMap != nil:
+			data, err := configMapDataFunc(fetch.ConfigMap.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Fetching config map '%s': %w", fetch.ConfigMap.Name, err)
+			}
+			paths = data
+		}
+
+		fetchDir := filepath.Join(dir, fmt.Sprintf("%d", i))
+
+		var sortedPaths []string
+		for path := range paths {
+			sortedPaths = append(sortedPaths, path)
+		}
+		sort.Strings(sortedPaths)
+
+		for _, path := range sortedPaths {
+			fullPath := filepath.Join(fetchDir, path)
+
+			err := os.MkdirAll(filepath.Dir(fullPath), 0700)
+			if err != nil {
+				return nil, fmt.Errorf("Creating fetch directory: %w", err)
+			}
+			err = os.WriteFile(fullPath, []byte(paths[path]), 0600)
+			if err != nil {
+				return nil, fmt.Errorf("Writing fetched file '%s': %w", path, err)
+			}
+		}
+
+		files = append(files, fetchDir)
+	}
+
+	return files, nil
+}
+
+func hasAnyPrefix(val string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(val, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s AppStatusDeploy) Description() string {
+	if s.Successful {
+		return "succeeded"
+	}
+	return "failed: " + strings.SplitN(s.Error, "\n", 2)[0]
+}
+
+// CRDDefinitions returns CRD that has to be installed before running serve
+func CRDDefinitions() string {
+	return crdDefinitionsYAML
+}
+
+const crdDefinitionsYAML = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: apps.serve.kapp.k14s.io
+spec:
+  group: serve.kapp.k14s.io
+  names:
+    kind: App
+    listKind: AppList
+    plural: apps
+    singular: app
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+    subresources:
+      status: {}
+    additionalPrinterColumns:
+    - name: Successful
+      type: boolean
+      jsonPath: .status.lastDeploy.successful
+    - name: Last Deploy
+      type: date
+      jsonPath: .status.lastDeploy.finishedAt
+    - name: Age
+      type: date
+      jsonPath: .metadata.creationTimestamp
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            x-kubernetes-preserve-unknown-fields: true
+          status:
+            type: object
+            x-kubernetes-preserve-unknown-fields: true
+`
@@ -0,0 +1,85 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	// appArgsFlags are always set by serve (see appArgs and fetched files)
+	appArgsFlags = map[string]bool{"app": true, "namespace": true, "app-namespace": true, "file": true}
+
+	// Flags that only tune how changes are shown, applied and waited for are allowed.
+	// Flags that read local files or run binaries (e.g. --helm-binary, --http-bearer-token-file),
+	// change target cluster (e.g. --clusters) or resource placement (e.g. --into-ns),
+	// take over other apps' resources or disable safety checks are not.
+	appCommonAllowedFlags = []string{
+		"diff-changes", "diff-context", "diff-summary", "diff-mask", "diff-line-numbers",
+		"diff-against-last-applied", "diff-anchored", "diff-concurrency", "diff-filter", "diff-run",
+		"apply-check-interval", "apply-concurrency", "apply-default-update-strategy", "apply-ignored", "apply-timeout",
+		"wait", "wait-check-interval", "wait-concurrency", "wait-ignored", "wait-resource-timeout",
+		"wait-service-endpoints", "wait-timeout",
+		"exit-early-on-apply-error", "exit-early-on-wait-error", "lock", "lock-ttl",
+	}
+	appDeployAllowedFlags = append([]string{
+		"wait-watch", "deploy-timeout", "patch", "existing-non-labeled-resources-check",
+		"app-changes-max-to-keep", "app-changes-max-to-keep-resources", "app-changes-max-age",
+		"app-label", "app-annotation", "labels", "change-metadata", "config-profile", "strict-config",
+		"ytt", "ytt-data-value", "ytt-data-value-yaml", "protect", "ttl", "logs", "logs-all",
+	}, appCommonAllowedFlags...)
+	appDeleteAllowedFlags = append([]string{"unprotect"}, appCommonAllowedFlags...)
+)
+
+// validateAppOptions parses App specified options on their own (with a separate
+// command) so that flags set by serve are not taken into account
+func validateAppOptions(cmd *cobra.Command, desc string, options []string, allowedFlags []string) error {
+	err := cmd.ParseFlags(options)
+	if err != nil {
+		return fmt.Errorf("Parsing %s options: %w", desc, err)
+	}
+
+	// Flags set by serve follow App options, hence they must not be turned into arguments via '--'
+	if cmd.Flags().ArgsLenAtDash() >= 0 {
+		return fmt.Errorf("Expected %s options to not include '--'", desc)
+	}
+	if args := cmd.Flags().Args(); len(args) > 0 {
+		return fmt.Errorf("Expected %s options to only include flags, but found '%s'", desc, args[0])
+	}
+
+	allowed := map[string]bool{}
+	for _, name := range allowedFlags {
+		allowed[name] = true
+	}
+
+	var disallowed []string
+
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if !allowed[flag.Name] {
+			disallowed = append(disallowed, flag.Name)
+		}
+	})
+
+	if len(disallowed) == 0 {
+		return nil
+	}
+
+	sort.Strings(disallowed)
+	name := disallowed[0]
+
+	if appArgsFlags[name] {
+		return fmt.Errorf("Expected %s options to not include --%s (set by serve based on App)", desc, name)
+	}
+
+	sortedAllowed := append([]string{}, allowedFlags...)
+	sort.Strings(sortedAllowed)
+
+	return fmt.Errorf("Expected %s options to not include --%s (allowed flags: --%s)",
+		desc, name, strings.Join(sortedAllowed, ", --"))
+}
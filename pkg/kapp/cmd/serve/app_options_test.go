@@ -0,0 +1,70 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"testing"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+)
+
+func TestValidateAppOptions(t *testing.T) {
+	flagsFactory := cmdcore.NewFlagsFactory(nil, nil)
+
+	examples := []struct {
+		Options []string
+		Delete  bool
+		Err     string
+	}{
+		{Options: []string{"--diff-changes", "-c", "--wait-timeout=5m", "--patch"}},
+		{Options: []string{"--wait-timeout", "5m", "--unprotect"}, Delete: true},
+		{Options: []string{"--app=other"}, Err: "Expected deploy options to not include --app (set by serve based on App)"},
+		{Options: []string{"-n", "kube-system"}, Err: "Expected deploy options to not include --namespace (set by serve based on App)"},
+		{Options: []string{"-f", "/etc/passwd"}, Err: "Expected deploy options to not include --file (set by serve based on App)"},
+		{Options: []string{"--app-namespace", "kube-system"}, Delete: true,
+			Err: "Expected delete options to not include --app-namespace (set by serve based on App)"},
+		{Options: []string{"--helm-binary=/bin/sh"}, Err: "Expected deploy options to not include --helm-binary (allowed flags: "},
+		{Options: []string{"--into-ns=kube-system"}, Err: "Expected deploy options to not include --into-ns (allowed flags: "},
+		{Options: []string{"--dangerous-override-ownership-of-existing-resources"},
+			Err: "Expected deploy options to not include --dangerous-override-ownership-of-existing-resources (allowed flags: "},
+		{Options: []string{"--watch"}, Err: "Expected deploy options to not include --watch (allowed flags: "},
+		{Options: []string{"--patch"}, Delete: true, Err: "Parsing delete options: unknown flag: --patch"},
+		{Options: []string{"--diff-changes", "extra"}, Err: "Expected deploy options to only include flags, but found 'extra'"},
+		{Options: []string{"--diff-changes", "--"}, Err: "Expected deploy options to not include '--'"},
+	}
+
+	for _, ex := range examples {
+		var err error
+		if ex.Delete {
+			cmd := cmdapp.NewDeleteCmd(cmdapp.NewDeleteOptions(ui.NewNoopUI(), nil, logger.NewNoopLogger()), flagsFactory)
+			err = validateAppOptions(cmd, "delete", ex.Options, appDeleteAllowedFlags)
+		} else {
+			cmd := cmdapp.NewDeployCmd(cmdapp.NewDeployOptions(ui.NewNoopUI(), nil, logger.NewNoopLogger()), flagsFactory)
+			err = validateAppOptions(cmd, "deploy", ex.Options, appDeployAllowedFlags)
+		}
+		if len(ex.Err) == 0 {
+			require.NoError(t, err, "Options: %v", ex.Options)
+		} else {
+			require.ErrorContains(t, err, ex.Err, "Options: %v", ex.Options)
+		}
+	}
+}
+
+func TestAppAllowedFlagsExist(t *testing.T) {
+	flagsFactory := cmdcore.NewFlagsFactory(nil, nil)
+
+	deployCmd := cmdapp.NewDeployCmd(cmdapp.NewDeployOptions(ui.NewNoopUI(), nil, logger.NewNoopLogger()), flagsFactory)
+	for _, name := range appDeployAllowedFlags {
+		require.NotNil(t, deployCmd.Flags().Lookup(name), "Expected deploy flag --%s to exist", name)
+	}
+
+	deleteCmd := cmdapp.NewDeleteCmd(cmdapp.NewDeleteOptions(ui.NewNoopUI(), nil, logger.NewNoopLogger()), flagsFactory)
+	for _, name := range appDeleteAllowedFlags {
+		require.NotNil(t, deleteCmd.Flags().Lookup(name), "Expected delete flag --%s to exist", name)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestAppFromUnstructured(t *testing.T) {
+	app := mustApp(t, `
+apiVersion: serve.kapp.k14s.io/v1alpha1
+kind: App
+metadata:
+  name: app1
+  namespace: ns1
+  generation: 2
+spec:
+  namespace: target-ns
+  syncPeriod: 30s
+  fetch:
+  - inline:
+      paths:
+        config.yml: "kind: ConfigMap"
+  - file: https://example.com/release.yml
+  deployOptions: [--diff-changes]
+status:
+  observedGeneration: 2
+  lastDeploy:
+    startedAt: "2024-01-01T00:00:00Z"
+    finishedAt: "2024-01-01T00:00:10Z"
+    successful: true
+`)
+
+	require.NoError(t, app.Validate())
+	require.Equal(t, "app1", app.AppName())
+	require.Equal(t, "target-ns", app.TargetNamespace())
+	require.Equal(t, "default", app.ServiceAccountName())
+	require.Equal(t, 30*time.Second, app.SyncPeriod(time.Minute))
+	require.Equal(t, []string{"--diff-changes"}, app.Spec.DeployOptions)
+
+	finishedAt := time.Date(2024, 1, 1, 0, 0, 10, 0, time.UTC)
+	require.Equal(t, 20*time.Second, app.NextSyncIn(time.Minute, finishedAt.Add(10*time.Second)))
+	require.Equal(t, time.Duration(0), app.NextSyncIn(time.Minute, finishedAt.Add(time.Minute)))
+
+	// Changed since last deploy
+	app.Generation = 3
+	require.Equal(t, time.Duration(0), app.NextSyncIn(time.Minute, finishedAt))
+}
+
+func TestAppFetchedFiles(t *testing.T) {
+	app := mustApp(t, `
+apiVersion: serve.kapp.k14s.io/v1alpha1
+kind: App
+metadata:
+  name: app1
+  namespace: ns1
+spec:
+  fetch:
+  - inline:
+      paths:
+        config/cm.yml: "kind: ConfigMap"
+  - file: https://example.com/release.yml
+  - configMap: {name: manifests}
+`)
+
+	dir := t.TempDir()
+
+	files, err := app.fetchedFiles(dir, func(name string) (map[string]string, error) {
+		require.Equal(t, "manifests", name)
+		return map[string]string{"secret.yml": "kind: Secret"}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "0"), "https://example.com/release.yml", filepath.Join(dir, "2")}, files)
+
+	bs, err := os.ReadFile(filepath.Join(dir, "0", "config", "cm.yml"))
+	require.NoError(t, err)
+	require.Equal(t, "kind: ConfigMap", string(bs))
+
+	bs, err = os.ReadFile(filepath.Join(dir, "2", "secret.yml"))
+	require.NoError(t, err)
+	require.Equal(t, "kind: Secret", string(bs))
+
+	_, err = app.fetchedFiles(t.TempDir(), func(string) (map[string]string, error) {
+		return nil, fmt.Errorf("not found")
+	})
+	require.EqualError(t, err, "Fetching config map 'manifests': not found")
+}
+
+func TestAppValidate(t *testing.T) {
+	examples := []struct {
+		Spec string
+		Err  string
+	}{
+		{`{}`, "Expected at least one fetch source to be specified"},
+		{`{fetch: [{file: a.yml, configMap: {name: cm}}]}`, "Expected fetch 0 to specify exactly one of inline, configMap or file"},
+		{`{fetch: [{inline: {paths: {../cm.yml: ""}}}]}`, "Expected fetch 0 inline path '../cm.yml' to be relative and within fetch directory"},
+		{`{fetch: [{configMap: {}}]}`, "Expected fetch 0 config map name to be specified"},
+		{`{fetch: [{file: https://example.com/a.yml}], syncPeriod: 1x}`, "Parsing sync period"},
+		{`{fetch: [{file: /etc/kapp/a.yml}]}`, "Expected fetch 0 file '/etc/kapp/a.yml' to be a remote URL"},
+		{`{fetch: [{file: git+file:///repo//config}]}`, "Expected fetch 0 file 'git+file:///repo//config' to be a remote URL"},
+	}
+
+	for _, ex := range examples {
+		app := mustApp(t, "apiVersion: serve.kapp.k14s.io/v1alpha1\nkind: App\nmetadata: {name: app1}\nspec: "+ex.Spec)
+		require.ErrorContains(t, app.Validate(), ex.Err, "Spec: %s", ex.Spec)
+	}
+}
+
+func mustApp(t *testing.T, appYAML string) App {
+	var obj map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(appYAML), &obj))
+
+	app, err := NewAppFromUnstructured(unstructured.Unstructured{Object: obj})
+	require.NoError(t, err)
+	return app
+}
@@ -0,0 +1,380 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package serve
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/spf13/cobra"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+type ServeOptions struct {
+	ui           ui.UI
+	depsFactory  cmdcore.DepsFactory
+	flagsFactory cmdcore.FlagsFactory
+	logger       logger.Logger
+
+	NamespaceFlags cmdcore.NamespaceFlags
+	AllNamespaces  bool
+	SyncPeriod     time.Duration
+	PrintCRDs      bool
+}
+
+func NewServeOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ServeOptions {
+	return &ServeOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewServeCmd(o *ServeOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Continuously deploy apps described by App custom resources",
+		Long: `Continuously deploy apps described by App custom resources.
+
+Each App is deployed when it changes and every sync period (to correct drift);
+deleting App deletes its resources. Runs until interrupted.
+
+  apiVersion: serve.kapp.k14s.io/v1alpha1
+  kind: App
+  metadata:
+    name: app1
+  spec:
+    serviceAccountName: app1-deployer  # impersonated when deploying (defaults to 'default')
+    syncPeriod: 5m
+    fetch:
+    - configMap: {name: app1-config}   # each key is a file
+    - file: https://github.com/...download/v0.6.0/release.yaml
+    deployOptions: [--diff-changes, --wait-timeout=5m]`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Install App CRD
+  kapp serve --print-crds | kapp deploy -a kapp-serve-crds -f -
+
+  # Deploy Apps from all namespaces
+  kapp serve --all-namespaces --yes`,
+		Annotations: map[string]string{
+			cmdcore.AppSupportHelpGroup.Key: cmdcore.AppSupportHelpGroup.Value,
+		},
+	}
+	o.flagsFactory = flagsFactory
+	o.NamespaceFlags.Set(cmd, flagsFactory)
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", false, "Watch Apps in all namespaces")
+	cmd.Flags().DurationVar(&o.SyncPeriod, "sync-period", 5*time.Minute,
+		"Set how often to re-deploy Apps that did not change (overridden by App syncPeriod)")
+	cmd.Flags().BoolVar(&o.PrintCRDs, "print-crds", false, "Print App CRD and exit")
+	return cmd
+}
+
+func (o *ServeOptions) Run() error {
+	if o.PrintCRDs {
+		o.ui.PrintBlock([]byte(CRDDefinitions()))
+		return nil
+	}
+
+	if o.ui.IsInteractive() {
+		return fmt.Errorf("Expected --yes (-y) to be specified since Apps are deployed without confirmation")
+	}
+	if o.SyncPeriod <= 0 {
+		return fmt.Errorf("Expected --sync-period to be greater than zero")
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient(cmdcore.DynamicClientOpts{})
+	if err != nil {
+		return err
+	}
+
+	namespace := o.NamespaceFlags.Name
+	if o.AllNamespaces {
+		namespace = ""
+	}
+	client := dynamicClient.Resource(appGVR).Namespace(namespace)
+
+	interruptCh := make(chan struct{})
+	stopWatchingInterrupts := cmdcore.CancelSignals{}.Watch(func() { close(interruptCh) })
+	defer stopWatchingInterrupts()
+
+	o.ui.PrintLinef("Serving Apps (namespace: %s); interrupt to stop", o.namespaceDesc(namespace))
+
+	for {
+		nextSyncIn, resourceVersion, err := o.reconcileAll(client, dynamicClient)
+		if err != nil {
+			o.ui.ErrorLinef("kapp: Error: %s", err)
+			nextSyncIn = o.SyncPeriod
+		}
+
+		stop, err := o.waitForNextReconcile(client, resourceVersion, nextSyncIn, interruptCh)
+		if err != nil || stop {
+			return err
+		}
+	}
+}
+
+// reconcileAll deploys or deletes Apps that are due and returns
+// duration until next App is due (at most sync period)
+func (o *ServeOptions) reconcileAll(client dynamic.ResourceInterface, dynamicClient dynamic.Interface) (time.Duration, string, error) {
+	list, err := client.List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return 0, "", fmt.Errorf("Listing Apps: %w", err)
+	}
+
+	nextSyncIn := o.SyncPeriod
+
+	for _, item := range list.Items {
+		app, err := NewAppFromUnstructured(item)
+		if err != nil {
+			o.ui.ErrorLinef("kapp: Error: %s", err)
+			continue
+		}
+		if app.Spec.Paused {
+			continue
+		}
+
+		if app.DeletionTimestamp != nil {
+			if app.HasFinalizer() {
+				o.deleteApp(app, dynamicClient)
+			}
+			continue
+		}
+
+		syncIn := app.NextSyncIn(o.SyncPeriod, time.Now())
+		if syncIn == 0 {
+			o.deployApp(app, dynamicClient)
+			syncIn = app.SyncPeriod(o.SyncPeriod)
+		}
+		if syncIn < nextSyncIn {
+			nextSyncIn = syncIn
+		}
+	}
+
+	return nextSyncIn, list.GetResourceVersion(), nil
+}
+
+// waitForNextReconcile returns when any App changes, next App is due or interrupt is received
+func (o *ServeOptions) waitForNextReconcile(client dynamic.ResourceInterface, resourceVersion string,
+	nextSyncIn time.Duration, interruptCh <-chan struct{}) (bool, error) {
+
+	var eventCh <-chan watch.Event
+
+	if len(resourceVersion) > 0 {
+		watcher, err := client.Watch(context.TODO(), metav1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			o.ui.ErrorLinef("Warning: Watching Apps (relying on sync period): %s", err)
+		} else {
+			defer watcher.Stop()
+			eventCh = watcher.ResultChan()
+		}
+	}
+
+	timer := time.NewTimer(nextSyncIn)
+	defer timer.Stop()
+
+	select {
+	case <-interruptCh:
+		o.ui.PrintLinef("\nReceived interrupt: stopped serving")
+		return true, nil
+	case <-timer.C:
+	case <-eventCh:
+	}
+
+	return false, nil
+}
+
+func (o *ServeOptions) deployApp(app App, dynamicClient dynamic.Interface) {
+	o.ui.PrintLinef("--- deploying App '%s' (app: %s, namespace: %s)", app.Description(), app.AppName(), app.TargetNamespace())
+
+	status := AppStatusDeploy{StartedAt: metav1.Now()}
+
+	err := o.addFinalizer(app, dynamicClient)
+	if err == nil {
+		err = o.runDeploy(app)
+	}
+
+	status.FinishedAt = metav1.Now()
+	status.Successful = err == nil
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	o.ui.PrintLinef("--- deploying App '%s' %s", app.Description(), status.Description())
+
+	err = o.updateStatus(app, status, dynamicClient)
+	if err != nil {
+		o.ui.ErrorLinef("kapp: Error: Updating App '%s' status: %s", app.Description(), err)
+	}
+}
+
+func (o *ServeOptions) runDeploy(app App) error {
+	err := app.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = validateAppOptions(cmdapp.NewDeployCmd(cmdapp.NewDeployOptions(o.ui, o.depsFactory, o.logger), o.flagsFactory),
+		"deploy", app.Spec.DeployOptions, appDeployAllowedFlags)
+	if err != nil {
+		return err
+	}
+
+	depsFactory := o.appDepsFactory(app)
+
+	dir, err := os.MkdirTemp("", "kapp-serve")
+	if err != nil {
+		return fmt.Errorf("Creating fetch directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files, err := app.fetchedFiles(dir, func(name string) (map[string]string, error) {
+		coreClient, err := depsFactory.CoreClient()
+		if err != nil {
+			return nil, err
+		}
+		cm, err := coreClient.CoreV1().ConfigMaps(app.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return cm.Data, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	deployOpts := cmdapp.NewDeployOptions(o.ui, depsFactory, o.logger)
+	deployCmd := cmdapp.NewDeployCmd(deployOpts, o.flagsFactory)
+
+	// App options come first so that serve set flags always take precedence
+	args := append(append([]string{}, app.Spec.DeployOptions...), o.appArgs(app)...)
+	for _, file := range files {
+		args = append(args, "--file", file)
+	}
+
+	err = deployCmd.ParseFlags(args)
+	if err != nil {
+		return fmt.Errorf("Parsing deploy options: %w", err)
+	}
+
+	return deployOpts.Run()
+}
+
+func (o *ServeOptions) deleteApp(app App, dynamicClient dynamic.Interface) {
+	o.ui.PrintLinef("--- deleting App '%s' (app: %s, namespace: %s)", app.Description(), app.AppName(), app.TargetNamespace())
+
+	deleteOpts := cmdapp.NewDeleteOptions(o.ui, o.appDepsFactory(app), o.logger)
+	deleteCmd := cmdapp.NewDeleteCmd(deleteOpts, o.flagsFactory)
+
+	err := validateAppOptions(cmdapp.NewDeleteCmd(cmdapp.NewDeleteOptions(o.ui, o.depsFactory, o.logger), o.flagsFactory),
+		"delete", app.Spec.DeleteOptions, appDeleteAllowedFlags)
+	if err == nil {
+		err = deleteCmd.ParseFlags(append(append([]string{}, app.Spec.DeleteOptions...), o.appArgs(app)...))
+	}
+	if err == nil {
+		err = deleteOpts.Run()
+	}
+	if err != nil {
+		// Finalizer is kept so that deletion is retried
+		o.ui.ErrorLinef("--- deleting App '%s' failed: %s", app.Description(), err)
+		return
+	}
+
+	err = o.removeFinalizer(app, dynamicClient)
+	if err != nil {
+		o.ui.ErrorLinef("kapp: Error: Removing App '%s' finalizer: %s", app.Description(), err)
+		return
+	}
+
+	o.ui.PrintLinef("--- deleted App '%s'", app.Description())
+}
+
+// appArgs keeps app state in App namespace
+func (o *ServeOptions) appArgs(app App) []string {
+	return []string{"--app", app.AppName(), "--namespace", app.TargetNamespace(), "--app-namespace", app.Namespace}
+}
+
+// appDepsFactory impersonates App service account so that Apps
+// cannot do more than their namespace users are allowed to
+func (o *ServeOptions) appDepsFactory(app App) cmdcore.DepsFactory {
+	return o.depsFactory.WithImpersonation(rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", app.Namespace, app.ServiceAccountName()),
+	})
+}
+
+func (o *ServeOptions) addFinalizer(app App, dynamicClient dynamic.Interface) error {
+	if app.HasFinalizer() {
+		return nil
+	}
+	return o.updateApp(app, dynamicClient, func(obj *unstructured.Unstructured) {
+		obj.SetFinalizers(append(obj.GetFinalizers(), appFinalizer))
+	})
+}
+
+func (o *ServeOptions) removeFinalizer(app App, dynamicClient dynamic.Interface) error {
+	return o.updateApp(app, dynamicClient, func(obj *unstructured.Unstructured) {
+		var finalizers []string
+		for _, finalizer := range obj.GetFinalizers() {
+			if finalizer != appFinalizer {
+				finalizers = append(finalizers, finalizer)
+			}
+		}
+		obj.SetFinalizers(finalizers)
+	})
+}
+
+func (o *ServeOptions) updateApp(app App, dynamicClient dynamic.Interface, updateFunc func(*unstructured.Unstructured)) error {
+	client := dynamicClient.Resource(appGVR).Namespace(app.Namespace)
+
+	obj, err := client.Get(context.TODO(), app.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("Getting App: %w", err)
+	}
+
+	updateFunc(obj)
+
+	_, err = client.Update(context.TODO(), obj, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("Updating App: %w", err)
+	}
+	return nil
+}
+
+// updateStatus records generation that was deployed (not the latest one)
+// so that changes made while deploying are deployed next
+func (o *ServeOptions) updateStatus(app App, deployStatus AppStatusDeploy, dynamicClient dynamic.Interface) error {
+	client := dynamicClient.Resource(appGVR).Namespace(app.Namespace)
+
+	obj, err := client.Get(context.TODO(), app.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&AppStatus{
+		ObservedGeneration: app.Generation,
+		LastDeploy:         &deployStatus,
+	})
+	if err != nil {
+		return err
+	}
+
+	obj.Object["status"] = status
+
+	_, err = client.UpdateStatus(context.TODO(), obj, metav1.UpdateOptions{})
+	return err
+}
+
+func (o *ServeOptions) namespaceDesc(namespace string) string {
+	if len(namespace) == 0 {
+		return "(all)"
+	}
+	return namespace
+}
@@ -4,6 +4,8 @@
 package tools
 
 import (
+	"runtime"
+
 	"github.com/spf13/cobra"
 	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
 	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
@@ -38,6 +40,7 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&s.Mask, prefix+"mask", true, "Apply masking rules")
 
 	cmd.Flags().BoolVar(&s.AgainstLastApplied, prefix+"against-last-applied", true, "Show changes against last applied copy when possible")
+	cmd.Flags().IntVar(&s.Concurrency, prefix+"concurrency", runtime.NumCPU(), "Maximum number of resources diffed concurrently")
 
 	cmd.Flags().StringVar(&s.Filter, prefix+"filter", "", `Set changes filter (example: {"and":[{"ops":["update"]},{"existingResource":{"kinds":["Deployment"]}]})`)
 	cmd.Flags().BoolVar(&s.ChangesYAML, prefix+"changes-yaml", false, "Print YAML to be applied")
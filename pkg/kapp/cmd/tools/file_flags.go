@@ -13,7 +13,7 @@ type FileFlags struct {
 }
 
 func (s *FileFlags) Set(cmd *cobra.Command) {
-	cmd.Flags().StringSliceVarP(&s.Files, "file", "f", s.Files, "Set file (format: /tmp/foo, https://..., -) (can repeat)")
+	cmd.Flags().StringSliceVarP(&s.Files, "file", "f", s.Files, "Set file (format: /tmp/foo, https://..., -, /dev/fd/3, label=-) (can repeat)")
 	cmd.Flags().BoolVar(&s.Sort, "sort", true, "Sort by namespace, name, etc.")
 }
 
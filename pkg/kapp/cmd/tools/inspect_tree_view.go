@@ -8,12 +8,12 @@ import (
 	"io"
 	"strings"
 
-	"github.com/cppforlife/color"
 	"github.com/cppforlife/go-cli-ui/ui"
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
 	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
 )
 
 type InspectTreeView struct {
@@ -84,7 +84,7 @@ func (v InspectTreeView) Print(ui ui.UI) {
 				S: prefix + resource.Name(),
 				Func: func(str string, opts ...interface{}) string {
 					result := fmt.Sprintf(str, opts...)
-					return strings.Replace(result, prefix, color.New(color.Faint).Sprintf("%s", prefix), 1)
+					return strings.Replace(result, prefix, theme.Current().Decoration.Sprintf("%s", prefix), 1)
 				},
 			},
 			uitable.NewValueString(resource.Kind()),
@@ -154,8 +154,47 @@ func (a *assocSortingValue) labelAssocStr() string {
 		} else {
 			lblVal = "lbl-" + lblVal + "-1" // parent
 		}
+		return lblVal
 	}
-	return lblVal
+
+	// Non-labeled resources (e.g. created by custom resource controllers)
+	// are grouped with their closest labeled owner
+	if ownerLblVal := a.ownerLabelAssocStr(); len(ownerLblVal) > 0 {
+		return "lbl-" + ownerLblVal + "-2/child"
+	}
+	return ""
+}
+
+func (a *assocSortingValue) ownerLabelAssocStr() string {
+	visited := map[string]struct{}{a.resource.UID(): {}}
+	res := a.resource
+
+	for {
+		owner, found := a.firstOwner(res)
+		if !found {
+			return ""
+		}
+		if _, seen := visited[owner.UID()]; seen {
+			return "" // ownership cycle
+		}
+		visited[owner.UID()] = struct{}{}
+
+		lblVal := owner.Labels()[ctlres.NewAssociationLabel(owner).Key()]
+		if len(lblVal) > 0 {
+			return lblVal
+		}
+		res = owner
+	}
+}
+
+func (a *assocSortingValue) firstOwner(res ctlres.Resource) (ctlres.Resource, bool) {
+	for _, ref := range res.OwnerRefs() {
+		foundRes, found := a.rsByUID[string(ref.UID)]
+		if found {
+			return foundRes, true
+		}
+	}
+	return nil, false
 }
 
 func (a *assocSortingValue) uidOwnersStr() string {
@@ -166,14 +205,11 @@ func (a *assocSortingValue) uidOwnersStr() string {
 		res := *nextRes
 		nextRes = nil
 
-		for _, ref := range res.OwnerRefs() {
-			foundRes, found := a.rsByUID[string(ref.UID)]
-			if found {
-				// only nest into first object that we find
-				identifiers = append([]string{a.resIdentifier(foundRes)}, identifiers...)
-				nextRes = &foundRes
-				break
-			}
+		// only nest into first object that we find
+		foundRes, found := a.firstOwner(res)
+		if found && len(identifiers) <= len(a.rsByUID) { // guards against ownership cycles
+			identifiers = append([]string{a.resIdentifier(foundRes)}, identifiers...)
+			nextRes = &foundRes
 		}
 	}
 
@@ -20,6 +20,10 @@ type InspectView struct {
 }
 
 func (v InspectView) Print(ui ui.UI) {
+	ui.PrintTable(v.Table())
+}
+
+func (v InspectView) Table() uitable.Table {
 	versionHeader := uitable.NewHeader("Version")
 	versionHeader.Hidden = true
 
@@ -87,7 +91,7 @@ func (v InspectView) Print(ui ui.UI) {
 		table.Rows = append(table.Rows, row)
 	}
 
-	ui.PrintTable(table)
+	return table
 }
 
 func NewValueResourceOwner(resource ctlres.Resource) uitable.ValueString {
@@ -53,6 +53,14 @@ func (s *ResourceFilterFlags) ResourceFilter() (ctlres.ResourceFilter, error) {
 	return rf, nil
 }
 
+// IsSet returns true if any of the filters were specified
+func (s *ResourceFilterFlags) IsSet() bool {
+	rf := s.rf
+	return len(s.age) > 0 || len(s.bf) > 0 || len(rf.Kinds) > 0 || len(rf.Namespaces) > 0 ||
+		len(rf.Names) > 0 || len(rf.KindNames) > 0 || len(rf.KindNamespaces) > 0 ||
+		len(rf.KindNsNames) > 0 || len(rf.Labels) > 0
+}
+
 func (s *ResourceFilterFlags) Times() (*time.Time, *time.Time, error) {
 	if len(s.age) == 0 {
 		return nil, nil, nil
@@ -4,30 +4,46 @@
 package cmd
 
 import (
+	"os"
+
+	"github.com/cppforlife/color"
 	"github.com/cppforlife/go-cli-ui/ui"
 	uitable "github.com/cppforlife/go-cli-ui/ui/table"
 	"github.com/spf13/cobra"
 	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
 )
 
 type UIFlags struct {
-	TTY            bool
-	Color          bool
-	JSON           bool
-	NonInteractive bool
-	Columns        []string
+	TTY             bool
+	Color           bool
+	ColorTheme      string
+	ColorBackground string
+	JSON            bool
+	NonInteractive  bool
+	Columns         []string
+
+	// Populated from user config
+	ColorStyles map[string]string
 }
 
 func (f *UIFlags) Set(cmd *cobra.Command, _ cmdcore.FlagsFactory) {
-	cmd.PersistentFlags().BoolVar(&f.Color, "color", true, "Set color output")
+	cmd.PersistentFlags().BoolVar(&f.Color, "color", true, "Set color output (disabled when $NO_COLOR is set)")
+	cmd.PersistentFlags().StringVar(&f.ColorTheme, "color-theme", theme.NameDefault,
+		"Set color theme (default, colorblind) ($KAPP_COLOR_THEME)")
+	cmd.PersistentFlags().StringVar(&f.ColorBackground, "color-background", theme.BackgroundAuto,
+		"Set terminal background used to pick theme colors (auto, dark, light) ($KAPP_COLOR_BACKGROUND)")
 	cmd.PersistentFlags().BoolVar(&f.JSON, "json", false, "Output as JSON")
 	cmd.PersistentFlags().BoolVarP(&f.NonInteractive, "yes", "y", false, "Assume yes for any prompt")
 	cmd.PersistentFlags().StringSliceVar(&f.Columns, "column", nil, "Filter to show only given columns")
 }
 
-func (f *UIFlags) ConfigureUI(ui *ui.ConfUI) {
-	if f.Color {
+func (f *UIFlags) ConfigureUI(ui *ui.ConfUI, cmd *cobra.Command) error {
+	if f.colorEnabled(cmd) {
 		ui.EnableColor()
+	} else {
+		// Disables colors that are not applied by UI (e.g. in diffs)
+		color.NoColor = true
 	}
 
 	if f.JSON {
@@ -49,4 +65,34 @@ func (f *UIFlags) ConfigureUI(ui *ui.ConfUI) {
 
 		ui.ShowColumns(headers)
 	}
+
+	return f.configureTheme(cmd)
+}
+
+// colorEnabled honors NO_COLOR (https://no-color.org) unless --color is explicitly specified
+func (f *UIFlags) colorEnabled(cmd *cobra.Command) bool {
+	if !cmd.Flags().Changed("color") && len(os.Getenv("NO_COLOR")) > 0 {
+		return false
+	}
+	return f.Color
+}
+
+func (f *UIFlags) configureTheme(cmd *cobra.Command) error {
+	name := f.flagOrEnv(cmd, "color-theme", f.ColorTheme, "KAPP_COLOR_THEME")
+	background := f.flagOrEnv(cmd, "color-background", f.ColorBackground, "KAPP_COLOR_BACKGROUND")
+
+	colorTheme, err := theme.New(name, background, f.ColorStyles)
+	if err != nil {
+		return err
+	}
+
+	theme.Configure(colorTheme)
+	return nil
+}
+
+func (*UIFlags) flagOrEnv(cmd *cobra.Command, flagName, flagVal, envName string) string {
+	if envVal := os.Getenv(envName); !cmd.Flags().Changed(flagName) && len(envVal) > 0 {
+		return envVal
+	}
+	return flagVal
 }
@@ -0,0 +1,49 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/cobrautil"
+	"github.com/spf13/cobra"
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+)
+
+// disallowExtraArgs is similar to cobrautil.DisallowExtraArgs
+// but returns usage exit status
+func disallowExtraArgs(cmd *cobra.Command) {
+	cobrautil.WrapRunEForCmd(func(cmd2 *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return cmdapp.UsageExitStatus{fmt.Errorf("command '%s' does not accept extra arguments '%s'", cmd2.CommandPath(), args[0])}
+		}
+		return nil
+	})(cmd)
+	cmd.Args = cobra.ArbitraryArgs
+}
+
+// configureUsageErrors makes sure that unknown flags and
+// commands invoked without a subcommand result in usage exit status.
+// Has to run before cobrautil.ReconfigureCmdWithSubcmd.
+func configureUsageErrors(cmd *cobra.Command) {
+	if !cmd.HasParent() {
+		// Flag error func is inherited by all subcommands
+		cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+			return cmdapp.UsageExitStatus{err}
+		})
+	}
+	if len(cmd.Commands()) > 0 && cmd.RunE == nil {
+		cmd.RunE = usageRunE(cobrautil.ShowSubcommands)
+	}
+}
+
+func usageRunE(runE func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		err := runE(cmd, args)
+		if err != nil {
+			return cmdapp.UsageExitStatus{err}
+		}
+		return nil
+	}
+}
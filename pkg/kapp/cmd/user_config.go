@@ -0,0 +1,181 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	userConfigFileEnvVar = "KAPP_CONFIG_FILE"
+	userConfigAPIVersion = "kapp.k14s.io/v1alpha1"
+	userConfigKind       = "UserConfig"
+)
+
+// UserConfig provides default flag values so that they do not
+// need to be repeated on every invocation. Explicitly specified flags take precedence.
+//
+//	apiVersion: kapp.k14s.io/v1alpha1
+//	kind: UserConfig
+//	flags:
+//	  diff-context: 5
+//	commands:
+//	  deploy:
+//	    wait-timeout: 30m
+//	colors:
+//	  diffAdded: bold,blue
+//	checkForUpdates: true
+type UserConfig struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Flags apply to all commands that have such flags
+	Flags map[string]interface{} `json:"flags,omitempty"`
+	// Commands holds flags for specific commands (e.g. 'deploy', 'app-group deploy');
+	// they take precedence over flags applied to all commands
+	Commands map[string]map[string]interface{} `json:"commands,omitempty"`
+	// Colors override styles of selected color theme
+	// (diffAdded, diffRemoved, warning, decoration)
+	Colors map[string]string `json:"colors,omitempty"`
+	// CheckForUpdates enables periodic (cached) check for newer kapp release
+	CheckForUpdates bool `json:"checkForUpdates,omitempty"`
+}
+
+// NewUserConfigFromEnv reads config from KAPP_CONFIG_FILE or ~/.config/kapp/config.yml.
+// Returns empty config if default config file does not exist.
+func NewUserConfigFromEnv() (UserConfig, error) {
+	path, explicit := userConfigPath()
+	if len(path) == 0 {
+		return UserConfig{}, nil
+	}
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return UserConfig{}, nil
+		}
+		return UserConfig{}, fmt.Errorf("Reading user config '%s': %w", path, err)
+	}
+
+	var config UserConfig
+
+	err = yaml.UnmarshalStrict(bs, &config)
+	if err != nil {
+		return UserConfig{}, fmt.Errorf("Unmarshaling user config '%s': %w", path, err)
+	}
+
+	if config.APIVersion != userConfigAPIVersion || config.Kind != userConfigKind {
+		return UserConfig{}, fmt.Errorf("Expected user config '%s' to have apiVersion '%s' and kind '%s'",
+			path, userConfigAPIVersion, userConfigKind)
+	}
+
+	return config, nil
+}
+
+func userConfigPath() (string, bool) {
+	if path := os.Getenv(userConfigFileEnvVar); len(path) > 0 {
+		return path, true
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); len(dir) > 0 {
+		return filepath.Join(dir, "kapp", "config.yml"), false
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(homeDir, ".config", "kapp", "config.yml"), false
+}
+
+// Apply sets values of flags that were not explicitly specified
+func (c UserConfig) Apply(cmd *cobra.Command) error {
+	cmdName := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+
+	for _, name := range c.sortedKeys(c.Flags) {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			continue
+		}
+		err := c.setFlag(flag, c.Flags[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	cmdFlags := c.Commands[cmdName]
+
+	for _, name := range c.sortedKeys(cmdFlags) {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("Expected flag '%s' specified in user config for command '%s' to exist", name, cmdName)
+		}
+		err := c.setFlag(flag, cmdFlags[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c UserConfig) setFlag(flag *pflag.Flag, val interface{}) error {
+	if flag.Changed {
+		return nil
+	}
+
+	vals := []interface{}{val}
+	if typedVal, ok := val.([]interface{}); ok {
+		vals = typedVal
+	}
+
+	var strVals []string
+	for _, val := range vals {
+		strVals = append(strVals, c.flagValue(val))
+	}
+
+	// Setting value directly (instead of via flag set) keeps flag not marked as changed.
+	// Slice values are replaced so that command flags do not append to global flags.
+	if sliceVal, ok := flag.Value.(pflag.SliceValue); ok {
+		err := sliceVal.Replace(strVals)
+		if err != nil {
+			return fmt.Errorf("Setting flag '%s' from user config: %w", flag.Name, err)
+		}
+		return nil
+	}
+
+	for _, strVal := range strVals {
+		err := flag.Value.Set(strVal)
+		if err != nil {
+			return fmt.Errorf("Setting flag '%s' from user config: %w", flag.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// flagValue formats YAML decoded value; numbers are decoded as floats
+// which should not be formatted in exponent form (e.g. 1e+06)
+func (UserConfig) flagValue(val interface{}) string {
+	if typedVal, ok := val.(float64); ok {
+		return strconv.FormatFloat(typedVal, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+func (UserConfig) sortedKeys(m map[string]interface{}) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
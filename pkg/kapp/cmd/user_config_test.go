@@ -0,0 +1,58 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd"
+)
+
+func TestUserConfigApply(t *testing.T) {
+	var (
+		maxBytes   int
+		labels     []string
+		filterKind []string
+		diffCtx    int
+	)
+
+	rootCmd := &cobra.Command{Use: "kapp"}
+	deployCmd := &cobra.Command{Use: "deploy"}
+	deployCmd.Flags().IntVar(&maxBytes, "max-bytes", 0, "")
+	deployCmd.Flags().StringSliceVar(&labels, "labels", []string{"default"}, "")
+	deployCmd.Flags().StringArrayVar(&filterKind, "filter-kind", nil, "")
+	deployCmd.Flags().IntVar(&diffCtx, "diff-context", 2, "")
+	rootCmd.AddCommand(deployCmd)
+
+	require.NoError(t, deployCmd.Flags().Parse([]string{"--diff-context=7"}))
+
+	config := cmd.UserConfig{
+		Flags: map[string]interface{}{
+			// Numbers are decoded from YAML as floats
+			"max-bytes":    float64(2000000),
+			"labels":       []interface{}{"global1", "global2"},
+			"filter-kind":  "Global",
+			"diff-context": float64(5),
+		},
+		Commands: map[string]map[string]interface{}{
+			"deploy": {
+				"labels":      []interface{}{"cmd"},
+				"filter-kind": []interface{}{"Cmd1", "Cmd2"},
+			},
+		},
+	}
+
+	require.NoError(t, config.Apply(deployCmd))
+
+	require.Equal(t, 2000000, maxBytes)
+	// Command flags replace global flags instead of appending to them
+	require.Equal(t, []string{"cmd"}, labels)
+	require.Equal(t, []string{"Cmd1", "Cmd2"}, filterKind)
+	// Explicitly specified flags take precedence
+	require.Equal(t, 7, diffCtx)
+
+	require.False(t, deployCmd.Flags().Lookup("max-bytes").Changed)
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+)
+
+type VerbosityFlags struct {
+	Level int
+	Quiet bool
+}
+
+func (f *VerbosityFlags) Set(cmd *cobra.Command, _ cmdcore.FlagsFactory) {
+	cmd.PersistentFlags().IntVarP(&f.Level, "verbosity", "v", cmdcore.VerbosityDefault,
+		"Set verbosity level (0: summary and errors, 1: +changes, 2: +apply and wait progress, 3: +diffs, 4: +debug)")
+	cmd.PersistentFlags().BoolVar(&f.Quiet, "quiet", false, "Only show summary and errors (same as --verbosity=0)")
+}
+
+func (f *VerbosityFlags) Configure(depsFactory cmdcore.DepsFactory, logger *logger.UILogger) error {
+	level := f.Level
+	if f.Quiet {
+		level = cmdcore.VerbosityQuiet
+	}
+
+	err := cmdcore.ValidateVerbosity(level)
+	if err != nil {
+		return err
+	}
+
+	depsFactory.ConfigureVerbosity(level)
+
+	switch level {
+	case cmdcore.VerbosityQuiet:
+		depsFactory.ConfigurePrintTarget(false)
+	case cmdcore.VerbosityDebug:
+		logger.SetDebug(true)
+	}
+
+	return nil
+}
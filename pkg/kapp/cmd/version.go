@@ -14,10 +14,12 @@ import (
 
 type VersionOptions struct {
 	ui ui.UI
+
+	Check bool
 }
 
 func NewVersionOptions(ui ui.UI) *VersionOptions {
-	return &VersionOptions{ui}
+	return &VersionOptions{ui: ui}
 }
 
 func NewVersionCmd(o *VersionOptions, _ cmdcore.FlagsFactory) *cobra.Command {
@@ -25,12 +27,46 @@ func NewVersionCmd(o *VersionOptions, _ cmdcore.FlagsFactory) *cobra.Command {
 		Use:   "version",
 		Short: "Print client version",
 		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Print version
+  kapp version
+
+  # Check if newer release is available
+  kapp version --check`,
 	}
+	cmd.Flags().BoolVar(&o.Check, "check", false,
+		"Check whether newer release is available and whether there are security advisories affecting this version")
 	return cmd
 }
 
 func (o *VersionOptions) Run() error {
 	o.ui.PrintBlock([]byte(fmt.Sprintf("kapp version %s\n", version.Version)))
 
+	if !o.Check {
+		return nil
+	}
+
+	result, err := version.NewReleaseChecker().Check(version.Version)
+	if err != nil {
+		return fmt.Errorf("Checking for newer release: %w", err)
+	}
+
+	o.ui.PrintLinef("")
+
+	switch {
+	case result.HasNewer():
+		o.ui.PrintLinef("Newer release %s is available: %s", result.Latest.Version, result.Latest.URL)
+	case len(result.Latest.Version) > 0:
+		o.ui.PrintLinef("Latest release is %s", result.Latest.Version)
+	}
+
+	if len(result.Advisories) > 0 {
+		o.ui.PrintLinef("")
+		o.ui.PrintLinef("Security advisories affecting this version (%d):", len(result.Advisories))
+		for _, adv := range result.Advisories {
+			o.ui.PrintLinef("- %s (%s): %s %s", adv.ID, adv.Severity, adv.Summary, adv.URL)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,49 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/version"
+)
+
+// backgroundVersionCheck checks for newer release while command
+// runs (enabled via 'checkForUpdates' in user config); results are
+// cached so that API is queried at most once a day.
+type backgroundVersionCheck struct {
+	resultCh chan version.CheckResult
+}
+
+func newBackgroundVersionCheck() *backgroundVersionCheck {
+	check := &backgroundVersionCheck{resultCh: make(chan version.CheckResult, 1)}
+
+	go func() {
+		result, err := version.NewReleaseChecker().CachedCheck(version.Version)
+		if err == nil {
+			check.resultCh <- result
+		}
+	}()
+
+	return check
+}
+
+// Notify does not wait for check to finish so that commands are never delayed
+func (c *backgroundVersionCheck) Notify(ui ui.UI) {
+	if c == nil {
+		return
+	}
+
+	select {
+	case result := <-c.resultCh:
+		if result.HasNewer() {
+			ui.ErrorLinef("Newer kapp release %s is available (current: %s): %s",
+				result.Latest.Version, result.Current, result.Latest.URL)
+		}
+		if len(result.Advisories) > 0 {
+			ui.ErrorLinef("kapp %s is affected by %d security advisories (see 'kapp version --check')",
+				result.Current, len(result.Advisories))
+		}
+	default:
+	}
+}
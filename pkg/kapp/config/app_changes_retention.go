@@ -0,0 +1,44 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// AppChangesRetention controls which app changes are kept around
+// (changes exceeding either limit are garbage collected)
+type AppChangesRetention struct {
+	MaxToKeep *int   `json:"maxToKeep,omitempty"`
+	MaxAge    string `json:"maxAge,omitempty"`
+}
+
+func (r AppChangesRetention) Validate() error {
+	if r.MaxToKeep != nil && *r.MaxToKeep < 0 {
+		return fmt.Errorf("Expected maxToKeep to be non-negative")
+	}
+	if len(r.MaxAge) > 0 {
+		dur, err := time.ParseDuration(r.MaxAge)
+		if err != nil {
+			return fmt.Errorf("Parsing maxAge: %w", err)
+		}
+		if dur < 0 {
+			return fmt.Errorf("Expected maxAge to be non-negative")
+		}
+	}
+	return nil
+}
+
+// MaxAgeDuration returns zero if max age is not specified
+func (r AppChangesRetention) MaxAgeDuration() time.Duration {
+	if len(r.MaxAge) == 0 {
+		return 0
+	}
+	dur, err := time.ParseDuration(r.MaxAge)
+	if err != nil {
+		panic(fmt.Sprintf("Parsing app changes retention max age: %s", err))
+	}
+	return dur
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// ValidateClusterSourcedResource rejects config documents read from the cluster
+// (e.g. 'kapp-config' ConfigMap) that would make kapp run commands or plugins,
+// call external services, or read local files and environment variables.
+// Anyone who can write such ConfigMap would otherwise be able to run commands
+// with privileges (and cluster credentials) of kapp user.
+func ValidateClusterSourcedResource(res ctlres.Resource) error {
+	if res.APIVersion() != configAPIVersion || (res.Kind() != configKind && res.Kind() != policyKind) {
+		return fmt.Errorf("Expected only %s or %s documents (apiVersion: %s), but found '%s'",
+			configKind, policyKind, configAPIVersion, res.Description())
+	}
+	if res.Kind() != configKind {
+		return nil
+	}
+
+	config, err := NewConfigFromResource(res)
+	if err != nil {
+		return err
+	}
+
+	if config.EnvSubstitution {
+		return fmt.Errorf("Expected envSubstitution to not be enabled")
+	}
+	if len(config.ConfigFrom) > 0 {
+		return fmt.Errorf("Expected configFrom to not be specified")
+	}
+	for i, rule := range config.WaitRules {
+		if rule.Exec != nil || rule.Plugin != nil {
+			return fmt.Errorf("Expected wait rule %d to not use exec or plugin", i)
+		}
+	}
+	if len(config.PreflightRules) > 0 {
+		return fmt.Errorf("Expected preflight rules to not be specified")
+	}
+
+	return nil
+}
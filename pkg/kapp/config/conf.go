@@ -16,19 +16,66 @@ const (
 )
 
 type Conf struct {
-	configs []Config
+	configs        []Config
+	readinessGates []ReadinessGates
+	policies       []Policy
+	secrets        []SecretProviders
+	notifications  []Notifications
+
+	// Policies selected via WithPolicies
+	appliedPolicies []Policy
 }
 
 func NewConfFromResources(resources []ctlres.Resource) ([]ctlres.Resource, Conf, error) {
+	return NewConfFromResourcesWithOpts(resources, ConfOpts{})
+}
+
+func NewConfFromResourcesWithOpts(resources []ctlres.Resource, opts ConfOpts) ([]ctlres.Resource, Conf, error) {
 	var rsWithoutConfigs []ctlres.Resource
 	var configs []Config
+	var readinessGates []ReadinessGates
+	var policies []Policy
+	var secrets []SecretProviders
+	var notifications []Notifications
 
 	for _, res := range resources {
 		_, isLabeledAsConfig := res.Labels()[configLabelKey]
 
 		switch {
+		case res.APIVersion() == configAPIVersion && res.Kind() == readinessGatesKind:
+			gates, err := NewReadinessGatesFromResource(res)
+			if err != nil {
+				return nil, Conf{}, fmt.Errorf(
+					"Parsing resource '%s' as kapp readiness gates: %w", res.Description(), err)
+			}
+			readinessGates = append(readinessGates, gates)
+
+		case res.APIVersion() == configAPIVersion && res.Kind() == policyKind:
+			policy, err := newPolicyFromResource(res, opts)
+			if err != nil {
+				return nil, Conf{}, fmt.Errorf(
+					"Parsing resource '%s' as kapp policy: %w", res.Description(), err)
+			}
+			policies = append(policies, policy)
+
+		case res.APIVersion() == configAPIVersion && res.Kind() == secretProvidersKind:
+			providers, err := NewSecretProvidersFromResource(res)
+			if err != nil {
+				return nil, Conf{}, fmt.Errorf(
+					"Parsing resource '%s' as kapp secret providers: %w", res.Description(), err)
+			}
+			secrets = append(secrets, providers)
+
+		case res.APIVersion() == configAPIVersion && res.Kind() == notificationsKind:
+			notificationsConfig, err := NewNotificationsFromResource(res)
+			if err != nil {
+				return nil, Conf{}, fmt.Errorf(
+					"Parsing resource '%s' as kapp notifications: %w", res.Description(), err)
+			}
+			notifications = append(notifications, notificationsConfig)
+
 		case res.APIVersion() == configAPIVersion:
-			config, err := NewConfigFromResource(res)
+			config, err := newConfigFromResource(res, opts)
 			if err != nil {
 				return nil, Conf{}, fmt.Errorf(
 					"Parsing resource '%s' as kapp config: %w", res.Description(), err)
@@ -36,7 +83,7 @@ func NewConfFromResources(resources []ctlres.Resource) ([]ctlres.Resource, Conf,
 			configs = append(configs, config)
 
 		case isLabeledAsConfig:
-			config, err := newConfigFromConfigMapRes(res)
+			config, err := newConfigFromConfigMapRes(res, opts)
 			if err != nil {
 				return nil, Conf{}, fmt.Errorf(
 					"Parsing resource '%s' labeled as kapp config: %w", res.Description(), err)
@@ -53,10 +100,44 @@ func NewConfFromResources(resources []ctlres.Resource) ([]ctlres.Resource, Conf,
 		}
 	}
 
-	return rsWithoutConfigs, Conf{configs}, nil
+	configs, err := activeProfileConfigs(configs, opts.Profile)
+	if err != nil {
+		return nil, Conf{}, err
+	}
+
+	return rsWithoutConfigs, Conf{layerConfigs(configs), readinessGates, policies, secrets, notifications, nil}, nil
 }
 
-func newConfigFromConfigMapRes(res ctlres.Resource) (Config, error) {
+// activeProfileConfigs drops configs tagged with profiles other than selected one
+func activeProfileConfigs(configs []Config, profile string) ([]Config, error) {
+	var result []Config
+	var found bool
+
+	for _, config := range configs {
+		switch {
+		case len(config.Profile) == 0:
+			result = append(result, config)
+		case config.Profile == profile:
+			result = append(result, config)
+			found = true
+		}
+	}
+
+	if len(profile) > 0 && !found {
+		return nil, fmt.Errorf("Expected at least one kapp config with profile '%s'", profile)
+	}
+
+	return result, nil
+}
+
+// IsConfigResource returns true for resources that are consumed by kapp
+// as configuration and are not deployed. ConfigMaps labeled as kapp config
+// are deployed hence are not considered to be config resources.
+func IsConfigResource(res ctlres.Resource) bool {
+	return res.APIVersion() == configAPIVersion
+}
+
+func newConfigFromConfigMapRes(res ctlres.Resource, opts ConfOpts) (Config, error) {
 	if res.APIVersion() != "v1" || res.Kind() != "ConfigMap" {
 		errMsg := "Expected kapp config to be within v1/ConfigMap but apiVersion or kind do not match"
 		return Config{}, fmt.Errorf(errMsg, res.Description())
@@ -79,9 +160,12 @@ func newConfigFromConfigMapRes(res ctlres.Resource) (Config, error) {
 		return Config{}, fmt.Errorf("Parsing kapp config as resource: %w", err)
 	}
 
-	return NewConfigFromResource(configRes)
+	return newConfigFromResource(configRes, opts)
 }
 
+// Configs returns configs in the order they are applied
+func (c Conf) Configs() []Config { return c.configs }
+
 func (c Conf) RebaseMods() []ctlres.ResourceModWithMultiple {
 	var mods []ctlres.ResourceModWithMultiple
 	for _, config := range c.configs {
@@ -92,6 +176,54 @@ func (c Conf) RebaseMods() []ctlres.ResourceModWithMultiple {
 	return mods
 }
 
+// ExportMods returns mods that strip cluster populated fields
+// from resources so that they could be deployed elsewhere
+func (c Conf) ExportMods() []ctlres.FieldRemoveMod {
+	var mods []ctlres.FieldRemoveMod
+	for _, config := range c.configs {
+		for _, rule := range config.RebaseRules {
+			mods = append(mods, rule.AsExportMods()...)
+		}
+	}
+	return mods
+}
+
+func (c Conf) ApplyMutationMods() []ctlres.ResourceModWithMultiple {
+	var mods []ctlres.ResourceModWithMultiple
+	for _, config := range c.configs {
+		for _, rule := range config.ApplyMutationRules {
+			mods = append(mods, rule.AsMod())
+		}
+	}
+	return mods
+}
+
+// ApplyStrategyMods returns mods that set strategy annotations on matched
+// resources; annotations already specified by resources are kept
+func (c Conf) ApplyStrategyMods(res ctlres.Resource) []ctlres.StringMapAppendMod {
+	anns := map[string]string{}
+	for _, config := range c.configs {
+		for _, rule := range config.ApplyStrategyRules {
+			if rule.ResourceMatcher().Matches(res) {
+				for k, v := range rule.Annotations() {
+					anns[k] = v
+				}
+			}
+		}
+	}
+	for k := range res.Annotations() {
+		delete(anns, k)
+	}
+	if len(anns) == 0 {
+		return nil
+	}
+	return []ctlres.StringMapAppendMod{{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations"}),
+		KVs:             anns,
+	}}
+}
+
 func (c Conf) DiffAgainstLastAppliedFieldExclusionMods() []ctlres.FieldRemoveMod {
 	var mods []ctlres.FieldRemoveMod
 	for _, config := range c.configs {
@@ -132,6 +264,42 @@ func (c Conf) WaitRules() []WaitRule {
 	return rules
 }
 
+func (c Conf) PreflightRules() []PreflightRule {
+	var rules []PreflightRule
+	for _, config := range c.configs {
+		rules = append(rules, config.PreflightRules...)
+	}
+	return rules
+}
+
+func (c Conf) WaitBehaviorRules() []WaitBehaviorRule {
+	var rules []WaitBehaviorRule
+	for _, config := range c.configs {
+		rules = append(rules, config.WaitBehaviorRules...)
+	}
+	return rules
+}
+
+func (c Conf) FallbackOnReplaceMatcher() ctlres.ResourceMatcher {
+	var matchers []ctlres.ResourceMatcher
+	for _, config := range c.configs {
+		for _, rule := range config.FallbackOnReplaceRules {
+			matchers = append(matchers, rule.ResourceMatcher())
+		}
+	}
+	return ctlres.AnyMatcher{Matchers: matchers}
+}
+
+func (c Conf) NonBlockingWaitMatcher() ctlres.ResourceMatcher {
+	var matchers []ctlres.ResourceMatcher
+	for _, config := range c.configs {
+		for _, rule := range config.NonBlockingWaitRules {
+			matchers = append(matchers, rule.ResourceMatcher())
+		}
+	}
+	return ctlres.AnyMatcher{Matchers: matchers}
+}
+
 func (c Conf) LabelScopingMods(defaultRules bool) func(kvs map[string]string) []ctlres.StringMapAppendMod {
 	return func(kvs map[string]string) []ctlres.StringMapAppendMod {
 		var mods []ctlres.StringMapAppendMod
@@ -160,6 +328,9 @@ func (c Conf) DiffMaskRules() []DiffMaskRule {
 	for _, config := range c.configs {
 		result = append(result, config.DiffMaskRules...)
 	}
+	for _, policy := range c.appliedPolicies {
+		result = append(result, policy.DiffMaskRules...)
+	}
 	return result
 }
 
@@ -173,6 +344,108 @@ func (c Conf) AdditionalLabels() map[string]string {
 	return result
 }
 
+// AppLabels returns labels to be set on the app record
+func (c Conf) AppLabels() map[string]string {
+	result := map[string]string{}
+	for _, config := range c.configs {
+		for k, v := range config.AppLabels {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// AppAnnotations returns annotations to be set on the app record
+func (c Conf) AppAnnotations() map[string]string {
+	result := map[string]string{}
+	for _, config := range c.configs {
+		for k, v := range config.AppAnnotations {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// PropagateAppLabels returns keys of app labels that should be added to app resources
+func (c Conf) PropagateAppLabels() []string {
+	var result []string
+	for _, config := range c.configs {
+		result = append(result, config.PropagateAppLabels...)
+	}
+	return result
+}
+
+// AppChangesRetention merges retention settings from all configs
+// with later configs taking precedence
+func (c Conf) AppChangesRetention() AppChangesRetention {
+	var result AppChangesRetention
+	for _, config := range c.configs {
+		if config.AppChangesRetention == nil {
+			continue
+		}
+		if config.AppChangesRetention.MaxToKeep != nil {
+			result.MaxToKeep = config.AppChangesRetention.MaxToKeep
+		}
+		if len(config.AppChangesRetention.MaxAge) > 0 {
+			result.MaxAge = config.AppChangesRetention.MaxAge
+		}
+	}
+	return result
+}
+
+func (c Conf) ReadinessGates() []ReadinessGates {
+	return c.readinessGates
+}
+
+func (c Conf) SecretProviders() []SecretProviders {
+	return c.secrets
+}
+
+func (c Conf) Notifications() []Notification {
+	var result []Notification
+	for _, notifications := range c.notifications {
+		result = append(result, notifications.Notifications...)
+	}
+	return result
+}
+
+// WithDiffMaskRules returns conf that additionally enforces given diff mask rules
+func (c Conf) WithDiffMaskRules(rules []DiffMaskRule) Conf {
+	if len(rules) == 0 {
+		return c
+	}
+	c.configs = append(append([]Config{}, c.configs...), Config{DiffMaskRules: rules})
+	return c
+}
+
+// WithPolicies returns conf that enforces named policies.
+// Policies that are not referenced are ignored.
+func (c Conf) WithPolicies(names []string) (Conf, error) {
+	policiesByName := map[string]Policy{}
+	for _, policy := range c.policies {
+		if _, found := policiesByName[policy.Name()]; found {
+			return Conf{}, fmt.Errorf("Expected policy '%s' to be specified once", policy.Name())
+		}
+		policiesByName[policy.Name()] = policy
+	}
+
+	c.appliedPolicies = nil
+
+	for _, name := range names {
+		policy, found := policiesByName[name]
+		if !found {
+			return Conf{}, fmt.Errorf("Expected to find policy '%s'", name)
+		}
+		c.appliedPolicies = append(c.appliedPolicies, policy)
+	}
+
+	return c, nil
+}
+
+func (c Conf) Policies() []Policy {
+	return c.appliedPolicies
+}
+
 func (c Conf) ChangeGroupBindings() []ChangeGroupBinding {
 	var result []ChangeGroupBinding
 	for _, config := range c.configs {
@@ -188,3 +461,25 @@ func (c Conf) ChangeRuleBindings() []ChangeRuleBinding {
 	}
 	return result
 }
+
+// MatchingRule is a config rule that applies to a resource
+type MatchingRule struct {
+	Config  string
+	Rule    string
+	Details string
+}
+
+// MatchingRules returns rules from all configs (in the order they are applied)
+// whose resource matchers match given resource; useful for config debugging
+func (c Conf) MatchingRules(res ctlres.Resource) []MatchingRule {
+	var result []MatchingRule
+	for _, config := range c.configs {
+		for _, rule := range config.resourceMatchersByRule() {
+			matcher := ctlres.AnyMatcher{Matchers: ResourceMatchers(rule.Matchers).AsResourceMatchers()}
+			if matcher.Matches(res) {
+				result = append(result, MatchingRule{config.Description(), rule.Desc, rule.Details})
+			}
+		}
+	}
+	return result
+}
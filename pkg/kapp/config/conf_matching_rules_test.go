@@ -0,0 +1,53 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+func TestConfMatchingRules(t *testing.T) {
+	configs := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+metadata: {name: custom}
+changeGroupBindings:
+- name: apps.example.com/web
+  resourceMatchers:
+  - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: default, name: web}
+applyStrategyRules:
+- updateStrategy: fallback-on-replace
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+ownershipLabelRules:
+- path: [metadata, labels]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: apps/v1, kind: Deployment}
+`
+
+	resource := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: default
+`
+
+	_, conf, err := config.NewConfFromResources(mustResources(t, configs))
+	require.NoError(t, err)
+
+	rules := conf.MatchingRules(mustResources(t, resource)[0])
+	require.Len(t, rules, 2)
+
+	require.Equal(t, "apply strategy rule 0", rules[0].Rule)
+	require.Equal(t, "kapp.k14s.io/update-strategy: fallback-on-replace", rules[0].Details)
+
+	require.Equal(t, "change group binding 0", rules[1].Rule)
+	require.Equal(t, "group: apps.example.com/web", rules[1].Details)
+	require.Contains(t, rules[1].Config, "custom")
+}
@@ -5,6 +5,8 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	semver "github.com/hashicorp/go-version"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
@@ -23,6 +25,26 @@ type Config struct {
 	Kind       string
 
 	MinimumRequiredVersion string `json:"minimumRequiredVersion,omitempty"`
+	// StrictParsing makes unknown keys within this config an error
+	StrictParsing bool `json:"strictParsing,omitempty"`
+	// EnvSubstitution replaces ${VAR} (or ${VAR:-default}) within this config's
+	// values with environment variables; use $${ to keep ${ as is
+	EnvSubstitution bool `json:"envSubstitution,omitempty"`
+
+	// Metadata is ignored; allowed so that strictly parsed configs could include it
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// ConfigFrom imports additional configs (see ResolveConfigFrom)
+	ConfigFrom []ConfigFrom `json:"configFrom,omitempty"`
+
+	// Profile makes config active only when selected (e.g. via deploy --config-profile)
+	Profile string `json:"profile,omitempty"`
+
+	// Priority orders configs (defaults to 0); rules of higher priority configs take precedence
+	Priority int `json:"priority,omitempty"`
+	// ReplaceRules lists rule kinds (e.g. rebaseRules) whose rules from
+	// lower priority configs are dropped (annulled if this config does not specify any)
+	ReplaceRules []string `json:"replaceRules,omitempty"`
 
 	RebaseRules         []RebaseRule
 	WaitRules           []WaitRule
@@ -30,8 +52,20 @@ type Config struct {
 	LabelScopingRules   []LabelScopingRule
 	TemplateRules       []TemplateRule
 	DiffMaskRules       []DiffMaskRule
+	ApplyMutationRules  []ApplyMutationRule
+
+	FallbackOnReplaceRules []FallbackOnReplaceRule
+	NonBlockingWaitRules   []NonBlockingWaitRule
+	WaitBehaviorRules      []WaitBehaviorRule
+	ApplyStrategyRules     []ApplyStrategyRule
+	PreflightRules         []PreflightRule
+
+	AppChangesRetention *AppChangesRetention
 
 	AdditionalLabels                          map[string]string
+	AppLabels                                 map[string]string
+	AppAnnotations                            map[string]string
+	PropagateAppLabels                        []string
 	DiffAgainstLastAppliedFieldExclusionRules []DiffAgainstLastAppliedFieldExclusionRule
 	DiffAgainstExistingFieldExclusionRules    []DiffAgainstExistingFieldExclusionRule
 
@@ -39,6 +73,10 @@ type Config struct {
 	// TODO validations
 	ChangeGroupBindings []ChangeGroupBinding
 	ChangeRuleBindings  []ChangeRuleBinding
+
+	desc          string
+	raw           map[string]interface{}
+	replacedRules []string
 }
 
 type WaitRule struct {
@@ -46,6 +84,8 @@ type WaitRule struct {
 	ConditionMatchers          []WaitRuleConditionMatcher
 	ResourceMatchers           []ResourceMatcher
 	Ytt                        *WaitRuleYtt
+	Exec                       *WaitRuleExec
+	Plugin                     *WaitRulePlugin
 }
 
 type WaitRuleConditionMatcher struct {
@@ -69,6 +109,24 @@ type FuncContractV1 struct {
 	Resource string `json:"resource.star"`
 }
 
+// WaitRuleExec runs external command to determine resource state.
+// Command receives resource as JSON on stdin and is expected
+// to print JSON object with done, successful, message and unblockChanges keys.
+type WaitRuleExec struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+	// Defaults to 1m
+	Timeout string
+}
+
+// WaitRulePlugin calls waitEvaluator capability of a plugin to determine resource state
+type WaitRulePlugin struct {
+	PluginRef
+	// Timeout limits each check (plugin process is reused across checks); defaults to 1m
+	Timeout string
+}
+
 type RebaseRule struct {
 	ResourceMatchers []ResourceMatcher
 
@@ -86,12 +144,79 @@ type RebaseRuleYtt struct {
 	// Multiple contracts will be offered at the same time
 	// so that existing rules do not not break as we decide to evolve running environment.
 	OverlayContractV1 *RebaseRuleYttOverlayContractV1 `json:"overlayContractV1"`
+	FuncContractV1    *RebaseRuleYttFuncContractV1    `json:"funcContractV1"`
 }
 
 type RebaseRuleYttOverlayContractV1 struct {
 	OverlayYAML string `json:"overlay.yml"`
 }
 
+// RebaseRuleYttFuncContractV1 expects Starlark file
+// to define rebase_resource(resource, sources) function
+type RebaseRuleYttFuncContractV1 struct {
+	Resource string `json:"resource.star"`
+}
+
+type ApplyMutationRule struct {
+	ResourceMatchers []ResourceMatcher
+
+	Path  ctlres.Path
+	Type  string
+	Value interface{}
+}
+
+// FallbackOnReplaceRule makes matched resources use fallback-on-replace
+// update strategy unless they explicitly specify update strategy annotation
+type FallbackOnReplaceRule struct {
+	ResourceMatchers []ResourceMatcher
+}
+
+// NonBlockingWaitRule makes wait failures of matched resources
+// reported as warnings instead of failing deploy
+type NonBlockingWaitRule struct {
+	ResourceMatchers []ResourceMatcher
+}
+
+// ApplyStrategyRule sets create, update and delete strategy annotations
+// (e.g. kapp.k14s.io/update-strategy) on matched resources
+// unless resources explicitly specify them. Later rules take precedence.
+type ApplyStrategyRule struct {
+	ResourceMatchers []ResourceMatcher
+
+	CreateStrategy string
+	UpdateStrategy string
+	DeleteStrategy string
+}
+
+const (
+	applyStrategyCreateAnnKey = "kapp.k14s.io/create-strategy"
+	applyStrategyUpdateAnnKey = "kapp.k14s.io/update-strategy"
+	applyStrategyDeleteAnnKey = "kapp.k14s.io/delete-strategy"
+)
+
+var (
+	applyStrategyCreateValues = []string{"fallback-on-update", "fallback-on-update-or-noop"}
+	applyStrategyUpdateValues = []string{"fallback-on-replace", "always-replace", "skip"}
+	applyStrategyDeleteValues = []string{"orphan", "scale-to-zero"}
+)
+
+const (
+	WaitBehaviorTypeDisable    = "disable"
+	WaitBehaviorTypeNoop       = "noop"
+	WaitBehaviorTypeConditions = "conditions"
+)
+
+// WaitBehaviorRule changes how matched resources are waited on
+// (similar to kapp.k14s.io/disable-wait annotation, but per kind or group of resources).
+// Types: disable (do not wait at all), noop (consider added or updated resource
+// done as soon as it's applied; deletions are still waited on), conditions
+// (in addition to regular checks, wait for listed conditions to be True).
+type WaitBehaviorRule struct {
+	ResourceMatchers []ResourceMatcher
+	Type             string
+	Conditions       []string
+}
+
 type DiffAgainstLastAppliedFieldExclusionRule struct {
 	ResourceMatchers []ResourceMatcher
 	Path             ctlres.Path
@@ -121,6 +246,10 @@ type TemplateRule struct {
 type DiffMaskRule struct {
 	ResourceMatchers []ResourceMatcher
 	Path             ctlres.Path
+	// Paths could be used to mask multiple fields of matched resources
+	Paths []ctlres.Path
+	// Enforce keeps masking even when it's disabled via --diff-mask=false
+	Enforce bool
 }
 
 type TemplateAffectedResources struct {
@@ -145,7 +274,23 @@ type ChangeRuleBinding struct {
 	ResourceMatchers []ResourceMatcher
 }
 
+// ConfOpts controls how kapp config documents are parsed
+type ConfOpts struct {
+	// StrictParsing makes unknown keys within all configs an error
+	StrictParsing bool
+	// EnvSubstitution enables environment variable substitution within all configs and policies
+	EnvSubstitution bool
+	// Profile selects configs tagged with this profile (untagged configs are always active)
+	Profile string
+	// LookupEnv defaults to os.LookupEnv
+	LookupEnv func(string) (string, bool)
+}
+
 func NewConfigFromResource(res ctlres.Resource) (Config, error) {
+	return newConfigFromResource(res, ConfOpts{})
+}
+
+func newConfigFromResource(res ctlres.Resource, opts ConfOpts) (Config, error) {
 	if res.APIVersion() != configAPIVersion {
 		return Config{}, fmt.Errorf(
 			"Expected kapp config to have apiVersion '%s', but was '%s'",
@@ -163,21 +308,51 @@ func NewConfigFromResource(res ctlres.Resource) (Config, error) {
 		return Config{}, err
 	}
 
-	return newConfigFromYAMLBytes(bs, res.Description())
+	return newConfigFromYAMLBytes(bs, res.Description(), opts)
 }
 
-func newConfigFromYAMLBytes(bs []byte, description string) (Config, error) {
+func newConfigFromYAMLBytes(bs []byte, description string, opts ConfOpts) (Config, error) {
 	var config Config
 	err := yaml.Unmarshal(bs, &config)
 	if err != nil {
 		return Config{}, fmt.Errorf("Unmarshaling %s: %w", description, err)
 	}
 
+	if opts.EnvSubstitution || config.EnvSubstitution {
+		bs, err = substituteEnvVars(bs, opts.LookupEnv)
+		if err != nil {
+			return Config{}, fmt.Errorf("Substituting environment variables in %s: %w", description, err)
+		}
+
+		config = Config{}
+
+		err = yaml.Unmarshal(bs, &config)
+		if err != nil {
+			return Config{}, fmt.Errorf("Unmarshaling %s: %w", description, err)
+		}
+	}
+
+	if opts.StrictParsing || config.StrictParsing {
+		// Catch misspelled keys (e.g. rebaseRulez) that are otherwise ignored
+		err := yaml.UnmarshalStrict(bs, &Config{})
+		if err != nil {
+			return Config{}, fmt.Errorf("Unmarshaling %s strictly: %w", description, err)
+		}
+	}
+
 	err = config.Validate()
 	if err != nil {
 		return Config{}, fmt.Errorf("Validating config: %w", err)
 	}
 
+	config.desc = description
+
+	// Keep config as specified to show it via 'kapp config effective'
+	err = yaml.Unmarshal(bs, &config.raw)
+	if err != nil {
+		return Config{}, fmt.Errorf("Unmarshaling %s: %w", description, err)
+	}
+
 	return config, nil
 }
 
@@ -217,14 +392,177 @@ func (c Config) Validate() error {
 		}
 	}
 
+	for i, rule := range c.WaitRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating wait rule %d: %w", i, err)
+		}
+	}
+
+	err := validateReplaceRules(c.ReplaceRules)
+	if err != nil {
+		return fmt.Errorf("Validating replace rules: %w", err)
+	}
+
+	for _, rule := range c.resourceMatchersByRule() {
+		err := ResourceMatchers(rule.Matchers).Validate()
+		if err != nil {
+			return fmt.Errorf("Validating %s: %w", rule.Desc, err)
+		}
+	}
+
+	for i, rule := range c.WaitBehaviorRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating wait behavior rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.ApplyMutationRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating apply mutation rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.ApplyStrategyRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating apply strategy rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.DiffMaskRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating diff mask rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range c.PreflightRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating preflight rule %d: %w", i, err)
+		}
+	}
+
+	if c.AppChangesRetention != nil {
+		err := c.AppChangesRetention.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating app changes retention: %w", err)
+		}
+	}
+
 	return nil
 }
 
+type ruleResourceMatchers struct {
+	Desc     string
+	Matchers []ResourceMatcher
+	// Details briefly describes what rule does (optional)
+	Details string
+}
+
+func (c Config) resourceMatchersByRule() []ruleResourceMatchers {
+	var result []ruleResourceMatchers
+	add := func(desc string, i int, matchers []ResourceMatcher, details string) {
+		result = append(result, ruleResourceMatchers{fmt.Sprintf("%s %d", desc, i), matchers, details})
+	}
+	for i, rule := range c.RebaseRules {
+		add("rebase rule", i, rule.ResourceMatchers, rule.details())
+	}
+	for i, rule := range c.WaitRules {
+		add("wait rule", i, rule.ResourceMatchers, rule.details())
+	}
+	for i, rule := range c.OwnershipLabelRules {
+		add("ownership label rule", i, rule.ResourceMatchers, "path: "+rule.Path.AsString())
+	}
+	for i, rule := range c.LabelScopingRules {
+		add("label scoping rule", i, rule.ResourceMatchers, "path: "+rule.Path.AsString())
+	}
+	for i, rule := range c.TemplateRules {
+		add("template rule", i, rule.ResourceMatchers, "")
+	}
+	for i, rule := range c.DiffMaskRules {
+		add("diff mask rule", i, rule.ResourceMatchers, "")
+	}
+	for i, rule := range c.ApplyMutationRules {
+		add("apply mutation rule", i, rule.ResourceMatchers, "")
+	}
+	for i, rule := range c.FallbackOnReplaceRules {
+		add("fallback on replace rule", i, rule.ResourceMatchers, "")
+	}
+	for i, rule := range c.NonBlockingWaitRules {
+		add("non-blocking wait rule", i, rule.ResourceMatchers, "")
+	}
+	for i, rule := range c.WaitBehaviorRules {
+		add("wait behavior rule", i, rule.ResourceMatchers, "type: "+rule.Type)
+	}
+	for i, rule := range c.ApplyStrategyRules {
+		add("apply strategy rule", i, rule.ResourceMatchers, rule.details())
+	}
+	for i, rule := range c.PreflightRules {
+		add("preflight rule", i, rule.ResourceMatchers, "name: "+rule.Name)
+	}
+	for i, rule := range c.ChangeGroupBindings {
+		add("change group binding", i, rule.ResourceMatchers, "group: "+rule.Name)
+	}
+	for i, rule := range c.ChangeRuleBindings {
+		add("change rule binding", i, rule.ResourceMatchers, "rules: "+strings.Join(rule.Rules, "; "))
+	}
+	return result
+}
+
+func (r RebaseRule) details() string {
+	if r.Ytt != nil {
+		return "ytt"
+	}
+	paths := r.Paths
+	if len(r.Path) > 0 {
+		paths = append([]ctlres.Path{r.Path}, paths...)
+	}
+	var pathStrs []string
+	for _, path := range paths {
+		pathStrs = append(pathStrs, path.AsString())
+	}
+	return fmt.Sprintf("type: %s, paths: %s", r.Type, strings.Join(pathStrs, ", "))
+}
+
+func (r WaitRule) details() string {
+	switch {
+	case r.Ytt != nil:
+		return "ytt"
+	case r.Exec != nil:
+		return "exec"
+	case r.Plugin != nil:
+		return "plugin"
+	}
+	var types []string
+	for _, matcher := range r.ConditionMatchers {
+		types = append(types, matcher.Type)
+	}
+	return "conditions: " + strings.Join(types, ", ")
+}
+
+func (r ApplyStrategyRule) details() string {
+	anns := r.Annotations()
+	var pieces []string
+	for _, key := range []string{applyStrategyCreateAnnKey, applyStrategyUpdateAnnKey, applyStrategyDeleteAnnKey} {
+		if val, found := anns[key]; found {
+			pieces = append(pieces, key+": "+val)
+		}
+	}
+	return strings.Join(pieces, ", ")
+}
+
 func (r RebaseRule) Validate() error {
 	if r.Ytt != nil {
 		if len(r.Path) > 0 || len(r.Paths) > 0 || len(r.Type) > 0 || len(r.Sources) > 0 {
 			return fmt.Errorf("Expected only resourceMatchers specified with ytt configuration")
 		}
+		if (r.Ytt.OverlayContractV1 != nil) == (r.Ytt.FuncContractV1 != nil) {
+			return fmt.Errorf("Expected exactly one of overlayContractV1 or funcContractV1 specified with ytt configuration")
+		}
 		return nil
 	}
 	if len(r.Path) > 0 && len(r.Paths) > 0 {
@@ -247,8 +585,16 @@ func (r RebaseRule) AsMods() []ctlres.ResourceModWithMultiple {
 				OverlayYAML: r.Ytt.OverlayContractV1.OverlayYAML,
 			}}
 
+		case r.Ytt.FuncContractV1 != nil:
+			return []ctlres.ResourceModWithMultiple{yttresmod.FuncContractV1Mod{
+				ResourceMatcher: ctlres.AnyMatcher{
+					Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+				},
+				Starlark: r.Ytt.FuncContractV1.Resource,
+			}}
+
 		default:
-			panic("Unknown rebase rule ytt contract (supported: overlayContractV1)")
+			panic("Unknown rebase rule ytt contract (supported: overlayContractV1, funcContractV1)")
 		}
 	}
 
@@ -288,6 +634,195 @@ func (r RebaseRule) AsMods() []ctlres.ResourceModWithMultiple {
 	return mods
 }
 
+// AsExportMods returns mods that remove fields which rebase rule
+// allows to be populated by the cluster (i.e. copied from existing resource).
+// Rules covering whole top level sections (e.g. metadata) are skipped.
+func (r RebaseRule) AsExportMods() []ctlres.FieldRemoveMod {
+	if r.Ytt != nil || r.Type != "copy" {
+		return nil
+	}
+
+	var fromExisting bool
+	for _, src := range r.Sources {
+		if src == ctlres.FieldCopyModSourceExisting {
+			fromExisting = true
+		}
+	}
+	if !fromExisting {
+		return nil
+	}
+
+	paths := r.Paths
+	if len(paths) == 0 {
+		paths = []ctlres.Path{r.Path}
+	}
+
+	var mods []ctlres.FieldRemoveMod
+	for _, path := range paths {
+		if len(path) < 2 {
+			continue
+		}
+		mods = append(mods, ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AnyMatcher{
+				Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+			},
+			Path: path,
+		})
+	}
+	return mods
+}
+
+func (r WaitRule) Validate() error {
+	if r.Plugin != nil {
+		if r.Exec != nil || r.Ytt != nil || len(r.ConditionMatchers) > 0 {
+			return fmt.Errorf("Expected only one of plugin, exec, ytt or conditionMatchers specified")
+		}
+		err := r.Plugin.Validate()
+		if err != nil {
+			return err
+		}
+		if len(r.Plugin.Timeout) > 0 {
+			if _, err := time.ParseDuration(r.Plugin.Timeout); err != nil {
+				return fmt.Errorf("Parsing plugin timeout: %w", err)
+			}
+		}
+	}
+	if r.Exec != nil {
+		if r.Ytt != nil || len(r.ConditionMatchers) > 0 {
+			return fmt.Errorf("Expected only one of exec, ytt or conditionMatchers specified")
+		}
+		if len(r.Exec.Command) == 0 {
+			return fmt.Errorf("Expected exec command to be specified")
+		}
+		if len(r.Exec.Timeout) > 0 {
+			if _, err := time.ParseDuration(r.Exec.Timeout); err != nil {
+				return fmt.Errorf("Parsing exec timeout: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r WaitBehaviorRule) Validate() error {
+	switch r.Type {
+	case WaitBehaviorTypeDisable, WaitBehaviorTypeNoop:
+		if len(r.Conditions) > 0 {
+			return fmt.Errorf("Expected conditions to only be specified for type '%s'", WaitBehaviorTypeConditions)
+		}
+	case WaitBehaviorTypeConditions:
+		if len(r.Conditions) == 0 {
+			return fmt.Errorf("Expected conditions to be specified for type '%s'", WaitBehaviorTypeConditions)
+		}
+	default:
+		return fmt.Errorf("Unknown type '%s' (supported: %s, %s, %s)", r.Type,
+			WaitBehaviorTypeDisable, WaitBehaviorTypeNoop, WaitBehaviorTypeConditions)
+	}
+	return nil
+}
+
+func (r DiffMaskRule) Validate() error {
+	if len(r.AllPaths()) == 0 {
+		return fmt.Errorf("Expected path or paths to be specified")
+	}
+	return nil
+}
+
+// AllPaths returns paths to be masked
+func (r DiffMaskRule) AllPaths() []ctlres.Path {
+	var result []ctlres.Path
+	if len(r.Path) > 0 {
+		result = append(result, r.Path)
+	}
+	return append(result, r.Paths...)
+}
+
+func (r ApplyStrategyRule) Validate() error {
+	if len(r.CreateStrategy) == 0 && len(r.UpdateStrategy) == 0 && len(r.DeleteStrategy) == 0 {
+		return fmt.Errorf("Expected at least one of create, update or delete strategy to be specified")
+	}
+	strategies := []struct {
+		Name   string
+		Value  string
+		Values []string
+	}{
+		{"create", r.CreateStrategy, applyStrategyCreateValues},
+		{"update", r.UpdateStrategy, applyStrategyUpdateValues},
+		{"delete", r.DeleteStrategy, applyStrategyDeleteValues},
+	}
+	for _, strategy := range strategies {
+		if len(strategy.Value) == 0 {
+			continue
+		}
+		var found bool
+		for _, val := range strategy.Values {
+			if val == strategy.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Unknown %s strategy '%s' (supported: %s)",
+				strategy.Name, strategy.Value, strings.Join(strategy.Values, ", "))
+		}
+	}
+	return nil
+}
+
+// Annotations returns strategy annotations that should be set
+func (r ApplyStrategyRule) Annotations() map[string]string {
+	result := map[string]string{}
+	if len(r.CreateStrategy) > 0 {
+		result[applyStrategyCreateAnnKey] = r.CreateStrategy
+	}
+	if len(r.UpdateStrategy) > 0 {
+		result[applyStrategyUpdateAnnKey] = r.UpdateStrategy
+	}
+	if len(r.DeleteStrategy) > 0 {
+		result[applyStrategyDeleteAnnKey] = r.DeleteStrategy
+	}
+	return result
+}
+
+func (r ApplyStrategyRule) ResourceMatcher() ctlres.ResourceMatcher {
+	return ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}
+}
+
+func (r ApplyMutationRule) Validate() error {
+	if len(r.Path) == 0 {
+		return fmt.Errorf("Expected path to be specified")
+	}
+	switch r.Type {
+	case "set":
+		if r.Value == nil {
+			return fmt.Errorf("Expected value to be specified for type 'set'")
+		}
+	case "remove":
+		if r.Value != nil {
+			return fmt.Errorf("Expected value to not be specified for type 'remove'")
+		}
+	default:
+		return fmt.Errorf("Unknown type '%s' (supported: set, remove)", r.Type)
+	}
+	return nil
+}
+
+func (r ApplyMutationRule) AsMod() ctlres.ResourceModWithMultiple {
+	matcher := ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}
+
+	switch r.Type {
+	case "set":
+		return ctlres.FieldSetMod{ResourceMatcher: matcher, Path: r.Path, Value: r.Value}
+	case "remove":
+		return ctlres.FieldRemoveMod{ResourceMatcher: matcher, Path: r.Path}
+	default:
+		panic(fmt.Sprintf("Unknown apply mutation rule type: %s (supported: set, remove)", r.Type))
+	}
+}
+
 func (r DiffAgainstLastAppliedFieldExclusionRule) AsMod() ctlres.FieldRemoveMod {
 	return ctlres.FieldRemoveMod{
 		ResourceMatcher: ctlres.AnyMatcher{
@@ -326,6 +861,24 @@ func (r LabelScopingRule) AsMod(kvs map[string]string) ctlres.StringMapAppendMod
 	}
 }
 
+func (r FallbackOnReplaceRule) ResourceMatcher() ctlres.ResourceMatcher {
+	return ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}
+}
+
+func (r NonBlockingWaitRule) ResourceMatcher() ctlres.ResourceMatcher {
+	return ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}
+}
+
+func (r WaitBehaviorRule) ResourceMatcher() ctlres.ResourceMatcher {
+	return ctlres.AnyMatcher{
+		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
+	}
+}
+
 func (r WaitRule) ResourceMatcher() ctlres.ResourceMatcher {
 	return ctlres.AnyMatcher{
 		Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers(),
@@ -0,0 +1,210 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	configFromMaxDepth         = 10
+	configFromDefaultConfigKey = "config.yml"
+)
+
+// ConfigFrom imports config documents from a local file,
+// ConfigMap or HTTPS URL (sha256 checksum is required for URLs)
+type ConfigFrom struct {
+	Path         string                  `json:"path,omitempty"`
+	URL          string                  `json:"url,omitempty"`
+	SHA256       string                  `json:"sha256,omitempty"`
+	ConfigMapRef *ConfigFromConfigMapRef `json:"configMapRef,omitempty"`
+}
+
+type ConfigFromConfigMapRef struct {
+	Name string `json:"name"`
+	// Defaults to app namespace
+	Namespace string `json:"namespace,omitempty"`
+	// Defaults to config.yml
+	Key string `json:"key,omitempty"`
+}
+
+// ConfigFromOpts provides access to sources of imported configs
+type ConfigFromOpts struct {
+	FileSystem fs.FS
+	// ConfigMapFunc returns contents of ConfigMap key
+	ConfigMapFunc func(ConfigFromConfigMapRef) ([]byte, error)
+}
+
+func (c ConfigFrom) Validate() error {
+	var sources int
+	for _, isSet := range []bool{len(c.Path) > 0, len(c.URL) > 0, c.ConfigMapRef != nil} {
+		if isSet {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("Expected exactly one of path, url or configMapRef to be specified")
+	}
+	if len(c.URL) > 0 {
+		if !strings.HasPrefix(c.URL, "https://") {
+			return fmt.Errorf("Expected url '%s' to use https", c.URL)
+		}
+		if len(c.SHA256) == 0 {
+			return fmt.Errorf("Expected sha256 to be specified for url '%s'", c.URL)
+		}
+	}
+	if c.ConfigMapRef != nil && len(c.ConfigMapRef.Name) == 0 {
+		return fmt.Errorf("Expected configMapRef name to be specified")
+	}
+	return nil
+}
+
+func (c ConfigFrom) description() string {
+	switch {
+	case len(c.Path) > 0:
+		return fmt.Sprintf("file '%s'", c.Path)
+	case len(c.URL) > 0:
+		return fmt.Sprintf("url '%s'", c.URL)
+	default:
+		return fmt.Sprintf("config map '%s' (namespace: %s, key: %s)",
+			c.ConfigMapRef.Name, c.ConfigMapRef.Namespace, c.configMapKey())
+	}
+}
+
+func (c ConfigFrom) configMapKey() string {
+	if len(c.ConfigMapRef.Key) > 0 {
+		return c.ConfigMapRef.Key
+	}
+	return configFromDefaultConfigKey
+}
+
+func (c ConfigFrom) bytes(opts ConfigFromOpts) ([]byte, error) {
+	var bs []byte
+	var err error
+
+	switch {
+	case len(c.Path) > 0:
+		bs, err = ctlres.NewLocalFileSource(opts.FileSystem, c.Path).Bytes()
+	case len(c.URL) > 0:
+		bs, err = ctlres.NewHTTPFileSource(c.URL).Bytes()
+	default:
+		if opts.ConfigMapFunc == nil {
+			return nil, fmt.Errorf("Expected access to cluster to import config from config map")
+		}
+		ref := *c.ConfigMapRef
+		ref.Key = c.configMapKey()
+		bs, err = opts.ConfigMapFunc(ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.SHA256) > 0 {
+		sum := sha256.Sum256(bs)
+		if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, c.SHA256) {
+			return nil, fmt.Errorf("Expected sha256 to be '%s', but was '%s'", c.SHA256, actual)
+		}
+	}
+
+	return bs, nil
+}
+
+// ResolveConfigFrom adds config documents imported via configFrom
+// right before configs that import them (so that importing configs take precedence).
+// Imported configs could import other configs.
+func ResolveConfigFrom(resources []ctlres.Resource, opts ConfigFromOpts) ([]ctlres.Resource, error) {
+	return resolveConfigFrom(resources, opts, 0)
+}
+
+func resolveConfigFrom(resources []ctlres.Resource, opts ConfigFromOpts, depth int) ([]ctlres.Resource, error) {
+	var result []ctlres.Resource
+
+	for _, res := range resources {
+		if res.APIVersion() != configAPIVersion || res.Kind() != configKind {
+			result = append(result, res)
+			continue
+		}
+
+		configFroms, err := configFromsForResource(res)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(configFroms) > 0 && depth >= configFromMaxDepth {
+			return nil, fmt.Errorf("Expected configFrom imports to be at most %d levels deep (check for import cycles)",
+				configFromMaxDepth)
+		}
+
+		for _, configFrom := range configFroms {
+			imported, err := configFrom.resources(opts)
+			if err != nil {
+				return nil, fmt.Errorf("Importing config from %s for '%s': %w",
+					configFrom.description(), res.Description(), err)
+			}
+
+			imported, err = resolveConfigFrom(imported, opts, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, imported...)
+		}
+
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+func configFromsForResource(res ctlres.Resource) ([]ConfigFrom, error) {
+	bs, err := res.AsYAMLBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		ConfigFrom []ConfigFrom `json:"configFrom"`
+	}
+
+	err = yaml.Unmarshal(bs, &config)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling %s: %w", res.Description(), err)
+	}
+
+	for i, configFrom := range config.ConfigFrom {
+		err := configFrom.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("Validating configFrom %d of '%s': %w", i, res.Description(), err)
+		}
+	}
+
+	return config.ConfigFrom, nil
+}
+
+func (c ConfigFrom) resources(opts ConfigFromOpts) ([]ctlres.Resource, error) {
+	bs, err := c.bytes(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, err := ctlres.NewFileResource(ctlres.NewBytesSource(bs)).Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range rs {
+		if !IsConfigResource(res) {
+			return nil, fmt.Errorf("Expected to only contain kapp config documents, but found '%s'", res.Description())
+		}
+	}
+
+	return rs, nil
+}
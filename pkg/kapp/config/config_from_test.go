@@ -0,0 +1,131 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+func TestResolveConfigFrom(t *testing.T) {
+	commonYAML := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+additionalLabels:
+  common: "true"
+  env: common
+`
+	fsys := fstest.MapFS{
+		"common.yml": &fstest.MapFile{Data: []byte(commonYAML)},
+		"nested.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+configFrom:
+- path: common.yml
+additionalLabels:
+  nested: "true"
+`)},
+		"cycle.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+configFrom:
+- path: cycle.yml
+`)},
+		"not-config.yml": &fstest.MapFile{Data: []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`)},
+	}
+
+	configMapFunc := func(ref config.ConfigFromConfigMapRef) ([]byte, error) {
+		if ref.Name == "shared-config" && ref.Key == "config.yml" {
+			return []byte(commonYAML), nil
+		}
+		return nil, fmt.Errorf("Not found")
+	}
+
+	resolve := func(yaml string) (config.Conf, error) {
+		rs, err := config.ResolveConfigFrom(mustResources(t, yaml),
+			config.ConfigFromOpts{FileSystem: fsys, ConfigMapFunc: configMapFunc})
+		if err != nil {
+			return config.Conf{}, err
+		}
+		_, conf, err := config.NewConfFromResources(rs)
+		return conf, err
+	}
+
+	t.Run("imports configs before importing config", func(t *testing.T) {
+		conf, err := resolve(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+configFrom:
+- path: nested.yml
+- configMapRef: {name: shared-config}
+additionalLabels:
+  env: app
+`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"common": "true", "nested": "true", "env": "app"}, conf.AdditionalLabels())
+	})
+
+	t.Run("verifies checksum", func(t *testing.T) {
+		sum := sha256.Sum256([]byte(commonYAML))
+
+		_, err := resolve(fmt.Sprintf(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+configFrom:
+- path: common.yml
+  sha256: %s
+`, hex.EncodeToString(sum[:])))
+		require.NoError(t, err)
+
+		_, err = resolve(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+configFrom:
+- path: common.yml
+  sha256: abc
+`)
+		require.ErrorContains(t, err, "Expected sha256 to be 'abc'")
+	})
+
+	t.Run("requires checksum for urls", func(t *testing.T) {
+		_, err := resolve(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+configFrom:
+- url: https://example.com/config.yml
+`)
+		require.ErrorContains(t, err, "Expected sha256 to be specified for url 'https://example.com/config.yml'")
+	})
+
+	t.Run("fails on import cycles", func(t *testing.T) {
+		_, err := resolve(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+configFrom:
+- path: cycle.yml
+`)
+		require.ErrorContains(t, err, "Expected configFrom imports to be at most 10 levels deep")
+	})
+
+	t.Run("fails on non-config documents", func(t *testing.T) {
+		_, err := resolve(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+configFrom:
+- path: not-config.yml
+`)
+		require.ErrorContains(t, err, "Expected to only contain kapp config documents")
+	})
+}
@@ -0,0 +1,280 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestStrictParsing(t *testing.T) {
+	misspelledConfig := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRulez:
+- path: [data]
+  type: copy
+  sources: [new, existing]
+  resourceMatchers:
+  - allMatcher: {}
+`
+
+	t.Run("ignores unknown keys by default", func(t *testing.T) {
+		_, _, err := config.NewConfFromResourcesWithDefaults(mustResources(t, misspelledConfig))
+		require.NoError(t, err)
+	})
+
+	t.Run("fails on unknown keys when enabled via opts", func(t *testing.T) {
+		_, _, err := config.NewConfFromResourcesWithDefaultsAndOpts(
+			mustResources(t, misspelledConfig), config.ConfOpts{StrictParsing: true})
+		require.ErrorContains(t, err, `unknown field "rebaseRulez"`)
+	})
+
+	t.Run("fails on unknown keys when enabled within config", func(t *testing.T) {
+		_, _, err := config.NewConfFromResourcesWithDefaults(
+			mustResources(t, misspelledConfig+"strictParsing: true\n"))
+		require.ErrorContains(t, err, `unknown field "rebaseRulez"`)
+	})
+
+	t.Run("allows known keys and metadata", func(t *testing.T) {
+		validConfig := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+metadata:
+  name: config
+rebaseRules:
+- path: [data]
+  type: copy
+  sources: [new, existing]
+  resourceMatchers:
+  - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: ns, name: cm}
+`
+		_, _, err := config.NewConfFromResourcesWithDefaultsAndOpts(
+			mustResources(t, validConfig), config.ConfOpts{StrictParsing: true})
+		require.NoError(t, err)
+	})
+}
+
+func TestApplyStrategyRules(t *testing.T) {
+	rs := mustResources(t, `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+applyStrategyRules:
+- updateStrategy: fallback-on-replace
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: batch/v1, kind: Job}
+- deleteStrategy: orphan
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: PersistentVolumeClaim}
+  - apiVersionKindMatcher: {apiVersion: batch/v1, kind: Job}
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: pvc
+  annotations:
+    kapp.k14s.io/delete-strategy: ""
+`)
+
+	rs, conf, err := config.NewConfFromResourcesWithDefaults(rs)
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	for _, res := range rs {
+		for _, mod := range conf.ApplyStrategyMods(res) {
+			require.NoError(t, mod.Apply(res))
+		}
+	}
+
+	require.Equal(t, map[string]string{
+		"kapp.k14s.io/update-strategy": "fallback-on-replace",
+		"kapp.k14s.io/delete-strategy": "orphan",
+	}, rs[0].Annotations())
+
+	// Explicitly specified annotations are kept
+	require.Equal(t, map[string]string{"kapp.k14s.io/delete-strategy": ""}, rs[1].Annotations())
+
+	_, _, err = config.NewConfFromResourcesWithDefaults(mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+applyStrategyRules:
+- updateStrategy: fallback-on-update
+  resourceMatchers:
+  - allMatcher: {}
+`))
+	require.ErrorContains(t, err, "Validating apply strategy rule 0: Unknown update strategy 'fallback-on-update'")
+}
+
+func TestPreflightRules(t *testing.T) {
+	_, conf, err := config.NewConfFromResourcesWithDefaultsAndOpts(mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: naming
+  exec:
+    command: check-naming
+  timeout: 5s
+- name: quota
+  webhook:
+    url: https://quota.example.com/check
+  config:
+    max: 10
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Secret}
+`), config.ConfOpts{StrictParsing: true})
+	require.NoError(t, err)
+
+	rules := conf.PreflightRules()
+	require.Len(t, rules, 2)
+	require.Equal(t, "check-naming", rules[0].Exec.Command)
+	require.Equal(t, "5s", rules[0].TimeoutDuration().String())
+	require.Equal(t, "https://quota.example.com/check", rules[1].Webhook.URL)
+	require.Equal(t, "30s", rules[1].TimeoutDuration().String())
+	require.Equal(t, map[string]interface{}{"max": float64(10)}, rules[1].Config)
+
+	_, _, err = config.NewConfFromResourcesWithDefaults(mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: both
+  exec: {command: check}
+  webhook: {url: https://example.com}
+`))
+	require.ErrorContains(t, err, "Validating preflight rule 0: Expected exactly one of exec, webhook or plugin to be specified")
+
+	for _, url := range []string{"http://localhost:8080/check", "http://127.0.0.1/check", "http://[::1]/check"} {
+		_, _, err = config.NewConfFromResourcesWithDefaults(mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: local
+  webhook: {url: "`+url+`"}
+`))
+		require.NoError(t, err, "URL: %s", url)
+	}
+
+	_, _, err = config.NewConfFromResourcesWithDefaults(mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: plain
+  webhook: {url: "http://quota.example.com/check"}
+`))
+	require.ErrorContains(t, err, "Validating preflight rule 0: Expected webhook url to use https scheme (http is only allowed for loopback hosts)")
+}
+
+func TestValidateClusterSourcedResource(t *testing.T) {
+	allowed := mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRules:
+- path: [data, keep]
+  type: copy
+  sources: [existing, new]
+  resourceMatchers:
+  - allMatcher: {}
+waitRules:
+- conditionMatchers:
+  - {type: Ready, status: "True", success: true}
+  resourceMatchers:
+  - allMatcher: {}
+`)
+	require.NoError(t, config.ValidateClusterSourcedResource(allowed[0]))
+
+	examples := []struct {
+		Description string
+		YAML        string
+		Err         string
+	}{
+		{"exec wait rule", `
+waitRules:
+- exec: {command: check}
+  resourceMatchers:
+  - allMatcher: {}`, "Expected wait rule 0 to not use exec or plugin"},
+		{"plugin wait rule", `
+waitRules:
+- plugin: {command: check}
+  resourceMatchers:
+  - allMatcher: {}`, "Expected wait rule 0 to not use exec or plugin"},
+		{"preflight rule", `
+preflightRules:
+- name: quota
+  webhook: {url: https://quota.example.com/check}`, "Expected preflight rules to not be specified"},
+		{"config import", `
+configFrom:
+- path: /etc/kapp/config.yml`, "Expected configFrom to not be specified"},
+		{"env substitution", `
+envSubstitution: true`, "Expected envSubstitution to not be enabled"},
+	}
+
+	for _, example := range examples {
+		t.Run(example.Description, func(t *testing.T) {
+			rs := mustResources(t, "apiVersion: kapp.k14s.io/v1alpha1\nkind: Config\n"+example.YAML)
+			require.EqualError(t, config.ValidateClusterSourcedResource(rs[0]), example.Err)
+		})
+	}
+
+	notifications := mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Notifications
+notifications:
+- url: https://example.com
+`)
+	require.ErrorContains(t, config.ValidateClusterSourcedResource(notifications[0]),
+		"Expected only Config or Policy documents (apiVersion: kapp.k14s.io/v1alpha1), but found")
+}
+
+func TestConfigProfiles(t *testing.T) {
+	configsYAML := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+additionalLabels:
+  shared: "true"
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+profile: dev
+additionalLabels:
+  env: dev
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+profile: prod
+additionalLabels:
+  env: prod
+`
+
+	t.Run("only includes configs without profile by default", func(t *testing.T) {
+		_, conf, err := config.NewConfFromResources(mustResources(t, configsYAML))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"shared": "true"}, conf.AdditionalLabels())
+	})
+
+	t.Run("includes configs with selected profile", func(t *testing.T) {
+		_, conf, err := config.NewConfFromResourcesWithOpts(mustResources(t, configsYAML), config.ConfOpts{Profile: "prod"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"shared": "true", "env": "prod"}, conf.AdditionalLabels())
+	})
+
+	t.Run("fails when no config has selected profile", func(t *testing.T) {
+		_, _, err := config.NewConfFromResourcesWithOpts(mustResources(t, configsYAML), config.ConfOpts{Profile: "stage"})
+		require.ErrorContains(t, err, "Expected at least one kapp config with profile 'stage'")
+	})
+}
+
+func mustResources(t *testing.T, yaml string) []ctlres.Resource {
+	rs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(yaml))).Resources()
+	require.NoError(t, err)
+	return rs
+}
@@ -701,18 +701,26 @@ changeRuleBindings:
       - hasNamespaceMatcher: {}
 `
 
+const defaultConfigDesc = "config/default (kapp.k14s.io/v1alpha1)"
+
 func NewDefaultConfigString() string { return defaultConfigYAML }
 
 func NewConfFromResourcesWithDefaults(resources []ctlres.Resource) ([]ctlres.Resource, Conf, error) {
-	resources, conf, err := NewConfFromResources(resources)
+	return NewConfFromResourcesWithDefaultsAndOpts(resources, ConfOpts{})
+}
+
+func NewConfFromResourcesWithDefaultsAndOpts(resources []ctlres.Resource, opts ConfOpts) ([]ctlres.Resource, Conf, error) {
+	resources, conf, err := NewConfFromResourcesWithOpts(resources, opts)
 	if err != nil {
 		return nil, Conf{}, err
 	}
 
-	defaultConfig, err := newConfigFromYAMLBytes([]byte(defaultConfigYAML), "config/default (kapp.k14s.io/v1alpha1)")
+	defaultConfig, err := newConfigFromYAMLBytes([]byte(defaultConfigYAML), defaultConfigDesc, opts)
 	if err != nil {
 		return nil, Conf{}, err
 	}
 
-	return resources, Conf{append([]Config{defaultConfig}, conf.configs...)}, err
+	configs := layerConfigs(append([]Config{defaultConfig}, conf.configs...))
+
+	return resources, Conf{configs, conf.readinessGates, conf.policies, conf.secrets, conf.notifications, conf.appliedPolicies}, err
 }
@@ -0,0 +1,87 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// Matches $${ (escaped), ${VAR} and ${VAR:-default}
+	envRefRegexp = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+)
+
+// substituteEnvVars replaces environment variable references within
+// string values of a YAML document (keys are kept as is).
+// Referenced variables without default value are expected to be set.
+func substituteEnvVars(bs []byte, lookupEnv func(string) (string, bool)) ([]byte, error) {
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	var obj interface{}
+
+	err := yaml.Unmarshal(bs, &obj)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err = substituteEnvVarsInObj(obj, lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(obj)
+}
+
+func substituteEnvVarsInObj(obj interface{}, lookupEnv func(string) (string, bool)) (interface{}, error) {
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range typedObj {
+			newV, err := substituteEnvVarsInObj(v, lookupEnv)
+			if err != nil {
+				return nil, err
+			}
+			typedObj[k] = newV
+		}
+		return typedObj, nil
+
+	case []interface{}:
+		for i, v := range typedObj {
+			newV, err := substituteEnvVarsInObj(v, lookupEnv)
+			if err != nil {
+				return nil, err
+			}
+			typedObj[i] = newV
+		}
+		return typedObj, nil
+
+	case string:
+		var lastErr error
+
+		result := envRefRegexp.ReplaceAllStringFunc(typedObj, func(ref string) string {
+			if ref == "$${" {
+				return "${"
+			}
+			pieces := envRefRegexp.FindStringSubmatch(ref)
+			if val, found := lookupEnv(pieces[1]); found {
+				return val
+			}
+			if len(pieces[2]) > 0 {
+				return pieces[3]
+			}
+			lastErr = fmt.Errorf("Expected environment variable '%s' to be set", pieces[1])
+			return ref
+		})
+
+		return result, lastErr
+
+	default:
+		return obj, nil
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+func TestEnvSubstitution(t *testing.T) {
+	configYAML := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+additionalLabels:
+  env: "${KAPP_ENV}"
+  region: "${KAPP_REGION:-us-east-1}"
+  literal: "$${KAPP_ENV}"
+`
+	lookupEnv := func(name string) (string, bool) {
+		if name == "KAPP_ENV" {
+			return "staging", true
+		}
+		return "", false
+	}
+
+	t.Run("keeps references as is by default", func(t *testing.T) {
+		_, conf, err := config.NewConfFromResources(mustResources(t, configYAML))
+		require.NoError(t, err)
+		require.Equal(t, "${KAPP_ENV}", conf.AdditionalLabels()["env"])
+	})
+
+	t.Run("substitutes references when enabled via opts", func(t *testing.T) {
+		_, conf, err := config.NewConfFromResourcesWithOpts(mustResources(t, configYAML),
+			config.ConfOpts{EnvSubstitution: true, LookupEnv: lookupEnv})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"env":     "staging",
+			"region":  "us-east-1",
+			"literal": "${KAPP_ENV}",
+		}, conf.AdditionalLabels())
+	})
+
+	t.Run("substitutes references when enabled within config", func(t *testing.T) {
+		_, conf, err := config.NewConfFromResourcesWithOpts(mustResources(t, configYAML+"envSubstitution: true\n"),
+			config.ConfOpts{LookupEnv: lookupEnv})
+		require.NoError(t, err)
+		require.Equal(t, "staging", conf.AdditionalLabels()["env"])
+	})
+
+	t.Run("fails when referenced variable is not set", func(t *testing.T) {
+		_, _, err := config.NewConfFromResourcesWithOpts(mustResources(t, configYAML+`  other: "${KAPP_OTHER}"`+"\n"),
+			config.ConfOpts{EnvSubstitution: true, LookupEnv: lookupEnv})
+		require.ErrorContains(t, err, "Expected environment variable 'KAPP_OTHER' to be set")
+	})
+}
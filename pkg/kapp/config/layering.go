@@ -0,0 +1,96 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// replaceableRules maps rule kinds that could be listed in replaceRules
+// to functions that clear such rules from a config
+var replaceableRules = map[string]func(*Config){
+	"rebaseRules":         func(c *Config) { c.RebaseRules = nil },
+	"waitRules":           func(c *Config) { c.WaitRules = nil },
+	"ownershipLabelRules": func(c *Config) { c.OwnershipLabelRules = nil },
+	"labelScopingRules":   func(c *Config) { c.LabelScopingRules = nil },
+	"templateRules":       func(c *Config) { c.TemplateRules = nil },
+	"diffMaskRules":       func(c *Config) { c.DiffMaskRules = nil },
+	"applyMutationRules":  func(c *Config) { c.ApplyMutationRules = nil },
+
+	"fallbackOnReplaceRules": func(c *Config) { c.FallbackOnReplaceRules = nil },
+	"nonBlockingWaitRules":   func(c *Config) { c.NonBlockingWaitRules = nil },
+	"waitBehaviorRules":      func(c *Config) { c.WaitBehaviorRules = nil },
+	"applyStrategyRules":     func(c *Config) { c.ApplyStrategyRules = nil },
+	"preflightRules":         func(c *Config) { c.PreflightRules = nil },
+
+	"diffAgainstLastAppliedFieldExclusionRules": func(c *Config) { c.DiffAgainstLastAppliedFieldExclusionRules = nil },
+	"diffAgainstExistingFieldExclusionRules":    func(c *Config) { c.DiffAgainstExistingFieldExclusionRules = nil },
+
+	"changeGroupBindings": func(c *Config) { c.ChangeGroupBindings = nil },
+	"changeRuleBindings":  func(c *Config) { c.ChangeRuleBindings = nil },
+}
+
+func validateReplaceRules(kinds []string) error {
+	for _, kind := range kinds {
+		if _, found := replaceableRules[kind]; !found {
+			var known []string
+			for kind := range replaceableRules {
+				known = append(known, kind)
+			}
+			sort.Strings(known)
+			return fmt.Errorf("Unknown rule kind '%s' (known: %s)", kind, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// layerConfigs orders configs by priority (keeping original order
+// for configs with same priority: default config, cluster config, provided config)
+// and clears rules of lower priority configs that are replaced by higher priority ones.
+// Rules of later configs take precedence (e.g. rebase rules are applied in order).
+func layerConfigs(configs []Config) []Config {
+	result := append([]Config{}, configs...)
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Priority < result[j].Priority })
+
+	for i := len(result) - 1; i >= 0; i-- {
+		for _, kind := range result[i].ReplaceRules {
+			for j := 0; j < i; j++ {
+				replaceableRules[kind](&result[j])
+				result[j].replacedRules = append(result[j].replacedRules, kind)
+			}
+		}
+	}
+
+	return result
+}
+
+// Description returns where config came from
+func (c Config) Description() string { return c.desc }
+
+// IsDefault returns true for config that kapp includes by default
+func (c Config) IsDefault() bool { return c.desc == defaultConfigDesc }
+
+// EffectiveYAML returns config as it was specified, without rules
+// replaced by higher priority configs
+func (c Config) EffectiveYAML() ([]byte, error) {
+	raw := map[string]interface{}{}
+	for k, v := range c.raw {
+		raw[k] = v
+	}
+
+	for _, kind := range c.replacedRules {
+		for k := range raw {
+			if strings.EqualFold(k, kind) {
+				delete(raw, k)
+			}
+		}
+	}
+
+	return yaml.Marshal(raw)
+}
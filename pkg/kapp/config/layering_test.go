@@ -0,0 +1,97 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+func TestConfigLayering(t *testing.T) {
+	configs := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+metadata: {name: high}
+priority: 10
+rebaseRules:
+- path: [data, high]
+  type: copy
+  sources: [existing]
+  resourceMatchers:
+  - allMatcher: {}
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+metadata: {name: low}
+rebaseRules:
+- path: [data, low]
+  type: copy
+  sources: [existing]
+  resourceMatchers:
+  - allMatcher: {}
+`
+
+	t.Run("orders configs by priority", func(t *testing.T) {
+		_, conf, err := config.NewConfFromResourcesWithDefaults(mustResources(t, configs))
+		require.NoError(t, err)
+
+		layers := conf.Configs()
+		require.Len(t, layers, 3)
+		require.True(t, layers[0].IsDefault())
+		require.Equal(t, "low", layers[1].Metadata["name"])
+		require.Equal(t, "high", layers[2].Metadata["name"])
+	})
+
+	t.Run("drops rules of replaced kinds from lower priority configs", func(t *testing.T) {
+		_, origConf, err := config.NewConfFromResourcesWithDefaults(mustResources(t, configs))
+		require.NoError(t, err)
+		require.Greater(t, len(origConf.RebaseMods()), 2)
+
+		replacing := configs + `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+metadata: {name: replacing}
+priority: 5
+replaceRules: [rebaseRules]
+rebaseRules:
+- path: [data, replacing]
+  type: copy
+  sources: [existing]
+  resourceMatchers:
+  - allMatcher: {}
+`
+
+		_, conf, err := config.NewConfFromResourcesWithDefaults(mustResources(t, replacing))
+		require.NoError(t, err)
+
+		layers := conf.Configs()
+		require.Len(t, layers, 4)
+		require.Equal(t, "replacing", layers[2].Metadata["name"])
+		require.Len(t, conf.RebaseMods(), 2)
+
+		for _, layer := range layers[:2] {
+			require.Empty(t, layer.RebaseRules)
+		}
+		// Other rule kinds are kept intact
+		require.NotEmpty(t, layers[0].TemplateRules)
+
+		bs, err := layers[1].EffectiveYAML()
+		require.NoError(t, err)
+		require.NotContains(t, string(bs), "rebaseRules")
+	})
+
+	t.Run("fails on unknown replaced rule kinds", func(t *testing.T) {
+		_, _, err := config.NewConfFromResourcesWithDefaults(mustResources(t, `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+replaceRules: [rebaseRulez]
+`))
+		require.ErrorContains(t, err, "Validating replace rules: Unknown rule kind 'rebaseRulez'")
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	notificationsKind = "Notifications"
+
+	NotificationFormatSlack   = "slack"
+	NotificationFormatWebhook = "webhook"
+
+	NotificationEventStarted   = "started"
+	NotificationEventSucceeded = "succeeded"
+	NotificationEventFailed    = "failed"
+)
+
+// Notifications configures where deploy lifecycle events are posted
+type Notifications struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string
+
+	Notifications []Notification
+}
+
+type Notification struct {
+	URL string
+	// URLFromEnv allows to keep URLs with embedded tokens out of manifests
+	URLFromEnv string `json:"urlFromEnv"`
+	// Defaults to webhook (JSON event is posted)
+	Format string
+	// Defaults to all events
+	Events []string
+	// Template is a Go text template executed against event;
+	// its output is posted as Slack message text or as webhook body
+	Template string
+}
+
+func NewNotificationsFromResource(res ctlres.Resource) (Notifications, error) {
+	bs, err := res.AsYAMLBytes()
+	if err != nil {
+		return Notifications{}, err
+	}
+
+	var notifications Notifications
+
+	err = yaml.Unmarshal(bs, &notifications)
+	if err != nil {
+		return Notifications{}, fmt.Errorf("Unmarshaling %s: %w", res.Description(), err)
+	}
+
+	err = notifications.Validate()
+	if err != nil {
+		return Notifications{}, fmt.Errorf("Validating notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (n Notifications) Validate() error {
+	for i, notification := range n.Notifications {
+		err := notification.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating notification %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (n Notification) Validate() error {
+	if (len(n.URL) > 0) == (len(n.URLFromEnv) > 0) {
+		return fmt.Errorf("Expected exactly one of url or urlFromEnv to be specified")
+	}
+
+	switch n.Format {
+	case "", NotificationFormatSlack, NotificationFormatWebhook:
+	default:
+		return fmt.Errorf("Unknown format '%s' (supported: %s, %s)",
+			n.Format, NotificationFormatSlack, NotificationFormatWebhook)
+	}
+
+	allEvents := []string{NotificationEventStarted, NotificationEventSucceeded, NotificationEventFailed}
+
+	for _, event := range n.Events {
+		var found bool
+		for _, knownEvent := range allEvents {
+			if event == knownEvent {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("Unknown event '%s' (supported: %s)", event, strings.Join(allEvents, ", "))
+		}
+	}
+
+	if len(n.Template) > 0 {
+		_, err := template.New("notification").Parse(n.Template)
+		if err != nil {
+			return fmt.Errorf("Parsing template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (n Notification) IsSubscribed(event string) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+	for _, subscribedEvent := range n.Events {
+		if subscribedEvent == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	policyKind = "Policy"
+
+	PolicyOperationCreate = "create"
+	PolicyOperationUpdate = "update"
+	PolicyOperationDelete = "delete"
+)
+
+// Policy bundles guardrails under a name so that they could be
+// distributed as a single document and enabled via --policy flag
+type Policy struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string
+	Metadata   PolicyMetadata
+	// EnvSubstitution replaces ${VAR} within values (same as in Config)
+	EnvSubstitution bool `json:"envSubstitution,omitempty"`
+
+	PreflightRules            []PolicyPreflightRule
+	DiffMaskRules             []DiffMaskRule
+	ProtectedResourceMatchers []ResourceMatcher
+	AllowedNamespaces         []string
+}
+
+type PolicyMetadata struct {
+	Name string
+}
+
+// PolicyPreflightRule rejects changes to matched resources before they are applied
+type PolicyPreflightRule struct {
+	ResourceMatchers []ResourceMatcher
+	// Defaults to all operations
+	Operations []string
+	Message    string
+}
+
+func NewPolicyFromResource(res ctlres.Resource) (Policy, error) {
+	return newPolicyFromResource(res, ConfOpts{})
+}
+
+func newPolicyFromResource(res ctlres.Resource, opts ConfOpts) (Policy, error) {
+	bs, err := res.AsYAMLBytes()
+	if err != nil {
+		return Policy{}, err
+	}
+
+	var policy Policy
+
+	err = yaml.Unmarshal(bs, &policy)
+	if err != nil {
+		return Policy{}, fmt.Errorf("Unmarshaling %s: %w", res.Description(), err)
+	}
+
+	if opts.EnvSubstitution || policy.EnvSubstitution {
+		bs, err = substituteEnvVars(bs, opts.LookupEnv)
+		if err != nil {
+			return Policy{}, fmt.Errorf("Substituting environment variables in %s: %w", res.Description(), err)
+		}
+
+		policy = Policy{}
+
+		err = yaml.Unmarshal(bs, &policy)
+		if err != nil {
+			return Policy{}, fmt.Errorf("Unmarshaling %s: %w", res.Description(), err)
+		}
+	}
+
+	err = policy.Validate()
+	if err != nil {
+		return Policy{}, fmt.Errorf("Validating policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (p Policy) Validate() error {
+	if len(p.Metadata.Name) == 0 {
+		return fmt.Errorf("Expected policy name to be specified")
+	}
+
+	err := ResourceMatchers(p.ProtectedResourceMatchers).Validate()
+	if err != nil {
+		return fmt.Errorf("Validating protected resource matchers: %w", err)
+	}
+
+	for i, rule := range p.DiffMaskRules {
+		err := rule.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating diff mask rule %d: %w", i, err)
+		}
+	}
+
+	for i, rule := range p.PreflightRules {
+		err := ResourceMatchers(rule.ResourceMatchers).Validate()
+		if err != nil {
+			return fmt.Errorf("Validating preflight rule %d: %w", i, err)
+		}
+		for _, op := range rule.Operations {
+			switch op {
+			case PolicyOperationCreate, PolicyOperationUpdate, PolicyOperationDelete:
+			default:
+				return fmt.Errorf("Validating preflight rule %d: Unknown operation '%s' (supported: %s, %s, %s)",
+					i, op, PolicyOperationCreate, PolicyOperationUpdate, PolicyOperationDelete)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p Policy) Name() string { return p.Metadata.Name }
+
+// Check returns violations for a change of given operation to a resource
+func (p Policy) Check(res ctlres.Resource, op string) []string {
+	var violations []string
+
+	for _, rule := range p.PreflightRules {
+		if rule.matchesOp(op) && rule.matcher().Matches(res) {
+			msg := fmt.Sprintf("Resource '%s' cannot be %sd (policy: %s)", res.Description(), op, p.Name())
+			if len(rule.Message) > 0 {
+				msg += ": " + rule.Message
+			}
+			violations = append(violations, msg)
+		}
+	}
+
+	if op == PolicyOperationDelete && len(p.ProtectedResourceMatchers) > 0 {
+		matcher := ctlres.AnyMatcher{Matchers: ResourceMatchers(p.ProtectedResourceMatchers).AsResourceMatchers()}
+		if matcher.Matches(res) {
+			violations = append(violations, fmt.Sprintf(
+				"Resource '%s' is protected from deletion (policy: %s)", res.Description(), p.Name()))
+		}
+	}
+
+	if op != PolicyOperationDelete && len(p.AllowedNamespaces) > 0 && len(res.Namespace()) > 0 {
+		var allowed bool
+		for _, ns := range p.AllowedNamespaces {
+			if ns == res.Namespace() {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			violations = append(violations, fmt.Sprintf(
+				"Resource '%s' is outside of allowed namespaces (policy: %s)", res.Description(), p.Name()))
+		}
+	}
+
+	return violations
+}
+
+func (r PolicyPreflightRule) matchesOp(op string) bool {
+	if len(r.Operations) == 0 {
+		return true
+	}
+	for _, ruleOp := range r.Operations {
+		if ruleOp == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (r PolicyPreflightRule) matcher() ctlres.ResourceMatcher {
+	if len(r.ResourceMatchers) == 0 {
+		return ctlres.AllMatcher{}
+	}
+	return ctlres.AnyMatcher{Matchers: ResourceMatchers(r.ResourceMatchers).AsResourceMatchers()}
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+const (
+	preflightRuleDefaultTimeout = 30 * time.Second
+)
+
+// PreflightRule runs external check (command or webhook) against
+// calculated changes before they are applied. Check receives changes
+// as PreflightCheckRequest JSON document and may deny them.
+type PreflightRule struct {
+	Name string
+	// Only changes of matched resources are sent to check (defaults to all);
+	// check is skipped when no changes match
+	ResourceMatchers []ResourceMatcher
+
+	Exec    *PreflightRuleExec
+	Webhook *PreflightRuleWebhook
+	Plugin  *PluginRef
+
+	// Config is passed to check as is (e.g. to parametrize shared checks)
+	Config map[string]interface{}
+
+	// Defaults to 30s
+	Timeout string
+}
+
+// PreflightRuleExec runs command with request on stdin;
+// non-zero exit code denies changes (stdout and stderr are shown as reason)
+type PreflightRuleExec struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+}
+
+// PreflightRuleWebhook posts request and expects PreflightCheckResponse JSON document;
+// non-2xx status code is treated as check error
+type PreflightRuleWebhook struct {
+	URL string
+	// Headers are added to request (e.g. for authentication)
+	Headers map[string]string
+}
+
+func (r PreflightRule) Validate() error {
+	if len(r.Name) == 0 {
+		return fmt.Errorf("Expected name to be specified")
+	}
+
+	var num int
+	for _, specified := range []bool{r.Exec != nil, r.Webhook != nil, r.Plugin != nil} {
+		if specified {
+			num++
+		}
+	}
+	if num != 1 {
+		return fmt.Errorf("Expected exactly one of exec, webhook or plugin to be specified")
+	}
+
+	if r.Exec != nil && len(r.Exec.Command) == 0 {
+		return fmt.Errorf("Expected exec command to be specified")
+	}
+
+	if r.Plugin != nil {
+		err := r.Plugin.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if r.Webhook != nil {
+		webhookURL, err := url.Parse(r.Webhook.URL)
+		if err != nil {
+			return fmt.Errorf("Parsing webhook url: %w", err)
+		}
+		// Requests include changed resources hence they are not sent in plain text over network
+		if webhookURL.Scheme != "https" && !(webhookURL.Scheme == "http" && isLoopbackHost(webhookURL.Hostname())) {
+			return fmt.Errorf("Expected webhook url to use https scheme (http is only allowed for loopback hosts)")
+		}
+	}
+
+	if len(r.Timeout) > 0 {
+		if _, err := time.ParseDuration(r.Timeout); err != nil {
+			return fmt.Errorf("Parsing timeout: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r PreflightRule) TimeoutDuration() time.Duration {
+	if len(r.Timeout) == 0 {
+		return preflightRuleDefaultTimeout
+	}
+	dur, err := time.ParseDuration(r.Timeout)
+	if err != nil {
+		panic(fmt.Sprintf("Expected duration to be validated: %s", err))
+	}
+	return dur
+}
+
+// PluginRef references plugin executable (see experimental/plugin package)
+type PluginRef struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+}
+
+func (r PluginRef) Validate() error {
+	if len(r.Command) == 0 {
+		return fmt.Errorf("Expected plugin command to be specified")
+	}
+	return nil
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	readinessGatesKind = "ReadinessGates"
+
+	readinessGatesDefaultTimeout       = 5 * time.Minute
+	readinessGatesDefaultCheckInterval = 5 * time.Second
+)
+
+// ReadinessGates lists external dependencies that
+// have to be ready before app changes are applied
+type ReadinessGates struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string
+
+	Gates []ReadinessGate
+
+	Timeout       string
+	CheckInterval string `json:"checkInterval"`
+}
+
+type ReadinessGate struct {
+	HTTP *ReadinessGateHTTP `json:"http"`
+	DNS  *ReadinessGateDNS  `json:"dns"`
+	App  *ReadinessGateApp  `json:"app"`
+}
+
+type ReadinessGateHTTP struct {
+	URL string `json:"url"`
+}
+
+type ReadinessGateDNS struct {
+	Name string
+}
+
+// ReadinessGateApp requires last change of another kapp app to be successful
+type ReadinessGateApp struct {
+	Name      string
+	Namespace string
+}
+
+func NewReadinessGatesFromResource(res ctlres.Resource) (ReadinessGates, error) {
+	bs, err := res.AsYAMLBytes()
+	if err != nil {
+		return ReadinessGates{}, err
+	}
+
+	var gates ReadinessGates
+
+	err = yaml.Unmarshal(bs, &gates)
+	if err != nil {
+		return ReadinessGates{}, fmt.Errorf("Unmarshaling %s: %w", res.Description(), err)
+	}
+
+	err = gates.Validate()
+	if err != nil {
+		return ReadinessGates{}, fmt.Errorf("Validating readiness gates: %w", err)
+	}
+
+	return gates, nil
+}
+
+func (g ReadinessGates) Validate() error {
+	for _, dur := range []string{g.Timeout, g.CheckInterval} {
+		if len(dur) > 0 {
+			if _, err := time.ParseDuration(dur); err != nil {
+				return fmt.Errorf("Parsing duration: %w", err)
+			}
+		}
+	}
+
+	for i, gate := range g.Gates {
+		var num int
+		if gate.HTTP != nil {
+			num++
+			if len(gate.HTTP.URL) == 0 {
+				return fmt.Errorf("Validating gate %d: Expected http url to be specified", i)
+			}
+		}
+		if gate.DNS != nil {
+			num++
+			if len(gate.DNS.Name) == 0 {
+				return fmt.Errorf("Validating gate %d: Expected dns name to be specified", i)
+			}
+		}
+		if gate.App != nil {
+			num++
+			if len(gate.App.Name) == 0 {
+				return fmt.Errorf("Validating gate %d: Expected app name to be specified", i)
+			}
+		}
+		if num != 1 {
+			return fmt.Errorf("Validating gate %d: Expected exactly one of http, dns or app to be specified", i)
+		}
+	}
+
+	return nil
+}
+
+func (g ReadinessGates) TimeoutDuration() time.Duration {
+	return g.parseDurationOrDefault(g.Timeout, readinessGatesDefaultTimeout)
+}
+
+func (g ReadinessGates) CheckIntervalDuration() time.Duration {
+	return g.parseDurationOrDefault(g.CheckInterval, readinessGatesDefaultCheckInterval)
+}
+
+func (ReadinessGates) parseDurationOrDefault(val string, defaultDur time.Duration) time.Duration {
+	if len(val) == 0 {
+		return defaultDur
+	}
+	dur, err := time.ParseDuration(val)
+	if err != nil {
+		panic(fmt.Sprintf("Expected duration to be validated: %s", err))
+	}
+	return dur
+}
+
+func (g ReadinessGate) Description() string {
+	switch {
+	case g.HTTP != nil:
+		return fmt.Sprintf("http '%s'", g.HTTP.URL)
+	case g.DNS != nil:
+		return fmt.Sprintf("dns '%s'", g.DNS.Name)
+	case g.App != nil:
+		if len(g.App.Namespace) > 0 {
+			return fmt.Sprintf("app '%s' (namespace: %s)", g.App.Name, g.App.Namespace)
+		}
+		return fmt.Sprintf("app '%s'", g.App.Name)
+	default:
+		return "unknown"
+	}
+}
@@ -5,8 +5,10 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type ResourceMatchers []ResourceMatcher
@@ -23,6 +25,10 @@ type ResourceMatcher struct {
 	HasNamespaceMatcher      *HasNamespaceMatcher
 	CustomResourceMatcher    *CustomResourceMatcher
 	EmptyFieldMatcher        *EmptyFieldMatcher
+	NameRegexMatcher         *NameRegexMatcher
+	NamePrefixMatcher        *NamePrefixMatcher
+	LabelSelectorMatcher     *LabelSelectorMatcher
+	AnnotationMatcher        *AnnotationMatcher
 }
 
 type AllMatcher struct{}
@@ -69,6 +75,70 @@ type EmptyFieldMatcher struct {
 	Path ctlres.Path
 }
 
+// NameRegexMatcher matches resources whose name matches regular expression
+// (use ^ and $ anchors to match whole name)
+type NameRegexMatcher struct {
+	Regex string
+}
+
+type NamePrefixMatcher struct {
+	Prefix string
+}
+
+// LabelSelectorMatcher matches resources using Kubernetes
+// label selector syntax (e.g. 'app=frontend,tier!=cache')
+type LabelSelectorMatcher struct {
+	Selector string
+}
+
+// AnnotationMatcher matches resources that have annotation
+// with one of the values (any value if values are not specified)
+type AnnotationMatcher struct {
+	Key    string
+	Values []string
+}
+
+func (ms ResourceMatchers) Validate() error {
+	for i, matcher := range ms {
+		err := matcher.Validate()
+		if err != nil {
+			return fmt.Errorf("Validating resource matcher %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (m ResourceMatcher) Validate() error {
+	switch {
+	case m.AnyMatcher != nil:
+		return ResourceMatchers(m.AnyMatcher.Matchers).Validate()
+
+	case m.AndMatcher != nil:
+		return ResourceMatchers(m.AndMatcher.Matchers).Validate()
+
+	case m.NotMatcher != nil:
+		return m.NotMatcher.Matcher.Validate()
+
+	case m.NameRegexMatcher != nil:
+		_, err := regexp.Compile(m.NameRegexMatcher.Regex)
+		if err != nil {
+			return fmt.Errorf("Parsing name regex: %w", err)
+		}
+
+	case m.LabelSelectorMatcher != nil:
+		_, err := labels.Parse(m.LabelSelectorMatcher.Selector)
+		if err != nil {
+			return fmt.Errorf("Parsing label selector: %w", err)
+		}
+
+	case m.AnnotationMatcher != nil:
+		if len(m.AnnotationMatcher.Key) == 0 {
+			return fmt.Errorf("Expected annotation matcher to specify key")
+		}
+	}
+	return nil
+}
+
 func (ms ResourceMatchers) AsResourceMatchers() []ctlres.ResourceMatcher {
 	var result []ctlres.ResourceMatcher
 	for _, matcher := range ms {
@@ -132,6 +202,26 @@ func (m ResourceMatcher) AsResourceMatcher() ctlres.ResourceMatcher {
 	case m.EmptyFieldMatcher != nil:
 		return ctlres.EmptyFieldMatcher{Path: m.EmptyFieldMatcher.Path}
 
+	// Regex and selector are expected to be checked via Validate
+	case m.NameRegexMatcher != nil:
+		return ctlres.NameRegexMatcher{Regex: regexp.MustCompile(m.NameRegexMatcher.Regex)}
+
+	case m.NamePrefixMatcher != nil:
+		return ctlres.NamePrefixMatcher{Prefix: m.NamePrefixMatcher.Prefix}
+
+	case m.LabelSelectorMatcher != nil:
+		sel, err := labels.Parse(m.LabelSelectorMatcher.Selector)
+		if err != nil {
+			panic(fmt.Sprintf("Parsing label selector: %s", err))
+		}
+		return ctlres.LabelSelectorMatcher{Selector: sel}
+
+	case m.AnnotationMatcher != nil:
+		return ctlres.AnnotationMatcher{
+			Key:    m.AnnotationMatcher.Key,
+			Values: m.AnnotationMatcher.Values,
+		}
+
 	default:
 		panic(fmt.Sprintf("Unknown resource matcher specified: %#v", m))
 	}
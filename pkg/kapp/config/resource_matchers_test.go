@@ -0,0 +1,82 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+func TestMetadataResourceMatchers(t *testing.T) {
+	rs := mustResources(t, `
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget-blue
+  labels:
+    tier: frontend
+  annotations:
+    example.com/owner: team-a
+---
+apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: gadget-red
+  labels:
+    tier: backend
+  annotations:
+    example.com/owner: team-b
+`)
+
+	matches := func(m config.ResourceMatcher) []bool {
+		require.NoError(t, m.Validate())
+		matcher := m.AsResourceMatcher()
+		return []bool{matcher.Matches(rs[0]), matcher.Matches(rs[1])}
+	}
+
+	require.Equal(t, []bool{true, false}, matches(config.ResourceMatcher{
+		NameRegexMatcher: &config.NameRegexMatcher{Regex: "^widget-.+$"}}))
+
+	require.Equal(t, []bool{false, true}, matches(config.ResourceMatcher{
+		NamePrefixMatcher: &config.NamePrefixMatcher{Prefix: "gadget-"}}))
+
+	require.Equal(t, []bool{false, true}, matches(config.ResourceMatcher{
+		LabelSelectorMatcher: &config.LabelSelectorMatcher{Selector: "tier in (backend,cache)"}}))
+
+	require.Equal(t, []bool{true, true}, matches(config.ResourceMatcher{
+		AnnotationMatcher: &config.AnnotationMatcher{Key: "example.com/owner"}}))
+
+	require.Equal(t, []bool{true, false}, matches(config.ResourceMatcher{
+		AnnotationMatcher: &config.AnnotationMatcher{Key: "example.com/owner", Values: []string{"team-a", "team-c"}}}))
+
+	require.Equal(t, []bool{false, false}, matches(config.ResourceMatcher{
+		AnnotationMatcher: &config.AnnotationMatcher{Key: "example.com/other"}}))
+}
+
+func TestInvalidResourceMatchersInConfig(t *testing.T) {
+	_, _, err := config.NewConfFromResourcesWithDefaults(mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+ownershipLabelRules:
+- path: [spec, template, metadata, labels]
+  resourceMatchers:
+  - nameRegexMatcher: {regex: "widget-("}
+`))
+	require.ErrorContains(t, err, "Validating ownership label rule 0: Validating resource matcher 0: Parsing name regex")
+
+	_, _, err = config.NewConfFromResourcesWithDefaults(mustResources(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+labelScopingRules:
+- path: [spec, selector]
+  resourceMatchers:
+  - notMatcher:
+      matcher:
+        labelSelectorMatcher: {selector: "tier in"}
+`))
+	require.ErrorContains(t, err, "Validating label scoping rule 0: Validating resource matcher 0: Parsing label selector")
+}
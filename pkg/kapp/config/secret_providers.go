@@ -0,0 +1,121 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	secretProvidersKind = "SecretProviders"
+)
+
+// SecretProviders maps secret references found in manifests
+// (e.g. '${secret:db-password}') to lookups in external secret stores
+type SecretProviders struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string
+
+	Providers []SecretProvider
+	Secrets   []SecretRef
+}
+
+type SecretProvider struct {
+	Name string
+
+	Vault             *SecretProviderVault             `json:"vault"`
+	AWSSecretsManager *SecretProviderAWSSecretsManager `json:"awsSecretsManager"`
+	GCPSecretManager  *SecretProviderGCPSecretManager  `json:"gcpSecretManager"`
+}
+
+type SecretProviderVault struct {
+	// Defaults to VAULT_ADDR environment variable
+	Address   string
+	Namespace string
+	// Defaults to VAULT_TOKEN
+	TokenEnv string `json:"tokenEnv"`
+}
+
+// SecretProviderAWSSecretsManager uses aws CLI (and its credentials chain)
+type SecretProviderAWSSecretsManager struct {
+	Region  string
+	Profile string
+}
+
+// SecretProviderGCPSecretManager uses gcloud CLI (and its credentials)
+type SecretProviderGCPSecretManager struct {
+	Project string
+}
+
+type SecretRef struct {
+	// Name is referenced in manifests as '${secret:<name>}'
+	Name     string
+	Provider string
+	// Key is Vault secret path, AWS secret ID or GCP secret name
+	Key string
+	// Field selects key within JSON secret value (or within Vault secret data)
+	Field string
+	// Version is AWS version stage or GCP secret version (defaults to latest)
+	Version string
+}
+
+func NewSecretProvidersFromResource(res ctlres.Resource) (SecretProviders, error) {
+	bs, err := res.AsYAMLBytes()
+	if err != nil {
+		return SecretProviders{}, err
+	}
+
+	var providers SecretProviders
+
+	err = yaml.Unmarshal(bs, &providers)
+	if err != nil {
+		return SecretProviders{}, fmt.Errorf("Unmarshaling %s: %w", res.Description(), err)
+	}
+
+	err = providers.Validate()
+	if err != nil {
+		return SecretProviders{}, fmt.Errorf("Validating secret providers: %w", err)
+	}
+
+	return providers, nil
+}
+
+func (p SecretProviders) Validate() error {
+	providerNames := map[string]struct{}{}
+
+	for i, provider := range p.Providers {
+		if len(provider.Name) == 0 {
+			return fmt.Errorf("Validating provider %d: Expected name to be specified", i)
+		}
+		if _, found := providerNames[provider.Name]; found {
+			return fmt.Errorf("Validating provider %d: Expected name '%s' to be unique", i, provider.Name)
+		}
+		providerNames[provider.Name] = struct{}{}
+
+		var num int
+		for _, isSet := range []bool{provider.Vault != nil, provider.AWSSecretsManager != nil, provider.GCPSecretManager != nil} {
+			if isSet {
+				num++
+			}
+		}
+		if num != 1 {
+			return fmt.Errorf("Validating provider %d: Expected exactly one of vault, "+
+				"awsSecretsManager or gcpSecretManager to be specified", i)
+		}
+	}
+
+	for i, secret := range p.Secrets {
+		if len(secret.Name) == 0 || len(secret.Key) == 0 {
+			return fmt.Errorf("Validating secret %d: Expected name and key to be specified", i)
+		}
+		if _, found := providerNames[secret.Provider]; !found {
+			return fmt.Errorf("Validating secret %d: Expected provider '%s' to be defined", i, secret.Provider)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"sync"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
+)
+
+type changePair struct {
+	ExistingRes ctlres.Resource
+	NewRes      ctlres.Resource
+}
+
+type changeFactoryFunc func(existingRes, newRes ctlres.Resource) (Change, error)
+
+// calculateChanges creates and diffs changes using up to concurrency workers
+// since diffing is CPU bound. Changes are returned in order of given pairs;
+// if multiple changes fail to be created, error of the first one is returned.
+func calculateChanges(pairs []changePair, factoryFunc changeFactoryFunc, concurrency int) ([]Change, error) {
+	changes := make([]Change, len(pairs))
+	errs := make([]error, len(pairs))
+
+	calculate := func(i int) {
+		changes[i], errs[i] = factoryFunc(pairs[i].ExistingRes, pairs[i].NewRes)
+		if errs[i] == nil {
+			// Op is memoized and requires calculating diff for updated resources
+			changes[i].Op()
+		}
+	}
+
+	if concurrency <= 1 || len(pairs) <= 1 {
+		for i := range pairs {
+			calculate(i)
+		}
+	} else {
+		throttle := util.NewThrottle(concurrency)
+		var wg sync.WaitGroup
+
+		for i := range pairs {
+			i := i
+			wg.Add(1)
+			throttle.Take()
+
+			go func() {
+				defer func() {
+					throttle.Done()
+					wg.Done()
+				}()
+				calculate(i)
+			}()
+		}
+
+		wg.Wait()
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
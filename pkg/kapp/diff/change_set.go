@@ -12,6 +12,10 @@ import (
 
 type ChangeSetOpts struct {
 	AgainstLastApplied bool
+
+	// Concurrency is maximum number of changes diffed concurrently
+	// (0 or 1 means changes are diffed one after another)
+	Concurrency int
 }
 
 type ChangeSet struct {
@@ -34,7 +38,7 @@ func (d ChangeSet) Calculate() ([]Change, error) {
 
 	existingRsMap := map[string]ctlres.Resource{}
 	alreadyChecked := map[string]struct{}{}
-	changes := []Change{}
+	pairs := []changePair{}
 
 	for _, existingRes := range d.existingRs {
 		existingRsMap[ctlres.NewUniqueResourceKey(existingRes).String()] = existingRes
@@ -42,44 +46,28 @@ func (d ChangeSet) Calculate() ([]Change, error) {
 
 	// Go through new set of resources and compare to existing set of resources
 	for _, newRes := range d.newRs {
-		newRes := newRes
 		newResKey := ctlres.NewUniqueResourceKey(newRes).String()
 
-		var change Change
-		var err error
-
-		if existingRes, found := existingRsMap[newResKey]; found {
-			change, err = changeFactoryFunc(existingRes, newRes)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			change, err = changeFactoryFunc(nil, newRes)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		changes = append(changes, change)
+		// Existing resource is nil if it's not found
+		pairs = append(pairs, changePair{ExistingRes: existingRsMap[newResKey], NewRes: newRes})
 		alreadyChecked[newResKey] = struct{}{}
 	}
 
 	// Find existing resources that were not already diffed (not in new set of resources)
 	for _, existingRes := range d.existingRs {
-		existingRes := existingRes
 		existingResKey := ctlres.NewUniqueResourceKey(existingRes).String()
 
 		if _, found := alreadyChecked[existingResKey]; !found {
-			change, err := changeFactoryFunc(existingRes, nil)
-			if err != nil {
-				return nil, err
-			}
-
-			changes = append(changes, change)
+			pairs = append(pairs, changePair{ExistingRes: existingRes})
 			alreadyChecked[existingResKey] = struct{}{}
 		}
 	}
 
+	changes, err := calculateChanges(pairs, changeFactoryFunc, d.opts.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
 	return d.collapseChangesWithSameUID(changes)
 }
 
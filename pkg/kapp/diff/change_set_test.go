@@ -4,6 +4,7 @@
 package diff_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -323,3 +324,47 @@ metadata:
 
 	require.Equal(t, expectedDiff, actualDiff, "Expected diff to match")
 }
+
+func TestChangeSet_ConcurrentlyDiffedChangesKeepOrder(t *testing.T) {
+	var existingRs, newRs []ctlres.Resource
+
+	for i := 0; i < 50; i++ {
+		existingRs = append(existingRs, ctlres.MustNewResourceFromBytes([]byte(fmt.Sprintf(`
+kind: ConfigMap
+metadata:
+  name: cm-%d
+data:
+  key: existing
+`, i))))
+		if i%2 == 0 {
+			newRs = append(newRs, ctlres.MustNewResourceFromBytes([]byte(fmt.Sprintf(`
+kind: ConfigMap
+metadata:
+  name: cm-%d
+data:
+  key: new
+`, i))))
+		}
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, nil, ctldiff.ChangeOpts{false})
+
+	describe := func(changes []ctldiff.Change) []string {
+		var result []string
+		for _, change := range changes {
+			result = append(result, string(change.Op())+" "+change.NewOrExistingResource().Name())
+		}
+		return result
+	}
+
+	serialChanges, err := ctldiff.NewChangeSet(existingRs, newRs, ctldiff.ChangeSetOpts{}, changeFactory).Calculate()
+	require.NoError(t, err)
+
+	concurrentChanges, err := ctldiff.NewChangeSet(existingRs, newRs, ctldiff.ChangeSetOpts{Concurrency: 8}, changeFactory).Calculate()
+	require.NoError(t, err)
+
+	require.Len(t, concurrentChanges, 50)
+	require.Equal(t, "update cm-0", describe(concurrentChanges)[0])
+	require.Equal(t, "delete cm-1", describe(concurrentChanges)[25])
+	require.Equal(t, describe(serialChanges), describe(concurrentChanges))
+}
@@ -163,6 +163,7 @@ func (d ChangeSetWithVersionedRs) noopAndDeleteChanges(
 	alreadyAdded map[string]ctlres.Resource) ([]Change, error) {
 
 	changes := []Change{}
+	deletePairs := []changePair{}
 
 	// Find existing resources that were not already diffed (not in new set of resources)
 	for existingResKey, existingRs := range existingRsGrouped {
@@ -181,11 +182,7 @@ func (d ChangeSetWithVersionedRs) noopAndDeleteChanges(
 
 		// Create changes to delete all or extra resources
 		for _, existingRes := range existingRs[0 : len(existingRs)-numToKeep] {
-			change, err := d.newChange(existingRes, nil)
-			if err != nil {
-				return nil, err
-			}
-			changes = append(changes, change)
+			deletePairs = append(deletePairs, changePair{ExistingRes: existingRes})
 		}
 
 		// Create changes that "noop" resources
@@ -194,7 +191,12 @@ func (d ChangeSetWithVersionedRs) noopAndDeleteChanges(
 		}
 	}
 
-	return changes, nil
+	deleteChanges, err := calculateChanges(deletePairs, d.changeFactoryFunc(), d.opts.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changes, deleteChanges...), nil
 }
 
 func (d ChangeSetWithVersionedRs) newKeepChange(existingRes ctlres.Resource) Change {
@@ -226,11 +228,14 @@ func (ChangeSetWithVersionedRs) numOfResourcesToKeep(res ctlres.Resource) (int,
 }
 
 func (d ChangeSetWithVersionedRs) newChange(existingRes, newRes ctlres.Resource) (Change, error) {
-	changeFactoryFunc := d.changeFactory.NewExactChange
+	return d.changeFactoryFunc()(existingRes, newRes)
+}
+
+func (d ChangeSetWithVersionedRs) changeFactoryFunc() changeFactoryFunc {
 	if d.opts.AgainstLastApplied {
-		changeFactoryFunc = d.changeFactory.NewChangeAgainstLastApplied
+		return d.changeFactory.NewChangeAgainstLastApplied
 	}
-	return changeFactoryFunc(existingRes, newRes)
+	return d.changeFactory.NewExactChange
 }
 
 type versionedResources struct {
@@ -33,24 +33,34 @@ func (d ConfigurableTextDiff) Full() TextDiff {
 }
 
 func (d ConfigurableTextDiff) Masked(rules []ctlconf.DiffMaskRule) (TextDiff, error) {
+	existingRes, newRes, err := d.MaskedResources(rules)
+	if err != nil {
+		return TextDiff{}, err
+	}
+	return d.calculate(existingRes, newRes), nil
+}
+
+// MaskedResources returns copies of compared resources (either may be nil)
+// with values matched by rules replaced
+func (d ConfigurableTextDiff) MaskedResources(rules []ctlconf.DiffMaskRule) (ctlres.Resource, ctlres.Resource, error) {
 	var existingRes, newRes ctlres.Resource
 	var err error
 
 	if d.existingRes != nil {
 		existingRes, err = NewMaskedResource(d.existingRes, rules).Resource()
 		if err != nil {
-			return TextDiff{}, fmt.Errorf("Masking existing resource: %w", err)
+			return nil, nil, fmt.Errorf("Masking existing resource: %w", err)
 		}
 	}
 
 	if d.newRes != nil {
 		newRes, err = NewMaskedResource(d.newRes, rules).Resource()
 		if err != nil {
-			return TextDiff{}, fmt.Errorf("Masking new resource: %w", err)
+			return nil, nil, fmt.Errorf("Masking new resource: %w", err)
 		}
 	}
 
-	return d.calculate(existingRes, newRes), nil
+	return existingRes, newRes, nil
 }
 
 func (d ConfigurableTextDiff) calculate(existingRes, newRes ctlres.Resource) TextDiff {
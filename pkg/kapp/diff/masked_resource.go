@@ -36,14 +36,20 @@ func (r MaskedResource) Resource() (ctlres.Resource, error) {
 }
 
 func (r MaskedResource) update(rule ctlconf.DiffMaskRule, res ctlres.Resource) error {
-	mod := ctlres.ObjectRefSetMod{
-		ResourceMatcher: ctlres.AnyMatcher{
-			ctlconf.ResourceMatchers(rule.ResourceMatchers).AsResourceMatchers(),
-		},
-		Path:            rule.Path,
-		ReplacementFunc: r.maskValues,
+	for _, path := range rule.AllPaths() {
+		mod := ctlres.ObjectRefSetMod{
+			ResourceMatcher: ctlres.AnyMatcher{
+				ctlconf.ResourceMatchers(rule.ResourceMatchers).AsResourceMatchers(),
+			},
+			Path:            path,
+			ReplacementFunc: r.maskValues,
+		}
+		err := mod.Apply(res)
+		if err != nil {
+			return err
+		}
 	}
-	return mod.Apply(res)
+	return nil
 }
 
 var (
@@ -34,3 +34,19 @@ func (l OpsDiff) MinimalString() string {
 
 	return string(bs)
 }
+
+// Paths returns paths of fields that are changed by ops
+func (l OpsDiff) Paths() []string {
+	opsDefs, err := patch.NewOpDefinitionsFromOps(patch.Ops(l))
+	if err != nil {
+		panic("building opdefs") // TODO panic
+	}
+
+	var result []string
+	for _, opDef := range opsDefs {
+		if opDef.Path != nil {
+			result = append(result, *opDef.Path)
+		}
+	}
+	return result
+}
@@ -63,6 +63,36 @@ func (r ResourceWithHistory) LastAppliedResource() ctlres.Resource {
 	return nil
 }
 
+// DriftChange returns change that would bring resource back to its last applied state.
+// Returned change is nil if resource was not modified since it was last applied.
+// Returns false if resource does not have recorded last applied state.
+func (r ResourceWithHistory) DriftChange() (Change, bool, error) {
+	lastAppliedResBytes := r.resource.Annotations()[appliedResAnnKey]
+	if len(lastAppliedResBytes) == 0 {
+		return nil, false, nil
+	}
+
+	if r.LastAppliedResource() != nil {
+		return nil, true, nil
+	}
+
+	lastAppliedRes, err := ctlres.NewResourceFromBytes([]byte(lastAppliedResBytes))
+	if err != nil {
+		return nil, true, fmt.Errorf("Parsing last applied resource: %w", err)
+	}
+
+	change, err := r.changeFactory.NewChangeAgainstLastApplied(r.resource, lastAppliedRes)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if !change.OpsDiff().HasChanges() {
+		return nil, true, nil
+	}
+
+	return change, true, nil
+}
+
 func (r ResourceWithHistory) AllowsRecordingLastApplied() bool {
 	_, found := r.resource.Annotations()[disableOriginalAnnKey]
 	return !found
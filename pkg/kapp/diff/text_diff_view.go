@@ -7,9 +7,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/cppforlife/color"
 	"github.com/k14s/difflib"
 	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
 )
 
 type TextDiffViewOpts struct {
@@ -33,8 +33,13 @@ func NewTextDiffView(diff *ConfigurableTextDiff,
 func (v TextDiffView) String() string {
 	var diffRecords []difflib.DiffRecord
 
-	if v.opts.Mask {
-		textDiff, err := v.diff.Masked(v.maskRules)
+	maskRules := v.maskRules
+	if !v.opts.Mask {
+		maskRules = v.enforcedMaskRules()
+	}
+
+	if len(maskRules) > 0 {
+		textDiff, err := v.diff.Masked(maskRules)
 		if err != nil {
 			return fmt.Sprintf("Error masking diff: %s", err)
 		}
@@ -66,11 +71,11 @@ func (v TextDiffView) String() string {
 	for lineNum, diff := range diffRecords {
 		switch diff.Delta {
 		case difflib.RightOnly:
-			lines = append(lines, color.New(color.FgGreen).Sprintf("%s+ %s",
+			lines = append(lines, theme.Current().DiffAdded.Sprintf("%s+ %s",
 				lineNums(emptyLineStr, " ", lineNumStr(diff.LineRight)), diff.Payload))
 
 		case difflib.LeftOnly:
-			lines = append(lines, color.New(color.FgRed).Sprintf("%s- %s",
+			lines = append(lines, theme.Current().DiffRemoved.Sprintf("%s- %s",
 				lineNums(lineNumStr(diff.LineLeft), " ", emptyLineStr), diff.Payload))
 
 		case difflib.Common:
@@ -102,3 +107,14 @@ func (v TextDiffView) inContext(lineNum int, changedLines map[int]struct{}) bool
 	}
 	return false
 }
+
+// enforcedMaskRules returns rules that cannot be disabled via opts
+func (v TextDiffView) enforcedMaskRules() []ctlconf.DiffMaskRule {
+	var result []ctlconf.DiffMaskRule
+	for _, rule := range v.maskRules {
+		if rule.Enforce {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
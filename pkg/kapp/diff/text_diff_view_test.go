@@ -0,0 +1,52 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestTextDiffViewMasking(t *testing.T) {
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Secret
+metadata:
+  name: my-secret
+data:
+  password: c2VjcmV0
+stringData:
+  token: my-token
+`))
+
+	rules := []ctlconf.DiffMaskRule{{
+		ResourceMatchers: []ctlconf.ResourceMatcher{{AllMatcher: &ctlconf.AllMatcher{}}},
+		Paths: []ctlres.Path{
+			ctlres.NewPathFromStrings([]string{"data"}),
+			ctlres.NewPathFromStrings([]string{"stringData"}),
+		},
+	}}
+
+	diffStr := func(rules []ctlconf.DiffMaskRule, mask bool) string {
+		textDiff := ctldiff.NewConfigurableTextDiff(nil, newRes, false, ctldiff.ChangeOpts{})
+		return ctldiff.NewTextDiffView(textDiff, rules, ctldiff.TextDiffViewOpts{Context: -1, Mask: mask}).String()
+	}
+
+	out := diffStr(rules, true)
+	require.NotContains(t, out, "c2VjcmV0")
+	require.NotContains(t, out, "my-token")
+
+	out = diffStr(rules, false)
+	require.Contains(t, out, "c2VjcmV0")
+	require.Contains(t, out, "my-token")
+
+	rules[0].Enforce = true
+
+	out = diffStr(rules, false)
+	require.NotContains(t, out, "c2VjcmV0", "Expected enforced rule to mask even when masking is disabled")
+	require.NotContains(t, out, "my-token")
+}
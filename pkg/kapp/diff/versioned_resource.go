@@ -81,6 +81,8 @@ func (d VersionedResource) updateAffected(rule ctlconf.TemplateRule, rs []ctlres
 			ResourceMatcher: ctlres.AnyMatcher{matchers},
 			Path:            affectedObjRef.Path,
 			ReplacementFunc: d.buildObjRefReplacementFunc(affectedObjRef),
+
+			StringReplacementFunc: d.buildNameReplacementFunc(),
 		}
 
 		for _, res := range rs {
@@ -171,6 +173,19 @@ func (d VersionedResource) buildObjRefReplacementFunc(
 	}
 }
 
+// buildNameReplacementFunc handles references that only consist of a name
+// (commonly found in custom resources) which carry no namespace or kind to check
+func (d VersionedResource) buildNameReplacementFunc() func(string) string {
+	baseName, _ := d.BaseNameAndVersion()
+
+	return func(name string) string {
+		if name == baseName {
+			return d.res.Name()
+		}
+		return name
+	}
+}
+
 func (d VersionedResource) matchingRules() ([]ctlconf.TemplateRule, error) {
 	var result []ctlconf.TemplateRule
 
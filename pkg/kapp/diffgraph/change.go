@@ -45,6 +45,18 @@ type Change struct {
 
 type Changes []*Change
 
+// NewChange returns change that is not yet part of a graph
+// (groups and rules could be inspected without building a graph)
+func NewChange(change ActualChange, changeGroupBindings []ctlconf.ChangeGroupBinding,
+	changeRuleBindings []ctlconf.ChangeRuleBinding) *Change {
+
+	return &Change{
+		Change:              change,
+		changeGroupBindings: changeGroupBindings,
+		changeRuleBindings:  changeRuleBindings,
+	}
+}
+
 func (c *Change) Description() string {
 	return fmt.Sprintf("(%s) %s", c.Change.Op(), c.Change.Resource().Description())
 }
@@ -214,17 +226,23 @@ func (c *Change) ApplicableRules() ([]ChangeRule, error) {
 	return applicableRules, nil
 }
 
-func (cs Changes) MatchesRule(rule ChangeRule, _ *Change) ([]*Change, error) {
+func (cs Changes) MatchesRule(rule ChangeRule, ruleChange *Change) ([]*Change, error) {
 	var result []*Change
 
 	for _, change := range cs {
+		// Patterns commonly match group of the change itself (e.g. crds-* for CRDs);
+		// ordering is only meant relative to other changes
+		if rule.TargetGroup.IsPattern() && change == ruleChange {
+			continue
+		}
+
 		groups, err := change.Groups()
 		if err != nil {
 			return nil, err
 		}
 
 		for _, group := range groups {
-			if !group.IsEqual(rule.TargetGroup) {
+			if !rule.TargetGroup.Matches(group) {
 				continue
 			}
 
@@ -243,6 +261,9 @@ func (cs Changes) MatchesRule(rule ChangeRule, _ *Change) ([]*Change, error) {
 			default:
 				panic(fmt.Sprintf("Unknown change operation: %s", op))
 			}
+
+			// Pattern may match multiple groups of the same change
+			break
 		}
 	}
 
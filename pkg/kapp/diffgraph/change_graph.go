@@ -28,11 +28,7 @@ func NewChangeGraph(changes []ActualChange,
 	graphChanges := []*Change{}
 
 	for _, change := range changes {
-		graphChanges = append(graphChanges, &Change{
-			Change:              change,
-			changeGroupBindings: changeGroupBindings,
-			changeRuleBindings:  changeRuleBindings,
-		})
+		graphChanges = append(graphChanges, NewChange(change, changeGroupBindings, changeRuleBindings))
 	}
 
 	graph := &ChangeGraph{graphChanges, logger}
@@ -238,6 +238,59 @@ metadata:
 	require.Equal(t, expectedOutput, output)
 }
 
+func TestChangeGraphWithGroupPatterns(t *testing.T) {
+	configYAML := `
+kind: CustomResourceDefinition
+metadata:
+  name: certs
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/crds-certs"
+---
+kind: CustomResourceDefinition
+metadata:
+  name: issuers
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/crds-issuers"
+    kapp.k14s.io/change-rule: "upsert after upserting apps.big.co/crds-*"
+---
+kind: Job
+metadata:
+  name: migrations
+---
+kind: Job
+metadata:
+  name: unrelated
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/unrelated"
+`
+
+	graph, err := buildChangeGraphWithOpts(buildGraphOpts{
+		resourcesBs: configYAML,
+		op:          ctldgraph.ActualChangeOpUpsert,
+		changeRuleBindings: []ctlconf.ChangeRuleBinding{{
+			Rules: []string{"upsert after upserting apps.big.co/crds-*"},
+			ResourceMatchers: []ctlconf.ResourceMatcher{{
+				KindNamespaceNameMatcher: &ctlconf.KindNamespaceNameMatcher{Kind: "Job", Name: "migrations"},
+			}},
+		}},
+	}, t)
+	require.NoErrorf(t, err, "Expected graph to build")
+
+	output := strings.TrimSpace(graph.PrintStr())
+	expectedOutput := strings.TrimSpace(`
+(upsert) customresourcedefinition/certs () cluster
+(upsert) customresourcedefinition/issuers () cluster
+  (upsert) customresourcedefinition/certs () cluster
+(upsert) job/migrations () cluster
+  (upsert) customresourcedefinition/certs () cluster
+  (upsert) customresourcedefinition/issuers () cluster
+    (upsert) customresourcedefinition/certs () cluster
+(upsert) job/unrelated () cluster
+`)
+
+	require.Equal(t, expectedOutput, output)
+}
+
 func TestChangeGraphWithDeletes(t *testing.T) {
 	configYAML := `
 kind: ConfigMap
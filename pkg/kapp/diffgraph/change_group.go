@@ -5,11 +5,16 @@ package diffgraph
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
 	k8sval "k8s.io/apimachinery/pkg/util/validation"
 )
 
+const changeGroupPatternChars = "*?"
+
+var changeGroupPatternReplacer = strings.NewReplacer("*", "x", "?", "x")
+
 type ChangeGroup struct {
 	Name string
 }
@@ -33,15 +38,49 @@ func NewChangeGroupFromAnnString(ann string) (ChangeGroup, error) {
 	return key, nil
 }
 
+// NewChangeGroupPatternFromAnnString allows group name to include
+// glob wildcards (e.g. apps.big.co/crds-*) so that it could match multiple groups
+func NewChangeGroupPatternFromAnnString(ann string) (ChangeGroup, error) {
+	key := ChangeGroup{ann}
+
+	err := key.validate(changeGroupPatternReplacer.Replace(ann))
+	if err != nil {
+		return ChangeGroup{}, err
+	}
+
+	return key, nil
+}
+
 func (r ChangeGroup) IsEqual(other ChangeGroup) bool {
 	return r.Name == other.Name
 }
 
+// IsPattern returns true if group name includes glob wildcards
+func (r ChangeGroup) IsPattern() bool {
+	return strings.ContainsAny(r.Name, changeGroupPatternChars)
+}
+
+// Matches returns true if other group is the same group or
+// matches this group's pattern ('*' does not match across '/')
+func (r ChangeGroup) Matches(other ChangeGroup) bool {
+	if !r.IsPattern() {
+		return r.IsEqual(other)
+	}
+	matched, err := path.Match(r.Name, other.Name)
+	return err == nil && matched
+}
+
 func (r ChangeGroup) Validate() error {
+	return r.validate(r.Name)
+}
+
+// validate checks name with wildcards replaced (for patterns)
+// so that the rest of the pattern is still validated
+func (r ChangeGroup) validate(name string) error {
 	if len(r.Name) == 0 {
 		return fmt.Errorf("Expected non-empty group name")
 	}
-	errStrs := r.isQualifiedNameWithoutLen(r.Name)
+	errStrs := r.isQualifiedNameWithoutLen(name)
 	if len(errStrs) > 0 {
 		return fmt.Errorf("Expected change group name %q to be a qualified name: %s", r.Name, strings.Join(errStrs, "; "))
 	}
@@ -43,3 +43,26 @@ func TestNewChangeGroupFromAnnString(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestNewChangeGroupPatternFromAnnString(t *testing.T) {
+	cg, err := ctldgraph.NewChangeGroupPatternFromAnnString("apps.big.co/crds-*")
+	require.NoError(t, err)
+	require.True(t, cg.IsPattern())
+	require.True(t, cg.Matches(ctldgraph.MustNewChangeGroupFromAnnString("apps.big.co/crds-certs")))
+	require.False(t, cg.Matches(ctldgraph.MustNewChangeGroupFromAnnString("apps.big.co/deployments")))
+	require.False(t, cg.Matches(ctldgraph.MustNewChangeGroupFromAnnString("other.big.co/crds-certs")))
+
+	cg, err = ctldgraph.NewChangeGroupPatternFromAnnString("apps.big.co/crds")
+	require.NoError(t, err)
+	require.False(t, cg.IsPattern())
+	require.True(t, cg.Matches(ctldgraph.MustNewChangeGroupFromAnnString("apps.big.co/crds")))
+
+	for _, name := range []string{"invalid/_*", "[a-z]", "/*"} {
+		_, err := ctldgraph.NewChangeGroupPatternFromAnnString(name)
+		require.Error(t, err)
+	}
+
+	// Wildcards are only allowed when referencing groups
+	_, err = ctldgraph.NewChangeGroupFromAnnString("apps.big.co/crds-*")
+	require.Error(t, err)
+}
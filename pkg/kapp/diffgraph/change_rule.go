@@ -24,6 +24,7 @@ const (
 )
 
 // Example: upsert before deleting apps.big.co/etcd
+// (target group may be a glob pattern, e.g. upsert after upserting apps.big.co/crds-*)
 type ChangeRule struct {
 	Action           ChangeRuleAction
 	Order            ChangeRuleOrder
@@ -49,7 +50,7 @@ func NewChangeRuleFromAnnString(ann string) (ChangeRule, error) {
 
 	var err error
 
-	rule.TargetGroup, err = NewChangeGroupFromAnnString(pieces[3])
+	rule.TargetGroup, err = NewChangeGroupPatternFromAnnString(pieces[3])
 	if err != nil {
 		return ChangeRule{}, err
 	}
@@ -62,6 +63,11 @@ func NewChangeRuleFromAnnString(ann string) (ChangeRule, error) {
 	return rule, nil
 }
 
+// String returns rule in annotation format (e.g. 'upsert after upserting group1')
+func (r ChangeRule) String() string {
+	return fmt.Sprintf("%s %s %s %s", r.Action, r.Order, r.TargetAction, r.TargetGroup.Name)
+}
+
 func (r ChangeRule) Validate() error {
 	if r.Action != ChangeRuleActionUpsert && r.Action != ChangeRuleActionDelete {
 		return fmt.Errorf("Unknown change rule Action")
@@ -0,0 +1,196 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package diffgraph orders changes based on their change groups and rules.
+//
+// Types and functions for building graphs (NewChangeGraph, NewChangeGraphFromResources),
+// traversing them (ChangeGraph.All, ChangeGraph.Linearized, Change.WaitingFor)
+// and serializing them (GraphDocument) are kept stable for external tooling
+// (e.g. preflight checks). GraphDocument fields are only added within same apiVersion.
+package diffgraph
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	GraphDocumentAPIVersion = "kapp.k14s.io/v1alpha1"
+	GraphDocumentKind       = "ChangeGraph"
+)
+
+// GraphDocument is JSON representation of a graph.
+// Changes reference each other by their index within Changes.
+type GraphDocument struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	Changes []ChangeDocument `json:"changes"`
+
+	// Order lists sections of changes that could be applied
+	// in parallel once all changes of previous sections are applied
+	Order [][]int `json:"order"`
+	// Blocked lists changes that could not be ordered
+	Blocked []int `json:"blocked,omitempty"`
+}
+
+type ChangeDocument struct {
+	Op       ActualChangeOp         `json:"op"`
+	Resource map[string]interface{} `json:"resource"`
+
+	Groups []string             `json:"groups,omitempty"`
+	Rules  []ChangeRuleDocument `json:"rules,omitempty"`
+
+	WaitingFor []int `json:"waitingFor,omitempty"`
+}
+
+type ChangeRuleDocument struct {
+	// Rule is in annotation format (e.g. 'upsert after upserting group1')
+	Rule             string `json:"rule"`
+	IgnoreIfCyclical bool   `json:"ignoreIfCyclical,omitempty"`
+}
+
+// Document returns representation of the graph that could be serialized
+func (g *ChangeGraph) Document() (GraphDocument, error) {
+	doc := GraphDocument{
+		APIVersion: GraphDocumentAPIVersion,
+		Kind:       GraphDocumentKind,
+		Changes:    []ChangeDocument{},
+		Order:      [][]int{},
+	}
+
+	idxs := map[*Change]int{}
+	for i, change := range g.changes {
+		idxs[change] = i
+	}
+
+	for _, change := range g.changes {
+		changeDoc := ChangeDocument{
+			Op:       change.Change.Op(),
+			Resource: change.Change.Resource().DeepCopyRaw(),
+		}
+
+		groups, err := change.Groups()
+		if err != nil {
+			return GraphDocument{}, err
+		}
+		for _, group := range groups {
+			changeDoc.Groups = append(changeDoc.Groups, group.Name)
+		}
+
+		rules, err := change.AllRules()
+		if err != nil {
+			return GraphDocument{}, err
+		}
+		for _, rule := range rules {
+			changeDoc.Rules = append(changeDoc.Rules, ChangeRuleDocument{rule.String(), rule.IgnoreIfCyclical})
+		}
+
+		for _, waitingForChange := range change.WaitingFor {
+			idx, found := idxs[waitingForChange]
+			if !found {
+				return GraphDocument{}, fmt.Errorf("Expected change %s to be part of graph", waitingForChange.Description())
+			}
+			changeDoc.WaitingFor = append(changeDoc.WaitingFor, idx)
+		}
+
+		doc.Changes = append(doc.Changes, changeDoc)
+	}
+
+	sections, blocked := g.Linearized()
+
+	for _, section := range sections {
+		sectionIdxs := []int{}
+		for _, change := range section {
+			sectionIdxs = append(sectionIdxs, idxs[change])
+		}
+		doc.Order = append(doc.Order, sectionIdxs)
+	}
+	for _, change := range blocked {
+		doc.Blocked = append(doc.Blocked, idxs[change])
+	}
+
+	return doc, nil
+}
+
+func (g *ChangeGraph) MarshalJSON() ([]byte, error) {
+	doc, err := g.Document()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// NewChangeGraphFromDocument recreates graph as it was serialized
+// (edges are not recalculated, hence change group and rule bindings are not needed)
+func NewChangeGraphFromDocument(doc GraphDocument, logger logger.Logger) (*ChangeGraph, error) {
+	if doc.APIVersion != GraphDocumentAPIVersion || doc.Kind != GraphDocumentKind {
+		return nil, fmt.Errorf("Expected graph document to have apiVersion '%s' and kind '%s'",
+			GraphDocumentAPIVersion, GraphDocumentKind)
+	}
+
+	var changes []*Change
+
+	for i, changeDoc := range doc.Changes {
+		switch changeDoc.Op {
+		case ActualChangeOpUpsert, ActualChangeOpDelete, ActualChangeOpNoop:
+		default:
+			return nil, fmt.Errorf("Change %d: Unknown change operation: %s", i, changeDoc.Op)
+		}
+
+		res := ctlres.NewResourceUnstructured(unstructured.Unstructured{Object: changeDoc.Resource}, ctlres.ResourceType{})
+		change := NewChange(NewResourceChange(res, changeDoc.Op), nil, nil)
+
+		groups := []ChangeGroup{}
+		for _, name := range changeDoc.Groups {
+			group, err := NewChangeGroupFromAnnString(name)
+			if err != nil {
+				return nil, fmt.Errorf("Change %d: %w", i, err)
+			}
+			groups = append(groups, group)
+		}
+		change.groups = &groups
+
+		rules := []ChangeRule{}
+		for _, ruleDoc := range changeDoc.Rules {
+			rule, err := NewChangeRuleFromAnnString(ruleDoc.Rule)
+			if err != nil {
+				return nil, fmt.Errorf("Change %d: %w", i, err)
+			}
+			rule.IgnoreIfCyclical = ruleDoc.IgnoreIfCyclical
+			rules = append(rules, rule)
+		}
+		change.rules = &rules
+
+		changes = append(changes, change)
+	}
+
+	for i, changeDoc := range doc.Changes {
+		for _, idx := range changeDoc.WaitingFor {
+			if idx < 0 || idx >= len(changes) {
+				return nil, fmt.Errorf("Change %d: Expected waiting for change index %d to be within graph", i, idx)
+			}
+			changes[i].WaitingFor = append(changes[i].WaitingFor, changes[idx])
+		}
+	}
+
+	graph := &ChangeGraph{changes, logger.NewPrefixed("ChangeGraph")}
+
+	return graph, graph.checkCycles()
+}
+
+// NewChangeGraphFromJSON recreates graph serialized via MarshalJSON
+func NewChangeGraphFromJSON(bs []byte, logger logger.Logger) (*ChangeGraph, error) {
+	var doc GraphDocument
+
+	err := json.Unmarshal(bs, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling change graph: %w", err)
+	}
+
+	return NewChangeGraphFromDocument(doc, logger)
+}
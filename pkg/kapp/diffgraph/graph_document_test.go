@@ -0,0 +1,70 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestChangeGraphJSONRoundTrip(t *testing.T) {
+	upsertYAML := `
+kind: Job
+metadata:
+  name: migrations
+  annotations:
+    kapp.k14s.io/change-group: "apps.big.co/db-migrations"
+---
+kind: Deployment
+metadata:
+  name: app
+  annotations:
+    kapp.k14s.io/change-rule: "upsert after upserting apps.big.co/db-migrations"
+`
+	deleteYAML := `
+kind: ConfigMap
+metadata:
+  name: old
+`
+
+	upsertedRs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(upsertYAML))).Resources()
+	require.NoError(t, err)
+	deletedRs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(deleteYAML))).Resources()
+	require.NoError(t, err)
+
+	graph, err := ctldgraph.NewChangeGraphFromResources(upsertedRs, deletedRs, nil, nil, logger.NewTODOLogger())
+	require.NoError(t, err)
+
+	bs, err := json.Marshal(graph)
+	require.NoError(t, err)
+
+	var doc ctldgraph.GraphDocument
+	require.NoError(t, json.Unmarshal(bs, &doc))
+
+	require.Equal(t, "ChangeGraph", doc.Kind)
+	require.Len(t, doc.Changes, 3)
+	require.Equal(t, []string{"apps.big.co/db-migrations"}, doc.Changes[0].Groups)
+	require.Equal(t, []ctldgraph.ChangeRuleDocument{{Rule: "upsert after upserting apps.big.co/db-migrations"}}, doc.Changes[1].Rules)
+	require.Equal(t, []int{0}, doc.Changes[1].WaitingFor)
+	require.Equal(t, ctldgraph.ActualChangeOpDelete, doc.Changes[2].Op)
+	require.Equal(t, [][]int{{0, 2}, {1}}, doc.Order)
+
+	restoredGraph, err := ctldgraph.NewChangeGraphFromJSON(bs, logger.NewTODOLogger())
+	require.NoError(t, err)
+	require.Equal(t, graph.PrintStr(), restoredGraph.PrintStr())
+	require.Equal(t, graph.PrintLinearizedStr(), restoredGraph.PrintLinearizedStr())
+
+	groups, err := restoredGraph.All()[0].Groups()
+	require.NoError(t, err)
+	require.Equal(t, []ctldgraph.ChangeGroup{{Name: "apps.big.co/db-migrations"}}, groups)
+
+	_, err = ctldgraph.NewChangeGraphFromJSON([]byte(`{"apiVersion":"kapp.k14s.io/v1alpha1","kind":"ChangeGraph",`+
+		`"changes":[{"op":"upsert","resource":{},"waitingFor":[1]}]}`), logger.NewTODOLogger())
+	require.EqualError(t, err, "Change 0: Expected waiting for change index 1 to be within graph")
+}
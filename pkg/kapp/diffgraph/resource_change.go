@@ -0,0 +1,43 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package diffgraph
+
+import (
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// ResourceChange is a plain ActualChange (e.g. for building graphs outside of deploy)
+type ResourceChange struct {
+	Res      ctlres.Resource
+	ChangeOp ActualChangeOp
+}
+
+var _ ActualChange = ResourceChange{}
+
+func NewResourceChange(res ctlres.Resource, op ActualChangeOp) ResourceChange {
+	return ResourceChange{Res: res, ChangeOp: op}
+}
+
+func (c ResourceChange) Resource() ctlres.Resource { return c.Res }
+func (c ResourceChange) Op() ActualChangeOp        { return c.ChangeOp }
+
+// NewChangeGraphFromResources builds graph with upsert changes for upserted resources
+// followed by delete changes for deleted resources (ordered same as given)
+func NewChangeGraphFromResources(upsertedRs, deletedRs []ctlres.Resource,
+	changeGroupBindings []ctlconf.ChangeGroupBinding, changeRuleBindings []ctlconf.ChangeRuleBinding,
+	logger logger.Logger) (*ChangeGraph, error) {
+
+	var changes []ActualChange
+
+	for _, res := range upsertedRs {
+		changes = append(changes, NewResourceChange(res, ActualChangeOpUpsert))
+	}
+	for _, res := range deletedRs {
+		changes = append(changes, NewResourceChange(res, ActualChangeOpDelete))
+	}
+
+	return NewChangeGraph(changes, changeGroupBindings, changeRuleBindings, logger)
+}
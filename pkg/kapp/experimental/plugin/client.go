@@ -0,0 +1,191 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Config struct {
+	Command string
+	Args    []string
+	Env     map[string]string
+	// Timeout limits each call (including handshake) and
+	// how long plugin has to exit once client is closed
+	Timeout time.Duration
+}
+
+// Client is connected to started plugin process;
+// it is safe to make concurrent calls
+type Client struct {
+	config Config
+	cmd    *exec.Cmd
+	rpc    *rpc.Client
+	info   InfoResponse
+
+	// stderr is written by exec's copying goroutine
+	// hence it is only read after process was waited for
+	stderr *bytes.Buffer
+
+	cancel context.CancelFunc
+
+	closeOnce sync.Once
+	closeErr  error
+	closed    chan struct{}
+}
+
+// Start starts plugin process and negotiates protocol version;
+// returned client has to be closed
+func Start(config Config) (*Client, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cmd := exec.CommandContext(ctx, config.Command, config.Args...)
+	cmd.Env = append(os.Environ(), MagicCookieKey+"="+MagicCookieValue)
+
+	for k, v := range config.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("Opening plugin stdin: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("Opening plugin stdout: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err = cmd.Start()
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("Starting plugin '%s': %w", config.Command, err)
+	}
+
+	client := &Client{
+		config: config,
+		cmd:    cmd,
+		rpc:    jsonrpc.NewClient(stdioConn{stdout, stdin}),
+		stderr: &stderr,
+		cancel: cancel,
+		closed: make(chan struct{}),
+	}
+
+	err = client.call(rpcServiceName+"."+methodInfo, InfoRequest{ProtocolVersion}, &client.info)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	if client.info.ProtocolVersion != ProtocolVersion {
+		client.Close()
+		return nil, fmt.Errorf("Expected plugin '%s' to implement protocol version %d, but was %d",
+			config.Command, ProtocolVersion, client.info.ProtocolVersion)
+	}
+
+	return client, nil
+}
+
+func (c *Client) Supports(capability Capability) bool {
+	for _, supported := range c.info.Capabilities {
+		if supported == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// Call calls method of a capability (args and reply are serialized as JSON).
+// Client is closed when plugin does not respond in time or connection fails
+// (errors returned by plugin's methods keep it running).
+func (c *Client) Call(capability Capability, args, reply interface{}) error {
+	if !c.Supports(capability) {
+		return fmt.Errorf("Expected plugin '%s' to support capability '%s'", c.config.Command, capability)
+	}
+	return c.call(MethodForCapability(capability), args, reply)
+}
+
+// Closed indicates that client cannot be used anymore
+func (c *Client) Closed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops plugin process (plugin is expected to exit once its stdin is closed;
+// it is killed if it does not exit within timeout)
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.stop(false)
+		close(c.closed)
+	})
+	return c.closeErr
+}
+
+func (c *Client) call(method string, args, reply interface{}) error {
+	call := c.rpc.Go(method, args, reply, make(chan *rpc.Call, 1))
+
+	timer := time.NewTimer(c.config.Timeout)
+	defer timer.Stop()
+
+	select {
+	case <-call.Done:
+		if call.Error == nil {
+			return nil
+		}
+		if _, ok := call.Error.(rpc.ServerError); ok {
+			return fmt.Errorf("Calling plugin '%s' (method: %s): %w", c.config.Command, method, call.Error)
+		}
+		// Connection failed (e.g. plugin exited), hence its stderr explains why
+		c.Close()
+		return fmt.Errorf("Calling plugin '%s' (method: %s): %w (stderr: %s)",
+			c.config.Command, method, call.Error, strings.TrimSpace(c.stderr.String()))
+
+	case <-timer.C:
+		c.closeOnce.Do(func() {
+			c.closeErr = c.stop(true)
+			close(c.closed)
+		})
+		return fmt.Errorf("Calling plugin '%s' (method: %s): timed out after %s",
+			c.config.Command, method, c.config.Timeout)
+	}
+}
+
+// stop waits for plugin process to exit; once it returns
+// stderr is not written to anymore
+func (c *Client) stop(kill bool) error {
+	defer c.cancel()
+
+	if kill {
+		c.cancel()
+	}
+
+	_ = c.rpc.Close()
+
+	killTimer := time.AfterFunc(c.config.Timeout, c.cancel)
+	defer killTimer.Stop()
+
+	err := c.cmd.Wait()
+	if err != nil && !kill && c.cmd.ProcessState != nil && c.cmd.ProcessState.Exited() {
+		return fmt.Errorf("Waiting for plugin '%s' to exit: %w", c.config.Command, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	ctlplugin "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/experimental/plugin"
+)
+
+const testPluginEnvKey = "KAPP_TEST_PLUGIN"
+
+// TestMain runs test binary as a plugin when started by tests below
+func TestMain(m *testing.M) {
+	switch os.Getenv(testPluginEnvKey) {
+	case "":
+		os.Exit(m.Run())
+	case "wait":
+		serveTestPlugin(ctlplugin.Server{
+			EvaluateWait: func(req ctlplugin.WaitRequest) (ctlplugin.WaitResponse, error) {
+				name := req.Resource["metadata"].(map[string]interface{})["name"].(string)
+				switch name {
+				case "failing":
+					return ctlplugin.WaitResponse{}, fmt.Errorf("cannot evaluate %s", name)
+				case "crashing":
+					fmt.Fprintf(os.Stderr, "crashed evaluating %s\n", name)
+					os.Exit(1)
+				case "slow":
+					time.Sleep(5 * time.Second)
+				}
+				return ctlplugin.WaitResponse{Done: true, Successful: true, Message: "ready " + name}, nil
+			},
+		})
+	case "slow":
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func serveTestPlugin(server ctlplugin.Server) {
+	err := ctlplugin.Serve(server)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func TestPluginCalls(t *testing.T) {
+	client, err := ctlplugin.Start(testPluginConfig("wait", time.Minute))
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.True(t, client.Supports(ctlplugin.CapabilityWaitEvaluator))
+	require.False(t, client.Supports(ctlplugin.CapabilityPreflightCheck))
+
+	var resp ctlplugin.WaitResponse
+
+	err = client.Call(ctlplugin.CapabilityWaitEvaluator, testWaitRequest("cm1"), &resp)
+	require.NoError(t, err)
+	require.Equal(t, ctlplugin.WaitResponse{Done: true, Successful: true, Message: "ready cm1"}, resp)
+
+	err = client.Call(ctlplugin.CapabilityWaitEvaluator, testWaitRequest("failing"), &resp)
+	require.ErrorContains(t, err, "(method: Plugin.EvaluateWait): cannot evaluate failing")
+
+	var preflightResp json.RawMessage
+
+	err = client.Call(ctlplugin.CapabilityPreflightCheck, map[string]interface{}{}, &preflightResp)
+	require.ErrorContains(t, err, "to support capability 'preflightCheck'")
+
+	require.False(t, client.Closed())
+	require.NoError(t, client.Close())
+	require.True(t, client.Closed())
+}
+
+func TestPluginConcurrentCalls(t *testing.T) {
+	client, err := ctlplugin.Start(testPluginConfig("wait", time.Minute))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var resp ctlplugin.WaitResponse
+
+			errs[i] = client.Call(ctlplugin.CapabilityWaitEvaluator, testWaitRequest(fmt.Sprintf("cm%d", i)), &resp)
+			if errs[i] == nil && resp.Message != fmt.Sprintf("ready cm%d", i) {
+				errs[i] = fmt.Errorf("Unexpected response: %#v", resp)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestPluginExitIncludesStderr(t *testing.T) {
+	client, err := ctlplugin.Start(testPluginConfig("wait", time.Minute))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var resp ctlplugin.WaitResponse
+
+	err = client.Call(ctlplugin.CapabilityWaitEvaluator, testWaitRequest("crashing"), &resp)
+	require.ErrorContains(t, err, "(method: Plugin.EvaluateWait): ")
+	require.ErrorContains(t, err, "(stderr: crashed evaluating crashing)")
+	require.True(t, client.Closed())
+
+	err = client.Call(ctlplugin.CapabilityWaitEvaluator, testWaitRequest("cm1"), &resp)
+	require.ErrorContains(t, err, "(method: Plugin.EvaluateWait): connection is shut down")
+}
+
+func TestPluginTimeout(t *testing.T) {
+	_, err := ctlplugin.Start(testPluginConfig("slow", 100*time.Millisecond))
+	require.ErrorContains(t, err, "(method: Plugin.Info): timed out after 100ms")
+
+	// Timeout applies to each call instead of plugin lifetime
+	client, err := ctlplugin.Start(testPluginConfig("wait", 500*time.Millisecond))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var resp ctlplugin.WaitResponse
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(200 * time.Millisecond)
+		require.NoError(t, client.Call(ctlplugin.CapabilityWaitEvaluator, testWaitRequest("cm1"), &resp))
+	}
+
+	err = client.Call(ctlplugin.CapabilityWaitEvaluator, testWaitRequest("slow"), &resp)
+	require.ErrorContains(t, err, "(method: Plugin.EvaluateWait): timed out after 500ms")
+	require.True(t, client.Closed())
+}
+
+func TestPluginServeRequiresMagicCookie(t *testing.T) {
+	require.NoError(t, os.Unsetenv(ctlplugin.MagicCookieKey))
+	require.EqualError(t, ctlplugin.Serve(ctlplugin.Server{}), "Expected to be started by kapp as a plugin")
+}
+
+func testPluginConfig(name string, timeout time.Duration) ctlplugin.Config {
+	return ctlplugin.Config{
+		Command: os.Args[0],
+		Env:     map[string]string{testPluginEnvKey: name},
+		Timeout: timeout,
+	}
+}
+
+func testWaitRequest(name string) ctlplugin.WaitRequest {
+	return ctlplugin.WaitRequest{Resource: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name},
+	}}
+}
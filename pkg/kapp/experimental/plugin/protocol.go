@@ -0,0 +1,82 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plugin allows to extend kapp with out-of-process plugins
+// (preflight checks and wait rules) without recompiling kapp.
+//
+// EXPERIMENTAL: protocol and Go API may change incompatibly between releases.
+// It is a bespoke JSON-RPC protocol and not hashicorp/go-plugin (gRPC) based,
+// hence go-plugin plugins cannot be used. Diff filter plugins are not supported.
+//
+// Plugin is an executable started by kapp. After handshake (magic cookie
+// environment variable and protocol version negotiation via Plugin.Info call)
+// kapp calls methods of capabilities plugin supports; plugin process may
+// receive multiple (possibly concurrent) calls before it is stopped.
+// Calls use JSON-RPC 1.0 (as implemented by net/rpc/jsonrpc) over plugin's
+// stdin and stdout, hence plugins could be written in any language;
+// plugins written in Go could use Serve. Plugin's stderr is shown
+// to the user when plugin fails.
+package plugin
+
+const (
+	// ProtocolVersion is incremented on incompatible changes to methods or their arguments
+	ProtocolVersion = 1
+
+	// Magic cookie makes sure that plugin is started by kapp
+	// (instead of e.g. accidentally by a user) before it blocks on stdin
+	MagicCookieKey   = "KAPP_PLUGIN_MAGIC_COOKIE"
+	MagicCookieValue = "b5d1f3e2-kapp-plugin-7a4c"
+
+	rpcServiceName = "Plugin"
+)
+
+type Capability string
+
+const (
+	// CapabilityPreflightCheck is provided via Plugin.PreflightCheck method
+	// that receives PreflightCheckRequest document and returns PreflightCheckResponse
+	// (see preflight package)
+	CapabilityPreflightCheck Capability = "preflightCheck"
+	// CapabilityWaitEvaluator is provided via Plugin.EvaluateWait method
+	CapabilityWaitEvaluator Capability = "waitEvaluator"
+
+	methodInfo           = "Info"
+	methodPreflightCheck = "PreflightCheck"
+	methodEvaluateWait   = "EvaluateWait"
+)
+
+// MethodForCapability returns fully qualified RPC method name
+func MethodForCapability(capability Capability) string {
+	switch capability {
+	case CapabilityPreflightCheck:
+		return rpcServiceName + "." + methodPreflightCheck
+	case CapabilityWaitEvaluator:
+		return rpcServiceName + "." + methodEvaluateWait
+	default:
+		panic("Unknown plugin capability: " + string(capability))
+	}
+}
+
+type InfoRequest struct {
+	// ProtocolVersion is the version used by kapp
+	ProtocolVersion int `json:"protocolVersion"`
+}
+
+type InfoResponse struct {
+	// ProtocolVersion is the version implemented by plugin
+	ProtocolVersion int          `json:"protocolVersion"`
+	Capabilities    []Capability `json:"capabilities"`
+}
+
+// WaitRequest is sent to Plugin.EvaluateWait for each check of a resource state
+type WaitRequest struct {
+	Resource map[string]interface{} `json:"resource"`
+}
+
+// WaitResponse has same meaning as result of ytt and exec based wait rules
+type WaitResponse struct {
+	Done           bool   `json:"done"`
+	Successful     bool   `json:"successful"`
+	Message        string `json:"message"`
+	UnblockChanges bool   `json:"unblockChanges"`
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+)
+
+// Server is implemented by plugins written in Go; only capabilities
+// with non-nil funcs are advertised to kapp
+type Server struct {
+	// PreflightCheck receives PreflightCheckRequest document and
+	// is expected to return value serializable as PreflightCheckResponse
+	PreflightCheck func(req json.RawMessage) (interface{}, error)
+	EvaluateWait   func(req WaitRequest) (WaitResponse, error)
+}
+
+// Serve is expected to be called from plugin's main;
+// it returns once kapp is done with the plugin
+func Serve(server Server) error {
+	if os.Getenv(MagicCookieKey) != MagicCookieValue {
+		return fmt.Errorf("Expected to be started by kapp as a plugin")
+	}
+	return ServeConn(server, stdioConn{os.Stdin, os.Stdout})
+}
+
+// ServeConn serves calls over given connection (e.g. for testing plugins)
+func ServeConn(server Server, conn io.ReadWriteCloser) error {
+	rpcServer := rpc.NewServer()
+
+	err := rpcServer.RegisterName(rpcServiceName, &rpcHandler{server})
+	if err != nil {
+		return fmt.Errorf("Registering plugin methods: %w", err)
+	}
+
+	rpcServer.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// rpcHandler exposes server funcs as methods following net/rpc conventions
+type rpcHandler struct {
+	server Server
+}
+
+func (h *rpcHandler) Info(_ InfoRequest, resp *InfoResponse) error {
+	resp.ProtocolVersion = ProtocolVersion
+	resp.Capabilities = []Capability{}
+
+	if h.server.PreflightCheck != nil {
+		resp.Capabilities = append(resp.Capabilities, CapabilityPreflightCheck)
+	}
+	if h.server.EvaluateWait != nil {
+		resp.Capabilities = append(resp.Capabilities, CapabilityWaitEvaluator)
+	}
+	return nil
+}
+
+func (h *rpcHandler) PreflightCheck(req json.RawMessage, resp *json.RawMessage) error {
+	if h.server.PreflightCheck == nil {
+		return fmt.Errorf("Unsupported capability '%s'", CapabilityPreflightCheck)
+	}
+
+	result, err := h.server.PreflightCheck(req)
+	if err != nil {
+		return err
+	}
+
+	bs, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("Serializing preflight check response: %w", err)
+	}
+
+	*resp = bs
+	return nil
+}
+
+func (h *rpcHandler) EvaluateWait(req WaitRequest, resp *WaitResponse) error {
+	if h.server.EvaluateWait == nil {
+		return fmt.Errorf("Unsupported capability '%s'", CapabilityWaitEvaluator)
+	}
+
+	result, err := h.server.EvaluateWait(req)
+	if err != nil {
+		return err
+	}
+
+	*resp = result
+	return nil
+}
+
+// stdioConn combines reader and writer of a process into single connection
+type stdioConn struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+func (c stdioConn) Close() error {
+	writeErr := c.WriteCloser.Close()
+	readErr := c.ReadCloser.Close()
+	if writeErr != nil {
+		return writeErr
+	}
+	return readErr
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinput
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	URLPrefix = "git+"
+
+	// Used in addition to git credential helpers (e.g. for CI)
+	gitUsernameEnvVar = "KAPP_GIT_USERNAME"
+	gitPasswordEnvVar = "KAPP_GIT_PASSWORD"
+)
+
+// Source points to a directory within git repository at particular ref
+// (format: git+https://host/org/repo//path?ref=v1.2.3)
+type Source struct {
+	RepoURL string
+	SubPath string
+	Ref     string
+}
+
+func IsURL(file string) bool { return strings.HasPrefix(file, URLPrefix) }
+
+func ParseURL(file string) (Source, error) {
+	parsedURL, err := url.Parse(strings.TrimPrefix(file, URLPrefix))
+	if err != nil {
+		return Source{}, fmt.Errorf("Parsing git URL '%s': %w", file, err)
+	}
+
+	switch parsedURL.Scheme {
+	case "https", "http", "ssh", "file":
+	default:
+		return Source{}, fmt.Errorf("Expected git URL '%s' to use https, http, ssh or file scheme", file)
+	}
+
+	src := Source{Ref: parsedURL.Query().Get("ref")}
+	parsedURL.RawQuery = ""
+
+	// Double slash separates repository from directory within it
+	if pieces := strings.SplitN(parsedURL.Path, "//", 2); len(pieces) == 2 {
+		parsedURL.Path = pieces[0]
+		src.SubPath = strings.Trim(pieces[1], "/")
+	}
+
+	if strings.Contains(src.SubPath, "..") {
+		return Source{}, fmt.Errorf("Expected git URL '%s' path to not include '..'", file)
+	}
+
+	src.RepoURL = parsedURL.String()
+
+	return src, nil
+}
+
+func (s Source) Description() string {
+	desc := s.RepoURL
+	if len(s.SubPath) > 0 {
+		desc += "//" + s.SubPath
+	}
+	return desc
+}
+
+type ResourcesOpts struct {
+	// AllowedSignersFile enables verification of ssh signature
+	// of the tag specified via ref (see ssh-keygen allowed signers format)
+	AllowedSignersFile string
+}
+
+// Resources shallow-clones repository and returns resources
+// found in subpath along with resolved commit SHA
+func (s Source) Resources() ([]ctlres.Resource, string, error) {
+	return s.ResourcesWithOpts(ResourcesOpts{})
+}
+
+func (s Source) ResourcesWithOpts(opts ResourcesOpts) ([]ctlres.Resource, string, error) {
+	verifyTag := len(opts.AllowedSignersFile) > 0
+
+	if verifyTag && len(s.Ref) == 0 {
+		return nil, "", fmt.Errorf("Expected git source '%s' to specify tag via ref to verify its signature", s.Description())
+	}
+
+	dir, err := os.MkdirTemp("", "kapp-git-")
+	if err != nil {
+		return nil, "", err
+	}
+	defer os.RemoveAll(dir)
+
+	ref := s.Ref
+	fetchRef := ref
+	checkoutRef := "FETCH_HEAD"
+
+	switch {
+	case len(ref) == 0:
+		fetchRef = "HEAD"
+	case verifyTag:
+		// Tag object (that holds signature) is only kept when fetched as a tag
+		fetchRef = "refs/tags/" + ref + ":refs/tags/" + ref
+		checkoutRef = "refs/tags/" + ref
+	}
+
+	cmds := [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", s.RepoURL},
+		{"fetch", "--quiet", "--depth", "1", "origin", fetchRef},
+	}
+
+	if verifyTag {
+		allowedSignersFile, err := filepath.Abs(opts.AllowedSignersFile)
+		if err != nil {
+			return nil, "", err
+		}
+		cmds = append(cmds, []string{"-c", "gpg.format=ssh", "-c", "gpg.ssh.allowedSignersFile=" + allowedSignersFile,
+			"verify-tag", "refs/tags/" + ref})
+	}
+
+	cmds = append(cmds, []string{"checkout", "--quiet", checkoutRef})
+
+	for _, args := range cmds {
+		_, err := s.git(dir, args...)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	sha, err := s.git(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, "", err
+	}
+
+	resources, err := s.resources(dir, sha)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return resources, sha, nil
+}
+
+func (s Source) resources(dir, sha string) ([]ctlres.Resource, error) {
+	fsys := os.DirFS(dir)
+	path := "."
+	if len(s.SubPath) > 0 {
+		path = filepath.ToSlash(s.SubPath)
+	}
+
+	fileRs, err := ctlres.NewFileResources(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("Reading git source '%s': %w", s.Description(), err)
+	}
+
+	var resources []ctlres.Resource
+
+	for _, fileRes := range fileRs {
+		rs, err := fileRes.Resources()
+		if err != nil {
+			return nil, err
+		}
+		// Origin points to repository instead of temporary checkout
+		for _, res := range rs {
+			res.SetOrigin(fmt.Sprintf("git '%s@%s' %s", s.RepoURL, sha, res.Origin()))
+		}
+		resources = append(resources, rs...)
+	}
+
+	return resources, nil
+}
+
+func (s Source) git(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Never prompt for credentials since kapp may be running non-interactively
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	if username := os.Getenv(gitUsernameEnvVar); len(username) > 0 {
+		creds := base64.StdEncoding.EncodeToString([]byte(username + ":" + os.Getenv(gitPasswordEnvVar)))
+		// Passed via env to avoid exposing credentials in process list
+		cmd.Env = append(cmd.Env, "GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: Basic "+creds)
+	}
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("Running 'git %s' for '%s': %w (stderr: %s)",
+			s.subcommand(args), s.Description(), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (Source) subcommand(args []string) string {
+	// Skip config options (e.g. '-c key=value')
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-c" {
+			i++
+			continue
+		}
+		return args[i]
+	}
+	return ""
+}
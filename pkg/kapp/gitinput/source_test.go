@@ -0,0 +1,133 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package gitinput_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/gitinput"
+)
+
+func TestParseURL(t *testing.T) {
+	src, err := gitinput.ParseURL("git+https://github.com/org/repo//config/prod?ref=v1.2.3")
+	require.NoError(t, err)
+	require.Equal(t, gitinput.Source{RepoURL: "https://github.com/org/repo", SubPath: "config/prod", Ref: "v1.2.3"}, src)
+
+	src, err = gitinput.ParseURL("git+https://github.com/org/repo")
+	require.NoError(t, err)
+	require.Equal(t, gitinput.Source{RepoURL: "https://github.com/org/repo"}, src)
+
+	_, err = gitinput.ParseURL("git+ftp://github.com/org/repo")
+	require.EqualError(t, err, "Expected git URL 'git+ftp://github.com/org/repo' to use https, http, ssh or file scheme")
+
+	_, err = gitinput.ParseURL("git+https://github.com/org/repo//../etc")
+	require.EqualError(t, err, "Expected git URL 'git+https://github.com/org/repo//../etc' path to not include '..'")
+}
+
+func TestSourceResources(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("Skipping test as git binary is not available")
+	}
+
+	repoDir := t.TempDir()
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	git("init", "--quiet")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDir, "config"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "config", "cm.yml"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: v1\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "other.yml"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: other\n"), 0600))
+
+	git("add", ".")
+	git("commit", "--quiet", "-m", "v1")
+	git("tag", "v1")
+	sha := git("rev-parse", "HEAD")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "config", "cm.yml"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: v2\n"), 0600))
+
+	git("commit", "--quiet", "-am", "v2")
+
+	src, err := gitinput.ParseURL("git+file://" + repoDir + "//config?ref=v1")
+	require.NoError(t, err)
+
+	rs, resolvedSHA, err := src.Resources()
+	require.NoError(t, err)
+	require.Equal(t, sha, resolvedSHA)
+	require.Len(t, rs, 1)
+	require.Equal(t, "v1", rs[0].Name())
+	require.Equal(t, "git 'file://"+repoDir+"@"+sha+"' file 'config/cm.yml' doc 1", rs[0].Origin())
+}
+
+func TestSourceResourcesWithTagVerification(t *testing.T) {
+	for _, binary := range []string{"git", "ssh-keygen"} {
+		if _, err := exec.LookPath(binary); err != nil {
+			t.Skipf("Skipping test as %s binary is not available", binary)
+		}
+	}
+
+	repoDir := t.TempDir()
+	keysDir := t.TempDir()
+
+	run := func(dir, name string, args ...string) string {
+		cmd := exec.Command(name, args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	signers := map[string]string{}
+	for _, name := range []string{"trusted", "other"} {
+		keyPath := filepath.Join(keysDir, name)
+		run(keysDir, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", name, "-f", keyPath)
+		pubKey, err := os.ReadFile(keyPath + ".pub")
+		require.NoError(t, err)
+		signersPath := filepath.Join(keysDir, name+"-signers")
+		require.NoError(t, os.WriteFile(signersPath, []byte("test@example.com "+string(pubKey)), 0600))
+		signers[name] = signersPath
+	}
+
+	git := func(args ...string) string {
+		return run(repoDir, "git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com",
+			"-c", "gpg.format=ssh", "-c", "user.signingkey=" + filepath.Join(keysDir, "trusted")}, args...)...)
+	}
+
+	git("init", "--quiet")
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "cm.yml"),
+		[]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: v1\n"), 0600))
+	git("add", ".")
+	git("commit", "--quiet", "-m", "v1")
+	git("tag", "-s", "-m", "v1", "v1")
+	git("tag", "unsigned")
+
+	src, err := gitinput.ParseURL("git+file://" + repoDir + "?ref=v1")
+	require.NoError(t, err)
+
+	rs, _, err := src.ResourcesWithOpts(gitinput.ResourcesOpts{AllowedSignersFile: signers["trusted"]})
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	_, _, err = src.ResourcesWithOpts(gitinput.ResourcesOpts{AllowedSignersFile: signers["other"]})
+	require.ErrorContains(t, err, "Running 'git verify-tag'")
+
+	src.Ref = "unsigned"
+
+	_, _, err = src.ResourcesWithOpts(gitinput.ResourcesOpts{AllowedSignersFile: signers["trusted"]})
+	require.ErrorContains(t, err, "Running 'git verify-tag'")
+}
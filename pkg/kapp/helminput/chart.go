@@ -0,0 +1,123 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package helminput
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	chartFileName = "Chart.yaml"
+)
+
+var (
+	// Helm prefixes each rendered document with its template path
+	sourceCommentRegexp = regexp.MustCompile(`(?m)^# Source: (.+)$`)
+)
+
+type ChartOpts struct {
+	// Binary defaults to 'helm' found in PATH
+	Binary string
+
+	ReleaseName string
+	Namespace   string
+
+	ValuesFiles []string
+	// Repo and Version are used for remote charts
+	Repo    string
+	Version string
+}
+
+// Chart renders Helm chart client-side via 'helm template'
+type Chart struct {
+	chart string
+	opts  ChartOpts
+}
+
+func NewChart(chart string, opts ChartOpts) Chart {
+	return Chart{chart, opts}
+}
+
+// IsChartDir returns true if directory includes Chart.yaml;
+// uses OS file system if fsys is nil
+func IsChartDir(fsys fs.FS, dir string) bool {
+	var err error
+	if fsys == nil {
+		_, err = os.Stat(path.Join(dir, chartFileName))
+	} else {
+		_, err = fs.Stat(fsys, path.Join(dir, chartFileName))
+	}
+	return err == nil
+}
+
+func (c Chart) Resources() ([]ctlres.Resource, error) {
+	binary := c.opts.Binary
+	if len(binary) == 0 {
+		binary = "helm"
+	}
+
+	args := []string{"template", c.opts.ReleaseName, c.chart, "--skip-tests"}
+
+	if len(c.opts.Namespace) > 0 {
+		args = append(args, "--namespace", c.opts.Namespace)
+	}
+	for _, file := range c.opts.ValuesFiles {
+		args = append(args, "--values", file)
+	}
+	if len(c.opts.Repo) > 0 {
+		args = append(args, "--repo", c.opts.Repo)
+	}
+	if len(c.opts.Version) > 0 {
+		args = append(args, "--version", c.opts.Version)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("Rendering helm chart '%s': %w (stderr: %s)",
+			c.chart, err, strings.TrimSpace(stderr.String()))
+	}
+
+	docs, err := ctlres.NewYAMLFile(ctlres.NewBytesSource(stdout.Bytes())).Docs()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []ctlres.Resource
+
+	for i, doc := range docs {
+		rs, err := ctlres.NewResourcesFromBytes(doc)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing rendered helm chart '%s': %w", c.chart, err)
+		}
+
+		origin := fmt.Sprintf("helm chart '%s' doc %d", c.chart, i+1)
+		if match := sourceCommentRegexp.FindSubmatch(doc); match != nil {
+			origin = fmt.Sprintf("helm chart '%s' template '%s'", c.chart, strings.TrimSpace(string(match[1])))
+		}
+
+		for _, res := range rs {
+			res.SetOrigin(origin)
+		}
+
+		resources = append(resources, rs...)
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package helminput_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/helminput"
+)
+
+func TestChartResources(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake helm binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	argsPath := filepath.Join(dir, "args")
+
+	// Fake helm records its arguments and prints rendered chart
+	helmPath := filepath.Join(dir, "helm")
+	err := os.WriteFile(helmPath, []byte(`#!/bin/sh
+echo "$@" > `+argsPath+`
+cat <<EOF
+---
+# Source: web/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+---
+# Source: web/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+EOF
+`), 0700)
+	require.NoError(t, err)
+
+	chart := helminput.NewChart("./web", helminput.ChartOpts{
+		Binary:      helmPath,
+		ReleaseName: "app1",
+		Namespace:   "ns1",
+		ValuesFiles: []string{"values.yml"},
+	})
+
+	rs, err := chart.Resources()
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	require.Equal(t, "web-config", rs[0].Name())
+	require.Equal(t, "helm chart './web' template 'web/templates/configmap.yaml'", rs[0].Origin())
+	require.Equal(t, "helm chart './web' template 'web/templates/service.yaml'", rs[1].Origin())
+
+	args, err := os.ReadFile(argsPath)
+	require.NoError(t, err)
+	require.Equal(t, "template app1 ./web --skip-tests --namespace ns1 --values values.yml\n", string(args))
+}
+
+func TestIsChartDir(t *testing.T) {
+	dir := t.TempDir()
+	require.False(t, helminput.IsChartDir(nil, dir))
+
+	err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: web"), 0600)
+	require.NoError(t, err)
+	require.True(t, helminput.IsChartDir(nil, dir))
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package images
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	LockAPIVersion = "imgpkg.carvel.dev/v1alpha1"
+	LockKind       = "ImagesLock"
+
+	// LockIDAnnKey holds original image reference (same as used by kbld)
+	LockIDAnnKey = "kbld.carvel.dev/id"
+)
+
+// Lock is compatible with images lock produced by kbld (--imgpkg-lock-output)
+type Lock struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Images     []LockedImage `json:"images"`
+}
+
+type LockedImage struct {
+	Image       string            `json:"image"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func NewLockFromFile(path string) (Lock, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return Lock{}, fmt.Errorf("Reading images lock '%s': %w", path, err)
+	}
+
+	var lock Lock
+
+	err = yaml.Unmarshal(bs, &lock)
+	if err != nil {
+		return Lock{}, fmt.Errorf("Unmarshaling images lock '%s': %w", path, err)
+	}
+
+	if lock.APIVersion != LockAPIVersion || lock.Kind != LockKind {
+		return Lock{}, fmt.Errorf("Expected images lock '%s' to have apiVersion '%s' and kind '%s'",
+			path, LockAPIVersion, LockKind)
+	}
+
+	return lock, nil
+}
+
+// NewLockFromResources records all image references found in resources;
+// resolutions map original references to resolved ones.
+func NewLockFromResources(rs []ctlres.Resource, resolutions map[string]string) Lock {
+	origRefs := map[string]string{}
+	for orig, resolved := range resolutions {
+		origRefs[resolved] = orig
+	}
+
+	seen := map[string]struct{}{}
+	lock := Lock{APIVersion: LockAPIVersion, Kind: LockKind}
+
+	for _, res := range rs {
+		for _, ref := range Refs(res) {
+			if _, found := seen[ref]; found {
+				continue
+			}
+			seen[ref] = struct{}{}
+
+			img := LockedImage{Image: ref}
+			if orig, found := origRefs[ref]; found && orig != ref {
+				img.Annotations = map[string]string{LockIDAnnKey: orig}
+			}
+			lock.Images = append(lock.Images, img)
+		}
+	}
+
+	sort.Slice(lock.Images, func(i, j int) bool { return lock.Images[i].Image < lock.Images[j].Image })
+
+	return lock
+}
+
+func (l Lock) WriteToFile(path string) error {
+	bs, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, bs, 0600)
+	if err != nil {
+		return fmt.Errorf("Writing images lock '%s': %w", path, err)
+	}
+	return nil
+}
+
+// Resolutions maps original image references to locked ones
+func (l Lock) Resolutions() map[string]string {
+	result := map[string]string{}
+	for _, img := range l.Images {
+		result[img.Image] = img.Image
+		if id, found := img.Annotations[LockIDAnnKey]; found {
+			result[id] = img.Image
+		}
+	}
+	return result
+}
+
+// AsMap is used to record images with app change
+func (l Lock) AsMap() map[string]string {
+	result := map[string]string{}
+	for _, img := range l.Images {
+		orig := img.Image
+		if id, found := img.Annotations[LockIDAnnKey]; found {
+			orig = id
+		}
+		result[orig] = img.Image
+	}
+	return result
+}
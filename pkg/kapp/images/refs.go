@@ -0,0 +1,59 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package images
+
+import (
+	"sort"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	imageKey = "image"
+)
+
+// Refs returns image references found in 'image' keys anywhere in
+// resource (e.g. in pod templates, custom resources) in a stable order
+func Refs(res ctlres.Resource) []string {
+	var result []string
+	visitRefs(res.UnstructuredObject(), func(ref string) string {
+		result = append(result, ref)
+		return ref
+	})
+	return result
+}
+
+// IsDigestRef returns true if image is referenced by its digest
+func IsDigestRef(ref string) bool {
+	return strings.Contains(ref, "@sha256:")
+}
+
+func replaceRefs(res ctlres.Resource, replaceFunc func(string) string) {
+	visitRefs(res.UnstructuredObject(), replaceFunc)
+}
+
+func visitRefs(val interface{}, visitFunc func(string) string) {
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		var keys []string
+		for k := range typedVal {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			v := typedVal[k]
+			if ref, ok := v.(string); ok && k == imageKey {
+				typedVal[k] = visitFunc(ref)
+				continue
+			}
+			visitRefs(v, visitFunc)
+		}
+	case []interface{}:
+		for _, v := range typedVal {
+			visitRefs(v, visitFunc)
+		}
+	}
+}
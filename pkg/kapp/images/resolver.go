@@ -0,0 +1,167 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+type ResolverOpts struct {
+	// Lock is used to replace image references with locked ones
+	Lock *Lock
+	// Command (e.g. 'kbld -f -') receives resources via stdin
+	// and is expected to print resources with resolved images
+	Command []string
+}
+
+// Resolver resolves image references to digests either
+// via an external command (e.g. kbld) or via images lock
+type Resolver struct {
+	opts ResolverOpts
+}
+
+func NewResolver(opts ResolverOpts) Resolver { return Resolver{opts} }
+
+func (r Resolver) IsEnabled() bool {
+	return r.opts.Lock != nil || len(r.opts.Command) > 0
+}
+
+// Resolve returns resources with resolved images and resolutions
+// of original image references (nil if resolver is not enabled)
+func (r Resolver) Resolve(rs []ctlres.Resource, skipFunc func(ctlres.Resource) bool) ([]ctlres.Resource, map[string]string, error) {
+	if !r.IsEnabled() {
+		return rs, nil, nil
+	}
+
+	resolutions := map[string]string{}
+
+	var toResolve []ctlres.Resource
+	var toResolveIdxs []int
+
+	for i, res := range rs {
+		if !skipFunc(res) {
+			toResolve = append(toResolve, res)
+			toResolveIdxs = append(toResolveIdxs, i)
+		}
+	}
+
+	origRefs := r.refsByResource(toResolve)
+
+	if len(r.opts.Command) > 0 {
+		var err error
+		toResolve, err = r.resolveWithCommand(toResolve)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if r.opts.Lock != nil {
+		lockResolutions := r.opts.Lock.Resolutions()
+		for _, res := range toResolve {
+			replaceRefs(res, func(ref string) string {
+				if resolved, found := lockResolutions[ref]; found {
+					return resolved
+				}
+				return ref
+			})
+		}
+	}
+
+	for _, res := range toResolve {
+		for _, ref := range Refs(res) {
+			if !IsDigestRef(ref) {
+				return nil, nil, fmt.Errorf("Expected image '%s' of %s to be resolved to a digest", ref, res.Description())
+			}
+		}
+	}
+
+	for key, resolvedRefs := range r.refsByResource(toResolve) {
+		refs := origRefs[key]
+		// Images are matched by position within the same resource
+		for i, resolvedRef := range resolvedRefs {
+			if i < len(refs) {
+				resolutions[refs[i]] = resolvedRef
+			}
+		}
+	}
+
+	// Preserve original order of resources
+	result := append([]ctlres.Resource{}, rs...)
+	for i, res := range toResolve {
+		result[toResolveIdxs[i]] = res
+	}
+
+	return result, resolutions, nil
+}
+
+func (r Resolver) refsByResource(rs []ctlres.Resource) map[string][]string {
+	result := map[string][]string{}
+	for _, res := range rs {
+		key := ctlres.NewUniqueResourceKey(res).String()
+		result[key] = Refs(res)
+	}
+	return result
+}
+
+func (r Resolver) resolveWithCommand(rs []ctlres.Resource) ([]ctlres.Resource, error) {
+	var input bytes.Buffer
+
+	origins := map[string]string{}
+
+	for _, res := range rs {
+		resBs, err := res.AsYAMLBytes()
+		if err != nil {
+			return nil, err
+		}
+		input.WriteString("---\n")
+		input.Write(resBs)
+
+		origins[ctlres.NewUniqueResourceKey(res).String()] = res.Origin()
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(r.opts.Command[0], r.opts.Command[1:]...)
+	cmd.Stdin = &input
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("Running images resolver '%s': %w (stderr: %s)",
+			strings.Join(r.opts.Command, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	docs, err := ctlres.NewYAMLFile(ctlres.NewBytesSource(stdout.Bytes())).Docs()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ctlres.Resource
+
+	for _, doc := range docs {
+		docRs, err := ctlres.NewResourcesFromBytes(doc)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing output of images resolver: %w", err)
+		}
+		// Resolver output does not carry file origins, hence restore them
+		for _, res := range docRs {
+			res.SetOrigin(origins[ctlres.NewUniqueResourceKey(res).String()])
+		}
+		result = append(result, docRs...)
+	}
+
+	if len(result) != len(rs) {
+		return nil, fmt.Errorf("Expected images resolver to return %d resources, but was %d", len(rs), len(result))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package images_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlimg "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/images"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	nginxDigest = "index.docker.io/library/nginx@sha256:1111111111111111111111111111111111111111111111111111111111111111"
+)
+
+func TestResolverWithLock(t *testing.T) {
+	rs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: nginx:1.25
+`))).Resources()
+	require.NoError(t, err)
+
+	lock := ctlimg.Lock{
+		APIVersion: ctlimg.LockAPIVersion,
+		Kind:       ctlimg.LockKind,
+		Images: []ctlimg.LockedImage{{
+			Image:       nginxDigest,
+			Annotations: map[string]string{ctlimg.LockIDAnnKey: "nginx:1.25"},
+		}},
+	}
+
+	resolver := ctlimg.NewResolver(ctlimg.ResolverOpts{Lock: &lock})
+
+	resolvedRs, resolutions, err := resolver.Resolve(rs, func(ctlres.Resource) bool { return false })
+	require.NoError(t, err)
+	require.Equal(t, []string{nginxDigest}, ctlimg.Refs(resolvedRs[0]))
+	require.Equal(t, map[string]string{"nginx:1.25": nginxDigest}, resolutions)
+
+	resultLock := ctlimg.NewLockFromResources(resolvedRs, resolutions)
+	require.Equal(t, lock, resultLock)
+	require.Equal(t, map[string]string{"nginx:1.25": nginxDigest}, resultLock.AsMap())
+}
+
+func TestResolverRequiresDigests(t *testing.T) {
+	rs, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - name: web
+    image: redis:7
+`))).Resources()
+	require.NoError(t, err)
+
+	lock := ctlimg.Lock{APIVersion: ctlimg.LockAPIVersion, Kind: ctlimg.LockKind}
+	resolver := ctlimg.NewResolver(ctlimg.ResolverOpts{Lock: &lock})
+
+	_, _, err = resolver.Resolve(rs, func(ctlres.Resource) bool { return false })
+	require.EqualError(t, err, "Expected image 'redis:7' of pod/web (v1) cluster to be resolved to a digest")
+}
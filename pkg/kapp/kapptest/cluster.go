@@ -0,0 +1,309 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kapptest allows to test kapp configs, preflight checks and
+// wait rules without a real cluster. Cluster serves Kubernetes API
+// from memory (no controllers are running) and deploy, diff and delete
+// flows run in-process via sdk package against it:
+//
+//	cluster := kapptest.NewCluster(t, kapptest.ClusterOpts{})
+//	result, err := cluster.Deploy(sdk.DeployOpts{
+//		App:       "app",
+//		Resources: kapptest.ResourcesFromFiles(t, "testdata/app.yml"),
+//	})
+//
+// Unlike other kapp packages, exported API of this package is kept stable.
+package kapptest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/memcluster"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/sdk"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+const defaultNamespace = "default"
+
+type ClusterOpts struct {
+	// ResourceTypes are served in addition to built-in ones (e.g. Deployment, ConfigMap);
+	// types of custom resources are also served once their CRDs are created
+	ResourceTypes []metav1.APIResource
+
+	// Reconcile (optional) simulates controllers: it is called with each created or
+	// updated object and may change its status (other changes are ignored).
+	// It allows to test wait rules, for example, by marking resources as ready.
+	Reconcile func(obj *unstructured.Unstructured)
+
+	// Debug includes kapp debug logs in test output
+	Debug bool
+}
+
+// Cluster is closed when test finishes
+type Cluster struct {
+	opts     ClusterOpts
+	objs     *memcluster.Cluster
+	resTypes *resourceTypes
+	server   *httptest.Server
+	client   *sdk.Client
+}
+
+func NewCluster(t testing.TB, opts ClusterOpts) *Cluster {
+	t.Helper()
+
+	cluster := &Cluster{
+		opts:     opts,
+		objs:     memcluster.NewCluster(),
+		resTypes: newResourceTypes(append(builtinResourceTypes(), opts.ResourceTypes...)),
+	}
+
+	cluster.server = httptest.NewServer(server{cluster})
+	t.Cleanup(cluster.server.Close)
+
+	client, err := sdk.NewClient(sdk.ClientOpts{
+		RESTConfig: cluster.RESTConfig(),
+		Namespace:  defaultNamespace,
+		Output:     newTestWriter(t),
+		Debug:      opts.Debug,
+	})
+	if err != nil {
+		t.Fatalf("Building client: %s", err)
+	}
+
+	cluster.client = client
+
+	return cluster
+}
+
+// RESTConfig allows to use other clients (e.g. client-go) against cluster
+func (c *Cluster) RESTConfig() *rest.Config {
+	return &rest.Config{
+		Host: c.server.URL,
+		// Client side rate limiting only slows down tests
+		QPS:   1000,
+		Burst: 1000,
+	}
+}
+
+// Client returns client connected to cluster; its output is included in test output
+func (c *Cluster) Client() *sdk.Client { return c.client }
+
+func (c *Cluster) Deploy(opts sdk.DeployOpts) (*sdk.AppChangeResult, error) {
+	return c.client.Deploy(opts)
+}
+
+func (c *Cluster) Diff(opts sdk.DeployOpts) (*sdk.AppChangeResult, error) {
+	return c.client.Diff(opts)
+}
+
+func (c *Cluster) Delete(opts sdk.DeleteOpts) (*sdk.AppChangeResult, error) {
+	return c.client.Delete(opts)
+}
+
+// Create adds objects to cluster as if they were created by someone else
+// (e.g. to test ownership checks); namespaced objects default to 'default' namespace
+func (c *Cluster) Create(objs ...unstructured.Unstructured) error {
+	for _, obj := range objs {
+		gvr, namespace, err := c.locate(&obj)
+		if err != nil {
+			return err
+		}
+
+		_, err = c.create(gvr, namespace, obj.DeepCopy())
+		if err != nil {
+			return fmt.Errorf("Creating %s: %w", c.desc(&obj), err)
+		}
+	}
+	return nil
+}
+
+// Get returns object from cluster; use errors.IsNotFound to check if it is missing
+func (c *Cluster) Get(apiVersion, kind, namespace, name string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+
+	gvr, namespace, err := c.locate(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.resClient(gvr, namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+// UpdateStatus replaces status of existing object (e.g. to simulate
+// controller reporting failure after resource was deployed)
+func (c *Cluster) UpdateStatus(obj unstructured.Unstructured) error {
+	gvr, namespace, err := c.locate(&obj)
+	if err != nil {
+		return err
+	}
+
+	updatedObj := obj.DeepCopy()
+	// Status is replaced regardless of changes made in the meantime
+	updatedObj.SetResourceVersion("")
+
+	_, err = c.resClient(gvr, namespace).UpdateStatus(context.TODO(), updatedObj, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("Updating status of %s: %w", c.desc(&obj), err)
+	}
+	return nil
+}
+
+func (c *Cluster) locate(obj *unstructured.Unstructured) (schema.GroupVersionResource, string, error) {
+	gvk := obj.GroupVersionKind()
+
+	resType, found := c.resTypes.FindKind(gvk)
+	if !found {
+		return schema.GroupVersionResource{}, "", fmt.Errorf("Expected kind '%s' in API version '%s' to be served",
+			gvk.Kind, gvk.GroupVersion())
+	}
+
+	namespace := ""
+	if resType.Namespaced {
+		namespace = obj.GetNamespace()
+		if len(namespace) == 0 {
+			namespace = defaultNamespace
+		}
+	}
+
+	return gvk.GroupVersion().WithResource(resType.Name), namespace, nil
+}
+
+func (c *Cluster) desc(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s (%s) namespace: %s", obj.GetKind(), obj.GetName(), obj.GetAPIVersion(), obj.GetNamespace())
+}
+
+func (c *Cluster) resClient(gvr schema.GroupVersionResource, namespace string) dynamic.ResourceInterface {
+	return c.objs.Resource(gvr).Namespace(namespace)
+}
+
+func (c *Cluster) create(gvr schema.GroupVersionResource, namespace string,
+	obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+
+	obj, err := c.resClient(gvr, namespace).Create(context.TODO(), obj, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return c.reconcile(gvr, obj)
+}
+
+func (c *Cluster) update(gvr schema.GroupVersionResource, namespace string,
+	obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+
+	obj, err := c.resClient(gvr, namespace).Update(context.TODO(), obj, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return c.reconcile(gvr, obj)
+}
+
+func (c *Cluster) patch(gvr schema.GroupVersionResource, namespace, name string,
+	pt types.PatchType, data []byte) (*unstructured.Unstructured, error) {
+
+	obj, err := c.resClient(gvr, namespace).Patch(context.TODO(), name, pt, data, metav1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return c.reconcile(gvr, obj)
+}
+
+func (c *Cluster) delete(gvr schema.GroupVersionResource, namespace, name string) error {
+	err := c.resClient(gvr, namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		return err
+	}
+	if gvr == crdGVR {
+		c.resTypes.RemoveCRD(name)
+	}
+	return nil
+}
+
+// reconcile serves custom resource types of CRDs (which are immediately
+// established) and lets Reconcile func update status of other objects
+func (c *Cluster) reconcile(gvr schema.GroupVersionResource,
+	obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+
+	reconciledObj := obj.DeepCopy()
+
+	switch {
+	case gvr == crdGVR:
+		c.resTypes.SetCRD(obj)
+		c.establishCRD(reconciledObj)
+	case c.opts.Reconcile != nil:
+		c.opts.Reconcile(reconciledObj)
+	default:
+		return obj, nil
+	}
+
+	if reflect.DeepEqual(obj.Object["status"], reconciledObj.Object["status"]) {
+		return obj, nil
+	}
+
+	reconciledObj, err := c.resClient(gvr, obj.GetNamespace()).UpdateStatus(context.TODO(), reconciledObj, metav1.UpdateOptions{})
+	if err != nil {
+		if errors.IsConflict(err) {
+			return nil, fmt.Errorf("Expected Reconcile func to not change resource version: %w", err)
+		}
+		return nil, err
+	}
+
+	return reconciledObj, nil
+}
+
+func (c *Cluster) establishCRD(crd *unstructured.Unstructured) {
+	names, _, _ := unstructured.NestedMap(crd.Object, "spec", "names")
+
+	crd.Object["status"] = map[string]interface{}{
+		"acceptedNames": names,
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "NamesAccepted", "status": "True", "reason": "NoConflicts"},
+			map[string]interface{}{"type": "Established", "status": "True", "reason": "InitialNamesAccepted"},
+		},
+	}
+}
+
+// testWriter includes client output in test output line by line
+type testWriter struct {
+	t    testing.TB
+	lock *sync.Mutex
+	buf  *bytes.Buffer
+}
+
+func newTestWriter(t testing.TB) testWriter {
+	return testWriter{t, &sync.Mutex{}, &bytes.Buffer{}}
+}
+
+func (w testWriter) Write(data []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buf.Write(data)
+
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// Incomplete line is kept until rest of it is written
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			break
+		}
+		w.t.Log(strings.TrimSuffix(line, "\n"))
+	}
+
+	return len(data), nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package kapptest_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/kapptest"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/sdk"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestClusterDeployDiffDelete(t *testing.T) {
+	cluster := kapptest.NewCluster(t, kapptest.ClusterOpts{
+		Reconcile: func(obj *unstructured.Unstructured) {
+			if obj.GetKind() == "Deployment" {
+				obj.Object["status"] = map[string]interface{}{
+					"observedGeneration": obj.GetGeneration(),
+					"replicas":           int64(1),
+					"updatedReplicas":    int64(1),
+					"readyReplicas":      int64(1),
+				}
+			}
+		},
+	})
+
+	resources := kapptest.ResourcesFromYAML(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key: val
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: dep
+  template:
+    metadata:
+      labels:
+        app: dep
+    spec:
+      containers:
+      - name: app
+        image: app
+`)
+
+	result, err := cluster.Deploy(sdk.DeployOpts{App: "app", Resources: resources})
+	require.NoError(t, err)
+	require.True(t, result.Successful)
+	require.Equal(t, map[string]int{"add": 2}, result.Summary.Ops)
+
+	cm, err := cluster.Get("v1", "ConfigMap", "default", "cm")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"key": "val"}, cm.Object["data"])
+
+	result, err = cluster.Diff(sdk.DeployOpts{App: "app", Resources: resources})
+	require.NoError(t, err)
+	require.False(t, result.HasChanges())
+
+	result, err = cluster.Deploy(sdk.DeployOpts{App: "app", Resources: resources[:1]})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"delete": 1}, result.Summary.Ops)
+
+	_, err = cluster.Get("apps/v1", "Deployment", "default", "dep")
+	require.True(t, errors.IsNotFound(err), "Expected deployment to be deleted: %v", err)
+
+	result, err = cluster.Delete(sdk.DeleteOpts{App: "app"})
+	require.NoError(t, err)
+	require.True(t, result.Successful)
+
+	_, err = cluster.Get("v1", "ConfigMap", "default", "cm")
+	require.True(t, errors.IsNotFound(err), "Expected config map to be deleted: %v", err)
+}
+
+func TestClusterCustomResourcesWithWaitRule(t *testing.T) {
+	cluster := kapptest.NewCluster(t, kapptest.ClusterOpts{
+		Reconcile: func(obj *unstructured.Unstructured) {
+			if obj.GetKind() == "CronTab" {
+				obj.Object["status"] = map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Available", "status": "True"},
+					},
+				}
+			}
+		},
+	})
+
+	resources := kapptest.ResourcesFromYAML(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitRules:
+- conditionMatchers:
+  - type: Available
+    status: "True"
+    success: true
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: stable.example.com/v1, kind: CronTab}
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+  scope: Namespaced
+  names:
+    plural: crontabs
+    singular: crontab
+    kind: CronTab
+---
+apiVersion: stable.example.com/v1
+kind: CronTab
+metadata:
+  name: cron
+spec:
+  cronSpec: "* * * * */5"
+`)
+
+	result, err := cluster.Deploy(sdk.DeployOpts{App: "app", Resources: resources})
+	require.NoError(t, err)
+	require.True(t, result.Successful)
+
+	cron, err := cluster.Get("stable.example.com/v1", "CronTab", "default", "cron")
+	require.NoError(t, err)
+	require.Equal(t, "* * * * */5", cron.Object["spec"].(map[string]interface{})["cronSpec"])
+
+	result, err = cluster.Delete(sdk.DeleteOpts{App: "app"})
+	require.NoError(t, err)
+	require.True(t, result.Successful)
+
+	_, err = cluster.Get("stable.example.com/v1", "CronTab", "default", "cron")
+	require.EqualError(t, err,
+		"Expected kind 'CronTab' in API version 'stable.example.com/v1' to be served")
+}
+
+func TestClusterPreflightRulesAndOwnership(t *testing.T) {
+	cluster := kapptest.NewCluster(t, kapptest.ClusterOpts{})
+
+	config := kapptest.ResourcesFromYAML(t, `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: naming
+  exec:
+    command: sh
+    args:
+    - -c
+    - 'if grep -q "\"name\":\"tmp-"; then echo "temporary names are not allowed"; exit 1; fi'
+`)
+
+	denied := kapptest.ResourcesFromYAML(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: tmp-cm
+`)
+
+	_, err := cluster.Deploy(sdk.DeployOpts{App: "app", Resources: append(config, denied...)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Check 'naming' denied changes: temporary names are not allowed")
+
+	_, err = cluster.Get("v1", "ConfigMap", "default", "tmp-cm")
+	require.True(t, errors.IsNotFound(err), "Expected config map to not be created: %v", err)
+
+	existing := kapptest.ResourcesFromYAML(t, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: existing
+`)
+
+	require.NoError(t, cluster.Create(existing...))
+
+	_, err = cluster.Deploy(sdk.DeployOpts{App: "app", Resources: append(config, existing...)})
+	require.NoError(t, err)
+
+	_, err = cluster.Deploy(sdk.DeployOpts{App: "other-app", Resources: existing})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is already associated with a different app")
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package kapptest
+
+import (
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	resourceVerbs = []string{"create", "delete", "get", "list", "patch", "update"}
+
+	crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+)
+
+// builtinResourceTypes are commonly deployed resource types
+// (controllers are not running for any of them)
+func builtinResourceTypes() []metav1.APIResource {
+	namespaced := func(group, version, kind, name string) metav1.APIResource {
+		return metav1.APIResource{Group: group, Version: version, Kind: kind, Name: name, Namespaced: true}
+	}
+	cluster := func(group, version, kind, name string) metav1.APIResource {
+		return metav1.APIResource{Group: group, Version: version, Kind: kind, Name: name}
+	}
+
+	return []metav1.APIResource{
+		cluster("", "v1", "Namespace", "namespaces"),
+		namespaced("", "v1", "ConfigMap", "configmaps"),
+		namespaced("", "v1", "Secret", "secrets"),
+		namespaced("", "v1", "Service", "services"),
+		namespaced("", "v1", "ServiceAccount", "serviceaccounts"),
+		namespaced("", "v1", "Pod", "pods"),
+		namespaced("", "v1", "PersistentVolumeClaim", "persistentvolumeclaims"),
+		namespaced("apps", "v1", "Deployment", "deployments"),
+		namespaced("apps", "v1", "StatefulSet", "statefulsets"),
+		namespaced("apps", "v1", "DaemonSet", "daemonsets"),
+		namespaced("apps", "v1", "ReplicaSet", "replicasets"),
+		namespaced("batch", "v1", "Job", "jobs"),
+		namespaced("batch", "v1", "CronJob", "cronjobs"),
+		namespaced("rbac.authorization.k8s.io", "v1", "Role", "roles"),
+		namespaced("rbac.authorization.k8s.io", "v1", "RoleBinding", "rolebindings"),
+		cluster("rbac.authorization.k8s.io", "v1", "ClusterRole", "clusterroles"),
+		cluster("rbac.authorization.k8s.io", "v1", "ClusterRoleBinding", "clusterrolebindings"),
+		namespaced("networking.k8s.io", "v1", "Ingress", "ingresses"),
+		namespaced("networking.k8s.io", "v1", "NetworkPolicy", "networkpolicies"),
+		namespaced("policy", "v1", "PodDisruptionBudget", "poddisruptionbudgets"),
+		namespaced("coordination.k8s.io", "v1", "Lease", "leases"),
+		cluster(crdGVR.Group, crdGVR.Version, "CustomResourceDefinition", crdGVR.Resource),
+	}
+}
+
+// resourceTypes keeps served resource types; types of
+// custom resources are added and removed together with their CRDs
+type resourceTypes struct {
+	lock  sync.Mutex
+	types map[schema.GroupVersion]map[string]metav1.APIResource
+	// crdTypes tracks types added for CRDs by CRD name
+	crdTypes map[string][]metav1.APIResource
+}
+
+func newResourceTypes(types []metav1.APIResource) *resourceTypes {
+	result := &resourceTypes{
+		types:    map[schema.GroupVersion]map[string]metav1.APIResource{},
+		crdTypes: map[string][]metav1.APIResource{},
+	}
+	for _, resType := range types {
+		result.add(resType)
+	}
+	return result
+}
+
+// add expects lock to be held (or type not to be shared yet)
+func (t *resourceTypes) add(resType metav1.APIResource) {
+	if len(resType.Verbs) == 0 {
+		resType.Verbs = resourceVerbs
+	}
+	gv := schema.GroupVersion{Group: resType.Group, Version: resType.Version}
+	if _, found := t.types[gv]; !found {
+		t.types[gv] = map[string]metav1.APIResource{}
+	}
+	t.types[gv][resType.Name] = resType
+}
+
+func (t *resourceTypes) Find(gv schema.GroupVersion, name string) (metav1.APIResource, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	resType, found := t.types[gv][name]
+	return resType, found
+}
+
+func (t *resourceTypes) FindKind(gvk schema.GroupVersionKind) (metav1.APIResource, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, resType := range t.types[gvk.GroupVersion()] {
+		if resType.Kind == gvk.Kind {
+			return resType, true
+		}
+	}
+	return metav1.APIResource{}, false
+}
+
+// GroupVersions returns served group versions sorted by group and version
+func (t *resourceTypes) GroupVersions() []schema.GroupVersion {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var result []schema.GroupVersion
+	for gv := range t.types {
+		result = append(result, gv)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Group != result[j].Group {
+			return result[i].Group < result[j].Group
+		}
+		return result[i].Version < result[j].Version
+	})
+	return result
+}
+
+func (t *resourceTypes) ResourceList(gv schema.GroupVersion) (*metav1.APIResourceList, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	types, found := t.types[gv]
+	if !found {
+		return nil, false
+	}
+
+	list := &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "APIResourceList"},
+		GroupVersion: gv.String(),
+	}
+	for _, resType := range types {
+		// Group and version are implied by the list
+		resType.Group = ""
+		resType.Version = ""
+		list.APIResources = append(list.APIResources, resType)
+	}
+	sort.Slice(list.APIResources, func(i, j int) bool {
+		return list.APIResources[i].Name < list.APIResources[j].Name
+	})
+
+	return list, true
+}
+
+// SetCRD serves types of CRD's served versions (replacing previously served ones)
+func (t *resourceTypes) SetCRD(crd *unstructured.Unstructured) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.removeCRD(crd.GetName())
+
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	singular, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "singular")
+	shortNames, _, _ := unstructured.NestedStringSlice(crd.Object, "spec", "names", "shortNames")
+	scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+
+	for _, version := range versions {
+		versionMap, ok := version.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(versionMap, "name")
+		served, _, _ := unstructured.NestedBool(versionMap, "served")
+		if !served || len(name) == 0 {
+			continue
+		}
+
+		resType := metav1.APIResource{
+			Group:        group,
+			Version:      name,
+			Kind:         kind,
+			Name:         plural,
+			SingularName: singular,
+			ShortNames:   shortNames,
+			Namespaced:   scope == "Namespaced",
+		}
+		t.add(resType)
+		t.crdTypes[crd.GetName()] = append(t.crdTypes[crd.GetName()], resType)
+	}
+}
+
+func (t *resourceTypes) RemoveCRD(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.removeCRD(name)
+}
+
+// removeCRD expects lock to be held
+func (t *resourceTypes) removeCRD(name string) {
+	for _, resType := range t.crdTypes[name] {
+		gv := schema.GroupVersion{Group: resType.Group, Version: resType.Version}
+		delete(t.types[gv], resType.Name)
+		if len(t.types[gv]) == 0 {
+			delete(t.types, gv)
+		}
+	}
+	delete(t.crdTypes, name)
+}
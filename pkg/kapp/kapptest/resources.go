@@ -0,0 +1,56 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package kapptest
+
+import (
+	"testing"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ResourcesFromYAML parses resources (including kapp configs) from YAML documents
+func ResourcesFromYAML(t testing.TB, content string) []unstructured.Unstructured {
+	t.Helper()
+
+	resources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(content))).Resources()
+	if err != nil {
+		t.Fatalf("Parsing resources: %s", err)
+	}
+
+	return unstructuredObjs(resources)
+}
+
+// ResourcesFromFiles reads resources from files or directories the same way as kapp deploy -f
+func ResourcesFromFiles(t testing.TB, paths ...string) []unstructured.Unstructured {
+	t.Helper()
+
+	var resources []ctlres.Resource
+
+	for _, path := range paths {
+		fileRs, err := ctlres.NewFileResources(nil, path)
+		if err != nil {
+			t.Fatalf("Reading files '%s': %s", path, err)
+		}
+
+		for _, fileRes := range fileRs {
+			rs, err := fileRes.Resources()
+			if err != nil {
+				t.Fatalf("Parsing resources from %s: %s", fileRes.Description(), err)
+			}
+			resources = append(resources, rs...)
+		}
+	}
+
+	return unstructuredObjs(resources)
+}
+
+func unstructuredObjs(resources []ctlres.Resource) []unstructured.Unstructured {
+	// Empty (but not nil) list allows to delete all app resources
+	result := []unstructured.Unstructured{}
+	for _, res := range resources {
+		result = append(result, unstructured.Unstructured{Object: res.DeepCopyRaw()})
+	}
+	return result
+}
@@ -0,0 +1,308 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package kapptest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// server translates Kubernetes API requests into operations against
+// in-memory cluster; only JSON encoded requests are supported
+type server struct {
+	cluster *Cluster
+}
+
+var _ http.Handler = server{}
+
+// resourceRequest describes request targeting resources
+// (e.g. /apis/apps/v1/namespaces/default/deployments/app)
+type resourceRequest struct {
+	gvr         schema.GroupVersionResource
+	resType     metav1.APIResource
+	namespace   string
+	name        string
+	subresource string
+}
+
+func (s server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	var gv schema.GroupVersion
+	var rest []string
+
+	switch {
+	case len(segments) == 1 && segments[0] == "version":
+		s.writeJSON(w, http.StatusOK, version.Info{Major: "1", Minor: "28", GitVersion: "v1.28.0-kapptest"})
+		return
+
+	case len(segments) == 1 && segments[0] == "api":
+		s.writeJSON(w, http.StatusOK, &metav1.APIVersions{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "APIVersions"},
+			Versions: []string{"v1"},
+		})
+		return
+
+	case len(segments) == 1 && segments[0] == "apis":
+		s.writeJSON(w, http.StatusOK, s.groupList())
+		return
+
+	case len(segments) >= 2 && segments[0] == "api":
+		gv = schema.GroupVersion{Version: segments[1]}
+		rest = segments[2:]
+
+	case len(segments) >= 3 && segments[0] == "apis":
+		gv = schema.GroupVersion{Group: segments[1], Version: segments[2]}
+		rest = segments[3:]
+
+	default:
+		s.writeErr(w, errors.NewNotFound(schema.GroupResource{}, r.URL.Path))
+		return
+	}
+
+	if len(rest) == 0 {
+		list, found := s.cluster.resTypes.ResourceList(gv)
+		if !found {
+			s.writeErr(w, errors.NewNotFound(schema.GroupResource{}, r.URL.Path))
+			return
+		}
+		s.writeJSON(w, http.StatusOK, list)
+		return
+	}
+
+	req, err := s.resourceRequest(gv, rest)
+	if err != nil {
+		s.writeErr(w, err)
+		return
+	}
+
+	var result interface{}
+	status := http.StatusOK
+
+	switch {
+	case r.Method == http.MethodGet && len(req.name) == 0:
+		result, err = s.list(r, req)
+	case r.Method == http.MethodGet:
+		result, err = s.cluster.resClient(req.gvr, req.namespace).Get(context.TODO(), req.name, metav1.GetOptions{})
+	case r.Method == http.MethodPost && len(req.name) == 0:
+		result, err = s.create(r, req)
+		status = http.StatusCreated
+	case r.Method == http.MethodPut && len(req.name) > 0:
+		result, err = s.update(r, req)
+	case r.Method == http.MethodPatch && len(req.name) > 0:
+		result, err = s.patch(r, req)
+	case r.Method == http.MethodDelete && len(req.name) > 0:
+		result, err = s.delete(req)
+	default:
+		err = errors.NewMethodNotSupported(req.gvr.GroupResource(), r.Method)
+	}
+	if err != nil {
+		s.writeErr(w, err)
+		return
+	}
+
+	s.writeJSON(w, status, result)
+}
+
+func (s server) resourceRequest(gv schema.GroupVersion, rest []string) (resourceRequest, error) {
+	req := resourceRequest{}
+
+	// Namespace's own subresources (e.g. namespaces/name/finalize) are not namespaced requests
+	if len(rest) >= 3 && rest[0] == "namespaces" {
+		if resType, found := s.cluster.resTypes.Find(gv, rest[2]); found && resType.Namespaced {
+			req.namespace = rest[1]
+			rest = rest[2:]
+		}
+	}
+
+	resType, found := s.cluster.resTypes.Find(gv, rest[0])
+	if !found {
+		return req, errors.NewNotFound(gv.WithResource(rest[0]).GroupResource(), "")
+	}
+
+	req.gvr = gv.WithResource(rest[0])
+	req.resType = resType
+
+	if len(rest) > 1 {
+		req.name = rest[1]
+	}
+	if len(rest) > 2 {
+		req.subresource = strings.Join(rest[2:], "/")
+	}
+	if len(rest) > 3 {
+		return req, errors.NewNotFound(req.gvr.GroupResource(), req.name)
+	}
+
+	return req, nil
+}
+
+func (s server) list(r *http.Request, req resourceRequest) (interface{}, error) {
+	if r.URL.Query().Get("watch") == "true" {
+		return nil, errors.NewMethodNotSupported(req.gvr.GroupResource(), "watch")
+	}
+
+	// Pagination is not supported hence all objects are always returned
+	list, err := s.cluster.resClient(req.gvr, req.namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: r.URL.Query().Get("labelSelector"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list.SetAPIVersion(req.gvr.GroupVersion().String())
+	list.SetKind(req.resType.Kind + "List")
+
+	if list.Items == nil {
+		// Clients expect list of items to be present
+		list.Items = []unstructured.Unstructured{}
+	}
+
+	return list, nil
+}
+
+func (s server) create(r *http.Request, req resourceRequest) (interface{}, error) {
+	if len(req.subresource) > 0 {
+		return nil, errors.NewMethodNotSupported(req.gvr.GroupResource(), "create "+req.subresource)
+	}
+
+	obj, err := s.readObj(r, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cluster.create(req.gvr, req.namespace, obj)
+}
+
+func (s server) update(r *http.Request, req resourceRequest) (interface{}, error) {
+	obj, err := s.readObj(r, req)
+	if err != nil {
+		return nil, err
+	}
+	if obj.GetName() != req.name {
+		return nil, errors.NewBadRequest(fmt.Sprintf("Expected object name '%s' to match name '%s' in request path", obj.GetName(), req.name))
+	}
+
+	switch req.subresource {
+	case "":
+		return s.cluster.update(req.gvr, req.namespace, obj)
+	case "status":
+		return s.cluster.resClient(req.gvr, req.namespace).UpdateStatus(context.TODO(), obj, metav1.UpdateOptions{})
+	default:
+		return nil, errors.NewMethodNotSupported(req.gvr.GroupResource(), "update "+req.subresource)
+	}
+}
+
+func (s server) patch(r *http.Request, req resourceRequest) (interface{}, error) {
+	if len(req.subresource) > 0 {
+		return nil, errors.NewMethodNotSupported(req.gvr.GroupResource(), "patch "+req.subresource)
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	contentType := strings.TrimSpace(strings.Split(r.Header.Get("Content-Type"), ";")[0])
+
+	return s.cluster.patch(req.gvr, req.namespace, req.name, types.PatchType(contentType), data)
+}
+
+func (s server) delete(req resourceRequest) (interface{}, error) {
+	if len(req.subresource) > 0 {
+		return nil, errors.NewMethodNotSupported(req.gvr.GroupResource(), "delete "+req.subresource)
+	}
+
+	// Objects are deleted immediately (finalizers and garbage collection are not simulated)
+	err := s.cluster.delete(req.gvr, req.namespace, req.name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metav1.Status{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+		Status:   metav1.StatusSuccess,
+	}, nil
+}
+
+func (s server) readObj(r *http.Request, req resourceRequest) (*unstructured.Unstructured, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	obj := &unstructured.Unstructured{}
+
+	err = obj.UnmarshalJSON(data)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("Expected JSON encoded object: %s", err))
+	}
+
+	if obj.GetAPIVersion() != req.gvr.GroupVersion().String() || obj.GetKind() != req.resType.Kind {
+		return nil, errors.NewBadRequest(fmt.Sprintf("Expected object of kind '%s' in API version '%s'",
+			req.resType.Kind, req.gvr.GroupVersion()))
+	}
+
+	return obj, nil
+}
+
+func (s server) groupList() *metav1.APIGroupList {
+	list := &metav1.APIGroupList{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "APIGroupList"}}
+	groupIdxs := map[string]int{}
+
+	for _, gv := range s.cluster.resTypes.GroupVersions() {
+		if len(gv.Group) == 0 {
+			continue
+		}
+		verForDiscovery := metav1.GroupVersionForDiscovery{GroupVersion: gv.String(), Version: gv.Version}
+
+		idx, found := groupIdxs[gv.Group]
+		if !found {
+			groupIdxs[gv.Group] = len(list.Groups)
+			list.Groups = append(list.Groups, metav1.APIGroup{Name: gv.Group, PreferredVersion: verForDiscovery})
+			idx = len(list.Groups) - 1
+		}
+		list.Groups[idx].Versions = append(list.Groups[idx].Versions, verForDiscovery)
+	}
+
+	return list
+}
+
+func (s server) writeJSON(w http.ResponseWriter, status int, obj interface{}) {
+	bs, err := json.Marshal(obj)
+	if err != nil {
+		s.writeErr(w, errors.NewInternalError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(bs)
+}
+
+func (s server) writeErr(w http.ResponseWriter, err error) {
+	apiStatus, ok := err.(errors.APIStatus)
+	if !ok {
+		apiStatus = errors.NewInternalError(err)
+	}
+
+	status := apiStatus.Status()
+	status.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}
+
+	bs, _ := json.Marshal(status)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(status.Code))
+	w.Write(bs)
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kustomizeinput builds directories with kustomization file.
+//
+// Kustomizations are built by running external kustomize binary
+// (not in-process via sigs.k8s.io/kustomize/api since it is not a kapp
+// dependency), hence kustomize has to be installed on the machine running kapp.
+package kustomizeinput
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Set by kustomize when 'buildMetadata: [originAnnotations]' is enabled
+	originAnnKey = "config.kubernetes.io/origin"
+)
+
+var (
+	// Same names as recognized by kustomize
+	kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+)
+
+// Dir builds kustomization directory via 'kustomize build'
+type Dir struct {
+	dir    string
+	binary string
+}
+
+// NewDir uses 'kustomize' found in PATH if binary is empty
+func NewDir(dir, binary string) Dir {
+	if len(binary) == 0 {
+		binary = "kustomize"
+	}
+	return Dir{dir, binary}
+}
+
+// IsKustomizationDir uses OS file system if fsys is nil
+func IsKustomizationDir(fsys fs.FS, dir string) bool {
+	for _, name := range kustomizationFileNames {
+		var err error
+		if fsys == nil {
+			_, err = os.Stat(filepath.Join(dir, name))
+		} else {
+			_, err = fs.Stat(fsys, path.Join(dir, name))
+		}
+		if err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Dir) Resources() ([]ctlres.Resource, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(d.binary, "build", d.dir)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+
+	err := cmd.Run()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("Building kustomization '%s': Expected kustomize binary '%s' to be installed "+
+				"(kapp does not build kustomizations in-process): %w", d.dir, d.binary, err)
+		}
+		return nil, fmt.Errorf("Building kustomization '%s': %w (stderr: %s)",
+			d.dir, err, strings.TrimSpace(stderr.String()))
+	}
+
+	docs, err := ctlres.NewYAMLFile(ctlres.NewBytesSource(stdout.Bytes())).Docs()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []ctlres.Resource
+
+	for i, doc := range docs {
+		rs, err := ctlres.NewResourcesFromBytes(doc)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing built kustomization '%s': %w", d.dir, err)
+		}
+
+		for _, res := range rs {
+			res.SetOrigin(d.origin(res, i))
+		}
+
+		resources = append(resources, rs...)
+	}
+
+	return resources, nil
+}
+
+// origin points to file from which resource originated if kustomize recorded it
+func (d Dir) origin(res ctlres.Resource, docIdx int) string {
+	defaultOrigin := fmt.Sprintf("kustomization '%s' doc %d", d.dir, docIdx+1)
+
+	originVal, found := res.Annotations()[originAnnKey]
+	if !found {
+		return defaultOrigin
+	}
+
+	var origin struct {
+		Path string `json:"path"`
+	}
+
+	err := yaml.Unmarshal([]byte(originVal), &origin)
+	if err != nil || len(origin.Path) == 0 {
+		return defaultOrigin
+	}
+
+	return fmt.Sprintf("file '%s' (kustomization '%s')", filepath.Join(d.dir, origin.Path), d.dir)
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package kustomizeinput_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/kustomizeinput"
+)
+
+func TestDirResources(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake kustomize binary is a shell script")
+	}
+
+	dir := t.TempDir()
+
+	// Fake kustomize prints resources as if originAnnotations were enabled
+	binaryPath := filepath.Join(dir, "kustomize")
+	err := os.WriteFile(binaryPath, []byte(`#!/bin/sh
+cat <<EOF
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: with-origin
+  annotations:
+    config.kubernetes.io/origin: |
+      path: base/configmap.yaml
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: without-origin
+EOF
+`), 0700)
+	require.NoError(t, err)
+
+	require.False(t, kustomizeinput.IsKustomizationDir(nil, dir))
+
+	err = os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte("resources: []"), 0600)
+	require.NoError(t, err)
+	require.True(t, kustomizeinput.IsKustomizationDir(nil, dir))
+
+	rs, err := kustomizeinput.NewDir("overlays/prod", binaryPath).Resources()
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	require.Equal(t, "file 'overlays/prod/base/configmap.yaml' (kustomization 'overlays/prod')", rs[0].Origin())
+	require.Equal(t, "kustomization 'overlays/prod' doc 2", rs[1].Origin())
+}
+
+func TestDirResourcesWithoutKustomize(t *testing.T) {
+	_, err := kustomizeinput.NewDir("overlays/prod", "kapp-test-missing-kustomize").Resources()
+	require.ErrorContains(t, err, "Building kustomization 'overlays/prod': Expected kustomize binary "+
+		"'kapp-test-missing-kustomize' to be installed (kapp does not build kustomizations in-process)")
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logger
+
+const (
+	SubsystemDiff      = "diff"
+	SubsystemApply     = "apply"
+	SubsystemWait      = "wait"
+	SubsystemPreflight = "preflight"
+)
+
+var (
+	Subsystems = []string{SubsystemDiff, SubsystemApply, SubsystemWait, SubsystemPreflight}
+
+	// subsystemsByName maps logger prefixes to subsystems;
+	// nested loggers inherit subsystem unless their name is listed
+	subsystemsByName = map[string]string{
+		"ChangeGraph":           SubsystemDiff,
+		"ClusterChangeSet":      SubsystemApply,
+		"WaitingChanges":        SubsystemWait,
+		"ReadinessGatesChecker": SubsystemPreflight,
+		"PreflightRegistry":     SubsystemPreflight,
+	}
+)
+
+func IsKnownSubsystem(name string) bool {
+	for _, subsystem := range Subsystems {
+		if subsystem == name {
+			return true
+		}
+	}
+	return false
+}
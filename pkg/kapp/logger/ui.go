@@ -4,7 +4,9 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cppforlife/go-cli-ui/ui"
@@ -14,32 +16,89 @@ const (
 	loggerLevelError = "error"
 	loggerLevelInfo  = "info"
 	loggerLevelDebug = "debug"
+
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var (
+	levelSeverities = map[string]int{
+		loggerLevelError: 0,
+		loggerLevelInfo:  1,
+		loggerLevelDebug: 2,
+	}
 )
 
 type UILogger struct {
-	prefix string
-	ui     ui.UI
-	debug  bool
+	prefix    string
+	subsystem string
+	ui        ui.UI
+	config    *uiLoggerConfig
+}
+
+// uiLoggerConfig is shared between prefixed loggers
+// so that it could be configured after they are created
+type uiLoggerConfig struct {
+	format string
+	// Level per subsystem; empty subsystem is the default level
+	levels map[string]string
 }
 
 var _ Logger = &UILogger{}
 
-func NewUILogger(ui ui.UI) *UILogger { return &UILogger{"", ui, false} }
+func NewUILogger(ui ui.UI) *UILogger {
+	config := &uiLoggerConfig{format: FormatText, levels: map[string]string{"": loggerLevelInfo}}
+	return &UILogger{"", "", ui, config}
+}
 
-func (l *UILogger) SetDebug(debug bool) { l.debug = debug }
+// SetDebug enables debug level for all subsystems
+func (l *UILogger) SetDebug(debug bool) {
+	if debug {
+		l.config.levels = map[string]string{"": loggerLevelDebug}
+	}
+}
+
+func (l *UILogger) SetFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON:
+		l.config.format = format
+		return nil
+	default:
+		return fmt.Errorf("Unknown log format '%s' (supported: %s, %s)", format, FormatText, FormatJSON)
+	}
+}
+
+// SetLevels configures levels from specs in 'level' or 'subsystem=level' format
+// (e.g. 'info', 'apply=debug'); later specs take precedence
+func (l *UILogger) SetLevels(specs []string) error {
+	for _, spec := range specs {
+		subsystem, level := "", spec
+		if pieces := strings.SplitN(spec, "=", 2); len(pieces) == 2 {
+			subsystem, level = pieces[0], pieces[1]
+			if !IsKnownSubsystem(subsystem) {
+				return fmt.Errorf("Unknown log subsystem '%s' (supported: %s)",
+					subsystem, strings.Join(Subsystems, ", "))
+			}
+		}
+		if _, found := levelSeverities[level]; !found {
+			return fmt.Errorf("Unknown log level '%s' (supported: %s, %s, %s)",
+				level, loggerLevelError, loggerLevelInfo, loggerLevelDebug)
+		}
+		l.config.levels[subsystem] = level
+	}
+	return nil
+}
 
 func (l *UILogger) Error(msg string, args ...interface{}) {
-	l.ui.BeginLinef(l.msg(loggerLevelError, msg), args...)
+	l.log(loggerLevelError, msg, args...)
 }
 
 func (l *UILogger) Info(msg string, args ...interface{}) {
-	l.ui.BeginLinef(l.msg(loggerLevelInfo, msg), args...)
+	l.log(loggerLevelInfo, msg, args...)
 }
 
 func (l *UILogger) Debug(msg string, args ...interface{}) {
-	if l.debug {
-		l.ui.BeginLinef(l.msg(loggerLevelDebug, msg), args...)
-	}
+	l.log(loggerLevelDebug, msg, args...)
 }
 
 func (l *UILogger) DebugFunc(name string) FuncLogger {
@@ -49,16 +108,60 @@ func (l *UILogger) DebugFunc(name string) FuncLogger {
 }
 
 func (l *UILogger) NewPrefixed(name string) Logger {
+	subsystem := l.subsystem
+	if nameSubsystem, found := subsystemsByName[name]; found {
+		subsystem = nameSubsystem
+	}
 	if len(l.prefix) > 0 {
 		name = l.prefix + name
 	}
 	name += ": "
-	return &UILogger{name, l.ui, l.debug}
+	return &UILogger{name, subsystem, l.ui, l.config}
 }
 
-func (l *UILogger) msg(level, msg string) string {
+func (l *UILogger) isEnabled(level string) bool {
+	maxLevel, found := l.config.levels[l.subsystem]
+	if !found {
+		maxLevel = l.config.levels[""]
+	}
+	return levelSeverities[level] <= levelSeverities[maxLevel]
+}
+
+func (l *UILogger) log(level, msg string, args ...interface{}) {
+	if !l.isEnabled(level) {
+		return
+	}
+
+	if l.config.format == FormatJSON {
+		l.ui.BeginLinef("%s\n", l.jsonMsg(level, fmt.Sprintf(msg, args...)))
+		return
+	}
+
 	ts := time.Now().Format("03:04:05PM")
-	return fmt.Sprintf("%s: %s: %s%s\n", ts, level, l.prefix, msg)
+	l.ui.BeginLinef(fmt.Sprintf("%s: %s: %s%s\n", ts, level, l.prefix, msg), args...)
+}
+
+type jsonLogLine struct {
+	Time      time.Time `json:"time"`
+	Level     string    `json:"level"`
+	Subsystem string    `json:"subsystem,omitempty"`
+	Logger    string    `json:"logger,omitempty"`
+	Msg       string    `json:"msg"`
+}
+
+func (l *UILogger) jsonMsg(level, msg string) string {
+	bs, err := json.Marshal(jsonLogLine{
+		Time:      time.Now().UTC(),
+		Level:     level,
+		Subsystem: l.subsystem,
+		Logger:    strings.TrimSuffix(l.prefix, ": "),
+		Msg:       msg,
+	})
+	if err != nil {
+		// Line only includes plain strings
+		panic(fmt.Sprintf("Marshaling log line: %s", err))
+	}
+	return string(bs)
 }
 
 type UIFuncLogger struct {
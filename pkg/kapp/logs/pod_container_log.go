@@ -129,9 +129,10 @@ func (l PodContainerLog) obtainStream(ui ui.UI, linePrefix string, cancelCh chan
 		// since that appears to make GetLogs call return actual log stream.
 		if l.readyToGetLogs() {
 			logs := l.podsClient.GetLogs(l.pod.Name, &corev1.PodLogOptions{
-				Follow:    l.opts.Follow,
-				TailLines: l.opts.Lines,
-				Container: l.container,
+				Follow:       l.opts.Follow,
+				TailLines:    l.opts.Lines,
+				SinceSeconds: l.opts.SinceSeconds,
+				Container:    l.container,
 				// TODO other options
 			})
 
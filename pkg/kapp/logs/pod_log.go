@@ -17,7 +17,10 @@ type PodLogOpts struct {
 	Lines          *int64
 	ContainerNames []string
 	ContainerTag   bool
-	LinePrefix     string
+	// ColorTags colors tags so that logs from different pods could be told apart
+	ColorTags    bool
+	LinePrefix   string
+	SinceSeconds *int64
 }
 
 type PodLog struct {
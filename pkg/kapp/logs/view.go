@@ -5,8 +5,10 @@ package logs
 
 import (
 	"fmt"
+	"hash/fnv"
 	"sync"
 
+	"github.com/cppforlife/color"
 	"github.com/cppforlife/go-cli-ui/ui"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -68,7 +70,11 @@ func (v View) Show(cancelCh chan struct{}) error {
 			podsClient := v.coreClient.CoreV1().Pods(pod.Namespace)
 
 			tagFunc := func(cont corev1.Container) string {
-				return fmt.Sprintf("%s > %s", pod.Name, cont.Name)
+				tag := fmt.Sprintf("%s > %s", pod.Name, cont.Name)
+				if v.tailOpts.ColorTags {
+					return podTagColor(pod).Sprint(tag)
+				}
+				return tag
 			}
 
 			tailOpts := v.tailOpts
@@ -87,3 +93,24 @@ func (v View) Show(cancelCh chan struct{}) error {
 
 	return nil
 }
+
+var podTagColors = []*color.Color{
+	color.New(color.FgCyan),
+	color.New(color.FgGreen),
+	color.New(color.FgYellow),
+	color.New(color.FgBlue),
+	color.New(color.FgMagenta),
+	color.New(color.FgHiCyan),
+	color.New(color.FgHiGreen),
+	color.New(color.FgHiYellow),
+	color.New(color.FgHiBlue),
+	color.New(color.FgHiMagenta),
+}
+
+// podTagColor consistently picks same color for a pod
+// so that its logs stay recognizable in merged output
+func podTagColor(pod corev1.Pod) *color.Color {
+	hash := fnv.New32a()
+	hash.Write([]byte(pod.Namespace + "/" + pod.Name))
+	return podTagColors[hash.Sum32()%uint32(len(podTagColors))]
+}
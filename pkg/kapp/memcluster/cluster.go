@@ -0,0 +1,285 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package memcluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+// Cluster is an in-memory dynamic client used by benchmark command
+// and kapptest package so that diff/apply code paths run without API server.
+// It only implements operations used while applying changes.
+type Cluster struct {
+	lock    sync.Mutex
+	objs    map[schema.GroupVersionResource]map[string]*unstructured.Unstructured
+	lastRev int
+}
+
+var _ dynamic.Interface = &Cluster{}
+
+func NewCluster() *Cluster {
+	return &Cluster{objs: map[schema.GroupVersionResource]map[string]*unstructured.Unstructured{}}
+}
+
+func (c *Cluster) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return resourceClient{cluster: c, gvr: gvr}
+}
+
+type resourceClient struct {
+	cluster   *Cluster
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+var _ dynamic.NamespaceableResourceInterface = resourceClient{}
+
+func (c resourceClient) Namespace(namespace string) dynamic.ResourceInterface {
+	c.namespace = namespace
+	return c
+}
+
+func (c resourceClient) Create(_ context.Context, obj *unstructured.Unstructured,
+	_ metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+
+	if len(subresources) > 0 {
+		return nil, c.unsupportedErr("Creating subresources")
+	}
+
+	c.cluster.lock.Lock()
+	defer c.cluster.lock.Unlock()
+
+	obj = obj.DeepCopy()
+	rev := c.cluster.nextRev()
+
+	if len(obj.GetName()) == 0 && len(obj.GetGenerateName()) > 0 {
+		obj.SetName(obj.GetGenerateName() + strconv.Itoa(rev))
+	}
+	if _, found := c.objs()[c.key(obj.GetName())]; found {
+		return nil, errors.NewAlreadyExists(c.gvr.GroupResource(), obj.GetName())
+	}
+
+	obj.SetNamespace(c.namespace)
+	obj.SetUID(types.UID(fmt.Sprintf("memcluster-%d", rev)))
+	obj.SetResourceVersion(strconv.Itoa(rev))
+	obj.SetGeneration(1)
+	obj.SetCreationTimestamp(metav1.NewTime(time.Now()))
+
+	c.objs()[c.key(obj.GetName())] = obj
+
+	return obj.DeepCopy(), nil
+}
+
+func (c resourceClient) Update(_ context.Context, obj *unstructured.Unstructured,
+	_ metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+
+	if len(subresources) > 0 {
+		return nil, c.unsupportedErr("Updating subresources")
+	}
+
+	return c.update(obj, func(existing *unstructured.Unstructured) *unstructured.Unstructured {
+		return c.replace(existing, obj.DeepCopy(), true)
+	})
+}
+
+// UpdateStatus only changes status of existing object (generation is not incremented)
+func (c resourceClient) UpdateStatus(_ context.Context, obj *unstructured.Unstructured,
+	_ metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+
+	return c.update(obj, func(existing *unstructured.Unstructured) *unstructured.Unstructured {
+		updated := existing.DeepCopy()
+		status, found := obj.DeepCopy().Object["status"]
+		if found {
+			updated.Object["status"] = status
+		} else {
+			delete(updated.Object, "status")
+		}
+		return c.replace(existing, updated, false)
+	})
+}
+
+func (c resourceClient) update(obj *unstructured.Unstructured,
+	updateFunc func(*unstructured.Unstructured) *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+
+	c.cluster.lock.Lock()
+	defer c.cluster.lock.Unlock()
+
+	existing, found := c.objs()[c.key(obj.GetName())]
+	if !found {
+		return nil, errors.NewNotFound(c.gvr.GroupResource(), obj.GetName())
+	}
+	if len(obj.GetResourceVersion()) > 0 && obj.GetResourceVersion() != existing.GetResourceVersion() {
+		return nil, errors.NewConflict(c.gvr.GroupResource(), obj.GetName(),
+			fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+	}
+
+	return updateFunc(existing), nil
+}
+
+func (c resourceClient) Delete(_ context.Context, name string, _ metav1.DeleteOptions, subresources ...string) error {
+	if len(subresources) > 0 {
+		return c.unsupportedErr("Deleting subresources")
+	}
+
+	c.cluster.lock.Lock()
+	defer c.cluster.lock.Unlock()
+
+	if _, found := c.objs()[c.key(name)]; !found {
+		return errors.NewNotFound(c.gvr.GroupResource(), name)
+	}
+	delete(c.objs(), c.key(name))
+
+	return nil
+}
+
+func (c resourceClient) DeleteCollection(context.Context, metav1.DeleteOptions, metav1.ListOptions) error {
+	return c.unsupportedErr("Deleting collections")
+}
+
+func (c resourceClient) Get(_ context.Context, name string, _ metav1.GetOptions,
+	subresources ...string) (*unstructured.Unstructured, error) {
+
+	if len(subresources) > 0 {
+		return nil, c.unsupportedErr("Getting subresources")
+	}
+
+	c.cluster.lock.Lock()
+	defer c.cluster.lock.Unlock()
+
+	obj, found := c.objs()[c.key(name)]
+	if !found {
+		return nil, errors.NewNotFound(c.gvr.GroupResource(), name)
+	}
+
+	return obj.DeepCopy(), nil
+}
+
+func (c resourceClient) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	c.cluster.lock.Lock()
+	defer c.cluster.lock.Unlock()
+
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	list.SetResourceVersion(strconv.Itoa(c.cluster.lastRev))
+
+	for _, obj := range c.objs() {
+		if len(c.namespace) > 0 && obj.GetNamespace() != c.namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		list.Items = append(list.Items, *obj.DeepCopy())
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		return c.key(list.Items[i].GetName()) < c.key(list.Items[j].GetName())
+	})
+
+	return list, nil
+}
+
+func (c resourceClient) Watch(context.Context, metav1.ListOptions) (watch.Interface, error) {
+	return nil, c.unsupportedErr("Watching")
+}
+
+func (c resourceClient) Patch(_ context.Context, name string, pt types.PatchType, data []byte,
+	_ metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+
+	if len(subresources) > 0 {
+		return nil, c.unsupportedErr("Patching subresources")
+	}
+
+	c.cluster.lock.Lock()
+	defer c.cluster.lock.Unlock()
+
+	existing, found := c.objs()[c.key(name)]
+	if !found {
+		return nil, errors.NewNotFound(c.gvr.GroupResource(), name)
+	}
+
+	var patchedObj map[string]interface{}
+	var err error
+
+	switch pt {
+	case types.MergePatchType:
+		patchedObj, err = mergePatch(existing.DeepCopy().Object, data)
+	case types.JSONPatchType:
+		patchedObj, err = jsonPatch(existing.DeepCopy().Object, data)
+	default:
+		return nil, c.unsupportedErr(fmt.Sprintf("Patching with %s", pt))
+	}
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	return c.replace(existing, &unstructured.Unstructured{Object: patchedObj}, true), nil
+}
+
+func (c resourceClient) Apply(context.Context, string, *unstructured.Unstructured,
+	metav1.ApplyOptions, ...string) (*unstructured.Unstructured, error) {
+
+	return nil, c.unsupportedErr("Server-side applying")
+}
+
+func (c resourceClient) ApplyStatus(context.Context, string, *unstructured.Unstructured,
+	metav1.ApplyOptions) (*unstructured.Unstructured, error) {
+
+	return nil, c.unsupportedErr("Server-side applying")
+}
+
+// replace keeps server managed fields of existing object; expects lock to be held
+func (c resourceClient) replace(existing, obj *unstructured.Unstructured, incGeneration bool) *unstructured.Unstructured {
+	obj.SetNamespace(existing.GetNamespace())
+	obj.SetUID(existing.GetUID())
+	obj.SetCreationTimestamp(existing.GetCreationTimestamp())
+	obj.SetResourceVersion(strconv.Itoa(c.cluster.nextRev()))
+	obj.SetGeneration(existing.GetGeneration())
+	if incGeneration {
+		obj.SetGeneration(existing.GetGeneration() + 1)
+	}
+
+	c.objs()[c.key(obj.GetName())] = obj
+
+	return obj.DeepCopy()
+}
+
+// objs returns objects of client's resource; expects lock to be held
+func (c resourceClient) objs() map[string]*unstructured.Unstructured {
+	objs, found := c.cluster.objs[c.gvr]
+	if !found {
+		objs = map[string]*unstructured.Unstructured{}
+		c.cluster.objs[c.gvr] = objs
+	}
+	return objs
+}
+
+func (c resourceClient) key(name string) string { return c.namespace + "/" + name }
+
+func (c resourceClient) unsupportedErr(action string) error {
+	return errors.NewMethodNotSupported(c.gvr.GroupResource(), action)
+}
+
+// nextRev expects lock to be held
+func (c *Cluster) nextRev() int {
+	c.lastRev++
+	return c.lastRev
+}
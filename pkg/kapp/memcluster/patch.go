@@ -0,0 +1,153 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package memcluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// mergePatch applies JSON merge patch (RFC 7386)
+func mergePatch(obj map[string]interface{}, data []byte) (map[string]interface{}, error) {
+	var patch map[string]interface{}
+
+	err := json.Unmarshal(data, &patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergePatchMap(obj, patch), nil
+}
+
+func mergePatchMap(obj, patch map[string]interface{}) map[string]interface{} {
+	for key, patchVal := range patch {
+		if patchVal == nil {
+			delete(obj, key)
+			continue
+		}
+		patchValMap, ok := patchVal.(map[string]interface{})
+		if !ok {
+			obj[key] = patchVal
+			continue
+		}
+		objValMap, ok := obj[key].(map[string]interface{})
+		if !ok {
+			objValMap = map[string]interface{}{}
+		}
+		obj[key] = mergePatchMap(objValMap, patchValMap)
+	}
+	return obj
+}
+
+type jsonPatchOp struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+// jsonPatch applies JSON patch (RFC 6902); only add, remove and replace operations are supported
+func jsonPatch(obj map[string]interface{}, data []byte) (map[string]interface{}, error) {
+	var ops []jsonPatchOp
+
+	err := json.Unmarshal(data, &ops)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		if !strings.HasPrefix(op.Path, "/") {
+			return nil, fmt.Errorf("Expected patch path '%s' to start with '/'", op.Path)
+		}
+
+		var segments []string
+		for _, segment := range strings.Split(op.Path[1:], "/") {
+			segments = append(segments, strings.NewReplacer("~1", "/", "~0", "~").Replace(segment))
+		}
+
+		var result interface{}
+
+		result, err = jsonPatchVal(obj, segments, op)
+		if err != nil {
+			return nil, fmt.Errorf("Applying patch operation '%s' at '%s': %w", op.Op, op.Path, err)
+		}
+
+		obj = result.(map[string]interface{})
+	}
+
+	return obj, nil
+}
+
+func jsonPatchVal(val interface{}, segments []string, op jsonPatchOp) (interface{}, error) {
+	segment := segments[0]
+	last := len(segments) == 1
+
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		childVal, found := typedVal[segment]
+		if !last {
+			if !found {
+				return nil, fmt.Errorf("Expected key '%s' to exist", segment)
+			}
+			newVal, err := jsonPatchVal(childVal, segments[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			typedVal[segment] = newVal
+			return typedVal, nil
+		}
+
+		switch op.Op {
+		case "add":
+			typedVal[segment] = op.Value
+		case "replace":
+			if !found {
+				return nil, fmt.Errorf("Expected key '%s' to exist", segment)
+			}
+			typedVal[segment] = op.Value
+		case "remove":
+			if !found {
+				return nil, fmt.Errorf("Expected key '%s' to exist", segment)
+			}
+			delete(typedVal, segment)
+		default:
+			return nil, fmt.Errorf("Unsupported operation")
+		}
+		return typedVal, nil
+
+	case []interface{}:
+		if last && op.Op == "add" && segment == "-" {
+			return append(typedVal, op.Value), nil
+		}
+
+		idx, err := strconv.Atoi(segment)
+		if err != nil || idx < 0 || idx > len(typedVal) || (idx == len(typedVal) && (!last || op.Op != "add")) {
+			return nil, fmt.Errorf("Expected index '%s' to be within array bounds", segment)
+		}
+		if !last {
+			newVal, err := jsonPatchVal(typedVal[idx], segments[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			typedVal[idx] = newVal
+			return typedVal, nil
+		}
+
+		switch op.Op {
+		case "add":
+			typedVal = append(typedVal[:idx], append([]interface{}{op.Value}, typedVal[idx:]...)...)
+		case "replace":
+			typedVal[idx] = op.Value
+		case "remove":
+			typedVal = append(typedVal[:idx], typedVal[idx+1:]...)
+		default:
+			return nil, fmt.Errorf("Unsupported operation")
+		}
+		return typedVal, nil
+
+	default:
+		return nil, fmt.Errorf("Expected '%s' to refer to object or array", segment)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package memcluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPatch(t *testing.T) {
+	obj := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"kapp.k14s.io/app": "123"},
+		},
+		"spec": map[string]interface{}{
+			"items": []interface{}{"a", "c"},
+		},
+	}
+
+	patched, err := jsonPatch(obj, []byte(`[
+{"op": "remove", "path": "/metadata/labels/kapp.k14s.io~1app"},
+{"op": "add", "path": "/metadata/labels/kapp.k14s.io~1orphaned", "value": ""},
+{"op": "add", "path": "/spec/items/1", "value": "b"},
+{"op": "add", "path": "/spec/items/-", "value": "d"},
+{"op": "replace", "path": "/spec/items/0", "value": "z"}
+]`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"kapp.k14s.io/orphaned": ""},
+		},
+		"spec": map[string]interface{}{
+			"items": []interface{}{"z", "b", "c", "d"},
+		},
+	}, patched)
+
+	_, err = jsonPatch(patched, []byte(`[{"op": "replace", "path": "/spec/missing", "value": 1}]`))
+	require.EqualError(t, err, "Applying patch operation 'replace' at '/spec/missing': Expected key 'missing' to exist")
+
+	_, err = jsonPatch(patched, []byte(`[{"op": "remove", "path": "/spec/items/10"}]`))
+	require.EqualError(t, err, "Applying patch operation 'remove' at '/spec/items/10': Expected index '10' to be within array bounds")
+}
+
+func TestMergePatch(t *testing.T) {
+	patched, err := mergePatch(map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": 3, "paused": true},
+	}, []byte(`{"spec": {"replicas": 0, "paused": null}}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(0)}}, patched)
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package memcluster
+
+import (
+	"sync"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceTypes guesses resource types from kinds of resources
+// since Cluster does not provide API discovery
+type ResourceTypes struct {
+	lock  sync.Mutex
+	types map[schema.GroupVersionResource]ctlres.ResourceType
+}
+
+var _ ctlres.ResourceTypes = &ResourceTypes{}
+
+func NewResourceTypes() *ResourceTypes {
+	return &ResourceTypes{types: map[schema.GroupVersionResource]ctlres.ResourceType{}}
+}
+
+func (t *ResourceTypes) All(bool) ([]ctlres.ResourceType, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var result []ctlres.ResourceType
+	for _, resType := range t.types {
+		result = append(result, resType)
+	}
+	return result, nil
+}
+
+func (t *ResourceTypes) Find(res ctlres.Resource) (ctlres.ResourceType, error) {
+	gvk := res.GroupVersion().WithKind(res.Kind())
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	resType, found := t.types[gvr]
+	if !found {
+		resType = ctlres.ResourceType{
+			GroupVersionResource: gvr,
+			APIResource: metav1.APIResource{
+				Name:       gvr.Resource,
+				Namespaced: len(res.Namespace()) > 0,
+				Group:      gvk.Group,
+				Version:    gvk.Version,
+				Kind:       gvk.Kind,
+				Verbs:      []string{"create", "delete", "get", "list", "patch", "update"},
+			},
+		}
+		t.types[gvr] = resType
+	}
+
+	return resType, nil
+}
+
+func (t *ResourceTypes) CanIgnoreFailingGroupVersion(schema.GroupVersion) bool { return false }
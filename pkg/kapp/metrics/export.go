@@ -0,0 +1,100 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	textContentType = "text/plain; version=0.0.4"
+)
+
+// WriteTextfile atomically writes metrics to a file
+// (e.g. to be picked up by node exporter textfile collector)
+func (r *Recorder) WriteTextfile(path string) error {
+	var buf bytes.Buffer
+
+	err := r.WriteText(&buf)
+	if err != nil {
+		return err
+	}
+
+	// Collector may read file at any time hence write via rename
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("Creating metrics file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(buf.Bytes())
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Writing metrics file: %w", err)
+	}
+
+	err = os.Chmod(tmpFile.Name(), 0644)
+	if err != nil {
+		return fmt.Errorf("Writing metrics file: %w", err)
+	}
+
+	return os.Rename(tmpFile.Name(), path)
+}
+
+// Push replaces metrics in Pushgateway group identified by job and recorder labels
+func (r *Recorder) Push(client *http.Client, gatewayURL, job string) error {
+	var buf bytes.Buffer
+
+	err := r.WriteText(&buf)
+	if err != nil {
+		return err
+	}
+
+	pushURL := strings.TrimSuffix(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(job)
+
+	var keys []string
+	for k := range r.labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// Pushgateway does not accept empty path segments
+		if len(r.labels[k]) > 0 {
+			pushURL += "/" + k + "/" + url.PathEscape(r.labels[k])
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPut, pushURL, &buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", textContentType)
+
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Pushing metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Pushing metrics: Expected 2xx response status, but was '%s'", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+const (
+	PhasePrepare = "prepare"
+	PhaseDiff    = "diff"
+	PhaseApply   = "apply"
+)
+
+// Recorder collects metrics of a single deploy; all methods
+// are no-op on nil recorder so that callers do not need to check if metrics are enabled
+type Recorder struct {
+	labels map[string]string
+	now    func() time.Time
+
+	lock           sync.Mutex
+	phase          string
+	phaseStartedAt time.Time
+	startedAt      time.Time
+	finishedAt     time.Time
+	durations      map[string]time.Duration
+	plannedOps     map[string]int
+	appliedOps     map[string]int
+	waitTimeouts   int
+	failedChanges  int
+	preflightFails int
+	successful     bool
+}
+
+func NewRecorder(app, namespace string) *Recorder {
+	return &Recorder{
+		labels:     map[string]string{"app": app, "namespace": namespace},
+		now:        time.Now,
+		durations:  map[string]time.Duration{},
+		plannedOps: map[string]int{},
+		appliedOps: map[string]int{},
+	}
+}
+
+// StartPhase ends previously started phase (if any) and starts new one
+func (r *Recorder) StartPhase(phase string) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	if r.startedAt.IsZero() {
+		r.startedAt = now
+	}
+	r.endPhase(now)
+	r.phase = phase
+	r.phaseStartedAt = now
+}
+
+// ProgressFunc returns progress func that records
+// applied changes and wait timeouts before calling next func
+func (r *Recorder) ProgressFunc(next ctlcap.ProgressEventFunc) ctlcap.ProgressEventFunc {
+	if r == nil {
+		return next
+	}
+	return func(event ctlcap.ProgressEvent) {
+		r.record(event)
+		if next != nil {
+			next(event)
+		}
+	}
+}
+
+func (r *Recorder) record(event ctlcap.ProgressEvent) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch event.Type {
+	case ctlcap.ProgressEventTypeDiffComputed:
+		for op, num := range event.Ops {
+			r.plannedOps[op] = num
+		}
+	case ctlcap.ProgressEventTypeChangeApplied:
+		r.appliedOps[event.Op]++
+	case ctlcap.ProgressEventTypeChangeFailed:
+		r.failedChanges++
+	case ctlcap.ProgressEventTypeWaitTimeout:
+		r.waitTimeouts++
+	}
+}
+
+// Finish ends current phase and records deploy outcome
+func (r *Recorder) Finish(successful, preflightFailed bool) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	if r.startedAt.IsZero() {
+		r.startedAt = now
+	}
+	r.endPhase(now)
+	r.finishedAt = now
+	r.successful = successful
+	if preflightFailed {
+		r.preflightFails++
+	}
+}
+
+func (r *Recorder) endPhase(now time.Time) {
+	if len(r.phase) > 0 {
+		r.durations[r.phase] += now.Sub(r.phaseStartedAt)
+		r.phase = ""
+	}
+}
+
+// WriteText writes metrics in Prometheus text exposition format
+func (r *Recorder) WriteText(out io.Writer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var sb strings.Builder
+
+	boolVal := func(val bool) float64 {
+		if val {
+			return 1
+		}
+		return 0
+	}
+
+	r.writeMetric(&sb, "kapp_deploy_duration_seconds", "Duration of kapp deploy", nil,
+		r.finishedAt.Sub(r.startedAt).Seconds())
+	r.writeMetricByLabel(&sb, "kapp_deploy_phase_duration_seconds", "Duration of kapp deploy by phase", "phase",
+		durationsAsSeconds(r.durations))
+	r.writeMetricByLabel(&sb, "kapp_deploy_planned_changes", "Number of calculated changes by operation", "op",
+		intsAsFloats(r.plannedOps))
+	r.writeMetricByLabel(&sb, "kapp_deploy_applied_changes", "Number of applied changes by operation", "op",
+		intsAsFloats(r.appliedOps))
+	r.writeMetric(&sb, "kapp_deploy_failed_changes", "Number of changes that failed to apply", nil, float64(r.failedChanges))
+	r.writeMetric(&sb, "kapp_deploy_wait_timeouts", "Number of changes that timed out waiting", nil, float64(r.waitTimeouts))
+	r.writeMetric(&sb, "kapp_deploy_preflight_failures", "Number of failed preflight checks", nil, float64(r.preflightFails))
+	r.writeMetric(&sb, "kapp_deploy_successful", "Whether kapp deploy succeeded", nil, boolVal(r.successful))
+	r.writeMetric(&sb, "kapp_deploy_last_finished_timestamp_seconds", "Time when kapp deploy finished", nil,
+		float64(r.finishedAt.UnixNano())/float64(time.Second))
+
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+func (r *Recorder) writeMetric(sb *strings.Builder, name, help string, extraLabels map[string]string, val float64) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	r.writeSample(sb, name, extraLabels, val)
+}
+
+func (r *Recorder) writeMetricByLabel(sb *strings.Builder, name, help, labelName string, vals map[string]float64) {
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+
+	var keys []string
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		r.writeSample(sb, name, map[string]string{labelName: k}, vals[k])
+	}
+}
+
+func (r *Recorder) writeSample(sb *strings.Builder, name string, extraLabels map[string]string, val float64) {
+	labels := map[string]string{}
+	for k, v := range r.labels {
+		labels[k] = v
+	}
+	for k, v := range extraLabels {
+		labels[k] = v
+	}
+
+	var keys []string
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+
+	fmt.Fprintf(sb, "%s{%s} %g\n", name, strings.Join(pairs, ","), val)
+}
+
+func durationsAsSeconds(durations map[string]time.Duration) map[string]float64 {
+	result := map[string]float64{}
+	for k, v := range durations {
+		result[k] = v.Seconds()
+	}
+	return result
+}
+
+func intsAsFloats(vals map[string]int) map[string]float64 {
+	result := map[string]float64{}
+	for k, v := range vals {
+		result[k] = float64(v)
+	}
+	return result
+}
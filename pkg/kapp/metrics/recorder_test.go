@@ -0,0 +1,81 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/metrics"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := metrics.NewRecorder("app1", "ns1")
+
+	var forwarded int
+	progressFunc := recorder.ProgressFunc(func(ctlcap.ProgressEvent) { forwarded++ })
+
+	recorder.StartPhase(metrics.PhasePrepare)
+	recorder.StartPhase(metrics.PhaseDiff)
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeDiffComputed, Ops: map[string]int{"add": 2, "delete": 1}})
+	recorder.StartPhase(metrics.PhaseApply)
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeChangeApplied, Op: "add"})
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeChangeApplied, Op: "add"})
+	progressFunc(ctlcap.ProgressEvent{Type: ctlcap.ProgressEventTypeWaitTimeout, Op: "add"})
+	recorder.Finish(false, false)
+
+	require.Equal(t, 4, forwarded)
+
+	var sb strings.Builder
+	require.NoError(t, recorder.WriteText(&sb))
+
+	text := sb.String()
+	require.Contains(t, text, "# TYPE kapp_deploy_phase_duration_seconds gauge\n")
+	require.Contains(t, text, `kapp_deploy_phase_duration_seconds{app="app1",namespace="ns1",phase="apply"} `)
+	require.Contains(t, text, `kapp_deploy_planned_changes{app="app1",namespace="ns1",op="add"} 2`+"\n")
+	require.Contains(t, text, `kapp_deploy_planned_changes{app="app1",namespace="ns1",op="delete"} 1`+"\n")
+	require.Contains(t, text, `kapp_deploy_applied_changes{app="app1",namespace="ns1",op="add"} 2`+"\n")
+	require.Contains(t, text, `kapp_deploy_wait_timeouts{app="app1",namespace="ns1"} 1`+"\n")
+	require.Contains(t, text, `kapp_deploy_successful{app="app1",namespace="ns1"} 0`+"\n")
+
+	// Nil recorder is a no-op
+	var nilRecorder *metrics.Recorder
+	nilRecorder.StartPhase(metrics.PhasePrepare)
+	nilRecorder.Finish(true, false)
+	require.Nil(t, nilRecorder.ProgressFunc(nil))
+}
+
+func TestRecorderExport(t *testing.T) {
+	recorder := metrics.NewRecorder("app1", "ns1")
+	recorder.StartPhase(metrics.PhasePrepare)
+	recorder.Finish(false, true)
+
+	path := filepath.Join(t.TempDir(), "kapp.prom")
+	require.NoError(t, recorder.WriteTextfile(path))
+
+	bs, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(bs), `kapp_deploy_preflight_failures{app="app1",namespace="ns1"} 1`+"\n")
+
+	var pushedBody string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.Equal(t, "/metrics/job/kapp/app/app1/namespace/ns1", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		pushedBody = string(body)
+	}))
+	defer server.Close()
+
+	require.NoError(t, recorder.Push(nil, server.URL, "kapp"))
+	require.Equal(t, string(bs), pushedBody)
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+)
+
+// Event summarizes deploy at particular lifecycle step
+type Event struct {
+	Type      string    `json:"type"`
+	App       string    `json:"app"`
+	Namespace string    `json:"namespace"`
+	Time      time.Time `json:"time"`
+	Summary   string    `json:"summary"`
+	Changes   []Change  `json:"changes"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type Change struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Op        string `json:"op"`
+	WaitOp    string `json:"waitOp"`
+}
+
+func NewEvent(app, namespace, summary string, changes []*ctlcap.ClusterChange) Event {
+	event := Event{App: app, Namespace: namespace, Summary: summary}
+
+	for _, change := range changes {
+		if change.ApplyOp() == ctlcap.ClusterChangeApplyOpNoop && change.WaitOp() == ctlcap.ClusterChangeWaitOpNoop {
+			continue
+		}
+		res := change.Resource()
+		event.Changes = append(event.Changes, Change{
+			Namespace: res.Namespace(),
+			Name:      res.Name(),
+			Kind:      res.Kind(),
+			Op:        string(change.ApplyOp()),
+			WaitOp:    string(change.WaitOp()),
+		})
+	}
+
+	return event
+}
+
+// WithResult returns event of succeeded or failed type
+func (e Event) WithResult(err error) Event {
+	e.Type = ctlconf.NotificationEventSucceeded
+	if err != nil {
+		e.Type = ctlconf.NotificationEventFailed
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// ChangesTable formats changes as plain text table (available in templates)
+func (e Event) ChangesTable() string {
+	var buf bytes.Buffer
+
+	writer := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "Namespace\tName\tKind\tOp\tWait to")
+
+	for _, change := range e.Changes {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", change.Namespace, change.Name, change.Kind, change.Op, change.WaitOp)
+	}
+
+	writer.Flush()
+
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+func (e Event) text() string {
+	msg := fmt.Sprintf("kapp deploy of app '%s' (namespace: %s) %s: %s", e.App, e.Namespace, e.Type, e.Summary)
+	if len(e.Error) > 0 {
+		msg += "\nError: " + e.Error
+	}
+	if len(e.Changes) > 0 {
+		msg += "\n```\n" + e.ChangesTable() + "\n```"
+	}
+	return msg
+}
+
+// Notifier posts events to subscribed notification targets
+type Notifier struct {
+	configs []ctlconf.Notification
+	client  *http.Client
+}
+
+func NewNotifier(configs []ctlconf.Notification, client *http.Client) *Notifier {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Notifier{configs, client}
+}
+
+// Notify posts event to all subscribed targets; returned error
+// includes failures for all targets (others are still notified)
+func (n *Notifier) Notify(event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	var errs []error
+
+	for i, config := range n.configs {
+		if !config.IsSubscribed(event.Type) {
+			continue
+		}
+		err := n.post(config, event)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Notifying %d: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) post(config ctlconf.Notification, event Event) error {
+	url := config.URL
+	if len(config.URLFromEnv) > 0 {
+		url = os.Getenv(config.URLFromEnv)
+		if len(url) == 0 {
+			return fmt.Errorf("Expected environment variable '%s' to be set", config.URLFromEnv)
+		}
+	}
+
+	body, err := n.body(config, event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Building request: %s", redactURLErr(err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Posting notification: %s", redactURLErr(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Expected 2xx response status, but was '%s'", resp.Status)
+	}
+
+	return nil
+}
+
+func (n *Notifier) body(config ctlconf.Notification, event Event) ([]byte, error) {
+	var text string
+
+	if len(config.Template) > 0 {
+		tpl, err := template.New("notification").Parse(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing template: %w", err)
+		}
+
+		var buf bytes.Buffer
+
+		err = tpl.Execute(&buf, event)
+		if err != nil {
+			return nil, fmt.Errorf("Executing template: %w", err)
+		}
+		text = buf.String()
+	}
+
+	switch config.Format {
+	case ctlconf.NotificationFormatSlack:
+		if len(text) == 0 {
+			text = event.text()
+		}
+		return json.Marshal(map[string]string{"text": text})
+
+	default:
+		if len(text) > 0 {
+			return []byte(text), nil
+		}
+		return json.Marshal(event)
+	}
+}
+
+// redactURLErr drops URL from error since it may include embedded token
+func redactURLErr(err error) error {
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
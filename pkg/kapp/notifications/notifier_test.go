@@ -0,0 +1,66 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/notifications"
+)
+
+func TestNotifier(t *testing.T) {
+	received := map[string][]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received[r.URL.Path] = append(received[r.URL.Path], string(body))
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_SLACK_URL", server.URL+"/slack")
+
+	notifier := notifications.NewNotifier([]ctlconf.Notification{
+		{URLFromEnv: "TEST_SLACK_URL", Format: ctlconf.NotificationFormatSlack},
+		{URL: server.URL + "/webhook", Events: []string{ctlconf.NotificationEventFailed},
+			Template: `{"app": "{{.App}}", "error": "{{.Error}}"}`},
+		{URL: server.URL + "/broken", Events: []string{ctlconf.NotificationEventStarted}},
+	}, nil)
+
+	event := notifications.Event{
+		App:       "app1",
+		Namespace: "ns1",
+		Summary:   "1 create",
+		Changes:   []notifications.Change{{Namespace: "ns1", Name: "cm", Kind: "ConfigMap", Op: "create", WaitOp: "reconcile"}},
+	}
+
+	startedEvent := event
+	startedEvent.Type = ctlconf.NotificationEventStarted
+
+	err := notifier.Notify(startedEvent)
+	require.EqualError(t, err, "Notifying 2: Expected 2xx response status, but was '500 Internal Server Error'")
+
+	err = notifier.Notify(event.WithResult(errors.New("timed out")))
+	require.NoError(t, err)
+
+	require.Len(t, received["/slack"], 2)
+
+	var slackMsg map[string]string
+	require.NoError(t, json.Unmarshal([]byte(received["/slack"][1]), &slackMsg))
+	require.Equal(t, "kapp deploy of app 'app1' (namespace: ns1) failed: 1 create\nError: timed out\n"+
+		"```\nNamespace  Name  Kind       Op      Wait to\nns1        cm    ConfigMap  create  reconcile\n```", slackMsg["text"])
+
+	require.Equal(t, []string{`{"app": "app1", "error": "timed out"}`}, received["/webhook"])
+	require.Len(t, received["/broken"], 1)
+}
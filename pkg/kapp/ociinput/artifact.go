@@ -0,0 +1,398 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ociinput
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	registryUsernameEnvVar = "KAPP_REGISTRY_USERNAME"
+	registryPasswordEnvVar = "KAPP_REGISTRY_PASSWORD"
+
+	// imgpkg keeps bundle metadata (e.g. images lock) in this directory
+	imgpkgMetadataDir = ".imgpkg"
+
+	titleAnnKey = "org.opencontainers.image.title"
+)
+
+var (
+	manifestMediaTypes = []string{
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}
+	manifestAllowedExts  = []string{".json", ".yaml", ".yml"}
+	authParamRegexp      = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	gzipMagic            = []byte{0x1f, 0x8b}
+	maxManifestSizeBytes = int64(4 << 20)
+)
+
+type ArtifactOpts struct {
+	HTTPClient *http.Client
+	// PlainHTTP is used for local registries without TLS
+	PlainHTTP bool
+	// Keychain (zero value uses env vars and docker config) resolves registry credentials
+	Keychain Keychain
+}
+
+// Artifact reads manifests from imgpkg bundle or plain OCI artifact;
+// digests of manifest and layers are verified when fetched.
+type Artifact struct {
+	ref  Ref
+	opts ArtifactOpts
+
+	// authHeader is set once registry challenges request
+	authHeader string
+}
+
+func NewArtifact(ref Ref, opts ArtifactOpts) *Artifact {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{}
+	}
+	return &Artifact{ref: ref, opts: opts}
+}
+
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []descriptor `json:"layers"`
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+func (a *Artifact) Resources() ([]ctlres.Resource, error) {
+	manifestBs, digest, err := a.manifest()
+	if err != nil {
+		return nil, fmt.Errorf("Fetching OCI manifest '%s': %w", a.ref, err)
+	}
+
+	var m manifest
+
+	err = json.Unmarshal(manifestBs, &m)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling OCI manifest '%s': %w", a.ref, err)
+	}
+
+	if len(m.Layers) == 0 {
+		return nil, fmt.Errorf("Expected OCI manifest '%s' to have at least one layer "+
+			"(image indexes are not supported)", a.ref)
+	}
+
+	files := map[string][]byte{}
+
+	for _, layer := range m.Layers {
+		blob, err := a.blob(layer.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("Fetching OCI layer '%s': %w", layer.Digest, err)
+		}
+
+		if title, found := layer.Annotations[titleAnnKey]; found && !strings.Contains(layer.MediaType, "tar") {
+			// Plain artifacts (e.g. pushed by oras) keep one file per layer
+			files[title] = blob
+			continue
+		}
+
+		err = a.untar(blob, files)
+		if err != nil {
+			return nil, fmt.Errorf("Extracting OCI layer '%s': %w", layer.Digest, err)
+		}
+	}
+
+	var paths []string
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var resources []ctlres.Resource
+
+	for _, p := range paths {
+		fileRes := ctlres.NewFileResource(ctlres.NewBytesSource(files[p]))
+
+		rs, err := fileRes.Resources()
+		if err != nil {
+			return nil, fmt.Errorf("Parsing file '%s' of OCI artifact '%s': %w", p, a.ref, err)
+		}
+
+		for i, res := range rs {
+			res.SetOrigin(fmt.Sprintf("oci '%s@%s' file '%s' doc %d", a.ref.Registry+"/"+a.ref.Repository, digest, p, i+1))
+		}
+
+		resources = append(resources, rs...)
+	}
+
+	return resources, nil
+}
+
+func (a *Artifact) manifest() ([]byte, string, error) {
+	resp, err := a.get("/manifests/"+a.ref.Reference(), strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSizeBytes))
+	if err != nil {
+		return nil, "", err
+	}
+
+	digest := sha256Digest(bs)
+
+	if len(a.ref.Digest) > 0 && a.ref.Digest != digest {
+		return nil, "", fmt.Errorf("Expected manifest digest to be '%s', but was '%s'", a.ref.Digest, digest)
+	}
+	if headerDigest := resp.Header.Get("Docker-Content-Digest"); len(headerDigest) > 0 && headerDigest != digest {
+		return nil, "", fmt.Errorf("Expected manifest digest to match registry reported digest '%s', but was '%s'", headerDigest, digest)
+	}
+
+	return bs, digest, nil
+}
+
+func (a *Artifact) blob(digest string) ([]byte, error) {
+	resp, err := a.get("/blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if actualDigest := sha256Digest(bs); actualDigest != digest {
+		return nil, fmt.Errorf("Expected blob digest to be '%s', but was '%s'", digest, actualDigest)
+	}
+
+	return bs, nil
+}
+
+func (a *Artifact) untar(blob []byte, files map[string][]byte) error {
+	var reader io.Reader = bytes.NewReader(blob)
+
+	if bytes.HasPrefix(blob, gzipMagic) {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	tarReader := tar.NewReader(reader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if name == imgpkgMetadataDir || strings.HasPrefix(name, imgpkgMetadataDir+"/") {
+			continue
+		}
+		if !hasAllowedExt(name) {
+			continue
+		}
+
+		bs, err := io.ReadAll(tarReader)
+		if err != nil {
+			return err
+		}
+		files[name] = bs
+	}
+}
+
+func (a *Artifact) get(path, accept string) (*http.Response, error) {
+	resp, err := a.doGet(path, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && len(a.authHeader) == 0 {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		a.authHeader, err = a.authenticate(challenge)
+		if err != nil {
+			return nil, fmt.Errorf("Authenticating: %w", err)
+		}
+
+		resp, err = a.doGet(path, accept)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Expected response status 200, but was '%s'", resp.Status)
+	}
+
+	return resp, nil
+}
+
+func (a *Artifact) doGet(path, accept string) (*http.Response, error) {
+	scheme := "https"
+	if a.opts.PlainHTTP {
+		scheme = "http"
+	}
+
+	url := fmt.Sprintf("%s://%s/v2/%s%s", scheme, a.ref.apiRegistry(), a.ref.Repository, path)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept)
+	}
+	if len(a.authHeader) > 0 {
+		req.Header.Set("Authorization", a.authHeader)
+	}
+
+	return a.opts.HTTPClient.Do(req)
+}
+
+// authenticate returns Authorization header value for registry challenge
+func (a *Artifact) authenticate(challenge string) (string, error) {
+	creds, err := a.opts.Keychain.Resolve(a.ref.Registry)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case strings.HasPrefix(challenge, "Bearer "):
+		token, err := a.fetchToken(challenge, creds)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+
+	case strings.HasPrefix(challenge, "Basic "):
+		if len(creds.Username) == 0 {
+			return "", fmt.Errorf("Expected credentials for registry '%s' to be configured "+
+				"(via %s/%s env vars or docker config)", a.ref.Registry, registryUsernameEnvVar, registryPasswordEnvVar)
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(creds.Username+":"+creds.Password)), nil
+
+	default:
+		return "", fmt.Errorf("Expected Bearer or Basic authentication challenge, but was '%s'", challenge)
+	}
+}
+
+// fetchToken implements registry token authentication
+// (https://distribution.github.io/distribution/spec/auth/token/)
+// and its OAuth2 variant for identity tokens
+// (https://distribution.github.io/distribution/spec/auth/oauth/)
+func (a *Artifact) fetchToken(challenge string, creds Credentials) (string, error) {
+	params := map[string]string{}
+	for _, match := range authParamRegexp.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	scope := params["scope"]
+	if len(scope) == 0 {
+		scope = fmt.Sprintf("repository:%s:pull", a.ref.Repository)
+	}
+
+	var req *http.Request
+	var err error
+
+	if len(creds.IdentityToken) > 0 {
+		form := url.Values{}
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", creds.IdentityToken)
+		form.Set("service", params["service"])
+		form.Set("scope", scope)
+		form.Set("client_id", "kapp")
+
+		req, err = http.NewRequest(http.MethodPost, params["realm"], strings.NewReader(form.Encode()))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req, err = http.NewRequest(http.MethodGet, params["realm"], nil)
+		if err != nil {
+			return "", err
+		}
+
+		query := req.URL.Query()
+		if service, found := params["service"]; found {
+			query.Set("service", service)
+		}
+		query.Set("scope", scope)
+		req.URL.RawQuery = query.Encode()
+
+		if len(creds.Username) > 0 {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		}
+	}
+
+	resp, err := a.opts.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Expected token response status 200, but was '%s'", resp.Status)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&tokenResp)
+	if err != nil {
+		return "", err
+	}
+
+	if len(tokenResp.Token) > 0 {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
+
+func sha256Digest(bs []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(bs))
+}
+
+func hasAllowedExt(name string) bool {
+	ext := path.Ext(name)
+	for _, allowedExt := range manifestAllowedExts {
+		if ext == allowedExt {
+			return true
+		}
+	}
+	return false
+}
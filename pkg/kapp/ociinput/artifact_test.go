@@ -0,0 +1,161 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ociinput_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/ociinput"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, err := ociinput.ParseRef("oci://localhost:5000/app/config:v1")
+	require.NoError(t, err)
+	require.Equal(t, ociinput.Ref{Registry: "localhost:5000", Repository: "app/config", Tag: "v1"}, ref)
+
+	ref, err = ociinput.ParseRef("oci://nginx")
+	require.NoError(t, err)
+	require.Equal(t, ociinput.Ref{Registry: "index.docker.io", Repository: "library/nginx", Tag: "latest"}, ref)
+
+	ref, err = ociinput.ParseRef("oci://registry.example.com/app@sha256:abc")
+	require.NoError(t, err)
+	require.Equal(t, "sha256:abc", ref.Reference())
+
+	_, err = ociinput.ParseRef("oci://registry.example.com/app@md5:abc")
+	require.EqualError(t, err, "Expected OCI reference 'oci://registry.example.com/app@md5:abc' digest to be sha256")
+}
+
+func TestArtifactResources(t *testing.T) {
+	layer := tarGz(t, map[string]string{
+		"config/app.yml":         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n",
+		"config/README.md":       "not a manifest",
+		".imgpkg/images.yml":     "apiVersion: imgpkg.carvel.dev/v1alpha1\nkind: ImagesLock\n",
+		"config/other/svc.yaml":  "apiVersion: v1\nkind: Service\nmetadata:\n  name: svc\n",
+		"config/other/empty.yml": "",
+	})
+	layerDigest := digest(layer)
+
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion": 2, "mediaType": "application/vnd.oci.image.manifest.v1+json",
+"layers": [{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": "%s"}]}`, layerDigest))
+	manifestDigest := digest(manifest)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			require.Equal(t, "repository:app/config:pull", r.URL.Query().Get("scope"))
+			w.Write([]byte(`{"token": "secret"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/app/config/manifests/v1":
+			w.Header().Set("Docker-Content-Digest", manifestDigest)
+			w.Write(manifest)
+		case "/v2/app/config/blobs/" + layerDigest:
+			w.Write(layer)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	file := "oci://" + strings.TrimPrefix(server.URL, "http://") + "/app/config:v1"
+
+	ref, err := ociinput.ParseRef(file)
+	require.NoError(t, err)
+
+	rs, err := ociinput.NewArtifact(ref, ociinput.ArtifactOpts{PlainHTTP: true}).Resources()
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+
+	require.Equal(t, "app", rs[0].Name())
+	require.Equal(t, "svc", rs[1].Name())
+	require.Equal(t, fmt.Sprintf("oci '%s/app/config@%s' file 'config/app.yml' doc 1",
+		strings.TrimPrefix(server.URL, "http://"), manifestDigest), rs[0].Origin())
+
+	// Digest mismatch is detected
+	ref.Digest = "sha256:0000"
+	ref.Tag = ""
+
+	_, err = ociinput.NewArtifact(ref, ociinput.ArtifactOpts{PlainHTTP: true}).Resources()
+	require.Error(t, err)
+}
+
+func TestCosignVerifier(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake cosign binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	argsPath := filepath.Join(dir, "args")
+
+	// Fake cosign records its arguments and prints verified signatures
+	cosignPath := filepath.Join(dir, "cosign")
+	err := os.WriteFile(cosignPath, []byte(`#!/bin/sh
+echo "$@" > `+argsPath+`
+echo '[{"critical": {"image": {"docker-manifest-digest": "sha256:abc"}}}]'
+`), 0700)
+	require.NoError(t, err)
+
+	ref, err := ociinput.ParseRef("oci://registry.example.com/app/config:v1")
+	require.NoError(t, err)
+
+	verifier := ociinput.CosignVerifier{Binary: cosignPath, Key: "cosign.pub"}
+
+	digest, err := verifier.Verify(ref)
+	require.NoError(t, err)
+	require.Equal(t, "sha256:abc", digest)
+
+	args, err := os.ReadFile(argsPath)
+	require.NoError(t, err)
+	require.Equal(t, "verify --output json --key cosign.pub registry.example.com/app/config:v1\n", string(args))
+
+	// Verified digest has to match requested one
+	ref.Digest = "sha256:def"
+
+	_, err = verifier.Verify(ref)
+	require.EqualError(t, err, "Expected verified digest 'sha256:abc' to match OCI artifact 'registry.example.com/app/config:v1@sha256:def'")
+
+	_, err = ociinput.CosignVerifier{Binary: cosignPath}.Verify(ref)
+	require.EqualError(t, err, "Expected either cosign key or signing identity with OIDC issuer to be specified")
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, content := range files {
+		err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		require.NoError(t, err)
+		_, err = tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	return buf.Bytes()
+}
+
+func digest(bs []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(bs))
+}
@@ -0,0 +1,207 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ociinput
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	dockerConfigEnvVar = "DOCKER_CONFIG"
+	// Docker CLI keeps Docker Hub credentials under this legacy key
+	dockerHubConfigKey = "https://index.docker.io/v1/"
+	// Credential helpers return this username for identity (refresh) tokens
+	identityTokenUsername = "<token>"
+)
+
+// Credentials are empty when registry is accessed anonymously
+type Credentials struct {
+	Username string
+	Password string
+	// IdentityToken is exchanged for registry token via OAuth2 refresh token grant
+	IdentityToken string
+}
+
+func (c Credentials) IsEmpty() bool {
+	return len(c.Username) == 0 && len(c.Password) == 0 && len(c.IdentityToken) == 0
+}
+
+// Keychain resolves registry credentials the same way as Docker CLI
+// (and imgpkg): KAPP_REGISTRY_USERNAME/PASSWORD env vars take precedence,
+// otherwise docker config file ($DOCKER_CONFIG/config.json or ~/.docker/config.json)
+// is used, preferring credential helpers (credHelpers, credsStore) over auths.
+// (go-containerregistry is not used, hence its other keychains,
+// e.g. cloud provider specific ones, are not supported.)
+type Keychain struct {
+	// ConfigDir defaults to $DOCKER_CONFIG or ~/.docker
+	ConfigDir string
+}
+
+type dockerConfig struct {
+	Auths       map[string]dockerConfigAuth `json:"auths"`
+	CredHelpers map[string]string           `json:"credHelpers"`
+	CredsStore  string                      `json:"credsStore"`
+}
+
+type dockerConfigAuth struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+func (k Keychain) Resolve(registry string) (Credentials, error) {
+	if username := os.Getenv(registryUsernameEnvVar); len(username) > 0 {
+		return Credentials{Username: username, Password: os.Getenv(registryPasswordEnvVar)}, nil
+	}
+
+	config, found, err := k.config()
+	if err != nil || !found {
+		return Credentials{}, err
+	}
+
+	if helper, found := k.findHelper(config, registry); found {
+		return k.helperCredentials(helper, registry)
+	}
+
+	for key, auth := range config.Auths {
+		if normalizeRegistry(key) == normalizeRegistry(registry) {
+			return k.authCredentials(key, auth)
+		}
+	}
+
+	return Credentials{}, nil
+}
+
+func (k Keychain) config() (dockerConfig, bool, error) {
+	dir := k.ConfigDir
+	if len(dir) == 0 {
+		dir = os.Getenv(dockerConfigEnvVar)
+	}
+	if len(dir) == 0 {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			// Without home directory there is no default docker config
+			return dockerConfig{}, false, nil
+		}
+		dir = filepath.Join(homeDir, ".docker")
+	}
+
+	path := filepath.Join(dir, "config.json")
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return dockerConfig{}, false, nil
+		}
+		return dockerConfig{}, false, fmt.Errorf("Reading docker config '%s': %w", path, err)
+	}
+
+	var config dockerConfig
+
+	err = json.Unmarshal(bs, &config)
+	if err != nil {
+		return dockerConfig{}, false, fmt.Errorf("Unmarshaling docker config '%s': %w", path, err)
+	}
+
+	return config, true, nil
+}
+
+func (k Keychain) findHelper(config dockerConfig, registry string) (string, bool) {
+	for key, helper := range config.CredHelpers {
+		if normalizeRegistry(key) == normalizeRegistry(registry) && len(helper) > 0 {
+			return helper, true
+		}
+	}
+	if len(config.CredsStore) > 0 {
+		return config.CredsStore, true
+	}
+	return "", false
+}
+
+// helperCredentials implements 'get' command of docker credential helper protocol
+// (https://github.com/docker/docker-credential-helpers)
+func (k Keychain) helperCredentials(helper, registry string) (Credentials, error) {
+	binary := "docker-credential-" + helper
+	serverURL := registry
+	if normalizeRegistry(registry) == dockerHubRegistry {
+		serverURL = dockerHubConfigKey
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(binary, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		// Helpers report missing credentials via output instead of exit code
+		if strings.Contains(stdout.String()+stderr.String(), "credentials not found") {
+			return Credentials{}, nil
+		}
+		return Credentials{}, fmt.Errorf("Getting credentials for registry '%s' via '%s': %w (stderr: %s)",
+			registry, binary, err, strings.TrimSpace(stdout.String()+stderr.String()))
+	}
+
+	var helperResp struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+
+	err = json.Unmarshal(stdout.Bytes(), &helperResp)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("Unmarshaling credentials for registry '%s' from '%s': %w", registry, binary, err)
+	}
+
+	if helperResp.Username == identityTokenUsername {
+		return Credentials{IdentityToken: helperResp.Secret}, nil
+	}
+	return Credentials{Username: helperResp.Username, Password: helperResp.Secret}, nil
+}
+
+func (k Keychain) authCredentials(key string, auth dockerConfigAuth) (Credentials, error) {
+	if len(auth.IdentityToken) > 0 {
+		return Credentials{IdentityToken: auth.IdentityToken}, nil
+	}
+	if len(auth.Auth) == 0 {
+		return Credentials{Username: auth.Username, Password: auth.Password}, nil
+	}
+
+	bs, err := base64.StdEncoding.DecodeString(auth.Auth)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("Decoding docker config auth for registry '%s': %w", key, err)
+	}
+
+	pieces := strings.SplitN(string(bs), ":", 2)
+	if len(pieces) != 2 {
+		return Credentials{}, fmt.Errorf("Expected docker config auth for registry '%s' to be in 'username:password' format", key)
+	}
+
+	return Credentials{Username: pieces[0], Password: pieces[1]}, nil
+}
+
+// normalizeRegistry allows to match docker config keys which may
+// include scheme and path (e.g. https://index.docker.io/v1/)
+func normalizeRegistry(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	if idx := strings.Index(registry, "/"); idx >= 0 {
+		registry = registry[:idx]
+	}
+	switch registry {
+	case "docker.io", dockerHubAPIRegistry:
+		return dockerHubRegistry
+	}
+	return registry
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ociinput_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/ociinput"
+)
+
+func TestKeychainDockerConfigAuths(t *testing.T) {
+	t.Setenv("KAPP_REGISTRY_USERNAME", "")
+
+	dir := t.TempDir()
+	writeDockerConfig(t, dir, fmt.Sprintf(`{"auths": {
+  "https://index.docker.io/v1/": {"auth": "%s"},
+  "registry.example.com": {"username": "user", "password": "pass"},
+  "https://token.example.com/v2/": {"identitytoken": "refresh"}
+}}`, base64.StdEncoding.EncodeToString([]byte("hub-user:hub:pass"))))
+
+	keychain := ociinput.Keychain{ConfigDir: dir}
+
+	creds, err := keychain.Resolve("index.docker.io")
+	require.NoError(t, err)
+	require.Equal(t, ociinput.Credentials{Username: "hub-user", Password: "hub:pass"}, creds)
+
+	creds, err = keychain.Resolve("registry.example.com")
+	require.NoError(t, err)
+	require.Equal(t, ociinput.Credentials{Username: "user", Password: "pass"}, creds)
+
+	creds, err = keychain.Resolve("token.example.com")
+	require.NoError(t, err)
+	require.Equal(t, ociinput.Credentials{IdentityToken: "refresh"}, creds)
+
+	creds, err = keychain.Resolve("other.example.com")
+	require.NoError(t, err)
+	require.True(t, creds.IsEmpty())
+
+	// Env vars take precedence over docker config
+	t.Setenv("KAPP_REGISTRY_USERNAME", "env-user")
+	t.Setenv("KAPP_REGISTRY_PASSWORD", "env-pass")
+
+	creds, err = keychain.Resolve("registry.example.com")
+	require.NoError(t, err)
+	require.Equal(t, ociinput.Credentials{Username: "env-user", Password: "env-pass"}, creds)
+}
+
+func TestKeychainCredentialHelpers(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake credential helpers are shell scripts")
+	}
+
+	t.Setenv("KAPP_REGISTRY_USERNAME", "")
+
+	binDir := t.TempDir()
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// Fake helpers follow docker credential helper protocol
+	writeExecutable(t, filepath.Join(binDir, "docker-credential-registry"), `#!/bin/sh
+[ "$1" = "get" ] || exit 1
+read server
+echo "{\"ServerURL\": \"$server\", \"Username\": \"helper-user\", \"Secret\": \"$server\"}"
+`)
+	writeExecutable(t, filepath.Join(binDir, "docker-credential-store"), `#!/bin/sh
+read server
+if [ "$server" = "https://index.docker.io/v1/" ]; then
+  echo '{"Username": "<token>", "Secret": "hub-refresh"}'
+  exit 0
+fi
+echo "credentials not found in native keychain"
+exit 1
+`)
+
+	dir := t.TempDir()
+	writeDockerConfig(t, dir, `{
+  "auths": {"registry.example.com": {"username": "auths-user", "password": "auths-pass"}},
+  "credHelpers": {"registry.example.com": "registry"},
+  "credsStore": "store"
+}`)
+
+	keychain := ociinput.Keychain{ConfigDir: dir}
+
+	creds, err := keychain.Resolve("registry.example.com")
+	require.NoError(t, err)
+	require.Equal(t, ociinput.Credentials{Username: "helper-user", Password: "registry.example.com"}, creds)
+
+	creds, err = keychain.Resolve("index.docker.io")
+	require.NoError(t, err)
+	require.Equal(t, ociinput.Credentials{IdentityToken: "hub-refresh"}, creds)
+
+	creds, err = keychain.Resolve("other.example.com")
+	require.NoError(t, err)
+	require.True(t, creds.IsEmpty())
+
+	writeDockerConfig(t, dir, `{"credsStore": "missing"}`)
+
+	_, err = keychain.Resolve("other.example.com")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Getting credentials for registry 'other.example.com' via 'docker-credential-missing'")
+}
+
+func TestArtifactBasicAuthFromDockerConfig(t *testing.T) {
+	t.Setenv("KAPP_REGISTRY_USERNAME", "")
+
+	layer := tarGz(t, map[string]string{"app.yml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n"})
+
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion": 2, "mediaType": "application/vnd.oci.image.manifest.v1+json",
+"layers": [{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": "%s"}]}`, digest(layer)))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/blobs/"+digest(layer)) {
+			w.Write(layer)
+			return
+		}
+		w.Header().Set("Docker-Content-Digest", digest(manifest))
+		w.Write(manifest)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	ref, err := ociinput.ParseRef("oci://" + host + "/app/config:v1")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeDockerConfig(t, dir, `{"auths": {"`+host+`": {"username": "user", "password": "pass"}}}`)
+
+	rs, err := ociinput.NewArtifact(ref, ociinput.ArtifactOpts{
+		PlainHTTP: true,
+		Keychain:  ociinput.Keychain{ConfigDir: dir},
+	}).Resources()
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	_, err = ociinput.NewArtifact(ref, ociinput.ArtifactOpts{
+		PlainHTTP: true,
+		Keychain:  ociinput.Keychain{ConfigDir: t.TempDir()},
+	}).Resources()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected credentials for registry '"+host+"' to be configured")
+}
+
+func writeDockerConfig(t *testing.T, dir, content string) {
+	err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(content), 0600)
+	require.NoError(t, err)
+}
+
+func writeExecutable(t *testing.T, path, content string) {
+	err := os.WriteFile(path, []byte(content), 0700)
+	require.NoError(t, err)
+}
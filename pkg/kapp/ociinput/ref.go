@@ -0,0 +1,95 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ociinput
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	RefPrefix = "oci://"
+
+	dockerHubRegistry    = "index.docker.io"
+	dockerHubAPIRegistry = "registry-1.docker.io"
+	defaultTag           = "latest"
+)
+
+// Ref points to OCI artifact (e.g. oci://registry.example.com/app/config:v1)
+type Ref struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+func IsRef(file string) bool { return strings.HasPrefix(file, RefPrefix) }
+
+func ParseRef(file string) (Ref, error) {
+	val := strings.TrimPrefix(file, RefPrefix)
+
+	var ref Ref
+
+	if idx := strings.Index(val, "@"); idx >= 0 {
+		ref.Digest = val[idx+1:]
+		val = val[:idx]
+		if !strings.HasPrefix(ref.Digest, "sha256:") {
+			return Ref{}, fmt.Errorf("Expected OCI reference '%s' digest to be sha256", file)
+		}
+	}
+
+	// Tag separator cannot be confused with registry port separator
+	if idx := strings.LastIndex(val, ":"); idx > strings.LastIndex(val, "/") {
+		ref.Tag = val[idx+1:]
+		val = val[:idx]
+	}
+
+	pieces := strings.SplitN(val, "/", 2)
+	if len(pieces) == 2 && (strings.ContainsAny(pieces[0], ".:") || pieces[0] == "localhost") {
+		ref.Registry = pieces[0]
+		ref.Repository = pieces[1]
+	} else {
+		ref.Registry = dockerHubRegistry
+		ref.Repository = val
+		if !strings.Contains(val, "/") {
+			ref.Repository = "library/" + val
+		}
+	}
+
+	if len(ref.Repository) == 0 {
+		return Ref{}, fmt.Errorf("Expected OCI reference '%s' to include repository", file)
+	}
+
+	if len(ref.Tag) == 0 && len(ref.Digest) == 0 {
+		ref.Tag = defaultTag
+	}
+
+	return ref, nil
+}
+
+// Reference is tag or digest used to fetch manifest (digest is preferred)
+func (r Ref) Reference() string {
+	if len(r.Digest) > 0 {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+func (r Ref) apiRegistry() string {
+	if r.Registry == dockerHubRegistry {
+		return dockerHubAPIRegistry
+	}
+	return r.Registry
+}
+
+func (r Ref) String() string {
+	result := r.Registry + "/" + r.Repository
+	if len(r.Tag) > 0 {
+		result += ":" + r.Tag
+	}
+	if len(r.Digest) > 0 {
+		result += "@" + r.Digest
+	}
+	return result
+}
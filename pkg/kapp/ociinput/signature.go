@@ -0,0 +1,96 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package ociinput
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CosignVerifier verifies OCI artifact signatures via cosign binary
+// either against public key or keyless signing identity
+type CosignVerifier struct {
+	Binary string
+	Key    string
+
+	Identity   string
+	OIDCIssuer string
+
+	AllowInsecureRegistry bool
+}
+
+type cosignVerification struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// Verify returns digest of the artifact that has valid signature;
+// artifact should be fetched by this digest to avoid it being replaced after verification.
+func (v CosignVerifier) Verify(ref Ref) (string, error) {
+	args := []string{"verify", "--output", "json"}
+
+	switch {
+	case len(v.Key) > 0:
+		args = append(args, "--key", v.Key)
+	case len(v.Identity) > 0 && len(v.OIDCIssuer) > 0:
+		args = append(args, "--certificate-identity", v.Identity, "--certificate-oidc-issuer", v.OIDCIssuer)
+	default:
+		return "", fmt.Errorf("Expected either cosign key or signing identity with OIDC issuer to be specified")
+	}
+
+	if v.AllowInsecureRegistry {
+		args = append(args, "--allow-insecure-registry")
+	}
+
+	args = append(args, ref.String())
+
+	binary := v.Binary
+	if len(binary) == 0 {
+		binary = "cosign"
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("Verifying signature of OCI artifact '%s': %w (stderr: %s)",
+			ref, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var verifications []cosignVerification
+
+	err = json.Unmarshal(stdout.Bytes(), &verifications)
+	if err != nil {
+		return "", fmt.Errorf("Unmarshaling cosign verification output: %w", err)
+	}
+
+	var digest string
+
+	for _, verification := range verifications {
+		verifiedDigest := verification.Critical.Image.DockerManifestDigest
+		if len(digest) > 0 && digest != verifiedDigest {
+			return "", fmt.Errorf("Expected all signatures of OCI artifact '%s' to be for the same digest", ref)
+		}
+		digest = verifiedDigest
+	}
+
+	if len(digest) == 0 {
+		return "", fmt.Errorf("Expected at least one verified signature for OCI artifact '%s'", ref)
+	}
+	if len(ref.Digest) > 0 && ref.Digest != digest {
+		return "", fmt.Errorf("Expected verified digest '%s' to match OCI artifact '%s'", digest, ref)
+	}
+
+	return digest, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+// ExecCheck runs command with request JSON on stdin.
+// Zero exit code allows changes; exit code 1 denies them with
+// output as reason; any other failure is reported as check error.
+type ExecCheck struct {
+	Name    string
+	Exec    ctlconf.PreflightRuleExec
+	Timeout time.Duration
+}
+
+var _ Check = ExecCheck{}
+
+const execCheckDeniedExitCode = 1
+
+func (c ExecCheck) Description() string {
+	return fmt.Sprintf("preflight check '%s' (exec: %s)", c.Name, c.Exec.Command)
+}
+
+func (c ExecCheck) Run(req CheckRequest) (CheckResponse, error) {
+	reqBs, err := json.Marshal(req)
+	if err != nil {
+		return CheckResponse{}, fmt.Errorf("Serializing request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, c.Exec.Command, c.Exec.Args...)
+	cmd.Stdin = bytes.NewReader(reqBs)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+
+	for k, v := range c.Exec.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	err = cmd.Run()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return CheckResponse{}, fmt.Errorf("Timed out after %s", c.Timeout)
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == execCheckDeniedExitCode {
+			msg := strings.TrimSpace(strings.TrimSpace(stdout.String()) + "\n" + strings.TrimSpace(stderr.String()))
+			return CheckResponse{Allowed: false, Message: msg}, nil
+		}
+
+		return CheckResponse{}, fmt.Errorf("%w (stderr: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return CheckResponse{Allowed: true}, nil
+}
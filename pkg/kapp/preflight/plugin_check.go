@@ -0,0 +1,47 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"fmt"
+	"time"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlplugin "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/experimental/plugin"
+)
+
+// PluginCheck calls preflight check capability of a plugin
+type PluginCheck struct {
+	Name    string
+	Plugin  ctlconf.PluginRef
+	Timeout time.Duration
+}
+
+var _ Check = PluginCheck{}
+
+func (c PluginCheck) Description() string {
+	return fmt.Sprintf("preflight check '%s' (plugin: %s)", c.Name, c.Plugin.Command)
+}
+
+func (c PluginCheck) Run(req CheckRequest) (CheckResponse, error) {
+	client, err := ctlplugin.Start(ctlplugin.Config{
+		Command: c.Plugin.Command,
+		Args:    c.Plugin.Args,
+		Env:     c.Plugin.Env,
+		Timeout: c.Timeout,
+	})
+	if err != nil {
+		return CheckResponse{}, err
+	}
+	defer client.Close()
+
+	var resp CheckResponse
+
+	err = client.Call(ctlplugin.CapabilityPreflightCheck, req, &resp)
+	if err != nil {
+		return CheckResponse{}, err
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,230 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Check decides whether changes are allowed to be applied
+type Check interface {
+	Description() string
+	Run(CheckRequest) (CheckResponse, error)
+}
+
+type registeredCheck struct {
+	Check
+	rule ctlconf.PreflightRule
+}
+
+// Registry runs checks declared via preflightRules in kapp config.
+// Checks only receive masked resources (all diff mask rules are applied
+// regardless of --diff-mask since requests leave kapp).
+type Registry struct {
+	checks    []registeredCheck
+	maskRules []ctlconf.DiffMaskRule
+	ui        ui.UI
+	logger    logger.Logger
+}
+
+func NewRegistry(rules []ctlconf.PreflightRule, maskRules []ctlconf.DiffMaskRule, ui ui.UI, logger logger.Logger) *Registry {
+	registry := &Registry{maskRules: maskRules, ui: ui, logger: logger.NewPrefixed("PreflightRegistry")}
+	for _, rule := range rules {
+		registry.Register(rule, newCheck(rule))
+	}
+	return registry
+}
+
+func newCheck(rule ctlconf.PreflightRule) Check {
+	switch {
+	case rule.Exec != nil:
+		return ExecCheck{rule.Name, *rule.Exec, rule.TimeoutDuration()}
+	case rule.Plugin != nil:
+		return PluginCheck{rule.Name, *rule.Plugin, rule.TimeoutDuration()}
+	default:
+		return WebhookCheck{rule.Name, *rule.Webhook, rule.TimeoutDuration()}
+	}
+}
+
+// Register adds check that receives changes matched by rule
+// (e.g. to provide checks that are not declared in config)
+func (r *Registry) Register(rule ctlconf.PreflightRule, check Check) {
+	r.checks = append(r.checks, registeredCheck{check, rule})
+}
+
+// Run runs checks in order they were registered
+// and returns error describing all denied checks (graph is optional)
+func (r *Registry) Run(app App, changes []Change, graph *ctldgraph.ChangeGraph) error {
+	var denials []string
+	var graphDoc *ctldgraph.GraphDocument
+
+	for _, check := range r.checks {
+		matchedChanges := r.matchedChanges(check.rule, changes)
+		if len(matchedChanges) == 0 {
+			r.logger.Debug("skipping %s: no matched changes", check.Description())
+			continue
+		}
+
+		r.ui.PrintLinef("Running %s", check.Description())
+
+		// Serialized once since graph is the same for all checks
+		if graph != nil && graphDoc == nil {
+			doc, err := graph.Document()
+			if err != nil {
+				return fmt.Errorf("Serializing change graph: %w", err)
+			}
+			graphDoc = &doc
+		}
+
+		req, err := r.request(check, app, matchedChanges, graphDoc)
+		if err != nil {
+			return fmt.Errorf("Preparing request for %s: %w", check.Description(), err)
+		}
+
+		resp, err := check.Run(req)
+		if err != nil {
+			return fmt.Errorf("Running %s: %w", check.Description(), err)
+		}
+
+		if !resp.Allowed {
+			denials = append(denials, resp.describe(check.rule.Name))
+		}
+	}
+
+	if len(denials) > 0 {
+		return fmt.Errorf("Preflight checks denied changes:\n- %s", strings.Join(denials, "\n- "))
+	}
+
+	return nil
+}
+
+func (r *Registry) request(check registeredCheck, app App, matchedChanges []Change,
+	graphDoc *ctldgraph.GraphDocument) (CheckRequest, error) {
+
+	req := CheckRequest{
+		APIVersion: CheckRequestAPIVersion,
+		Kind:       CheckRequestKind,
+		Check:      check.rule.Name,
+		App:        app,
+		Config:     check.rule.Config,
+	}
+
+	matchedKeys := map[string]struct{}{}
+
+	for _, change := range matchedChanges {
+		maskedChange, err := change.masked(r.maskRules)
+		if err != nil {
+			return CheckRequest{}, err
+		}
+		req.Changes = append(req.Changes, maskedChange)
+		matchedKeys[ctlres.NewUniqueResourceKey(change.res).String()] = struct{}{}
+	}
+
+	if graphDoc != nil {
+		matchedGraphDoc, err := r.matchedGraphDocument(*graphDoc, matchedKeys)
+		if err != nil {
+			return CheckRequest{}, err
+		}
+		req.Graph = &matchedGraphDoc
+	}
+
+	return req, nil
+}
+
+// matchedGraphDocument returns graph document that only includes matched changes
+// (with masked resources); dependencies on other changes are not included
+func (r *Registry) matchedGraphDocument(doc ctldgraph.GraphDocument,
+	matchedKeys map[string]struct{}) (ctldgraph.GraphDocument, error) {
+
+	result := ctldgraph.GraphDocument{
+		APIVersion: doc.APIVersion,
+		Kind:       doc.Kind,
+		Changes:    []ctldgraph.ChangeDocument{},
+		Order:      [][]int{},
+	}
+
+	newIdxs := map[int]int{}
+
+	for i, changeDoc := range doc.Changes {
+		res := ctlres.NewResourceUnstructured(unstructured.Unstructured{Object: changeDoc.Resource}, ctlres.ResourceType{})
+		if _, found := matchedKeys[ctlres.NewUniqueResourceKey(res).String()]; !found {
+			continue
+		}
+
+		maskedRes, err := ctldiff.NewMaskedResource(res, r.maskRules).Resource()
+		if err != nil {
+			return ctldgraph.GraphDocument{}, fmt.Errorf("Masking resource '%s': %w", res.Description(), err)
+		}
+		changeDoc.Resource = maskedRes.DeepCopyRaw()
+
+		newIdxs[i] = len(result.Changes)
+		result.Changes = append(result.Changes, changeDoc)
+	}
+
+	for i, changeDoc := range result.Changes {
+		var waitingFor []int
+		for _, idx := range changeDoc.WaitingFor {
+			if newIdx, found := newIdxs[idx]; found {
+				waitingFor = append(waitingFor, newIdx)
+			}
+		}
+		result.Changes[i].WaitingFor = waitingFor
+	}
+
+	for _, section := range doc.Order {
+		var newSection []int
+		for _, idx := range section {
+			if newIdx, found := newIdxs[idx]; found {
+				newSection = append(newSection, newIdx)
+			}
+		}
+		if len(newSection) > 0 {
+			result.Order = append(result.Order, newSection)
+		}
+	}
+
+	for _, idx := range doc.Blocked {
+		if newIdx, found := newIdxs[idx]; found {
+			result.Blocked = append(result.Blocked, newIdx)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Registry) matchedChanges(rule ctlconf.PreflightRule, changes []Change) []Change {
+	if len(rule.ResourceMatchers) == 0 {
+		return changes
+	}
+
+	matcher := ctlres.AnyMatcher{Matchers: ctlconf.ResourceMatchers(rule.ResourceMatchers).AsResourceMatchers()}
+
+	var result []Change
+	for _, change := range changes {
+		if matcher.Matches(change.res) {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+func (r CheckResponse) describe(checkName string) string {
+	msg := fmt.Sprintf("Check '%s' denied changes", checkName)
+	if len(r.Message) > 0 {
+		msg += ": " + r.Message
+	}
+	for _, violation := range r.Violations {
+		msg += "\n  - " + violation
+	}
+	return msg
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestRegistryExecCheck(t *testing.T) {
+	changes := []Change{newTestChange(t, "ConfigMap", "cm1"), newTestChange(t, "Secret", "secret1")}
+
+	run := func(script string) error {
+		rule := ctlconf.PreflightRule{
+			Name: "naming",
+			Exec: &ctlconf.PreflightRuleExec{Command: "sh", Args: []string{"-c", script}},
+		}
+		return NewRegistry([]ctlconf.PreflightRule{rule}, nil, ui.NewNoopUI(), logger.NewNoopLogger()).
+			Run(App{Name: "app1", Namespace: "ns1"}, changes, nil)
+	}
+
+	require.NoError(t, run(`grep -q '"kind":"PreflightCheckRequest"'`))
+
+	err := run(`echo "name is not allowed"; exit 1`)
+	require.EqualError(t, err, "Preflight checks denied changes:\n- Check 'naming' denied changes: name is not allowed")
+
+	err = run(`exit 2`)
+	require.ErrorContains(t, err, "Running preflight check 'naming' (exec: sh): exit status 2")
+
+	rule := ctlconf.PreflightRule{
+		Name: "slow",
+		Exec: &ctlconf.PreflightRuleExec{Command: "sleep", Args: []string{"5"}},
+	}
+	registry := &Registry{ui: ui.NewNoopUI(), logger: logger.NewNoopLogger()}
+	registry.Register(rule, ExecCheck{rule.Name, *rule.Exec, 10 * time.Millisecond})
+
+	err = registry.Run(App{}, changes, nil)
+	require.EqualError(t, err, "Running preflight check 'slow' (exec: sleep): Timed out after 10ms")
+}
+
+func TestRegistryWebhookCheck(t *testing.T) {
+	var requests []CheckRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CheckRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		require.NoError(t, json.NewEncoder(w).Encode(CheckResponse{
+			Allowed: false, Message: "quota exceeded", Violations: []string{"too many secrets"}}))
+	}))
+	defer server.Close()
+
+	rule := ctlconf.PreflightRule{
+		Name:             "quota",
+		ResourceMatchers: []ctlconf.ResourceMatcher{{APIVersionKindMatcher: &ctlconf.APIVersionKindMatcher{APIVersion: "v1", Kind: "Secret"}}},
+		Webhook:          &ctlconf.PreflightRuleWebhook{URL: server.URL},
+		Config:           map[string]interface{}{"max": float64(1)},
+	}
+
+	maskRules := []ctlconf.DiffMaskRule{{
+		Path:             ctlres.NewPathFromStrings([]string{"data"}),
+		ResourceMatchers: rule.ResourceMatchers,
+	}}
+
+	registry := NewRegistry([]ctlconf.PreflightRule{rule}, maskRules, ui.NewNoopUI(), logger.NewNoopLogger())
+
+	secretChange := newTestChange(t, "Secret", "secret1")
+	secretChange.Op = ChangeOpUpdate
+	secretChange.existingRes = newTestChange(t, "Secret", "secret1").res
+	secretChange.Existing = secretChange.existingRes.DeepCopyRaw()
+	configMapChange := newTestChange(t, "ConfigMap", "cm1")
+
+	// Graph includes all changes, while only matched ones are sent
+	graph, err := ctldgraph.NewChangeGraphFromResources([]ctlres.Resource{configMapChange.res, secretChange.res},
+		nil, nil, nil, logger.NewNoopLogger())
+	require.NoError(t, err)
+
+	err = registry.Run(App{Name: "app1", Namespace: "ns1"}, []Change{configMapChange, secretChange}, graph)
+	require.EqualError(t, err, "Preflight checks denied changes:\n- Check 'quota' denied changes: quota exceeded\n  - too many secrets")
+
+	require.Len(t, requests, 1)
+	require.Equal(t, "quota", requests[0].Check)
+	require.Equal(t, App{Name: "app1", Namespace: "ns1"}, requests[0].App)
+	require.Equal(t, map[string]interface{}{"max": float64(1)}, requests[0].Config)
+	require.Len(t, requests[0].Changes, 1)
+	require.Equal(t, ChangeOpUpdate, requests[0].Changes[0].Op)
+	require.Equal(t, "secret1", requests[0].Changes[0].Resource["metadata"].(map[string]interface{})["name"])
+
+	// Values matched by diff mask rules are not sent
+	maskedData := map[string]interface{}{"password": "<-- value not shown (#1)"}
+	require.Equal(t, maskedData, requests[0].Changes[0].Resource["data"])
+	require.Equal(t, maskedData, requests[0].Changes[0].Existing["data"])
+
+	require.Len(t, requests[0].Graph.Changes, 1)
+	require.Equal(t, "secret1", requests[0].Graph.Changes[0].Resource["metadata"].(map[string]interface{})["name"])
+	require.Equal(t, maskedData, requests[0].Graph.Changes[0].Resource["data"])
+	require.Equal(t, [][]int{{0}}, requests[0].Graph.Order)
+
+	// Check is skipped when there are no matched changes
+	err = registry.Run(App{}, []Change{newTestChange(t, "ConfigMap", "cm1")}, nil)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+}
+
+func newTestChange(t *testing.T, kind, name string) Change {
+	res, err := ctlres.NewResourceFromBytes([]byte("apiVersion: v1\nkind: " + kind + "\nmetadata:\n  name: " + name +
+		"\ndata:\n  password: c2VjcmV0\n"))
+	require.NoError(t, err)
+	return Change{Op: ChangeOpCreate, Resource: res.DeepCopyRaw(), res: res}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"fmt"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctldgraph "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diffgraph"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// Documents exchanged with external checks; fields are only added
+// (never changed) within same apiVersion so that checks keep working.
+const (
+	CheckRequestAPIVersion = "kapp.k14s.io/v1alpha1"
+	CheckRequestKind       = "PreflightCheckRequest"
+
+	ChangeOpCreate = "create"
+	ChangeOpUpdate = "update"
+	ChangeOpDelete = "delete"
+)
+
+// CheckRequest is sent to external check (as JSON)
+type CheckRequest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Check is the name of preflight rule
+	Check string `json:"check"`
+	App   App    `json:"app"`
+	// Config is passed through from preflight rule
+	Config map[string]interface{} `json:"config,omitempty"`
+
+	// Changes are listed in order they were calculated (without noop changes).
+	// Values matched by diff mask rules (e.g. Secret data) are masked.
+	Changes []Change `json:"changes"`
+	// Graph includes matched changes (masked the same way) and order they are applied in
+	Graph *ctldgraph.GraphDocument `json:"graph,omitempty"`
+}
+
+type App struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type Change struct {
+	// Op is one of create, update, delete
+	Op string `json:"op"`
+	// Resource is the resource as it will be applied (or deleted)
+	Resource map[string]interface{} `json:"resource"`
+	// Existing is the resource as it is on the cluster (not set for create)
+	Existing map[string]interface{} `json:"existing,omitempty"`
+
+	res         ctlres.Resource
+	existingRes ctlres.Resource
+}
+
+// CheckResponse is expected from webhook checks (as JSON)
+type CheckResponse struct {
+	Allowed bool   `json:"allowed"`
+	Message string `json:"message,omitempty"`
+	// Violations (optional) list individual reasons for denying changes
+	Violations []string `json:"violations,omitempty"`
+}
+
+func NewChanges(clusterChanges []*ctlcap.ClusterChange) []Change {
+	var result []Change
+
+	for _, clusterChange := range clusterChanges {
+		var op string
+
+		switch clusterChange.ApplyOp() {
+		case ctlcap.ClusterChangeApplyOpAdd:
+			op = ChangeOpCreate
+		case ctlcap.ClusterChangeApplyOpUpdate:
+			op = ChangeOpUpdate
+		case ctlcap.ClusterChangeApplyOpDelete:
+			op = ChangeOpDelete
+		default:
+			continue
+		}
+
+		res := clusterChange.Resource()
+		change := Change{Op: op, Resource: res.DeepCopyRaw(), res: res}
+
+		if op != ChangeOpCreate && clusterChange.ClusterOriginalResource() != nil {
+			change.existingRes = clusterChange.ClusterOriginalResource()
+			change.Existing = change.existingRes.DeepCopyRaw()
+		}
+
+		result = append(result, change)
+	}
+
+	return result
+}
+
+// masked returns copy of change with values matched by rules masked
+func (c Change) masked(rules []ctlconf.DiffMaskRule) (Change, error) {
+	maskedRes, err := ctldiff.NewMaskedResource(c.res, rules).Resource()
+	if err != nil {
+		return Change{}, fmt.Errorf("Masking resource '%s': %w", c.res.Description(), err)
+	}
+	c.Resource = maskedRes.DeepCopyRaw()
+
+	if c.existingRes != nil {
+		maskedRes, err := ctldiff.NewMaskedResource(c.existingRes, rules).Resource()
+		if err != nil {
+			return Change{}, fmt.Errorf("Masking resource '%s': %w", c.existingRes.Description(), err)
+		}
+		c.Existing = maskedRes.DeepCopyRaw()
+	}
+
+	return c, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+const (
+	// Response is only used to decide outcome, hence it is expected to be small
+	webhookCheckMaxResponseSize = 1024 * 1024
+)
+
+// WebhookCheck posts request JSON to URL and expects CheckResponse JSON back
+type WebhookCheck struct {
+	Name    string
+	Webhook ctlconf.PreflightRuleWebhook
+	Timeout time.Duration
+}
+
+var _ Check = WebhookCheck{}
+
+func (c WebhookCheck) Description() string {
+	return fmt.Sprintf("preflight check '%s' (webhook: %s)", c.Name, c.Webhook.URL)
+}
+
+func (c WebhookCheck) Run(req CheckRequest) (CheckResponse, error) {
+	reqBs, err := json.Marshal(req)
+	if err != nil {
+		return CheckResponse{}, fmt.Errorf("Serializing request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, c.Webhook.URL, bytes.NewReader(reqBs))
+	if err != nil {
+		return CheckResponse{}, fmt.Errorf("Building request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range c.Webhook.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	client := http.Client{Timeout: c.Timeout}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return CheckResponse{}, fmt.Errorf("Posting request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBs, err := io.ReadAll(io.LimitReader(resp.Body, webhookCheckMaxResponseSize))
+	if err != nil {
+		return CheckResponse{}, fmt.Errorf("Reading response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return CheckResponse{}, fmt.Errorf("Expected 2xx status code but got %d: %s", resp.StatusCode, bytes.TrimSpace(respBs))
+	}
+
+	var result CheckResponse
+
+	err = json.Unmarshal(respBs, &result)
+	if err != nil {
+		return CheckResponse{}, fmt.Errorf("Deserializing response: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"time"
+)
+
+const (
+	adaptivePageSizeMin    = 50
+	adaptivePageSizeMax    = 5000
+	adaptivePageSizeTarget = time.Second
+)
+
+// adaptivePageSize grows page size while pages are returned quickly
+// and shrinks it for slow pages (e.g. large resources or busy API server)
+type adaptivePageSize struct {
+	size int64
+}
+
+// newAdaptivePageSize starts with page size that would fit expected number of items
+func newAdaptivePageSize(expectedItems int) *adaptivePageSize {
+	return &adaptivePageSize{size: clampPageSize(int64(expectedItems) * 2)}
+}
+
+func (s *adaptivePageSize) Size() int64 { return s.size }
+
+// Observe adjusts page size based on how long it took to retrieve last page
+func (s *adaptivePageSize) Observe(duration time.Duration) {
+	switch {
+	case duration < adaptivePageSizeTarget/2:
+		s.size = clampPageSize(s.size * 2)
+	case duration > adaptivePageSizeTarget*2:
+		s.size = clampPageSize(s.size / 2)
+	}
+}
+
+func clampPageSize(size int64) int64 {
+	switch {
+	case size < adaptivePageSizeMin:
+		return adaptivePageSizeMin
+	case size > adaptivePageSizeMax:
+		return adaptivePageSizeMax
+	default:
+		return size
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptivePageSize(t *testing.T) {
+	require.Equal(t, int64(adaptivePageSizeMin), newAdaptivePageSize(1).Size())
+	require.Equal(t, int64(adaptivePageSizeMax), newAdaptivePageSize(100000).Size())
+
+	pageSize := newAdaptivePageSize(100)
+	require.Equal(t, int64(200), pageSize.Size())
+
+	pageSize.Observe(100 * time.Millisecond)
+	require.Equal(t, int64(400), pageSize.Size())
+
+	pageSize.Observe(time.Second)
+	require.Equal(t, int64(400), pageSize.Size())
+
+	pageSize.Observe(5 * time.Second)
+	require.Equal(t, int64(200), pageSize.Size())
+
+	for i := 0; i < 10; i++ {
+		pageSize.Observe(5 * time.Second)
+	}
+	require.Equal(t, int64(adaptivePageSizeMin), pageSize.Size())
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DiscoveryCache keeps discovered API resources on disk (one file per cluster)
+// so that subsequent kapp invocations do not have to discover them again
+type DiscoveryCache struct {
+	dir string
+	ttl time.Duration
+
+	nowFunc func() time.Time
+}
+
+type discoveryCacheFile struct {
+	Cluster   string                    `json:"cluster"`
+	FetchedAt time.Time                 `json:"fetchedAt"`
+	Resources []*metav1.APIResourceList `json:"resources"`
+}
+
+func NewDiscoveryCache(dir string, ttl time.Duration) *DiscoveryCache {
+	return &DiscoveryCache{dir: dir, ttl: ttl, nowFunc: time.Now}
+}
+
+// Load returns cached resources unless they are missing, expired or unreadable
+func (c *DiscoveryCache) Load(cluster string) ([]*metav1.APIResourceList, bool) {
+	bs, err := os.ReadFile(c.path(cluster))
+	if err != nil {
+		return nil, false
+	}
+
+	var file discoveryCacheFile
+
+	err = json.Unmarshal(bs, &file)
+	if err != nil || file.Cluster != cluster {
+		return nil, false
+	}
+	if c.nowFunc().After(file.FetchedAt.Add(c.ttl)) {
+		return nil, false
+	}
+
+	return file.Resources, true
+}
+
+// Save replaces cached resources; file is replaced atomically
+// since multiple kapp invocations may target the same cluster
+func (c *DiscoveryCache) Save(cluster string, resources []*metav1.APIResourceList) error {
+	bs, err := json.Marshal(discoveryCacheFile{Cluster: cluster, FetchedAt: c.nowFunc(), Resources: resources})
+	if err != nil {
+		return fmt.Errorf("Marshaling discovery cache: %w", err)
+	}
+
+	err = os.MkdirAll(c.dir, 0700)
+	if err != nil {
+		return fmt.Errorf("Creating discovery cache directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(c.dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("Creating discovery cache file: %w", err)
+	}
+
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(bs)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("Writing discovery cache file: %w", err)
+	}
+
+	err = os.Rename(tmpFile.Name(), c.path(cluster))
+	if err != nil {
+		return fmt.Errorf("Writing discovery cache file: %w", err)
+	}
+
+	return nil
+}
+
+// Invalidate removes cached resources (e.g. when they are known to be outdated)
+func (c *DiscoveryCache) Invalidate(cluster string) {
+	_ = os.Remove(c.path(cluster))
+}
+
+func (c *DiscoveryCache) path(cluster string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(cluster))))
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiscoveryCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cache := NewDiscoveryCache(t.TempDir(), time.Minute)
+	cache.nowFunc = func() time.Time { return now }
+
+	_, found := cache.Load("https://cluster1")
+	require.False(t, found)
+
+	resources := []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"}},
+	}}
+
+	require.NoError(t, cache.Save("https://cluster1", resources))
+
+	cachedResources, found := cache.Load("https://cluster1")
+	require.True(t, found)
+	require.Equal(t, resources, cachedResources)
+
+	_, found = cache.Load("https://cluster2")
+	require.False(t, found, "Expected cache to be scoped to cluster")
+
+	now = now.Add(2 * time.Minute)
+	_, found = cache.Load("https://cluster1")
+	require.False(t, found, "Expected cache to expire")
+
+	require.NoError(t, cache.Save("https://cluster1", resources))
+	cache.Invalidate("https://cluster1")
+
+	_, found = cache.Load("https://cluster1")
+	require.False(t, found)
+}
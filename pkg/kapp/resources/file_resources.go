@@ -20,20 +20,33 @@ type FileResource struct {
 	fileSrc FileSource
 }
 
-// NewFileResources inspects file and returns a slice of FileResource objects. If file is "-", a FileResource for STDIN
-// is returned. If it is prefixed with either http:// or https://, a FileResource that supports an HTTP transport is
+type FileResourcesOpts struct {
+	HTTP HTTPFileSourceOpts
+	// WrapSource allows to transform file contents before parsing (e.g. decrypt)
+	WrapSource func(FileSource) FileSource
+}
+
+// NewFileResources inspects file and returns a slice of FileResource objects. If file is "-" (or other stream,
+// e.g. "/dev/fd/3", optionally labeled, e.g. "helm=-"), a FileResource for the stream is returned. If it is prefixed with either http:// or https://, a FileResource that supports an HTTP transport is
 // returned. If file is a directory, one FileResource object is returned for each file in the directory with an allowed
 // extension (.json, .yml, .yaml). If file is not a directory, a FileResource object is returned for that one file. If
 // fsys is nil, NewFileResources uses the OS's file system. Otherwise, it uses the passed in file system.
 func NewFileResources(fsys fs.FS, file string) ([]FileResource, error) {
+	return NewFileResourcesWithOpts(fsys, file, FileResourcesOpts{})
+}
+
+// NewFileResourcesWithOpts is same as NewFileResources, but allows to configure
+// how remote files are fetched (e.g. authentication, retries)
+func NewFileResourcesWithOpts(fsys fs.FS, file string, opts FileResourcesOpts) ([]FileResource, error) {
 	var fileRs []FileResource
 
 	switch {
-	case file == "-":
-		fileRs = append(fileRs, NewFileResource(NewStdinSource()))
+	case IsStreamFile(file):
+		path, label, _ := ParseStreamFile(file)
+		fileRs = append(fileRs, NewFileResource(opts.wrap(NewStreamSource(path, label))))
 
 	case strings.HasPrefix(file, "http://") || strings.HasPrefix(file, "https://"):
-		fileRs = append(fileRs, NewFileResource(NewHTTPFileSource(file)))
+		fileRs = append(fileRs, NewFileResource(opts.wrap(NewHTTPFileSourceWithOpts(file, opts.HTTP))))
 
 	default:
 		dir, err := isDir(fsys, file)
@@ -69,39 +82,50 @@ func NewFileResources(fsys fs.FS, file string) ([]FileResource, error) {
 			sort.Strings(paths)
 
 			for _, path := range paths {
-				fileRs = append(fileRs, NewFileResource(NewLocalFileSource(fsys, path)))
+				fileRs = append(fileRs, NewFileResource(opts.wrap(NewLocalFileSource(fsys, path))))
 			}
 		} else {
-			fileRs = append(fileRs, NewFileResource(NewLocalFileSource(fsys, file)))
+			fileRs = append(fileRs, NewFileResource(opts.wrap(NewLocalFileSource(fsys, file))))
 		}
 	}
 
 	return fileRs, nil
 }
 
+func (o FileResourcesOpts) wrap(src FileSource) FileSource {
+	if o.WrapSource == nil {
+		return src
+	}
+	return o.WrapSource(src)
+}
+
 func NewFileResource(fileSrc FileSource) FileResource { return FileResource{fileSrc} }
 
 func (r FileResource) Description() string { return r.fileSrc.Description() }
 
+// Resources decodes documents as they are read (instead of reading
+// whole file first) to keep memory bounded for large inputs
 func (r FileResource) Resources() ([]Resource, error) {
-	docs, err := NewYAMLFile(r.fileSrc).Docs()
-	if err != nil {
-		return nil, err
-	}
-
 	var resources []Resource
+	var docIdx int
+
+	err := NewYAMLFile(r.fileSrc).EachDoc(func(doc []byte) error {
+		docIdx++
 
-	for i, doc := range docs {
 		rs, err := NewResourcesFromBytes(doc)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("Parsing %s doc %d: %w", r.fileSrc.Description(), docIdx, err)
 		}
 
 		for _, res := range rs {
-			res.SetOrigin(fmt.Sprintf("%s doc %d", r.fileSrc.Description(), i+1))
+			res.SetOrigin(fmt.Sprintf("%s doc %d", r.fileSrc.Description(), docIdx))
 		}
 
 		resources = append(resources, rs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return resources, nil
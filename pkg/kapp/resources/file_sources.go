@@ -4,11 +4,20 @@
 package resources
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"io/fs"
 	"net/http"
 	"os"
+	"strings"
+	"time"
+)
+
+const (
+	httpFileSourcePinPrefix = "#sha256="
 )
 
 type FileSource interface {
@@ -16,6 +25,13 @@ type FileSource interface {
 	Bytes() ([]byte, error)
 }
 
+// ReaderFileSource is implemented by sources that could be read
+// incrementally instead of being read into memory at once
+type ReaderFileSource interface {
+	FileSource
+	Open() (io.ReadCloser, error)
+}
+
 type BytesSource struct {
 	bytes []byte
 }
@@ -61,35 +77,190 @@ func (s LocalFileSource) Bytes() ([]byte, error) {
 	}
 }
 
+var _ ReaderFileSource = LocalFileSource{}
+
+func (s LocalFileSource) Open() (io.ReadCloser, error) {
+	if s.fsys == nil {
+		return os.Open(s.path)
+	}
+	return s.fsys.Open(s.path)
+}
+
+type HTTPFileSourceOpts struct {
+	// Headers are added to each request (e.g. custom authentication)
+	Headers     map[string]string
+	BearerToken string
+	TLSConfig   *tls.Config
+
+	// Retries of failed requests (network errors, 429 and 5xx statuses)
+	Retries      int
+	RetryBackoff time.Duration
+}
+
 type HTTPFileSource struct {
 	url    string
 	Client *http.Client
+
+	opts HTTPFileSourceOpts
+	// sha256 is an optional pin specified via URL fragment (e.g. '#sha256=...')
+	sha256 string
 }
 
 var _ FileSource = HTTPFileSource{}
 
-func NewHTTPFileSource(path string) HTTPFileSource { return HTTPFileSource{path, &http.Client{}} }
+func NewHTTPFileSource(path string) HTTPFileSource {
+	return NewHTTPFileSourceWithOpts(path, HTTPFileSourceOpts{})
+}
+
+func NewHTTPFileSourceWithOpts(path string, opts HTTPFileSourceOpts) HTTPFileSource {
+	var pin string
+	if idx := strings.LastIndex(path, httpFileSourcePinPrefix); idx >= 0 {
+		pin = strings.ToLower(path[idx+len(httpFileSourcePinPrefix):])
+		path = path[:idx]
+	}
+
+	client := &http.Client{}
+	if opts.TLSConfig != nil {
+		client.Transport = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: opts.TLSConfig}
+	}
+
+	return HTTPFileSource{url: path, Client: client, opts: opts, sha256: pin}
+}
 
 func (s HTTPFileSource) Description() string {
 	return fmt.Sprintf("HTTP URL '%s'", s.url)
 }
 
 func (s HTTPFileSource) Bytes() ([]byte, error) {
-	resp, err := s.Client.Get(s.url)
+	var result []byte
+
+	err := s.retry(func() (bool, error) {
+		var retryable bool
+		var err error
+		result, retryable, err = s.fetch()
+		return retryable, err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("Requesting URL '%s': %w", s.url, err)
+		return nil, err
 	}
 
-	defer resp.Body.Close()
+	if len(s.sha256) > 0 {
+		actual := fmt.Sprintf("%x", sha256.Sum256(result))
+		if actual != s.sha256 {
+			return nil, fmt.Errorf("Expected URL '%s' content to have sha256 '%s', but was '%s'", s.url, s.sha256, actual)
+		}
+	}
 
-	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return nil, fmt.Errorf("Requesting URL '%s': %s", s.url, resp.Status)
+	return result, nil
+}
+
+var _ ReaderFileSource = HTTPFileSource{}
+
+// Open returns response body to be read incrementally. Pinned content
+// is read into memory first since it has to be verified before it's used.
+// Unlike requests, reading of the body is not retried.
+func (s HTTPFileSource) Open() (io.ReadCloser, error) {
+	if len(s.sha256) > 0 {
+		result, err := s.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(result)), nil
+	}
+
+	var body io.ReadCloser
+
+	err := s.retry(func() (bool, error) {
+		resp, retryable, err := s.request()
+		if err == nil {
+			body = resp.Body
+		}
+		return retryable, err
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return httpFileSourceBody{s.url, body}, nil
+}
+
+func (s HTTPFileSource) retry(doFunc func() (bool, error)) error {
+	backoff := s.opts.RetryBackoff
+	if backoff == 0 {
+		backoff = time.Second
+	}
+
+	var err error
+	var retryable bool
+
+	for i := 0; i <= s.opts.Retries; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		retryable, err = doFunc()
+		if err == nil || !retryable {
+			break
+		}
+	}
+
+	return err
+}
+
+func (s HTTPFileSource) fetch() ([]byte, bool, error) {
+	resp, retryable, err := s.request()
+	if err != nil {
+		return nil, retryable, err
+	}
+
+	defer resp.Body.Close()
+
 	result, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("Reading URL '%s': %w", s.url, err)
+		return nil, true, fmt.Errorf("Reading URL '%s': %w", s.url, err)
 	}
 
-	return result, nil
+	return result, false, nil
+}
+
+// request returns response with successful status; its body has to be closed
+func (s HTTPFileSource) request() (*http.Response, bool, error) {
+	req, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("Requesting URL '%s': %w", s.url, err)
+	}
+
+	for k, v := range s.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	if len(s.opts.BearerToken) > 0 {
+		req.Header.Set("Authorization", "Bearer "+s.opts.BearerToken)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("Requesting URL '%s': %w", s.url, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retryable, fmt.Errorf("Requesting URL '%s': %s", s.url, resp.Status)
+	}
+
+	return resp, false, nil
+}
+
+// httpFileSourceBody adds URL to errors returned while reading body
+type httpFileSourceBody struct {
+	url string
+	io.ReadCloser
+}
+
+func (b httpFileSourceBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("Reading URL '%s': %w", b.url, err)
+	}
+	return n, err
 }
@@ -5,10 +5,12 @@ package resources_test
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
@@ -70,6 +72,97 @@ func TestHTTPFileSources(t *testing.T) {
 	require.EqualError(t, err, fmt.Sprintf("Requesting URL '%s': %s", url, status))
 }
 
+func TestHTTPFileSourcesWithOpts(t *testing.T) {
+	url := "https://example.com/some/path"
+	bodySHA256 := fmt.Sprintf("%x", sha256.Sum256([]byte("OK")))
+	requests := 0
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		// Pin is not sent to the server
+		require.Equal(t, url, req.URL.String())
+		require.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		require.Equal(t, "value", req.Header.Get("X-Custom"))
+
+		if requests == 1 {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable", Header: make(http.Header)}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`OK`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	opts := ctlres.HTTPFileSourceOpts{
+		Headers:      map[string]string{"X-Custom": "value"},
+		BearerToken:  "token",
+		Retries:      1,
+		RetryBackoff: time.Millisecond,
+	}
+
+	fileSource := ctlres.NewHTTPFileSourceWithOpts(url+"#sha256="+bodySHA256, opts)
+	fileSource.Client = client
+	body, err := fileSource.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, []byte("OK"), body)
+	require.Equal(t, 2, requests)
+	require.Equal(t, fmt.Sprintf("HTTP URL '%s'", url), fileSource.Description())
+
+	// Mismatched pin
+	fileSource = ctlres.NewHTTPFileSourceWithOpts(url+"#sha256=0000", opts)
+	fileSource.Client = client
+	_, err = fileSource.Bytes()
+	require.EqualError(t, err, fmt.Sprintf("Expected URL '%s' content to have sha256 '0000', but was '%s'", url, bodySHA256))
+
+	// Client errors are not retried
+	requests = 0
+	client = NewTestClient(func(req *http.Request) *http.Response {
+		requests++
+		return &http.Response{StatusCode: http.StatusForbidden, Status: "403 Forbidden", Header: make(http.Header)}
+	})
+
+	fileSource = ctlres.NewHTTPFileSourceWithOpts(url, opts)
+	fileSource.Client = client
+	_, err = fileSource.Bytes()
+	require.EqualError(t, err, fmt.Sprintf("Requesting URL '%s': 403 Forbidden", url))
+	require.Equal(t, 1, requests)
+}
+
+func TestHTTPFileSourcesStreamed(t *testing.T) {
+	url := "https://example.com/release.yml"
+	reader, writer := io.Pipe()
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{StatusCode: http.StatusOK, Body: reader, Header: make(http.Header)}
+	})
+
+	go func() {
+		// Documents are written one by one to show that they are not read all at once
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(writer, "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm%d\n", i)
+		}
+		writer.CloseWithError(fmt.Errorf("connection reset"))
+	}()
+
+	fileSource := ctlres.NewHTTPFileSource(url)
+	fileSource.Client = client
+
+	var names []string
+
+	err := ctlres.NewYAMLFile(fileSource).EachDoc(func(doc []byte) error {
+		rs, err := ctlres.NewResourcesFromBytes(doc)
+		require.NoError(t, err)
+		for _, res := range rs {
+			names = append(names, res.Name())
+		}
+		return nil
+	})
+	require.EqualError(t, err, fmt.Sprintf("Reading URL '%s': connection reset", url))
+	// Last document is not known to be complete when reading fails
+	require.Equal(t, []string{"cm0", "cm1"}, names)
+}
+
 // NewTestClient returns *http.Client with Transport replaced to avoid making real calls
 func NewTestClient(fn RoundTripFunc) *http.Client {
 	return &http.Client{
@@ -4,6 +4,7 @@
 package resources
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
@@ -26,6 +27,12 @@ func NewIdentifiedResources(coreClient kubernetes.Interface, resourceTypes Resou
 		fallbackAllowedNamespaces, logger.NewPrefixed("IdentifiedResources")}
 }
 
+// WithResources returns copy that uses given resources (e.g. ones served from watches)
+func (r IdentifiedResources) WithResources(resources Resources) IdentifiedResources {
+	r.resources = resources
+	return r
+}
+
 func (r IdentifiedResources) Create(resource Resource) (Resource, error) {
 	defer r.logger.DebugFunc(fmt.Sprintf("Create(%s)", resource.Description())).Finish()
 
@@ -77,6 +84,63 @@ func (r IdentifiedResources) Patch(resource Resource, patchType types.PatchType,
 	return r.resources.Patch(resource, patchType, data)
 }
 
+// Adopt adds labels to existing resource and marks it as created by kapp
+// (so that it's no longer considered transient). Rest of the resource is not modified.
+func (r IdentifiedResources) Adopt(resource Resource, labels map[string]string) (Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("Adopt(%s)", resource.Description())).Finish()
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+			"annotations": map[string]string{
+				kappIdentityAnnKey: NewIdentityAnnotation(resource).v1Value(),
+			},
+		},
+	}
+
+	patchBs, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("Marshaling adoption patch: %w", err)
+	}
+
+	resource, err = r.resources.Patch(resource, types.MergePatchType, patchBs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = NewIdentityAnnotation(resource).RemoveMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+// Disown removes labels from existing resource and unmarks it as created by kapp.
+// Rest of the resource is not modified.
+func (r IdentifiedResources) Disown(resource Resource, labelKeys []string) (Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("Disown(%s)", resource.Description())).Finish()
+
+	labels := map[string]interface{}{}
+	for _, key := range labelKeys {
+		labels[key] = nil
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      labels,
+			"annotations": map[string]interface{}{kappIdentityAnnKey: nil},
+		},
+	}
+
+	patchBs, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("Marshaling disownment patch: %w", err)
+	}
+
+	return r.resources.Patch(resource, types.MergePatchType, patchBs)
+}
+
 func (r IdentifiedResources) Delete(resource Resource) error {
 	defer r.logger.DebugFunc(fmt.Sprintf("Delete(%s)", resource.Description())).Finish()
 	return r.resources.Delete(resource)
@@ -102,3 +166,8 @@ func (r IdentifiedResources) Exists(resource Resource, existsOpts ExistsOpts) (R
 	defer r.logger.DebugFunc(fmt.Sprintf("Exists(%s)", resource.Description())).Finish()
 	return r.resources.Exists(resource, existsOpts)
 }
+
+func (r IdentifiedResources) ExistsMany(resources []Resource) ([]Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("ExistsMany(%d)", len(resources))).Finish()
+	return r.resources.ExistsMany(resources)
+}
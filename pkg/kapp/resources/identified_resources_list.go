@@ -14,7 +14,10 @@ import (
 type IdentifiedResourcesListOpts struct {
 	IgnoreCachedResTypes bool
 	GKsScope             []schema.GroupKind
-	ResourceNamespaces   []string
+	// Limits versions listed for each group kind (e.g. to versions
+	// used by the app) since listing each version returns same resources
+	GVsScope           []schema.GroupVersion
+	ResourceNamespaces []string
 }
 
 func (r IdentifiedResources) List(labelSelector labels.Selector, resRefs []ResourceRef, opts IdentifiedResourcesListOpts) ([]Resource, error) {
@@ -46,6 +49,10 @@ func (r IdentifiedResources) List(labelSelector labels.Selector, resRefs []Resou
 		resTypes = MatchingAnyGK(resTypes, opts.GKsScope)
 	}
 
+	if len(opts.GVsScope) > 0 {
+		resTypes = MatchingGVsOfGKs(resTypes, opts.GVsScope)
+	}
+
 	if len(resRefs) > 0 {
 		resTypes = MatchingAny(resTypes, resRefs)
 	}
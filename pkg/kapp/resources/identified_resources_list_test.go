@@ -60,7 +60,8 @@ func (r *FakeResources) Delete(ctlres.Resource) error { return nil }
 func (r *FakeResources) Exists(ctlres.Resource, ctlres.ExistsOpts) (ctlres.Resource, bool, error) {
 	return nil, true, nil
 }
-func (r *FakeResources) Get(ctlres.Resource) (ctlres.Resource, error) { return nil, nil }
+func (r *FakeResources) ExistsMany([]ctlres.Resource) ([]ctlres.Resource, error) { return nil, nil }
+func (r *FakeResources) Get(ctlres.Resource) (ctlres.Resource, error)            { return nil, nil }
 func (r *FakeResources) Patch(ctlres.Resource, types.PatchType, []byte) (ctlres.Resource, error) {
 	return nil, nil
 }
@@ -76,3 +77,81 @@ func (r *FakeResourceTypes) Find(ctlres.Resource) (ctlres.ResourceType, error) {
 	return ctlres.ResourceType{}, nil
 }
 func (r *FakeResourceTypes) CanIgnoreFailingGroupVersion(schema.GroupVersion) bool { return true }
+
+func TestIdentifiedResourcesListOwnedReturnsTransitivelyOwnedResources(t *testing.T) {
+	fakeResources := &FakeOwnedResources{}
+
+	identifiedResources := ctlres.NewIdentifiedResources(nil, &FakeResourceTypes{}, fakeResources, []string{}, logger.NewUILogger(ui.NewNoopUI()))
+
+	owner := ctlres.MustNewResourceFromBytes([]byte(`---
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+  uid: db-uid
+`))
+
+	resources, err := identifiedResources.ListOwned([]ctlres.Resource{owner}, ctlres.IdentifiedResourcesListOpts{})
+	require.NoError(t, err)
+
+	var names []string
+	for _, res := range resources {
+		names = append(names, res.Name())
+		require.True(t, res.Transient(), "Expected non-kapp resources to be transient")
+	}
+	require.ElementsMatch(t, []string{"db-statefulset", "db-pod"}, names)
+}
+
+type FakeOwnedResources struct {
+	FakeResources
+}
+
+func (r *FakeOwnedResources) All([]ctlres.ResourceType, ctlres.AllOpts) ([]ctlres.Resource, error) {
+	// Pod is listed before its owner to check that hierarchy order does not matter
+	resourcesBs := []string{`---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db-pod
+  uid: db-pod-uid
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: StatefulSet
+    name: db-statefulset
+    uid: db-statefulset-uid
+`, `---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db-statefulset
+  uid: db-statefulset-uid
+  ownerReferences:
+  - apiVersion: example.com/v1
+    kind: Database
+    name: db
+    uid: db-uid
+`, `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+  uid: unrelated-uid
+  ownerReferences:
+  - apiVersion: v1
+    kind: Secret
+    name: other
+    uid: other-uid
+`, `---
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+  uid: db-uid
+`}
+
+	var resources []ctlres.Resource
+	for _, bs := range resourcesBs {
+		resources = append(resources, ctlres.MustNewResourceFromBytes([]byte(bs)))
+	}
+	return resources, nil
+}
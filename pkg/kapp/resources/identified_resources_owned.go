@@ -0,0 +1,71 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ListOwned returns resources that are owned (directly or through other owned resources)
+// via ownerReferences by given resources even if they are not labeled
+// (e.g. resources created by controllers for custom resources).
+// Given resources are not included in the result.
+func (r IdentifiedResources) ListOwned(owners []Resource, opts IdentifiedResourcesListOpts) ([]Resource, error) {
+	defer r.logger.DebugFunc("ListOwned").Finish()
+
+	if len(owners) == 0 {
+		return nil, nil
+	}
+
+	allResources, err := r.List(labels.Everything(), nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerUIDs := map[string]struct{}{}
+
+	for _, res := range owners {
+		ownerUIDs[res.UID()] = struct{}{}
+	}
+
+	var candidates []Resource
+
+	for _, res := range allResources {
+		if _, found := ownerUIDs[res.UID()]; !found && len(res.OwnerRefs()) > 0 {
+			candidates = append(candidates, res)
+		}
+	}
+
+	var result []Resource
+
+	// Repeat until no new owned resources are found
+	// since candidates are not ordered by hierarchy
+	for found := true; found; {
+		found = false
+		var remaining []Resource
+
+		for _, res := range candidates {
+			if r.isOwnedByAny(res, ownerUIDs) {
+				result = append(result, res)
+				ownerUIDs[res.UID()] = struct{}{}
+				found = true
+			} else {
+				remaining = append(remaining, res)
+			}
+		}
+
+		candidates = remaining
+	}
+
+	return result, nil
+}
+
+func (IdentifiedResources) isOwnedByAny(res Resource, ownerUIDs map[string]struct{}) bool {
+	for _, ref := range res.OwnerRefs() {
+		if _, found := ownerUIDs[string(ref.UID)]; found {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
 	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/util"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -92,8 +93,12 @@ func (a *LabeledResources) All(listOpts IdentifiedResourcesListOpts) ([]Resource
 type AllAndMatchingOpts struct {
 	ExistingNonLabeledResourcesCheck            bool
 	ExistingNonLabeledResourcesCheckConcurrency int
-	SkipResourceOwnershipCheck                  bool
-	IsNewApp                                    bool
+	// Resources of the same type and namespace are listed instead of
+	// retrieved one by one when there are at least this many of them
+	// (0 means resources are always retrieved one by one)
+	ExistingNonLabeledResourcesCheckListThreshold int
+	SkipResourceOwnershipCheck                    bool
+	IsNewApp                                      bool
 
 	DisallowedResourcesByLabelKeys []string
 	LabelErrorResolutionFunc       func(string, string) string
@@ -124,8 +129,8 @@ func (a *LabeledResources) AllAndMatching(newResources []Resource, opts AllAndMa
 	var nonLabeledResources []Resource
 
 	if opts.ExistingNonLabeledResourcesCheck {
-		nonLabeledResources, err = a.findNonLabeledResources(
-			resources, newResources, opts.ExistingNonLabeledResourcesCheckConcurrency)
+		nonLabeledResources, err = a.findNonLabeledResources(resources, newResources,
+			opts.ExistingNonLabeledResourcesCheckConcurrency, opts.ExistingNonLabeledResourcesCheckListThreshold)
 		if err != nil {
 			return nil, err
 		}
@@ -232,7 +237,9 @@ func (a *LabeledResources) checkDisallowedLabels(resources []Resource, disallowe
 	return nil
 }
 
-func (a *LabeledResources) findNonLabeledResources(labeledResources, newResources []Resource, concurrency int) ([]Resource, error) {
+func (a *LabeledResources) findNonLabeledResources(labeledResources, newResources []Resource,
+	concurrency, listThreshold int) ([]Resource, error) {
+
 	defer a.logger.DebugFunc("findNonLabeledResources").Finish()
 
 	var foundResources []Resource
@@ -242,16 +249,62 @@ func (a *LabeledResources) findNonLabeledResources(labeledResources, newResource
 		rsMap[NewUniqueResourceKey(res).String()] = struct{}{}
 	}
 
+	// Group resources by type and namespace so that resources
+	// in large groups could be listed instead of retrieved one by one
+	var groupKeys []string
+	groups := map[string][]Resource{}
+
+	for _, res := range newResources {
+		if _, found := rsMap[NewUniqueResourceKey(res).String()]; !found {
+			groupKey := res.APIVersion() + "/" + res.Kind() + "/" + res.Namespace()
+			if _, found := groups[groupKey]; !found {
+				groupKeys = append(groupKeys, groupKey)
+			}
+			groups[groupKey] = append(groups[groupKey], res)
+		}
+	}
+
 	var wg sync.WaitGroup
 	throttle := util.NewThrottle(concurrency)
 
 	errCh := make(chan error, len(newResources))
 	resCh := make(chan Resource, len(newResources))
 
-	for _, res := range newResources {
-		res := res // copy
+	for _, groupKey := range groupKeys {
+		groupRs := groups[groupKey]
+
+		if listThreshold > 0 && len(groupRs) >= listThreshold {
+			wg.Add(1)
+			go func() {
+				throttle.Take()
+				defer throttle.Done()
+
+				defer func() { wg.Done() }()
+
+				clusterRs, err := a.identifiedResources.ExistsMany(groupRs)
+				if err != nil {
+					if !errors.IsForbidden(err) {
+						errCh <- err
+						return
+					}
+					// Listing may not be allowed even though getting is
+					clusterRs, err = a.existingResources(groupRs)
+					if err != nil {
+						errCh <- err
+						return
+					}
+				}
+
+				for _, clusterRes := range clusterRs {
+					resCh <- clusterRes
+				}
+			}()
+			continue
+		}
+
+		for _, res := range groupRs {
+			res := res // copy
 
-		if _, found := rsMap[NewUniqueResourceKey(res).String()]; !found {
 			wg.Add(1)
 			go func() {
 				throttle.Take()
@@ -285,3 +338,17 @@ func (a *LabeledResources) findNonLabeledResources(labeledResources, newResource
 
 	return foundResources, nil
 }
+
+func (a *LabeledResources) existingResources(resources []Resource) ([]Resource, error) {
+	var result []Resource
+	for _, res := range resources {
+		clusterRes, exists, err := a.identifiedResources.Exists(res, ExistsOpts{})
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			result = append(result, clusterRes)
+		}
+	}
+	return result, nil
+}
@@ -3,6 +3,13 @@
 
 package resources
 
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
 type ResourceMatcher interface {
 	Matches(Resource) bool
 }
@@ -162,3 +169,56 @@ func (m CustomResourceMatcher) Matches(res Resource) bool {
 	_, found := builtinAPIGroups[res.APIGroup()]
 	return !found
 }
+
+type NameRegexMatcher struct {
+	Regex *regexp.Regexp
+}
+
+var _ ResourceMatcher = NameRegexMatcher{}
+
+func (m NameRegexMatcher) Matches(res Resource) bool {
+	return m.Regex.MatchString(res.Name())
+}
+
+type NamePrefixMatcher struct {
+	Prefix string
+}
+
+var _ ResourceMatcher = NamePrefixMatcher{}
+
+func (m NamePrefixMatcher) Matches(res Resource) bool {
+	return strings.HasPrefix(res.Name(), m.Prefix)
+}
+
+type LabelSelectorMatcher struct {
+	Selector labels.Selector
+}
+
+var _ ResourceMatcher = LabelSelectorMatcher{}
+
+func (m LabelSelectorMatcher) Matches(res Resource) bool {
+	return m.Selector.Matches(labels.Set(res.Labels()))
+}
+
+type AnnotationMatcher struct {
+	Key    string
+	Values []string
+}
+
+var _ ResourceMatcher = AnnotationMatcher{}
+
+func (m AnnotationMatcher) Matches(res Resource) bool {
+	val, found := res.Annotations()[m.Key]
+	if !found {
+		return false
+	}
+	if len(m.Values) == 0 {
+		return true
+	}
+	for _, expectedVal := range m.Values {
+		if val == expectedVal {
+			return true
+		}
+	}
+	return false
+}
@@ -31,6 +31,10 @@ func (t FieldCopyMod) IsResourceMatching(res Resource) bool {
 }
 
 func (t FieldCopyMod) ApplyFromMultiple(res Resource, srcs map[FieldCopyModSource]Resource) error {
+	if t.Path.ContainsWildcards() {
+		return t.applyWildcardFromMultiple(res, srcs)
+	}
+
 	for _, src := range t.Sources {
 		source, found := srcs[src]
 		if !found {
@@ -52,6 +56,34 @@ func (t FieldCopyMod) ApplyFromMultiple(res Resource, srcs map[FieldCopyModSourc
 	return nil
 }
 
+// applyWildcardFromMultiple copies fields found under wildcard path in any of the sources
+func (t FieldCopyMod) applyWildcardFromMultiple(res Resource, srcs map[FieldCopyModSource]Resource) error {
+	var paths []Path
+	seen := map[string]struct{}{}
+
+	for _, src := range t.Sources {
+		source, found := srcs[src]
+		if !found || source == nil {
+			continue
+		}
+		for _, path := range t.Path.ExpandWildcards(source.unstructured().Object) {
+			if _, found := seen[path.AsString()]; !found && path.endsWithMapKey() {
+				seen[path.AsString()] = struct{}{}
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		err := FieldCopyMod{ResourceMatcher: t.ResourceMatcher, Path: path, Sources: t.Sources}.ApplyFromMultiple(res, srcs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (t FieldCopyMod) apply(obj interface{}, srcObj interface{}, path Path, fullPath Path, srcs map[FieldCopyModSource]Resource) (bool, error) {
 	for i, part := range path {
 		isLast := len(path) == i+1
@@ -144,7 +176,7 @@ func (t FieldCopyMod) apply(obj interface{}, srcObj interface{}, path Path, full
 					return false, fmt.Errorf("Unexpected non-array found: %T", srcObj)
 				}
 
-				if *part.ArrayIndex.Index < len(typedObj) {
+				if *part.ArrayIndex.Index < len(typedObj) && *part.ArrayIndex.Index < len(srcTypedObj) {
 					obj = typedObj[*part.ArrayIndex.Index]
 					srcObj = srcTypedObj[*part.ArrayIndex.Index]
 					return t.apply(obj, srcObj, path[i+1:], fullPath, srcs)
@@ -27,6 +27,20 @@ func (t FieldRemoveMod) ApplyFromMultiple(res Resource, _ map[FieldCopyModSource
 }
 
 func (t FieldRemoveMod) Apply(res Resource) error {
+	if t.Path.ContainsWildcards() {
+		for _, path := range t.Path.ExpandWildcards(res.unstructured().Object) {
+			// Wildcards may resolve to array items which cannot be removed by key
+			if !path.endsWithMapKey() {
+				continue
+			}
+			err := FieldRemoveMod{ResourceMatcher: t.ResourceMatcher, Path: path}.Apply(res)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	err := t.apply(res.unstructured().Object, t.Path)
 	if err != nil {
 		return fmt.Errorf("FieldRemoveMod for path '%s' on resource '%s': %w", t.Path.AsString(), res.Description(), err)
@@ -0,0 +1,128 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type FieldSetMod struct {
+	ResourceMatcher ResourceMatcher
+	Path            Path
+	Value           interface{}
+}
+
+var _ ResourceMod = FieldSetMod{}
+var _ ResourceModWithMultiple = FieldSetMod{}
+
+func (t FieldSetMod) IsResourceMatching(res Resource) bool {
+	if res == nil || !t.ResourceMatcher.Matches(res) {
+		return false
+	}
+	return true
+}
+
+func (t FieldSetMod) ApplyFromMultiple(res Resource, _ map[FieldCopyModSource]Resource) error {
+	return t.Apply(res)
+}
+
+func (t FieldSetMod) Apply(res Resource) error {
+	err := t.apply(res.unstructured().Object, t.Path)
+	if err != nil {
+		return fmt.Errorf("FieldSetMod for path '%s' on resource '%s': %w", t.Path.AsString(), res.Description(), err)
+	}
+	return nil
+}
+
+func (t FieldSetMod) apply(obj interface{}, path Path) error {
+	for i, part := range path {
+		isLast := len(path) == i+1
+
+		switch {
+		case part.MapKey != nil:
+			typedObj, ok := obj.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("Unexpected non-map found: %T", obj)
+			}
+
+			if isLast {
+				val, err := t.copiedValue()
+				if err != nil {
+					return err
+				}
+				typedObj[*part.MapKey] = val
+				return nil
+			}
+
+			var found bool
+			obj, found = typedObj[*part.MapKey]
+			if !found || obj == nil {
+				// create empty maps only if next part is a map key;
+				// arrays cannot be made up, so just exit
+				if path[i+1].MapKey == nil {
+					return nil
+				}
+				obj = map[string]interface{}{}
+				typedObj[*part.MapKey] = obj
+			}
+
+		case part.ArrayIndex != nil:
+			if isLast {
+				return fmt.Errorf("Expected last part of the path to be map key")
+			}
+
+			typedObj, ok := obj.([]interface{})
+			if !ok {
+				return fmt.Errorf("Unexpected non-array found: %T", obj)
+			}
+
+			switch {
+			case part.ArrayIndex.All != nil:
+				for _, obj := range typedObj {
+					err := t.apply(obj, path[i+1:])
+					if err != nil {
+						return err
+					}
+				}
+
+				return nil // dealt with children, get out
+
+			case part.ArrayIndex.Index != nil:
+				if *part.ArrayIndex.Index < len(typedObj) {
+					return t.apply(typedObj[*part.ArrayIndex.Index], path[i+1:])
+				}
+
+				return nil // index not found, nothing to set
+
+			default:
+				panic(fmt.Sprintf("Unknown array index: %#v", part.ArrayIndex))
+			}
+
+		case part.Regex != nil:
+			panic("Regex in path part is only supported for rebaseRules.")
+
+		default:
+			panic(fmt.Sprintf("Unexpected path part: %#v", part))
+		}
+	}
+
+	panic("unreachable")
+}
+
+// copiedValue makes sure that multiple resources do not share the same value
+func (t FieldSetMod) copiedValue() (interface{}, error) {
+	bs, err := json.Marshal(t.Value)
+	if err != nil {
+		return nil, fmt.Errorf("Marshaling value: %w", err)
+	}
+
+	var val interface{}
+	err = json.Unmarshal(bs, &val)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling value: %w", err)
+	}
+
+	return val, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestModFieldSet(t *testing.T) {
+	exs := []modFieldSetExample{
+		{
+			Description: "setting leaf key that exists",
+			Res: `
+metadata:
+  labels:
+    label-key: label-val`,
+			Expected: `
+metadata:
+  labels:
+    label-key: new-val`,
+			Path:  ctlres.NewPathFromStrings([]string{"metadata", "labels", "label-key"}),
+			Value: "new-val",
+		},
+		{
+			Description: "setting leaf key whose parents do not exist",
+			Res: `
+metadata: {}`,
+			Expected: `
+metadata:
+  labels:
+    label-key: new-val`,
+			Path:  ctlres.NewPathFromStrings([]string{"metadata", "labels", "label-key"}),
+			Value: "new-val",
+		},
+		{
+			Description: "setting non-scalar value",
+			Res: `
+spec: {}`,
+			Expected: `
+spec:
+  selector:
+    app: foo`,
+			Path:  ctlres.NewPathFromStrings([]string{"spec", "selector"}),
+			Value: map[string]interface{}{"app": "foo"},
+		},
+		{
+			Description: "setting keys under all array items",
+			Res: `
+spec:
+  containers:
+  - name: a
+  - name: b
+    imagePullPolicy: Always`,
+			Expected: `
+spec:
+  containers:
+  - imagePullPolicy: IfNotPresent
+    name: a
+  - imagePullPolicy: IfNotPresent
+    name: b`,
+			Path: ctlres.Path{
+				ctlres.NewPathPartFromString("spec"),
+				ctlres.NewPathPartFromString("containers"),
+				ctlres.NewPathPartFromIndexAll(),
+				ctlres.NewPathPartFromString("imagePullPolicy"),
+			},
+			Value: "IfNotPresent",
+		},
+		{
+			Description: "setting key under array that does not exist",
+			Res: `
+spec: {}`,
+			Expected: `
+spec: {}`,
+			Path: ctlres.Path{
+				ctlres.NewPathPartFromString("spec"),
+				ctlres.NewPathPartFromString("containers"),
+				ctlres.NewPathPartFromIndexAll(),
+				ctlres.NewPathPartFromString("imagePullPolicy"),
+			},
+			Value: "IfNotPresent",
+		},
+	}
+
+	for _, ex := range exs {
+		ex.Check(t)
+	}
+}
+
+type modFieldSetExample struct {
+	Description string
+	Res         string
+	Path        ctlres.Path
+	Value       interface{}
+	Expected    string
+}
+
+func (e modFieldSetExample) Check(t *testing.T) {
+	res, err := ctlres.NewResourceFromBytes([]byte(e.Res))
+	require.NoError(t, err)
+
+	err = ctlres.FieldSetMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            e.Path,
+		Value:           e.Value,
+	}.Apply(res)
+	require.NoError(t, err)
+
+	resultBs, err := res.AsYAMLBytes()
+	require.NoError(t, err)
+
+	expectEqualsStripped(t, e.Description, string(resultBs), e.Expected)
+}
@@ -11,6 +11,9 @@ type ObjectRefSetMod struct {
 	ResourceMatcher ResourceMatcher
 	Path            Path
 	ReplacementFunc func(map[string]interface{}) error
+	// Optionally used when path points to plain string name references
+	// (e.g. custom resource field 'spec.configMapName: foo')
+	StringReplacementFunc func(string) string
 }
 
 var _ ResourceMod = ObjectRefSetMod{}
@@ -41,6 +44,13 @@ func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
 				return nil
 			}
 
+			if i == len(path)-1 {
+				if str, replaced := t.replaceString(obj); replaced {
+					typedObj[*part.MapKey] = str
+					return nil
+				}
+			}
+
 		case part.ArrayIndex != nil:
 			switch {
 			case part.ArrayIndex.All != nil:
@@ -52,7 +62,13 @@ func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
 					return fmt.Errorf("Unexpected non-array found: %T", obj)
 				}
 
-				for _, obj := range typedObj {
+				for j, obj := range typedObj {
+					if i == len(path)-1 {
+						if str, replaced := t.replaceString(obj); replaced {
+							typedObj[j] = str
+							continue
+						}
+					}
 					err := t.apply(obj, path[i+1:])
 					if err != nil {
 						return err
@@ -68,6 +84,12 @@ func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
 				}
 
 				if *part.ArrayIndex.Index < len(typedObj) {
+					if i == len(path)-1 {
+						if str, replaced := t.replaceString(typedObj[*part.ArrayIndex.Index]); replaced {
+							typedObj[*part.ArrayIndex.Index] = str
+							return nil
+						}
+					}
 					return t.apply(typedObj[*part.ArrayIndex.Index], path[i+1:])
 				}
 
@@ -92,3 +114,14 @@ func (t ObjectRefSetMod) apply(obj interface{}, path Path) error {
 
 	return t.ReplacementFunc(typedObj)
 }
+
+func (t ObjectRefSetMod) replaceString(obj interface{}) (string, bool) {
+	if t.StringReplacementFunc == nil {
+		return "", false
+	}
+	typedObj, ok := obj.(string)
+	if !ok {
+		return "", false
+	}
+	return t.StringReplacementFunc(typedObj), true
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"regexp"
+	"sort"
+)
+
+const (
+	// Matches any single map key or array index
+	pathWildcard = "*"
+	// Matches any number (including zero) of nested map keys or array indexes
+	pathRecursiveWildcard = "**"
+)
+
+func (p *PathPart) IsWildcard() bool {
+	return p.MapKey != nil && (*p.MapKey == pathWildcard || *p.MapKey == pathRecursiveWildcard)
+}
+
+func (p Path) ContainsWildcards() bool {
+	for _, part := range p {
+		if part.IsWildcard() {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandWildcards returns concrete paths (consisting of map keys and
+// array indexes) that wildcard path resolves to within given object.
+// Last part of a concrete path is not required to exist when it's a map key.
+func (p Path) ExpandWildcards(obj interface{}) []Path {
+	var result []Path
+	seen := map[string]struct{}{}
+
+	p.expand(obj, Path{}, func(path Path) {
+		key := path.AsString()
+		if _, found := seen[key]; !found {
+			seen[key] = struct{}{}
+			result = append(result, path)
+		}
+	})
+
+	return result
+}
+
+func (p Path) expand(obj interface{}, prefix Path, leafFunc func(Path)) {
+	if len(p) == 0 {
+		leafFunc(prefix)
+		return
+	}
+
+	part, rest := p[0], p[1:]
+
+	switch {
+	case part.MapKey != nil && *part.MapKey == pathRecursiveWildcard:
+		// Trailing recursive wildcard only matches descendants
+		if len(rest) > 0 {
+			rest.expand(obj, prefix, leafFunc)
+		}
+		forEachPathChild(obj, func(childPart *PathPart, child interface{}) {
+			p.expand(child, appendPathPart(prefix, childPart), leafFunc)
+		})
+
+	case part.MapKey != nil && *part.MapKey == pathWildcard:
+		forEachPathChild(obj, func(childPart *PathPart, child interface{}) {
+			rest.expand(child, appendPathPart(prefix, childPart), leafFunc)
+		})
+
+	case part.MapKey != nil:
+		typedObj, ok := obj.(map[string]interface{})
+		if !ok {
+			return
+		}
+		child, found := typedObj[*part.MapKey]
+		if !found && len(rest) > 0 {
+			return
+		}
+		rest.expand(child, appendPathPart(prefix, part), leafFunc)
+
+	case part.ArrayIndex != nil:
+		typedObj, ok := obj.([]interface{})
+		if !ok {
+			return
+		}
+		for i, child := range typedObj {
+			if part.ArrayIndex.Index != nil && *part.ArrayIndex.Index != i {
+				continue
+			}
+			rest.expand(child, appendPathPart(prefix, NewPathPartFromIndex(i)), leafFunc)
+		}
+
+	case part.Regex != nil && part.Regex.Regex != nil:
+		regex, err := regexp.Compile(*part.Regex.Regex)
+		if err != nil {
+			return
+		}
+		forEachPathChild(obj, func(childPart *PathPart, child interface{}) {
+			if childPart.MapKey != nil && regex.MatchString(*childPart.MapKey) {
+				rest.expand(child, appendPathPart(prefix, childPart), leafFunc)
+			}
+		})
+	}
+}
+
+// forEachPathChild iterates over map values (sorted by key) or array items
+func forEachPathChild(obj interface{}, childFunc func(*PathPart, interface{})) {
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		var keys []string
+		for key := range typedObj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childFunc(NewPathPartFromString(key), typedObj[key])
+		}
+	case []interface{}:
+		for i, child := range typedObj {
+			childFunc(NewPathPartFromIndex(i), child)
+		}
+	}
+}
+
+func appendPathPart(path Path, part *PathPart) Path {
+	return append(append(Path{}, path...), part)
+}
+
+func (p Path) endsWithMapKey() bool {
+	return len(p) > 0 && p[len(p)-1].MapKey != nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestModFieldRemoveWithWildcards(t *testing.T) {
+	exs := []modFieldRemoveExample{
+		{
+			Description: "removing field from all array items",
+			Res: `
+spec:
+  containers:
+  - name: a
+    resources: {limits: {cpu: 1}}
+  - name: b
+    resources: {limits: {cpu: 2}}`,
+			Expected: `
+spec:
+  containers:
+  - name: a
+  - name: b`,
+			Path: ctlres.NewPathFromStrings([]string{"spec", "containers", "*", "resources"}),
+		},
+		{
+			Description: "removing all map keys",
+			Res: `
+metadata:
+  labels:
+    a: b
+    c: d`,
+			Expected: `
+metadata:
+  labels: {}`,
+			Path: ctlres.NewPathFromStrings([]string{"metadata", "labels", "*"}),
+		},
+		{
+			Description: "removing field at any nesting level",
+			Res: `
+spec:
+  replicas: 1
+  template:
+    spec:
+      replicas: 2
+      other:
+      - replicas: 3`,
+			Expected: `
+spec:
+  template:
+    spec:
+      other:
+      - {}`,
+			Path: ctlres.NewPathFromStrings([]string{"spec", "**", "replicas"}),
+		},
+	}
+
+	for _, ex := range exs {
+		ex.Check(t)
+	}
+}
+
+func TestModFieldCopyWithWildcards(t *testing.T) {
+	newRes, err := ctlres.NewResourceFromBytes([]byte(`
+spec:
+  containers:
+  - name: a
+  - name: b
+`))
+	require.NoError(t, err)
+
+	existingRes, err := ctlres.NewResourceFromBytes([]byte(`
+spec:
+  containers:
+  - name: a
+    resources: {limits: {cpu: 1}}
+  - name: b
+    resources: {limits: {cpu: 2}}
+`))
+	require.NoError(t, err)
+
+	err = ctlres.FieldCopyMod{
+		ResourceMatcher: ctlres.AllMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"spec", "containers", "*", "resources"}),
+		Sources:         []ctlres.FieldCopyModSource{ctlres.FieldCopyModSourceNew, ctlres.FieldCopyModSourceExisting},
+	}.ApplyFromMultiple(newRes, map[ctlres.FieldCopyModSource]ctlres.Resource{
+		ctlres.FieldCopyModSourceNew:      newRes,
+		ctlres.FieldCopyModSourceExisting: existingRes,
+	})
+	require.NoError(t, err)
+
+	resultBs, err := newRes.AsYAMLBytes()
+	require.NoError(t, err)
+
+	expectEqualsStripped(t, "copying field from all array items", string(resultBs), `
+spec:
+  containers:
+  - name: a
+    resources:
+      limits:
+        cpu: 1
+  - name: b
+    resources:
+      limits:
+        cpu: 2`)
+}
@@ -43,6 +43,7 @@ type Resource interface {
 	IsProvisioned() bool
 	IsDeleting() bool
 	UID() string
+	ResourceVersion() string
 
 	Equal(res Resource) bool
 	DeepCopy() Resource
@@ -198,8 +199,9 @@ func (r *ResourceImpl) Description() string {
 	return result
 }
 
-func (r *ResourceImpl) CreatedAt() time.Time { return r.un.GetCreationTimestamp().Time }
-func (r *ResourceImpl) UID() string          { return string(r.un.GetUID()) }
+func (r *ResourceImpl) CreatedAt() time.Time    { return r.un.GetCreationTimestamp().Time }
+func (r *ResourceImpl) UID() string             { return string(r.un.GetUID()) }
+func (r *ResourceImpl) ResourceVersion() string { return r.un.GetResourceVersion() }
 
 func (r *ResourceImpl) IsProvisioned() bool {
 	// metrics.k8s.io/PodMetrics for example did not have a UID set
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 type ResourceTypes interface {
@@ -24,6 +25,10 @@ type ResourceTypes interface {
 type ResourceTypesImplOpts struct {
 	IgnoreFailingAPIServices   bool
 	CanIgnoreFailingAPIService func(schema.GroupVersion) bool
+
+	// DiscoveryCache (if set) is used instead of discovering resource types
+	// until resource type is not found or fresh resource types are requested
+	DiscoveryCache *DiscoveryCache
 }
 
 type ResourceTypesImpl struct {
@@ -32,6 +37,8 @@ type ResourceTypesImpl struct {
 
 	memoizedResTypes     *[]ResourceType
 	memoizedResTypesLock sync.RWMutex
+	// Set once cached resource types were found to be outdated
+	skipDiscoveryCache bool
 }
 
 var _ ResourceTypes = &ResourceTypesImpl{}
@@ -48,13 +55,13 @@ func NewResourceTypesImpl(coreClient kubernetes.Interface, opts ResourceTypesImp
 func (g *ResourceTypesImpl) All(ignoreCachedResTypes bool) ([]ResourceType, error) {
 	if ignoreCachedResTypes {
 		// TODO Update cache while doing a fresh fetch
-		return g.all()
+		return g.all(false)
 	}
 	return g.memoizedAll()
 }
 
-func (g *ResourceTypesImpl) all() ([]ResourceType, error) {
-	serverResources, err := g.serverResources()
+func (g *ResourceTypesImpl) all(useDiscoveryCache bool) ([]ResourceType, error) {
+	serverResources, err := g.serverResources(useDiscoveryCache)
 	if err != nil {
 		return nil, err
 	}
@@ -112,13 +119,28 @@ func (g *ResourceTypesImpl) canIgnoreFailingGroupVersions(groupVers map[schema.G
 	return false
 }
 
-func (g *ResourceTypesImpl) serverResources() ([]*metav1.APIResourceList, error) {
+func (g *ResourceTypesImpl) serverResources(useDiscoveryCache bool) ([]*metav1.APIResourceList, error) {
 	var serverResources []*metav1.APIResourceList
 	var lastErr error
 
+	cache, cluster := g.opts.DiscoveryCache, g.clusterIdentity()
+	if len(cluster) == 0 {
+		cache = nil
+	}
+
+	if cache != nil && useDiscoveryCache {
+		if cachedResources, found := cache.Load(cluster); found {
+			return cachedResources, nil
+		}
+	}
+
 	for i := 0; i < 10; i++ {
 		_, serverResources, lastErr = g.coreClient.Discovery().ServerGroupsAndResources()
 		if lastErr == nil {
+			if cache != nil {
+				// Failing to cache only makes next invocation slower
+				_ = cache.Save(cluster, serverResources)
+			}
 			return serverResources, nil
 		} else if typedLastErr, ok := lastErr.(*discovery.ErrGroupDiscoveryFailed); ok {
 			if len(serverResources) > 0 && g.canIgnoreFailingGroupVersions(typedLastErr.Groups) {
@@ -133,6 +155,19 @@ func (g *ResourceTypesImpl) serverResources() ([]*metav1.APIResourceList, error)
 	return nil, lastErr
 }
 
+// clusterIdentity returns API server URL used to key discovery cache
+// (empty if it cannot be determined, e.g. for fake clients)
+func (g *ResourceTypesImpl) clusterIdentity() string {
+	if g.opts.DiscoveryCache == nil {
+		return ""
+	}
+	restClient, ok := g.coreClient.Discovery().RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		return ""
+	}
+	return restClient.Get().URL().String()
+}
+
 func (g *ResourceTypesImpl) memoizedAll() ([]ResourceType, error) {
 	g.memoizedResTypesLock.RLock()
 
@@ -149,7 +184,7 @@ func (g *ResourceTypesImpl) memoizedAll() ([]ResourceType, error) {
 	g.memoizedResTypesLock.Lock()
 	defer g.memoizedResTypesLock.Unlock()
 
-	resTypes, err := g.all()
+	resTypes, err := g.all(!g.skipDiscoveryCache)
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +198,8 @@ func (g *ResourceTypesImpl) Find(resource Resource) (ResourceType, error) {
 	if err != nil {
 		g.memoizedResTypesLock.Lock()
 		g.memoizedResTypes = nil
+		// Cached resource types may not include recently added ones (e.g. CRDs)
+		g.skipDiscoveryCache = true
 		g.memoizedResTypesLock.Unlock()
 
 		return g.findOnce(resource)
@@ -280,6 +317,36 @@ func NonMatchingGK(in []ResourceType, gk schema.GroupKind) []ResourceType {
 	return out
 }
 
+// MatchingGVsOfGKs keeps only versions of each group kind that are among given
+// group versions; all versions are kept for group kinds without any of given versions
+func MatchingGVsOfGKs(in []ResourceType, gvs []schema.GroupVersion) []ResourceType {
+	gvsByGV := map[schema.GroupVersion]struct{}{}
+	for _, gv := range gvs {
+		gvsByGV[gv] = struct{}{}
+	}
+
+	gkOf := func(item ResourceType) schema.GroupKind {
+		return schema.GroupKind{Group: item.GroupVersionResource.Group, Kind: item.APIResource.Kind}
+	}
+
+	gksWithMatchingGV := map[schema.GroupKind]struct{}{}
+	for _, item := range in {
+		if _, found := gvsByGV[item.GroupVersionResource.GroupVersion()]; found {
+			gksWithMatchingGV[gkOf(item)] = struct{}{}
+		}
+	}
+
+	var out []ResourceType
+	for _, item := range in {
+		_, gkMatched := gksWithMatchingGV[gkOf(item)]
+		_, gvMatched := gvsByGV[item.GroupVersionResource.GroupVersion()]
+		if !gkMatched || gvMatched {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 // TODO: Extend ResourceRef and PartialResourceRefd to allow GVK matching
 func MatchingAnyGK(in []ResourceType, gks []schema.GroupKind) []ResourceType {
 	var out []ResourceType
@@ -0,0 +1,48 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestMatchingGVsOfGKs(t *testing.T) {
+	resType := func(group, version, kind string) ctlres.ResourceType {
+		gvr := schema.GroupVersionResource{Group: group, Version: version, Resource: kind + "s"}
+		return ctlres.ResourceType{GroupVersionResource: gvr, APIResource: metav1.APIResource{Kind: kind}}
+	}
+
+	resTypes := []ctlres.ResourceType{
+		resType("autoscaling", "v1", "HorizontalPodAutoscaler"),
+		resType("autoscaling", "v2", "HorizontalPodAutoscaler"),
+		resType("example.com", "v1alpha1", "Widget"),
+		resType("example.com", "v1", "Widget"),
+		resType("", "v1", "Pod"),
+	}
+
+	result := ctlres.MatchingGVsOfGKs(resTypes, []schema.GroupVersion{
+		{Group: "autoscaling", Version: "v2"},
+		{Group: "example.com", Version: "v1alpha1"},
+		{Group: "example.com", Version: "v1"},
+		{Group: "example.com", Version: "v2"},
+	})
+
+	var gvrs []string
+	for _, resType := range result {
+		gvrs = append(gvrs, resType.GroupVersionResource.String())
+	}
+
+	// Group kinds without any of given versions (e.g. Pod) keep all versions
+	require.Equal(t, []string{
+		"autoscaling/v2, Resource=HorizontalPodAutoscalers",
+		"example.com/v1alpha1, Resource=Widgets",
+		"example.com/v1, Resource=Widgets",
+		"/v1, Resource=Pods",
+	}, gvrs)
+}
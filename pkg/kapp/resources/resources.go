@@ -42,6 +42,7 @@ type Resources interface {
 	All([]ResourceType, AllOpts) ([]Resource, error)
 	Delete(Resource) error
 	Exists(Resource, ExistsOpts) (Resource, bool, error)
+	ExistsMany([]Resource) ([]Resource, error)
 	Get(Resource) (Resource, error)
 	Patch(Resource, types.PatchType, []byte) (Resource, error)
 	Update(Resource) (Resource, error)
@@ -62,19 +63,24 @@ type ResourcesImpl struct {
 	assumedAllowedNamespacesMemoLock sync.Mutex
 	assumedAllowedNamespacesMemo     *[]string
 
+	// Limits number of resource types listed concurrently (nil means no limit)
+	listThrottle *util.Throttle
+
 	logger logger.Logger
 }
 
 type ResourcesImplOpts struct {
 	FallbackAllowedNamespaces        []string
 	ScopeToFallbackAllowedNamespaces bool
+	// 0 means all resource types are listed concurrently
+	ListConcurrency int
 }
 
 func NewResourcesImpl(resourceTypes ResourceTypes, coreClient kubernetes.Interface,
 	dynamicClient dynamic.Interface, mutedDynamicClient dynamic.Interface,
 	opts ResourcesImplOpts, logger logger.Logger) *ResourcesImpl {
 
-	return &ResourcesImpl{
+	resources := &ResourcesImpl{
 		resourceTypes:      resourceTypes,
 		coreClient:         coreClient,
 		dynamicClient:      dynamicClient,
@@ -82,6 +88,13 @@ func NewResourcesImpl(resourceTypes ResourceTypes, coreClient kubernetes.Interfa
 		opts:               opts,
 		logger:             logger.NewPrefixed("Resources"),
 	}
+
+	if opts.ListConcurrency > 0 {
+		throttle := util.NewThrottle(opts.ListConcurrency)
+		resources.listThrottle = &throttle
+	}
+
+	return resources
 }
 
 type unstructItems struct {
@@ -110,6 +123,11 @@ func (c *ResourcesImpl) All(resTypes []ResourceType, opts AllOpts) ([]Resource,
 		go func() {
 			defer itemsDone.Done()
 
+			if c.listThrottle != nil {
+				c.listThrottle.Take()
+				defer c.listThrottle.Done()
+			}
+
 			defer c.logger.DebugFunc(resType.GroupVersionResource.String()).Finish()
 
 			var list *unstructured.UnstructuredList
@@ -448,6 +466,69 @@ func (c *ResourcesImpl) Exists(resource Resource, existsOpts ExistsOpts) (Resour
 	return resObj, found, err
 }
 
+// ExistsMany returns existing resources out of given ones by listing resources
+// instead of getting each one; given resources are expected to be of the same
+// type and namespace. Listing stops once all given resources are found.
+func (c *ResourcesImpl) ExistsMany(resources []Resource) ([]Resource, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	defer c.logger.DebugFunc(fmt.Sprintf("ExistsMany(%d)", len(resources))).Finish()
+
+	resClient, resType, err := c.resourceClient(resources[0], resourceClientOpts{Warnings: false})
+	if err != nil {
+		// Assume if type is not known to the API server
+		// then such resources cannot exist on the server
+		if _, ok := err.(ResourceTypesUnknownTypeErr); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	remainingNames := map[string]struct{}{}
+	for _, res := range resources {
+		remainingNames[res.Name()] = struct{}{}
+	}
+
+	var result []Resource
+
+	pageSize := newAdaptivePageSize(len(remainingNames))
+	listOpts := metav1.ListOptions{}
+
+	for len(remainingNames) > 0 {
+		var list *unstructured.UnstructuredList
+		var err error
+
+		listOpts.Limit = pageSize.Size()
+		startTime := time.Now()
+
+		err = util.Retry2(time.Second, 5*time.Second, c.isServerRescaleErr, func() error {
+			list, err = resClient.List(context.TODO(), listOpts)
+			return err
+		})
+		if err != nil {
+			return nil, c.resourceErr(err, "Listing existing", resources[0])
+		}
+
+		pageSize.Observe(time.Now().Sub(startTime))
+
+		for _, item := range list.Items {
+			if _, found := remainingNames[item.GetName()]; found {
+				result = append(result, NewResourceUnstructured(item, resType))
+				delete(remainingNames, item.GetName())
+			}
+		}
+
+		listOpts.Continue = list.GetContinue()
+		if len(listOpts.Continue) == 0 {
+			break
+		}
+	}
+
+	return result, nil
+}
+
 var (
 	// Error example: Checking existence of resource podmetrics/knative-ingressgateway-646d475cbb-c82qb (metrics.k8s.io/v1beta1)
 	//   namespace: istio-system: Error while getting pod knative-ingressgateway-646d475cbb-c82qb:
@@ -0,0 +1,102 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestResourcesExistsManyListsPages(t *testing.T) {
+	client := &fakePagedClient{}
+	for i := 0; i < 150; i++ {
+		client.items = append(client.items, *fakeNamedConfigMap(fmt.Sprintf("cm-%d", i)))
+	}
+
+	resources := NewResourcesImpl(fakeWatchedResourceTypes{}, nil, client, client, ResourcesImplOpts{}, logger.NewTODOLogger())
+
+	existingRs, err := resources.ExistsMany([]Resource{
+		NewResourceUnstructured(*fakeNamedConfigMap("cm-1"), ResourceType{}),
+		NewResourceUnstructured(*fakeNamedConfigMap("cm-120"), ResourceType{}),
+		NewResourceUnstructured(*fakeNamedConfigMap("cm-missing"), ResourceType{}),
+	})
+	require.NoError(t, err)
+
+	var names []string
+	for _, res := range existingRs {
+		names = append(names, res.Name())
+	}
+	require.Equal(t, []string{"cm-1", "cm-120"}, names)
+	require.Equal(t, []int64{adaptivePageSizeMin, adaptivePageSizeMin * 2}, client.limits)
+}
+
+func TestResourcesExistsManyStopsOnceAllFound(t *testing.T) {
+	client := &fakePagedClient{}
+	for i := 0; i < 150; i++ {
+		client.items = append(client.items, *fakeNamedConfigMap(fmt.Sprintf("cm-%d", i)))
+	}
+
+	resources := NewResourcesImpl(fakeWatchedResourceTypes{}, nil, client, client, ResourcesImplOpts{}, logger.NewTODOLogger())
+
+	existingRs, err := resources.ExistsMany([]Resource{
+		NewResourceUnstructured(*fakeNamedConfigMap("cm-1"), ResourceType{}),
+	})
+	require.NoError(t, err)
+	require.Len(t, existingRs, 1)
+	require.Len(t, client.limits, 1)
+}
+
+func fakeNamedConfigMap(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "ns",
+		},
+	}}
+}
+
+// fakePagedClient lists items in pages; unused methods panic
+type fakePagedClient struct {
+	dynamic.Interface
+	dynamic.NamespaceableResourceInterface
+
+	items  []unstructured.Unstructured
+	limits []int64
+}
+
+func (c *fakePagedClient) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return c
+}
+
+func (c *fakePagedClient) Namespace(string) dynamic.ResourceInterface { return c }
+
+func (c *fakePagedClient) List(_ context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	c.limits = append(c.limits, opts.Limit)
+
+	start := 0
+	if len(opts.Continue) > 0 {
+		start, _ = strconv.Atoi(opts.Continue)
+	}
+	end := start + int(opts.Limit)
+	if end > len(c.items) {
+		end = len(c.items)
+	}
+
+	list := &unstructured.UnstructuredList{Items: c.items[start:end]}
+	if end < len(c.items) {
+		list.SetContinue(strconv.Itoa(end))
+	}
+	return list, nil
+}
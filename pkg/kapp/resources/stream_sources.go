@@ -0,0 +1,114 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	stdinPath      = "-"
+	streamLabelSep = "="
+)
+
+var (
+	fdStreamPathRegexp = regexp.MustCompile(`^/(dev|proc/self)/fd/\d+$`)
+
+	// Streams could only be read once, hence contents are
+	// shared between sources that refer to the same stream
+	streamsLock sync.Mutex
+	streams     = map[string]*streamContents{}
+)
+
+type streamContents struct {
+	once  sync.Once
+	bytes []byte
+	err   error
+}
+
+// IsStreamFile returns true if file refers to stdin ('-', '/dev/stdin')
+// or to a file descriptor (e.g. '/dev/fd/3', process substitution),
+// optionally prefixed with label (e.g. 'helm=-', 'ytt=/dev/fd/63')
+func IsStreamFile(file string) bool {
+	_, _, ok := ParseStreamFile(file)
+	return ok
+}
+
+// ParseStreamFile returns stream path and its (optional) label
+func ParseStreamFile(file string) (string, string, bool) {
+	if isStreamPath(file) {
+		return file, "", true
+	}
+	if idx := strings.Index(file, streamLabelSep); idx > 0 && isStreamPath(file[idx+1:]) {
+		return file[idx+1:], file[:idx], true
+	}
+	return "", "", false
+}
+
+func isStreamPath(path string) bool {
+	return path == stdinPath || path == "/dev/stdin" || fdStreamPathRegexp.MatchString(path)
+}
+
+// StreamSource reads stdin or file descriptor. Label is used to distinguish
+// multiple streams (e.g. generated by different tools) in descriptions.
+type StreamSource struct {
+	path  string
+	label string
+}
+
+var _ FileSource = StreamSource{}
+
+func NewStreamSource(path, label string) StreamSource {
+	return StreamSource{path: path, label: label}
+}
+
+func (s StreamSource) Description() string {
+	switch {
+	case len(s.label) > 0 && s.isStdin():
+		return fmt.Sprintf("stdin '%s'", s.label)
+	case len(s.label) > 0:
+		return fmt.Sprintf("stream '%s' (%s)", s.label, s.path)
+	case s.isStdin():
+		return "stdin"
+	default:
+		return fmt.Sprintf("stream '%s'", s.path)
+	}
+}
+
+func (s StreamSource) Bytes() ([]byte, error) {
+	key := s.path
+	if s.isStdin() {
+		key = stdinPath
+	}
+
+	streamsLock.Lock()
+	contents, found := streams[key]
+	if !found {
+		contents = &streamContents{}
+		streams[key] = contents
+	}
+	streamsLock.Unlock()
+
+	contents.once.Do(func() {
+		if key == stdinPath {
+			contents.bytes, contents.err = io.ReadAll(os.Stdin)
+		} else {
+			contents.bytes, contents.err = os.ReadFile(s.path)
+		}
+		if contents.err != nil {
+			contents.err = fmt.Errorf("Reading %s: %w", s.Description(), contents.err)
+		}
+	})
+
+	return contents.bytes, contents.err
+}
+
+func (s StreamSource) isStdin() bool {
+	return s.path == stdinPath || s.path == "/dev/stdin" || s.path == "/dev/fd/0" || s.path == "/proc/self/fd/0"
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestParseStreamFile(t *testing.T) {
+	examples := []struct {
+		File  string
+		Path  string
+		Label string
+		OK    bool
+	}{
+		{"-", "-", "", true},
+		{"/dev/stdin", "/dev/stdin", "", true},
+		{"/dev/fd/63", "/dev/fd/63", "", true},
+		{"/proc/self/fd/3", "/proc/self/fd/3", "", true},
+		{"helm=-", "-", "helm", true},
+		{"ytt=/dev/fd/63", "/dev/fd/63", "ytt", true},
+		{"=-", "", "", false},
+		{"/tmp/config.yml", "", "", false},
+		{"a=b.yml", "", "", false},
+		{"/dev/fd/x", "", "", false},
+	}
+
+	for _, ex := range examples {
+		path, label, ok := ctlres.ParseStreamFile(ex.File)
+		require.Equal(t, ex.OK, ok, "File: %s", ex.File)
+		require.Equal(t, ex.Path, path, "File: %s", ex.File)
+		require.Equal(t, ex.Label, label, "File: %s", ex.File)
+	}
+}
+
+func TestStreamSourceDescription(t *testing.T) {
+	require.Equal(t, "stdin", ctlres.NewStreamSource("-", "").Description())
+	require.Equal(t, "stdin 'helm'", ctlres.NewStreamSource("/dev/stdin", "helm").Description())
+	require.Equal(t, "stream '/dev/fd/63'", ctlres.NewStreamSource("/dev/fd/63", "").Description())
+	require.Equal(t, "stream 'ytt' (/dev/fd/63)", ctlres.NewStreamSource("/dev/fd/63", "ytt").Description())
+}
+
+func TestStreamFileResources(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	defer reader.Close()
+
+	_, err = writer.WriteString("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n---\nkind: [\n")
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	file := fmt.Sprintf("gen=/dev/fd/%d", reader.Fd())
+
+	for i := 0; i < 2; i++ {
+		fileRs, err := ctlres.NewFileResources(nil, file)
+		require.NoError(t, err)
+		require.Len(t, fileRs, 1)
+		require.Equal(t, fmt.Sprintf("stream 'gen' (/dev/fd/%d)", reader.Fd()), fileRs[0].Description())
+
+		// Stream is read once, but its contents could be used multiple times
+		_, err = fileRs[0].Resources()
+		require.ErrorContains(t, err, fmt.Sprintf("Parsing stream 'gen' (/dev/fd/%d) doc 2:", reader.Fd()))
+	}
+}
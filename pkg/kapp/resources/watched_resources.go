@@ -0,0 +1,314 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	watchedResourcesRelistDelay = time.Second
+)
+
+// WatchedResources serves Get from watches (one per resource type and namespace)
+// scoped by label selector instead of getting each resource from the API server.
+// Watch is started on first Get for particular resource type and namespace;
+// resources that are not (yet) known to watch are retrieved via regular Get.
+type WatchedResources struct {
+	*ResourcesImpl
+
+	labelSelector labels.Selector
+
+	lock      sync.Mutex
+	watches   map[string]*resourceWatch
+	changedCh chan struct{}
+	stopCh    chan struct{}
+	stopOnce  sync.Once
+
+	logger logger.Logger
+}
+
+var _ Resources = &WatchedResources{}
+
+type resourceWatch struct {
+	resType ResourceType
+	client  dynamic.ResourceInterface
+
+	synced bool
+	failed bool
+	items  map[string]unstructured.Unstructured
+
+	// Resources written by kapp are not served from watch until
+	// watch delivers version that was written (or watch is re-listed)
+	// since watch may still deliver older versions of resources
+	pendingVersions map[string]pendingVersion
+	numWrites       int
+}
+
+type pendingVersion struct {
+	Version string
+	// Number of writes recorded before this one
+	WriteIdx int
+}
+
+func NewWatchedResources(resources *ResourcesImpl, labelSelector labels.Selector, logger logger.Logger) *WatchedResources {
+	return &WatchedResources{
+		ResourcesImpl: resources,
+		labelSelector: labelSelector,
+		watches:       map[string]*resourceWatch{},
+		changedCh:     make(chan struct{}),
+		stopCh:        make(chan struct{}),
+		logger:        logger.NewPrefixed("WatchedResources"),
+	}
+}
+
+// Changed returns channel that is closed once any of watched resources changes
+func (c *WatchedResources) Changed() <-chan struct{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.changedCh
+}
+
+// Stop stops all watches; Get falls back to getting resources from API server
+func (c *WatchedResources) Stop() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+}
+
+func (c *WatchedResources) Get(resource Resource) (Resource, error) {
+	w, err := c.watch(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	item, found := c.cachedItem(w, resource.Name())
+	c.lock.Unlock()
+
+	if found {
+		return NewResourceUnstructured(item, w.resType), nil
+	}
+	return c.ResourcesImpl.Get(resource)
+}
+
+func (c *WatchedResources) Create(resource Resource) (Resource, error) {
+	createdRes, err := c.ResourcesImpl.Create(resource)
+	if err == nil {
+		c.markWritten(createdRes, false)
+	}
+	return createdRes, err
+}
+
+func (c *WatchedResources) Update(resource Resource) (Resource, error) {
+	updatedRes, err := c.ResourcesImpl.Update(resource)
+	if err == nil {
+		c.markWritten(updatedRes, false)
+	}
+	return updatedRes, err
+}
+
+func (c *WatchedResources) Patch(resource Resource, patchType types.PatchType, data []byte) (Resource, error) {
+	patchedRes, err := c.ResourcesImpl.Patch(resource, patchType, data)
+	if err == nil {
+		c.markWritten(patchedRes, false)
+	}
+	return patchedRes, err
+}
+
+func (c *WatchedResources) Delete(resource Resource) error {
+	err := c.ResourcesImpl.Delete(resource)
+	if err == nil {
+		// Deletion may only set deletion timestamp, hence next version is not known
+		c.markWritten(resource, true)
+	}
+	return err
+}
+
+func (c *WatchedResources) cachedItem(w *resourceWatch, name string) (unstructured.Unstructured, bool) {
+	if !w.synced || w.failed {
+		return unstructured.Unstructured{}, false
+	}
+	if _, pending := w.pendingVersions[name]; pending {
+		return unstructured.Unstructured{}, false
+	}
+	item, found := w.items[name]
+	if !found {
+		// Resource may not be labeled (e.g. not owned by the app)
+		return unstructured.Unstructured{}, false
+	}
+	return *item.DeepCopy(), true
+}
+
+func (c *WatchedResources) markWritten(resource Resource, deleted bool) {
+	resType, err := c.resourceTypes.Find(resource)
+	if err != nil {
+		return
+	}
+
+	var version string
+	if !deleted {
+		un := resource.unstructured()
+		version = un.GetResourceVersion()
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if w, found := c.watches[c.watchKey(resType, resource.Namespace())]; found {
+		w.pendingVersions[resource.Name()] = pendingVersion{Version: version, WriteIdx: w.numWrites}
+		w.numWrites++
+	}
+}
+
+func (c *WatchedResources) watch(resource Resource) (*resourceWatch, error) {
+	client, resType, err := c.resourceClient(resource, resourceClientOpts{Warnings: false})
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := c.watchKey(resType, resource.Namespace())
+
+	w, found := c.watches[key]
+	if !found {
+		w = &resourceWatch{
+			resType:         resType,
+			client:          client,
+			items:           map[string]unstructured.Unstructured{},
+			pendingVersions: map[string]pendingVersion{},
+		}
+		c.watches[key] = w
+		go c.run(key, w)
+	}
+
+	return w, nil
+}
+
+func (c *WatchedResources) watchKey(resType ResourceType, namespace string) string {
+	if !resType.Namespaced() {
+		namespace = ""
+	}
+	return resType.GroupVersionResource.String() + "/" + namespace
+}
+
+// run lists and then watches resources until stopped; if list or watch
+// cannot be started (e.g. not permitted), resources are retrieved via Get
+func (c *WatchedResources) run(key string, w *resourceWatch) {
+	for {
+		watcher, err := c.listAndWatch(w)
+		if err != nil {
+			c.logger.Debug("%s: %s", key, err)
+			c.lock.Lock()
+			w.failed = true
+			c.lock.Unlock()
+			return
+		}
+
+		for done := false; !done; {
+			select {
+			case <-c.stopCh:
+				watcher.Stop()
+				return
+
+			case event, ok := <-watcher.ResultChan():
+				if !ok || event.Type == watch.Error {
+					// Watch may expire (e.g. resource version is too old), hence re-list
+					watcher.Stop()
+					done = true
+					continue
+				}
+				c.record(w, event)
+			}
+		}
+
+		select {
+		case <-c.stopCh:
+			return
+		case <-time.After(watchedResourcesRelistDelay):
+		}
+	}
+}
+
+func (c *WatchedResources) listAndWatch(w *resourceWatch) (watch.Interface, error) {
+	listOpts := metav1.ListOptions{LabelSelector: c.labelSelector.String()}
+
+	c.lock.Lock()
+	numWritesBeforeList := w.numWrites
+	c.lock.Unlock()
+
+	list, err := w.client.List(context.TODO(), listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Listing: %w", err)
+	}
+
+	listOpts.ResourceVersion = list.GetResourceVersion()
+	listOpts.AllowWatchBookmarks = true
+
+	watcher, err := w.client.Watch(context.TODO(), listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("Watching: %w", err)
+	}
+
+	c.lock.Lock()
+	w.items = map[string]unstructured.Unstructured{}
+	for _, item := range list.Items {
+		w.items[item.GetName()] = item
+	}
+	// Listed resources are at least as new as ones written before listing
+	for name, pending := range w.pendingVersions {
+		if pending.WriteIdx < numWritesBeforeList {
+			delete(w.pendingVersions, name)
+		}
+	}
+	w.synced = true
+	c.notifyChangedLocked()
+	c.lock.Unlock()
+
+	return watcher, nil
+}
+
+func (c *WatchedResources) record(w *resourceWatch, event watch.Event) {
+	item, ok := event.Object.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	name := item.GetName()
+
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		w.items[name] = *item
+		if pending, found := w.pendingVersions[name]; found && pending.Version == item.GetResourceVersion() {
+			delete(w.pendingVersions, name)
+		}
+	case watch.Deleted:
+		delete(w.items, name)
+		delete(w.pendingVersions, name)
+	default:
+		// Bookmarks only advance resource version
+		return
+	}
+
+	c.notifyChangedLocked()
+}
+
+func (c *WatchedResources) notifyChangedLocked() {
+	close(c.changedCh)
+	c.changedCh = make(chan struct{})
+}
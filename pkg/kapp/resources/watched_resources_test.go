@@ -0,0 +1,179 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resources
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestWatchedResourcesGet(t *testing.T) {
+	client := &fakeWatchedClient{
+		obj:     fakeWatchedConfigMap("1", "v1"),
+		watcher: watch.NewFakeWithChanSize(10, false),
+		listCh:  make(chan struct{}),
+	}
+
+	resources := NewWatchedResources(
+		NewResourcesImpl(fakeWatchedResourceTypes{}, nil, client, client, ResourcesImplOpts{}, logger.NewTODOLogger()),
+		labels.SelectorFromSet(labels.Set{"app": "test"}), logger.NewTODOLogger())
+	defer resources.Stop()
+
+	res := NewResourceUnstructured(*fakeWatchedConfigMap("", ""), ResourceType{})
+
+	getValue := func() string {
+		res, err := resources.Get(res)
+		require.NoError(t, err)
+		return res.UnstructuredObject()["data"].(map[string]interface{})["key"].(string)
+	}
+
+	changedCh := resources.Changed()
+
+	// Watch is started by first get
+	require.Equal(t, "v1", getValue())
+	require.Equal(t, 1, client.numGets())
+
+	close(client.listCh)
+	waitForChange(t, changedCh)
+
+	require.Equal(t, "v1", getValue())
+	require.Equal(t, 1, client.numGets(), "Expected resource to be served from watch")
+
+	changedCh = resources.Changed()
+	client.watcher.Modify(fakeWatchedConfigMap("2", "v2"))
+	waitForChange(t, changedCh)
+
+	require.Equal(t, "v2", getValue())
+	require.Equal(t, 1, client.numGets())
+
+	// Written resource is retrieved via get until watch catches up
+	client.setObj(fakeWatchedConfigMap("4", "v4"))
+	_, err := resources.Update(res)
+	require.NoError(t, err)
+
+	changedCh = resources.Changed()
+	client.watcher.Modify(fakeWatchedConfigMap("3", "v3"))
+	waitForChange(t, changedCh)
+
+	require.Equal(t, "v4", getValue())
+	require.Equal(t, 2, client.numGets())
+
+	changedCh = resources.Changed()
+	client.watcher.Modify(fakeWatchedConfigMap("4", "v4"))
+	waitForChange(t, changedCh)
+
+	require.Equal(t, "v4", getValue())
+	require.Equal(t, 2, client.numGets())
+
+	changedCh = resources.Changed()
+	client.watcher.Delete(fakeWatchedConfigMap("4", "v4"))
+	waitForChange(t, changedCh)
+
+	// Resources not known to watch are retrieved via get
+	require.Equal(t, "v4", getValue())
+	require.Equal(t, 3, client.numGets())
+}
+
+func waitForChange(t *testing.T, changedCh <-chan struct{}) {
+	select {
+	case <-changedCh:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "Timed out waiting for watched resources to change")
+	}
+}
+
+func fakeWatchedConfigMap(version, value string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "cm",
+			"namespace":       "ns",
+			"resourceVersion": version,
+		},
+		"data": map[string]interface{}{"key": value},
+	}}
+}
+
+type fakeWatchedResourceTypes struct{}
+
+func (fakeWatchedResourceTypes) All(bool) ([]ResourceType, error) { return nil, nil }
+
+func (fakeWatchedResourceTypes) Find(Resource) (ResourceType, error) {
+	return ResourceType{
+		GroupVersionResource: schema.GroupVersionResource{Version: "v1", Resource: "configmaps"},
+		APIResource:          metav1.APIResource{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"},
+	}, nil
+}
+
+func (fakeWatchedResourceTypes) CanIgnoreFailingGroupVersion(schema.GroupVersion) bool { return false }
+
+// fakeWatchedClient serves single resource; unused methods panic
+type fakeWatchedClient struct {
+	dynamic.Interface
+	dynamic.NamespaceableResourceInterface
+
+	lock    sync.Mutex
+	obj     *unstructured.Unstructured
+	gets    int
+	watcher *watch.FakeWatcher
+	listCh  chan struct{}
+}
+
+func (c *fakeWatchedClient) Resource(schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return c
+}
+
+func (c *fakeWatchedClient) Namespace(string) dynamic.ResourceInterface { return c }
+
+func (c *fakeWatchedClient) Get(context.Context, string, metav1.GetOptions, ...string) (*unstructured.Unstructured, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.gets++
+	return c.obj.DeepCopy(), nil
+}
+
+func (c *fakeWatchedClient) Update(context.Context, *unstructured.Unstructured, metav1.UpdateOptions, ...string) (*unstructured.Unstructured, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.obj.DeepCopy(), nil
+}
+
+func (c *fakeWatchedClient) List(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	<-c.listCh
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{*c.obj.DeepCopy()}}
+	list.SetResourceVersion("1")
+	return list, nil
+}
+
+func (c *fakeWatchedClient) Watch(context.Context, metav1.ListOptions) (watch.Interface, error) {
+	return c.watcher, nil
+}
+
+func (c *fakeWatchedClient) setObj(obj *unstructured.Unstructured) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.obj = obj
+}
+
+func (c *fakeWatchedClient) numGets() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.gets
+}
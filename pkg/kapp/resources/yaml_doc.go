@@ -22,23 +22,50 @@ func NewYAMLFile(fileSrc FileSource) YAMLFile {
 func (f YAMLFile) Docs() ([][]byte, error) {
 	var docs [][]byte
 
-	fileBytes, err := f.fileSrc.Bytes()
+	err := f.EachDoc(func(docBytes []byte) error {
+		docs = append(docs, docBytes)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	reader := kyaml.NewYAMLReader(bufio.NewReaderSize(bytes.NewReader(fileBytes), 4096))
+	return docs, nil
+}
+
+// EachDoc calls docFunc for each document as it's read so that whole file
+// does not have to be kept in memory (if file source supports reading incrementally)
+func (f YAMLFile) EachDoc(docFunc func([]byte) error) error {
+	var fileReader io.Reader
+
+	if readerSrc, ok := f.fileSrc.(ReaderFileSource); ok {
+		readCloser, err := readerSrc.Open()
+		if err != nil {
+			return err
+		}
+		defer readCloser.Close()
+		fileReader = readCloser
+	} else {
+		fileBytes, err := f.fileSrc.Bytes()
+		if err != nil {
+			return err
+		}
+		fileReader = bytes.NewReader(fileBytes)
+	}
+
+	reader := kyaml.NewYAMLReader(bufio.NewReaderSize(fileReader, 4096))
 
 	for {
 		docBytes, err := reader.Read()
 		if err == io.EOF {
-			break
+			return nil
 		}
 		if err != nil {
-			return nil, err
+			return err
+		}
+		err = docFunc(docBytes)
+		if err != nil {
+			return err
 		}
-		docs = append(docs, docBytes)
 	}
-
-	return docs, nil
 }
@@ -13,6 +13,10 @@ type Conditions struct {
 	resource ctlres.Resource
 }
 
+func NewConditions(resource ctlres.Resource) Conditions {
+	return Conditions{resource}
+}
+
 func (c Conditions) IsSelectedTrue(checkedTypes []string) (bool, string) {
 	statuses := c.statuses()
 
@@ -8,23 +8,66 @@ import (
 
 	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	coreV1ServiceWaitEndpointsAnnKey = "kapp.k14s.io/core-v1-service-wait-endpoints" // valid value is ''
+)
+
+var (
+	// EndpointSlices are not labeled with kapp's association label,
+	// hence Service associated resources are found via service name label
+	CoreV1ServiceEndpointSlicesRef = ctlres.ResourceRef{
+		schema.GroupVersionResource{Group: "discovery.k8s.io", Resource: "endpointslices"}}
 )
 
 type CoreV1Service struct {
-	resource ctlres.Resource
+	resource         ctlres.Resource
+	associatedRs     []ctlres.Resource
+	waitForEndpoints bool
 }
 
-func NewCoreV1Service(resource ctlres.Resource) *CoreV1Service {
+func NewCoreV1Service(resource ctlres.Resource, associatedRs []ctlres.Resource, waitForEndpoints bool) *CoreV1Service {
 	matcher := ctlres.APIVersionKindMatcher{
 		APIVersion: "v1",
 		Kind:       "Service",
 	}
 	if matcher.Matches(resource) {
-		return &CoreV1Service{resource}
+		return &CoreV1Service{resource, associatedRs, waitForEndpoints}
 	}
 	return nil
 }
 
+// WaitsForEndpoints indicates whether Service is only considered
+// ready once it has at least one ready endpoint address.
+// Services without selector only wait when annotated since their
+// endpoints are managed externally (and may never show up).
+func (s CoreV1Service) WaitsForEndpoints() bool {
+	if _, found := s.resource.Annotations()[coreV1ServiceWaitEndpointsAnnKey]; found {
+		return true
+	}
+	if !s.waitForEndpoints {
+		return false
+	}
+
+	svc := corev1.Service{}
+
+	err := s.resource.AsTypedObj(&svc)
+	if err != nil {
+		// Conversion error is reported when checking if done applying
+		return false
+	}
+
+	return len(svc.Spec.Selector) > 0
+}
+
+// EndpointSlicesSelector returns labels that EndpointSlices of this Service carry
+func (s CoreV1Service) EndpointSlicesSelector() map[string]string {
+	return map[string]string{discoveryv1.LabelServiceName: s.resource.Name()}
+}
+
 func (s CoreV1Service) IsDoneApplying() DoneApplyState {
 	svc := corev1.Service{}
 
@@ -47,5 +90,35 @@ func (s CoreV1Service) IsDoneApplying() DoneApplyState {
 		}
 	}
 
+	if s.WaitsForEndpoints() {
+		return s.isEndpointReady()
+	}
+
 	return DoneApplyState{Done: true, Successful: true}
 }
+
+func (s CoreV1Service) isEndpointReady() DoneApplyState {
+	for _, res := range s.associatedRs {
+		if res.Namespace() != s.resource.Namespace() || res.Kind() != "EndpointSlice" {
+			continue
+		}
+
+		slice := discoveryv1.EndpointSlice{}
+
+		err := res.AsTypedObj(&slice)
+		if err != nil {
+			return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf("Error: Failed obj conversion: %s", err)}
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			// Nil ready condition should be interpreted as ready
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				if len(endpoint.Addresses) > 0 {
+					return DoneApplyState{Done: true, Successful: true}
+				}
+			}
+		}
+	}
+
+	return DoneApplyState{Done: false, Message: "Waiting for at least one ready endpoint"}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+)
+
+func TestCoreV1ServiceWaitForEndpoints(t *testing.T) {
+	svcData := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis
+  namespace: default
+spec:
+  clusterIP: 10.0.0.1
+  selector:
+    app: redis
+`
+
+	state := buildService(svcData, nil, false, t).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true}, state)
+
+	state = buildService(svcData, nil, true, t).IsDoneApplying()
+	expectedState := ctlresm.DoneApplyState{
+		Done:       false,
+		Successful: false,
+		Message:    "Waiting for at least one ready endpoint",
+	}
+	require.Equal(t, expectedState, state)
+
+	notReadySliceData := `
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: redis-abc
+  namespace: default
+addressType: IPv4
+endpoints:
+- addresses: [10.1.0.1]
+  conditions:
+    ready: false
+`
+
+	state = buildService(svcData, []string{notReadySliceData}, true, t).IsDoneApplying()
+	require.Equal(t, expectedState, state)
+
+	readySliceData := `
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: redis-def
+  namespace: default
+addressType: IPv4
+endpoints:
+- addresses: [10.1.0.2]
+  conditions:
+    ready: true
+`
+
+	state = buildService(svcData, []string{notReadySliceData, readySliceData}, true, t).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true}, state)
+}
+
+func TestCoreV1ServiceWaitForEndpointsAnnotation(t *testing.T) {
+	svcData := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis
+  namespace: default
+  annotations:
+    kapp.k14s.io/core-v1-service-wait-endpoints: ""
+spec:
+  clusterIP: 10.0.0.1
+`
+
+	svc := buildService(svcData, nil, false, t)
+	require.True(t, svc.WaitsForEndpoints())
+	require.False(t, svc.IsDoneApplying().Done)
+}
+
+func TestCoreV1ServiceWaitForEndpointsWithoutSelector(t *testing.T) {
+	svcData := `
+apiVersion: v1
+kind: Service
+metadata:
+  name: external
+  namespace: default
+spec:
+  clusterIP: 10.0.0.1
+`
+
+	// Endpoints of Services without selector are managed externally
+	svc := buildService(svcData, nil, true, t)
+	require.False(t, svc.WaitsForEndpoints())
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true}, svc.IsDoneApplying())
+}
+
+func buildService(svcBs string, sliceBss []string, waitForEndpoints bool, t *testing.T) *ctlresm.CoreV1Service {
+	svcResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(svcBs))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	var associatedRs []ctlres.Resource
+	for _, sliceBs := range sliceBss {
+		sliceResources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(sliceBs))).Resources()
+		require.NoErrorf(t, err, "Expected resources to parse")
+		associatedRs = append(associatedRs, sliceResources...)
+	}
+
+	return ctlresm.NewCoreV1Service(svcResources[0], associatedRs, waitForEndpoints)
+}
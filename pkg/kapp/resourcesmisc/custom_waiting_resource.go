@@ -14,12 +14,15 @@ import (
 type CustomWaitingResource struct {
 	resource ctlres.Resource
 	waitRule ctlconf.WaitRule
+	plugins  *WaitRulePlugins
 }
 
-func NewCustomWaitingResource(resource ctlres.Resource, waitRules []ctlconf.WaitRule) *CustomWaitingResource {
+func NewCustomWaitingResource(resource ctlres.Resource, waitRules []ctlconf.WaitRule,
+	plugins *WaitRulePlugins) *CustomWaitingResource {
+
 	for _, rule := range waitRules {
 		if rule.ResourceMatcher().Matches(resource) {
-			return &CustomWaitingResource{resource, rule}
+			return &CustomWaitingResource{resource, rule, plugins}
 		}
 	}
 	return nil
@@ -55,6 +58,34 @@ func (s CustomWaitingResource) IsDoneApplying() DoneApplyState {
 			"Waiting for generation %d to be observed", obj.Metadata.Generation)}
 	}
 
+	if s.waitRule.Plugin != nil {
+		configObj, err := s.plugins.Apply(s.waitRule.Plugin, s.resource)
+		if err != nil {
+			return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf(
+				"Error: Applying plugin wait rule: %s", err.Error())}
+		}
+		message := configObj.Message
+		if configObj.UnblockChanges {
+			message = fmt.Sprintf("Allowing blocked changes to proceed: %s", configObj.Message)
+		}
+		return DoneApplyState{Done: configObj.Done, Successful: configObj.Successful,
+			UnblockChanges: configObj.UnblockChanges, Message: message}
+	}
+
+	if s.waitRule.Exec != nil {
+		configObj, err := WaitRuleExec{*s.waitRule.Exec}.Apply(s.resource)
+		if err != nil {
+			return DoneApplyState{Done: true, Successful: false, Message: fmt.Sprintf(
+				"Error: Applying exec wait rule: %s", err.Error())}
+		}
+		message := configObj.Message
+		if configObj.UnblockChanges {
+			message = fmt.Sprintf("Allowing blocked changes to proceed: %s", configObj.Message)
+		}
+		return DoneApplyState{Done: configObj.Done, Successful: configObj.Successful,
+			UnblockChanges: configObj.UnblockChanges, Message: message}
+	}
+
 	if s.waitRule.Ytt != nil {
 		configObj, err := WaitRuleContractV1{
 			ResourceMatcher: ctlres.AnyMatcher{
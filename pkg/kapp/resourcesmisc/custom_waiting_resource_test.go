@@ -0,0 +1,55 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+)
+
+func TestCustomWaitingResourceExec(t *testing.T) {
+	resData := `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+status:
+  phase: Ready
+`
+
+	resources, err := ctlres.NewFileResource(ctlres.NewBytesSource([]byte(resData))).Resources()
+	require.NoErrorf(t, err, "Expected resources to parse")
+
+	newWaitRule := func(script string) ctlconf.WaitRule {
+		return ctlconf.WaitRule{
+			ResourceMatchers: []ctlconf.ResourceMatcher{{
+				APIVersionKindMatcher: &ctlconf.APIVersionKindMatcher{APIVersion: "example.com/v1", Kind: "Widget"},
+			}},
+			Exec: &ctlconf.WaitRuleExec{Command: "sh", Args: []string{"-c", script}},
+		}
+	}
+
+	// Resource is passed via stdin
+	rule := newWaitRule(`grep -q '"phase":"Ready"' && echo '{"done": true, "successful": true, "message": "is ready"}'`)
+
+	state := ctlresm.NewCustomWaitingResource(resources[0], []ctlconf.WaitRule{rule}, nil).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: true, Successful: true, Message: "is ready"}, state)
+
+	rule = newWaitRule(`echo '{"done": false, "message": "in progress"}'`)
+
+	state = ctlresm.NewCustomWaitingResource(resources[0], []ctlconf.WaitRule{rule}, nil).IsDoneApplying()
+	require.Equal(t, ctlresm.DoneApplyState{Done: false, Successful: false, Message: "in progress"}, state)
+
+	rule = newWaitRule(`echo failed >&2; exit 1`)
+
+	state = ctlresm.NewCustomWaitingResource(resources[0], []ctlconf.WaitRule{rule}, nil).IsDoneApplying()
+	require.True(t, state.Done)
+	require.False(t, state.Successful)
+	require.Contains(t, state.Message, "Error: Applying exec wait rule")
+	require.Contains(t, state.Message, "stderr: failed")
+}
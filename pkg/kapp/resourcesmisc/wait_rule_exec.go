@@ -0,0 +1,75 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+const (
+	waitRuleExecDefaultTimeout = time.Minute
+)
+
+// WaitRuleExec determines resource state by running external command.
+// Result is expected in the same shape as ytt based wait rules return.
+type WaitRuleExec struct {
+	Exec ctlconf.WaitRuleExec
+}
+
+func (t WaitRuleExec) Apply(res ctlres.Resource) (*WaitRuleContractV1ResultDetails, error) {
+	resBs, err := json.Marshal(res.DeepCopyRaw())
+	if err != nil {
+		return nil, fmt.Errorf("Serializing resource: %w", err)
+	}
+
+	timeout := waitRuleExecDefaultTimeout
+	if len(t.Exec.Timeout) > 0 {
+		timeout, err = time.ParseDuration(t.Exec.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing timeout: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, t.Exec.Command, t.Exec.Args...)
+	cmd.Stdin = bytes.NewReader(resBs)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = os.Environ()
+
+	for k, v := range t.Exec.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	err = cmd.Run()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("Running '%s': timed out after %s", t.Exec.Command, timeout)
+		}
+		return nil, fmt.Errorf("Running '%s': %w (stderr: %s)", t.Exec.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result WaitRuleContractV1ResultDetails
+
+	err = json.Unmarshal(stdout.Bytes(), &result)
+	if err != nil {
+		return nil, fmt.Errorf("Deserializing result of '%s': %w", t.Exec.Command, err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlplugin "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/experimental/plugin"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+// WaitRulePlugins determines resource state by calling plugin's wait evaluator.
+// Plugin process is started once per wait rule and reused across checks
+// (it is restarted if it exits or times out); Close stops all plugin processes.
+type WaitRulePlugins struct {
+	clients     map[*ctlconf.WaitRulePlugin]*ctlplugin.Client
+	clientsLock sync.Mutex
+}
+
+func NewWaitRulePlugins() *WaitRulePlugins {
+	return &WaitRulePlugins{clients: map[*ctlconf.WaitRulePlugin]*ctlplugin.Client{}}
+}
+
+func (p *WaitRulePlugins) Apply(plugin *ctlconf.WaitRulePlugin, res ctlres.Resource) (*WaitRuleContractV1ResultDetails, error) {
+	client, err := p.client(plugin)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ctlplugin.WaitResponse
+
+	err = client.Call(ctlplugin.CapabilityWaitEvaluator, ctlplugin.WaitRequest{Resource: res.DeepCopyRaw()}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WaitRuleContractV1ResultDetails{
+		Done:           result.Done,
+		Successful:     result.Successful,
+		Message:        result.Message,
+		UnblockChanges: result.UnblockChanges,
+	}, nil
+}
+
+// Close stops started plugin processes; plugins are started again if needed
+func (p *WaitRulePlugins) Close() error {
+	p.clientsLock.Lock()
+	clients := p.clients
+	p.clients = map[*ctlconf.WaitRulePlugin]*ctlplugin.Client{}
+	p.clientsLock.Unlock()
+
+	var firstErr error
+
+	for _, client := range clients {
+		err := client.Close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (p *WaitRulePlugins) client(plugin *ctlconf.WaitRulePlugin) (*ctlplugin.Client, error) {
+	p.clientsLock.Lock()
+	defer p.clientsLock.Unlock()
+
+	if client, found := p.clients[plugin]; found && !client.Closed() {
+		return client, nil
+	}
+
+	timeout := waitRuleExecDefaultTimeout
+	if len(plugin.Timeout) > 0 {
+		var err error
+		timeout, err = time.ParseDuration(plugin.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("Parsing timeout: %w", err)
+		}
+	}
+
+	client, err := ctlplugin.Start(ctlplugin.Config{
+		Command: plugin.Command,
+		Args:    plugin.Args,
+		Env:     plugin.Env,
+		Timeout: timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.clients[plugin] = client
+
+	return client, nil
+}
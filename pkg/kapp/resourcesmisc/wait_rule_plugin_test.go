@@ -0,0 +1,97 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesmisc_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	ctlresm "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resourcesmisc"
+)
+
+// Fake plugin speaks JSON-RPC line by line and records each start
+const testWaitPluginScript = `#!/bin/sh
+echo started >> "$STARTS_PATH"
+while read -r line; do
+  id=$(echo "$line" | sed 's/.*"id":\([0-9]*\).*/\1/')
+  case "$line" in
+    *'"Plugin.Info"'*)
+      echo "{\"id\":$id,\"result\":{\"protocolVersion\":1,\"capabilities\":[\"waitEvaluator\"]},\"error\":null}" ;;
+    *'"crashing"'*)
+      echo "crashed" >&2
+      exit 1 ;;
+    *)
+      echo "{\"id\":$id,\"result\":{\"done\":true,\"successful\":true,\"message\":\"is ready\"},\"error\":null}" ;;
+  esac
+done
+`
+
+func TestWaitRulePluginsReuseClient(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake plugin is a shell script")
+	}
+
+	dir := t.TempDir()
+	pluginPath := filepath.Join(dir, "plugin")
+	startsPath := filepath.Join(dir, "starts")
+
+	err := os.WriteFile(pluginPath, []byte(testWaitPluginScript), 0700)
+	require.NoError(t, err)
+
+	plugin := &ctlconf.WaitRulePlugin{
+		PluginRef: ctlconf.PluginRef{Command: pluginPath, Env: map[string]string{"STARTS_PATH": startsPath}},
+	}
+	otherPlugin := &ctlconf.WaitRulePlugin{PluginRef: plugin.PluginRef}
+
+	numStarts := func() int {
+		bs, err := os.ReadFile(startsPath)
+		require.NoError(t, err)
+		return strings.Count(string(bs), "started")
+	}
+
+	plugins := ctlresm.NewWaitRulePlugins()
+	defer plugins.Close()
+
+	res := newTestWaitResource(t, "widget")
+
+	// Plugin process is reused across checks of the same rule
+	for i := 0; i < 3; i++ {
+		result, err := plugins.Apply(plugin, res)
+		require.NoError(t, err)
+		require.Equal(t, &ctlresm.WaitRuleContractV1ResultDetails{Done: true, Successful: true, Message: "is ready"}, result)
+	}
+	require.Equal(t, 1, numStarts())
+
+	// Each rule gets its own plugin process
+	_, err = plugins.Apply(otherPlugin, res)
+	require.NoError(t, err)
+	require.Equal(t, 2, numStarts())
+
+	// Plugin is restarted after it exits
+	_, err = plugins.Apply(plugin, newTestWaitResource(t, "crashing"))
+	require.ErrorContains(t, err, "(stderr: crashed)")
+
+	_, err = plugins.Apply(plugin, res)
+	require.NoError(t, err)
+	require.Equal(t, 3, numStarts())
+
+	// Closing stops plugins; they are started again if needed
+	require.NoError(t, plugins.Close())
+
+	_, err = plugins.Apply(plugin, res)
+	require.NoError(t, err)
+	require.Equal(t, 4, numStarts())
+}
+
+func newTestWaitResource(t *testing.T, name string) ctlres.Resource {
+	res, err := ctlres.NewResourceFromBytes([]byte(`{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "` + name + `"}}`))
+	require.NoError(t, err)
+	return res
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sdk allows to deploy, diff, delete and inspect apps from Go programs
+// (e.g. controllers) without running kapp CLI. Functions accept in-memory
+// resources and typed options, and return results that match documents
+// printed by corresponding commands with --output json.
+//
+// Unlike other kapp packages, exported API of this package is kept stable.
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/core"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/logger"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+const defaultNamespace = "default"
+
+type ClientOpts struct {
+	// RESTConfig is used to connect to the cluster (required)
+	RESTConfig *rest.Config
+	// Namespace is used for apps and resources that do not specify it
+	// (defaults to 'default')
+	Namespace string
+	// Output receives human oriented output (e.g. diffs, progress);
+	// it is discarded if not set
+	Output io.Writer
+	// Debug includes debug logs in output
+	Debug bool
+}
+
+// Client is safe to use from multiple goroutines
+// as long as operations target different apps
+type Client struct {
+	opts          ClientOpts
+	ui            ui.UI
+	logger        logger.Logger
+	configFactory cmdcore.ConfigFactory
+}
+
+func NewClient(opts ClientOpts) (*Client, error) {
+	if opts.RESTConfig == nil {
+		return nil, fmt.Errorf("Expected RESTConfig to be specified")
+	}
+	if len(opts.Namespace) == 0 {
+		opts.Namespace = defaultNamespace
+	}
+	if opts.Output == nil {
+		opts.Output = io.Discard
+	}
+
+	// Operations are never interactive (e.g. changes are not confirmed)
+	clientUI := ui.NewNonInteractiveUI(ui.NewWriterUI(opts.Output, opts.Output, ui.NewNoopLogger()))
+
+	uiLogger := logger.NewUILogger(clientUI)
+	uiLogger.SetDebug(opts.Debug)
+
+	return &Client{
+		opts:          opts,
+		ui:            clientUI,
+		logger:        uiLogger,
+		configFactory: restConfigFactory{opts.RESTConfig, opts.Namespace},
+	}, nil
+}
+
+// deps returns new dependencies for each operation since
+// some of them are configured by operations (e.g. warnings)
+func (c *Client) deps() (cmdcore.DepsFactory, cmdcore.FlagsFactory) {
+	depsFactory := cmdcore.NewDepsFactoryImpl(c.configFactory, c.ui)
+	depsFactory.ConfigurePrintTarget(false)
+	return depsFactory, cmdcore.NewFlagsFactory(c.configFactory, depsFactory)
+}
+
+func (c *Client) namespace(namespace string) string {
+	if len(namespace) > 0 {
+		return namespace
+	}
+	return c.opts.Namespace
+}
+
+// documentFunc decodes documents produced by commands into given result
+func documentFunc(result interface{}) func(interface{}) error {
+	return func(obj interface{}) error {
+		bs, err := json.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("Marshaling result: %w", err)
+		}
+		err = json.Unmarshal(bs, result)
+		if err != nil {
+			return fmt.Errorf("Unmarshaling result: %w", err)
+		}
+		return nil
+	}
+}
+
+func newResources(objs []unstructured.Unstructured) []ctlres.Resource {
+	// Empty (but not nil) list indicates that all resources should be deleted
+	result := []ctlres.Resource{}
+	for _, obj := range objs {
+		result = append(result, ctlres.NewResourceUnstructured(*obj.DeepCopy(), ctlres.ResourceType{}))
+	}
+	return result
+}
+
+// restConfigFactory provides given config instead of loading kubeconfig
+type restConfigFactory struct {
+	config    *rest.Config
+	namespace string
+}
+
+var _ cmdcore.ConfigFactory = restConfigFactory{}
+
+func (restConfigFactory) ConfigurePathResolver(func() (string, error))    {}
+func (restConfigFactory) ConfigureContextResolver(func() (string, error)) {}
+func (restConfigFactory) ConfigureYAMLResolver(func() (string, error))    {}
+func (restConfigFactory) ConfigureClient(float32, int)                    {}
+func (restConfigFactory) ConfigureClientMaxInflight(int)                  {}
+func (restConfigFactory) ConfigureClientProtobuf(bool)                    {}
+
+func (f restConfigFactory) RESTConfig() (*rest.Config, error) { return rest.CopyConfig(f.config), nil }
+func (f restConfigFactory) DefaultNamespace() (string, error) { return f.namespace, nil }
+
+func (f restConfigFactory) WithContext(string) cmdcore.ConfigFactory { return f }
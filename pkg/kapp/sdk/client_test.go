@@ -0,0 +1,52 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestNewClientRequiresRESTConfig(t *testing.T) {
+	_, err := NewClient(ClientOpts{})
+	require.EqualError(t, err, "Expected RESTConfig to be specified")
+
+	client, err := NewClient(ClientOpts{RESTConfig: &rest.Config{Host: "https://example.com"}})
+	require.NoError(t, err)
+	require.Equal(t, "default", client.namespace(""))
+	require.Equal(t, "ns1", client.namespace("ns1"))
+}
+
+func TestDocumentFuncDecodesAppChangeResult(t *testing.T) {
+	doc := map[string]interface{}{
+		"app":       map[string]interface{}{"name": "app1", "namespace": "default"},
+		"operation": "deploy",
+		"summary": map[string]interface{}{
+			"ops":     map[string]int{"create": 1, "noop": 1},
+			"waitOps": map[string]int{"reconcile": 1, "noop": 1},
+		},
+		"changes": []interface{}{
+			map[string]interface{}{"name": "cm1", "kind": "ConfigMap", "apiVersion": "v1", "op": "noop", "waitOp": "noop"},
+			map[string]interface{}{"namespace": "default", "name": "cm2", "kind": "ConfigMap",
+				"apiVersion": "v1", "op": "create", "waitOp": "reconcile", "applyResult": "succeeded"},
+		},
+		"successful": true,
+	}
+
+	result := &AppChangeResult{}
+	require.NoError(t, documentFunc(result)(doc))
+
+	require.Equal(t, AppMeta{Name: "app1", Namespace: "default"}, result.App)
+	require.Equal(t, 1, result.Summary.Ops["create"])
+	require.Len(t, result.Changes, 2)
+	require.Equal(t, ResourceRef{Namespace: "default", Name: "cm2", Kind: "ConfigMap", APIVersion: "v1"}, result.Changes[1].ResourceRef)
+	require.Equal(t, "succeeded", result.Changes[1].ApplyResult)
+	require.True(t, result.Successful)
+	require.True(t, result.HasChanges())
+
+	result.Changes = result.Changes[:1]
+	require.False(t, result.HasChanges())
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"fmt"
+	"time"
+
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+)
+
+type DeleteOpts struct {
+	// App name (required)
+	App string
+	// Namespace where app is recorded (defaults to client's namespace)
+	Namespace string
+
+	// Unprotect allows deleting app that was deployed with protection
+	Unprotect bool
+
+	// DisableWait skips waiting for resources to be deleted
+	DisableWait bool
+	// WaitTimeout limits time spent waiting in wait phase (defaults to 15m)
+	WaitTimeout time.Duration
+
+	// Observer (optional) is notified about progress of changes
+	Observer ProgressObserver
+
+	// DryRun calculates changes without deleting resources
+	DryRun bool
+}
+
+// Delete deletes all app resources and app record
+func (c *Client) Delete(opts DeleteOpts) (*AppChangeResult, error) {
+	if len(opts.App) == 0 {
+		return nil, fmt.Errorf("Expected app name to be specified")
+	}
+
+	depsFactory, flagsFactory := c.deps()
+
+	deleteOpts := cmdapp.NewDeleteOptions(c.ui, depsFactory, c.logger)
+	// Populates defaults of all flags
+	cmdapp.NewDeleteCmd(deleteOpts, flagsFactory)
+
+	deleteOpts.AppFlags.Name = opts.App
+	deleteOpts.AppFlags.NamespaceFlags.Name = c.namespace(opts.Namespace)
+	deleteOpts.Unprotect = opts.Unprotect
+
+	deleteOpts.ApplyFlags.Wait = !opts.DisableWait
+	if opts.WaitTimeout > 0 {
+		deleteOpts.ApplyFlags.WaitingChangesOpts.Timeout = opts.WaitTimeout
+	}
+
+	deleteOpts.ApplyFlags.ProgressFunc = progressFunc(opts.Observer)
+	deleteOpts.DiffFlags.Run = opts.DryRun
+
+	return c.runAppChange(&deleteOpts.OutputFlags, deleteOpts.Run)
+}
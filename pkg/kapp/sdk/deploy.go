@@ -0,0 +1,110 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"fmt"
+	"time"
+
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type DeployOpts struct {
+	// App name (required)
+	App string
+	// Namespace where app is recorded (defaults to client's namespace)
+	Namespace string
+
+	// Resources to deploy; kapp config resources are recognized as well.
+	// Resources of the app that are not included are deleted (unless Patch is set).
+	Resources []unstructured.Unstructured
+
+	// IntoNamespace places all namespaced resources into given namespace
+	IntoNamespace string
+	// Labels are added to all app resources (format: key=val)
+	Labels []string
+
+	// Patch only adds or updates given resources instead of replacing all app resources
+	Patch bool
+	// Incremental skips diffing resources unchanged since last deploy
+	Incremental bool
+	// OverrideOwnershipOfExistingResources allows to take over resources owned by other apps
+	OverrideOwnershipOfExistingResources bool
+
+	// DisableWait skips waiting for resources to reconcile
+	DisableWait bool
+	// WaitTimeout limits time spent waiting in wait phase (defaults to 15m)
+	WaitTimeout time.Duration
+	// Timeout limits time of whole deploy (no limit by default)
+	Timeout time.Duration
+
+	// Observer (optional) is notified about progress of changes
+	Observer ProgressObserver
+}
+
+// Deploy creates or updates app so that it consists of given resources.
+// Result is returned even if deploy failed as long as changes were calculated.
+func (c *Client) Deploy(opts DeployOpts) (*AppChangeResult, error) {
+	return c.deploy(opts, false)
+}
+
+// Diff calculates changes that Deploy would apply without applying them
+func (c *Client) Diff(opts DeployOpts) (*AppChangeResult, error) {
+	return c.deploy(opts, true)
+}
+
+func (c *Client) deploy(opts DeployOpts, diffOnly bool) (*AppChangeResult, error) {
+	if len(opts.App) == 0 {
+		return nil, fmt.Errorf("Expected app name to be specified")
+	}
+
+	depsFactory, flagsFactory := c.deps()
+
+	deployOpts := cmdapp.NewDeployOptions(c.ui, depsFactory, c.logger)
+	// Populates defaults of all flags
+	cmdapp.NewDeployCmd(deployOpts, flagsFactory)
+
+	deployOpts.AppFlags.Name = opts.App
+	deployOpts.AppFlags.NamespaceFlags.Name = c.namespace(opts.Namespace)
+	deployOpts.Resources = newResources(opts.Resources)
+
+	deployOpts.DeployFlags.IntoNamespace = opts.IntoNamespace
+	deployOpts.LabelFlags.Labels = opts.Labels
+	deployOpts.DeployFlags.Patch = opts.Patch
+	deployOpts.DeployFlags.Incremental = opts.Incremental
+	deployOpts.DeployFlags.OverrideOwnershipOfExistingResources = opts.OverrideOwnershipOfExistingResources
+	deployOpts.DeployFlags.DeployTimeout = opts.Timeout
+	// Pod logs are only useful for interactive use
+	deployOpts.DeployFlags.Logs = false
+
+	deployOpts.ApplyFlags.Wait = !opts.DisableWait
+	if opts.WaitTimeout > 0 {
+		deployOpts.ApplyFlags.WaitingChangesOpts.Timeout = opts.WaitTimeout
+	}
+
+	deployOpts.ApplyFlags.ProgressFunc = progressFunc(opts.Observer)
+	deployOpts.DiffFlags.Run = diffOnly
+
+	return c.runAppChange(&deployOpts.OutputFlags, deployOpts.Run)
+}
+
+func (c *Client) runAppChange(outputFlags *cmdapp.OutputFlags, runFunc func() error) (*AppChangeResult, error) {
+	var result *AppChangeResult
+
+	outputFlags.DocumentFunc = func(obj interface{}) error {
+		result = &AppChangeResult{}
+		return documentFunc(result)(obj)
+	}
+
+	err := runFunc()
+	if err != nil {
+		return result, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("Expected command to produce result")
+	}
+
+	return result, nil
+}
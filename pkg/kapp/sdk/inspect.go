@@ -0,0 +1,48 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"fmt"
+
+	cmdapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/cmd/app"
+)
+
+type InspectOpts struct {
+	// App name (required)
+	App string
+	// Namespace where app is recorded (defaults to client's namespace)
+	Namespace string
+
+	// IncludeOwned includes resources owned by app resources
+	// (e.g. Pods created for Deployments)
+	IncludeOwned bool
+}
+
+// Inspect returns app resources sorted by namespace, name, kind and apiVersion
+func (c *Client) Inspect(opts InspectOpts) ([]InspectedResource, error) {
+	if len(opts.App) == 0 {
+		return nil, fmt.Errorf("Expected app name to be specified")
+	}
+
+	depsFactory, flagsFactory := c.deps()
+
+	inspectOpts := cmdapp.NewInspectOptions(c.ui, depsFactory, c.logger)
+	// Populates defaults of all flags
+	cmdapp.NewInspectCmd(inspectOpts, flagsFactory)
+
+	inspectOpts.AppFlags.Name = opts.App
+	inspectOpts.AppFlags.NamespaceFlags.Name = c.namespace(opts.Namespace)
+	inspectOpts.IncludeOwned = opts.IncludeOwned
+
+	result := []InspectedResource{}
+	inspectOpts.OutputFlags.DocumentFunc = documentFunc(&result)
+
+	err := inspectOpts.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+// ProgressObserver is notified as changes are applied and resources reconcile
+// (e.g. to report progress in custom frontends). Embed NoopProgressObserver
+// to only implement some methods.
+type ProgressObserver = ctlcap.ProgressObserver
+
+type NoopProgressObserver = ctlcap.NoopProgressObserver
+
+type ProgressEvent = ctlcap.ProgressEvent
+
+type ProgressEventResource = ctlcap.ProgressEventResource
+
+func progressFunc(observer ProgressObserver) ctlcap.ProgressEventFunc {
+	if observer == nil {
+		return nil
+	}
+	return ctlcap.NewObserverProgressFunc(observer)
+}
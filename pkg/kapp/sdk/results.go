@@ -0,0 +1,84 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"time"
+
+	ctlcap "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/clusterapply"
+)
+
+// Results are decoded from documents printed by commands with --output json,
+// hence fields (and their JSON tags) must match ones in cmd/app/structured_output.go
+
+// AppChangeResult describes changes calculated (and applied) by Deploy, Diff and Delete
+type AppChangeResult struct {
+	App        AppMeta          `json:"app"`
+	Operation  string           `json:"operation"`
+	DryRun     bool             `json:"dryRun,omitempty"`
+	Summary    AppChangeSummary `json:"summary"`
+	Changes    []ResourceChange `json:"changes"`
+	Successful bool             `json:"successful"`
+	Error      string           `json:"error,omitempty"`
+	LastChange *AppLastChange   `json:"lastChange,omitempty"`
+}
+
+type AppMeta struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+type AppChangeSummary struct {
+	Description string `json:"description,omitempty"`
+	// Ops and WaitOps count changes by their operation (e.g. create, noop)
+	Ops     map[string]int `json:"ops"`
+	WaitOps map[string]int `json:"waitOps"`
+}
+
+type ResourceChange struct {
+	ResourceRef
+
+	Op          string `json:"op"`
+	WaitOp      string `json:"waitOp"`
+	ApplyResult string `json:"applyResult,omitempty"`
+	ApplyError  string `json:"applyError,omitempty"`
+	WaitResult  string `json:"waitResult,omitempty"`
+	WaitMessage string `json:"waitMessage,omitempty"`
+	WaitError   string `json:"waitError,omitempty"`
+	NonBlocking bool   `json:"nonBlockingWait,omitempty"`
+}
+
+type ResourceRef struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+}
+
+type AppLastChange struct {
+	Name       string     `json:"name"`
+	Successful *bool      `json:"successful,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// HasChanges indicates that at least one resource needs to be (or was) changed
+func (r AppChangeResult) HasChanges() bool {
+	for _, change := range r.Changes {
+		if change.Op != string(ctlcap.ClusterChangeApplyOpNoop) || change.WaitOp != string(ctlcap.ClusterChangeWaitOpNoop) {
+			return true
+		}
+	}
+	return false
+}
+
+// InspectedResource describes app resource returned by Inspect
+type InspectedResource struct {
+	ResourceRef
+
+	Owner          string     `json:"owner,omitempty"`
+	ReconcileState string     `json:"reconcileState,omitempty"`
+	ReconcileInfo  string     `json:"reconcileInfo,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+// Provider looks up secret values in external secret store
+type Provider interface {
+	Lookup(ref ctlconf.SecretRef) (string, error)
+}
+
+type ProviderOpts struct {
+	HTTPClient   *http.Client
+	AWSBinary    string
+	GCloudBinary string
+}
+
+func NewProvider(config ctlconf.SecretProvider, opts ProviderOpts) (Provider, error) {
+	switch {
+	case config.Vault != nil:
+		return NewVault(*config.Vault, opts.HTTPClient), nil
+	case config.AWSSecretsManager != nil:
+		return NewAWSSecretsManager(*config.AWSSecretsManager, opts.AWSBinary), nil
+	case config.GCPSecretManager != nil:
+		return NewGCPSecretManager(*config.GCPSecretManager, opts.GCloudBinary), nil
+	default:
+		return nil, fmt.Errorf("Unknown secret provider '%s'", config.Name)
+	}
+}
+
+type AWSSecretsManager struct {
+	config ctlconf.SecretProviderAWSSecretsManager
+	binary string
+}
+
+var _ Provider = AWSSecretsManager{}
+
+func NewAWSSecretsManager(config ctlconf.SecretProviderAWSSecretsManager, binary string) AWSSecretsManager {
+	if len(binary) == 0 {
+		binary = "aws"
+	}
+	return AWSSecretsManager{config, binary}
+}
+
+func (p AWSSecretsManager) Lookup(ref ctlconf.SecretRef) (string, error) {
+	args := []string{"secretsmanager", "get-secret-value", "--secret-id", ref.Key,
+		"--query", "SecretString", "--output", "text"}
+	if len(ref.Version) > 0 {
+		args = append(args, "--version-stage", ref.Version)
+	}
+	if len(p.config.Region) > 0 {
+		args = append(args, "--region", p.config.Region)
+	}
+	if len(p.config.Profile) > 0 {
+		args = append(args, "--profile", p.config.Profile)
+	}
+
+	val, err := run(p.binary, args)
+	if err != nil {
+		return "", err
+	}
+
+	return jsonField(val, ref.Field)
+}
+
+type GCPSecretManager struct {
+	config ctlconf.SecretProviderGCPSecretManager
+	binary string
+}
+
+var _ Provider = GCPSecretManager{}
+
+func NewGCPSecretManager(config ctlconf.SecretProviderGCPSecretManager, binary string) GCPSecretManager {
+	if len(binary) == 0 {
+		binary = "gcloud"
+	}
+	return GCPSecretManager{config, binary}
+}
+
+func (p GCPSecretManager) Lookup(ref ctlconf.SecretRef) (string, error) {
+	version := ref.Version
+	if len(version) == 0 {
+		version = "latest"
+	}
+
+	args := []string{"secrets", "versions", "access", version, "--secret", ref.Key}
+	if len(p.config.Project) > 0 {
+		args = append(args, "--project", p.config.Project)
+	}
+
+	val, err := run(p.binary, args)
+	if err != nil {
+		return "", err
+	}
+
+	return jsonField(val, ref.Field)
+}
+
+func run(binary string, args []string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("Running '%s %s': %w (stderr: %s)",
+			binary, strings.Join(args[:2], " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
+
+// jsonField returns value of a field if secret value is a JSON object
+func jsonField(val, field string) (string, error) {
+	if len(field) == 0 {
+		return val, nil
+	}
+
+	var obj map[string]interface{}
+
+	err := json.Unmarshal([]byte(val), &obj)
+	if err != nil {
+		return "", fmt.Errorf("Expected secret value to be JSON object to select field '%s'", field)
+	}
+
+	return fieldValue(obj, field)
+}
+
+func fieldValue(obj map[string]interface{}, field string) (string, error) {
+	val, found := obj[field]
+	if !found {
+		return "", fmt.Errorf("Expected secret to include field '%s'", field)
+	}
+	if str, ok := val.(string); ok {
+		return str, nil
+	}
+
+	bs, err := json.Marshal(val)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
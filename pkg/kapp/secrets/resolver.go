@@ -0,0 +1,193 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+var (
+	// Matches $${secret:...} (escaped) and ${secret:name}
+	secretRefRegexp = regexp.MustCompile(`\$\$\{secret:|\$\{secret:([A-Za-z0-9_.-]+)\}`)
+)
+
+// Resolver replaces secret references within string values of resources
+// with values looked up via configured providers
+type Resolver struct {
+	providers map[string]Provider
+	refs      map[string]ctlconf.SecretRef
+
+	values map[string]string
+}
+
+func NewResolver(providers map[string]Provider, refs []ctlconf.SecretRef) *Resolver {
+	refsByName := map[string]ctlconf.SecretRef{}
+	for _, ref := range refs {
+		refsByName[ref.Name] = ref
+	}
+	return &Resolver{providers: providers, refs: refsByName, values: map[string]string{}}
+}
+
+func NewResolverFromConfigs(configs []ctlconf.SecretProviders, opts ProviderOpts) (*Resolver, error) {
+	providers := map[string]Provider{}
+	var refs []ctlconf.SecretRef
+
+	for _, config := range configs {
+		for _, providerConfig := range config.Providers {
+			provider, err := NewProvider(providerConfig, opts)
+			if err != nil {
+				return nil, err
+			}
+			providers[providerConfig.Name] = provider
+		}
+		refs = append(refs, config.Secrets...)
+	}
+
+	return NewResolver(providers, refs), nil
+}
+
+// Resolve modifies resources in place and returns diff mask rules
+// that cover fields holding resolved values
+func (r *Resolver) Resolve(resources []ctlres.Resource) ([]ctlconf.DiffMaskRule, error) {
+	var rules []ctlconf.DiffMaskRule
+
+	for _, res := range resources {
+		paths, err := r.resolveInMap(res.UnstructuredObject(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("Resolving secrets in resource '%s': %w", res.Description(), err)
+		}
+		if len(paths) == 0 {
+			continue
+		}
+
+		rule := ctlconf.DiffMaskRule{
+			ResourceMatchers: []ctlconf.ResourceMatcher{{
+				KindNamespaceNameMatcher: &ctlconf.KindNamespaceNameMatcher{
+					Kind: res.Kind(), Namespace: res.Namespace(), Name: res.Name(),
+				},
+			}},
+			Enforce: true,
+		}
+		for _, path := range paths {
+			rule.Paths = append(rule.Paths, ctlres.NewPathFromInterfaces(path))
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// resolveInMap returns paths to maps with resolved values (at any depth via arrays).
+// Values at root level are resolved, but not masked since resource identity cannot be masked.
+func (r *Resolver) resolveInMap(obj map[string]interface{}, path []interface{}) ([][]interface{}, error) {
+	var result [][]interface{}
+	var masked bool
+
+	// Sorted for deterministic mask paths
+	var keys []string
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		newV, resolved, paths, err := r.resolveInValue(obj[k], append(append([]interface{}{}, path...), k))
+		if err != nil {
+			return nil, err
+		}
+		obj[k] = newV
+
+		if resolved && !masked && len(path) > 0 {
+			masked = true
+			result = append(result, path)
+		}
+		result = append(result, paths...)
+	}
+
+	return result, nil
+}
+
+// resolveInValue returns true as second value if value itself
+// (or one of array items) was a string with resolved secrets
+func (r *Resolver) resolveInValue(val interface{}, path []interface{}) (interface{}, bool, [][]interface{}, error) {
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		paths, err := r.resolveInMap(typedVal, path)
+		return typedVal, false, paths, err
+
+	case []interface{}:
+		var anyResolved bool
+		var result [][]interface{}
+
+		for i, item := range typedVal {
+			newItem, resolved, paths, err := r.resolveInValue(item, append(append([]interface{}{}, path...), i))
+			if err != nil {
+				return nil, false, nil, err
+			}
+			typedVal[i] = newItem
+			anyResolved = anyResolved || resolved
+			result = append(result, paths...)
+		}
+		return typedVal, anyResolved, result, nil
+
+	case string:
+		str, resolved, err := r.resolveInString(typedVal)
+		return str, resolved, nil, err
+
+	default:
+		return val, false, nil, nil
+	}
+}
+
+func (r *Resolver) resolveInString(str string) (string, bool, error) {
+	var lastErr error
+	var resolved bool
+
+	result := secretRefRegexp.ReplaceAllStringFunc(str, func(ref string) string {
+		if ref == "$${secret:" {
+			return "${secret:"
+		}
+		name := secretRefRegexp.FindStringSubmatch(ref)[1]
+		val, err := r.value(name)
+		if err != nil {
+			lastErr = err
+			return ref
+		}
+		resolved = true
+		return val
+	})
+
+	return result, resolved, lastErr
+}
+
+func (r *Resolver) value(name string) (string, error) {
+	if val, found := r.values[name]; found {
+		return val, nil
+	}
+
+	ref, found := r.refs[name]
+	if !found {
+		return "", fmt.Errorf("Expected secret '%s' to be defined in kapp secret providers config", name)
+	}
+
+	provider, found := r.providers[ref.Provider]
+	if !found {
+		return "", fmt.Errorf("Expected secret provider '%s' to be defined", ref.Provider)
+	}
+
+	val, err := provider.Lookup(ref)
+	if err != nil {
+		return "", fmt.Errorf("Looking up secret '%s' via provider '%s': %w", name, ref.Provider, err)
+	}
+
+	r.values[name] = val
+
+	return val, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/secrets"
+)
+
+func TestResolverWithVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "token", r.Header.Get("X-Vault-Token"))
+		switch r.URL.Path {
+		case "/v1/secret/data/db":
+			w.Write([]byte(`{"data": {"data": {"password": "pass1"}, "metadata": {"version": 1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("TEST_VAULT_TOKEN", "token")
+
+	configRes, err := ctlres.NewResourceFromBytes([]byte(`
+apiVersion: kapp.k14s.io/v1alpha1
+kind: SecretProviders
+providers:
+- name: vault
+  vault:
+    address: ` + server.URL + `
+    tokenEnv: TEST_VAULT_TOKEN
+secrets:
+- name: db-password
+  provider: vault
+  key: secret/data/db
+  field: password
+- name: missing
+  provider: vault
+  key: secret/data/missing
+`))
+	require.NoError(t, err)
+
+	_, conf, err := ctlconf.NewConfFromResources([]ctlres.Resource{configRes})
+	require.NoError(t, err)
+
+	resolver, err := secrets.NewResolverFromConfigs(conf.SecretProviders(), secrets.ProviderOpts{})
+	require.NoError(t, err)
+
+	res, err := ctlres.NewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: ns1
+data:
+  url: postgres://user:${secret:db-password}@db
+  literal: $${secret:db-password}
+  other: value
+`))
+	require.NoError(t, err)
+
+	maskRules, err := resolver.Resolve([]ctlres.Resource{res})
+	require.NoError(t, err)
+	require.Len(t, maskRules, 1)
+
+	data := res.UnstructuredObject()["data"].(map[string]interface{})
+	require.Equal(t, "postgres://user:pass1@db", data["url"])
+	require.Equal(t, "${secret:db-password}", data["literal"])
+
+	maskedRes, err := ctldiff.NewMaskedResource(res, conf.WithDiffMaskRules(maskRules).DiffMaskRules()).Resource()
+	require.NoError(t, err)
+	require.Contains(t, maskedRes.UnstructuredObject()["data"].(map[string]interface{})["url"], "value not shown")
+	require.Equal(t, "app", maskedRes.Name())
+
+	res, err = ctlres.NewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app2
+data:
+  key: ${secret:missing}
+`))
+	require.NoError(t, err)
+
+	_, err = resolver.Resolve([]ctlres.Resource{res})
+	require.EqualError(t, err, "Resolving secrets in resource 'configmap/app2 (v1) cluster': "+
+		"Looking up secret 'missing' via provider 'vault': Requesting Vault secret 'secret/data/missing': 404 Not Found")
+}
+
+func TestAWSSecretsManagerLookup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake aws binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	argsPath := filepath.Join(dir, "args")
+
+	awsPath := filepath.Join(dir, "aws")
+	err := os.WriteFile(awsPath, []byte(`#!/bin/sh
+echo "$@" > `+argsPath+`
+echo '{"username": "admin", "password": "pass1"}'
+`), 0700)
+	require.NoError(t, err)
+
+	provider := secrets.NewAWSSecretsManager(ctlconf.SecretProviderAWSSecretsManager{Region: "us-east-1"}, awsPath)
+
+	val, err := provider.Lookup(ctlconf.SecretRef{Key: "prod/db", Field: "password"})
+	require.NoError(t, err)
+	require.Equal(t, "pass1", val)
+
+	args, err := os.ReadFile(argsPath)
+	require.NoError(t, err)
+	require.Equal(t, "secretsmanager get-secret-value --secret-id prod/db --query SecretString --output text --region us-east-1\n", string(args))
+
+	_, err = provider.Lookup(ctlconf.SecretRef{Key: "prod/db", Field: "token"})
+	require.EqualError(t, err, "Expected secret to include field 'token'")
+}
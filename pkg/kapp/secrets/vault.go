@@ -0,0 +1,100 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+)
+
+const (
+	vaultAddrEnvVar         = "VAULT_ADDR"
+	vaultDefaultTokenEnvVar = "VAULT_TOKEN"
+	vaultDefaultField       = "value"
+)
+
+// Vault reads secrets via Vault HTTP API (KV secrets engine v1 and v2)
+type Vault struct {
+	config ctlconf.SecretProviderVault
+	client *http.Client
+}
+
+var _ Provider = Vault{}
+
+func NewVault(config ctlconf.SecretProviderVault, client *http.Client) Vault {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return Vault{config, client}
+}
+
+func (p Vault) Lookup(ref ctlconf.SecretRef) (string, error) {
+	addr := p.config.Address
+	if len(addr) == 0 {
+		addr = os.Getenv(vaultAddrEnvVar)
+	}
+	if len(addr) == 0 {
+		return "", fmt.Errorf("Expected Vault address to be specified (or %s to be set)", vaultAddrEnvVar)
+	}
+
+	tokenEnv := p.config.TokenEnv
+	if len(tokenEnv) == 0 {
+		tokenEnv = vaultDefaultTokenEnvVar
+	}
+
+	url := strings.TrimSuffix(addr, "/") + "/v1/" + strings.TrimPrefix(ref.Key, "/")
+	if len(ref.Version) > 0 {
+		url += "?version=" + ref.Version
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("X-Vault-Token", os.Getenv(tokenEnv))
+	if len(p.config.Namespace) > 0 {
+		req.Header.Set("X-Vault-Namespace", p.config.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Requesting Vault secret '%s': %w", ref.Key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Requesting Vault secret '%s': %s", ref.Key, resp.Status)
+	}
+
+	var secretResp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&secretResp)
+	if err != nil {
+		return "", fmt.Errorf("Decoding Vault secret '%s': %w", ref.Key, err)
+	}
+
+	data := secretResp.Data
+
+	// KV v2 nests secret data along with its metadata
+	if nestedData, ok := data["data"].(map[string]interface{}); ok {
+		if _, found := data["metadata"]; found {
+			data = nestedData
+		}
+	}
+
+	field := ref.Field
+	if len(field) == 0 {
+		field = vaultDefaultField
+	}
+
+	return fieldValue(data, field)
+}
@@ -0,0 +1,319 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sopsinput decrypts SOPS encrypted input files.
+//
+// Files are decrypted by running external sops binary (not in-process via
+// github.com/getsops/sops/v3/decrypt since it is not a kapp dependency),
+// hence sops has to be installed on the machine running kapp.
+package sopsinput
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	sopsMetadataKey = "sops"
+	sopsMACKey      = "mac"
+
+	maskConfigName = "kapp-sops-decrypted-values"
+)
+
+var (
+	encryptedValueRegexp = regexp.MustCompile(`^ENC\[[A-Z0-9_]+,`)
+)
+
+// Decryptor decrypts SOPS encrypted files in memory via sops binary
+// (key management, e.g. age, KMS or PGP, is configured as usual for sops)
+// and keeps track of decrypted fields so that they could be masked.
+type Decryptor struct {
+	binary string
+
+	decryptedLock sync.Mutex
+	decrypted     []decryptedDoc
+}
+
+type decryptedDoc struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	// MaskPaths point to maps that hold decrypted values
+	MaskPaths [][]interface{}
+}
+
+func NewDecryptor(binary string) *Decryptor {
+	return &Decryptor{binary: binary}
+}
+
+// Source wraps file source so that its contents are decrypted if necessary
+func (d *Decryptor) Source(src ctlres.FileSource) ctlres.FileSource {
+	return decryptingSource{src, d}
+}
+
+// IsEncrypted returns true if at least one document includes SOPS metadata
+func IsEncrypted(bs []byte) (bool, error) {
+	docs, err := docs(bs)
+	if err != nil {
+		return false, err
+	}
+	for _, doc := range docs {
+		if isEncryptedDoc(doc) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MaskConfigResource returns kapp config with diff mask rules that cover
+// all decrypted values; returns nil if no files were decrypted.
+func (d *Decryptor) MaskConfigResource() (ctlres.Resource, error) {
+	d.decryptedLock.Lock()
+	defer d.decryptedLock.Unlock()
+
+	var rules []interface{}
+
+	for _, doc := range d.decrypted {
+		if len(doc.MaskPaths) == 0 {
+			continue
+		}
+
+		matchers := []interface{}{
+			map[string]interface{}{"apiVersionKindMatcher": map[string]interface{}{
+				"apiVersion": doc.APIVersion, "kind": doc.Kind}},
+			map[string]interface{}{"nameRegexMatcher": map[string]interface{}{
+				"regex": "^" + regexp.QuoteMeta(doc.Name) + "$"}},
+		}
+		// Resources without namespace are placed into default namespace later
+		if len(doc.Namespace) > 0 {
+			matchers = append(matchers, map[string]interface{}{"hasNamespaceMatcher": map[string]interface{}{
+				"names": []string{doc.Namespace}}})
+		}
+
+		rules = append(rules, map[string]interface{}{
+			"paths":            doc.MaskPaths,
+			"enforce":          true,
+			"resourceMatchers": []interface{}{map[string]interface{}{"andMatcher": map[string]interface{}{"matchers": matchers}}},
+		})
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	bs, err := json.Marshal(map[string]interface{}{
+		"apiVersion":    "kapp.k14s.io/v1alpha1",
+		"kind":          "Config",
+		"metadata":      map[string]interface{}{"name": maskConfigName},
+		"diffMaskRules": rules,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := ctlres.NewResourceFromBytes(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	res.SetOrigin("sops decrypted values")
+
+	return res, nil
+}
+
+func (d *Decryptor) decrypt(desc string, bs []byte) ([]byte, error) {
+	docs, err := docs(bs)
+	if err != nil {
+		return nil, err
+	}
+
+	var decrypted []decryptedDoc
+
+	for _, doc := range docs {
+		if !isEncryptedDoc(doc) {
+			continue
+		}
+		decryptedDoc := decryptedDoc{MaskPaths: maskPaths(doc, nil)}
+		decryptedDoc.APIVersion, _ = doc["apiVersion"].(string)
+		decryptedDoc.Kind, _ = doc["kind"].(string)
+		if meta, ok := doc["metadata"].(map[string]interface{}); ok {
+			decryptedDoc.Name, _ = meta["name"].(string)
+			decryptedDoc.Namespace, _ = meta["namespace"].(string)
+		}
+		decrypted = append(decrypted, decryptedDoc)
+	}
+
+	if len(decrypted) == 0 {
+		return bs, nil
+	}
+
+	// Encrypted contents are safe to be written to disk; decrypted ones are only kept in memory
+	file, err := os.CreateTemp("", "kapp-sops-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(bs)
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	format := "yaml"
+	if bytes.HasPrefix(bytes.TrimSpace(bs), []byte("{")) {
+		format = "json"
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(d.binary, "--decrypt", "--input-type", format, "--output-type", format, file.Name())
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("Decrypting %s: Expected sops binary '%s' to be installed "+
+				"(kapp does not decrypt SOPS files in-process): %w", desc, d.binary, err)
+		}
+		return nil, fmt.Errorf("Decrypting %s via sops: %w (stderr: %s)", desc, err, strings.TrimSpace(stderr.String()))
+	}
+
+	d.decryptedLock.Lock()
+	d.decrypted = append(d.decrypted, decrypted...)
+	d.decryptedLock.Unlock()
+
+	return stdout.Bytes(), nil
+}
+
+type decryptingSource struct {
+	src       ctlres.FileSource
+	decryptor *Decryptor
+}
+
+var _ ctlres.FileSource = decryptingSource{}
+
+func (s decryptingSource) Description() string { return s.src.Description() }
+
+func (s decryptingSource) Bytes() ([]byte, error) {
+	bs, err := s.src.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptor.decrypt(s.src.Description(), bs)
+}
+
+func docs(bs []byte) ([]map[string]interface{}, error) {
+	docBs, err := ctlres.NewYAMLFile(ctlres.NewBytesSource(bs)).Docs()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]interface{}
+
+	for _, docBs := range docBs {
+		var doc map[string]interface{}
+		// Not failing on unparseable documents since they will be reported later
+		if yaml.Unmarshal(docBs, &doc) == nil && doc != nil {
+			result = append(result, doc)
+		}
+	}
+
+	return result, nil
+}
+
+func isEncryptedDoc(doc map[string]interface{}) bool {
+	meta, ok := doc[sopsMetadataKey].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, found := meta[sopsMACKey]
+	return found
+}
+
+// maskPaths returns paths to maps that directly or indirectly (via arrays)
+// hold encrypted values. Encrypted values at root level are not masked
+// since resource identity (e.g. kind) cannot be masked.
+func maskPaths(obj map[string]interface{}, path []interface{}) [][]interface{} {
+	seen := map[string]struct{}{}
+	var result [][]interface{}
+
+	var keys []string
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if len(path) == 0 && k == sopsMetadataKey {
+			continue
+		}
+
+		var paths [][]interface{}
+
+		switch {
+		case hasEncryptedScalar(obj[k]):
+			if len(path) > 0 {
+				paths = append(paths, path)
+			}
+		default:
+			paths = maskPathsInValue(obj[k], append(append([]interface{}{}, path...), k))
+		}
+
+		for _, p := range paths {
+			key := fmt.Sprintf("%v", p)
+			if _, found := seen[key]; !found {
+				seen[key] = struct{}{}
+				result = append(result, p)
+			}
+		}
+	}
+
+	return result
+}
+
+func maskPathsInValue(val interface{}, path []interface{}) [][]interface{} {
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		return maskPaths(typedVal, path)
+
+	case []interface{}:
+		var result [][]interface{}
+		for i, item := range typedVal {
+			result = append(result, maskPathsInValue(item, append(append([]interface{}{}, path...), map[string]interface{}{"index": i}))...)
+		}
+		return result
+
+	default:
+		return nil
+	}
+}
+
+// hasEncryptedScalar checks if value is encrypted or is an array including
+// encrypted scalars (arrays cannot be masked element by element)
+func hasEncryptedScalar(val interface{}) bool {
+	switch typedVal := val.(type) {
+	case string:
+		return encryptedValueRegexp.MatchString(typedVal)
+	case []interface{}:
+		for _, item := range typedVal {
+			if str, ok := item.(string); ok && encryptedValueRegexp.MatchString(str) {
+				return true
+			}
+		}
+	}
+	return false
+}
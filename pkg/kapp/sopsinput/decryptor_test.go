@@ -0,0 +1,119 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package sopsinput_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlconf "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/config"
+	ctldiff "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/diff"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/sopsinput"
+)
+
+const encryptedYAML = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: ns1
+data:
+  password: ENC[AES256_GCM,data:abc=,iv:def=,tag:ghi=,type:str]
+  user: admin
+  nested:
+    hosts:
+    - ENC[AES256_GCM,data:jkl=,iv:mno=,tag:pqr=,type:str]
+sops:
+  mac: ENC[AES256_GCM,data:stu=,iv:vwx=,tag:yz=,type:str]
+  version: 3.8.1
+`
+
+func TestDecryptorSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Fake sops binary is a shell script")
+	}
+
+	dir := t.TempDir()
+
+	// Fake sops prints decrypted documents
+	sopsPath := filepath.Join(dir, "sops")
+	err := os.WriteFile(sopsPath, []byte(`#!/bin/sh
+cat <<EOF
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: ns1
+data:
+  password: secret
+  user: admin
+  nested:
+    hosts:
+    - db.internal
+EOF
+`), 0700)
+	require.NoError(t, err)
+
+	encrypted, err := sopsinput.IsEncrypted([]byte(encryptedYAML))
+	require.NoError(t, err)
+	require.True(t, encrypted)
+
+	decryptor := sopsinput.NewDecryptor(sopsPath)
+
+	rs, err := ctlres.NewFileResource(decryptor.Source(ctlres.NewBytesSource([]byte(encryptedYAML)))).Resources()
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+	require.Equal(t, "secret", rs[1].UnstructuredObject()["data"].(map[string]interface{})["password"])
+
+	maskConfig, err := decryptor.MaskConfigResource()
+	require.NoError(t, err)
+	require.NotNil(t, maskConfig)
+
+	_, conf, err := ctlconf.NewConfFromResources([]ctlres.Resource{maskConfig})
+	require.NoError(t, err)
+
+	maskedRes, err := ctldiff.NewMaskedResource(rs[1], conf.DiffMaskRules()).Resource()
+	require.NoError(t, err)
+
+	maskedData := maskedRes.UnstructuredObject()["data"].(map[string]interface{})
+	require.Contains(t, maskedData["password"], "value not shown")
+	// Values of parent map are masked entirely
+	require.Contains(t, maskedData["nested"], "value not shown")
+
+	// Resources that were not encrypted are not masked
+	maskedRes, err = ctldiff.NewMaskedResource(rs[0], conf.DiffMaskRules()).Resource()
+	require.NoError(t, err)
+	require.Equal(t, "value", maskedRes.UnstructuredObject()["data"].(map[string]interface{})["key"])
+}
+
+func TestDecryptorSkipsPlainFiles(t *testing.T) {
+	decryptor := sopsinput.NewDecryptor("/non-existent/sops")
+
+	bs, err := decryptor.Source(ctlres.NewBytesSource([]byte("kind: ConfigMap\n"))).Bytes()
+	require.NoError(t, err)
+	require.Equal(t, "kind: ConfigMap\n", string(bs))
+
+	maskConfig, err := decryptor.MaskConfigResource()
+	require.NoError(t, err)
+	require.Nil(t, maskConfig)
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package theme
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cppforlife/color"
+)
+
+const (
+	NameDefault    = "default"
+	NameColorBlind = "colorblind"
+
+	BackgroundAuto  = "auto"
+	BackgroundDark  = "dark"
+	BackgroundLight = "light"
+)
+
+// Theme holds styles for kapp specific output (tables are styled by UI)
+type Theme struct {
+	DiffAdded   Style
+	DiffRemoved Style
+	Warning     Style
+	Decoration  Style
+}
+
+// Style is a set of terminal attributes (e.g. bold, blue)
+type Style []color.Attribute
+
+func (s Style) Sprintf(format string, args ...interface{}) string {
+	return color.New(s...).Sprintf(format, args...)
+}
+
+var (
+	attributes = map[string]color.Attribute{
+		"bold":      color.Bold,
+		"faint":     color.Faint,
+		"underline": color.Underline,
+
+		"black":   color.FgBlack,
+		"red":     color.FgRed,
+		"green":   color.FgGreen,
+		"yellow":  color.FgYellow,
+		"blue":    color.FgBlue,
+		"magenta": color.FgMagenta,
+		"cyan":    color.FgCyan,
+		"white":   color.FgWhite,
+
+		"hi-black":   color.FgHiBlack,
+		"hi-red":     color.FgHiRed,
+		"hi-green":   color.FgHiGreen,
+		"hi-yellow":  color.FgHiYellow,
+		"hi-blue":    color.FgHiBlue,
+		"hi-magenta": color.FgHiMagenta,
+		"hi-cyan":    color.FgHiCyan,
+		"hi-white":   color.FgHiWhite,
+	}
+
+	// Palettes are keyed by theme name and then by background;
+	// color blind palettes avoid relying on red/green distinction
+	palettes = map[string]map[string]Theme{
+		NameDefault: {
+			BackgroundDark: Theme{
+				DiffAdded:   Style{color.FgGreen},
+				DiffRemoved: Style{color.FgRed},
+				Warning:     Style{color.FgYellow},
+				Decoration:  Style{color.Faint},
+			},
+			BackgroundLight: Theme{
+				DiffAdded:   Style{color.FgGreen},
+				DiffRemoved: Style{color.FgRed},
+				Warning:     Style{color.FgMagenta},
+				Decoration:  Style{color.Faint},
+			},
+		},
+		NameColorBlind: {
+			BackgroundDark: Theme{
+				DiffAdded:   Style{color.FgHiCyan},
+				DiffRemoved: Style{color.FgHiYellow},
+				Warning:     Style{color.Bold, color.FgHiMagenta},
+				Decoration:  Style{color.Faint},
+			},
+			BackgroundLight: Theme{
+				DiffAdded:   Style{color.FgBlue},
+				DiffRemoved: Style{color.Bold, color.FgMagenta},
+				Warning:     Style{color.Bold},
+				Decoration:  Style{color.Faint},
+			},
+		},
+	}
+
+	current     = palettes[NameDefault][BackgroundDark]
+	currentLock sync.RWMutex
+)
+
+// Current returns theme configured for this process
+func Current() Theme {
+	currentLock.RLock()
+	defer currentLock.RUnlock()
+	return current
+}
+
+func Configure(theme Theme) {
+	currentLock.Lock()
+	defer currentLock.Unlock()
+	current = theme
+}
+
+// New returns named theme for given background with style overrides
+// keyed by style name (diffAdded, diffRemoved, warning, decoration)
+func New(name, background string, overrides map[string]string) (Theme, error) {
+	palette, found := palettes[name]
+	if !found {
+		return Theme{}, fmt.Errorf("Unknown color theme '%s' (supported: %s, %s)", name, NameDefault, NameColorBlind)
+	}
+
+	switch background {
+	case BackgroundAuto:
+		background = DetectBackground()
+	case BackgroundDark, BackgroundLight:
+	default:
+		return Theme{}, fmt.Errorf("Unknown color background '%s' (supported: %s, %s, %s)",
+			background, BackgroundAuto, BackgroundDark, BackgroundLight)
+	}
+
+	theme := palette[background]
+
+	for _, key := range sortedKeys(overrides) {
+		style, err := NewStyle(overrides[key])
+		if err != nil {
+			return Theme{}, fmt.Errorf("Parsing style '%s': %w", key, err)
+		}
+		switch key {
+		case "diffAdded":
+			theme.DiffAdded = style
+		case "diffRemoved":
+			theme.DiffRemoved = style
+		case "warning":
+			theme.Warning = style
+		case "decoration":
+			theme.Decoration = style
+		default:
+			return Theme{}, fmt.Errorf("Unknown style '%s' (supported: diffAdded, diffRemoved, warning, decoration)", key)
+		}
+	}
+
+	return theme, nil
+}
+
+// NewStyle parses comma separated attributes (e.g. 'bold,blue')
+func NewStyle(val string) (Style, error) {
+	var style Style
+	for _, piece := range strings.Split(val, ",") {
+		piece = strings.TrimSpace(piece)
+		if len(piece) == 0 {
+			continue
+		}
+		attr, found := attributes[piece]
+		if !found {
+			return nil, fmt.Errorf("Unknown attribute '%s'", piece)
+		}
+		style = append(style, attr)
+	}
+	return style, nil
+}
+
+// DetectBackground uses COLORFGBG (set by some terminals, e.g. '0;15')
+// to determine background; defaults to dark when it is not available
+func DetectBackground() string {
+	pieces := strings.Split(os.Getenv("COLORFGBG"), ";")
+	switch pieces[len(pieces)-1] {
+	case "7", "9", "10", "11", "12", "13", "14", "15":
+		return BackgroundLight
+	default:
+		return BackgroundDark
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
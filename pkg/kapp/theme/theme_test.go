@@ -0,0 +1,47 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package theme_test
+
+import (
+	"testing"
+
+	"github.com/cppforlife/color"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/theme"
+)
+
+func TestNewThemeWithOverrides(t *testing.T) {
+	result, err := theme.New(theme.NameColorBlind, theme.BackgroundLight, map[string]string{
+		"diffAdded": "bold, hi-blue",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, theme.Style{color.Bold, color.FgHiBlue}, result.DiffAdded)
+	require.Equal(t, theme.Style{color.Bold, color.FgMagenta}, result.DiffRemoved)
+}
+
+func TestNewThemeErrors(t *testing.T) {
+	_, err := theme.New("unknown", theme.BackgroundDark, nil)
+	require.EqualError(t, err, "Unknown color theme 'unknown' (supported: default, colorblind)")
+
+	_, err = theme.New(theme.NameDefault, "grey", nil)
+	require.EqualError(t, err, "Unknown color background 'grey' (supported: auto, dark, light)")
+
+	_, err = theme.New(theme.NameDefault, theme.BackgroundDark, map[string]string{"diffAdded": "orange"})
+	require.EqualError(t, err, "Parsing style 'diffAdded': Unknown attribute 'orange'")
+
+	_, err = theme.New(theme.NameDefault, theme.BackgroundDark, map[string]string{"header": "bold"})
+	require.EqualError(t, err, "Unknown style 'header' (supported: diffAdded, diffRemoved, warning, decoration)")
+}
+
+func TestDetectBackground(t *testing.T) {
+	t.Setenv("COLORFGBG", "0;15")
+	require.Equal(t, theme.BackgroundLight, theme.DetectBackground())
+
+	t.Setenv("COLORFGBG", "15;default;0")
+	require.Equal(t, theme.BackgroundDark, theme.DetectBackground())
+
+	t.Setenv("COLORFGBG", "")
+	require.Equal(t, theme.BackgroundDark, theme.DetectBackground())
+}
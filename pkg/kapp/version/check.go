@@ -0,0 +1,249 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultReleasesAPIURL = "https://api.github.com/repos/carvel-dev/kapp"
+	DefaultCheckCacheTTL  = 24 * time.Hour
+)
+
+var (
+	versionRegexp = regexp.MustCompile(`v?(\d+)\.(\d+)\.(\d+)`)
+)
+
+// Release describes published kapp release
+type Release struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+// Advisory describes published security advisory
+// that affects currently used version
+type Advisory struct {
+	ID       string `json:"id"`
+	Summary  string `json:"summary"`
+	Severity string `json:"severity"`
+	URL      string `json:"url"`
+}
+
+type CheckResult struct {
+	Current    string     `json:"current"`
+	Latest     Release    `json:"latest"`
+	Advisories []Advisory `json:"advisories,omitempty"`
+	CheckedAt  time.Time  `json:"checkedAt"`
+}
+
+// HasNewer is false when current version could not be
+// compared with latest release (e.g. development build)
+func (r CheckResult) HasNewer() bool {
+	cmp, ok := compareVersions(r.Current, r.Latest.Version)
+	return ok && cmp < 0
+}
+
+// ReleaseChecker queries GitHub releases API for latest release and
+// security advisories; results could be cached in a file to avoid
+// querying API on every invocation.
+type ReleaseChecker struct {
+	APIURL     string
+	HTTPClient *http.Client
+
+	CachePath string
+	CacheTTL  time.Duration
+}
+
+func NewReleaseChecker() ReleaseChecker {
+	var cachePath string
+	if dir, err := os.UserCacheDir(); err == nil {
+		cachePath = filepath.Join(dir, "kapp", "version-check.json")
+	}
+	return ReleaseChecker{
+		APIURL:     DefaultReleasesAPIURL,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		CachePath:  cachePath,
+		CacheTTL:   DefaultCheckCacheTTL,
+	}
+}
+
+// Check always queries API and updates cache
+func (c ReleaseChecker) Check(current string) (CheckResult, error) {
+	latest, err := c.latestRelease()
+	if err != nil {
+		return CheckResult{}, fmt.Errorf("Fetching latest release: %w", err)
+	}
+
+	advisories, err := c.advisories(current)
+	if err != nil {
+		return CheckResult{}, fmt.Errorf("Fetching security advisories: %w", err)
+	}
+
+	result := CheckResult{
+		Current:    current,
+		Latest:     latest,
+		Advisories: advisories,
+		CheckedAt:  time.Now().UTC(),
+	}
+
+	c.writeCache(result)
+
+	return result, nil
+}
+
+// CachedCheck returns cached result if it is not older than TTL
+func (c ReleaseChecker) CachedCheck(current string) (CheckResult, error) {
+	if result, found := c.readCache(current); found {
+		return result, nil
+	}
+	return c.Check(current)
+}
+
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+}
+
+type githubAdvisory struct {
+	GHSAID          string `json:"ghsa_id"`
+	Summary         string `json:"summary"`
+	Severity        string `json:"severity"`
+	HTMLURL         string `json:"html_url"`
+	Vulnerabilities []struct {
+		PatchedVersions string `json:"patched_versions"`
+	} `json:"vulnerabilities"`
+}
+
+func (c ReleaseChecker) latestRelease() (Release, error) {
+	var release githubRelease
+
+	err := c.get("/releases/latest", &release)
+	if err != nil {
+		return Release{}, err
+	}
+	return Release{Version: release.TagName, URL: release.HTMLURL}, nil
+}
+
+func (c ReleaseChecker) advisories(current string) ([]Advisory, error) {
+	var advisories []githubAdvisory
+
+	err := c.get("/security-advisories?state=published", &advisories)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Advisory
+
+	for _, adv := range advisories {
+		for _, vuln := range adv.Vulnerabilities {
+			// Advisory is relevant if current version is older than patched one
+			cmp, ok := compareVersions(current, vuln.PatchedVersions)
+			if ok && cmp < 0 {
+				result = append(result, Advisory{
+					ID:       adv.GHSAID,
+					Summary:  adv.Summary,
+					Severity: adv.Severity,
+					URL:      adv.HTMLURL,
+				})
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (c ReleaseChecker) get(path string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.APIURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Expected response status 200, but was '%s'", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c ReleaseChecker) readCache(current string) (CheckResult, bool) {
+	if len(c.CachePath) == 0 {
+		return CheckResult{}, false
+	}
+
+	bs, err := os.ReadFile(c.CachePath)
+	if err != nil {
+		return CheckResult{}, false
+	}
+
+	var result CheckResult
+
+	err = json.Unmarshal(bs, &result)
+	if err != nil {
+		return CheckResult{}, false
+	}
+
+	// Advisories are relevant to a particular version
+	if result.Current != current || time.Since(result.CheckedAt) > c.CacheTTL {
+		return CheckResult{}, false
+	}
+	return result, true
+}
+
+// writeCache ignores errors since cache is only an optimization
+func (c ReleaseChecker) writeCache(result CheckResult) {
+	if len(c.CachePath) == 0 {
+		return
+	}
+
+	bs, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.CachePath), 0700)
+	if err != nil {
+		return
+	}
+
+	_ = os.WriteFile(c.CachePath, bs, 0600)
+}
+
+// compareVersions compares first found versions (e.g. 'v0.58.0', '>= 0.58.0');
+// returns false if either does not contain a version
+func compareVersions(a, b string) (int, bool) {
+	aMatch := versionRegexp.FindStringSubmatch(a)
+	bMatch := versionRegexp.FindStringSubmatch(b)
+	if aMatch == nil || bMatch == nil {
+		return 0, false
+	}
+	for i := 1; i < len(aMatch); i++ {
+		aNum, _ := strconv.Atoi(aMatch[i])
+		bNum, _ := strconv.Atoi(bMatch[i])
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
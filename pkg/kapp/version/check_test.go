@@ -0,0 +1,68 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package version_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/version"
+)
+
+func TestReleaseCheckerCheck(t *testing.T) {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/releases/latest":
+			w.Write([]byte(`{"tag_name": "v0.60.0", "html_url": "https://github.com/carvel-dev/kapp/releases/tag/v0.60.0"}`))
+		case "/security-advisories":
+			w.Write([]byte(`[
+{"ghsa_id": "GHSA-1", "summary": "old issue", "severity": "high", "vulnerabilities": [{"patched_versions": "0.50.0"}]},
+{"ghsa_id": "GHSA-2", "summary": "new issue", "severity": "critical", "vulnerabilities": [{"patched_versions": ">= 0.59.1"}]}
+]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	checker := version.ReleaseChecker{
+		APIURL:     server.URL,
+		HTTPClient: server.Client(),
+		CachePath:  filepath.Join(t.TempDir(), "version-check.json"),
+		CacheTTL:   time.Hour,
+	}
+
+	result, err := checker.CachedCheck("v0.59.0")
+	require.NoError(t, err)
+	require.True(t, result.HasNewer())
+	require.Equal(t, "v0.60.0", result.Latest.Version)
+	require.Len(t, result.Advisories, 1)
+	require.Equal(t, "GHSA-2", result.Advisories[0].ID)
+	require.Equal(t, 2, requests)
+
+	// Second check is served from cache
+	result, err = checker.CachedCheck("v0.59.0")
+	require.NoError(t, err)
+	require.True(t, result.HasNewer())
+	require.Equal(t, 2, requests)
+
+	// Cache is not used for another version
+	result, err = checker.CachedCheck("v0.60.0")
+	require.NoError(t, err)
+	require.False(t, result.HasNewer())
+	require.Empty(t, result.Advisories)
+	require.Equal(t, 4, requests)
+}
+
+func TestCheckResultHasNewerForDevelopmentBuild(t *testing.T) {
+	result := version.CheckResult{Current: "develop", Latest: version.Release{Version: "v0.60.0"}}
+	require.False(t, result.HasNewer())
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package yttinput
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cmdtpl "github.com/k14s/ytt/pkg/cmd/template"
+	"github.com/k14s/ytt/pkg/cmd/ui"
+	"github.com/k14s/ytt/pkg/files"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+var (
+	// Starlark files are always templates; YAML files only when they include ytt annotations
+	starlarkExts = []string{".star"}
+	yamlExts     = []string{".yml", ".yaml"}
+)
+
+// DataValues are passed to ytt the same way as its
+// --data-value and --data-values-file flags
+type DataValues struct {
+	KVsFromStrings []string
+	KVsFromYAML    []string
+	FromFiles      []string
+}
+
+// DirTemplate renders directory of ytt templates in-process
+type DirTemplate struct {
+	fsys fs.FS
+	dir  string
+	// desc is used for resource origins (fsys may be rooted at dir)
+	desc string
+}
+
+// NewDirTemplate uses OS file system rooted at dir if fsys is nil
+func NewDirTemplate(fsys fs.FS, dir string) DirTemplate {
+	desc := dir
+	if fsys == nil {
+		fsys = os.DirFS(dir)
+		dir = "."
+	}
+	return DirTemplate{fsys: fsys, dir: dir, desc: desc}
+}
+
+// IsTemplate returns true if directory contains at least one ytt template
+func (t DirTemplate) IsTemplate() (bool, error) {
+	paths, err := t.paths()
+	if err != nil {
+		return false, err
+	}
+
+	for _, path := range paths {
+		if hasExt(path, starlarkExts) {
+			return true, nil
+		}
+		if !hasExt(path, yamlExts) {
+			continue
+		}
+		bs, err := fs.ReadFile(t.fsys, path)
+		if err != nil {
+			return false, err
+		}
+		if hasYttAnnotations(bs) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (t DirTemplate) Resources(values DataValues) ([]ctlres.Resource, error) {
+	paths, err := t.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	var filesToProcess []*files.File
+
+	for _, path := range paths {
+		bs, err := fs.ReadFile(t.fsys, path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := files.NewFileFromSource(files.NewBytesSource(t.relPath(path), bs))
+		if err != nil {
+			return nil, err
+		}
+		filesToProcess = append(filesToProcess, file)
+	}
+
+	opts := cmdtpl.NewOptions()
+	opts.DataValuesFlags.KVsFromStrings = values.KVsFromStrings
+	opts.DataValuesFlags.KVsFromYAML = values.KVsFromYAML
+	opts.DataValuesFlags.FromFiles = values.FromFiles
+
+	out := opts.RunWithFiles(cmdtpl.Input{Files: files.NewSortedFiles(filesToProcess)}, ui.NewTTY(false))
+	if out.Err != nil {
+		return nil, fmt.Errorf("Templating directory '%s' with ytt: %w", t.desc, out.Err)
+	}
+
+	var resources []ctlres.Resource
+
+	for _, file := range out.Files {
+		if !hasExt(file.RelativePath(), yamlExts) {
+			continue
+		}
+
+		docs, err := ctlres.NewYAMLFile(ctlres.NewBytesSource(file.Bytes())).Docs()
+		if err != nil {
+			return nil, err
+		}
+
+		for i, doc := range docs {
+			rs, err := ctlres.NewResourcesFromBytes(doc)
+			if err != nil {
+				return nil, err
+			}
+
+			// Keep track of template file that produced resource
+			for _, res := range rs {
+				res.SetOrigin(fmt.Sprintf("file '%s' doc %d (ytt)", filepath.Join(t.desc, file.RelativePath()), i+1))
+			}
+
+			resources = append(resources, rs...)
+		}
+	}
+
+	return resources, nil
+}
+
+func (t DirTemplate) paths() ([]string, error) {
+	var paths []string
+
+	err := fs.WalkDir(t.fsys, t.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Listing directory '%s': %w", t.desc, err)
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func (t DirTemplate) relPath(p string) string {
+	if t.dir == "." {
+		return p
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(p, path.Clean(t.dir)), "/")
+}
+
+func hasExt(path string, exts []string) bool {
+	ext := filepath.Ext(path)
+	for _, e := range exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func hasYttAnnotations(bs []byte) bool {
+	return bytes.Contains(bs, []byte("#@"))
+}
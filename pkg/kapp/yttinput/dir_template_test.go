@@ -0,0 +1,56 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package yttinput_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/carvel-kapp/pkg/kapp/yttinput"
+)
+
+func TestDirTemplateResources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/config/cm.yml": {Data: []byte(`
+#@ load("@ytt:data", "data")
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: #@ data.values.name
+data:
+  key: #@ data.values.value
+`)},
+		"app/values.yml": {Data: []byte(`
+#@data/values
+---
+name: cm1
+value: default
+`)},
+		"plain/cm.yml": {Data: []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+`)},
+	}
+
+	isTemplate, err := yttinput.NewDirTemplate(fsys, "plain").IsTemplate()
+	require.NoError(t, err)
+	require.False(t, isTemplate)
+
+	tpl := yttinput.NewDirTemplate(fsys, "app")
+
+	isTemplate, err = tpl.IsTemplate()
+	require.NoError(t, err)
+	require.True(t, isTemplate)
+
+	rs, err := tpl.Resources(yttinput.DataValues{KVsFromStrings: []string{"value=custom"}})
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+
+	require.Equal(t, "cm1", rs[0].Name())
+	require.Equal(t, map[string]interface{}{"key": "custom"}, rs[0].UnstructuredObject()["data"])
+	require.Equal(t, "file 'app/config/cm.yml' doc 1 (ytt)", rs[0].Origin())
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package yttresmod
+
+import (
+	"fmt"
+
+	cmdtpl "github.com/k14s/ytt/pkg/cmd/template"
+	"github.com/k14s/ytt/pkg/cmd/ui"
+	"github.com/k14s/ytt/pkg/files"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+	"sigs.k8s.io/yaml"
+)
+
+// FuncContractV1Mod rebases resource via rebase_resource(resource, sources)
+// Starlark function that returns rebased resource. Sources hold resources
+// by source name (e.g. new, existing) and may be None if source is not available.
+type FuncContractV1Mod struct {
+	ResourceMatcher ctlres.ResourceMatcher
+	Starlark        string
+}
+
+var _ ctlres.ResourceModWithMultiple = FuncContractV1Mod{}
+
+func (t FuncContractV1Mod) IsResourceMatching(res ctlres.Resource) bool {
+	if res == nil || !t.ResourceMatcher.Matches(res) {
+		return false
+	}
+	return true
+}
+
+func (t FuncContractV1Mod) ApplyFromMultiple(res ctlres.Resource, srcs map[ctlres.FieldCopyModSource]ctlres.Resource) error {
+	result, err := t.evalYtt(res, srcs)
+	if err != nil {
+		return fmt.Errorf("Applying ytt (funcContractV1): %w", err)
+	}
+
+	res.DeepCopyIntoFrom(result)
+	return nil
+}
+
+func (t FuncContractV1Mod) evalYtt(res ctlres.Resource, srcs map[ctlres.FieldCopyModSource]ctlres.Resource) (ctlres.Resource, error) {
+	opts := cmdtpl.NewOptions()
+
+	opts.DataValuesFlags.FromFiles = []string{"values.yml"}
+	opts.DataValuesFlags.ReadFileFunc = func(path string) ([]byte, error) {
+		if path != "values.yml" {
+			return nil, fmt.Errorf("Unknown file to read: %s", path)
+		}
+		return t.valuesYAML(res, srcs)
+	}
+
+	filesToProcess := []*files.File{
+		files.MustNewFileFromSource(files.NewBytesSource("resource.star", []byte(t.Starlark))),
+		files.MustNewFileFromSource(files.NewBytesSource("resource.yml", t.getResourceYAML())),
+	}
+
+	out := opts.RunWithFiles(cmdtpl.Input{Files: filesToProcess}, ui.NewTTY(false))
+	if out.Err != nil {
+		return nil, fmt.Errorf("Evaluating: %w", out.Err)
+	}
+
+	if len(out.Files) == 0 {
+		return nil, fmt.Errorf("Expected to find resource.yml but saw zero files")
+	}
+
+	file := out.Files[0]
+	if file.RelativePath() != "resource.yml" {
+		return nil, fmt.Errorf("Expected resource.yml but was: %s", file.RelativePath())
+	}
+
+	rs, err := ctlres.NewResourcesFromBytes(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("Deserializing result: %w", err)
+	}
+
+	if len(rs) != 1 {
+		return nil, fmt.Errorf("Expected rebase_resource to return one resource, but was %d", len(rs))
+	}
+
+	return rs[0], nil
+}
+
+func (t FuncContractV1Mod) valuesYAML(res ctlres.Resource, srcs map[ctlres.FieldCopyModSource]ctlres.Resource) ([]byte, error) {
+	sources := map[string]interface{}{}
+	for src, srcRes := range srcs {
+		if srcRes != nil {
+			sources[string(src)] = srcRes.DeepCopyRaw()
+		} else {
+			sources[string(src)] = nil
+		}
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"resource": res.DeepCopyRaw(),
+		"sources":  sources,
+	})
+}
+
+func (t FuncContractV1Mod) getResourceYAML() []byte {
+	config := `
+#@ load("resource.star", "rebase_resource")
+#@ load("@ytt:data", "data")
+
+--- #@ rebase_resource(data.values.resource, data.values.sources)
+`
+	return []byte(config)
+}
@@ -16,9 +16,6 @@ import (
 type OverlayContractV1Mod struct {
 	ResourceMatcher ctlres.ResourceMatcher
 	OverlayYAML     string
-
-	// TODO support rebase_resource(res, sources) func via .star file?
-	// Starlark string
 }
 
 var _ ctlres.ResourceModWithMultiple = OverlayContractV1Mod{}
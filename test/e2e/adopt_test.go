@@ -0,0 +1,102 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestAdopt(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	existingYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+  labels:
+    adopt-test: "true"
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+  labels:
+    adopt-test: "true"
+data:
+  key: value
+`
+
+	firstYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: other-value
+`
+
+	name := "test-adopt"
+	otherName := "test-adopt-other"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.Run([]string{"delete", "-a", otherName})
+		kubectl.RunWithOpts([]string{"delete", "configmap", "first", "second", "--ignore-not-found"}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("create resources outside of kapp", func() {
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(existingYAML)})
+	})
+
+	logger.Section("adopt resources from files", func() {
+		kapp.RunWithOpts([]string{"adopt", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(firstYAML)})
+
+		cm := NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		require.Contains(t, cm.Labels(), "kapp.k14s.io/app")
+		require.Equal(t, "value", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})), "Expected data to be unchanged")
+
+		out := kapp.Run([]string{"inspect", "-a", name, "--json"})
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+		require.Equal(t, "first", resp.Tables[0].Rows[0]["name"])
+	})
+
+	logger.Section("adopt resources by selector", func() {
+		kapp.Run([]string{"adopt", "-a", name, "--selector", "adopt-test=true"})
+
+		out := kapp.Run([]string{"inspect", "-a", name, "--json"})
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 2)
+	})
+
+	logger.Section("adopting resources owned by other app fails", func() {
+		_, err := kapp.RunWithOpts([]string{"adopt", "-a", otherName, "--selector", "adopt-test=true"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is already associated with a different label")
+	})
+
+	logger.Section("deploy manages adopted resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(firstYAML)})
+
+		cm := NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		require.Equal(t, "other-value", cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+
+		NewMissingClusterResource(t, "configmap", "second", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestAppChangeInputChunks(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	// Random data is not compressed away hence recorded input
+	// (~1.8MiB encoded) does not fit into app change ConfigMap (768KiB)
+	largeYAML := ""
+	values := map[string]string{}
+	for i := 0; i < 3; i++ {
+		data := make([]byte, 450*1024)
+		rand.New(rand.NewSource(int64(i))).Read(data)
+		name := fmt.Sprintf("large-%d", i)
+		values[name] = base64.StdEncoding.EncodeToString(data)
+		largeYAML += fmt.Sprintf(`
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+data:
+  key: %s
+`, name, values[name])
+	}
+
+	smallYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: small
+`
+
+	name := "test-app-change-input-chunks"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	chunkNames := func() []string {
+		out := kubectl.Run([]string{"get", "configmaps", "-l", "kapp.k14s.io/is-app-change-chunk",
+			"-o", "jsonpath={.items[*].metadata.name}"})
+		return strings.Fields(out)
+	}
+
+	logger.Section("deploy large input", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(largeYAML)})
+
+		require.Greater(t, len(chunkNames()), 1, "Expected recorded input to be split into chunks")
+	})
+
+	logger.Section("rollback reassembles large input", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(smallYAML)})
+
+		NewMissingClusterResource(t, "configmap", "large-0", env.Namespace, kubectl)
+
+		out := kapp.Run([]string{"rollback", "-a", name})
+		require.Contains(t, out, "Rolling back to app change")
+
+		for cmName, val := range values {
+			res := NewPresentClusterResource("configmap", cmName, env.Namespace, kubectl)
+			require.Equal(t, val, res.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+		}
+		NewMissingClusterResource(t, "configmap", "small", env.Namespace, kubectl)
+	})
+
+	logger.Section("delete removes chunks", func() {
+		kapp.Run([]string{"delete", "-a", name})
+
+		require.Empty(t, chunkNames())
+	})
+}
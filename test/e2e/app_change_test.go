@@ -240,3 +240,98 @@ type yamlSubset struct {
 	LastChange lastChange `yaml:"lastChange"`
 	UsedGKs    []usedGK   `yaml:"usedGKs"`
 }
+
+func TestAppChangeDescribe(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: visible-value
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: first
+stringData:
+  key: secret-value
+`
+
+	name := "test-app-change-describe"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("describe last app change", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		out := kapp.Run([]string{"app-change", "describe", "-a", name})
+		require.Contains(t, out, "visible-value")
+		require.NotContains(t, out, "secret-value")
+	})
+
+	logger.Section("do not record resources", func() {
+		yaml2 := strings.ReplaceAll(yaml1, "visible-value", "visible-value-2")
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-changes-max-to-keep-resources", "0"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		out := kapp.Run([]string{"app-change", "describe", "-a", name})
+		require.Contains(t, out, "App change does not have recorded resources")
+	})
+}
+
+func TestAppChangeMetadata(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`
+
+	name := "test-app-change-metadata"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with change metadata", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--change-metadata", "ticket=OPS-123",
+			"--change-metadata", "git-sha=abc123"}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		out, _ := kapp.RunWithOpts([]string{"app-change", "ls", "-a", name, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		require.Equal(t, 1, len(resp.Tables[0].Rows), "Expected to have 1 app-change")
+		require.Contains(t, resp.Tables[0].Rows[0]["metadata"], "ticket=OPS-123")
+		require.Contains(t, resp.Tables[0].Rows[0]["metadata"], "git-sha=abc123")
+	})
+
+	logger.Section("reject invalid change metadata", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--change-metadata", "ticket"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected change metadata to be in 'key=val' format")
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppChangesRetention(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: "%d"
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+appChangesRetention:
+  maxToKeep: 2
+`
+
+	name := "test-app-changes-retention"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	numChanges := func() int {
+		out := kapp.Run([]string{"app-change", "ls", "-a", name, "--json"})
+		return len(uitest.JSONUIFromBytes(t, []byte(out)).Tables[0].Rows)
+	}
+
+	logger.Section("keep number of changes specified in config", func() {
+		for i := 0; i < 4; i++ {
+			kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+				RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, i))})
+		}
+
+		require.Equal(t, 2, numChanges())
+	})
+
+	logger.Section("flag takes precedence over config", func() {
+		for i := 4; i < 7; i++ {
+			kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-changes-max-to-keep", "3"},
+				RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, i))})
+		}
+
+		require.Equal(t, 3, numChanges())
+	})
+
+	logger.Section("delete changes based on age", func() {
+		time.Sleep(2 * time.Second)
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-changes-max-age", "1s"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, 7))})
+
+		// Newest change is always kept
+		require.Equal(t, 1, numChanges())
+	})
+
+	logger.Section("garbage collect based on age", func() {
+		time.Sleep(2 * time.Second)
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(fmt.Sprintf(yaml, 8))})
+		require.Equal(t, 2, numChanges())
+
+		kapp.Run([]string{"app-change", "gc", "-a", name, "--max-age", "1s"})
+
+		require.Equal(t, 1, numChanges())
+	})
+}
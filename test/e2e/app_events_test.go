@@ -0,0 +1,110 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppEvents(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+`
+
+	yaml2 := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job-fail
+spec:
+  template:
+    metadata:
+      name: job-fail
+    spec:
+      restartPolicy: Never
+      containers:
+        - name: job-fail
+          image: busybox
+          command: [ "sh", "-c", "exit 1" ]
+  backoffLimit: 0
+`
+
+	name := "test-app-events"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	type event struct {
+		Type           string
+		Reason         string
+		Message        string
+		InvolvedObject struct {
+			Kind string
+			Name string
+		} `json:"involvedObject"`
+	}
+
+	appEvents := func() map[string]event {
+		out := kubectl.Run([]string{"get", "events", "-o", "json"})
+
+		var list struct {
+			Items []event
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &list))
+
+		result := map[string]event{}
+		for _, item := range list.Items {
+			if strings.HasPrefix(item.InvolvedObject.Name, name) && item.InvolvedObject.Kind == "ConfigMap" {
+				result[item.Reason] = item
+			}
+		}
+		return result
+	}
+
+	logger.Section("events are not created by default", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		require.Len(t, appEvents(), 0)
+	})
+
+	logger.Section("successful deploy", func() {
+		yaml1 += "data:\n  key: value\n"
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-events"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		events := appEvents()
+		require.Equal(t, "Normal", events["DeployStarted"].Type)
+		require.Equal(t, "Normal", events["DeploySucceeded"].Type)
+	})
+
+	logger.Section("failed deploy", func() {
+		cleanUp()
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-events"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+		require.Error(t, err)
+
+		events := appEvents()
+		require.Equal(t, "Warning", events["DeployFailed"].Type)
+		require.Equal(t, "Warning", events["ResourceWaitFailed"].Type)
+		require.Contains(t, events["ResourceWaitFailed"].Message, "Job/job-fail (batch/v1)")
+	})
+}
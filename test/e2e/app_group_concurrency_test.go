@@ -0,0 +1,70 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppGroupConcurrency(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	groupConfigYAML := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: AppGroupConfig
+apps:
+- name: app4
+  dependsOn: [app1, app2, app3]
+`
+
+	groupName := "test-app-group-concurrency"
+	cleanUp := func() {
+		kapp.Run([]string{"app-group", "delete", "-g", groupName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	dir, err := os.MkdirTemp("", "kapp-test-app-group-concurrency")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for i := 1; i <= 4; i++ {
+		appDir := filepath.Join(dir, fmt.Sprintf("app%d", i))
+		require.NoError(t, os.MkdirAll(appDir, 0700))
+
+		configMapYAML := fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm%d\ndata:\n  key: value\n", i)
+		require.NoError(t, os.WriteFile(filepath.Join(appDir, "config.yml"), []byte(configMapYAML), 0600))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-group.yml"), []byte(groupConfigYAML), 0600))
+
+	logger.Section("deploy in parallel", func() {
+		out := kapp.Run([]string{"app-group", "deploy", "-g", groupName, "--directory", dir, "--app-group-concurrency", "3"})
+
+		for i := 1; i <= 4; i++ {
+			require.Contains(t, out, fmt.Sprintf("[app%d] --- deploying app '%s-app%d'", i, groupName, i))
+			NewPresentClusterResource("configmap", fmt.Sprintf("cm%d", i), env.Namespace, kubectl)
+		}
+
+		lastDepIdx := 0
+		for i := 1; i <= 3; i++ {
+			idx := strings.Index(out, fmt.Sprintf("[app%d] --- deployed app", i))
+			if idx > lastDepIdx {
+				lastDepIdx = idx
+			}
+		}
+		require.Less(t, lastDepIdx, strings.Index(out, "[app4] --- deploying app"),
+			"Expected dependent app to be deployed after its dependencies")
+	})
+}
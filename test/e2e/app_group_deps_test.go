@@ -0,0 +1,82 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppGroupDependencies(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	configMapYAML := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+data:
+  key: value
+`
+
+	groupConfigYAML := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: AppGroupConfig
+apps:
+- name: a-second
+  dependsOn: [b-first]
+`
+
+	groupName := "test-app-group-deps"
+	cleanUp := func() {
+		kapp.Run([]string{"app-group", "delete", "-g", groupName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	dir, err := os.MkdirTemp("", "kapp-test-app-group-deps")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	writeFile(filepath.Join(dir, "a-second", "config.yml"), strings.ReplaceAll(configMapYAML, "%s", "second"))
+	writeFile(filepath.Join(dir, "b-first", "config.yml"), strings.ReplaceAll(configMapYAML, "%s", "first"))
+	writeFile(filepath.Join(dir, "app-group.yml"), groupConfigYAML)
+
+	logger.Section("deploy in dependency order", func() {
+		out := kapp.Run([]string{"app-group", "deploy", "-g", groupName, "--directory", dir})
+
+		firstIdx := strings.Index(out, "deploying app '"+groupName+"-b-first'")
+		secondIdx := strings.Index(out, "deploying app '"+groupName+"-a-second'")
+		require.True(t, firstIdx >= 0 && secondIdx >= 0, "Expected both apps to be deployed")
+		require.Less(t, firstIdx, secondIdx, "Expected dependency to be deployed first")
+	})
+
+	logger.Section("skip dependents of failed app", func() {
+		writeFile(filepath.Join(dir, "b-first", "config.yml"), "apiVersion: v1\nkind: ConfigMap\n")
+
+		out, err := kapp.RunWithOpts([]string{"app-group", "deploy", "-g", groupName, "--directory", dir}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, out, "skipping app '"+groupName+"-a-second' because its dependencies failed: b-first")
+	})
+
+	logger.Section("reject dependency cycles", func() {
+		writeFile(filepath.Join(dir, "app-group.yml"), groupConfigYAML+"- name: b-first\n  dependsOn: [a-second]\n")
+
+		_, err := kapp.RunWithOpts([]string{"app-group", "deploy", "-g", groupName, "--directory", dir}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected app group config to not have dependency cycles (found between apps: a-second, b-first)")
+	})
+}
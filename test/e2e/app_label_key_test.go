@@ -0,0 +1,73 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppLabelKey(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key: value
+`
+
+	name := "test-app-label-key"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	deploy := func(args ...string) {
+		kapp.RunWithOpts(append([]string{"deploy", "-f", "-", "-a", name}, args...),
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	}
+
+	labels := func() map[string]string {
+		return NewPresentClusterResource("configmap", "cm", env.Namespace, kubectl).Labels()
+	}
+
+	logger.Section("deploy with default label key", func() {
+		deploy()
+
+		require.Contains(t, labels(), "kapp.k14s.io/app")
+	})
+
+	logger.Section("migrate to custom label key", func() {
+		deploy("--app-label-key", "example.com/app")
+
+		require.Contains(t, labels(), "example.com/app")
+		require.NotContains(t, labels(), "kapp.k14s.io/app")
+
+		out := kapp.Run([]string{"inspect", "-a", name})
+		require.Contains(t, out, "cm")
+	})
+
+	logger.Section("subsequent deploys keep custom label key", func() {
+		deploy()
+
+		require.Contains(t, labels(), "example.com/app")
+	})
+
+	logger.Section("delete app with custom label key", func() {
+		cleanUp()
+
+		NewMissingClusterResource(t, "configmap", "cm", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestAppLabelsAndAnnotations(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+appLabels:
+  team: frontend
+appAnnotations:
+  owner: frontend@example.com
+propagateAppLabels:
+- team
+`
+
+	name := "test-app-labels"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("labels and annotations from config", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		app := NewPresentClusterResource("configmap", name, env.Namespace, kubectl)
+		require.Equal(t, "frontend", app.Labels()["team"])
+		require.Equal(t, "frontend@example.com", app.RawPath(ctlres.NewPathFromStrings([]string{"metadata", "annotations", "owner"})))
+
+		cm := NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		require.Equal(t, "frontend", cm.Labels()["team"])
+	})
+
+	logger.Section("flags take precedence and labels could change", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-label", "team=backend",
+			"--app-label", "cost-center=123", "--app-annotation", "owner=backend@example.com",
+			"--propagate-app-label", "cost-center"}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		app := NewPresentClusterResource("configmap", name, env.Namespace, kubectl)
+		require.Equal(t, "backend", app.Labels()["team"])
+		require.Equal(t, "123", app.Labels()["cost-center"])
+		require.Equal(t, "backend@example.com", app.RawPath(ctlres.NewPathFromStrings([]string{"metadata", "annotations", "owner"})))
+
+		cm := NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		require.Equal(t, "backend", cm.Labels()["team"])
+		require.Equal(t, "123", cm.Labels()["cost-center"])
+	})
+
+	logger.Section("propagating unknown label", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--propagate-app-label", "unknown"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected app label 'unknown' to be specified")
+	})
+}
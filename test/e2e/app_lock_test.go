@@ -0,0 +1,83 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppLock(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+`
+
+	name := "test-app-lock"
+	lockName := name + "-kapp-lock"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "--force-unlock"})
+		kubectl.RunWithOpts([]string{"delete", "lease", lockName, "--ignore-not-found"}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	leaseYAML := fmt.Sprintf(`
+---
+apiVersion: coordination.k8s.io/v1
+kind: Lease
+metadata:
+  name: %s
+spec:
+  holderIdentity: other-kapp
+  leaseDurationSeconds: 3600
+  acquireTime: %[2]s
+  renewTime: %[2]s
+`, lockName, time.Now().UTC().Format("2006-01-02T15:04:05.000000Z"))
+
+	logger.Section("deploy releases lock", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		NewMissingClusterResource(t, "lease", lockName, env.Namespace, kubectl)
+	})
+
+	logger.Section("deploy fails when app is locked", func() {
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(leaseYAML)})
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "App is locked by 'other-kapp'")
+	})
+
+	logger.Section("deploy succeeds with force unlock", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--force-unlock"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		NewMissingClusterResource(t, "lease", lockName, env.Namespace, kubectl)
+	})
+
+	logger.Section("delete fails when app is locked", func() {
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(leaseYAML)})
+
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "App is locked by 'other-kapp'")
+
+		NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppMetadataCRD(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+`
+
+	crdsName := "test-app-metadata-crds"
+	name := "test-app-metadata-crd"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "--app-metadata", "crd"})
+		kapp.Run([]string{"delete", "-a", crdsName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("install app metadata CRDs", func() {
+		crds := kapp.Run([]string{"app-metadata-crds"})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", crdsName}, RunOpts{StdinReader: strings.NewReader(crds)})
+	})
+
+	logger.Section("deploy app with metadata in custom resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--app-metadata", "crd"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", name, env.Namespace, kubectl)
+
+		out := kubectl.Run([]string{"get", "apps.kapp.k14s.io", "-o", "name"})
+		require.Contains(t, out, "app.kapp.k14s.io/"+name)
+
+		out = kubectl.Run([]string{"get", "appchanges.kapp.k14s.io", "-o", "name"})
+		require.Contains(t, out, "appchange.kapp.k14s.io/"+name+"-change-")
+
+		out = kapp.Run([]string{"app-change", "list", "-a", name, "--app-metadata", "crd"})
+		require.Contains(t, out, "update: Op: 1 create")
+	})
+}
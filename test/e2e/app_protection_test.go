@@ -0,0 +1,72 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppProtection(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`
+
+	name := "test-app-protection"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name, "--unprotect"})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy protected app", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--protect"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+	})
+
+	logger.Section("refuse to delete protected app", func() {
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "specify --unprotect to delete it")
+
+		NewPresentClusterResource("configmap", "config", env.Namespace, kubectl)
+	})
+
+	logger.Section("keep protection on subsequent deploys", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+		require.Error(t, err)
+	})
+
+	logger.Section("delete with --unprotect", func() {
+		kapp.Run([]string{"delete", "-a", name, "--unprotect"})
+
+		NewMissingClusterResource(t, "configmap", "config", env.Namespace, kubectl)
+	})
+
+	logger.Section("remove protection", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--protect"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--protect=false"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml)})
+
+		kapp.Run([]string{"delete", "-a", name})
+	})
+}
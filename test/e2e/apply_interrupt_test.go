@@ -0,0 +1,79 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyInterrupt(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-job
+  annotations:
+    kapp.k14s.io/change-group: "slow"
+spec:
+  template:
+    metadata:
+      name: slow-job
+    spec:
+      containers:
+      - name: slow-job
+        image: busybox
+        command: ["/bin/sh", "-c", "sleep 60"]
+      restartPolicy: Never
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: after-slow-job
+  annotations:
+    kapp.k14s.io/change-rule: "upsert after upserting slow"
+`
+
+	name := "test-apply-interrupt"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("interrupted deploy stops applying new changes", func() {
+		cancelCh := make(chan struct{})
+		go func() {
+			time.Sleep(10 * time.Second)
+			close(cancelCh)
+		}()
+
+		out, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, CancelCh: cancelCh, StdinReader: strings.NewReader(yaml1)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Stopped applying changes (interrupted): applied 1/2")
+		require.Contains(t, out, "To resume, re-run the same 'kapp deploy' command")
+
+		_, err = kubectl.RunWithOpts([]string{"get", "configmap", "after-slow-job"}, RunOpts{AllowError: true})
+		require.Error(t, err, "Expected config map to not be created")
+	})
+
+	logger.Section("re-running deploy applies remaining changes", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait=false"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		kubectl.Run([]string{"get", "configmap", "after-slow-job"})
+	})
+}
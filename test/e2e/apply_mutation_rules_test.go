@@ -0,0 +1,79 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestApplyMutationRules(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  keep: ""
+  delete: ""
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+data:
+  keep: ""
+  delete: ""
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+applyMutationRules:
+- path: [metadata, labels, cost-center]
+  type: set
+  value: team-a
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+- path: [data, delete]
+  type: remove
+  resourceMatchers:
+  - kindNamespaceNameMatcher:
+      kind: ConfigMap
+      namespace: kapp-test
+      name: first
+`
+
+	name := "test-apply-mutation-rules"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with mutations", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-changes"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "cost-center: team-a", "Expected mutation to be visible in diff")
+
+		firstRes := NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		require.Exactlyf(t, map[string]interface{}{"keep": ""},
+			firstRes.RawPath(ctlres.NewPathFromStrings([]string{"data"})), "Expected field to be removed")
+		require.Equal(t, "team-a", firstRes.Labels()["cost-center"])
+
+		secondRes := NewPresentClusterResource("configmap", "second", env.Namespace, kubectl)
+		require.Exactlyf(t, map[string]interface{}{"keep": "", "delete": ""},
+			secondRes.RawPath(ctlres.NewPathFromStrings([]string{"data"})), "Expected field to be kept")
+		require.Equal(t, "team-a", secondRes.Labels()["cost-center"])
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestClusterConfig(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	clusterConfigYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kapp-config
+data:
+  config.yml: |
+    apiVersion: kapp.k14s.io/v1alpha1
+    kind: Config
+    rebaseRules:
+    - path: [data, keep]
+      type: copy
+      sources: [existing, new]
+      resourceMatchers:
+      - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: __ns__, name: cm}
+    ---
+    apiVersion: kapp.k14s.io/v1alpha1
+    kind: Config
+    rebaseRules:
+    - path: [data, keep2]
+      type: copy
+      sources: [existing, new]
+      resourceMatchers:
+      - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: __ns__, name: cm}
+`
+
+	// Commands from cluster config would run on machine of kapp user
+	execClusterConfigYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kapp-config
+data:
+  config.yml: |
+    apiVersion: kapp.k14s.io/v1alpha1
+    kind: Config
+    waitRules:
+    - exec: {command: touch, args: [/tmp/kapp-cluster-config-exec]}
+      resourceMatchers:
+      - allMatcher: {}
+`
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  keep: original
+  keep2: original
+`
+
+	name := "test-cluster-config"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kubectl.RunWithOpts([]string{"delete", "configmap", "kapp-config", "--ignore-not-found"}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	deploy := func(args ...string) (string, error) {
+		return kapp.RunWithOpts(append([]string{"deploy", "-f", "-", "-a", name}, args...),
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+	}
+
+	keepVal := func() string {
+		cm := NewPresentClusterResource("configmap", "cm", env.Namespace, kubectl)
+		return cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "keep"})).(string)
+	}
+
+	// Rule from second config document of cluster config
+	keep2Val := func() string {
+		cm := NewPresentClusterResource("configmap", "cm", env.Namespace, kubectl)
+		return cm.RawPath(ctlres.NewPathFromStrings([]string{"data", "keep2"})).(string)
+	}
+
+	logger.Section("deploy initial app and cluster config", func() {
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{
+			StdinReader: strings.NewReader(strings.ReplaceAll(clusterConfigYAML, "__ns__", env.Namespace))})
+
+		_, err := deploy("--cluster-config")
+		require.NoError(t, err)
+		require.Equal(t, "original", keepVal())
+
+		kubectl.Run([]string{"patch", "configmap", "cm", "--type=merge", "-p", `{"data":{"keep":"changed","keep2":"changed"}}`})
+	})
+
+	logger.Section("rebase rule from cluster config is applied", func() {
+		_, err := deploy("--cluster-config")
+		require.NoError(t, err)
+		require.Equal(t, "changed", keepVal())
+		require.Equal(t, "changed", keep2Val())
+	})
+
+	logger.Section("cluster config is ignored by default", func() {
+		_, err := deploy()
+		require.NoError(t, err)
+		require.Equal(t, "original", keepVal())
+		require.Equal(t, "original", keep2Val())
+	})
+
+	logger.Section("cluster config with exec rules is rejected", func() {
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(execClusterConfigYAML)})
+
+		_, err := deploy("--cluster-config")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected wait rule 0 to not use exec or plugin")
+	})
+}
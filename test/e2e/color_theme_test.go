@@ -0,0 +1,60 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorTheme(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+`
+
+	name := "test-color-theme"
+	diffArgs := []string{"deploy", "-f", "-", "-a", name, "--diff-changes", "--diff-run"}
+
+	// Force colors even though output is not a terminal
+	t.Setenv("FORCE_COLOR", "1")
+
+	logger.Section("colorblind theme", func() {
+		out, _ := kapp.RunWithOpts(append(diffArgs, "--color-theme", "colorblind", "--color-background", "dark"),
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		require.Contains(t, out, "\x1b[96m", "Expected added lines to use hi-cyan color")
+		require.NotContains(t, out, "\x1b[32m", "Expected green color to not be used")
+	})
+
+	logger.Section("theme from env", func() {
+		t.Setenv("KAPP_COLOR_THEME", "colorblind")
+		t.Setenv("KAPP_COLOR_BACKGROUND", "light")
+
+		out, _ := kapp.RunWithOpts(diffArgs, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		require.Contains(t, out, "\x1b[34m", "Expected added lines to use blue color")
+	})
+
+	logger.Section("NO_COLOR disables colors", func() {
+		t.Setenv("NO_COLOR", "1")
+
+		out, _ := kapp.RunWithOpts(diffArgs, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		require.NotContains(t, out, "\x1b[")
+	})
+
+	logger.Section("unknown theme", func() {
+		_, err := kapp.RunWithOpts(append(diffArgs, "--color-theme", "unknown"),
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unknown color theme 'unknown'")
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionOfAppNamesAndNamespaces(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`
+
+	name := "test-completion-app"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+	// Completion arguments are expected to be last hence namespace is specified explicitly
+	completeOpts := RunOpts{NoNamespace: true, Interactive: true}
+
+	logger.Section("app names", func() {
+		out, _ := kapp.RunWithOpts([]string{"__complete", "delete", "-n", env.Namespace, "-a", "test-completion"}, completeOpts)
+
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Contains(t, lines, name)
+		require.Equal(t, ":4", lines[len(lines)-1], "Expected no file completion directive")
+	})
+
+	logger.Section("app names not matching prefix", func() {
+		out, _ := kapp.RunWithOpts([]string{"__complete", "delete", "-n", env.Namespace, "-a", "non-matching"}, completeOpts)
+
+		require.NotContains(t, out, name)
+	})
+
+	logger.Section("namespaces", func() {
+		out, _ := kapp.RunWithOpts([]string{"__complete", "delete", "-n", env.Namespace[:1]}, completeOpts)
+
+		require.Contains(t, strings.Split(strings.TrimSpace(out), "\n"), env.Namespace)
+	})
+}
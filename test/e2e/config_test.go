@@ -554,6 +554,75 @@ data:
 	})
 }
 
+func TestYttRebaseRule_FuncContractV1(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	config := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+
+rebaseRules:
+- ytt:
+    funcContractV1:
+      resource.star: |
+        load("@ytt:struct", "struct")
+
+        def rebase_resource(resource, sources):
+          res = struct.decode(resource)
+          existing = sources.existing
+          if existing != None and hasattr(existing.data, "counter"):
+            res["data"]["counter"] = existing.data.counter
+          end
+          return res
+        end
+  resourceMatchers:
+  - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: __ns__, name: test-cm}
+`
+
+	config = strings.ReplaceAll(config, "__ns__", env.Namespace)
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cm
+data:
+  key1: val1`
+
+	name := "test-config-ytt-rebase-func-contract-v1"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("initial deploy and change in cluster", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + yaml1)})
+
+		kubectl.Run([]string{"patch", "configmap", "test-cm", "--type=merge", "-p", `{"data":{"counter":"5"}}`})
+	})
+
+	logger.Section("deploy keeps cluster value", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(config + yaml1)})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Exactlyf(t, []map[string]string{}, resp.Tables[0].Rows, "Expected to see no changes, but did not")
+
+		cm := NewPresentClusterResource("configmap", "test-cm", env.Namespace, kubectl)
+		data := cm.RawPath(ctlres.NewPathFromStrings([]string{"data"})).(map[string]interface{})
+
+		require.Equal(t, map[string]interface{}{"key1": "val1", "counter": "5"}, data)
+	})
+}
+
 func asYAML(t *testing.T, val interface{}) string {
 	bs, err := yaml.Marshal(val)
 	require.NoError(t, err)
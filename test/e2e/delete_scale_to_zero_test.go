@@ -0,0 +1,66 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteScaleToZero(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: graceful
+  annotations:
+    kapp.k14s.io/delete-strategy: scale-to-zero
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: graceful
+  template:
+    metadata:
+      labels:
+        app: graceful
+    spec:
+      containers:
+      - name: app
+        image: busybox
+        command: ["sleep", "3600"]
+`
+
+	name := "test-delete-scale-to-zero"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		NewPresentClusterResource("deployment", "graceful", env.Namespace, kubectl)
+	})
+
+	logger.Section("delete scales down before deleting", func() {
+		out, _ := kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{})
+
+		require.Contains(t, out, "scale-to-zero")
+		NewMissingClusterResource(t, "deployment", "graceful", env.Namespace, kubectl)
+
+		pods := kubectl.Run([]string{"get", "pods", "-l", "app=graceful", "-o", "name"})
+		require.Empty(t, strings.TrimSpace(pods))
+	})
+}
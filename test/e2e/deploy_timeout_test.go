@@ -0,0 +1,81 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployTimeout(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-job
+  annotations:
+    kapp.k14s.io/change-group: "slow"
+spec:
+  template:
+    metadata:
+      name: slow-job
+    spec:
+      containers:
+      - name: slow-job
+        image: busybox
+        command: ["/bin/sh", "-c", "sleep 60"]
+      restartPolicy: Never
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: after-slow-job
+  annotations:
+    kapp.k14s.io/change-rule: "upsert after upserting slow"
+`
+
+	name := "test-deploy-timeout"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy stops applying new changes after deploy timeout", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--deploy-timeout", "5s"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Deploy timed out after 5s")
+		require.Contains(t, err.Error(), "Stopped applying changes (deadline exceeded): applied 1/2")
+		require.Contains(t, err.Error(), "exit code: '4'")
+
+		_, err = kubectl.RunWithOpts([]string{"get", "configmap", "after-slow-job"}, RunOpts{AllowError: true})
+		require.Error(t, err, "Expected config map to not be created")
+	})
+
+	logger.Section("deploy timeout includes time spent calculating changes", func() {
+		cleanUp()
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--deploy-timeout", "1ns"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Deploy timed out after 1ns")
+		require.Contains(t, err.Error(), "applied 0/")
+		require.Contains(t, err.Error(), "exit code: '4'")
+
+		_, err = kubectl.RunWithOpts([]string{"get", "job", "slow-job"}, RunOpts{AllowError: true})
+		require.Error(t, err, "Expected job to not be created")
+	})
+}
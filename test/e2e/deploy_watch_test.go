@@ -0,0 +1,95 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployWatch(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: watched
+data:
+  key: value1
+`
+
+	yaml2 := strings.Replace(yaml1, "value1", "value2", 1)
+
+	name := "test-deploy-watch"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+
+	require.NoError(t, os.WriteFile(path, []byte(yaml1), 0600))
+
+	waitForValue := func(expectedVal string) {
+		var val string
+		for i := 0; i < 60; i++ {
+			out, err := kubectl.RunWithOpts([]string{"get", "configmap", "watched",
+				"-o", "jsonpath={.data.key}"}, RunOpts{AllowError: true})
+			if err == nil && out == expectedVal {
+				return
+			}
+			val = out
+			time.Sleep(1 * time.Second)
+		}
+		require.FailNowf(t, "Timed out waiting for config map value", "Expected '%s', but was '%s'", expectedVal, val)
+	}
+
+	logger.Section("watch deploys, re-deploys changed inputs and corrects drift", func() {
+		cancelCh := make(chan struct{})
+		doneCh := make(chan struct{})
+
+		var out string
+		var err error
+
+		go func() {
+			out, err = kapp.RunWithOpts([]string{"deploy", "-f", path, "-a", name, "--watch",
+				"--watch-resync-interval", "5s", "--watch-poll-interval", "500ms"},
+				RunOpts{IntoNs: true, AllowError: true, CancelCh: cancelCh})
+			close(doneCh)
+		}()
+
+		waitForValue("value1")
+
+		require.NoError(t, os.WriteFile(path, []byte(yaml2), 0600))
+		waitForValue("value2")
+
+		kubectl.Run([]string{"patch", "configmap", "watched", "--type=merge", "-p", `{"data":{"key":"drifted"}}`})
+		waitForValue("value2")
+
+		close(cancelCh)
+		<-doneCh
+
+		// Interrupt may land in the middle of periodic re-deploy
+		if err != nil {
+			require.Contains(t, err.Error(), "Stopped applying changes (interrupted)")
+		} else {
+			require.Contains(t, out, "Received interrupt: stopped watching")
+		}
+		require.Contains(t, out, "re-deploying (input files changed)")
+		require.Contains(t, out, "re-deploying (resync interval passed)")
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisown(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+data:
+  key: value
+`
+
+	name := "test-disown"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kubectl.RunWithOpts([]string{"delete", "configmap", "first", "--ignore-not-found"}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("requires selection", func() {
+		_, err := kapp.RunWithOpts([]string{"disown", "-a", name}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected either resources to be selected via filter flags or --all to be specified")
+	})
+
+	logger.Section("disown single resource", func() {
+		kapp.Run([]string{"disown", "-a", name, "--filter-kind-name", "ConfigMap/first"})
+
+		cm := NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		require.NotContains(t, cm.Labels(), "kapp.k14s.io/app")
+		require.NotContains(t, cm.Labels(), "kapp.k14s.io/association")
+
+		out := kapp.Run([]string{"inspect", "-a", name, "--json"})
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+		require.Equal(t, "second", resp.Tables[0].Rows[0]["name"])
+	})
+
+	logger.Section("delete keeps disowned resource", func() {
+		kapp.Run([]string{"delete", "-a", name})
+
+		NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "second", env.Namespace, kubectl)
+	})
+}
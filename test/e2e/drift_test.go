@@ -0,0 +1,98 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrift(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+data:
+  key: value
+`
+
+	name := "test-drift"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("no drift", func() {
+		out := kapp.Run([]string{"drift", "-a", name, "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 2)
+		for _, row := range resp.Tables[0].Rows {
+			require.Equal(t, "false", row["drifted"])
+		}
+
+		_, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exit status 2")
+	})
+
+	logger.Section("drift after modification outside of kapp", func() {
+		kubectl.Run([]string{"patch", "configmap", "first", "--type=merge", "-p", `{"data":{"key":"changed","other":"value"}}`})
+
+		out := kapp.Run([]string{"drift", "-a", name, "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 2)
+
+		for _, row := range resp.Tables[0].Rows {
+			switch row["name"] {
+			case "first":
+				require.Equal(t, "true", row["drifted"])
+				require.Equal(t, "/data/key\n/data/other", row["fields"])
+			case "second":
+				require.Equal(t, "false", row["drifted"])
+			default:
+				t.Fatalf("Unexpected resource: %#v", row)
+			}
+		}
+
+		_, err := kapp.RunWithOpts([]string{"drift", "-a", name, "--exit-status"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exit status 3")
+	})
+
+	logger.Section("no drift after redeploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		out := kapp.Run([]string{"drift", "-a", name, "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		for _, row := range resp.Tables[0].Rows {
+			require.Equal(t, "false", row["drifted"])
+		}
+	})
+}
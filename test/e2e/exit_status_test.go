@@ -0,0 +1,93 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitStatus(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	policy := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Policy
+metadata:
+  name: no-secrets
+preflightRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Secret}
+  operations: [create]
+  message: secrets are managed externally
+`
+
+	secretYAML := `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+stringData:
+  key: value
+`
+
+	slowJobYAML := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-job
+spec:
+  template:
+    metadata:
+      name: slow-job
+    spec:
+      containers:
+      - name: slow-job
+        image: busybox
+        command: ["/bin/sh", "-c", "sleep 60"]
+      restartPolicy: Never
+`
+
+	name := "test-exit-status"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("usage error", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-a", name, "--unknown-flag"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown flag: --unknown-flag")
+		require.Contains(t, err.Error(), "exit code: '64'")
+
+		_, err = kapp.RunWithOpts([]string{"ls", "extra"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "exit code: '64'")
+	})
+
+	logger.Section("preflight error", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--policy", "no-secrets"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(policy + secretYAML)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "secrets are managed externally")
+		require.Contains(t, err.Error(), "exit code: '6'")
+	})
+
+	logger.Section("wait timeout", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait-timeout", "2s"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(slowJobYAML)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Timed out waiting after 2s")
+		require.Contains(t, err.Error(), "exit code: '4'")
+	})
+}
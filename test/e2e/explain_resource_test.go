@@ -0,0 +1,83 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainResource(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  annotations:
+    kapp.k14s.io/update-strategy: fallback-on-replace
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+changeGroupBindings:
+- name: example.com/configs
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+`
+
+	name := "test-explain-resource"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("explain resource of new app", func() {
+		out, _ := kapp.RunWithOpts([]string{"explain-resource", "-f", "-", "-a", name, "--resource", "configmap/config"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "Resource configmap/config (v1) namespace: "+env.Namespace)
+		require.Contains(t, out, "create (wait: reconcile)")
+		require.Contains(t, out, "example.com/configs")
+		require.Contains(t, out, "kapp.k14s.io/app=")
+		require.Contains(t, out, "change group binding 0")
+		require.Contains(t, out, "group: example.com/configs")
+		require.NotContains(t, out, "configmap/other")
+
+		NewMissingClusterResource(t, "configmap", "config", env.Namespace, Kubectl{t, env.Namespace, logger})
+	})
+
+	logger.Section("explain resource of deployed app", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		out, _ := kapp.RunWithOpts([]string{"explain-resource", "-f", "-", "-a", name, "--resource", "ConfigMap/config"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "none (resource is up to date)")
+		require.Contains(t, out, "kapp.k14s.io/update-strategy: fallback-on-replace")
+	})
+
+	logger.Section("explain missing resource", func() {
+		_, err := kapp.RunWithOpts([]string{"explain-resource", "-f", "-", "-a", name, "--resource", "configmap/missing"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1), AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected to find resource 'configmap/missing' in provided files")
+	})
+}
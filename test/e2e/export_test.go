@@ -0,0 +1,121 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestExport(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis-primary
+spec:
+  ports:
+  - port: 6380
+    targetPort: 6380
+  selector:
+    app: redis
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  annotations:
+    kapp.k14s.io/change-group: "group1"
+data:
+  key: value
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+rebaseRules:
+- path: [data, other]
+  type: copy
+  sources: [new, existing]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+`
+
+	name := "test-export"
+	exportedName := "test-export-exported"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.Run([]string{"delete", "-a", exportedName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	outputDir, err := os.MkdirTemp("", "kapp-test-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(outputDir)
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("export as yaml stream", func() {
+		out := kapp.Run([]string{"export", "-a", name, "--tty=false"})
+
+		resources, err := ctlres.NewResourcesFromBytes([]byte(out))
+		require.NoError(t, err)
+		require.Len(t, resources, 3)
+
+		for _, res := range resources {
+			switch res.Kind() {
+			case "Service":
+				bs, err := res.AsYAMLBytes()
+				require.NoError(t, err)
+				require.NotContains(t, string(bs), "clusterIP:")
+				require.NotContains(t, string(bs), "status:")
+			case "ConfigMap":
+				require.Equal(t, map[string]string{"kapp.k14s.io/change-group": "group1"}, res.Annotations())
+				require.Empty(t, res.Labels())
+				require.Empty(t, res.UID())
+			case "Config":
+			default:
+				t.Fatalf("Unexpected resource: %s", res.Description())
+			}
+		}
+	})
+
+	logger.Section("export into directory and deploy as another app", func() {
+		kapp.Run([]string{"export", "-a", name, "--output-dir", outputDir})
+
+		entries, err := os.ReadDir(outputDir)
+		require.NoError(t, err)
+
+		var fileNames []string
+		for _, entry := range entries {
+			fileNames = append(fileNames, entry.Name())
+		}
+		require.Equal(t, []string{
+			"configmap_" + env.Namespace + "_config.yml",
+			"kapp-config.yml",
+			"service_" + env.Namespace + "_redis-primary.yml",
+		}, fileNames)
+
+		_, err = os.Stat(filepath.Join(outputDir, "kapp-config.yml"))
+		require.NoError(t, err)
+
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.Run([]string{"deploy", "-a", exportedName, "-f", outputDir})
+
+		NewPresentClusterResource("configmap", "config", env.Namespace, Kubectl{t, env.Namespace, logger})
+		NewPresentClusterResource("service", "redis-primary", env.Namespace, Kubectl{t, env.Namespace, logger})
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCExpiredApps(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: __name__
+data:
+  key: value
+`
+
+	expiredName := "test-gc-expired"
+	protectedName := "test-gc-protected"
+	keptName := "test-gc-kept"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", expiredName})
+		kapp.Run([]string{"delete", "-a", protectedName, "--unprotect"})
+		kapp.Run([]string{"delete", "-a", keptName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	deploy := func(name string, args ...string) {
+		kapp.RunWithOpts(append([]string{"deploy", "-f", "-", "-a", name}, args...),
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__name__", name))})
+	}
+
+	logger.Section("deploy apps", func() {
+		deploy(expiredName, "--ttl", "1s")
+		deploy(protectedName, "--ttl", "1s", "--protect")
+		deploy(keptName, "--ttl", "1h")
+	})
+
+	logger.Section("garbage collect expired apps", func() {
+		time.Sleep(2 * time.Second)
+
+		out := kapp.Run([]string{"gc"})
+		require.Contains(t, out, "Skipping expired app '"+protectedName+"'")
+
+		NewMissingClusterResource(t, "configmap", expiredName, env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", protectedName, env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", keptName, env.Namespace, kubectl)
+	})
+
+	logger.Section("remove TTL", func() {
+		deploy(keptName, "--ttl", "0s")
+
+		out := kapp.Run([]string{"gc"})
+		require.NotContains(t, out, keptName)
+	})
+}
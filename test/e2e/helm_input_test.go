@@ -0,0 +1,70 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelmInput(t *testing.T) {
+	if _, err := exec.LookPath("helm"); err != nil {
+		t.Skip("Skipping test as helm binary is not available")
+	}
+
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "chart")
+
+	files := map[string]string{
+		"chart/Chart.yaml": `
+apiVersion: v2
+name: test-chart
+version: 0.1.0
+`,
+		"chart/values.yaml": `
+value: default
+`,
+		"chart/templates/configmap.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  key: {{ .Values.value }}
+`,
+		"values.yml": `
+value: custom
+`,
+	}
+
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0600))
+	}
+
+	name := "test-helm-input"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy local chart", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", chartDir, "-a", name,
+			"--helm-values", filepath.Join(dir, "values.yml")}, RunOpts{IntoNs: true})
+
+		out := kubectl.Run([]string{"get", "configmap", name + "-config", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "custom", out)
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImagesLock(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	// ConfigMap is used to avoid pulling images; any 'image' key is resolved
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: images
+data:
+  image: nginx:1.25
+`
+
+	digestRef := "index.docker.io/library/nginx@sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, "images.lock.yml")
+	lockOutputPath := filepath.Join(dir, "images.lock.out.yml")
+
+	err := os.WriteFile(lockPath, []byte(`
+apiVersion: imgpkg.carvel.dev/v1alpha1
+kind: ImagesLock
+images:
+- image: `+digestRef+`
+  annotations:
+    kbld.carvel.dev/id: nginx:1.25
+`), 0600)
+	require.NoError(t, err)
+
+	name := "test-images-lock"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with images lock", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--images-lock", lockPath, "--kbld-lock-output", lockOutputPath},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		out := kubectl.Run([]string{"get", "configmap", "images", "-o", "jsonpath={.data.image}"})
+		require.Equal(t, digestRef, out)
+
+		lockOutput, err := os.ReadFile(lockOutputPath)
+		require.NoError(t, err)
+		require.Contains(t, string(lockOutput), "image: "+digestRef)
+		require.Contains(t, string(lockOutput), "kbld.carvel.dev/id: nginx:1.25")
+
+		out = kapp.Run([]string{"app-change", "describe", "-a", name})
+		require.Contains(t, out, "- nginx:1.25 -> "+digestRef)
+	})
+
+	logger.Section("deploy with image missing from images lock", func() {
+		yaml2 := strings.Replace(yaml1, "nginx:1.25", "redis:7", 1)
+
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--images-lock", lockPath},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2), AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected image 'redis:7' of configmap/images (v1) namespace: "+env.Namespace+" to be resolved to a digest")
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonationRecordedWithApp(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	rbac := `
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: impersonated-sa
+  namespace: __ns__
+---
+kind: Role
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: impersonated-role
+  namespace: __ns__
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["*"]
+---
+kind: RoleBinding
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: impersonated-role-binding
+  namespace: __ns__
+subjects:
+- kind: ServiceAccount
+  name: impersonated-sa
+  namespace: __ns__
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: impersonated-role
+`
+
+	rbac = strings.ReplaceAll(rbac, "__ns__", env.Namespace)
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: value
+`
+
+	yaml2 := yaml1 + `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+stringData:
+  key: value
+`
+
+	rbacName := "test-impersonation-rbac"
+	name := "test-impersonation"
+	sa := "system:serviceaccount:" + env.Namespace + ":impersonated-sa"
+
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.Run([]string{"delete", "-a", rbacName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	kapp.RunWithOpts([]string{"deploy", "-a", rbacName, "-f", "-"}, RunOpts{StdinReader: strings.NewReader(rbac)})
+
+	logger.Section("deploy new app with impersonation", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--as", sa},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		NewPresentClusterResource("configmap", "config", env.Namespace, kubectl)
+	})
+
+	logger.Section("subsequent deploy uses recorded identity", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml2)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "forbidden")
+
+		NewMissingClusterResource(t, "secret", "secret", env.Namespace, kubectl)
+	})
+
+	logger.Section("delete uses recorded identity", func() {
+		kapp.Run([]string{"delete", "-a", name})
+
+		NewMissingClusterResource(t, "configmap", "config", env.Namespace, kubectl)
+	})
+}
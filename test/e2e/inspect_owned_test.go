@@ -0,0 +1,107 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectTreeIncludeOwned(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: parent
+`
+
+	// Resources created outside of kapp (e.g. by a controller) are not labeled
+	ownedYAML := func(name, ownerName, ownerUID string) string {
+		return fmt.Sprintf(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  ownerReferences:
+  - apiVersion: v1
+    kind: ConfigMap
+    name: %s
+    uid: %s
+`, name, ownerName, ownerUID)
+	}
+
+	name := "test-inspect-tree-include-owned"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kubectl.RunWithOpts([]string{"delete", "configmap", "child", "grandchild", "--ignore-not-found"}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy and create owned resources", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		parentUID := kubectl.Run([]string{"get", "configmap", "parent", "-o", "jsonpath={.metadata.uid}"})
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(ownedYAML("child", "parent", parentUID))})
+
+		childUID := kubectl.Run([]string{"get", "configmap", "child", "-o", "jsonpath={.metadata.uid}"})
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(ownedYAML("grandchild", "child", childUID))})
+	})
+
+	logger.Section("tree inspect without owned resources", func() {
+		out := kapp.Run([]string{"inspect", "-a", name, "-t", "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+		require.Equal(t, "parent", resp.Tables[0].Rows[0]["name"])
+	})
+
+	logger.Section("tree inspect with owned resources", func() {
+		out := kapp.Run([]string{"inspect", "-a", name, "-t", "--include-owned", "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		expected := []map[string]string{{
+			"age":             "<replaced>",
+			"kind":            "ConfigMap",
+			"name":            "parent",
+			"namespace":       env.Namespace,
+			"owner":           "kapp",
+			"reconcile_info":  "",
+			"reconcile_state": "ok",
+		}, {
+			"age":             "<replaced>",
+			"kind":            "ConfigMap",
+			"name":            " L child",
+			"namespace":       env.Namespace,
+			"owner":           "cluster",
+			"reconcile_info":  "",
+			"reconcile_state": "ok",
+		}, {
+			"age":             "<replaced>",
+			"kind":            "ConfigMap",
+			"name":            " L.. grandchild",
+			"namespace":       env.Namespace,
+			"owner":           "cluster",
+			"reconcile_info":  "",
+			"reconcile_state": "ok",
+		}}
+
+		require.Exactlyf(t, expected, replaceAge(resp.Tables[0].Rows), "Expected to see owned resources nested under their owners")
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKustomizeInput(t *testing.T) {
+	if _, err := exec.LookPath("kustomize"); err != nil {
+		t.Skip("Skipping test as kustomize binary is not available")
+	}
+
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"base/kustomization.yaml": `
+resources:
+- configmap.yaml
+`,
+		"base/configmap.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  key: base
+`,
+		"overlay/kustomization.yaml": `
+resources:
+- ../base
+namePrefix: prod-
+`,
+	}
+
+	for path, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0600))
+	}
+
+	name := "test-kustomize-input"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy kustomization overlay", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", filepath.Join(dir, "overlay"), "-a", name}, RunOpts{IntoNs: true})
+
+		out := kubectl.Run([]string{"get", "configmap", "prod-config", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "base", out)
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestListWithDeploymentMetadata(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+	name := "test-list-metadata"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+	var listedApp map[string]string
+
+	logger.Section("wide table", func() {
+		out := kapp.Run([]string{"ls", "--wide", "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		for _, row := range resp.Tables[0].Rows {
+			if row["name"] == name {
+				listedApp = row
+			}
+		}
+
+		require.NotNil(t, listedApp, "Expected to find app")
+		require.Equal(t, "deploy", listedApp["last_change_operation"])
+		require.Equal(t, "2", listedApp["resources"])
+		require.NotEmpty(t, listedApp["last_successful_change_age"])
+	})
+
+	type listedAppChange struct {
+		Name         string
+		Operation    string
+		NumResources int
+		Successful   *bool
+	}
+
+	type listedAppOutput struct {
+		Name                 string
+		LastChange           *listedAppChange
+		LastSuccessfulChange *listedAppChange
+	}
+
+	findApp := func(apps []listedAppOutput) listedAppOutput {
+		for _, app := range apps {
+			if app.Name == name {
+				return app
+			}
+		}
+		t.Fatalf("Expected to find app '%s'", name)
+		return listedAppOutput{}
+	}
+
+	logger.Section("json output", func() {
+		out := kapp.Run([]string{"ls", "-o", "json"})
+
+		var apps []listedAppOutput
+		require.NoError(t, json.Unmarshal([]byte(out), &apps))
+
+		app := findApp(apps)
+		require.NotNil(t, app.LastChange)
+		require.Equal(t, "deploy", app.LastChange.Operation)
+		require.Equal(t, 2, app.LastChange.NumResources)
+		require.True(t, *app.LastChange.Successful)
+		require.NotNil(t, app.LastSuccessfulChange)
+		require.Equal(t, app.LastChange.Name, app.LastSuccessfulChange.Name)
+	})
+
+	logger.Section("yaml output", func() {
+		out := kapp.Run([]string{"ls", "-o", "yaml"})
+
+		var apps []listedAppOutput
+		require.NoError(t, yaml.Unmarshal([]byte(out), &apps))
+
+		app := findApp(apps)
+		require.Equal(t, "deploy", app.LastChange.Operation)
+	})
+
+	logger.Section("unknown output format", func() {
+		_, err := kapp.RunWithOpts([]string{"ls", "-o", "xml"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unknown output format 'xml'")
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFormatJSON(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: value
+`
+
+	name := "test-log-format-json"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	type logLine struct {
+		Level     string
+		Subsystem string
+		Logger    string
+		Msg       string
+	}
+
+	parseLogLines := func(out string) []logLine {
+		var lines []logLine
+		for _, line := range strings.Split(out, "\n") {
+			if !strings.HasPrefix(line, `{"time":`) {
+				continue
+			}
+			var parsed logLine
+			require.NoError(t, json.Unmarshal([]byte(line), &parsed), "Expected log line to be JSON: %s", line)
+			lines = append(lines, parsed)
+		}
+		return lines
+	}
+
+	logger.Section("deploy with wait subsystem at debug level", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name,
+			"--log-format", "json", "--log-level", "wait=debug"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		var debugLines []logLine
+		for _, line := range parseLogLines(out) {
+			if line.Level == "debug" {
+				debugLines = append(debugLines, line)
+			}
+		}
+		require.NotEmpty(t, debugLines)
+
+		for _, line := range debugLines {
+			require.Equal(t, "wait", line.Subsystem, "Expected only wait subsystem to log debug messages")
+			require.Contains(t, line.Logger, "WaitingChanges")
+		}
+	})
+
+	logger.Section("deploy with debug enabled", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--log-format", "json", "--debug"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		subsystems := map[string]bool{}
+		for _, line := range parseLogLines(out) {
+			subsystems[line.Subsystem] = true
+		}
+		require.True(t, subsystems["diff"], "Expected diff subsystem logs")
+		require.True(t, subsystems["apply"], "Expected apply subsystem logs")
+	})
+
+	logger.Section("invalid log level", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--log-level", "unknown=debug"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unknown log subsystem 'unknown'")
+	})
+}
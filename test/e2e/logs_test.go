@@ -0,0 +1,79 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogs(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  labels:
+    tier: web
+spec:
+  containers:
+  - name: first
+    image: busybox
+    command: ["/bin/sh", "-c", "echo from-first; sleep 3600"]
+  - name: second
+    image: busybox
+    command: ["/bin/sh", "-c", "echo from-second; sleep 3600"]
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: worker
+  labels:
+    tier: worker
+spec:
+  containers:
+  - name: first
+    image: busybox
+    command: ["/bin/sh", "-c", "echo from-worker; sleep 3600"]
+`
+
+	name := "test-logs"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("select pods by label and containers by name", func() {
+		out := kapp.Run([]string{"logs", "-a", name, "-l", "tier=web", "--container", "second"})
+		require.Contains(t, out, "web > second | from-second")
+		require.NotContains(t, out, "from-first")
+		require.NotContains(t, out, "from-worker")
+	})
+
+	logger.Section("limit logs by time without line limit", func() {
+		out := kapp.Run([]string{"logs", "-a", name, "--since", "1h", "--lines", "-1"})
+		require.Contains(t, out, "web > first | from-first")
+		require.Contains(t, out, "worker > first | from-worker")
+	})
+
+	logger.Section("reject invalid pod selector", func() {
+		_, err := kapp.RunWithOpts([]string{"logs", "-a", name, "-l", "tier in (web"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Parsing pod selector")
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateApp(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	nsYAML := `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: kapp-test-migrate-app
+`
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+`
+
+	nsAppName := "test-migrate-app-ns"
+	name := "test-migrate-app"
+	newNs := "kapp-test-migrate-app"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.RunWithOpts([]string{"delete", "-a", name, "--app-namespace", newNs}, RunOpts{})
+		kapp.Run([]string{"delete", "-a", nsAppName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", nsAppName}, RunOpts{StdinReader: strings.NewReader(nsYAML)})
+
+	logger.Section("migrate app metadata", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		kapp.Run([]string{"migrate-app", "-a", name, "--new-namespace", newNs})
+
+		NewMissingClusterResource(t, "configmap", name, env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", name, newNs, kubectl)
+		NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+	})
+
+	logger.Section("inspect migrated app", func() {
+		out := kapp.Run([]string{"inspect", "-a", name, "--app-namespace", newNs, "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+
+		out = kapp.Run([]string{"app-change", "list", "-a", name, "--app-namespace", newNs, "--json"})
+
+		resp = uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+	})
+}
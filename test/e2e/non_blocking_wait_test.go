@@ -0,0 +1,72 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonBlockingWaitRules(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: canary-job
+  annotations:
+    kapp.k14s.io/change-group: "canary"
+spec:
+  template:
+    metadata:
+      name: canary-job
+    spec:
+      restartPolicy: Never
+      containers:
+        - name: canary-job
+          image: busybox
+          command: [ "sh", "-c", "exit 1" ]
+  backoffLimit: 0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: after-canary
+  annotations:
+    kapp.k14s.io/change-rule: "upsert after upserting canary"
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+nonBlockingWaitRules:
+- resourceMatchers:
+  - kindNamespaceNameMatcher:
+      kind: Job
+      namespace: kapp-test
+      name: canary-job
+`
+
+	name := "test-non-blocking-wait-rules"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with failing non-blocking resource", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "Warning: Ignoring failure of non-blocking resource: waiting on reconcile job/canary-job (batch/v1)")
+
+		NewPresentClusterResource("configmap", "after-canary", env.Namespace, kubectl)
+	})
+}
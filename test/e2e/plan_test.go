@@ -0,0 +1,86 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanAndApplyPlan(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: value1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+`
+
+	yaml2 := strings.ReplaceAll(yaml1, "value1", "value2")
+
+	name := "test-plan"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	planFile, err := os.CreateTemp(os.TempDir(), "plan")
+	require.NoError(t, err)
+	defer os.Remove(planFile.Name())
+
+	logger.Section("plan and apply plan for new app", func() {
+		kapp.RunWithOpts([]string{"plan", "-f", "-", "-a", name, "--plan-file", planFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		NewMissingClusterResource(t, "configmap", "cm1", env.Namespace, kubectl)
+
+		kapp.RunWithOpts([]string{"apply-plan", "--plan-file", planFile.Name()}, RunOpts{NoNamespace: true})
+
+		NewPresentClusterResource("configmap", "cm1", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "cm2", env.Namespace, kubectl)
+	})
+
+	logger.Section("apply plan fails when cluster drifted", func() {
+		kapp.RunWithOpts([]string{"plan", "-f", "-", "-a", name, "--plan-file", planFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		kubectl.Run([]string{"patch", "configmap", "cm1", "--type=merge", "-p", `{"data":{"key":"value3"}}`})
+
+		_, err := kapp.RunWithOpts([]string{"apply-plan", "--plan-file", planFile.Name()},
+			RunOpts{NoNamespace: true, AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Cluster has drifted since planning")
+	})
+
+	logger.Section("apply plan fails when plan was modified", func() {
+		kapp.RunWithOpts([]string{"plan", "-f", "-", "-a", name, "--plan-file", planFile.Name()},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		planBs, err := os.ReadFile(planFile.Name())
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(planFile.Name(), []byte(strings.ReplaceAll(string(planBs), "value2", "value4")), 0600))
+
+		_, err = kapp.RunWithOpts([]string{"apply-plan", "--plan-file", planFile.Name()},
+			RunOpts{NoNamespace: true, AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected plan file signature to match its contents")
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	policy := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Policy
+metadata:
+  name: prod
+preflightRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Secret}
+  operations: [create]
+  message: secrets are managed externally
+protectedResourceMatchers:
+- kindNamespaceNameMatcher: {kind: ConfigMap, namespace: __ns__, name: important}
+allowedNamespaces: [__ns__]
+`
+
+	policy = strings.ReplaceAll(policy, "__ns__", env.Namespace)
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: important
+data:
+  key: value
+`
+
+	secretYAML := `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+stringData:
+  key: value
+`
+
+	otherNsYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other
+  namespace: kube-system
+data:
+  key: value
+`
+
+	name := "test-policy"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	deploy := func(yaml string) error {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--policy", "prod"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(policy + yaml)})
+		return err
+	}
+
+	logger.Section("deploy allowed resources", func() {
+		require.NoError(t, deploy(yaml1))
+	})
+
+	logger.Section("reject resource creation based on preflight rule", func() {
+		err := deploy(yaml1 + secretYAML)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Resource 'secret/secret (v1) namespace: "+env.Namespace+"' cannot be created (policy: prod): secrets are managed externally")
+
+		NewMissingClusterResource(t, "secret", "secret", env.Namespace, kubectl)
+	})
+
+	logger.Section("reject resource in namespace that is not allowed", func() {
+		err := deploy(yaml1 + otherNsYAML)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is outside of allowed namespaces (policy: prod)")
+	})
+
+	logger.Section("reject deletion of protected resource", func() {
+		err := deploy(strings.ReplaceAll(yaml1, "important", "replacement"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is protected from deletion (policy: prod)")
+
+		NewPresentClusterResource("configmap", "important", env.Namespace, kubectl)
+	})
+
+	logger.Section("reject unknown policy", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--policy", "unknown"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(policy + yaml1)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected to find policy 'unknown'")
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightRules(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	// Denies changes to resources whose names start with 'tmp-'
+	config := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+preflightRules:
+- name: naming
+  timeout: 10s
+  exec:
+    command: sh
+    args:
+    - -c
+    - 'if grep -q "\"name\":\"tmp-"; then echo "temporary names are not allowed"; exit 1; fi'
+`
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: allowed
+`
+
+	deniedYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: tmp-denied
+`
+
+	name := "test-preflight-rules"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	deploy := func(yaml string) (string, error) {
+		return kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(config + yaml)})
+	}
+
+	logger.Section("deploy changes allowed by check", func() {
+		out, err := deploy(yaml1)
+		require.NoError(t, err)
+		require.Contains(t, out, "Running preflight check 'naming' (exec: sh)")
+
+		NewPresentClusterResource("configmap", "allowed", env.Namespace, kubectl)
+	})
+
+	logger.Section("reject changes denied by check", func() {
+		_, err := deploy(yaml1 + deniedYAML)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Check 'naming' denied changes: temporary names are not allowed")
+
+		NewMissingClusterResource(t, "configmap", "tmp-denied", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressFormatNDJSON(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+	name := "test-progress-format-ndjson"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	type progressEvent struct {
+		Type     string
+		Resource *struct {
+			Name string
+			Kind string
+		}
+		Ops        map[string]int
+		Successful *bool
+	}
+
+	parseEvents := func(out string) []progressEvent {
+		var events []progressEvent
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			var event progressEvent
+			require.NoError(t, json.Unmarshal([]byte(line), &event), "Expected each line to be JSON: %s", line)
+			events = append(events, event)
+		}
+		return events
+	}
+
+	countEvents := func(events []progressEvent, eventType string) int {
+		var count int
+		for _, event := range events {
+			if event.Type == eventType {
+				count++
+			}
+		}
+		return count
+	}
+
+	logger.Section("deploy", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--progress-format", "ndjson"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		events := parseEvents(out)
+
+		require.Equal(t, "diffComputed", events[0].Type)
+		require.Equal(t, 2, events[0].Ops["add"])
+		require.Equal(t, 2, countEvents(events, "changeApplied"))
+		require.Equal(t, 2, countEvents(events, "resourceReady"))
+
+		last := events[len(events)-1]
+		require.Equal(t, "completed", last.Type)
+		require.True(t, *last.Successful)
+	})
+
+	logger.Section("delete", func() {
+		out := kapp.Run([]string{"delete", "-a", name, "--progress-format", "ndjson"})
+
+		events := parseEvents(out)
+
+		require.Equal(t, 2, events[0].Ops["delete"])
+		require.Equal(t, 2, countEvents(events, "changeApplied"))
+		require.Equal(t, "completed", events[len(events)-1].Type)
+	})
+
+	logger.Section("conflicting output flags", func() {
+		_, err := kapp.RunWithOpts([]string{"delete", "-a", name, "--progress-format", "ndjson", "-o", "json"},
+			RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected --progress-format=ndjson to not be used together with --output json")
+	})
+}
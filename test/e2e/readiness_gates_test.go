@@ -0,0 +1,74 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessGates(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	depYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dep
+`
+
+	appYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: gated
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: ReadinessGates
+timeout: 3s
+checkInterval: 1s
+gates:
+- app:
+    name: test-readiness-gates-dep
+`
+
+	depName := "test-readiness-gates-dep"
+	name := "test-readiness-gates"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.Run([]string{"delete", "-a", depName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy with app gate that is not ready", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(appYAML)})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Timed out waiting after 3s for readiness gate app 'test-readiness-gates-dep': App does not exist")
+
+		NewMissingClusterResource(t, "configmap", "gated", env.Namespace, kubectl)
+	})
+
+	logger.Section("deploy with app gate that is ready", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", depName},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(depYAML)})
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(appYAML)})
+
+		require.Contains(t, out, "Waiting for readiness gate app 'test-readiness-gates-dep'")
+
+		NewPresentClusterResource("configmap", "gated", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameAppKeepsAppChanges(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+`
+
+	name := "test-rename-app"
+	newName := "test-rename-app-new"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+		kapp.Run([]string{"delete", "-a", newName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy and rename", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		kapp.Run([]string{"rename-app", "-a", name, "--new-name", newName})
+
+		NewMissingClusterResource(t, "configmap", name, env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", newName, env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+	})
+
+	logger.Section("app changes are available under new name", func() {
+		out := kapp.Run([]string{"app-change", "list", "-a", newName, "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+	})
+
+	logger.Section("resources are still owned by renamed app", func() {
+		out := kapp.Run([]string{"inspect", "-a", newName, "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+		require.Equal(t, "first", resp.Tables[0].Rows[0]["name"])
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskConfirmation(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: kapp-risk-confirmation
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+	name := "test-risk-confirmation"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("delete requires typing app name", func() {
+		out, err := kapp.RunWithOpts([]string{"delete", "-a", name, "--tty"},
+			RunOpts{Interactive: true, AllowError: true, StdinReader: strings.NewReader("wrong-name\n")})
+		require.Error(t, err)
+		require.Contains(t, out, "High-risk changes (1):")
+		require.Contains(t, out, "- delete namespace: namespace/kapp-risk-confirmation (v1) cluster")
+		require.Contains(t, err.Error(), "Stopped due to typed app name not matching '"+name+"'")
+		require.Contains(t, err.Error(), "exit code: '7'")
+
+		NewPresentClusterResource("namespace", "kapp-risk-confirmation", "", kubectl)
+	})
+
+	logger.Section("delete with typed app name", func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name, "--tty"},
+			RunOpts{Interactive: true, StdinReader: strings.NewReader(name + "\n")})
+
+		NewMissingClusterResource(t, "namespace", "kapp-risk-confirmation", "", kubectl)
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlapp "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/app"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestRollback(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: v1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: third
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: v2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+	name := "test-rollback"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy two versions", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		NewPresentClusterResource("configmap", "second", env.Namespace, kubectl)
+	})
+
+	logger.Section("rollback to previous change", func() {
+		out := kapp.Run([]string{"rollback", "-a", name})
+		require.Contains(t, out, "Rolling back to app change")
+
+		firstRes := NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		require.Equal(t, map[string]interface{}{"key": "v1"}, firstRes.RawPath(ctlres.NewPathFromStrings([]string{"data"})))
+
+		NewMissingClusterResource(t, "configmap", "second", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "third", env.Namespace, kubectl)
+	})
+
+	logger.Section("rollback to unknown change", func() {
+		_, err := kapp.RunWithOpts([]string{"rollback", "-a", name, "--to-change", "unknown"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected to find app change 'unknown'")
+	})
+}
+
+func TestRollbackWithSecrets(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+stringData:
+  password: my-password
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: v1
+`
+
+	yaml2 := strings.Replace(yaml1, "key: v1", "key: v2", 1)
+
+	name := "test-rollback-with-secrets"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("recorded input does not include secret values", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		out := kubectl.Run([]string{"get", "configmaps", "-o", "json"})
+
+		var list struct {
+			Items []struct {
+				Metadata struct{ Name string }
+				Data     map[string]string
+			}
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &list))
+
+		var numInputs int
+		for _, item := range list.Items {
+			if !strings.HasPrefix(item.Metadata.Name, name+"-change-") || len(item.Data["input"]) == 0 {
+				continue
+			}
+			input, err := ctlapp.NewChangeInputFromEncodedString(item.Data["input"])
+			require.NoError(t, err)
+
+			for _, res := range input.Resources {
+				bs, err := res.AsYAMLBytes()
+				require.NoError(t, err)
+				require.NotContains(t, string(bs), "my-password")
+			}
+			numInputs++
+		}
+		require.Equal(t, 2, numInputs)
+	})
+
+	logger.Section("rollback keeps secret values from the cluster", func() {
+		kapp.Run([]string{"rollback", "-a", name})
+
+		cmRes := NewPresentClusterResource("configmap", "cm1", env.Namespace, kubectl)
+		require.Equal(t, map[string]interface{}{"key": "v1"}, cmRes.RawPath(ctlres.NewPathFromStrings([]string{"data"})))
+
+		secretRes := NewPresentClusterResource("secret", "secret1", env.Namespace, kubectl)
+		require.Equal(t, map[string]interface{}{"password": "bXktcGFzc3dvcmQ="}, secretRes.RawPath(ctlres.NewPathFromStrings([]string{"data"})))
+	})
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	appYAML := `
+apiVersion: serve.kapp.k14s.io/v1alpha1
+kind: App
+metadata:
+  name: test-served-app
+spec:
+  serviceAccountName: test-served-app-deployer
+  syncPeriod: 5s
+  fetch:
+  - inline:
+      paths:
+        config.yml: |
+          apiVersion: v1
+          kind: ConfigMap
+          metadata:
+            name: served
+          data:
+            key: value1
+`
+
+	// Apps are deployed as their service account (never as serve itself)
+	rbacYAML := `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: test-served-app-deployer
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: test-served-app-deployer
+rules:
+- apiGroups: ["*"]
+  resources: ["*"]
+  verbs: ["*"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: test-served-app-deployer
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: test-served-app-deployer
+subjects:
+- kind: ServiceAccount
+  name: test-served-app-deployer
+`
+
+	rejectedAppYAML := `
+apiVersion: serve.kapp.k14s.io/v1alpha1
+kind: App
+metadata:
+  name: test-served-app-rejected
+spec:
+  serviceAccountName: test-served-app-deployer
+  fetch:
+  - inline:
+      paths:
+        config.yml: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: rejected\n"
+  deployOptions: [--into-ns=kube-system]
+`
+
+	crdsName := "test-serve-crds"
+	rbacName := "test-serve-rbac"
+	cleanUp := func() {
+		kubectl.RunWithOpts([]string{"delete", "apps.serve.kapp.k14s.io", "test-served-app", "--ignore-not-found", "--wait=false"},
+			RunOpts{AllowError: true})
+		kubectl.RunWithOpts([]string{"patch", "apps.serve.kapp.k14s.io", "test-served-app", "--type=merge",
+			"-p", `{"metadata":{"finalizers":null}}`}, RunOpts{AllowError: true})
+		kubectl.RunWithOpts([]string{"delete", "apps.serve.kapp.k14s.io", "test-served-app-rejected", "--ignore-not-found", "--wait=false"},
+			RunOpts{AllowError: true})
+		kubectl.RunWithOpts([]string{"patch", "apps.serve.kapp.k14s.io", "test-served-app-rejected", "--type=merge",
+			"-p", `{"metadata":{"finalizers":null}}`}, RunOpts{AllowError: true})
+		kapp.Run([]string{"delete", "-a", "test-served-app"})
+		kapp.Run([]string{"delete", "-a", rbacName})
+		kapp.Run([]string{"delete", "-a", crdsName})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	waitFor := func(desc string, checkFunc func() bool) {
+		for i := 0; i < 60; i++ {
+			if checkFunc() {
+				return
+			}
+			time.Sleep(1 * time.Second)
+		}
+		require.FailNowf(t, "Timed out waiting", "Expected %s", desc)
+	}
+
+	configMapValue := func() string {
+		out, err := kubectl.RunWithOpts([]string{"get", "configmap", "served", "-o", "jsonpath={.data.key}"},
+			RunOpts{AllowError: true})
+		if err != nil {
+			return ""
+		}
+		return out
+	}
+
+	logger.Section("install App CRD", func() {
+		crds := kapp.Run([]string{"serve", "--print-crds"})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", crdsName}, RunOpts{StdinReader: strings.NewReader(crds)})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", rbacName}, RunOpts{StdinReader: strings.NewReader(rbacYAML)})
+	})
+
+	logger.Section("serve deploys, re-deploys and deletes App", func() {
+		cancelCh := make(chan struct{})
+		doneCh := make(chan struct{})
+
+		var serveOut string
+
+		go func() {
+			serveOut, _ = kapp.RunWithOpts([]string{"serve", "--yes"}, RunOpts{AllowError: true, CancelCh: cancelCh})
+			close(doneCh)
+		}()
+
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(appYAML)})
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"}, RunOpts{StdinReader: strings.NewReader(rejectedAppYAML)})
+
+		waitFor("config map to be deployed", func() bool { return configMapValue() == "value1" })
+
+		waitFor("App status to be successful", func() bool {
+			out := kubectl.Run([]string{"get", "apps.serve.kapp.k14s.io", "test-served-app",
+				"-o", "jsonpath={.status.lastDeploy.successful}"})
+			return out == "true"
+		})
+
+		kubectl.RunWithOpts([]string{"apply", "-f", "-"},
+			RunOpts{StdinReader: strings.NewReader(strings.Replace(appYAML, "value1", "value2", 1))})
+
+		waitFor("config map to be updated", func() bool { return configMapValue() == "value2" })
+
+		kubectl.Run([]string{"patch", "configmap", "served", "--type=merge", "-p", `{"data":{"key":"drifted"}}`})
+
+		waitFor("drift to be corrected", func() bool { return configMapValue() == "value2" })
+
+		waitFor("App with disallowed deploy options to fail", func() bool {
+			out := kubectl.Run([]string{"get", "apps.serve.kapp.k14s.io", "test-served-app-rejected",
+				"-o", "jsonpath={.status.lastDeploy.error}"})
+			return strings.Contains(out, "Expected deploy options to not include --into-ns")
+		})
+
+		kubectl.Run([]string{"delete", "apps.serve.kapp.k14s.io", "test-served-app", "test-served-app-rejected"})
+
+		NewMissingClusterResource(t, "configmap", "served", env.Namespace, kubectl)
+
+		close(cancelCh)
+		<-doneCh
+
+		require.Contains(t, serveOut, "--- deploying App '"+env.Namespace+"/test-served-app' succeeded")
+		require.Contains(t, serveOut, "--- deleted App '"+env.Namespace+"/test-served-app'")
+		require.Contains(t, serveOut, "Received interrupt: stopped serving")
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestSnapshot(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+data:
+  key: value
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: changed
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: third
+data:
+  key: value
+`
+
+	name := "test-snapshot"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	dir, err := os.MkdirTemp("", "kapp-test-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	snapshotFile := filepath.Join(dir, "snapshot.yml")
+
+	logger.Section("deploy and mutate resource in cluster", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		kubectl.Run([]string{"patch", "configmap", "first", "--type=merge", "-p", `{"data":{"extra":"cluster"}}`})
+	})
+
+	logger.Section("deploy with snapshot", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--snapshot", "--snapshot-file", snapshotFile},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		bs, err := os.ReadFile(snapshotFile)
+		require.NoError(t, err)
+
+		resources, err := ctlres.NewResourcesFromBytes(bs)
+		require.NoError(t, err)
+		require.Len(t, resources, 2, "Expected updated and deleted resources in snapshot")
+
+		NewMissingClusterResource(t, "configmap", "second", env.Namespace, kubectl)
+	})
+
+	logger.Section("restore from snapshot", func() {
+		kapp.Run([]string{"rollback", "-a", name, "--from-snapshot"})
+
+		first := NewPresentClusterResource("configmap", "first", env.Namespace, kubectl)
+		require.Equal(t, "value", first.RawPath(ctlres.NewPathFromStrings([]string{"data", "key"})))
+		require.Equal(t, "cluster", first.RawPath(ctlres.NewPathFromStrings([]string{"data", "extra"})))
+
+		NewPresentClusterResource("configmap", "second", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "third", env.Namespace, kubectl)
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamFiles(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: from-stream
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {}
+`
+
+	name := "test-stream-files"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("labeled stdin is used as resource origin", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "generator=-", "-a", name},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yaml1)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected 'metadata.name' on resource")
+		require.Contains(t, err.Error(), "to be non-empty (stdin 'generator' doc")
+	})
+
+	logger.Section("labeled stdin is deployed", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "generator=-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.SplitN(yaml1, "---", 3)[1])})
+		require.Contains(t, out, "from-stream")
+	})
+}
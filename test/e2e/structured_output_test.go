@@ -0,0 +1,154 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestStructuredOutput(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: value
+`
+
+	name := "test-structured-output"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	type changeOutput struct {
+		Name        string
+		Kind        string
+		Op          string
+		WaitOp      string
+		ApplyResult string
+		WaitResult  string
+	}
+
+	type resultOutput struct {
+		App struct {
+			Name string
+		}
+		Operation string
+		DryRun    bool
+		Summary   struct {
+			Ops map[string]int
+		}
+		Changes    []changeOutput
+		Successful bool
+		LastChange *struct {
+			Name       string
+			Successful *bool
+		}
+	}
+
+	findChange := func(result resultOutput, name string) changeOutput {
+		for _, change := range result.Changes {
+			if change.Name == name {
+				return change
+			}
+		}
+		t.Fatalf("Expected to find change for '%s'", name)
+		return changeOutput{}
+	}
+
+	logger.Section("deploy with json output", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "-o", "json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		var result resultOutput
+		require.NoError(t, json.Unmarshal([]byte(out), &result), "Expected only JSON on stdout")
+
+		require.Equal(t, name, result.App.Name)
+		require.Equal(t, "deploy", result.Operation)
+		require.True(t, result.Successful)
+		require.Equal(t, 2, result.Summary.Ops["add"])
+		require.Len(t, result.Changes, 2)
+
+		change := findChange(result, "first")
+		require.Equal(t, "ConfigMap", change.Kind)
+		require.Equal(t, "add", change.Op)
+		require.Equal(t, "ok", change.WaitOp)
+		require.Equal(t, "succeeded", change.ApplyResult)
+		require.Equal(t, "succeeded", change.WaitResult)
+
+		require.NotNil(t, result.LastChange)
+		require.True(t, *result.LastChange.Successful)
+	})
+
+	logger.Section("diff run with yaml output", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "-o", "yaml"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		var result resultOutput
+		require.NoError(t, yaml.Unmarshal([]byte(out), &result))
+
+		require.True(t, result.DryRun)
+		require.True(t, result.Successful)
+		require.Equal(t, "update", findChange(result, "first").Op)
+		require.Equal(t, "delete", findChange(result, "second").Op)
+		require.Empty(t, findChange(result, "first").ApplyResult)
+	})
+
+	logger.Section("inspect with json output", func() {
+		out := kapp.Run([]string{"inspect", "-a", name, "-o", "json"})
+
+		var resources []struct {
+			Name           string
+			Kind           string
+			Owner          string
+			ReconcileState string
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &resources))
+
+		require.Len(t, resources, 2)
+		require.Equal(t, "first", resources[0].Name)
+		require.Equal(t, "ConfigMap", resources[0].Kind)
+		require.Equal(t, "kapp", resources[0].Owner)
+		require.Equal(t, "ok", resources[0].ReconcileState)
+	})
+
+	logger.Section("delete with json output", func() {
+		out := kapp.Run([]string{"delete", "-a", name, "-o", "json"})
+
+		var result resultOutput
+		require.NoError(t, json.Unmarshal([]byte(out), &result))
+
+		require.Equal(t, "delete", result.Operation)
+		require.True(t, result.Successful)
+		require.Equal(t, 2, result.Summary.Ops["delete"])
+		require.Equal(t, "succeeded", findChange(result, "second").ApplyResult)
+	})
+}
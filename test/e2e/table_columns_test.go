@@ -0,0 +1,82 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableColumns(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+`
+
+	name := "test-table-columns"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	rowKeys := func(row map[string]string) []string {
+		var keys []string
+		for key := range row {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	logger.Section("deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("inspect with selected columns", func() {
+		out := kapp.Run([]string{"inspect", "-a", name, "--columns", "name,version", "--tty", "--no-truncate"})
+
+		require.Contains(t, out, "Name   Version")
+		require.Contains(t, out, "first  v1")
+		require.NotContains(t, out, "Owner")
+	})
+
+	logger.Section("list with selected columns", func() {
+		out := kapp.Run([]string{"ls", "--columns", "name", "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables, 1)
+
+		for _, row := range resp.Tables[0].Rows {
+			require.Equal(t, []string{"name"}, rowKeys(row))
+		}
+	})
+
+	logger.Section("app-change ls with selected columns", func() {
+		out := kapp.Run([]string{"app-change", "ls", "-a", name, "--columns", "name,successful", "--json"})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+		require.Len(t, resp.Tables[0].Rows, 1)
+		require.Equal(t, []string{"name", "successful"}, rowKeys(resp.Tables[0].Rows[0]))
+	})
+
+	logger.Section("unknown column", func() {
+		_, err := kapp.RunWithOpts([]string{"inspect", "-a", name, "--columns", "unknown"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Failed to find header: unknown")
+	})
+}
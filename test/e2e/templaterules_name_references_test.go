@@ -0,0 +1,89 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	ctlres "github.com/vmware-tanzu/carvel-kapp/pkg/kapp/resources"
+)
+
+func TestTemplateRulesWithNameReferences(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	// ConfigMap data stands in for custom resource fields that refer to config by name
+	yaml := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: consumer
+data:
+  configName: simple-cm
+  otherName: unrelated-cm
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: simple-cm
+  annotations:
+    kapp.k14s.io/versioned: ""
+data:
+  hello_msg: __msg__
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+templateRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+  affectedResources:
+    objectReferences:
+    - path: [data, configName]
+      resourceMatchers:
+      - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: __ns__, name: consumer}
+    - path: [data, otherName]
+      resourceMatchers:
+      - kindNamespaceNameMatcher: {kind: ConfigMap, namespace: __ns__, name: consumer}
+`
+
+	yaml = strings.ReplaceAll(yaml, "__ns__", env.Namespace)
+
+	name := "test-templaterules-with-name-references"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	deploy := func(msg string) {
+		kapp.RunWithOpts([]string{"deploy", "-a", name, "-f", "-"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml, "__msg__", msg))})
+	}
+
+	consumerData := func(key string) interface{} {
+		cm := NewPresentClusterResource("configmap", "consumer", env.Namespace, kubectl)
+		return cm.RawPath(ctlres.NewPathFromStrings([]string{"data", key}))
+	}
+
+	logger.Section("initial deploy", func() {
+		deploy("carvel")
+
+		NewPresentClusterResource("configmap", "simple-cm-ver-1", env.Namespace, kubectl)
+		require.Equal(t, "simple-cm-ver-1", consumerData("configName"))
+		require.Equal(t, "unrelated-cm", consumerData("otherName"))
+	})
+
+	logger.Section("deploy new version", func() {
+		deploy("kapp")
+
+		NewPresentClusterResource("configmap", "simple-cm-ver-2", env.Namespace, kubectl)
+		require.Equal(t, "simple-cm-ver-2", consumerData("configName"))
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTerraformPlanFile(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: value1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+stringData:
+  password: my-password
+`
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+data:
+  key: value2
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm3
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+stringData:
+  password: my-password
+`
+
+	name := "test-diff-terraform-plan-file"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	planPath := filepath.Join(t.TempDir(), "tfplan.json")
+
+	type tfChange struct {
+		Address string
+		Type    string
+		Index   string
+		Change  struct {
+			Actions []string
+			Before  map[string]interface{}
+			After   map[string]interface{}
+		}
+	}
+
+	readPlan := func() (string, map[string]tfChange) {
+		bs, err := os.ReadFile(planPath)
+		require.NoError(t, err)
+
+		var plan struct {
+			FormatVersion   string     `json:"format_version"`
+			ResourceChanges []tfChange `json:"resource_changes"`
+		}
+		require.NoError(t, json.Unmarshal(bs, &plan))
+		require.Equal(t, "1.2", plan.FormatVersion)
+
+		changes := map[string]tfChange{}
+		for _, change := range plan.ResourceChanges {
+			require.Equal(t, "kubernetes_manifest", change.Type)
+			changes[change.Index] = change
+		}
+		return string(bs), changes
+	}
+
+	logger.Section("initial deploy", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-terraform-plan-file", planPath},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		planStr, changes := readPlan()
+		require.NotContains(t, planStr, "my-password", "Expected secret values to be masked")
+
+		cm1 := changes[env.Namespace+"//ConfigMap/cm1"]
+		require.Equal(t, `kubernetes_manifest.`+name+`["`+env.Namespace+`//ConfigMap/cm1"]`, cm1.Address)
+		require.Equal(t, []string{"create"}, cm1.Change.Actions)
+		require.Nil(t, cm1.Change.Before)
+		require.Equal(t, map[string]interface{}{"key": "value1"}, cm1.Change.After["data"])
+	})
+
+	logger.Section("plan changes without applying", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-run", "--diff-terraform-plan-file", planPath},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		_, changes := readPlan()
+
+		cm1 := changes[env.Namespace+"//ConfigMap/cm1"]
+		require.Equal(t, []string{"update"}, cm1.Change.Actions)
+		require.Equal(t, map[string]interface{}{"key": "value1"}, cm1.Change.Before["data"])
+		require.Equal(t, map[string]interface{}{"key": "value2"}, cm1.Change.After["data"])
+
+		cm2 := changes[env.Namespace+"//ConfigMap/cm2"]
+		require.Equal(t, []string{"delete"}, cm2.Change.Actions)
+		require.NotNil(t, cm2.Change.Before)
+		require.Nil(t, cm2.Change.After)
+
+		cm3 := changes[env.Namespace+"//ConfigMap/cm3"]
+		require.Equal(t, []string{"create"}, cm3.Change.Actions)
+	})
+}
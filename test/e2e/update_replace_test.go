@@ -82,6 +82,76 @@ spec:
 	})
 }
 
+func TestUpdateFallbackOnReplaceFromConfig(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	config := `
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+fallbackOnReplaceRules:
+- resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Service}
+`
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis-primary
+spec:
+  ports:
+  - port: 6380
+    targetPort: 6380
+  selector:
+    app: redis
+    tier: backend
+` + config
+
+	yaml2 := `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis-primary
+spec:
+  clusterIP: None
+  ports:
+  - port: 6380
+    targetPort: 6380
+  selector:
+    app: redis
+    tier: backend
+` + config
+
+	name := "test-update-fallback-on-replace-from-config"
+	objKind := "service"
+	objName := "redis-primary"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy basic service", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("deploy update to service that changes immutable field spec.clusterIP", func() {
+		prev := NewPresentClusterResource(objKind, objName, env.Namespace, kubectl)
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+		curr := NewPresentClusterResource(objKind, objName, env.Namespace, kubectl)
+
+		require.NotEqual(t, prev.UID(), curr.UID(), "Expected object to be replaced, but found same UID")
+	})
+}
+
 func TestUpdateAlwaysReplace(t *testing.T) {
 	env := BuildEnv(t)
 	logger := Logger{}
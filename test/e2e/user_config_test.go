@@ -0,0 +1,74 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserConfig(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	userConfigYAML := `
+apiVersion: kapp.k14s.io/v1alpha1
+kind: UserConfig
+commands:
+  deploy:
+    diff-changes: true
+    diff-context: 0
+`
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key1: val1
+`
+
+	name := "test-user-config"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(userConfigYAML), 0600))
+
+	logger.Section("deploy with flags from user config", func() {
+		t.Setenv("KAPP_CONFIG_FILE", configPath)
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		require.Contains(t, out, "key1: val1", "Expected diff to be shown")
+	})
+
+	logger.Section("explicitly specified flags take precedence", func() {
+		t.Setenv("KAPP_CONFIG_FILE", configPath)
+
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--diff-changes=false"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yaml1, "val1", "val2"))})
+
+		require.NotContains(t, out, "key1: val2", "Expected diff to not be shown")
+	})
+
+	logger.Section("fails when specified user config is missing", func() {
+		t.Setenv("KAPP_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yml"))
+
+		_, err := kapp.RunWithOpts([]string{"ls"}, RunOpts{AllowError: true})
+		require.ErrorContains(t, err, "Reading user config")
+	})
+}
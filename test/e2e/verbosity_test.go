@@ -0,0 +1,80 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerbosity(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yamlTpl := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+data:
+  key: VAL
+`
+
+	name := "test-verbosity"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	deploy := func(val string, args ...string) string {
+		out, _ := kapp.RunWithOpts(append([]string{"deploy", "-f", "-", "-a", name}, args...),
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(strings.ReplaceAll(yamlTpl, "VAL", val))})
+		return out
+	}
+
+	logger.Section("quiet", func() {
+		out := deploy("1", "--quiet")
+
+		require.NotContains(t, out, "Target cluster")
+		require.NotContains(t, out, "Namespace")
+		require.NotContains(t, out, "---- applying")
+		require.Contains(t, out, "App '"+name+"' (namespace: "+env.Namespace+"): Op: 1 create")
+		require.Contains(t, out, "Succeeded")
+	})
+
+	logger.Section("changes only", func() {
+		out := deploy("2", "-v", "1")
+
+		require.Contains(t, out, "Namespace")
+		require.Contains(t, out, "---- applying 1 changes")
+		require.NotContains(t, out, "update configmap/first")
+	})
+
+	logger.Section("default shows per-resource progress", func() {
+		out := deploy("3")
+
+		require.Contains(t, out, "update configmap/first")
+		require.NotContains(t, out, "@@ update configmap/first")
+	})
+
+	logger.Section("diffs", func() {
+		out := deploy("4", "--verbosity", "3")
+
+		require.Contains(t, out, "@@ update configmap/first")
+		require.Contains(t, out, "update configmap/first")
+	})
+
+	logger.Section("invalid level", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "-v", "5"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(yamlTpl)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected verbosity to be between 0 and 4, but was 5")
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitBehaviorRules(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	jobYAML := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: failing-job
+spec:
+  template:
+    metadata:
+      name: failing-job
+    spec:
+      restartPolicy: Never
+      containers:
+        - name: failing-job
+          image: busybox
+          command: [ "sh", "-c", "exit 1" ]
+  backoffLimit: 0
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitBehaviorRules:
+- type: disable
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: batch/v1, kind: Job}
+`
+
+	conditionsYAML := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+---
+apiVersion: kapp.k14s.io/v1alpha1
+kind: Config
+waitBehaviorRules:
+- type: conditions
+  conditions: [Ready]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
+`
+
+	name := "test-wait-behavior-rules"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy failing job without waiting", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(jobYAML)})
+
+		NewPresentClusterResource("job", "failing-job", env.Namespace, kubectl)
+	})
+
+	cleanUp()
+
+	logger.Section("wait for extra conditions", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait-timeout", "5s"},
+			RunOpts{IntoNs: true, AllowError: true, StdinReader: strings.NewReader(conditionsYAML)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Timed out waiting after 5s")
+	})
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWait(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: slow-job
+spec:
+  template:
+    metadata:
+      name: slow-job
+    spec:
+      containers:
+      - name: slow-job
+        image: busybox
+        command: ["/bin/sh", "-c", "sleep 5"]
+      restartPolicy: Never
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`
+
+	name := "test-wait"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy without waiting", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--wait=false"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("wait for resources that have not converged", func() {
+		out := kapp.Run([]string{"wait", "-a", name})
+		require.Contains(t, out, "ok: reconcile job/slow-job (batch/v1)")
+		require.NotContains(t, out, "configmap/config")
+	})
+
+	logger.Section("wait for converged resources", func() {
+		out := kapp.Run([]string{"wait", "-a", name})
+		require.Contains(t, out, "All resources of app '"+name+"' namespace: "+env.Namespace+" have already converged")
+	})
+
+	logger.Section("wait for non-existent app", func() {
+		_, err := kapp.RunWithOpts([]string{"wait", "-a", name + "-missing"}, RunOpts{AllowError: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not exist")
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestYttInput(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"config.yml": `
+#@ load("@ytt:data", "data")
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: #@ data.values.name
+data:
+  key: #@ data.values.value
+`,
+		"values.yml": `
+#@data/values
+---
+name: ytt-config
+value: default
+`,
+	}
+
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+		require.NoError(t, err)
+	}
+
+	name := "test-ytt-input"
+	cleanUp := func() {
+		kapp.Run([]string{"delete", "-a", name})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy directory with ytt templates", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", dir, "-a", name, "--ytt-data-value", "value=custom"}, RunOpts{IntoNs: true})
+
+		out := kubectl.Run([]string{"get", "configmap", "ytt-config", "-o", "jsonpath={.data.key}"})
+		require.Equal(t, "custom", out)
+	})
+
+	logger.Section("data values require directory with templates", func() {
+		_, err := kapp.RunWithOpts([]string{"deploy", "-f", filepath.Join(dir, "config.yml"), "-a", name,
+			"--ytt-data-value", "value=custom", "--ytt=false"}, RunOpts{IntoNs: true, AllowError: true})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Expected at least one --file (-f) directory with ytt templates")
+	})
+}